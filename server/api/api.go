@@ -100,6 +100,20 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 
 	// V3 routes
 	a.registerCardsRoutes(apiv2)
+	a.registerAutomationsRoutes(apiv2)
+	a.registerArchivedPropertyValuesRoutes(apiv2)
+	a.registerBoardSnapshotsRoutes(apiv2)
+	a.registerUndoRoutes(apiv2)
+	a.registerTrashRoutes(apiv2)
+	a.registerRetentionPoliciesRoutes(apiv2)
+	a.registerBackupsRoutes(apiv2)
+	a.registerAuditRecordsRoutes(apiv2)
+	a.registerArchivingRoutes(apiv2)
+	a.registerCardTemplatesRoutes(apiv2)
+	a.registerBulkOperationsRoutes(apiv2)
+	a.registerCardMoveRoutes(apiv2)
+	a.registerCSVImportRoutes(apiv2)
+	a.registerBoardExportRoutes(apiv2)
 
 	// AI routes
 	a.registerAIRoutes(apiv2)
@@ -110,6 +124,9 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 
 func (a *API) RegisterAdminRoutes(r *mux.Router) {
 	r.HandleFunc("/api/v2/admin/users/{username}/password", a.adminRequired(a.handleAdminSetPassword)).Methods("POST")
+	a.registerAdminRetentionPoliciesRoutes(r)
+	a.registerAdminBackupsRoutes(r)
+	a.registerAdminAuditRecordsRoutes(r)
 }
 
 func getUserID(r *http.Request) string {
@@ -188,12 +205,18 @@ func (a *API) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	switch {
 	case model.IsErrBadRequest(err):
 		errorResponse.ErrorCode = http.StatusBadRequest
+		var ipv *model.ErrInvalidPropertyValues
+		if errors.As(err, &ipv) {
+			errorResponse.PropertyErrors = ipv.Errors
+		}
 	case model.IsErrUnauthorized(err):
 		errorResponse.ErrorCode = http.StatusUnauthorized
 	case model.IsErrForbidden(err):
 		errorResponse.ErrorCode = http.StatusForbidden
 	case model.IsErrNotFound(err):
 		errorResponse.ErrorCode = http.StatusNotFound
+	case model.IsErrConflict(err):
+		errorResponse.ErrorCode = http.StatusConflict
 	case model.IsErrRequestEntityTooLarge(err):
 		errorResponse.ErrorCode = http.StatusRequestEntityTooLarge
 	case model.IsErrNotImplemented(err):
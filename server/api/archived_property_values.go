@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) registerArchivedPropertyValuesRoutes(r *mux.Router) {
+	// Archived card property values APIs
+	r.HandleFunc("/boards/{boardID}/archived-values", a.sessionRequired(a.handleGetArchivedPropertyValues)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/archived-values/{propertyID}/restore", a.sessionRequired(a.handleRestoreArchivedPropertyValues)).Methods("POST")
+}
+
+func (a *API) handleGetArchivedPropertyValues(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/archived-values getArchivedPropertyValues
+	//
+	// Fetches the card values archived when their property or option
+	// was removed from the specified board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/ArchivedPropertyValue"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch archived values"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getArchivedPropertyValues", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	values, err := a.app.GetArchivedPropertyValues(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("valueCount", len(values))
+	auditRec.Success()
+}
+
+func (a *API) handleRestoreArchivedPropertyValues(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/archived-values/{propertyID}/restore restoreArchivedPropertyValues
+	//
+	// Restores the archived card values of a property. The property must
+	// be part of the board again.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: propertyID
+	//   in: path
+	//   description: Property ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Block"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	propertyID := vars["propertyID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to restore archived values"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreArchivedPropertyValues", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("propertyID", propertyID)
+
+	cards, err := a.app.RestoreArchivedPropertyValues(boardID, propertyID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(cards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("cardCount", len(cards))
+	auditRec.Success()
+}
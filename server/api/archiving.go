@@ -0,0 +1,249 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerArchivingRoutes(r *mux.Router) {
+	// Archiving APIs
+	r.HandleFunc("/cards/{cardID}/archive", a.sessionRequired(a.handleArchiveCard)).Methods("POST")
+	r.HandleFunc("/cards/{cardID}/unarchive", a.sessionRequired(a.handleUnarchiveCard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/archive", a.sessionRequired(a.handleArchiveBoard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/unarchive", a.sessionRequired(a.handleUnarchiveBoard)).Methods("POST")
+}
+
+// includeArchived tells if a request asks for the archived cards and
+// boards.
+func includeArchived(r *http.Request) bool {
+	return r.URL.Query().Get("include_archived") == "true"
+}
+
+func (a *API) handleArchiveCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/archive archiveCard
+	//
+	// Archives a card. The archived cards are hidden from the boards but
+	// remain searchable and exportable.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Card"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.setCardArchived(w, r, true)
+}
+
+func (a *API) handleUnarchiveCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/unarchive unarchiveCard
+	//
+	// Restores an archived card.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Card"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.setCardArchived(w, r, false)
+}
+
+func (a *API) setCardArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	block, err := a.app.GetBlockByID(cardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, block.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to archive card"))
+		return
+	}
+
+	event := "unarchiveCard"
+	if archived {
+		event = "archiveCard"
+	}
+	auditRec := a.makeAuditRecord(r, event, audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", block.BoardID)
+	auditRec.AddMeta("cardID", cardID)
+
+	var updated *model.Block
+	if archived {
+		updated, err = a.app.ArchiveCard(cardID, userID)
+	} else {
+		updated, err = a.app.UnarchiveCard(cardID, userID)
+	}
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	card, err := model.Block2Card(updated)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug(event,
+		mlog.String("boardID", card.BoardID),
+		mlog.String("cardID", card.ID),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(card)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleArchiveBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/archive archiveBoard
+	//
+	// Archives a board. The archived boards are hidden from the board lists
+	// but keep their content.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Board"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.setBoardArchived(w, r, true)
+}
+
+func (a *API) handleUnarchiveBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/unarchive unarchiveBoard
+	//
+	// Restores an archived board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Board"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.setBoardArchived(w, r, false)
+}
+
+func (a *API) setBoardArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to archive board"))
+		return
+	}
+
+	event := "unarchiveBoard"
+	if archived {
+		event = "archiveBoard"
+	}
+	auditRec := a.makeAuditRecord(r, event, audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	var board *model.Board
+	var err error
+	if archived {
+		board, err = a.app.ArchiveBoard(boardID, userID)
+	} else {
+		board, err = a.app.UnarchiveBoard(boardID, userID)
+	}
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug(event,
+		mlog.String("boardID", board.ID),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerAuditRecordsRoutes(r *mux.Router) {
+	// Audit records APIs
+	r.HandleFunc("/audit_records", a.sessionRequired(a.handleGetAuditRecords)).Methods("GET")
+	r.HandleFunc("/audit_records/export", a.sessionRequired(a.handleExportAuditRecords)).Methods("GET")
+}
+
+// registerAdminAuditRecordsRoutes exposes the audit records APIs on the
+// local mode admin socket, for the servers without system admins.
+func (a *API) registerAdminAuditRecordsRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v2/admin/audit_records", a.adminRequired(a.handleGetAuditRecords)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/audit_records/export", a.adminRequired(a.handleExportAuditRecords)).Methods("GET")
+}
+
+// canReadAuditRecords tells if the request comes from the local mode admin
+// socket or from a system admin.
+func (a *API) canReadAuditRecords(r *http.Request) bool {
+	if _, isUnix := GetContextConn(r).(*net.UnixConn); isUnix {
+		return true
+	}
+	return a.permissions.HasPermissionTo(getUserID(r), model.PermissionManageSystem)
+}
+
+// auditRecordsQueryOptions reads the audit records filters of a request.
+func auditRecordsQueryOptions(r *http.Request) (model.QueryAuditRecordsOptions, error) {
+	query := r.URL.Query()
+	opts := model.QueryAuditRecordsOptions{
+		UserID:  query.Get("user_id"),
+		Event:   query.Get("event"),
+		BoardID: query.Get("board_id"),
+		Status:  query.Get("status"),
+		Cursor:  query.Get("cursor"),
+	}
+
+	var err error
+	if strSince := query.Get("since"); strSince != "" {
+		if opts.Since, err = strconv.ParseInt(strSince, 10, 64); err != nil {
+			return opts, model.NewErrBadRequest(fmt.Sprintf("invalid `since` parameter: %s", err))
+		}
+	}
+	if strUntil := query.Get("until"); strUntil != "" {
+		if opts.Until, err = strconv.ParseInt(strUntil, 10, 64); err != nil {
+			return opts, model.NewErrBadRequest(fmt.Sprintf("invalid `until` parameter: %s", err))
+		}
+	}
+	if strPerPage := query.Get("per_page"); strPerPage != "" {
+		if opts.PerPage, err = strconv.Atoi(strPerPage); err != nil {
+			return opts, model.NewErrBadRequest(fmt.Sprintf("invalid `per_page` parameter: %s", err))
+		}
+	}
+	if opts.Cursor != "" {
+		if _, _, err := model.ParseAuditCursor(opts.Cursor); err != nil {
+			return opts, model.NewErrBadRequest(err.Error())
+		}
+	}
+	return opts, nil
+}
+
+func (a *API) handleGetAuditRecords(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /audit_records getAuditRecords
+	//
+	// Returns the audit records stored in the database, the most recent
+	// first. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: user_id
+	//   in: query
+	//   description: Filters for the records of a user
+	//   required: false
+	//   type: string
+	// - name: event
+	//   in: query
+	//   description: Filters for the records of an event
+	//   required: false
+	//   type: string
+	// - name: board_id
+	//   in: query
+	//   description: Filters for the records of a board
+	//   required: false
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: Filters for the records with a status, one of attempt, success or fail
+	//   required: false
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: Filters for the records created at or after a time; Unix time in milliseconds
+	//   required: false
+	//   type: integer
+	// - name: until
+	//   in: query
+	//   description: Filters for the records created before a time; Unix time in milliseconds
+	//   required: false
+	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: The nextCursor of the previous page
+	//   required: false
+	//   type: string
+	// - name: per_page
+	//   in: query
+	//   description: Number of records to return per page (default=100, max=1000)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/AuditRecordsResponse"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if !a.canReadAuditRecords(r) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to audit records"))
+		return
+	}
+
+	opts, err := auditRecordsQueryOptions(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	response, err := a.app.GetAuditRecords(opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleExportAuditRecords(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /audit_records/export exportAuditRecords
+	//
+	// Exports the audit records stored in the database as CSV, the most
+	// recent first. Accepts the filters of getAuditRecords, except the
+	// pagination. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - text/csv
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if !a.canReadAuditRecords(r) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to audit records"))
+		return
+	}
+
+	opts, err := auditRecordsQueryOptions(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	opts.Cursor = ""
+
+	// the records are queried once before writing the headers, so a
+	// database error can still be reported
+	if _, err := a.app.GetAuditRecords(model.QueryAuditRecordsOptions{PerPage: 1}); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-records-%s.csv", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	if err := a.app.ExportAuditRecords(opts, w); err != nil {
+		a.logger.Error("Cannot export the audit records", mlog.Err(err))
+	}
+}
@@ -0,0 +1,532 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const defaultAutomationExecutionsPerPage = "50"
+
+func (a *API) registerAutomationsRoutes(r *mux.Router) {
+	// Automation rules APIs
+	r.HandleFunc("/boards/{boardID}/automations", a.sessionRequired(a.handleGetAutomationRules)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/automations", a.sessionRequired(a.handleCreateAutomationRule)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/automations/{ruleID}", a.sessionRequired(a.handleUpdateAutomationRule)).Methods("PUT")
+	r.HandleFunc("/boards/{boardID}/automations/{ruleID}", a.sessionRequired(a.handleDeleteAutomationRule)).Methods("DELETE")
+	r.HandleFunc("/boards/{boardID}/automations/{ruleID}/test", a.sessionRequired(a.handleTestAutomationRule)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/automations/{ruleID}/executions", a.sessionRequired(a.handleGetAutomationExecutions)).Methods("GET")
+}
+
+func (a *API) handleGetAutomationRules(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/automations getAutomationRules
+	//
+	// Fetches the automation rules of the specified board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/AutomationRule"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch automation rules"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getAutomationRules", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	rules, err := a.app.GetAutomationRules(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("ruleCount", len(rules))
+	auditRec.Success()
+}
+
+func (a *API) handleCreateAutomationRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/automations createAutomationRule
+	//
+	// Creates an automation rule for the specified board. The actions of
+	// the rule run as the current user.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the rule to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/AutomationRule"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/AutomationRule'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	rule, err := model.AutomationRuleFromJSON(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	rule.BoardID = boardID
+
+	if !a.hasPermissionToAutomationRule(userID, rule) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create automation rule"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createAutomationRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("trigger", rule.Trigger.Type)
+
+	rule, err = a.app.CreateAutomationRule(rule, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateAutomationRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", rule.ID),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("ruleID", rule.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleUpdateAutomationRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /boards/{boardID}/automations/{ruleID} updateAutomationRule
+	//
+	// Updates the title, state, trigger and actions of an automation rule.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Automation rule ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the updated rule
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/AutomationRule"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/AutomationRule'
+	//   '404':
+	//     description: rule not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+
+	rule, err := model.AutomationRuleFromJSON(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	rule.ID = ruleID
+	rule.BoardID = boardID
+
+	if !a.hasPermissionToAutomationRule(userID, rule) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify automation rule"))
+		return
+	}
+
+	if _, err = a.getBoardAutomationRule(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "updateAutomationRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	rule, err = a.app.UpdateAutomationRule(rule, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("UpdateAutomationRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteAutomationRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/automations/{ruleID} deleteAutomationRule
+	//
+	// Deletes an automation rule and its execution log.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Automation rule ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: rule not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to delete automation rule"))
+		return
+	}
+
+	if _, err := a.getBoardAutomationRule(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteAutomationRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	if err := a.app.DeleteAutomationRule(ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteAutomationRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+		mlog.String("userID", userID),
+	)
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleTestAutomationRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/automations/{ruleID}/test testAutomationRule
+	//
+	// Runs the actions of an automation rule on a card as a dry run, and
+	// returns what they would do. Nothing is changed.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Automation rule ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the card to test the rule on, as {"cardId": "..."}
+	//   required: true
+	//   schema:
+	//     type: object
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/AutomationExecution'
+	//   '404':
+	//     description: rule not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+
+	var body struct {
+		CardID string `json:"cardId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	if body.CardID == "" {
+		a.errorResponse(w, r, model.NewErrBadRequest("missing cardId"))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to test automation rule"))
+		return
+	}
+
+	if _, err := a.getBoardAutomationRule(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "testAutomationRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+	auditRec.AddMeta("cardID", body.CardID)
+
+	execution, err := a.app.TestAutomationRule(ruleID, body.CardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(execution)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetAutomationExecutions(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/automations/{ruleID}/executions getAutomationExecutions
+	//
+	// Fetches the execution log of an automation rule, the most recent
+	// first.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Automation rule ID
+	//   required: true
+	//   type: string
+	// - name: per_page
+	//   in: query
+	//   description: Number of executions to return (default=50)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/AutomationExecution"
+	//   '404':
+	//     description: rule not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+
+	strPerPage := r.URL.Query().Get("per_page")
+	if strPerPage == "" {
+		strPerPage = defaultAutomationExecutionsPerPage
+	}
+	perPage, err := strconv.ParseUint(strPerPage, 10, 64)
+	if err != nil {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch automation executions"))
+		return
+	}
+
+	if _, err = a.getBoardAutomationRule(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getAutomationExecutions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	executions, err := a.app.GetAutomationExecutions(ruleID, perPage)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(executions)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("executionCount", len(executions))
+	auditRec.Success()
+}
+
+// getBoardAutomationRule returns a rule, or a not found error if it
+// doesn't belong to the board.
+func (a *API) getBoardAutomationRule(boardID, ruleID string) (*model.AutomationRule, error) {
+	rule, err := a.app.GetAutomationRule(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.BoardID != boardID {
+		return nil, model.NewErrNotFound("automation rule ID=" + ruleID)
+	}
+	return rule, nil
+}
+
+// hasPermissionToAutomationRule checks that the user can modify the cards
+// of the board of a rule and of the boards it moves cards to.
+func (a *API) hasPermissionToAutomationRule(userID string, rule *model.AutomationRule) bool {
+	if !a.permissions.HasPermissionToBoard(userID, rule.BoardID, model.PermissionManageBoardCards) {
+		return false
+	}
+	for _, action := range rule.Actions {
+		if action.Type == model.AutomationActionMoveToBoard &&
+			!a.permissions.HasPermissionToBoard(userID, action.BoardID, model.PermissionManageBoardCards) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) registerBackupsRoutes(r *mux.Router) {
+	// Backups APIs
+	r.HandleFunc("/backups", a.sessionRequired(a.handleGetBackups)).Methods("GET")
+	r.HandleFunc("/backups", a.sessionRequired(a.handleCreateBackup)).Methods("POST")
+}
+
+// registerAdminBackupsRoutes exposes the backups APIs on the local mode
+// admin socket, for the servers without system admins.
+func (a *API) registerAdminBackupsRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v2/admin/backups", a.adminRequired(a.handleGetBackups)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/backups", a.adminRequired(a.handleCreateBackup)).Methods("POST")
+}
+
+// canManageBackups tells if the request comes from the local mode admin
+// socket or from a system admin.
+func (a *API) canManageBackups(r *http.Request) bool {
+	if _, isUnix := GetContextConn(r).(*net.UnixConn); isUnix {
+		return true
+	}
+	return a.permissions.HasPermissionTo(getUserID(r), model.PermissionManageSystem)
+}
+
+func (a *API) handleGetBackups(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /backups getBackups
+	//
+	// Returns the backup archives of the backup directory, newest first.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BackupInfo"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if !a.canManageBackups(r) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to backups"))
+		return
+	}
+
+	backups, err := a.app.GetBackups()
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(backups)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleCreateBackup(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /backups createBackup
+	//
+	// Creates a backup archive of the database and of the file store in
+	// the backup directory. The oldest archives beyond the retention
+	// count are removed.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/BackupInfo"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if !a.canManageBackups(r) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to backups"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createBackup", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	backup, err := a.app.CreateBackup(time.Now())
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("name", backup.Name)
+
+	data, err := json.Marshal(backup)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
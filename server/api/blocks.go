@@ -49,6 +49,11 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 	//   description: Type of blocks to return, omit to specify all types
 	//   required: false
 	//   type: string
+	// - name: include_archived
+	//   in: query
+	//   description: Whether to include the archived cards and their content (default=false)
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -151,6 +156,11 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// the archived cards are hidden from the views unless requested
+	if blockID == "" && !includeArchived(r) {
+		blocks = model.FilterArchivedBlocks(blocks)
+	}
+
 	a.logger.Debug("GetBlocks",
 		mlog.String("boardID", boardID),
 		mlog.String("parentID", parentID),
@@ -308,6 +318,12 @@ func (a *API) handlePostBlocks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	changes := make([]model.BlockChange, 0, len(newBlocks))
+	for _, block := range newBlocks {
+		changes = append(changes, model.BlockChange{After: block})
+	}
+	a.app.RecordUndoEntry(session.UserID, boardID, model.UndoOperationInsertBlocks, nil, changes)
+
 	a.logger.Debug("POST Blocks",
 		mlog.Int("block_count", len(blocks)),
 		mlog.Bool("disable_notify", disableNotify),
@@ -395,6 +411,7 @@ func (a *API) handleDeleteBlock(w http.ResponseWriter, r *http.Request) {
 		a.errorResponse(w, r, err)
 		return
 	}
+	a.app.RecordUndoEntry(userID, boardID, model.UndoOperationDeleteBlock, nil, []model.BlockChange{{Before: block}})
 
 	a.logger.Debug("DELETE Block", mlog.String("boardID", boardID), mlog.String("blockID", blockID))
 	jsonStringResponse(w, http.StatusOK, "{}")
@@ -572,10 +589,12 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("boardID", boardID)
 	auditRec.AddMeta("blockID", blockID)
 
-	if _, err = a.app.PatchBlockAndNotify(blockID, patch, userID, disableNotify); err != nil {
+	patchedBlock, err := a.app.PatchBlockAndNotify(blockID, patch, userID, disableNotify)
+	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
+	a.app.RecordUndoEntry(userID, boardID, model.UndoOperationPatchBlocks, nil, []model.BlockChange{{Before: block, After: patchedBlock}})
 
 	a.logger.Debug("PATCH Block", mlog.String("boardID", boardID), mlog.String("blockID", blockID))
 	jsonStringResponse(w, http.StatusOK, "{}")
@@ -647,6 +666,7 @@ func (a *API) handlePatchBlocks(w http.ResponseWriter, r *http.Request) {
 		auditRec.AddMeta("block_"+strconv.FormatInt(int64(i), 10), patches.BlockIDs[i])
 	}
 
+	oldBlocks := make([]*model.Block, 0, len(patches.BlockIDs))
 	for _, blockID := range patches.BlockIDs {
 		var block *model.Block
 		block, err = a.app.GetBlockByID(blockID)
@@ -658,6 +678,7 @@ func (a *API) handlePatchBlocks(w http.ResponseWriter, r *http.Request) {
 			a.errorResponse(w, r, model.NewErrPermission("access denied to make board changesa"))
 			return
 		}
+		oldBlocks = append(oldBlocks, block)
 	}
 
 	err = a.app.PatchBlocksAndNotify(teamID, patches, userID, disableNotify)
@@ -665,6 +686,7 @@ func (a *API) handlePatchBlocks(w http.ResponseWriter, r *http.Request) {
 		a.errorResponse(w, r, err)
 		return
 	}
+	a.app.RecordUndoBlockPatches(userID, oldBlocks)
 
 	a.logger.Debug("PATCH Blocks", mlog.String("patches", strconv.Itoa(len(patches.BlockIDs))))
 	jsonStringResponse(w, http.StatusOK, "{}")
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) registerBoardExportRoutes(r *mux.Router) {
+	// Board export APIs
+	r.HandleFunc("/boards/{boardID}/export", a.sessionRequired(a.handleExportBoard)).Methods("GET")
+}
+
+func (a *API) handleExportBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/export exportBoard
+	//
+	// Exports the cards of a board as a CSV or XLSX table, with the display
+	// values of their properties, or the board as a zip of Markdown or HTML
+	// pages, with a page per view and per card and the files of the cards.
+	// If a view is passed, its filter, sort and visible properties are
+	// applied.
+	//
+	// ---
+	// produces:
+	// - text/csv
+	// - application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+	// - application/zip
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: format
+	//   in: query
+	//   description: The format of the export, csv, xlsx, markdown or html
+	//   required: true
+	//   type: string
+	// - name: view
+	//   in: query
+	//   description: The ID of the view to export
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     content:
+	//       application-octet-stream:
+	//         type: string
+	//         format: binary
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+	query := r.URL.Query()
+	opts := model.BoardExportOptions{
+		BoardID: boardID,
+		ViewID:  query.Get("view"),
+		Format:  model.BoardExportFormat(query.Get("format")),
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+	if !opts.Format.IsValid() {
+		a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("unsupported export format %q", opts.Format)))
+		return
+	}
+
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "exportBoard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("viewID", opts.ViewID)
+	auditRec.AddMeta("format", opts.Format)
+
+	// the response is streamed, so the errors after the first write can't
+	// change its status
+	w.Header().Set("Content-Type", opts.Format.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": exportFilename(board.Title, opts.Format.Extension()),
+	}))
+	w.Header().Set("Content-Transfer-Encoding", "binary")
+
+	if opts.Format.IsTable() {
+		err = a.app.ExportBoardTable(w, opts)
+	} else {
+		err = a.app.ExportBoardSite(w, opts)
+	}
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec.Success()
+}
+
+// exportFilename returns the name of the export of a board, made of its
+// title and the date.
+func exportFilename(title, extension string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || r < ' ' {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(title))
+	if name == "" {
+		name = "board"
+	}
+	return fmt.Sprintf("%s-%s.%s", name, time.Now().Format("2006-01-02"), extension)
+}
@@ -0,0 +1,360 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+const defaultBoardVersionsPerPage = "50"
+
+func (a *API) registerBoardSnapshotsRoutes(r *mux.Router) {
+	// Board history APIs
+	r.HandleFunc("/boards/{boardID}/versions", a.sessionRequired(a.handleGetBoardVersions)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/snapshot", a.sessionRequired(a.handleGetBoardSnapshot)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/diff", a.sessionRequired(a.handleDiffBoardSnapshots)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/restore", a.sessionRequired(a.handleRestoreBoardSnapshot)).Methods("POST")
+}
+
+// parseTimeParam parses a time in miliseconds from the query string.
+func parseTimeParam(r *http.Request, name string, required bool) (int64, error) {
+	str := r.URL.Query().Get(name)
+	if str == "" {
+		if required {
+			return 0, model.NewErrBadRequest(fmt.Sprintf("missing `%s` parameter", name))
+		}
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || value < 0 {
+		return 0, model.NewErrBadRequest(fmt.Sprintf("invalid `%s` parameter: %s", name, str))
+	}
+	return value, nil
+}
+
+func (a *API) handleGetBoardVersions(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/versions getBoardVersions
+	//
+	// Fetches the latest versions of a board, the newest first. Each
+	// version groups the changes made by a user at the same time.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: before
+	//   in: query
+	//   description: Only return the versions before this time, in miliseconds
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: The maximum number of versions to return
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BoardVersion"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	before, err := parseTimeParam(r, "before", false)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	strPerPage := r.URL.Query().Get("per_page")
+	if strPerPage == "" {
+		strPerPage = defaultBoardVersionsPerPage
+	}
+	perPage, err := strconv.ParseUint(strPerPage, 10, 64)
+	if err != nil {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board history"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardVersions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	versions, err := a.app.GetBoardVersions(boardID, before, perPage)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(versions)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("versionCount", len(versions))
+	auditRec.Success()
+}
+
+func (a *API) handleGetBoardSnapshot(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/snapshot getBoardSnapshot
+	//
+	// Fetches a board and its blocks as they were at a given time.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: at
+	//   in: query
+	//   description: The time of the snapshot, in miliseconds
+	//   required: true
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/BoardSnapshot"
+	//   '404':
+	//     description: board not found at that time
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	at, err := parseTimeParam(r, "at", true)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board history"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardSnapshot", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("at", at)
+
+	snapshot, err := a.app.GetBoardSnapshot(boardID, at)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("blockCount", len(snapshot.Blocks))
+	auditRec.Success()
+}
+
+func (a *API) handleDiffBoardSnapshots(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/diff diffBoardSnapshots
+	//
+	// Fetches the changes of a board and its blocks between two times.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: from
+	//   in: query
+	//   description: The start of the changes, in miliseconds
+	//   required: true
+	//   type: integer
+	// - name: to
+	//   in: query
+	//   description: The end of the changes, in miliseconds
+	//   required: true
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/BoardSnapshotDiff"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	from, err := parseTimeParam(r, "from", true)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	to, err := parseTimeParam(r, "to", true)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board history"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "diffBoardSnapshots", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("from", from)
+	auditRec.AddMeta("to", to)
+
+	diff, err := a.app.DiffBoardSnapshots(boardID, from, to)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleRestoreBoardSnapshot(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/restore restoreBoardSnapshot
+	//
+	// Restores a board, or some of its cards, as they were at a given
+	// time. The restore is recorded as new changes, so it can be reverted.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the time and the cards to restore
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BoardSnapshotRestoreRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/BoardSnapshotRestore"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var req model.BoardSnapshotRestoreRequest
+	if err = json.Unmarshal(requestBody, &req); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to restore board"))
+		return
+	}
+	if len(req.CardIDs) == 0 && !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to restore board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreBoardSnapshot", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("at", req.At)
+	auditRec.AddMeta("cardCount", len(req.CardIDs))
+
+	restore, err := a.app.RestoreBoardSnapshot(boardID, &req, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(restore)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("restoredCount", len(restore.Blocks))
+	auditRec.AddMeta("deletedCount", len(restore.DeletedBlockIDs))
+	auditRec.Success()
+}
@@ -37,6 +37,11 @@ func (a *API) handleGetBoards(w http.ResponseWriter, r *http.Request) {
 	//   description: Team ID
 	//   required: true
 	//   type: string
+	// - name: include_archived
+	//   in: query
+	//   description: Whether to include the archived boards (default=false)
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -75,6 +80,9 @@ func (a *API) handleGetBoards(w http.ResponseWriter, r *http.Request) {
 		a.errorResponse(w, r, err)
 		return
 	}
+	if !includeArchived(r) {
+		boards = model.FilterArchivedBoards(boards)
+	}
 
 	a.logger.Debug("GetBoards",
 		mlog.String("teamID", teamID),
@@ -318,7 +326,8 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	//       "$ref": "#/definitions/ErrorResponse"
 
 	boardID := mux.Vars(r)["boardID"]
-	if _, err := a.app.GetBoard(boardID); err != nil {
+	oldBoard, err := a.app.GetBoard(boardID)
+	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
@@ -371,6 +380,9 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 		a.errorResponse(w, r, err)
 		return
 	}
+	if !model.BoardContentEqual(oldBoard, updatedBoard) {
+		a.app.RecordUndoEntry(userID, boardID, model.UndoOperationPatchBoard, &model.BoardChange{Before: oldBoard, After: updatedBoard}, nil)
+	}
 
 	a.logger.Debug("PatchBoard",
 		mlog.String("boardID", boardID),
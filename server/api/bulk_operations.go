@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerBulkOperationsRoutes(r *mux.Router) {
+	// Bulk Operations APIs
+	r.HandleFunc("/boards/{boardID}/cards/bulk", a.sessionRequired(a.handleStartBulkOperation)).Methods("POST")
+	r.HandleFunc("/bulk_operations/{operationID}", a.sessionRequired(a.handleGetBulkOperation)).Methods("GET")
+}
+
+func (a *API) handleStartBulkOperation(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/cards/bulk startBulkOperation
+	//
+	// Applies an operation to every card of a board matching a filter:
+	// set or clear a property, move to another board, archive, delete or
+	// subscribe a user. Large operations run in the background, report
+	// their progress over websocket and respond with 202. The changes are
+	// undone as a single unit, except for the subscriptions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the filter and the operation
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BulkOperationRequest"
+	// - name: disable_notify
+	//   in: query
+	//   description: Disables notifications (for bulk data inserting)
+	//   required: false
+	//   type: bool
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: the operation is done
+	//     schema:
+	//       "$ref": "#/definitions/BulkOperation"
+	//   '202':
+	//     description: the operation runs in the background
+	//     schema:
+	//       "$ref": "#/definitions/BulkOperation"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+	disableNotify := r.URL.Query().Get("disable_notify") == True
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var request model.BulkOperationRequest
+	if err = json.Unmarshal(requestBody, &request); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify board cards"))
+		return
+	}
+	if request.Type == model.BulkOperationMoveToBoard &&
+		!a.permissions.HasPermissionToBoard(userID, request.TargetBoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify target board cards"))
+		return
+	}
+	// only the board admins can subscribe other users
+	if request.Type == model.BulkOperationAddSubscriber && request.SubscriberID != userID &&
+		!a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardRoles) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to subscribe other users"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "startBulkOperation", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("type", request.Type)
+
+	operation, err := a.app.StartBulkOperation(boardID, userID, &request, disableNotify)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("operationID", operation.ID)
+	auditRec.AddMeta("total", operation.Total)
+
+	a.logger.Debug("StartBulkOperation",
+		mlog.String("boardID", boardID),
+		mlog.String("operationID", operation.ID),
+		mlog.String("type", string(operation.Type)),
+		mlog.Int("total", operation.Total),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(operation)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if !operation.IsDone() {
+		status = http.StatusAccepted
+	}
+
+	// response
+	jsonBytesResponse(w, status, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetBulkOperation(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /bulk_operations/{operationID} getBulkOperation
+	//
+	// Returns the progress of a bulk operation.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: operationID
+	//   in: path
+	//   description: Bulk operation ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/BulkOperation"
+	//   '404':
+	//     description: bulk operation not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	operationID := mux.Vars(r)["operationID"]
+
+	operation, err := a.app.GetBulkOperation(operationID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, operation.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to bulk operation"))
+		return
+	}
+
+	data, err := json.Marshal(operation)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerCardMoveRoutes(r *mux.Router) {
+	// Card move APIs
+	r.HandleFunc("/cards/{cardID}/move", a.sessionRequired(a.handleMoveCard)).Methods("POST")
+	r.HandleFunc("/cards/{cardID}/move/preview", a.sessionRequired(a.handlePreviewCardMove)).Methods("POST")
+}
+
+// readCardMoveRequest reads the move request of a card and checks that
+// the user has a permission on the boards of the card and of the target.
+func (a *API) readCardMoveRequest(r *http.Request, permission *mmModel.Permission) (*model.Block, *model.CardMoveRequest, error) {
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var request model.CardMoveRequest
+	if err = json.Unmarshal(requestBody, &request); err != nil {
+		return nil, nil, model.NewErrBadRequest(err.Error())
+	}
+
+	card, err := a.app.GetBlockByID(cardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, permission) {
+		return nil, nil, model.NewErrPermission("access denied to card")
+	}
+	if request.TargetBoardID != "" && !a.permissions.HasPermissionToBoard(userID, request.TargetBoardID, permission) {
+		return nil, nil, model.NewErrPermission("access denied to target board")
+	}
+	return card, &request, nil
+}
+
+func (a *API) handlePreviewCardMove(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/move/preview previewCardMove
+	//
+	// Returns how the property values of a card would be mapped to the
+	// properties of the target board, without moving it.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the move request
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardMoveRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardMoveMapping"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	card, request, err := a.readCardMoveRequest(r, model.PermissionViewBoard)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	mapping, err := a.app.PreviewCardMove(card.ID, request)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(mapping)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleMoveCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/move moveCard
+	//
+	// Moves a card to another board. The card, its content blocks,
+	// comments and files are copied to the target board, mapping the
+	// properties by name and the select options by value. The source card
+	// is deleted, or archived and linked to the moved card.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the move request
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardMoveRequest"
+	// - name: disable_notify
+	//   in: query
+	//   description: Disables notifications (for bulk data inserting)
+	//   required: false
+	//   type: bool
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardMoveResult"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	disableNotify := r.URL.Query().Get("disable_notify") == True
+
+	card, request, err := a.readCardMoveRequest(r, model.PermissionManageBoardCards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	// the missing options are added to the properties of the target board
+	if request.CreateMissingOptions &&
+		!a.permissions.HasPermissionToBoard(userID, request.TargetBoardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify target board properties"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "moveCard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("targetBoardID", request.TargetBoardID)
+
+	result, err := a.app.MoveCardWithMapping(card.ID, userID, request, disableNotify)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("movedCardID", result.Card.ID)
+
+	a.logger.Debug("MoveCard",
+		mlog.String("cardID", card.ID),
+		mlog.String("movedCardID", result.Card.ID),
+		mlog.String("targetBoardID", request.TargetBoardID),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerCardTemplatesRoutes(r *mux.Router) {
+	// Card templates APIs
+	r.HandleFunc("/cards/{cardID}/template_variables", a.sessionRequired(a.handleGetCardTemplateVariables)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/instantiate", a.sessionRequired(a.handleCreateCardFromTemplate)).Methods("POST")
+}
+
+func (a *API) handleGetCardTemplateVariables(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/template_variables getCardTemplateVariables
+	//
+	// Returns the variables of a template card.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: ID of the template card
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardTemplateVariables"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	templateID := mux.Vars(r)["cardID"]
+
+	template, err := a.app.GetBlockByID(templateID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, template.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to card template"))
+		return
+	}
+
+	variables, err := a.app.GetCardTemplateVariables(templateID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(variables)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleCreateCardFromTemplate(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/instantiate createCardFromTemplate
+	//
+	// Creates a card from a template card. The variables of the titles
+	// and of the default property values of the template, such as
+	// {{date}}, {{user}} or custom ones, are replaced.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: ID of the template card
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the values of the custom variables
+	//   required: false
+	//   schema:
+	//     "$ref": "#/definitions/CardFromTemplateRequest"
+	// - name: disable_notify
+	//   in: query
+	//   description: Disables notifications (for bulk data inserting)
+	//   required: false
+	//   type: bool
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Card"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	templateID := mux.Vars(r)["cardID"]
+	disableNotify := r.URL.Query().Get("disable_notify") == True
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var request model.CardFromTemplateRequest
+	if len(requestBody) != 0 {
+		if err = json.Unmarshal(requestBody, &request); err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+			return
+		}
+	}
+
+	template, err := a.app.GetBlockByID(templateID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, template.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createCardFromTemplate", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", template.BoardID)
+	auditRec.AddMeta("templateID", templateID)
+
+	blocks, err := a.app.CreateCardFromTemplate(templateID, userID, request.Variables, disableNotify)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	changes := make([]model.BlockChange, 0, len(blocks))
+	for _, block := range blocks {
+		changes = append(changes, model.BlockChange{After: block})
+	}
+	a.app.RecordUndoEntry(userID, template.BoardID, model.UndoOperationInsertBlocks, nil, changes)
+
+	card, err := model.Block2Card(blocks[0])
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("cardID", card.ID)
+
+	a.logger.Debug("CreateCardFromTemplate",
+		mlog.String("boardID", card.BoardID),
+		mlog.String("templateID", templateID),
+		mlog.String("cardID", card.ID),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(card)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
@@ -25,6 +25,12 @@ func (a *API) registerCardsRoutes(r *mux.Router) {
 	r.HandleFunc("/boards/{boardID}/cards", a.sessionRequired(a.handleGetCards)).Methods("GET")
 	r.HandleFunc("/cards/{cardID}", a.sessionRequired(a.handlePatchCard)).Methods("PATCH")
 	r.HandleFunc("/cards/{cardID}", a.sessionRequired(a.handleGetCard)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/dependencies", a.sessionRequired(a.handleGetCardDependencies)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/blockers", a.sessionRequired(a.handleAddCardBlocker)).Methods("POST")
+	r.HandleFunc("/cards/{cardID}/blockers/{blockerID}", a.sessionRequired(a.handleDeleteCardBlocker)).Methods("DELETE")
+	r.HandleFunc("/cards/{cardID}/recurrence", a.sessionRequired(a.handleGetCardRecurrence)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/recurrence", a.sessionRequired(a.handleSetCardRecurrence)).Methods("PUT")
+	r.HandleFunc("/cards/{cardID}/recurrence", a.sessionRequired(a.handleDeleteCardRecurrence)).Methods("DELETE")
 }
 
 func (a *API) handleCreateCard(w http.ResponseWriter, r *http.Request) {
@@ -108,6 +114,7 @@ func (a *API) handleCreateCard(w http.ResponseWriter, r *http.Request) {
 		a.errorResponse(w, r, err)
 		return
 	}
+	a.app.RecordUndoEntry(userID, boardID, model.UndoOperationInsertBlocks, nil, []model.BlockChange{{After: model.Card2Block(card)}})
 
 	a.logger.Debug("CreateCard",
 		mlog.String("boardID", boardID),
@@ -151,6 +158,11 @@ func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 	//   description: Number of cards to return per page(default=100)
 	//   required: false
 	//   type: integer
+	// - name: include_archived
+	//   in: query
+	//   description: Whether to include the archived cards (default=false)
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -200,8 +212,9 @@ func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("boardID", boardID)
 	auditRec.AddMeta("page", page)
 	auditRec.AddMeta("per_page", perPage)
+	auditRec.AddMeta("include_archived", includeArchived(r))
 
-	cards, err := a.app.GetCardsForBoard(boardID, page, perPage)
+	cards, err := a.app.GetCardsForBoard(boardID, page, perPage, includeArchived(r))
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
@@ -299,12 +312,20 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("boardID", card.BoardID)
 	auditRec.AddMeta("cardID", card.ID)
 
+	// the block keeps the fields that are not part of the card
+	oldBlock, err := a.app.GetBlockByID(card.ID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	// patch card
 	cardPatched, err := a.app.PatchCard(patch, card.ID, userID, disableNotify)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
+	a.app.RecordUndoBlockPatches(userID, []*model.Block{oldBlock})
 
 	a.logger.Debug("PatchCard",
 		mlog.String("boardID", cardPatched.BoardID),
@@ -387,3 +408,447 @@ func (a *API) handleGetCard(w http.ResponseWriter, r *http.Request) {
 
 	auditRec.Success()
 }
+
+func (a *API) handleGetCardDependencies(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/dependencies getCardDependencies
+	//
+	// Fetches the cards blocking and blocked by the specified card.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/CardDependencies'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		message := fmt.Sprintf("could not fetch card %s: %s", cardID, err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch card dependencies"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardDependencies", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	dependencies, err := a.app.GetCardDependencies(card.ID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(dependencies)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleAddCardBlocker(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/blockers addCardBlocker
+	//
+	// Adds a card that blocks the specified card.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: ID of the blocked card
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the dependency, only blockerId is required
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardDependency"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/CardDependency'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	dependency, err := model.CardDependencyFromJSON(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	dependency.BlockedID = cardID
+	dependency.CreatedBy = userID
+	dependency.CreateAt = 0
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		message := fmt.Sprintf("could not fetch card %s: %s", cardID, err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify card dependencies"))
+		return
+	}
+
+	blocker, err := a.app.GetCardByID(dependency.BlockerID)
+	if err != nil {
+		message := fmt.Sprintf("could not fetch card %s: %s", dependency.BlockerID, err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, blocker.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to blocker card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "addCardBlocker", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("blockerID", blocker.ID)
+
+	dependency, err = a.app.AddCardDependency(dependency)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AddCardBlocker",
+		mlog.String("cardID", card.ID),
+		mlog.String("blockerID", blocker.ID),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(dependency)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteCardBlocker(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /cards/{cardID}/blockers/{blockerID} deleteCardBlocker
+	//
+	// Removes a card from the blockers of the specified card.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: ID of the blocked card
+	//   required: true
+	//   type: string
+	// - name: blockerID
+	//   in: path
+	//   description: ID of the blocker card
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	cardID := vars["cardID"]
+	blockerID := vars["blockerID"]
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		message := fmt.Sprintf("could not fetch card %s: %s", cardID, err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify card dependencies"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteCardBlocker", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("blockerID", blockerID)
+
+	if err := a.app.DeleteCardDependency(blockerID, card.ID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteCardBlocker",
+		mlog.String("cardID", card.ID),
+		mlog.String("blockerID", blockerID),
+		mlog.String("userID", userID),
+	)
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetCardRecurrence(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/recurrence getCardRecurrence
+	//
+	// Fetches the recurrence of the specified template card.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Template card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/CardRecurrence'
+	//   '404':
+	//     description: the card has no recurrence
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		message := fmt.Sprintf("could not fetch card %s: %s", cardID, err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch card recurrence"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardRecurrence", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	recurrence, err := a.app.GetCardRecurrence(card.ID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(recurrence)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleSetCardRecurrence(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /cards/{cardID}/recurrence setCardRecurrence
+	//
+	// Sets the recurrence of the specified template card. New cards are
+	// created from the template following the rule, in the timezone of
+	// the current user.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Template card ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the recurrence, only rule is required
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardRecurrence"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/CardRecurrence'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	recurrence, err := model.CardRecurrenceFromJSON(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	recurrence.CardID = cardID
+	recurrence.CreatedBy = userID
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		message := fmt.Sprintf("could not fetch card %s: %s", cardID, err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify card recurrence"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "setCardRecurrence", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("rule", recurrence.Rule)
+
+	recurrence, err = a.app.SetCardRecurrence(recurrence)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("SetCardRecurrence",
+		mlog.String("cardID", card.ID),
+		mlog.String("rule", recurrence.Rule),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(recurrence)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteCardRecurrence(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /cards/{cardID}/recurrence deleteCardRecurrence
+	//
+	// Removes the recurrence of the specified template card.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Template card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.app.GetCardByID(cardID)
+	if err != nil {
+		message := fmt.Sprintf("could not fetch card %s: %s", cardID, err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify card recurrence"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteCardRecurrence", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	if err := a.app.DeleteCardRecurrence(card.ID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteCardRecurrence",
+		mlog.String("cardID", card.ID),
+		mlog.String("userID", userID),
+	)
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
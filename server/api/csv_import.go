@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// CSVImportOptionsFormKey is the form field of the options of a CSV
+	// import, as JSON.
+	CSVImportOptionsFormKey = "options"
+)
+
+func (a *API) registerCSVImportRoutes(r *mux.Router) {
+	// CSV import APIs
+	r.HandleFunc("/boards/{boardID}/import/csv", a.sessionRequired(a.handleImportCSV)).Methods("POST")
+}
+
+func (a *API) handleImportCSV(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/import/csv importCSV
+	//
+	// Imports the rows of a CSV file as cards of a board, mapping the
+	// columns to the board properties. Cards can be updated by the value of
+	// a key column, and dry runs report the errors of each row without
+	// writing anything.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: file
+	//   in: formData
+	//   description: CSV file to import
+	//   required: true
+	//   type: file
+	// - name: options
+	//   in: formData
+	//   description: the import options, as a JSON CSVImportOptions
+	//   required: false
+	//   type: string
+	// - name: disable_notify
+	//   in: query
+	//   description: Disables notifications (for bulk data inserting)
+	//   required: false
+	//   type: bool
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CSVImportResult"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+	disableNotify := r.URL.Query().Get("disable_notify") == True
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to import cards"))
+		return
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	var opts model.CSVImportOptions
+	if optsJSON := r.FormValue(CSVImportOptionsFormKey); optsJSON != "" {
+		if err = json.Unmarshal([]byte(optsJSON), &opts); err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+			return
+		}
+	}
+
+	// the missing options are added to the properties of the board
+	if opts.CreateMissingOptions && !opts.DryRun &&
+		!a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify board properties"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "importCSV", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+	auditRec.AddMeta("dryRun", opts.DryRun)
+
+	result, err := a.app.ImportCSV(boardID, userID, file, &opts, disableNotify)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("created", result.Created)
+	auditRec.AddMeta("updated", result.Updated)
+
+	a.logger.Debug("ImportCSV",
+		mlog.String("boardID", boardID),
+		mlog.String("userID", userID),
+		mlog.Int("rows", result.Rows),
+		mlog.Int("created", result.Created),
+		mlog.Int("updated", result.Updated),
+		mlog.Int("skipped", result.Skipped),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
@@ -0,0 +1,363 @@
+package api
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) registerRetentionPoliciesRoutes(r *mux.Router) {
+	// Retention policies APIs
+	r.HandleFunc("/retentionpolicies", a.sessionRequired(a.handleGetRetentionPolicies)).Methods("GET")
+	r.HandleFunc("/retentionpolicies/preview", a.sessionRequired(a.handlePreviewDataRetention)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/retentionpolicy", a.sessionRequired(a.handleUpsertTeamRetentionPolicy)).Methods("PUT")
+	r.HandleFunc("/teams/{teamID}/retentionpolicy", a.sessionRequired(a.handleDeleteTeamRetentionPolicy)).Methods("DELETE")
+	r.HandleFunc("/boards/{boardID}/retentionpolicy", a.sessionRequired(a.handleUpsertBoardRetentionPolicy)).Methods("PUT")
+	r.HandleFunc("/boards/{boardID}/retentionpolicy", a.sessionRequired(a.handleDeleteBoardRetentionPolicy)).Methods("DELETE")
+}
+
+// registerAdminRetentionPoliciesRoutes exposes the retention policies APIs
+// on the local mode admin socket, for the servers without system admins.
+func (a *API) registerAdminRetentionPoliciesRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v2/admin/retentionpolicies", a.adminRequired(a.handleGetRetentionPolicies)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/retentionpolicies/preview", a.adminRequired(a.handlePreviewDataRetention)).Methods("GET")
+	r.HandleFunc("/api/v2/admin/teams/{teamID}/retentionpolicy", a.adminRequired(a.handleUpsertTeamRetentionPolicy)).Methods("PUT")
+	r.HandleFunc("/api/v2/admin/teams/{teamID}/retentionpolicy", a.adminRequired(a.handleDeleteTeamRetentionPolicy)).Methods("DELETE")
+	r.HandleFunc("/api/v2/admin/boards/{boardID}/retentionpolicy", a.adminRequired(a.handleUpsertBoardRetentionPolicy)).Methods("PUT")
+	r.HandleFunc("/api/v2/admin/boards/{boardID}/retentionpolicy", a.adminRequired(a.handleDeleteBoardRetentionPolicy)).Methods("DELETE")
+}
+
+// canManageRetention tells if the request comes from the local mode admin
+// socket, from a system admin or, when teamID is not empty, from an admin
+// of the team.
+func (a *API) canManageRetention(r *http.Request, teamID string) bool {
+	if _, isUnix := GetContextConn(r).(*net.UnixConn); isUnix {
+		return true
+	}
+
+	userID := getUserID(r)
+	if a.permissions.HasPermissionTo(userID, model.PermissionManageSystem) {
+		return true
+	}
+	return teamID != "" && a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam)
+}
+
+func (a *API) handleGetRetentionPolicies(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /retentionpolicies getRetentionPolicies
+	//
+	// Returns the retention policies of the teams and of their boards.
+	// Only system admins can list the policies of all the teams.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: team_id
+	//   in: query
+	//   description: Only returns the policies of this team
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/RetentionPolicy"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := r.URL.Query().Get("team_id")
+
+	if !a.canManageRetention(r, teamID) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to retention policies"))
+		return
+	}
+
+	policies, err := a.app.GetRetentionPolicies(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(policies)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handlePreviewDataRetention(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /retentionpolicies/preview previewDataRetention
+	//
+	// Returns the boards that the data retention would delete now, with
+	// the policy that made each of them expire. Only system admins can
+	// preview the boards of all the teams.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: team_id
+	//   in: query
+	//   description: Only returns the boards of this team
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/RetentionCandidate"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := r.URL.Query().Get("team_id")
+
+	if !a.canManageRetention(r, teamID) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to data retention preview"))
+		return
+	}
+
+	candidates, err := a.app.PreviewDataRetention(time.Now(), teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(candidates)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleUpsertTeamRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /teams/{teamID}/retentionpolicy upsertTeamRetentionPolicy
+	//
+	// Creates or replaces the retention policy of a team. It applies to the
+	// boards of the team without a board policy.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the retention policy, only retentionDays is used
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RetentionPolicy"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/RetentionPolicy"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	a.upsertRetentionPolicy(w, r, teamID, "")
+}
+
+func (a *API) handleUpsertBoardRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /boards/{boardID}/retentionpolicy upsertBoardRetentionPolicy
+	//
+	// Creates or replaces the retention policy of a board. It takes
+	// precedence over the policy of the team.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the retention policy, only retentionDays is used
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RetentionPolicy"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/RetentionPolicy"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	a.upsertRetentionPolicy(w, r, board.TeamID, boardID)
+}
+
+func (a *API) upsertRetentionPolicy(w http.ResponseWriter, r *http.Request, teamID, boardID string) {
+	if !a.canManageRetention(r, teamID) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to retention policies"))
+		return
+	}
+
+	userID := getUserID(r)
+	if userID == "" {
+		userID = model.SystemUserID
+	}
+
+	policy, err := model.RetentionPolicyFromJSON(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	policy.TeamID = teamID
+	policy.BoardID = boardID
+	policy.ModifiedBy = userID
+
+	auditRec := a.makeAuditRecord(r, "upsertRetentionPolicy", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("retentionDays", policy.RetentionDays)
+
+	policy, err = a.app.UpsertRetentionPolicy(policy)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteTeamRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID}/retentionpolicy deleteTeamRetentionPolicy
+	//
+	// Deletes the retention policy of a team.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: retention policy not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	a.deleteRetentionPolicy(w, r, teamID, "")
+}
+
+func (a *API) handleDeleteBoardRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/retentionpolicy deleteBoardRetentionPolicy
+	//
+	// Deletes the retention policy of a board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: retention policy not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	a.deleteRetentionPolicy(w, r, board.TeamID, boardID)
+}
+
+func (a *API) deleteRetentionPolicy(w http.ResponseWriter, r *http.Request, teamID, boardID string) {
+	if !a.canManageRetention(r, teamID) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to retention policies"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteRetentionPolicy", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("boardID", boardID)
+
+	if err := a.app.DeleteRetentionPolicy(teamID, boardID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
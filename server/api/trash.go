@@ -0,0 +1,310 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) registerTrashRoutes(r *mux.Router) {
+	// Trash APIs
+	r.HandleFunc("/teams/{teamID}/trash", a.sessionRequired(a.handleGetTrash)).Methods("GET")
+	r.HandleFunc("/trash/boards/{boardID}/restore", a.sessionRequired(a.handleRestoreTrashedBoard)).Methods("POST")
+	r.HandleFunc("/trash/boards/{boardID}", a.sessionRequired(a.handlePurgeTrashedBoard)).Methods("DELETE")
+	r.HandleFunc("/trash/boards/{boardID}/cards/{cardID}/restore", a.sessionRequired(a.handleRestoreTrashedCard)).Methods("POST")
+	r.HandleFunc("/trash/boards/{boardID}/cards/{cardID}", a.sessionRequired(a.handlePurgeTrashedCard)).Methods("DELETE")
+}
+
+func (a *API) handleGetTrash(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/trash getTrash
+	//
+	// Returns the deleted boards and cards of a team that the user can
+	// view, the most recently deleted first.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/TrashItem"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := mux.Vars(r)["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	items, err := a.app.GetTrash(userID, teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleRestoreTrashedBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /trash/boards/{boardID}/restore restoreTrashedBoard
+	//
+	// Restores a deleted board with its cards.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: ID of the deleted board
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Board"
+	//   '404':
+	//     description: deleted board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionDeleteBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to restore board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreTrashedBoard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	board, err := a.app.RestoreTrashedBoard(boardID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handlePurgeTrashedBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /trash/boards/{boardID} purgeTrashedBoard
+	//
+	// Permanently removes a deleted board and all its data. Only system
+	// admins and admins of the board can purge it.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: ID of the deleted board
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: deleted board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.canPurgeTrash(userID, boardID) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to purge board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "purgeTrashedBoard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	if err := a.app.PurgeTrashedBoard(boardID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleRestoreTrashedCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /trash/boards/{boardID}/cards/{cardID}/restore restoreTrashedCard
+	//
+	// Restores a deleted card with its content.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: cardID
+	//   in: path
+	//   description: ID of the deleted card
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Block"
+	//   '404':
+	//     description: deleted card not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	cardID := vars["cardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to restore card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreTrashedCard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("cardID", cardID)
+
+	block, err := a.app.RestoreTrashedCard(boardID, cardID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(block)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handlePurgeTrashedCard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /trash/boards/{boardID}/cards/{cardID} purgeTrashedCard
+	//
+	// Permanently removes a deleted card with its content and history.
+	// Only system admins and admins of the board can purge it.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: cardID
+	//   in: path
+	//   description: ID of the deleted card
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: deleted card not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	cardID := vars["cardID"]
+
+	if !a.canPurgeTrash(userID, boardID) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to purge card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "purgeTrashedCard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("cardID", cardID)
+
+	if err := a.app.PurgeTrashedCard(boardID, cardID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+// canPurgeTrash tells if a user is a system admin or an admin of a board.
+func (a *API) canPurgeTrash(userID, boardID string) bool {
+	return a.permissions.HasPermissionTo(userID, model.PermissionManageSystem) ||
+		a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionDeleteBoard)
+}
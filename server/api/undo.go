@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) registerUndoRoutes(r *mux.Router) {
+	// Undo APIs
+	r.HandleFunc("/boards/{boardID}/undo", a.sessionRequired(a.handleGetUndoState)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/undo", a.sessionRequired(a.handleUndo)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/redo", a.sessionRequired(a.handleRedo)).Methods("POST")
+}
+
+func (a *API) handleGetUndoState(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/undo getUndoState
+	//
+	// Returns the next changes the user can undo and redo on a board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UndoState"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	state, err := a.app.GetUndoState(userID, boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleUndo(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/undo undo
+	//
+	// Reverts the last change of the user on a board. Fails with a
+	// conflict if other users changed the same data since, unless forced.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: force
+	//   in: query
+	//   description: Reverts the change even if other users changed the same data since
+	//   required: false
+	//   type: boolean
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UndoResult"
+	//   '404':
+	//     description: nothing to undo
+	//   '409':
+	//     description: changed by other users
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.handleApplyUndoEntry(w, r, false)
+}
+
+func (a *API) handleRedo(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/redo redo
+	//
+	// Applies again the last change undone by the user on a board. Fails
+	// with a conflict if other users changed the same data since, unless
+	// forced.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: force
+	//   in: query
+	//   description: Applies the change even if other users changed the same data since
+	//   required: false
+	//   type: boolean
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UndoResult"
+	//   '404':
+	//     description: nothing to redo
+	//   '409':
+	//     description: changed by other users
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	a.handleApplyUndoEntry(w, r, true)
+}
+
+func (a *API) handleApplyUndoEntry(w http.ResponseWriter, r *http.Request, redo bool) {
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+	force := r.URL.Query().Get("force") == True
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to make board changes"))
+		return
+	}
+
+	state, err := a.app.GetUndoState(userID, boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	entry := state.Undo
+	if redo {
+		entry = state.Redo
+	}
+	if entry != nil && entry.BoardChange != nil &&
+		!a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modifying board properties"))
+		return
+	}
+
+	action, apply := "undo", a.app.Undo
+	if redo {
+		action, apply = "redo", a.app.Redo
+	}
+
+	auditRec := a.makeAuditRecord(r, action, audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("force", force)
+
+	result, err := apply(userID, boardID, force)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("entryID", result.Entry.ID)
+	auditRec.AddMeta("operation", result.Entry.Operation)
+	auditRec.Success()
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/mattermost/focalboard/server/auth"
+	"github.com/mattermost/focalboard/server/services/audit"
 	"github.com/mattermost/focalboard/server/services/config"
 	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/focalboard/server/services/notify"
@@ -24,6 +25,9 @@ const (
 	blockChangeNotifierQueueSize       = 1000
 	blockChangeNotifierPoolSize        = 10
 	blockChangeNotifierShutdownTimeout = time.Second * 10
+
+	automationWebhookQueueSize = 1000
+	automationWebhookPoolSize  = 5
 )
 
 type servicesAPI interface {
@@ -39,6 +43,7 @@ type fileBackend interface {
 	MoveFile(oldPath, newPath string) error
 	WriteFile(fr io.Reader, path string) (int64, error)
 	RemoveFile(path string) error
+	ListDirectoryRecursively(path string) ([]string, error)
 }
 
 type Services struct {
@@ -52,6 +57,7 @@ type Services struct {
 	Permissions      permissions.PermissionsService
 	SkipTemplateInit bool
 	ServicesAPI      servicesAPI
+	Audit            *audit.Audit
 }
 
 type App struct {
@@ -66,7 +72,10 @@ type App struct {
 	logger              mlog.LoggerIFace
 	permissions         permissions.PermissionsService
 	blockChangeNotifier *utils.CallbackQueue
+	webhookQueue        *utils.CallbackQueue
+	bulkOperationQueue  *utils.CallbackQueue
 	servicesAPI         servicesAPI
+	audit               *audit.Audit
 
 	cardLimitMux sync.RWMutex
 	cardLimit    int
@@ -93,7 +102,10 @@ func New(config *config.Configuration, wsAdapter ws.Adapter, services Services)
 		logger:              services.Logger,
 		permissions:         services.Permissions,
 		blockChangeNotifier: utils.NewCallbackQueue("blockChangeNotifier", blockChangeNotifierQueueSize, blockChangeNotifierPoolSize, services.Logger),
+		webhookQueue:        utils.NewCallbackQueue("automationWebhooks", automationWebhookQueueSize, automationWebhookPoolSize, services.Logger),
+		bulkOperationQueue:  utils.NewCallbackQueue("bulkOperations", bulkOperationQueueSize, bulkOperationPoolSize, services.Logger),
 		servicesAPI:         services.ServicesAPI,
+		audit:               services.Audit,
 	}
 	app.initialize(services.SkipTemplateInit)
 	return app
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *App) GetArchivedPropertyValues(boardID string) ([]*model.ArchivedPropertyValue, error) {
+	return a.store.GetArchivedPropertyValues(boardID)
+}
+
+// RestoreArchivedPropertyValues writes back the archived card values of
+// a property, which must be part of the board again.
+func (a *App) RestoreArchivedPropertyValues(boardID, propertyID, userID string) ([]*model.Block, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := schema[propertyID]; !ok {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("property %s is not part of the board", propertyID))
+	}
+
+	cards, err := a.store.RestoreArchivedPropertyValues(boardID, propertyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.updateBoardComputedProperties(board, userID)
+	a.blockChangeNotifier.Enqueue(func() error {
+		for _, card := range cards {
+			a.wsAdapter.BroadcastBlockChange(board.TeamID, card)
+		}
+		return nil
+	})
+	return cards, nil
+}
+
+// broadcastCardPropertyCleanup sends the cards of a board whose values
+// were cleaned up after a change of its card properties.
+func (a *App) broadcastCardPropertyCleanup(oldBoard, board *model.Board, remappedOptions map[string]map[string]string) {
+	oldSchema, err := model.ParsePropertySchema(oldBoard)
+	if err != nil {
+		return
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return
+	}
+	if model.NewPropertyCleanup(oldSchema, schema, remappedOptions).IsEmpty() {
+		return
+	}
+
+	cards, err := a.store.GetBlocksWithType(board.ID, model.TypeCard)
+	if err != nil {
+		a.logger.Error("broadcastCardPropertyCleanup cannot get the board cards", mlog.String("board_id", board.ID), mlog.Err(err))
+		return
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		for _, card := range cards {
+			a.wsAdapter.BroadcastBlockChange(board.TeamID, card)
+		}
+		return nil
+	})
+}
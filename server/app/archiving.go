@@ -0,0 +1,97 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+// ArchiveCard archives a card, which hides it from the boards without
+// removing it from the searches and the exports.
+func (a *App) ArchiveCard(cardID, userID string) (*model.Block, error) {
+	return a.setCardArchived(cardID, true, userID)
+}
+
+// UnarchiveCard restores an archived card.
+func (a *App) UnarchiveCard(cardID, userID string) (*model.Block, error) {
+	return a.setCardArchived(cardID, false, userID)
+}
+
+func (a *App) setCardArchived(cardID string, archived bool, userID string) (*model.Block, error) {
+	card, err := a.store.GetBlock(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.Type != model.TypeCard {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("block %s is not a card", cardID))
+	}
+	if (card.ArchiveAt != 0) == archived {
+		return card, nil
+	}
+
+	var archiveAt int64
+	if archived {
+		archiveAt = utils.GetMillis()
+	}
+
+	card, err = a.store.SetBlockArchiveAt(cardID, archiveAt, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastCardArchivedStateChange(board.TeamID, card)
+		a.webhook.NotifyUpdate(card)
+		return nil
+	})
+	return card, nil
+}
+
+// ArchiveBoard archives a board, which hides it from the board lists
+// without removing its content.
+func (a *App) ArchiveBoard(boardID, userID string) (*model.Board, error) {
+	return a.setBoardArchived(boardID, true, userID)
+}
+
+// UnarchiveBoard restores an archived board.
+func (a *App) UnarchiveBoard(boardID, userID string) (*model.Board, error) {
+	return a.setBoardArchived(boardID, false, userID)
+}
+
+func (a *App) setBoardArchived(boardID string, archived bool, userID string) (*model.Board, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if board.IsTemplate {
+		return nil, model.NewErrBadRequest("templates cannot be archived")
+	}
+	if (board.ArchiveAt != 0) == archived {
+		return board, nil
+	}
+
+	var archiveAt int64
+	if archived {
+		archiveAt = utils.GetMillis()
+	}
+
+	board, err = a.store.SetBoardArchiveAt(boardID, archiveAt, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBoardArchivedStateChange(board.TeamID, board)
+		return nil
+	})
+	return board, nil
+}
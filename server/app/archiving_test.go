@@ -0,0 +1,97 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestArchiveCard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{ID: testBoardID, TeamID: "team-id"}
+	th.Store.EXPECT().GetMembersForBoard(testBoardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+
+	t.Run("archives a card", func(t *testing.T) {
+		card := &model.Block{ID: "card-id", BoardID: testBoardID, Type: model.TypeCard}
+		archived := &model.Block{ID: "card-id", BoardID: testBoardID, Type: model.TypeCard, ArchiveAt: 100}
+		th.Store.EXPECT().GetBlock("card-id").Return(card, nil)
+		th.Store.EXPECT().SetBlockArchiveAt("card-id", gomock.Not(int64(0)), "user-id").Return(archived, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+
+		result, err := th.App.ArchiveCard("card-id", "user-id")
+		require.NoError(t, err)
+		require.EqualValues(t, 100, result.ArchiveAt)
+	})
+
+	t.Run("unarchives a card", func(t *testing.T) {
+		card := &model.Block{ID: "card-id", BoardID: testBoardID, Type: model.TypeCard, ArchiveAt: 100}
+		unarchived := &model.Block{ID: "card-id", BoardID: testBoardID, Type: model.TypeCard}
+		th.Store.EXPECT().GetBlock("card-id").Return(card, nil)
+		th.Store.EXPECT().SetBlockArchiveAt("card-id", int64(0), "user-id").Return(unarchived, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+
+		result, err := th.App.UnarchiveCard("card-id", "user-id")
+		require.NoError(t, err)
+		require.Zero(t, result.ArchiveAt)
+	})
+
+	t.Run("a card already archived is unchanged", func(t *testing.T) {
+		card := &model.Block{ID: "card-id", BoardID: testBoardID, Type: model.TypeCard, ArchiveAt: 100}
+		th.Store.EXPECT().GetBlock("card-id").Return(card, nil)
+		th.Store.EXPECT().SetBlockArchiveAt(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		result, err := th.App.ArchiveCard("card-id", "user-id")
+		require.NoError(t, err)
+		require.EqualValues(t, 100, result.ArchiveAt)
+	})
+
+	t.Run("only cards can be archived", func(t *testing.T) {
+		block := &model.Block{ID: "block-id", BoardID: testBoardID, Type: model.TypeText}
+		th.Store.EXPECT().GetBlock("block-id").Return(block, nil)
+
+		_, err := th.App.ArchiveCard("block-id", "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestArchiveBoard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.Store.EXPECT().GetMembersForBoard(testBoardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+
+	t.Run("archives a board", func(t *testing.T) {
+		board := &model.Board{ID: testBoardID, TeamID: "team-id"}
+		archived := &model.Board{ID: testBoardID, TeamID: "team-id", ArchiveAt: 100}
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().SetBoardArchiveAt(testBoardID, gomock.Not(int64(0)), "user-id").Return(archived, nil)
+
+		result, err := th.App.ArchiveBoard(testBoardID, "user-id")
+		require.NoError(t, err)
+		require.EqualValues(t, 100, result.ArchiveAt)
+	})
+
+	t.Run("unarchives a board", func(t *testing.T) {
+		board := &model.Board{ID: testBoardID, TeamID: "team-id", ArchiveAt: 100}
+		unarchived := &model.Board{ID: testBoardID, TeamID: "team-id"}
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().SetBoardArchiveAt(testBoardID, int64(0), "user-id").Return(unarchived, nil)
+
+		result, err := th.App.UnarchiveBoard(testBoardID, "user-id")
+		require.NoError(t, err)
+		require.Zero(t, result.ArchiveAt)
+	})
+
+	t.Run("templates cannot be archived", func(t *testing.T) {
+		template := &model.Board{ID: testBoardID, TeamID: "team-id", IsTemplate: true}
+		th.Store.EXPECT().GetBoard(testBoardID).Return(template, nil)
+
+		_, err := th.App.ArchiveBoard(testBoardID, "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
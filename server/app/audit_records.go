@@ -0,0 +1,148 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var auditRecordsCSVHeader = []string{
+	"id",
+	"create_at",
+	"time",
+	"level",
+	"event",
+	"status",
+	"user_id",
+	"session_id",
+	"client",
+	"ip_address",
+	"api_path",
+	"team_id",
+	"board_id",
+	"meta",
+}
+
+// auditRecordWriter is the audit target that stores the records in the
+// database.
+type auditRecordWriter struct {
+	store store.Store
+}
+
+func (w *auditRecordWriter) WriteRecord(level mlog.Level, rec *audit.Record) error {
+	return w.store.InsertAuditRecord(model.AuditRecordFromRecord(level.Name, rec))
+}
+
+// NewAuditRecordWriter returns an audit target that stores the records
+// in the database.
+func (a *App) NewAuditRecordWriter() audit.RecordWriter {
+	return &auditRecordWriter{store: a.store}
+}
+
+// GetAuditRecords returns a page of the audit records matching the
+// options, the most recent first.
+func (a *App) GetAuditRecords(opts model.QueryAuditRecordsOptions) (*model.AuditRecordsResponse, error) {
+	if opts.PerPage <= 0 {
+		opts.PerPage = model.AuditRecordsDefaultPerPage
+	}
+	if opts.PerPage > model.AuditRecordsMaxPerPage {
+		opts.PerPage = model.AuditRecordsMaxPerPage
+	}
+
+	records, hasNext, err := a.store.GetAuditRecords(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.AuditRecordsResponse{
+		HasNext: hasNext,
+		Results: records,
+	}
+	if hasNext {
+		response.NextCursor = records[len(records)-1].Cursor()
+	}
+	return response, nil
+}
+
+// ExportAuditRecords writes all the audit records matching the options as
+// CSV, the most recent first. The cells hold values sent by the clients,
+// so the ones starting like a formula are escaped.
+func (a *App) ExportAuditRecords(opts model.QueryAuditRecordsOptions, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditRecordsCSVHeader); err != nil {
+		return err
+	}
+
+	opts.PerPage = model.AuditRecordsMaxPerPage
+	for {
+		page, err := a.GetAuditRecords(opts)
+		if err != nil {
+			return err
+		}
+		for _, record := range page.Results {
+			meta, err := json.Marshal(record.Meta)
+			if err != nil {
+				return err
+			}
+			cells := []string{
+				record.ID,
+				strconv.FormatInt(record.CreateAt, 10),
+				utils.GetTimeForMillis(record.CreateAt).UTC().Format(time.RFC3339Nano),
+				record.Level,
+				record.Event,
+				record.Status,
+				record.UserID,
+				record.SessionID,
+				record.Client,
+				record.IPAddress,
+				record.APIPath,
+				record.TeamID,
+				record.BoardID,
+				string(meta),
+			}
+			for i, cell := range cells {
+				cells[i] = escapeCSVFormula(tableCell{value: cell})
+			}
+			if err := writer.Write(cells); err != nil {
+				return err
+			}
+		}
+		if !page.HasNext {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// purgeExpiredAuditRecords removes the audit records older than the audit
+// retention period.
+func (a *App) purgeExpiredAuditRecords(now time.Time) error {
+	if a.config.AuditDBRetentionDays <= 0 {
+		return nil
+	}
+
+	before := utils.GetMillisForTime(now.AddDate(0, 0, -a.config.AuditDBRetentionDays))
+	deleted, err := a.store.DeleteAuditRecords(before)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		a.logger.Info("Purged expired audit records", mlog.Int("deleted", deleted))
+	}
+	return nil
+}
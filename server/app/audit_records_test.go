@@ -0,0 +1,130 @@
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func TestAuditRecordWriter(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	rec := &audit.Record{Event: "deleteBoard", Status: audit.Success, UserID: "user-id"}
+	rec.AddMeta(audit.KeyTeamID, "team-id")
+	rec.AddMeta("boardID", "board-id")
+
+	th.Store.EXPECT().InsertAuditRecord(gomock.Any()).DoAndReturn(func(record *model.AuditRecord) error {
+		require.NotEmpty(t, record.ID)
+		require.NotZero(t, record.CreateAt)
+		require.Equal(t, "mod", record.Level)
+		require.Equal(t, "deleteBoard", record.Event)
+		require.Equal(t, "team-id", record.TeamID)
+		require.Equal(t, "board-id", record.BoardID)
+		require.Equal(t, "board-id", record.Meta["boardID"])
+		return nil
+	})
+
+	require.NoError(t, th.App.NewAuditRecordWriter().WriteRecord(audit.LevelModify, rec))
+}
+
+func TestGetAuditRecords(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	records := []*model.AuditRecord{
+		{ID: "record-2", CreateAt: 2000, Event: "deleteBoard", Meta: map[string]interface{}{}},
+		{ID: "record-1", CreateAt: 1000, Event: "deleteBoard", Meta: map[string]interface{}{"boardID": "board-id"}},
+	}
+
+	t.Run("returns the cursor of the next page", func(t *testing.T) {
+		th.Store.EXPECT().GetAuditRecords(model.QueryAuditRecordsOptions{PerPage: model.AuditRecordsDefaultPerPage}).Return(records, true, nil)
+
+		response, err := th.App.GetAuditRecords(model.QueryAuditRecordsOptions{})
+		require.NoError(t, err)
+		require.True(t, response.HasNext)
+		require.Equal(t, records[1].Cursor(), response.NextCursor)
+
+		createAt, id, err := model.ParseAuditCursor(response.NextCursor)
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), createAt)
+		require.Equal(t, "record-1", id)
+	})
+
+	t.Run("limits the page size", func(t *testing.T) {
+		th.Store.EXPECT().GetAuditRecords(model.QueryAuditRecordsOptions{PerPage: model.AuditRecordsMaxPerPage}).Return(records, false, nil)
+
+		response, err := th.App.GetAuditRecords(model.QueryAuditRecordsOptions{PerPage: 100000})
+		require.NoError(t, err)
+		require.False(t, response.HasNext)
+		require.Empty(t, response.NextCursor)
+	})
+
+	t.Run("exports all the pages as CSV", func(t *testing.T) {
+		opts := model.QueryAuditRecordsOptions{Event: "deleteBoard", PerPage: model.AuditRecordsMaxPerPage}
+		th.Store.EXPECT().GetAuditRecords(opts).Return(records[:1], true, nil)
+		opts.Cursor = records[0].Cursor()
+		th.Store.EXPECT().GetAuditRecords(opts).Return(records[1:], false, nil)
+
+		var buf bytes.Buffer
+		require.NoError(t, th.App.ExportAuditRecords(model.QueryAuditRecordsOptions{Event: "deleteBoard"}, &buf))
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, auditRecordsCSVHeader, rows[0])
+		require.Equal(t, "record-2", rows[1][0])
+		require.Equal(t, "1970-01-01T00:00:02Z", rows[1][2])
+		require.Equal(t, `{"boardID":"board-id"}`, rows[2][13])
+	})
+
+	t.Run("escapes the cells starting like a formula", func(t *testing.T) {
+		record := &model.AuditRecord{
+			ID:       "record-3",
+			CreateAt: 3000,
+			Event:    "deleteBoard",
+			Client:   `=HYPERLINK("https://example.com","click")`,
+			Meta:     map[string]interface{}{},
+		}
+		th.Store.EXPECT().GetAuditRecords(gomock.Any()).Return([]*model.AuditRecord{record}, false, nil)
+
+		var buf bytes.Buffer
+		require.NoError(t, th.App.ExportAuditRecords(model.QueryAuditRecordsOptions{}, &buf))
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, `'=HYPERLINK("https://example.com","click")`, rows[1][8])
+		require.Equal(t, "3000", rows[1][1])
+	})
+}
+
+func TestPurgeExpiredAuditRecords(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("purges the records older than the retention period", func(t *testing.T) {
+		th.App.config.AuditDBRetentionDays = 30
+		before := utils.GetMillisForTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+		th.Store.EXPECT().DeleteAuditRecords(before).Return(int64(5), nil)
+
+		require.NoError(t, th.App.purgeExpiredAuditRecords(now))
+	})
+
+	t.Run("keeps the records forever when disabled", func(t *testing.T) {
+		th.App.config.AuditDBRetentionDays = 0
+		th.Store.EXPECT().DeleteAuditRecords(gomock.Any()).Times(0)
+
+		require.NoError(t, th.App.purgeExpiredAuditRecords(now))
+	})
+}
@@ -0,0 +1,663 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// maxAutomationDepth is how many times the changes made by rules can
+	// trigger other rules, starting from a change made by a user.
+	maxAutomationDepth = 5
+
+	// automationDateWindow is how long after a date the dateReached rules
+	// can still run, so dates far in the past don't trigger them.
+	automationDateWindow = 24 * time.Hour
+
+	// automationExecutionRetention is how long the execution log is kept.
+	automationExecutionRetention = 30 * 24 * time.Hour
+)
+
+var errAutomationPermission = errors.New("the creator of the rule cannot modify the cards of the board")
+
+// automationChain tracks the rules run from a change made by a user. Each
+// rule runs once per card in a chain, so rules triggering each other
+// cannot loop.
+type automationChain struct {
+	depth int
+	ran   map[string]bool
+}
+
+func newAutomationChain() *automationChain {
+	return &automationChain{ran: map[string]bool{}}
+}
+
+func (c *automationChain) next() *automationChain {
+	return &automationChain{depth: c.depth + 1, ran: c.ran}
+}
+
+// automationWebhookPayload is the body sent by the callWebhook action.
+type automationWebhookPayload struct {
+	RuleID  string                  `json:"ruleId"`
+	BoardID string                  `json:"boardId"`
+	Trigger model.AutomationTrigger `json:"trigger"`
+	Card    *model.Block            `json:"card"`
+}
+
+func (a *App) GetAutomationRules(boardID string) ([]*model.AutomationRule, error) {
+	return a.store.GetAutomationRulesForBoard(boardID)
+}
+
+func (a *App) GetAutomationRule(ruleID string) (*model.AutomationRule, error) {
+	return a.store.GetAutomationRule(ruleID)
+}
+
+// CreateAutomationRule adds a rule to a board. The actions of the rule
+// run as the user creating it.
+func (a *App) CreateAutomationRule(rule *model.AutomationRule, userID string) (*model.AutomationRule, error) {
+	rule.ID = utils.NewID(utils.IDTypeNone)
+	rule.CreatedBy = userID
+	rule.ModifiedBy = userID
+
+	if err := a.validateAutomationRule(rule); err != nil {
+		return nil, err
+	}
+	if err := a.store.InsertAutomationRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateAutomationRule replaces the title, state, trigger and actions of a
+// rule. The actions then run as the user updating it, so that users can't
+// act with the permissions of another user by editing their rules.
+func (a *App) UpdateAutomationRule(rule *model.AutomationRule, userID string) (*model.AutomationRule, error) {
+	existing, err := a.store.GetAutomationRule(rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	rule.BoardID = existing.BoardID
+	rule.CreatedBy = userID
+	rule.CreateAt = existing.CreateAt
+	rule.ModifiedBy = userID
+
+	if err := a.validateAutomationRule(rule); err != nil {
+		return nil, err
+	}
+	if err := a.store.UpdateAutomationRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (a *App) DeleteAutomationRule(ruleID string) error {
+	return a.store.DeleteAutomationRule(ruleID)
+}
+
+// GetAutomationExecutions returns the execution log of a rule, the most
+// recent first.
+func (a *App) GetAutomationExecutions(ruleID string, limit uint64) ([]*model.AutomationExecution, error) {
+	return a.store.GetAutomationExecutions(ruleID, limit)
+}
+
+// TestAutomationRule runs the actions of a rule on a card as a dry run,
+// describing what they would do without changing anything. The trigger of
+// the rule is not checked.
+func (a *App) TestAutomationRule(ruleID, cardID string) (*model.AutomationExecution, error) {
+	rule, err := a.store.GetAutomationRule(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	card, err := a.getCardBlock(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card.BoardID != rule.BoardID {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("card %s is not in the board of rule %s", cardID, ruleID))
+	}
+	board, err := a.store.GetBoard(rule.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	execution := &model.AutomationExecution{
+		RuleID:   rule.ID,
+		BoardID:  rule.BoardID,
+		CardID:   card.ID,
+		DryRun:   true,
+		CreateAt: utils.GetMillis(),
+	}
+	execution.Results, _ = a.applyAutomationActions(rule, board, card, true)
+	execution.Status = automationExecutionStatus(execution.Results)
+	return execution, nil
+}
+
+// validateAutomationRule checks that the properties and boards used by a
+// rule exist.
+func (a *App) validateAutomationRule(rule *model.AutomationRule) error {
+	if err := rule.IsValid(); err != nil {
+		return model.NewErrBadRequest(err.Error())
+	}
+
+	board, err := a.store.GetBoard(rule.BoardID)
+	if err != nil {
+		return err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+
+	checkProperty := func(propertyID string, types ...string) error {
+		propDef, ok := schema[propertyID]
+		if !ok {
+			return model.NewErrBadRequest(fmt.Sprintf("property %s not found in board %s", propertyID, board.ID))
+		}
+		if len(types) != 0 && !containsString(types, propDef.Type) {
+			return model.NewErrBadRequest(fmt.Sprintf("property %s is not of type %v", propertyID, types))
+		}
+		return nil
+	}
+
+	switch rule.Trigger.Type {
+	case model.AutomationTriggerPropertyChanged:
+		if err := checkProperty(rule.Trigger.PropertyID); err != nil {
+			return err
+		}
+	case model.AutomationTriggerDateReached:
+		if err := checkProperty(rule.Trigger.PropertyID, "date"); err != nil {
+			return err
+		}
+	}
+
+	for i, action := range rule.Actions {
+		switch action.Type {
+		case model.AutomationActionSetProperty:
+			if err := checkProperty(action.PropertyID); err != nil {
+				return err
+			}
+		case model.AutomationActionAssignPerson:
+			if err := checkProperty(action.PropertyID, "person", "multiPerson"); err != nil {
+				return err
+			}
+		case model.AutomationActionCallWebhook:
+			if !a.automationWebhookAllowed(action.URL) {
+				return model.NewErrBadRequest(fmt.Sprintf("the host of webhook %s is not allowed", action.URL))
+			}
+		case model.AutomationActionMoveToBoard:
+			if action.BoardID == board.ID {
+				return model.NewErrBadRequest("cannot move cards to the board of the rule")
+			}
+			destBoard, err := a.store.GetBoard(action.BoardID)
+			if err != nil {
+				return err
+			}
+			if destBoard.TeamID != board.TeamID {
+				return model.NewErrBadRequest(fmt.Sprintf("board %s is not in the team of board %s", destBoard.ID, board.ID))
+			}
+			if i != len(rule.Actions)-1 {
+				return model.NewErrBadRequest("moveToBoard must be the last action of a rule")
+			}
+		}
+	}
+	return nil
+}
+
+// runAutomationRules runs the rules triggered by a change made by a user.
+func (a *App) runAutomationRules(evt notify.BlockChangeEvent) {
+	if a.config == nil || !a.config.EnableAutomationRules || evt.Board == nil || evt.Card == nil {
+		return
+	}
+	a.evaluateAutomationRules(evt, newAutomationChain())
+}
+
+func (a *App) evaluateAutomationRules(evt notify.BlockChangeEvent, chain *automationChain) {
+	if isTemplate, _ := evt.Card.Fields["isTemplate"].(bool); isTemplate {
+		return
+	}
+
+	rules, err := a.store.GetAutomationRulesForBoard(evt.Board.ID)
+	if err != nil {
+		a.logger.Error("Cannot get the automation rules of the board", mlog.String("board_id", evt.Board.ID), mlog.Err(err))
+		return
+	}
+
+	for _, rule := range rules {
+		if !rule.Enabled || !automationTriggerMatches(rule.Trigger, evt) {
+			continue
+		}
+		a.runAutomationRule(rule, evt.Board, evt.Card, "", chain)
+	}
+}
+
+// automationTriggerMatches returns true if a block change fires a trigger.
+func automationTriggerMatches(trigger model.AutomationTrigger, evt notify.BlockChangeEvent) bool {
+	block := evt.BlockChanged
+	if block == nil {
+		return false
+	}
+
+	switch trigger.Type {
+	case model.AutomationTriggerCardCreated:
+		return evt.Action == notify.Add && block.Type == model.TypeCard
+	case model.AutomationTriggerCommentAdded:
+		return evt.Action == notify.Add && block.Type == model.TypeComment
+	case model.AutomationTriggerPropertyChanged:
+		if evt.Action != notify.Update || block.Type != model.TypeCard || evt.BlockOld == nil {
+			return false
+		}
+		newValue := getCardProperties(block)[trigger.PropertyID]
+		oldValue := getCardProperties(evt.BlockOld)[trigger.PropertyID]
+		if reflect.DeepEqual(newValue, oldValue) {
+			return false
+		}
+		if trigger.Value == "" {
+			return true
+		}
+		return propertyValueContains(newValue, trigger.Value) && !propertyValueContains(oldValue, trigger.Value)
+	}
+	return false
+}
+
+// propertyValueContains returns true if a single or multiple value
+// property value is or contains the given value.
+func propertyValueContains(value interface{}, want string) bool {
+	switch value := value.(type) {
+	case string:
+		return value == want
+	case []interface{}:
+		for _, v := range value {
+			if s, ok := v.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runAutomationRule runs the actions of a rule on a card and records the
+// execution. The triggerKey identifies the occurrence of triggers that
+// must run the rule once per card, and is empty otherwise.
+func (a *App) runAutomationRule(rule *model.AutomationRule, board *model.Board, card *model.Block, triggerKey string, chain *automationChain) {
+	logFields := []mlog.Field{
+		mlog.String("rule_id", rule.ID),
+		mlog.String("card_id", card.ID),
+	}
+
+	ranKey := rule.ID + "-" + card.ID
+	if chain.ran[ranKey] {
+		a.logger.Debug("Automation rule already ran on the card for this change, skipping", logFields...)
+		return
+	}
+	if chain.depth >= maxAutomationDepth {
+		a.logger.Warn("Automation rules triggered each other too many times, stopping", logFields...)
+		return
+	}
+	chain.ran[ranKey] = true
+
+	execution := &model.AutomationExecution{
+		ID:         utils.NewID(utils.IDTypeNone),
+		RuleID:     rule.ID,
+		BoardID:    board.ID,
+		CardID:     card.ID,
+		TriggerKey: triggerKey,
+		Status:     model.AutomationExecutionRunning,
+		Results:    []model.AutomationActionResult{},
+	}
+	added, err := a.store.AddAutomationExecution(execution)
+	if err != nil {
+		a.logger.Error("Cannot record the automation execution", append(logFields, mlog.Err(err))...)
+		return
+	}
+	if !added {
+		// the rule already ran for this trigger, e.g. on another server
+		return
+	}
+
+	var events []notify.BlockChangeEvent
+	if a.permissions.HasPermissionToBoard(rule.CreatedBy, board.ID, model.PermissionManageBoardCards) {
+		execution.Results, events = a.applyAutomationActions(rule, board, card, false)
+	} else {
+		execution.Results = []model.AutomationActionResult{{Error: errAutomationPermission.Error()}}
+	}
+	execution.Status = automationExecutionStatus(execution.Results)
+
+	if err := a.store.UpdateAutomationExecution(execution); err != nil {
+		a.logger.Error("Cannot record the automation execution result", append(logFields, mlog.Err(err))...)
+	}
+	a.logger.Debug("Automation rule ran", append(logFields, mlog.String("status", string(execution.Status)))...)
+
+	// the changes made by the rule are notified and can trigger other rules
+	next := chain.next()
+	for _, evt := range events {
+		if a.notifications != nil {
+			a.notifications.BlockChanged(evt)
+		}
+		a.evaluateAutomationRules(evt, next)
+	}
+}
+
+func automationExecutionStatus(results []model.AutomationActionResult) model.AutomationExecutionStatus {
+	for _, result := range results {
+		if result.Error != "" {
+			return model.AutomationExecutionError
+		}
+	}
+	return model.AutomationExecutionSuccess
+}
+
+// applyAutomationActions runs the actions of a rule on a card, stopping
+// at the first error. It returns the result of each action and the block
+// changes they made. For a dry run the actions are only described.
+func (a *App) applyAutomationActions(rule *model.AutomationRule, board *model.Board, card *model.Block, dryRun bool) ([]model.AutomationActionResult, []notify.BlockChangeEvent) {
+	results := []model.AutomationActionResult{}
+	events := []notify.BlockChangeEvent{}
+
+	for _, action := range rule.Actions {
+		result := model.AutomationActionResult{Type: action.Type}
+
+		schema, err := model.ParsePropertySchema(board)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			break
+		}
+
+		var actionEvents []notify.BlockChangeEvent
+		switch action.Type {
+		case model.AutomationActionSetProperty, model.AutomationActionAssignPerson:
+			var newCard *model.Block
+			result.Detail, newCard, actionEvents, err = a.applyAutomationPropertyAction(rule, action, schema, board, card, dryRun)
+			if newCard != nil {
+				card = newCard
+			}
+		case model.AutomationActionAddChecklist:
+			result.Detail = fmt.Sprintf("add %d checklist items", len(action.Items))
+			if !dryRun {
+				var newCard *model.Block
+				newCard, actionEvents, err = a.addAutomationChecklist(rule, action, board, card)
+				if newCard != nil {
+					card = newCard
+				}
+			}
+		case model.AutomationActionPostComment:
+			result.Detail = fmt.Sprintf("post comment %q", action.Text)
+			if !dryRun {
+				actionEvents, err = a.postAutomationComment(rule, action, board, card)
+			}
+		case model.AutomationActionMoveToBoard:
+			result.Detail = fmt.Sprintf("move card to board %s", action.BoardID)
+			if !dryRun {
+				err = a.moveAutomationCard(rule, action, card)
+			}
+		case model.AutomationActionCallWebhook:
+			result.Detail = fmt.Sprintf("call webhook %s", action.URL)
+			if !dryRun {
+				err = a.callAutomationWebhook(rule, card, action.URL)
+			}
+		default:
+			err = fmt.Errorf("unknown action %q", action.Type)
+		}
+
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			break
+		}
+		results = append(results, result)
+		events = append(events, actionEvents...)
+	}
+	return results, events
+}
+
+// applyAutomationPropertyAction sets a property of a card or assigns a
+// user to it.
+func (a *App) applyAutomationPropertyAction(rule *model.AutomationRule, action model.AutomationAction, schema model.PropSchema, board *model.Board, card *model.Block, dryRun bool) (string, *model.Block, []notify.BlockChangeEvent, error) {
+	propDef, ok := schema[action.PropertyID]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("property %s not found", action.PropertyID)
+	}
+
+	props := getCardProperties(card)
+	var detail string
+	if action.Type == model.AutomationActionAssignPerson {
+		detail = fmt.Sprintf("assign user %s to %s", action.Value, propDef.Name)
+		if propDef.Type == "multiPerson" {
+			userIDs, _ := props[propDef.ID].([]interface{})
+			if !propertyValueContains(userIDs, action.Value) {
+				props[propDef.ID] = append(userIDs, action.Value)
+			}
+		} else {
+			props[propDef.ID] = action.Value
+		}
+	} else {
+		value := action.Value
+		if opt, ok := propDef.Options[action.Value]; ok {
+			value = opt.Value
+		}
+		detail = fmt.Sprintf("set %s to %q", propDef.Name, value)
+		if action.Value == "" {
+			delete(props, propDef.ID)
+		} else {
+			props[propDef.ID] = action.Value
+		}
+	}
+
+	if dryRun || reflect.DeepEqual(props, getCardProperties(card)) {
+		return detail, nil, nil, nil
+	}
+
+	patch := &model.BlockPatch{
+		UpdatedFields: map[string]interface{}{"properties": props},
+	}
+	newCard, err := a.PatchBlockAndNotify(card.ID, patch, rule.CreatedBy, true)
+	if err != nil {
+		return detail, nil, nil, err
+	}
+	evt := a.automationBlockChangeEvent(notify.Update, board, newCard, newCard, card, rule.CreatedBy)
+	return detail, newCard, []notify.BlockChangeEvent{evt}, nil
+}
+
+// addAutomationChecklist adds checkbox blocks to the end of a card.
+func (a *App) addAutomationChecklist(rule *model.AutomationRule, action model.AutomationAction, board *model.Board, card *model.Block) (*model.Block, []notify.BlockChangeEvent, error) {
+	now := utils.GetMillis()
+	blocks := make([]*model.Block, 0, len(action.Items))
+	for _, item := range action.Items {
+		blocks = append(blocks, &model.Block{
+			ID:         utils.NewID(utils.IDTypeBlock),
+			ParentID:   card.ID,
+			BoardID:    card.BoardID,
+			CreatedBy:  rule.CreatedBy,
+			ModifiedBy: rule.CreatedBy,
+			Schema:     1,
+			Type:       model.TypeCheckbox,
+			Title:      item,
+			Fields:     map[string]interface{}{"value": false},
+			CreateAt:   now,
+			UpdateAt:   now,
+		})
+	}
+
+	blocks, err := a.InsertBlocksAndNotify(blocks, rule.CreatedBy, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contentOrder, _ := card.Fields["contentOrder"].([]interface{})
+	newContentOrder := append([]interface{}{}, contentOrder...)
+	events := make([]notify.BlockChangeEvent, 0, len(blocks))
+	for _, block := range blocks {
+		newContentOrder = append(newContentOrder, block.ID)
+		events = append(events, a.automationBlockChangeEvent(notify.Add, board, card, block, nil, rule.CreatedBy))
+	}
+
+	patch := &model.BlockPatch{
+		UpdatedFields: map[string]interface{}{"contentOrder": newContentOrder},
+	}
+	newCard, err := a.PatchBlockAndNotify(card.ID, patch, rule.CreatedBy, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newCard, events, nil
+}
+
+// postAutomationComment adds a comment to a card.
+func (a *App) postAutomationComment(rule *model.AutomationRule, action model.AutomationAction, board *model.Board, card *model.Block) ([]notify.BlockChangeEvent, error) {
+	now := utils.GetMillis()
+	comment := &model.Block{
+		ID:         utils.NewID(utils.IDTypeBlock),
+		ParentID:   card.ID,
+		BoardID:    card.BoardID,
+		CreatedBy:  rule.CreatedBy,
+		ModifiedBy: rule.CreatedBy,
+		Schema:     1,
+		Type:       model.TypeComment,
+		Title:      action.Text,
+		Fields:     map[string]interface{}{},
+		CreateAt:   now,
+		UpdateAt:   now,
+	}
+	if err := a.InsertBlockAndNotify(comment, rule.CreatedBy, true); err != nil {
+		return nil, err
+	}
+	return []notify.BlockChangeEvent{a.automationBlockChangeEvent(notify.Add, board, card, comment, nil, rule.CreatedBy)}, nil
+}
+
+// moveAutomationCard moves a card to another board, mapping its
+// properties, if the creator of the rule can add cards to it.
+func (a *App) moveAutomationCard(rule *model.AutomationRule, action model.AutomationAction, card *model.Block) error {
+	if !a.permissions.HasPermissionToBoard(rule.CreatedBy, action.BoardID, model.PermissionManageBoardCards) {
+		return fmt.Errorf("the creator of the rule cannot modify the cards of board %s", action.BoardID)
+	}
+	_, err := a.MoveCardWithMapping(card.ID, rule.CreatedBy, &model.CardMoveRequest{TargetBoardID: action.BoardID}, true)
+	return err
+}
+
+// callAutomationWebhook queues the call of a webhook, so that slow
+// webhooks don't delay the notifications of the changes. The failed calls
+// are logged.
+func (a *App) callAutomationWebhook(rule *model.AutomationRule, card *model.Block, webhookURL string) error {
+	if a.webhook == nil {
+		return errors.New("webhooks are not available")
+	}
+	// the allowed hosts may have changed since the rule was saved
+	if !a.automationWebhookAllowed(webhookURL) {
+		return fmt.Errorf("the host of webhook %s is not allowed", webhookURL)
+	}
+
+	payload := automationWebhookPayload{
+		RuleID:  rule.ID,
+		BoardID: rule.BoardID,
+		Trigger: rule.Trigger,
+		Card:    card,
+	}
+	queued := a.webhookQueue.TryEnqueue(func() error {
+		if err := a.webhook.Post(webhookURL, payload); err != nil {
+			a.logger.Warn("Automation webhook call failed",
+				mlog.String("rule_id", rule.ID),
+				mlog.String("card_id", card.ID),
+				mlog.Err(err),
+			)
+		}
+		return nil
+	})
+	if !queued {
+		return errors.New("too many webhook calls are pending")
+	}
+	return nil
+}
+
+// automationWebhookAllowed returns true if the host of a webhook url is
+// one of the hosts allowed by the configuration.
+func (a *App) automationWebhookAllowed(webhookURL string) bool {
+	u, err := url.Parse(webhookURL)
+	if err != nil || a.config == nil {
+		return false
+	}
+	for _, host := range a.config.AutomationWebhookAllowedHosts {
+		if strings.EqualFold(strings.TrimSpace(host), u.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) automationBlockChangeEvent(action notify.Action, board *model.Board, card, block, oldBlock *model.Block, userID string) notify.BlockChangeEvent {
+	member, _ := a.GetMemberForBoard(board.ID, userID)
+	if member == nil {
+		member = &model.BoardMember{
+			BoardID: board.ID,
+			UserID:  userID,
+		}
+	}
+	return notify.BlockChangeEvent{
+		Action:       action,
+		TeamID:       board.TeamID,
+		Board:        board,
+		Card:         card,
+		BlockChanged: block,
+		BlockOld:     oldBlock,
+		ModifiedBy:   member,
+	}
+}
+
+// RunDateReachedAutomations runs the dateReached rules of the cards whose
+// date was reached. Each rule runs once per card and date.
+func (a *App) RunDateReachedAutomations(now time.Time) {
+	nowMillis := utils.GetMillisForTime(now)
+	if _, err := a.store.DeleteAutomationExecutionsBefore(nowMillis - automationExecutionRetention.Milliseconds()); err != nil {
+		a.logger.Error("RunDateReachedAutomations cannot delete the old executions", mlog.Err(err))
+	}
+
+	rules, err := a.store.GetEnabledAutomationRulesWithTrigger(model.AutomationTriggerDateReached)
+	if err != nil {
+		a.logger.Error("RunDateReachedAutomations cannot get the rules", mlog.Err(err))
+		return
+	}
+
+	boards := map[string]*model.Board{}
+	cardsByBoard := map[string][]*model.Block{}
+	for _, rule := range rules {
+		board, ok := boards[rule.BoardID]
+		if !ok {
+			board, err = a.store.GetBoard(rule.BoardID)
+			if err != nil {
+				a.logger.Error("RunDateReachedAutomations cannot get the board", mlog.String("board_id", rule.BoardID), mlog.Err(err))
+				continue
+			}
+			boards[rule.BoardID] = board
+
+			cards, err := a.store.GetBlocksWithType(board.ID, model.TypeCard)
+			if err != nil {
+				a.logger.Error("RunDateReachedAutomations cannot get the cards", mlog.String("board_id", rule.BoardID), mlog.Err(err))
+				continue
+			}
+			cardsByBoard[board.ID] = cards
+		}
+
+		for _, card := range cardsByBoard[board.ID] {
+			if isTemplate, _ := card.Fields["isTemplate"].(bool); isTemplate {
+				continue
+			}
+			dueAt, ok := model.GetDueDate(getCardProperties(card)[rule.Trigger.PropertyID])
+			if !ok || nowMillis < dueAt || nowMillis >= dueAt+automationDateWindow.Milliseconds() {
+				continue
+			}
+			triggerKey := fmt.Sprintf("%s:%d", rule.Trigger.PropertyID, dueAt)
+			a.runAutomationRule(rule, board, card, triggerKey, newAutomationChain())
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+)
+
+func TestAutomationTriggerMatches(t *testing.T) {
+	card := func(props map[string]interface{}) *model.Block {
+		return &model.Block{ID: "card", Type: model.TypeCard, Fields: map[string]interface{}{"properties": props}}
+	}
+	comment := &model.Block{ID: "comment", Type: model.TypeComment}
+
+	statusChanged := model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "status"}
+	statusDone := model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "status", Value: "done"}
+	tagAdded := model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "tags", Value: "urgent"}
+
+	testCases := []struct {
+		name     string
+		trigger  model.AutomationTrigger
+		evt      notify.BlockChangeEvent
+		expected bool
+	}{
+		{
+			name:     "card created",
+			trigger:  model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			evt:      notify.BlockChangeEvent{Action: notify.Add, BlockChanged: card(nil)},
+			expected: true,
+		},
+		{
+			name:     "card updated is not created",
+			trigger:  model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			evt:      notify.BlockChangeEvent{Action: notify.Update, BlockChanged: card(nil), BlockOld: card(nil)},
+			expected: false,
+		},
+		{
+			name:     "comment added",
+			trigger:  model.AutomationTrigger{Type: model.AutomationTriggerCommentAdded},
+			evt:      notify.BlockChangeEvent{Action: notify.Add, BlockChanged: comment},
+			expected: true,
+		},
+		{
+			name:    "property changed to any value",
+			trigger: statusChanged,
+			evt: notify.BlockChangeEvent{Action: notify.Update,
+				BlockChanged: card(map[string]interface{}{"status": "todo"}), BlockOld: card(nil)},
+			expected: true,
+		},
+		{
+			name:    "other property changed",
+			trigger: statusChanged,
+			evt: notify.BlockChangeEvent{Action: notify.Update,
+				BlockChanged: card(map[string]interface{}{"owner": "user"}), BlockOld: card(nil)},
+			expected: false,
+		},
+		{
+			name:    "property changed to the value",
+			trigger: statusDone,
+			evt: notify.BlockChangeEvent{Action: notify.Update,
+				BlockChanged: card(map[string]interface{}{"status": "done"}), BlockOld: card(map[string]interface{}{"status": "todo"})},
+			expected: true,
+		},
+		{
+			name:    "property changed to another value",
+			trigger: statusDone,
+			evt: notify.BlockChangeEvent{Action: notify.Update,
+				BlockChanged: card(map[string]interface{}{"status": "todo"}), BlockOld: card(nil)},
+			expected: false,
+		},
+		{
+			name:    "value added to a multiple value property",
+			trigger: tagAdded,
+			evt: notify.BlockChangeEvent{Action: notify.Update,
+				BlockChanged: card(map[string]interface{}{"tags": []interface{}{"a", "urgent"}}),
+				BlockOld:     card(map[string]interface{}{"tags": []interface{}{"a"}})},
+			expected: true,
+		},
+		{
+			name:    "value already in a multiple value property",
+			trigger: tagAdded,
+			evt: notify.BlockChangeEvent{Action: notify.Update,
+				BlockChanged: card(map[string]interface{}{"tags": []interface{}{"urgent", "b"}}),
+				BlockOld:     card(map[string]interface{}{"tags": []interface{}{"urgent"}})},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, automationTriggerMatches(tc.trigger, tc.evt))
+		})
+	}
+}
+
+func TestUpdateAutomationRule(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{ID: testBoardID, TeamID: "team-id"}
+	existing := &model.AutomationRule{
+		ID:        "rule-id",
+		BoardID:   testBoardID,
+		CreatedBy: "admin-id",
+		CreateAt:  1000,
+		Trigger:   model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+		Actions:   []model.AutomationAction{{Type: model.AutomationActionPostComment, Text: "created"}},
+	}
+
+	t.Run("the rule runs as the user updating it", func(t *testing.T) {
+		th.Store.EXPECT().GetAutomationRule("rule-id").Return(existing, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().UpdateAutomationRule(gomock.Any()).Return(nil)
+
+		rule := &model.AutomationRule{
+			ID:        "rule-id",
+			CreatedBy: "admin-id",
+			Trigger:   model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			Actions:   []model.AutomationAction{{Type: model.AutomationActionPostComment, Text: "updated"}},
+		}
+		updated, err := th.App.UpdateAutomationRule(rule, "editor-id")
+		require.NoError(t, err)
+		require.Equal(t, "editor-id", updated.CreatedBy)
+		require.Equal(t, "editor-id", updated.ModifiedBy)
+		require.Equal(t, int64(1000), updated.CreateAt)
+	})
+}
@@ -0,0 +1,457 @@
+package app
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	backupFilePrefix    = "focalboard-backup-"
+	backupFileExtension = ".zip"
+	backupTimeFormat    = "20060102-150405.000"
+
+	backupManifestName = "manifest.json"
+	backupDatabaseName = "database.sqlite"
+	backupTablesDir    = "tables/"
+	backupFilesDir     = "files/"
+)
+
+var ErrBackupPathNotSet = errors.New("the backup path is not configured")
+
+// CreateBackup writes an archive with the content of the database and of
+// the file store to the backup directory, then removes the oldest
+// archives beyond the retention count.
+func (a *App) CreateBackup(now time.Time) (*model.BackupInfo, error) {
+	dir := a.config.BackupPath
+	if dir == "" {
+		return nil, ErrBackupPathNotSet
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create the backup directory: %w", err)
+	}
+
+	name := backupFilePrefix + now.UTC().Format(backupTimeFormat) + backupFileExtension
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the backup file: %w", err)
+	}
+
+	manifest, err := a.writeBackup(tmp, dir, now)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return nil, err
+	}
+
+	// the archive only gets its final name once complete, so an
+	// interrupted backup is never listed
+	backupPath := filepath.Join(dir, name)
+	if err := os.Rename(tmp.Name(), backupPath); err != nil {
+		_ = os.Remove(tmp.Name())
+		return nil, fmt.Errorf("cannot rename the backup file: %w", err)
+	}
+
+	a.logger.Info("Backup created",
+		mlog.String("path", backupPath),
+		mlog.Int("schema_version", manifest.SchemaVersion),
+		mlog.Int("files", manifest.Files),
+	)
+
+	if err := a.pruneBackups(); err != nil {
+		a.logger.Error("Cannot remove the old backups", mlog.Err(err))
+	}
+
+	info, err := os.Stat(backupPath)
+	if err != nil {
+		return nil, err
+	}
+	return backupInfoFromFileInfo(info), nil
+}
+
+// writeBackup writes the backup archive to w, using dir for the
+// temporary database file of SQLite.
+func (a *App) writeBackup(w io.Writer, dir string, now time.Time) (*model.BackupManifest, error) {
+	schemaVersion, err := a.store.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &model.BackupManifest{
+		FormatVersion: model.BackupFormatVersion,
+		CreateAt:      utils.GetMillisForTime(now),
+		DBType:        a.store.DBType(),
+		SchemaVersion: schemaVersion,
+	}
+
+	zw := zip.NewWriter(w)
+
+	if manifest.DBType == model.SqliteDBType {
+		manifest.Tables, err = a.writeBackupDatabaseFile(zw, dir)
+	} else {
+		manifest.Tables, err = a.store.DumpDatabase(&zipDumpWriter{zw: zw})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot backup the database: %w", err)
+	}
+
+	if manifest.Files, err = a.writeBackupFiles(zw); err != nil {
+		return nil, fmt.Errorf("cannot backup the files: %w", err)
+	}
+
+	mw, err := zw.Create(backupManifestName)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(mw).Encode(manifest); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// writeBackupDatabaseFile copies the SQLite database to the archive.
+func (a *App) writeBackupDatabaseFile(zw *zip.Writer, dir string) (map[string]int64, error) {
+	dbPath := filepath.Join(dir, "."+utils.NewID(utils.IDTypeNone)+".sqlite")
+	defer os.Remove(dbPath)
+
+	counts, err := a.store.BackupDatabaseFile(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	w, err := zw.Create(backupDatabaseName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// writeBackupFiles copies the content of the file store to the archive
+// and returns the number of files copied.
+func (a *App) writeBackupFiles(zw *zip.Writer) (int, error) {
+	paths, err := a.filesBackend.ListDirectoryRecursively("")
+	if err != nil {
+		return 0, err
+	}
+
+	for _, p := range paths {
+		if err := a.writeBackupFile(zw, p); err != nil {
+			return 0, fmt.Errorf("cannot backup file %s: %w", p, err)
+		}
+	}
+	return len(paths), nil
+}
+
+func (a *App) writeBackupFile(zw *zip.Writer, p string) error {
+	r, err := a.filesBackend.Reader(p)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := zw.Create(backupFilesDir + filepath.ToSlash(p))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// GetBackups returns the archives of the backup directory, newest first.
+func (a *App) GetBackups() ([]*model.BackupInfo, error) {
+	if a.config.BackupPath == "" {
+		return nil, ErrBackupPathNotSet
+	}
+
+	entries, err := os.ReadDir(a.config.BackupPath)
+	if os.IsNotExist(err) {
+		return []*model.BackupInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []*model.BackupInfo{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupFilePrefix) || !strings.HasSuffix(name, backupFileExtension) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backupInfoFromFileInfo(info))
+	}
+
+	// the names hold the creation time in a sortable format
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name > backups[j].Name
+	})
+	return backups, nil
+}
+
+// pruneBackups removes the oldest archives beyond the retention count.
+func (a *App) pruneBackups() error {
+	if a.config.BackupRetentionCount <= 0 {
+		return nil
+	}
+
+	backups, err := a.GetBackups()
+	if err != nil {
+		return err
+	}
+	if len(backups) <= a.config.BackupRetentionCount {
+		return nil
+	}
+
+	for _, backup := range backups[a.config.BackupRetentionCount:] {
+		if err := os.Remove(filepath.Join(a.config.BackupPath, backup.Name)); err != nil {
+			return err
+		}
+		a.logger.Info("Old backup removed", mlog.String("name", backup.Name))
+	}
+	return nil
+}
+
+func backupInfoFromFileInfo(info os.FileInfo) *model.BackupInfo {
+	return &model.BackupInfo{
+		Name:     info.Name(),
+		Size:     info.Size(),
+		CreateAt: utils.GetMillisForTime(info.ModTime()),
+	}
+}
+
+// RestoreBackup replaces the content of the database and restores the
+// files of a backup archive. The archive must have been written with the
+// same schema version as the one of the database, which is the latest
+// known by the server once its migrations have run.
+func (a *App) RestoreBackup(archivePath string) (*model.BackupManifest, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open the backup archive: %w", err)
+	}
+	defer zr.Close()
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+
+	manifest, err := readBackupManifest(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.FormatVersion > model.BackupFormatVersion {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("unsupported backup format version %d", manifest.FormatVersion))
+	}
+
+	schemaVersion, err := a.store.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if manifest.SchemaVersion > schemaVersion {
+		return nil, model.NewErrBadRequest(fmt.Sprintf(
+			"the backup schema version %d is newer than the database schema version %d, upgrade the server before restoring it",
+			manifest.SchemaVersion, schemaVersion,
+		))
+	}
+	if manifest.SchemaVersion < schemaVersion {
+		return nil, model.NewErrBadRequest(fmt.Sprintf(
+			"the backup schema version %d is older than the database schema version %d, restore it with the server version that created it",
+			manifest.SchemaVersion, schemaVersion,
+		))
+	}
+
+	if dbFile, ok := entries[backupDatabaseName]; ok {
+		err = a.restoreBackupDatabaseFile(dbFile)
+	} else {
+		_, err = a.store.RestoreDatabase(&zipDumpReader{entries: entries, manifest: manifest})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot restore the database: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if !strings.HasPrefix(f.Name, backupFilesDir) || strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		if err := a.restoreBackupFile(f); err != nil {
+			return nil, fmt.Errorf("cannot restore file %s: %w", f.Name, err)
+		}
+	}
+
+	a.logger.Info("Backup restored",
+		mlog.String("path", archivePath),
+		mlog.Int("schema_version", manifest.SchemaVersion),
+		mlog.Int("files", manifest.Files),
+	)
+	return manifest, nil
+}
+
+func readBackupManifest(entries map[string]*zip.File) (*model.BackupManifest, error) {
+	f, ok := entries[backupManifestName]
+	if !ok {
+		return nil, model.NewErrBadRequest("not a backup archive, the manifest is missing")
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	manifest, err := model.BackupManifestFromJSON(r)
+	if err != nil {
+		return nil, model.NewErrBadRequest("invalid backup manifest: " + err.Error())
+	}
+	return manifest, nil
+}
+
+// restoreBackupDatabaseFile extracts the SQLite database of the archive
+// to a temporary file and restores it.
+func (a *App) restoreBackupDatabaseFile(f *zip.File) error {
+	tmp, err := os.CreateTemp("", "focalboard-restore-*.sqlite")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	r, err := f.Open()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	_, err = io.Copy(tmp, r)
+	r.Close()
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = a.store.RestoreDatabaseFile(tmp.Name())
+	return err
+}
+
+func (a *App) restoreBackupFile(f *zip.File) error {
+	filePath := strings.TrimPrefix(f.Name, backupFilesDir)
+	if cleaned := path.Clean(filePath); cleaned != filePath || strings.HasPrefix(cleaned, "../") || path.IsAbs(cleaned) {
+		return model.NewErrBadRequest("invalid file path in the backup archive")
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	_, err = a.filesBackend.WriteFile(r, filepath.FromSlash(filePath))
+	return err
+}
+
+// zipDumpWriter writes the rows of each table as a JSON lines file of a
+// backup archive. The rows of a table must be written consecutively.
+type zipDumpWriter struct {
+	zw      *zip.Writer
+	table   string
+	encoder *json.Encoder
+}
+
+func (w *zipDumpWriter) WriteRow(table string, row map[string]interface{}) error {
+	if w.encoder == nil || table != w.table {
+		tw, err := w.zw.Create(backupTablesDir + table + ".jsonl")
+		if err != nil {
+			return err
+		}
+		w.table = table
+		w.encoder = json.NewEncoder(tw)
+	}
+	return w.encoder.Encode(row)
+}
+
+// zipDumpReader reads the tables of a backup archive written by
+// zipDumpWriter. The tables without rows have no file in the archive.
+type zipDumpReader struct {
+	entries  map[string]*zip.File
+	manifest *model.BackupManifest
+}
+
+func (r *zipDumpReader) Tables() []string {
+	tables := make([]string, 0, len(r.manifest.Tables))
+	for table := range r.manifest.Tables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+func (r *zipDumpReader) ReadRows(table string, fn func(row map[string]interface{}) error) error {
+	f, ok := r.entries[backupTablesDir+table+".jsonl"]
+	if !ok {
+		return nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	decoder := json.NewDecoder(rc)
+	decoder.UseNumber()
+	for {
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// numbers are decoded as json.Number to keep the precision of the
+		// large integers, such as the timestamps
+		for column, value := range row {
+			if n, ok := value.(json.Number); ok {
+				if i, err := n.Int64(); err == nil {
+					row[column] = i
+				} else if f, err := n.Float64(); err == nil {
+					row[column] = f
+				}
+			}
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+func setupBackupTest(t *testing.T, th *TestHelper) filestore.FileBackend {
+	th.App.config.BackupPath = t.TempDir()
+	th.App.config.BackupRetentionCount = 0
+
+	filesBackend, err := filestore.NewFileBackend(filestore.FileBackendSettings{
+		DriverName: "local",
+		Directory:  t.TempDir(),
+	})
+	require.NoError(t, err)
+	th.App.filesBackend = filesBackend
+	return filesBackend
+}
+
+func TestCreateAndRestoreBackup(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	filesBackend := setupBackupTest(t, th)
+
+	filePath := filepath.Join("team-id", testBoardID, "attachment.txt")
+	_, err := filesBackend.WriteFile(bytes.NewReader([]byte("attachment")), filePath)
+	require.NoError(t, err)
+
+	rows := []map[string]interface{}{
+		{"id": "board-1", "title": "Board", "create_at": int64(1700000000123), "is_template": false},
+		{"id": "board-2", "title": "Other board", "create_at": int64(1700000000456), "is_template": true},
+	}
+
+	th.Store.EXPECT().GetSchemaVersion().Return(47, nil).Times(2)
+	th.Store.EXPECT().DBType().Return(model.PostgresDBType)
+	th.Store.EXPECT().DumpDatabase(gomock.Any()).DoAndReturn(func(w model.DatabaseDumpWriter) (map[string]int64, error) {
+		for _, row := range rows {
+			require.NoError(t, w.WriteRow("boards", row))
+		}
+		return map[string]int64{"boards": 2, "blocks": 0}, nil
+	})
+
+	info, err := th.App.CreateBackup(time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, "focalboard-backup-20240331-120000.000.zip", info.Name)
+
+	require.NoError(t, filesBackend.RemoveFile(filePath))
+
+	var restored map[string][]map[string]interface{}
+	th.Store.EXPECT().RestoreDatabase(gomock.Any()).DoAndReturn(func(dump model.DatabaseDumpReader) (map[string]int64, error) {
+		restored = map[string][]map[string]interface{}{}
+		for _, table := range dump.Tables() {
+			restored[table] = []map[string]interface{}{}
+			require.NoError(t, dump.ReadRows(table, func(row map[string]interface{}) error {
+				restored[table] = append(restored[table], row)
+				return nil
+			}))
+		}
+		return map[string]int64{"boards": 2, "blocks": 0}, nil
+	})
+
+	manifest, err := th.App.RestoreBackup(filepath.Join(th.App.config.BackupPath, info.Name))
+	require.NoError(t, err)
+	require.Equal(t, 47, manifest.SchemaVersion)
+	require.Equal(t, 1, manifest.Files)
+	require.Equal(t, rows, restored["boards"])
+	require.Empty(t, restored["blocks"])
+
+	data, err := filesBackend.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, "attachment", string(data))
+
+	t.Run("the schema version must match", func(t *testing.T) {
+		th.Store.EXPECT().GetSchemaVersion().Return(48, nil)
+		th.Store.EXPECT().RestoreDatabase(gomock.Any()).Times(0)
+
+		_, err := th.App.RestoreBackup(filepath.Join(th.App.config.BackupPath, info.Name))
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestBackupRetentionCount(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	setupBackupTest(t, th)
+	th.App.config.BackupRetentionCount = 2
+
+	th.Store.EXPECT().GetSchemaVersion().Return(47, nil).AnyTimes()
+	th.Store.EXPECT().DBType().Return(model.PostgresDBType).AnyTimes()
+	th.Store.EXPECT().DumpDatabase(gomock.Any()).Return(map[string]int64{}, nil).AnyTimes()
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		_, err := th.App.CreateBackup(now.Add(time.Duration(i) * time.Hour))
+		require.NoError(t, err)
+	}
+
+	backups, err := th.App.GetBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	require.Equal(t, "focalboard-backup-20240331-140000.000.zip", backups[0].Name)
+	require.Equal(t, "focalboard-backup-20240331-130000.000.zip", backups[1].Name)
+}
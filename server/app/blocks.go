@@ -72,6 +72,10 @@ func (a *App) PatchBlockAndNotify(blockID string, blockPatch *model.BlockPatch,
 		return nil, err
 	}
 
+	if err = a.validateCardPatch(board, oldBlock, blockPatch); err != nil {
+		return nil, err
+	}
+
 	err = a.store.PatchBlock(blockID, blockPatch, modifiedByID)
 	if err != nil {
 		return nil, err
@@ -82,6 +86,14 @@ func (a *App) PatchBlockAndNotify(blockID string, blockPatch *model.BlockPatch,
 	if err != nil {
 		return nil, err
 	}
+	if block.Type == model.TypeCard {
+		a.updateComputedProperties(board, block, modifiedByID)
+	} else {
+		a.updateParentRollups(board, block, modifiedByID)
+	}
+	a.syncRelationBackReferences(board, oldBlock, block, modifiedByID)
+	a.notifyBlockerCompletionChanged(board, oldBlock, block)
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		// broadcast on websocket
 		a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
@@ -108,17 +120,44 @@ func (a *App) PatchBlocksAndNotify(teamID string, blockPatches *model.BlockPatch
 		return err
 	}
 
+	if err := a.validateCardPatches(oldBlocks, blockPatches); err != nil {
+		return err
+	}
+
 	if err := a.store.PatchBlocks(blockPatches, modifiedByID); err != nil {
 		return err
 	}
 
+	a.notifyBlocksPatched(teamID, oldBlocks, blockPatches.BlockIDs, modifiedByID, disableNotify)
+	return nil
+}
+
+// notifyBlocksPatched updates the computed properties of the patched
+// blocks and broadcasts their changes.
+func (a *App) notifyBlocksPatched(teamID string, oldBlocks []*model.Block, blockIDs []string, modifiedByID string, disableNotify bool) {
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.metrics.IncrementBlocksPatched(len(oldBlocks))
-		for i, blockID := range blockPatches.BlockIDs {
+		boards := map[string]*model.Board{}
+		for i, blockID := range blockIDs {
 			newBlock, err := a.store.GetBlock(blockID)
 			if err != nil {
 				return err
 			}
+			if newBlock.Type == model.TypeCard || newBlock.ParentID != "" {
+				board, ok := boards[newBlock.BoardID]
+				if !ok {
+					if board, err = a.store.GetBoard(newBlock.BoardID); err != nil {
+						return err
+					}
+					boards[board.ID] = board
+				}
+				if newBlock.Type == model.TypeCard {
+					a.updateComputedProperties(board, newBlock, modifiedByID)
+					a.notifyBlockerCompletionChanged(board, oldBlocks[i], newBlock)
+				} else {
+					a.updateParentRollups(board, newBlock, modifiedByID)
+				}
+			}
 			a.wsAdapter.BroadcastBlockChange(teamID, newBlock)
 			a.webhook.NotifyUpdate(newBlock)
 			if !disableNotify {
@@ -127,7 +166,6 @@ func (a *App) PatchBlocksAndNotify(teamID string, blockPatches *model.BlockPatch
 		}
 		return nil
 	})
-	return nil
 }
 
 func (a *App) InsertBlock(block *model.Block, modifiedByID string) error {
@@ -140,8 +178,18 @@ func (a *App) InsertBlockAndNotify(block *model.Block, modifiedByID string, disa
 		return bErr
 	}
 
+	if vErr := a.validateCardBlocks(map[string]*model.Board{board.ID: board}, []*model.Block{block}); vErr != nil {
+		return vErr
+	}
+
 	err := a.store.InsertBlock(block, modifiedByID)
 	if err == nil {
+		if block.Type == model.TypeCard {
+			a.updateComputedProperties(board, block, modifiedByID)
+		} else {
+			a.updateParentRollups(board, block, modifiedByID)
+		}
+		a.syncRelationBackReferences(board, nil, block, modifiedByID)
 		a.blockChangeNotifier.Enqueue(func() error {
 			a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
 			a.metrics.IncrementBlocksInserted(1)
@@ -178,6 +226,10 @@ func (a *App) InsertBlocksAndNotify(blocks []*model.Block, modifiedByID string,
 		return nil, err
 	}
 
+	if err = a.validateCardBlocks(map[string]*model.Board{board.ID: board}, blocks); err != nil {
+		return nil, err
+	}
+
 	needsNotify := make([]*model.Block, 0, len(blocks))
 	for i := range blocks {
 		err := a.store.InsertBlock(blocks[i], modifiedByID)
@@ -185,13 +237,39 @@ func (a *App) InsertBlocksAndNotify(blocks []*model.Block, modifiedByID string,
 			return nil, err
 		}
 		needsNotify = append(needsNotify, blocks[i])
+	}
+
+	a.notifyBlocksInserted(board, needsNotify, modifiedByID, disableNotify)
+	return blocks, nil
+}
 
+// notifyBlocksInserted updates the computed properties of the inserted
+// blocks and of their parents, and broadcasts the new blocks.
+func (a *App) notifyBlocksInserted(board *model.Board, blocks []*model.Block, modifiedByID string, disableNotify bool) {
+	// computed properties are updated once all the blocks are inserted,
+	// as the rollups of the cards depend on their content
+	insertedIDs := make(map[string]bool, len(blocks))
+	for _, block := range blocks {
+		insertedIDs[block.ID] = true
+	}
+	updatedParents := map[string]bool{}
+	for _, block := range blocks {
+		if block.Type == model.TypeCard {
+			a.updateComputedProperties(board, block, modifiedByID)
+			a.syncRelationBackReferences(board, nil, block, modifiedByID)
+		} else if !insertedIDs[block.ParentID] && !updatedParents[block.ParentID] {
+			updatedParents[block.ParentID] = true
+			a.updateParentRollups(board, block, modifiedByID)
+		}
+	}
+
+	for i := range blocks {
 		a.wsAdapter.BroadcastBlockChange(board.TeamID, blocks[i])
 		a.metrics.IncrementBlocksInserted(1)
 	}
 
 	a.blockChangeNotifier.Enqueue(func() error {
-		for _, b := range needsNotify {
+		for _, b := range blocks {
 			block := b
 			a.webhook.NotifyUpdate(block)
 			if !disableNotify {
@@ -200,8 +278,6 @@ func (a *App) InsertBlocksAndNotify(blocks []*model.Block, modifiedByID string,
 		}
 		return nil
 	})
-
-	return blocks, nil
 }
 
 func (a *App) GetBlockByID(blockID string) (*model.Block, error) {
@@ -228,10 +304,29 @@ func (a *App) DeleteBlockAndNotify(blockID string, modifiedBy string, disableNot
 		return nil
 	}
 
+	var refs []cardRelationReference
+	if block.Type == model.TypeCard {
+		if refs, err = a.removeCardRelations(block, modifiedBy); err != nil {
+			return err
+		}
+		if len(refs) > 0 {
+			patch := &model.BlockPatch{
+				UpdatedFields: map[string]interface{}{relationReferencesField: refs},
+			}
+			if err = a.store.PatchBlock(blockID, patch, modifiedBy); err != nil {
+				a.restoreRelationReferences(blockID, refs, modifiedBy)
+				return err
+			}
+		}
+	}
+
 	err = a.store.DeleteBlock(blockID, modifiedBy)
 	if err != nil {
+		// the references are put back, as the card still exists
+		a.restoreRelationReferences(blockID, refs, modifiedBy)
 		return err
 	}
+	a.updateParentRollups(board, block, modifiedBy)
 
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastBlockDelete(board.TeamID, blockID, block.BoardID)
@@ -281,11 +376,23 @@ func (a *App) UndeleteBlock(blockID string, modifiedBy string) (*model.Block, er
 		return nil, err
 	}
 
+	if block.Type == model.TypeCard {
+		if block, err = a.restoreCardRelations(block, modifiedBy); err != nil {
+			return nil, err
+		}
+	}
+
 	board, err := a.store.GetBoard(block.BoardID)
 	if err != nil {
 		return nil, err
 	}
 
+	if block.Type == model.TypeCard {
+		a.updateComputedProperties(board, block, modifiedBy)
+	} else {
+		a.updateParentRollups(board, block, modifiedBy)
+	}
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
 		a.metrics.IncrementBlocksInserted(1)
@@ -307,8 +414,9 @@ func (a *App) GetBlocksForBoard(boardID string) ([]*model.Block, error) {
 }
 
 func (a *App) notifyBlockChanged(action notify.Action, block *model.Block, oldBlock *model.Block, modifiedByID string) {
-	// don't notify if notifications service disabled, or block change is generated via system user.
-	if a.notifications == nil || modifiedByID == model.SystemUserID {
+	// don't notify if notifications service and automations are disabled, or block change is generated via system user.
+	automationsEnabled := a.config != nil && a.config.EnableAutomationRules
+	if (a.notifications == nil && !automationsEnabled) || modifiedByID == model.SystemUserID {
 		return
 	}
 
@@ -337,7 +445,10 @@ func (a *App) notifyBlockChanged(action notify.Action, block *model.Block, oldBl
 		BlockOld:     oldBlock,
 		ModifiedBy:   boardMember,
 	}
-	a.notifications.BlockChanged(evt)
+	if a.notifications != nil {
+		a.notifications.BlockChanged(evt)
+	}
+	a.runAutomationRules(evt)
 }
 
 const (
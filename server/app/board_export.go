@@ -0,0 +1,510 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// exportCardsPerPage is the number of cards read at once by the
+	// exports, so that large boards are not loaded in memory.
+	exportCardsPerPage = 500
+
+	// exportTitleColumnID is the id of the title in the view sort
+	// options.
+	exportTitleColumnID = "__title"
+
+	exportTimeLayout = "January 02, 2006 15:04"
+)
+
+// exportColumn is a column of an exported table, the card title if it
+// has no property.
+type exportColumn struct {
+	name    string
+	propDef *model.PropDef
+}
+
+// exportSortOption is a sort option of a view.
+type exportSortOption struct {
+	PropertyID string `json:"propertyId"`
+	Reversed   bool   `json:"reversed"`
+}
+
+// exportSortValue is the value of a card for a sort option.
+type exportSortValue struct {
+	text    string
+	number  float64
+	numeric bool
+	empty   bool
+}
+
+// exportSortKey holds what is needed to sort a card, so that the cards
+// can be sorted without keeping them in memory.
+type exportSortKey struct {
+	id          string
+	title       string
+	createAt    int64
+	manualIndex int
+	values      []exportSortValue
+}
+
+// boardTableExport exports the cards of a board, or of a view, as a
+// table.
+type boardTableExport struct {
+	app         *App
+	board       *model.Board
+	columns     []exportColumn
+	filter      *model.CardFilter
+	sortOptions []exportSortOption
+	sortDefs    []*model.PropDef
+	cardOrder   map[string]int
+	resolver    *exportValueResolver
+}
+
+// ExportBoardTable writes the cards of a board as a CSV or XLSX table.
+// Property values are resolved to their display value. If a view is
+// passed, only its visible properties and the cards matching its filter
+// are exported, in the view order. The cards are read by pages, and only
+// their sort keys are kept in memory when they need to be sorted.
+func (a *App) ExportBoardTable(w io.Writer, opts model.BoardExportOptions) error {
+	if !opts.Format.IsValid() {
+		return model.NewErrBadRequest(fmt.Sprintf("unsupported export format %s", opts.Format))
+	}
+
+	export, err := a.newBoardTableExport(opts.BoardID, opts.ViewID)
+	if err != nil {
+		return err
+	}
+
+	tw, err := newTableWriter(w, opts.Format, export.board.Title)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(export.columns))
+	for i, column := range export.columns {
+		names[i] = column.name
+	}
+	if err = tw.WriteHeader(names); err != nil {
+		return err
+	}
+
+	if err = export.writeRows(tw); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func (a *App) newBoardTableExport(boardID, viewID string) (*boardTableExport, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &boardTableExport{
+		app:      a,
+		board:    board,
+		columns:  []exportColumn{{name: "Title"}},
+		resolver: newExportValueResolver(a.store),
+	}
+
+	propDefs := make([]*model.PropDef, 0, len(schema))
+	for _, propDef := range schema {
+		propDef := propDef
+		propDefs = append(propDefs, &propDef)
+	}
+	sort.Slice(propDefs, func(i, j int) bool { return propDefs[i].Index < propDefs[j].Index })
+
+	if viewID == "" {
+		for _, propDef := range propDefs {
+			export.columns = append(export.columns, exportColumn{name: propDef.Name, propDef: propDef})
+		}
+		return export, nil
+	}
+
+	view, err := a.store.GetBlock(viewID)
+	if err != nil {
+		return nil, err
+	}
+	if view.Type != model.TypeView || view.BoardID != board.ID {
+		return nil, model.NewErrNotFound("view ID=" + viewID)
+	}
+
+	visibleIDs, _ := stringValues(view.Fields["visiblePropertyIds"])
+	for _, propID := range visibleIDs {
+		if propDef, ok := schema[propID]; ok {
+			propDef := propDef
+			export.columns = append(export.columns, exportColumn{name: propDef.Name, propDef: &propDef})
+		}
+	}
+
+	if err = decodeViewField(view, "filter", &export.filter); err != nil {
+		return nil, err
+	}
+	if export.filter != nil {
+		if err = export.filter.IsValid(); err != nil {
+			return nil, model.NewErrBadRequest(err.Error())
+		}
+	}
+
+	var sortOptions []exportSortOption
+	if err = decodeViewField(view, "sortOptions", &sortOptions); err != nil {
+		return nil, err
+	}
+	for _, sortOption := range sortOptions {
+		if sortOption.PropertyID == exportTitleColumnID {
+			export.sortOptions = append(export.sortOptions, sortOption)
+			export.sortDefs = append(export.sortDefs, nil)
+		} else if propDef, ok := schema[sortOption.PropertyID]; ok {
+			propDef := propDef
+			export.sortOptions = append(export.sortOptions, sortOption)
+			export.sortDefs = append(export.sortDefs, &propDef)
+		}
+	}
+
+	cardOrder, _ := stringValues(view.Fields["cardOrder"])
+	if len(export.sortOptions) == 0 && len(cardOrder) != 0 {
+		export.cardOrder = make(map[string]int, len(cardOrder))
+		for i, cardID := range cardOrder {
+			if _, ok := export.cardOrder[cardID]; !ok {
+				export.cardOrder[cardID] = i
+			}
+		}
+	}
+	return export, nil
+}
+
+// writeRows writes the rows of the cards in the view order.
+func (e *boardTableExport) writeRows(tw tableWriter) error {
+	return e.forEachOrderedCard(func(card *model.Block) error {
+		return tw.WriteRow(e.row(card))
+	})
+}
+
+// forEachOrderedCard calls f for each card in the view order. Without
+// sort the cards are passed as they are read, otherwise their sort keys
+// are collected and the cards are read again in order.
+func (e *boardTableExport) forEachOrderedCard(f func(card *model.Block) error) error {
+	sorted := len(e.sortOptions) != 0 || e.cardOrder != nil
+
+	keys := []exportSortKey{}
+	err := e.forEachCard(func(card *model.Block) error {
+		if !sorted {
+			return f(card)
+		}
+		keys = append(keys, e.sortKey(card))
+		return nil
+	})
+	if err != nil || !sorted {
+		return err
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool { return e.compare(&keys[i], &keys[j]) < 0 })
+
+	for start := 0; start < len(keys); start += exportCardsPerPage {
+		end := start + exportCardsPerPage
+		if end > len(keys) {
+			end = len(keys)
+		}
+		ids := make([]string, 0, end-start)
+		for _, key := range keys[start:end] {
+			ids = append(ids, key.id)
+		}
+
+		// the cards deleted since they were read are skipped
+		cards, err := e.app.store.GetBlocksByIDs(ids)
+		if err != nil && !model.IsErrNotFound(err) {
+			return err
+		}
+		cardsByID := make(map[string]*model.Block, len(cards))
+		for _, card := range cards {
+			cardsByID[card.ID] = card
+		}
+		for _, id := range ids {
+			if card, ok := cardsByID[id]; ok {
+				if err := f(card); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// forEachCard calls f for each card of the board matching the filter,
+// reading the cards by pages.
+func (e *boardTableExport) forEachCard(f func(card *model.Block) error) error {
+	for page := 0; ; page++ {
+		cards, err := e.app.store.GetBlocks(model.QueryBlocksOptions{
+			BoardID:         e.board.ID,
+			BlockType:       model.TypeCard,
+			Page:            page,
+			PerPage:         exportCardsPerPage,
+			ExcludeArchived: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, card := range cards {
+			if card.Fields["isTemplate"] == true || !e.filter.Matches(card) {
+				continue
+			}
+			if err := f(card); err != nil {
+				return err
+			}
+		}
+
+		if len(cards) < exportCardsPerPage {
+			return nil
+		}
+	}
+}
+
+func (e *boardTableExport) row(card *model.Block) []tableCell {
+	cells := make([]tableCell, len(e.columns))
+	for i, column := range e.columns {
+		if column.propDef == nil {
+			cells[i] = tableCell{value: card.Title}
+			continue
+		}
+		cells[i] = e.cell(card, column.propDef)
+	}
+	return cells
+}
+
+// cell returns the display value of a card property, resolving the
+// options, users and dates with the property definition.
+func (e *boardTableExport) cell(card *model.Block, propDef *model.PropDef) tableCell {
+	switch propDef.Type {
+	case "createdTime":
+		return tableCell{value: utils.GetTimeForMillis(card.CreateAt).UTC().Format(exportTimeLayout)}
+	case "updatedTime":
+		return tableCell{value: utils.GetTimeForMillis(card.UpdateAt).UTC().Format(exportTimeLayout)}
+	case "createdBy":
+		return tableCell{value: e.resolver.username(card.CreatedBy)}
+	case "updatedBy":
+		return tableCell{value: e.resolver.username(card.ModifiedBy)}
+	}
+
+	props, _ := card.Fields["properties"].(map[string]interface{})
+	value, ok := props[propDef.ID]
+	if !ok || value == nil || value == "" {
+		return tableCell{}
+	}
+
+	display, err := propDef.GetValue(value, e.resolver)
+	if err != nil {
+		e.app.logger.Debug("Cannot resolve a property value for an export",
+			mlog.String("card_id", card.ID),
+			mlog.String("property_id", propDef.ID),
+			mlog.Err(err),
+		)
+		return tableCell{}
+	}
+	return tableCell{value: display, number: propDef.Type == "number"}
+}
+
+func (e *boardTableExport) sortKey(card *model.Block) exportSortKey {
+	key := exportSortKey{
+		id:          card.ID,
+		title:       card.Title,
+		createAt:    card.CreateAt,
+		manualIndex: -1,
+		values:      make([]exportSortValue, len(e.sortOptions)),
+	}
+	if index, ok := e.cardOrder[card.ID]; ok {
+		key.manualIndex = index
+	}
+
+	props, _ := card.Fields["properties"].(map[string]interface{})
+	for i, propDef := range e.sortDefs {
+		if propDef == nil {
+			continue
+		}
+
+		var sortValue exportSortValue
+		switch propDef.Type {
+		case "createdTime":
+			sortValue = exportSortValue{number: float64(card.CreateAt), numeric: true}
+		case "updatedTime":
+			sortValue = exportSortValue{number: float64(card.UpdateAt), numeric: true}
+		case "number":
+			number, err := strconv.ParseFloat(fmt.Sprint(props[propDef.ID]), 64)
+			sortValue = exportSortValue{number: number, numeric: true, empty: err != nil}
+		case "date":
+			var date struct {
+				From *int64 `json:"from"`
+			}
+			s, _ := props[propDef.ID].(string)
+			if err := json.Unmarshal([]byte(s), &date); err == nil && date.From != nil {
+				sortValue = exportSortValue{number: float64(*date.From), numeric: true}
+			} else {
+				sortValue = exportSortValue{numeric: true, empty: true}
+			}
+		default:
+			text := strings.ToLower(e.cell(card, propDef).value)
+			sortValue = exportSortValue{text: text, empty: text == ""}
+		}
+		key.values[i] = sortValue
+	}
+	return key
+}
+
+// compare orders two cards like the views do: by the sort options, the
+// first one first, with empty values last, or by their manual order. Ties
+// are ordered by title, then by creation.
+func (e *boardTableExport) compare(a, b *exportSortKey) int {
+	if len(e.sortOptions) == 0 {
+		switch {
+		case a.manualIndex >= 0 && b.manualIndex >= 0:
+			return a.manualIndex - b.manualIndex
+		case a.manualIndex >= 0:
+			return -1
+		case b.manualIndex >= 0:
+			return 1
+		}
+		return compareTitleOrCreated(a, b)
+	}
+
+	for i, sortOption := range e.sortOptions {
+		var result int
+		if e.sortDefs[i] == nil {
+			result = compareTitleOrCreated(a, b)
+		} else {
+			va, vb := a.values[i], b.values[i]
+			switch {
+			case va.empty && vb.empty:
+				result = 0
+			case va.empty:
+				return 1
+			case vb.empty:
+				return -1
+			case va.numeric && va.number < vb.number:
+				result = -1
+			case va.numeric && va.number > vb.number:
+				result = 1
+			case !va.numeric:
+				result = strings.Compare(va.text, vb.text)
+			}
+		}
+
+		if sortOption.Reversed {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return compareTitleOrCreated(a, b)
+}
+
+// compareTitleOrCreated orders cards by title, the untitled ones last,
+// then by creation.
+func compareTitleOrCreated(a, b *exportSortKey) int {
+	switch {
+	case a.title != "" && b.title == "":
+		return -1
+	case a.title == "" && b.title != "":
+		return 1
+	}
+	if result := strings.Compare(strings.ToLower(a.title), strings.ToLower(b.title)); result != 0 {
+		return result
+	}
+	switch {
+	case a.createAt < b.createAt:
+		return -1
+	case a.createAt > b.createAt:
+		return 1
+	}
+	return strings.Compare(a.id, b.id)
+}
+
+// decodeViewField decodes a field of a view block.
+func decodeViewField(view *model.Block, field string, v interface{}) error {
+	value, ok := view.Fields[field]
+	if !ok || value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return model.NewErrBadRequest(fmt.Sprintf("invalid %s of view %s: %s", field, view.ID, err))
+	}
+	return nil
+}
+
+// stringValues returns the strings of a list field of a block.
+func stringValues(value interface{}) ([]string, bool) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values, true
+}
+
+// exportValueResolver resolves the users and cards of property values,
+// caching the users as the same ones are found in many cards.
+type exportValueResolver struct {
+	store store.Store
+	users map[string]*model.User
+}
+
+func newExportValueResolver(s store.Store) *exportValueResolver {
+	return &exportValueResolver{store: s, users: map[string]*model.User{}}
+}
+
+func (r *exportValueResolver) GetUserByID(userID string) (*model.User, error) {
+	if user, ok := r.users[userID]; ok {
+		return user, nil
+	}
+	user, err := r.store.GetUserByID(userID)
+	if model.IsErrNotFound(err) {
+		user, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.users[userID] = user
+	return user, nil
+}
+
+func (r *exportValueResolver) GetBlocksByIDs(ids []string) ([]*model.Block, error) {
+	return r.store.GetBlocksByIDs(ids)
+}
+
+// username returns the username of a user, or the id of unknown users.
+func (r *exportValueResolver) username(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	user, err := r.GetUserByID(userID)
+	if err != nil || user == nil {
+		return userID
+	}
+	return user.Username
+}
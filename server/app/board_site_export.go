@@ -0,0 +1,486 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// sitePageSlugLength is the maximum length of the title part of the
+	// page names.
+	sitePageSlugLength = 40
+
+	siteFilesDir = "files"
+	siteViewsDir = "views"
+	siteCardsDir = "cards"
+)
+
+// boardSiteExport writes the pages of a board to a zip: an index page,
+// a page per view and a page per card, with the files of the cards.
+type boardSiteExport struct {
+	app      *App
+	board    *model.Board
+	format   model.BoardExportFormat
+	zip      *zip.Writer
+	cards    *boardTableExport
+	children map[string][]*model.Block
+	files    []string
+	fileSet  map[string]bool
+	markdown goldmark.Markdown
+}
+
+// ExportBoardSite writes a board as a zip of Markdown or HTML pages. The
+// index page links to a page per view, listing its cards in the view
+// order, and to a page per card with its properties, content and
+// comments. The images and attachments of the cards are copied to the
+// files directory and linked relatively, so that the pages can be read
+// offline. If a view is passed, only the view and its cards are exported.
+func (a *App) ExportBoardSite(w io.Writer, opts model.BoardExportOptions) error {
+	if opts.Format != model.BoardExportFormatMarkdown && opts.Format != model.BoardExportFormatHTML {
+		return model.NewErrBadRequest(fmt.Sprintf("unsupported export format %s", opts.Format))
+	}
+
+	cards, err := a.newBoardTableExport(opts.BoardID, "")
+	if err != nil {
+		return err
+	}
+	blocks, err := a.store.GetBlocksForBoard(opts.BoardID)
+	if err != nil {
+		return err
+	}
+
+	site := &boardSiteExport{
+		app:      a,
+		board:    cards.board,
+		format:   opts.Format,
+		zip:      zip.NewWriter(w),
+		cards:    cards,
+		children: map[string][]*model.Block{},
+		fileSet:  map[string]bool{},
+		markdown: goldmark.New(goldmark.WithExtensions(extension.GFM)),
+	}
+
+	views := []*model.Block{}
+	for _, block := range blocks {
+		switch block.Type {
+		case model.TypeView:
+			if opts.ViewID == "" || block.ID == opts.ViewID {
+				views = append(views, block)
+			}
+		case model.TypeCard:
+		default:
+			site.children[block.ParentID] = append(site.children[block.ParentID], block)
+		}
+	}
+	if opts.ViewID != "" && len(views) == 0 {
+		return model.NewErrNotFound("view ID=" + opts.ViewID)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Title != views[j].Title {
+			return views[i].Title < views[j].Title
+		}
+		return views[i].ID < views[j].ID
+	})
+
+	// the exported cards are those of the view when one is passed, and
+	// all the cards by title otherwise
+	var exported []*model.Block
+	for _, view := range views {
+		viewCards, err := site.writeViewPage(view)
+		if err != nil {
+			return err
+		}
+		if opts.ViewID != "" {
+			exported = viewCards
+		}
+	}
+	if opts.ViewID == "" {
+		if exported, err = site.allCards(); err != nil {
+			return err
+		}
+	}
+
+	if err = site.writeIndexPage(views, exported); err != nil {
+		return err
+	}
+	for _, card := range exported {
+		if err = site.writeCardPage(card); err != nil {
+			return err
+		}
+	}
+	for _, filename := range site.files {
+		if err = a.writeBoardFile(site.zip, siteFilesDir+"/"+filename, site.board.TeamID, site.board.ID, filename); err != nil {
+			return fmt.Errorf("cannot write file %s to export: %w", filename, err)
+		}
+	}
+	return site.zip.Close()
+}
+
+// allCards returns the cards of the board, ordered by title.
+func (e *boardSiteExport) allCards() ([]*model.Block, error) {
+	cards := []*model.Block{}
+	keys := []exportSortKey{}
+	err := e.cards.forEachCard(func(card *model.Block) error {
+		cards = append(cards, card)
+		keys = append(keys, e.cards.sortKey(card))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(cardsByKey{cards: cards, keys: keys, export: e.cards})
+	return cards, nil
+}
+
+// cardsByKey sorts cards with their sort keys.
+type cardsByKey struct {
+	cards  []*model.Block
+	keys   []exportSortKey
+	export *boardTableExport
+}
+
+func (c cardsByKey) Len() int { return len(c.cards) }
+
+func (c cardsByKey) Less(i, j int) bool { return c.export.compare(&c.keys[i], &c.keys[j]) < 0 }
+
+func (c cardsByKey) Swap(i, j int) {
+	c.cards[i], c.cards[j] = c.cards[j], c.cards[i]
+	c.keys[i], c.keys[j] = c.keys[j], c.keys[i]
+}
+
+func (e *boardSiteExport) writeIndexPage(views []*model.Block, cards []*model.Block) error {
+	var sb strings.Builder
+	title := withIcon(e.board.Icon, untitled(e.board.Title, "Untitled board"))
+	fmt.Fprintf(&sb, "# %s\n\n", markdownEscape(title))
+	if description := strings.TrimSpace(e.board.Description); description != "" {
+		fmt.Fprintf(&sb, "%s\n\n", description)
+	}
+
+	sb.WriteString("## Views\n\n")
+	for _, view := range views {
+		fmt.Fprintf(&sb, "- [%s](%s)\n", markdownEscape(untitled(view.Title, "Untitled view")), e.pagePath(siteViewsDir, view))
+	}
+
+	sb.WriteString("\n## Cards\n\n")
+	for _, card := range cards {
+		fmt.Fprintf(&sb, "- [%s](%s)\n", markdownEscape(cardTitle(card)), e.pagePath(siteCardsDir, card))
+	}
+	return e.writePage("index"+e.extension(), title, sb.String())
+}
+
+// writeViewPage writes the cards of a view as a table, with the visible
+// properties and in the view order, and returns the cards.
+func (e *boardSiteExport) writeViewPage(view *model.Block) ([]*model.Block, error) {
+	export, err := e.app.newBoardTableExport(e.board.ID, view.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	title := untitled(view.Title, "Untitled view")
+	fmt.Fprintf(&sb, "# %s\n\n", markdownEscape(title))
+	fmt.Fprintf(&sb, "[%s](../index%s)\n\n", markdownEscape(untitled(e.board.Title, "Untitled board")), e.extension())
+
+	var header, separator strings.Builder
+	for _, column := range export.columns {
+		fmt.Fprintf(&header, "| %s ", markdownEscape(column.name))
+		separator.WriteString("| --- ")
+	}
+	fmt.Fprintf(&sb, "%s|\n%s|\n", header.String(), separator.String())
+
+	cards := []*model.Block{}
+	err = export.forEachOrderedCard(func(card *model.Block) error {
+		cards = append(cards, card)
+		for i, cell := range export.row(card) {
+			if export.columns[i].propDef == nil {
+				fmt.Fprintf(&sb, "| [%s](../%s) ", markdownEscape(cardTitle(card)), e.pagePath(siteCardsDir, card))
+				continue
+			}
+			fmt.Fprintf(&sb, "| %s ", markdownEscape(cell.value))
+		}
+		sb.WriteString("|\n")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = e.writePage(e.pagePath(siteViewsDir, view), title, sb.String()); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// writeCardPage writes the properties of a card, its content blocks in
+// the card order, and its comments.
+func (e *boardSiteExport) writeCardPage(card *model.Block) error {
+	var sb strings.Builder
+	title := withIcon(getCardIcon(card), cardTitle(card))
+	fmt.Fprintf(&sb, "# %s\n\n", markdownEscape(title))
+	fmt.Fprintf(&sb, "[%s](../index%s)\n\n", markdownEscape(untitled(e.board.Title, "Untitled board")), e.extension())
+
+	properties := false
+	for _, column := range e.cards.columns {
+		if column.propDef == nil {
+			continue
+		}
+		value := e.cards.cell(card, column.propDef).value
+		if value == "" {
+			continue
+		}
+		if !properties {
+			sb.WriteString("| Property | Value |\n| --- | --- |\n")
+			properties = true
+		}
+		fmt.Fprintf(&sb, "| %s | %s |\n", markdownEscape(column.propDef.Name), markdownEscape(value))
+	}
+	if properties {
+		sb.WriteString("\n")
+	}
+
+	content, comments := e.cardContent(card)
+	for _, block := range content {
+		if text := e.blockMarkdown(block); text != "" {
+			fmt.Fprintf(&sb, "%s\n\n", text)
+		}
+	}
+
+	if len(comments) != 0 {
+		sb.WriteString("## Comments\n\n")
+	}
+	for _, comment := range comments {
+		fmt.Fprintf(&sb, "**%s** · %s\n\n%s\n\n",
+			markdownEscape(e.cards.resolver.username(comment.CreatedBy)),
+			utils.GetTimeForMillis(comment.CreateAt).UTC().Format(exportTimeLayout),
+			strings.TrimSpace(comment.Title),
+		)
+	}
+	return e.writePage(e.pagePath(siteCardsDir, card), title, sb.String())
+}
+
+// cardContent returns the content blocks of a card in its content order,
+// followed by the blocks missing from the order, and its comments by
+// creation.
+func (e *boardSiteExport) cardContent(card *model.Block) ([]*model.Block, []*model.Block) {
+	children := e.children[card.ID]
+	sort.SliceStable(children, func(i, j int) bool { return children[i].CreateAt < children[j].CreateAt })
+
+	byID := map[string]*model.Block{}
+	comments := []*model.Block{}
+	for _, child := range children {
+		if child.Type == model.TypeComment {
+			comments = append(comments, child)
+		} else {
+			byID[child.ID] = child
+		}
+	}
+
+	content := []*model.Block{}
+	for _, id := range flattenContentOrder(card.Fields["contentOrder"]) {
+		if block, ok := byID[id]; ok {
+			content = append(content, block)
+			delete(byID, id)
+		}
+	}
+	for _, child := range children {
+		if _, ok := byID[child.ID]; ok {
+			content = append(content, child)
+		}
+	}
+	return content, comments
+}
+
+// blockMarkdown returns the Markdown of a content block. Text blocks are
+// already Markdown, and the files are linked to their copy in the
+// export.
+func (e *boardSiteExport) blockMarkdown(block *model.Block) string {
+	switch block.Type {
+	case model.TypeText:
+		return strings.TrimSpace(block.Title)
+	case model.TypeCheckbox:
+		checked := " "
+		if block.Fields["value"] == true {
+			checked = "x"
+		}
+		return fmt.Sprintf("- [%s] %s", checked, markdownEscape(block.Title))
+	case model.TypeDivider:
+		return "---"
+	case model.TypeImage, model.TypeAttachment:
+		filename, ok := e.addFile(block)
+		if !ok {
+			return ""
+		}
+		link := "../" + siteFilesDir + "/" + filename
+		if block.Type == model.TypeImage {
+			return fmt.Sprintf("![%s](%s)", markdownEscape(block.Title), link)
+		}
+		return fmt.Sprintf("[%s](%s)", markdownEscape(untitled(block.Title, filename)), link)
+	}
+	return ""
+}
+
+// addFile adds the file of a block to the export, ignoring the names
+// that are not a plain file name.
+func (e *boardSiteExport) addFile(block *model.Block) (string, bool) {
+	filename, err := extractFilename(block)
+	if err != nil || filename != path.Base(filename) || strings.ContainsAny(filename, `\:`) ||
+		filename == "." || filename == ".." {
+		e.app.logger.Debug("Cannot export the file of a block",
+			mlog.String("block_id", block.ID),
+			mlog.String("filename", filename),
+		)
+		return "", false
+	}
+
+	if !e.fileSet[filename] {
+		e.fileSet[filename] = true
+		e.files = append(e.files, filename)
+	}
+	return filename, true
+}
+
+// writePage writes the Markdown of a page, or its HTML for the HTML
+// format. The HTML renderer drops the raw HTML and unsafe links of the
+// Markdown.
+func (e *boardSiteExport) writePage(name, title, markdown string) error {
+	f, err := e.zip.Create(name)
+	if err != nil {
+		return err
+	}
+	if e.format == model.BoardExportFormatMarkdown {
+		_, err = io.WriteString(f, markdown)
+		return err
+	}
+
+	var body bytes.Buffer
+	if err = e.markdown.Convert([]byte(markdown), &body); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, siteHTMLPage, html.EscapeString(title), body.String())
+	return err
+}
+
+// pagePath returns the path of the page of a view or card, made of its
+// title and id so that the names are readable and unique.
+func (e *boardSiteExport) pagePath(dir string, block *model.Block) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(block.Title) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() != 0 {
+			sb.WriteRune('-')
+			dash = true
+		}
+		if sb.Len() >= sitePageSlugLength {
+			break
+		}
+	}
+	slug := strings.Trim(sb.String(), "-")
+	if slug != "" {
+		slug += "-"
+	}
+	return dir + "/" + slug + block.ID + e.extension()
+}
+
+func (e *boardSiteExport) extension() string {
+	if e.format == model.BoardExportFormatHTML {
+		return ".html"
+	}
+	return ".md"
+}
+
+// flattenContentOrder returns the ids of a content order, where a row of
+// blocks is a nested list.
+func flattenContentOrder(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		ids := []string{}
+		for _, item := range v {
+			ids = append(ids, flattenContentOrder(item)...)
+		}
+		return ids
+	}
+	return nil
+}
+
+func getCardIcon(card *model.Block) string {
+	icon, _ := card.Fields["icon"].(string)
+	return icon
+}
+
+func cardTitle(card *model.Block) string {
+	return untitled(card.Title, "Untitled")
+}
+
+func untitled(title, fallback string) string {
+	if strings.TrimSpace(title) == "" {
+		return fallback
+	}
+	return title
+}
+
+func withIcon(icon, title string) string {
+	if icon == "" {
+		return title
+	}
+	return icon + " " + title
+}
+
+// markdownEscape escapes a text written inline in Markdown, in headings,
+// links or table cells.
+func markdownEscape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\r':
+		case r == '\n':
+			sb.WriteRune(' ')
+		case strings.ContainsRune("\\`*_[]<>|~#!", r):
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+const siteHTMLPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; line-height: 1.5; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+img { max-width: 100%%; }
+</style>
+</head>
+<body>
+%s</body>
+</html>
+`
@@ -0,0 +1,34 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestMarkdownEscape(t *testing.T) {
+	require.Equal(t, `plain text`, markdownEscape("plain text"))
+	require.Equal(t, `\[a\](b) \| \*c\*`, markdownEscape("[a](b) | *c*"))
+	require.Equal(t, `two lines`, markdownEscape("two\r\nlines"))
+	require.Equal(t, `\<script\>`, markdownEscape("<script>"))
+}
+
+func TestSitePagePath(t *testing.T) {
+	site := &boardSiteExport{format: model.BoardExportFormatMarkdown}
+	require.Equal(t, "cards/fix-the-login-page-c1.md", site.pagePath(siteCardsDir, &model.Block{ID: "c1", Title: "Fix the login page!"}))
+	require.Equal(t, "cards/c2.md", site.pagePath(siteCardsDir, &model.Block{ID: "c2", Title: "¿?"}))
+
+	site.format = model.BoardExportFormatHTML
+	require.Equal(t, "views/a-b-v1.html", site.pagePath(siteViewsDir, &model.Block{ID: "v1", Title: "  A / B  "}))
+}
+
+func TestFlattenContentOrder(t *testing.T) {
+	order := []interface{}{"a", []interface{}{"b", "c"}, 3, "d"}
+	require.Equal(t, []string{"a", "b", "c", "d"}, flattenContentOrder(order))
+	require.Nil(t, flattenContentOrder(nil))
+}
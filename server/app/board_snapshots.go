@@ -0,0 +1,79 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+)
+
+const maxBoardVersionsPerPage = 200
+
+func (a *App) GetBoardVersions(boardID string, before int64, limit uint64) ([]*model.BoardVersion, error) {
+	if limit == 0 || limit > maxBoardVersionsPerPage {
+		limit = maxBoardVersionsPerPage
+	}
+	return a.store.GetBoardVersions(boardID, before, limit)
+}
+
+func (a *App) GetBoardSnapshot(boardID string, at int64) (*model.BoardSnapshot, error) {
+	return a.store.GetBoardSnapshot(boardID, at)
+}
+
+// DiffBoardSnapshots returns the changes of a board between two times.
+func (a *App) DiffBoardSnapshots(boardID string, from, to int64) (*model.BoardSnapshotDiff, error) {
+	if from > to {
+		return nil, model.NewErrBadRequest("the start of the diff must be before its end")
+	}
+
+	fromSnapshot, err := a.store.GetBoardSnapshot(boardID, from)
+	if err != nil {
+		return nil, err
+	}
+	toSnapshot, err := a.store.GetBoardSnapshot(boardID, to)
+	if err != nil {
+		return nil, err
+	}
+	return model.DiffBoardSnapshots(fromSnapshot, toSnapshot), nil
+}
+
+// RestoreBoardSnapshot restores a board, or some of its cards, as they
+// were at the requested time. The restore is recorded as new changes, so
+// it can be reverted by restoring the time before it.
+func (a *App) RestoreBoardSnapshot(boardID string, req *model.BoardSnapshotRestoreRequest, userID string) (*model.BoardSnapshotRestore, error) {
+	if req.At <= 0 {
+		return nil, model.NewErrBadRequest("invalid restore time")
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	restore, err := a.store.RestoreBoardSnapshot(boardID, req.At, req.CardIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if restore.Board != nil {
+		board = restore.Board
+		a.updateBoardComputedProperties(board, userID)
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		if restore.Board != nil {
+			a.wsAdapter.BroadcastBoardChange(board.TeamID, board)
+		}
+		for _, block := range restore.Blocks {
+			a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
+			a.webhook.NotifyUpdate(block)
+		}
+		for _, blockID := range restore.DeletedBlockIDs {
+			a.wsAdapter.BroadcastBlockDelete(board.TeamID, blockID, boardID)
+		}
+		return nil
+	})
+
+	a.metrics.IncrementBlocksPatched(len(restore.Blocks))
+	return restore, nil
+}
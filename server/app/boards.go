@@ -342,11 +342,25 @@ func (a *App) PatchBoard(patch *model.BoardPatch, boardID, userID string) (*mode
 		}
 	}
 
+	var oldBoard *model.Board
+	cardPropertiesChanged := len(patch.UpdatedCardProperties) != 0 || len(patch.DeletedCardProperties) != 0
+	if cardPropertiesChanged {
+		var err error
+		if oldBoard, err = a.store.GetBoard(boardID); err != nil {
+			return nil, err
+		}
+	}
+
 	updatedBoard, err := a.store.PatchBoard(boardID, patch, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	if cardPropertiesChanged {
+		a.broadcastCardPropertyCleanup(oldBoard, updatedBoard, patch.RemappedOptions)
+		a.updateBoardComputedProperties(updatedBoard, userID)
+	}
+
 	// Post message to channel if linked/unlinked
 	if patch.ChannelID != nil {
 		var username string
@@ -408,6 +422,35 @@ func (a *App) PatchBoard(patch *model.BoardPatch, boardID, userID string) (*mode
 	return updatedBoard, nil
 }
 
+// propertyOptionsPatch returns the patch adding select options to the
+// card properties of a board, by property id.
+func propertyOptionsPatch(board *model.Board, added map[string][]model.PropDefOption) *model.BoardPatch {
+	patch := &model.BoardPatch{}
+	for _, prop := range board.CardProperties {
+		propID, _ := prop["id"].(string)
+		if len(added[propID]) == 0 {
+			continue
+		}
+
+		updated := make(map[string]interface{}, len(prop))
+		for k, v := range prop {
+			updated[k] = v
+		}
+		options, _ := prop["options"].([]interface{})
+		options = append([]interface{}{}, options...)
+		for _, option := range added[propID] {
+			options = append(options, map[string]interface{}{
+				"id":    option.ID,
+				"value": option.Value,
+				"color": option.Color,
+			})
+		}
+		updated["options"] = options
+		patch.UpdatedCardProperties = append(patch.UpdatedCardProperties, updated)
+	}
+	return patch
+}
+
 func (a *App) postChannelMessage(message, channelID string) {
 	err := a.store.PostMessage(message, "", channelID)
 	if err != nil {
@@ -453,6 +496,12 @@ func (a *App) DeleteBoard(boardID, userID string) error {
 		return err
 	}
 
+	// the board is deleted even if its cards can't be removed from the
+	// relations of other boards
+	if err := a.removeBoardRelations(boardID, userID); err != nil {
+		a.logger.Error("Unable to remove the relations to a deleted board", mlog.String("board_id", boardID), mlog.Err(err))
+	}
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastBoardDelete(board.TeamID, boardID)
 		return nil
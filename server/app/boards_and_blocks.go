@@ -12,6 +12,14 @@ func (a *App) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, a
 	var members []*model.BoardMember
 	var err error
 
+	boards := make(map[string]*model.Board, len(bab.Boards))
+	for _, board := range bab.Boards {
+		boards[board.ID] = board
+	}
+	if err = a.validateCardBlocks(boards, bab.Blocks); err != nil {
+		return nil, err
+	}
+
 	if addMember {
 		newBab, members, err = a.store.CreateBoardsAndBlocksWithAdmin(bab, userID)
 	} else {
@@ -66,11 +74,38 @@ func (a *App) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID stri
 		oldBlocksMap[block.ID] = block
 	}
 
+	if err = a.validatePatchBoardsAndBlocks(pbab, oldBlocks); err != nil {
+		return nil, err
+	}
+
+	oldBoards := map[string]*model.Board{}
+	for i, boardID := range pbab.BoardIDs {
+		patch := pbab.BoardPatches[i]
+		if patch == nil || (len(patch.UpdatedCardProperties) == 0 && len(patch.DeletedCardProperties) == 0) {
+			continue
+		}
+		if oldBoards[boardID], err = a.store.GetBoard(boardID); err != nil {
+			return nil, err
+		}
+	}
+
 	bab, err := a.store.PatchBoardsAndBlocks(pbab, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	for i, boardID := range pbab.BoardIDs {
+		oldBoard, ok := oldBoards[boardID]
+		if !ok {
+			continue
+		}
+		for _, board := range bab.Boards {
+			if board.ID == boardID {
+				a.broadcastCardPropertyCleanup(oldBoard, board, pbab.BoardPatches[i].RemappedOptions)
+			}
+		}
+	}
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		teamID := bab.Boards[0].TeamID
 
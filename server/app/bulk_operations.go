@@ -0,0 +1,307 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// bulkOperationSyncLimit is the maximum number of cards of an
+	// operation applied during the request, larger operations run in the
+	// background.
+	bulkOperationSyncLimit = 100
+
+	// bulkOperationProgressStep is the number of cards processed between
+	// two progress updates of a background operation.
+	bulkOperationProgressStep = 25
+
+	bulkOperationQueueSize = 100
+	bulkOperationPoolSize  = 2
+
+	// bulkOperationStaleAfter is the time after which a running operation
+	// without progress is considered interrupted, e.g. by a restart.
+	bulkOperationStaleAfter = 10 * time.Minute
+)
+
+// StartBulkOperation applies an operation to the cards of a board that
+// match a filter. The operations on more than bulkOperationSyncLimit
+// cards run in the background and report their progress over websocket;
+// the returned operation is still running then. The changes are recorded
+// as a single undo entry, except for the subscriptions.
+func (a *App) StartBulkOperation(boardID, userID string, req *model.BulkOperationRequest, disableNotify bool) (*model.BulkOperation, error) {
+	if err := req.IsValid(); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.checkBulkOperationRequest(board, req); err != nil {
+		return nil, err
+	}
+
+	blocks, err := a.store.GetBlocks(model.QueryBlocksOptions{
+		BoardID:         boardID,
+		BlockType:       model.TypeCard,
+		ExcludeArchived: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cards := []*model.Block{}
+	for _, card := range model.FilterCards(blocks, req.Filter) {
+		if isTemplate, _ := card.Fields["isTemplate"].(bool); !isTemplate {
+			cards = append(cards, card)
+		}
+	}
+
+	operation := &model.BulkOperation{
+		ID:      utils.NewID(utils.IDTypeNone),
+		BoardID: boardID,
+		UserID:  userID,
+		Type:    req.Type,
+		Status:  model.BulkOperationStatusRunning,
+		Total:   len(cards),
+	}
+	if err = a.store.InsertBulkOperation(operation); err != nil {
+		return nil, err
+	}
+
+	if len(cards) > bulkOperationSyncLimit {
+		running := *operation
+		queued := a.bulkOperationQueue.TryEnqueue(func() error {
+			a.runBulkOperation(operation, board, req, cards, disableNotify)
+			return nil
+		})
+		if !queued {
+			operation.Status = model.BulkOperationStatusFailed
+			operation.Error = "too many bulk operations are running, try again later"
+			a.updateBulkOperation(board, operation)
+			return operation, nil
+		}
+		return &running, nil
+	}
+
+	a.runBulkOperation(operation, board, req, cards, disableNotify)
+	return operation, nil
+}
+
+// FailStaleBulkOperations marks as failed the running operations without
+// progress for bulkOperationStaleAfter, whose server stopped before they
+// completed.
+func (a *App) FailStaleBulkOperations(now time.Time) {
+	updatedBefore := utils.GetMillisForTime(now.Add(-bulkOperationStaleAfter))
+	failed, err := a.store.FailStaleBulkOperations(updatedBefore, "the operation was interrupted")
+	if err != nil {
+		a.logger.Error("Unable to fail the stale bulk operations", mlog.Err(err))
+		return
+	}
+	if failed > 0 {
+		a.logger.Info("Stale bulk operations marked as failed", mlog.Int("count", failed))
+	}
+}
+
+// GetBulkOperation returns the progress of a bulk operation.
+func (a *App) GetBulkOperation(operationID string) (*model.BulkOperation, error) {
+	return a.store.GetBulkOperation(operationID)
+}
+
+// checkBulkOperationRequest checks the property, the target board or the
+// subscriber of an operation against the board of the cards.
+func (a *App) checkBulkOperationRequest(board *model.Board, req *model.BulkOperationRequest) error {
+	switch req.Type {
+	case model.BulkOperationSetProperty, model.BulkOperationClearProperty:
+		schema, err := model.ParsePropertySchema(board)
+		if err != nil {
+			return err
+		}
+		if _, ok := schema[req.PropertyID]; !ok {
+			return model.NewErrBadRequest(fmt.Sprintf("property %s not found in board %s", req.PropertyID, board.ID))
+		}
+	case model.BulkOperationMoveToBoard:
+		if req.TargetBoardID == board.ID {
+			return model.NewErrBadRequest("the cards are already in the target board")
+		}
+		target, err := a.store.GetBoard(req.TargetBoardID)
+		if err != nil {
+			return err
+		}
+		if target.TeamID != board.TeamID {
+			return model.NewErrBadRequest(fmt.Sprintf("board %s is not in the team of board %s", target.ID, board.ID))
+		}
+	case model.BulkOperationAddSubscriber:
+		if !a.permissions.HasPermissionToBoard(req.SubscriberID, board.ID, model.PermissionViewBoard) {
+			return model.NewErrBadRequest(fmt.Sprintf("user %s cannot view board %s", req.SubscriberID, board.ID))
+		}
+	}
+	return nil
+}
+
+// runBulkOperation applies an operation to the cards one by one. It stops
+// at the first failure, and the changes applied until then are still
+// recorded for undo.
+func (a *App) runBulkOperation(operation *model.BulkOperation, board *model.Board, req *model.BulkOperationRequest, cards []*model.Block, disableNotify bool) {
+	changes := make([]model.BlockChange, 0, len(cards))
+	var opErr error
+	for i, card := range cards {
+		cardChanges, err := a.applyBulkOperationRecover(board, req, card, operation.UserID, disableNotify)
+		if err != nil {
+			opErr = fmt.Errorf("card %s: %w", card.ID, err)
+			break
+		}
+		changes = append(changes, cardChanges...)
+
+		operation.Processed = i + 1
+		if operation.Processed < operation.Total && operation.Processed%bulkOperationProgressStep == 0 {
+			a.updateBulkOperation(board, operation)
+		}
+	}
+
+	operation.Status = model.BulkOperationStatusCompleted
+	if opErr != nil {
+		a.logger.Error("Bulk operation failed",
+			mlog.String("operation_id", operation.ID),
+			mlog.String("board_id", board.ID),
+			mlog.Err(opErr),
+		)
+		operation.Status = model.BulkOperationStatusFailed
+		operation.Error = opErr.Error()
+	}
+
+	if req.IsUndoable() {
+		a.recordBulkOperationUndo(operation.UserID, board.ID, req, changes)
+	}
+
+	a.updateBulkOperation(board, operation)
+}
+
+// recordBulkOperationUndo records the changes of an operation with one
+// undo entry per board, as the moved cards are copied to the target
+// board and undoing the move on each board reverts its own blocks.
+func (a *App) recordBulkOperationUndo(userID, boardID string, req *model.BulkOperationRequest, changes []model.BlockChange) {
+	changesByBoard := map[string][]model.BlockChange{}
+	boardIDs := []string{boardID}
+	for _, change := range changes {
+		changeBoardID := boardID
+		if change.After != nil {
+			changeBoardID = change.After.BoardID
+		} else if change.Before != nil {
+			changeBoardID = change.Before.BoardID
+		}
+		if _, ok := changesByBoard[changeBoardID]; !ok && changeBoardID != boardID {
+			boardIDs = append(boardIDs, changeBoardID)
+		}
+		changesByBoard[changeBoardID] = append(changesByBoard[changeBoardID], change)
+	}
+
+	for _, changeBoardID := range boardIDs {
+		undoOperation := model.UndoOperationPatchBlocks
+		switch {
+		case req.Type == model.BulkOperationDelete:
+			undoOperation = model.UndoOperationDeleteBlock
+		case req.Type == model.BulkOperationMoveToBoard && changeBoardID == boardID:
+			undoOperation = model.UndoOperationDeleteBlock
+		case req.Type == model.BulkOperationMoveToBoard:
+			undoOperation = model.UndoOperationInsertBlocks
+		}
+		a.RecordUndoEntry(userID, changeBoardID, undoOperation, nil, changesByBoard[changeBoardID])
+	}
+}
+
+// updateBulkOperation stores the progress of an operation and broadcasts
+// it to the board.
+func (a *App) updateBulkOperation(board *model.Board, operation *model.BulkOperation) {
+	if err := a.store.UpdateBulkOperation(operation); err != nil {
+		a.logger.Error("Unable to update bulk operation", mlog.String("operation_id", operation.ID), mlog.Err(err))
+	}
+
+	progress := *operation
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBulkOperationChange(board.TeamID, &progress)
+		return nil
+	})
+}
+
+// applyBulkOperationRecover applies an operation to a card, turning a
+// panic into an error so that the operation is marked as failed.
+func (a *App) applyBulkOperationRecover(board *model.Board, req *model.BulkOperationRequest, card *model.Block, userID string, disableNotify bool) (changes []model.BlockChange, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.logger.Error("Bulk operation panic",
+				mlog.String("card_id", card.ID),
+				mlog.Any("panic", r),
+				mlog.String("stack", string(debug.Stack())),
+			)
+			err = fmt.Errorf("unexpected error: %v", r)
+		}
+	}()
+	return a.applyBulkOperation(board, req, card, userID, disableNotify)
+}
+
+// applyBulkOperation applies an operation to a card and returns the
+// changes of the blocks, or none if the card didn't change. A moved card
+// is deleted and copied to the target board.
+func (a *App) applyBulkOperation(board *model.Board, req *model.BulkOperationRequest, card *model.Block, userID string, disableNotify bool) ([]model.BlockChange, error) {
+	switch req.Type {
+	case model.BulkOperationSetProperty, model.BulkOperationClearProperty:
+		props := getCardProperties(card)
+		if req.Type == model.BulkOperationSetProperty {
+			props[req.PropertyID] = req.Value
+		} else {
+			delete(props, req.PropertyID)
+		}
+		if reflect.DeepEqual(props, getCardProperties(card)) {
+			return nil, nil
+		}
+
+		patch := &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": props},
+		}
+		newCard, err := a.PatchBlockAndNotify(card.ID, patch, userID, disableNotify)
+		if err != nil {
+			return nil, err
+		}
+		return []model.BlockChange{{Before: card, After: newCard}}, nil
+	case model.BulkOperationMoveToBoard:
+		moveReq := &model.CardMoveRequest{TargetBoardID: req.TargetBoardID}
+		_, newCard, _, err := a.moveCardWithMapping(card.ID, userID, moveReq, disableNotify)
+		if err != nil {
+			return nil, err
+		}
+		// undoing the move deletes the copy and restores the card
+		return []model.BlockChange{{Before: card}, {After: newCard}}, nil
+	case model.BulkOperationArchive:
+		newCard, err := a.ArchiveCard(card.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		return []model.BlockChange{{Before: card, After: newCard}}, nil
+	case model.BulkOperationDelete:
+		if err := a.DeleteBlockAndNotify(card.ID, userID, disableNotify); err != nil {
+			return nil, err
+		}
+		return []model.BlockChange{{Before: card}}, nil
+	case model.BulkOperationAddSubscriber:
+		sub := &model.Subscription{
+			BlockType:      model.TypeCard,
+			BlockID:        card.ID,
+			SubscriberType: model.SubTypeUser,
+			SubscriberID:   req.SubscriberID,
+		}
+		_, err := a.CreateSubscription(sub)
+		return nil, err
+	}
+	return nil, fmt.Errorf("unknown bulk operation %q", req.Type)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestRunBulkOperation(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	th.Store.EXPECT().GetMembersForBoard(testBoardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+
+	board := &model.Board{ID: testBoardID, TeamID: "team-id"}
+	cards := []*model.Block{{ID: "card-1", BoardID: testBoardID, Type: model.TypeCard}}
+
+	t.Run("a panic fails the operation", func(t *testing.T) {
+		operation := &model.BulkOperation{
+			ID:      "operation-id",
+			BoardID: testBoardID,
+			UserID:  "user-id",
+			Type:    model.BulkOperationArchive,
+			Status:  model.BulkOperationStatusRunning,
+			Total:   1,
+		}
+		th.Store.EXPECT().GetBlock("card-1").DoAndReturn(func(string) (*model.Block, error) {
+			panic("unexpected")
+		})
+		th.Store.EXPECT().UpdateBulkOperation(gomock.Any()).Return(nil)
+
+		require.NotPanics(t, func() {
+			th.App.runBulkOperation(operation, board, &model.BulkOperationRequest{Type: model.BulkOperationArchive}, cards, true)
+		})
+		require.Equal(t, model.BulkOperationStatusFailed, operation.Status)
+		require.Contains(t, operation.Error, "unexpected")
+		require.Zero(t, operation.Processed)
+	})
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// AddCardDependency makes a card block another one. Dependencies that
+// would create a cycle are rejected.
+func (a *App) AddCardDependency(dependency *model.CardDependency) (*model.CardDependency, error) {
+	if err := dependency.IsValid(); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	blocker, err := a.getCardBlock(dependency.BlockerID)
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := a.getCardBlock(dependency.BlockedID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the new dependency closes a cycle if the blocked card already
+	// blocks the blocker, directly or through other cards
+	cycle, err := a.cardBlocks(blocked.ID, blocker.ID)
+	if err != nil {
+		return nil, err
+	}
+	if cycle {
+		return nil, model.NewErrBadRequest(model.ErrCardDependencyCycle.Error())
+	}
+
+	if err := a.store.AddCardDependency(dependency); err != nil {
+		return nil, err
+	}
+
+	a.broadcastCardBlockedState(blocked, blocker.ID)
+	return dependency, nil
+}
+
+// DeleteCardDependency removes the dependency between two cards.
+func (a *App) DeleteCardDependency(blockerID, blockedID string) error {
+	if err := a.store.DeleteCardDependency(blockerID, blockedID); err != nil {
+		return err
+	}
+
+	blocked, err := a.store.GetBlock(blockedID)
+	if model.IsErrNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	a.broadcastCardBlockedState(blocked, blockerID)
+	return nil
+}
+
+// GetCardDependencies returns the dependencies of a card and its
+// computed blocked state.
+func (a *App) GetCardDependencies(cardID string) (*model.CardDependencies, error) {
+	dependencies, err := a.store.GetCardDependencies(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.CardDependencies{
+		CardID:    cardID,
+		BlockedBy: []*model.CardDependency{},
+		Blocking:  []*model.CardDependency{},
+	}
+	blockerIDs := []string{}
+	for _, dependency := range dependencies {
+		if dependency.BlockedID == cardID {
+			result.BlockedBy = append(result.BlockedBy, dependency)
+			blockerIDs = append(blockerIDs, dependency.BlockerID)
+		} else {
+			result.Blocking = append(result.Blocking, dependency)
+		}
+	}
+
+	if result.Blocked, err = a.isBlockedBy(blockerIDs); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (a *App) getCardBlock(cardID string) (*model.Block, error) {
+	block, err := a.store.GetBlock(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != model.TypeCard {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("block %s is not a card", cardID))
+	}
+	return block, nil
+}
+
+// cardBlocks returns true if the card fromID blocks the card toID,
+// directly or through a chain of dependencies.
+func (a *App) cardBlocks(fromID, toID string) (bool, error) {
+	visited := map[string]bool{fromID: true}
+	pending := []string{fromID}
+	for len(pending) > 0 {
+		cardID := pending[0]
+		pending = pending[1:]
+
+		dependencies, err := a.store.GetCardDependencies(cardID)
+		if err != nil {
+			return false, err
+		}
+		for _, dependency := range dependencies {
+			if dependency.BlockerID != cardID {
+				continue
+			}
+			if dependency.BlockedID == toID {
+				return true, nil
+			}
+			if !visited[dependency.BlockedID] {
+				visited[dependency.BlockedID] = true
+				pending = append(pending, dependency.BlockedID)
+			}
+		}
+	}
+	return false, nil
+}
+
+// getCompletedCards returns the completion state of the given cards.
+// Deleted cards are not included in the result.
+func (a *App) getCompletedCards(cardIDs []string) (map[string]bool, error) {
+	completed := map[string]bool{}
+	if len(cardIDs) == 0 {
+		return completed, nil
+	}
+
+	cards, err := a.store.GetBlocksByIDs(cardIDs)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	boards := map[string]*model.Board{}
+	for _, card := range cards {
+		board, ok := boards[card.BoardID]
+		if !ok {
+			if board, err = a.store.GetBoard(card.BoardID); err != nil {
+				return nil, err
+			}
+			boards[board.ID] = board
+		}
+		completed[card.ID] = model.IsCardCompleted(board, card)
+	}
+	return completed, nil
+}
+
+// isBlockedBy returns true if any of the blocker cards exists and is
+// not completed.
+func (a *App) isBlockedBy(blockerIDs []string) (bool, error) {
+	completed, err := a.getCompletedCards(blockerIDs)
+	if err != nil {
+		return false, err
+	}
+	for _, isCompleted := range completed {
+		if !isCompleted {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// setCardsBlockedState sets the computed blocked state of the cards of
+// a board.
+func (a *App) setCardsBlockedState(boardID string, cards []*model.Card) error {
+	dependencies, err := a.store.GetCardDependenciesForBoard(boardID)
+	if err != nil {
+		return err
+	}
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	blockerIDs := []string{}
+	for _, dependency := range dependencies {
+		blockerIDs = append(blockerIDs, dependency.BlockerID)
+	}
+	completed, err := a.getCompletedCards(blockerIDs)
+	if err != nil {
+		return err
+	}
+
+	blocked := map[string]bool{}
+	for _, dependency := range dependencies {
+		if isCompleted, ok := completed[dependency.BlockerID]; ok && !isCompleted {
+			blocked[dependency.BlockedID] = true
+		}
+	}
+	for _, card := range cards {
+		card.Blocked = blocked[card.ID]
+	}
+	return nil
+}
+
+// notifyBlockerCompletionChanged broadcasts the new blocked state of the
+// cards blocked by a card that was completed or reopened.
+func (a *App) notifyBlockerCompletionChanged(board *model.Board, oldCard, card *model.Block) {
+	if card.Type != model.TypeCard {
+		return
+	}
+	if model.IsCardCompleted(board, oldCard) == model.IsCardCompleted(board, card) {
+		return
+	}
+
+	dependencies, err := a.store.GetCardDependencies(card.ID)
+	if err != nil {
+		a.logger.Error("notifyBlockerCompletionChanged cannot get the card dependencies", mlog.String("card_id", card.ID), mlog.Err(err))
+		return
+	}
+
+	for _, dependency := range dependencies {
+		if dependency.BlockerID != card.ID {
+			continue
+		}
+		blocked, err := a.store.GetBlock(dependency.BlockedID)
+		if model.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			a.logger.Error("notifyBlockerCompletionChanged cannot get the blocked card", mlog.String("card_id", dependency.BlockedID), mlog.Err(err))
+			continue
+		}
+		a.broadcastCardBlockedState(blocked, card.ID)
+	}
+}
+
+// broadcastCardBlockedState computes the blocked state of a card after
+// one of its blockers changed and sends it through the websockets.
+func (a *App) broadcastCardBlockedState(card *model.Block, blockerID string) {
+	dependencies, err := a.GetCardDependencies(card.ID)
+	if err != nil {
+		a.logger.Error("broadcastCardBlockedState cannot get the card dependencies", mlog.String("card_id", card.ID), mlog.Err(err))
+		return
+	}
+
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		a.logger.Error("broadcastCardBlockedState cannot get the board", mlog.String("board_id", card.BoardID), mlog.Err(err))
+		return
+	}
+
+	change := &model.CardBlockedStateChange{
+		BoardID:   card.BoardID,
+		CardID:    card.ID,
+		BlockerID: blockerID,
+		Blocked:   dependencies.Blocked,
+	}
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastCardBlockedStateChange(board.TeamID, change)
+		return nil
+	})
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func dependencyTestBoard() *model.Board {
+	return &model.Board{
+		ID:     testBoardID,
+		TeamID: "team-id",
+		Properties: map[string]interface{}{
+			model.BoardPropertyCompletedOptionIDs: []interface{}{"done-option"},
+		},
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select"},
+		},
+	}
+}
+
+func TestAddCardDependency(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := dependencyTestBoard()
+	th.Store.EXPECT().GetMembersForBoard(gomock.Any()).AnyTimes()
+	th.Store.EXPECT().GetBoard(testBoardID).AnyTimes().Return(board, nil)
+
+	card1 := relationTestCard("card1", testBoardID, map[string]interface{}{})
+	card2 := relationTestCard("card2", testBoardID, map[string]interface{}{})
+	card3 := relationTestCard("card3", testBoardID, map[string]interface{}{})
+
+	t.Run("a card cannot block itself", func(t *testing.T) {
+		_, err := th.App.AddCardDependency(&model.CardDependency{BlockerID: "card1", BlockedID: "card1"})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("only cards can be dependencies", func(t *testing.T) {
+		view := &model.Block{ID: "view1", BoardID: testBoardID, Type: model.TypeView}
+		th.Store.EXPECT().GetBlock("view1").Return(view, nil)
+
+		_, err := th.App.AddCardDependency(&model.CardDependency{BlockerID: "view1", BlockedID: "card1"})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("rejects dependencies that create a cycle", func(t *testing.T) {
+		// card1 blocks card2, card2 blocks card3; card3 cannot block card1
+		th.Store.EXPECT().GetBlock("card3").Return(card3, nil)
+		th.Store.EXPECT().GetBlock("card1").Return(card1, nil)
+		th.Store.EXPECT().GetCardDependencies("card1").Return([]*model.CardDependency{
+			{BlockerID: "card1", BlockedID: "card2"},
+		}, nil)
+		th.Store.EXPECT().GetCardDependencies("card2").Return([]*model.CardDependency{
+			{BlockerID: "card1", BlockedID: "card2"},
+			{BlockerID: "card2", BlockedID: "card3"},
+		}, nil)
+
+		_, err := th.App.AddCardDependency(&model.CardDependency{BlockerID: "card3", BlockedID: "card1"})
+		require.True(t, model.IsErrBadRequest(err))
+		require.Contains(t, err.Error(), model.ErrCardDependencyCycle.Error())
+	})
+
+	t.Run("stores the dependency", func(t *testing.T) {
+		dependency := &model.CardDependency{BlockerID: "card1", BlockedID: "card2", CreatedBy: "user-id-1"}
+
+		th.Store.EXPECT().GetBlock("card1").Return(card1, nil)
+		th.Store.EXPECT().GetBlock("card2").Return(card2, nil)
+		th.Store.EXPECT().GetCardDependencies("card2").Return([]*model.CardDependency{}, nil)
+		th.Store.EXPECT().AddCardDependency(dependency).Return(nil)
+		th.Store.EXPECT().GetCardDependencies("card2").Return([]*model.CardDependency{dependency}, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{"card1"}).Return([]*model.Block{card1}, nil)
+
+		result, err := th.App.AddCardDependency(dependency)
+		require.NoError(t, err)
+		require.Equal(t, dependency, result)
+	})
+}
+
+func TestGetCardDependencies(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := dependencyTestBoard()
+	th.Store.EXPECT().GetBoard(testBoardID).AnyTimes().Return(board, nil)
+
+	dependencies := []*model.CardDependency{
+		{BlockerID: "card1", BlockedID: "card2"},
+		{BlockerID: "card2", BlockedID: "card3"},
+	}
+
+	t.Run("blocked by a card that is not completed", func(t *testing.T) {
+		card1 := relationTestCard("card1", testBoardID, map[string]interface{}{"status": "todo-option"})
+
+		th.Store.EXPECT().GetCardDependencies("card2").Return(dependencies, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{"card1"}).Return([]*model.Block{card1}, nil)
+
+		result, err := th.App.GetCardDependencies("card2")
+		require.NoError(t, err)
+		require.True(t, result.Blocked)
+		require.Equal(t, []*model.CardDependency{dependencies[0]}, result.BlockedBy)
+		require.Equal(t, []*model.CardDependency{dependencies[1]}, result.Blocking)
+	})
+
+	t.Run("not blocked once the blocker is completed", func(t *testing.T) {
+		card1 := relationTestCard("card1", testBoardID, map[string]interface{}{"status": "done-option"})
+
+		th.Store.EXPECT().GetCardDependencies("card2").Return(dependencies, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{"card1"}).Return([]*model.Block{card1}, nil)
+
+		result, err := th.App.GetCardDependencies("card2")
+		require.NoError(t, err)
+		require.False(t, result.Blocked)
+	})
+
+	t.Run("deleted blockers do not block", func(t *testing.T) {
+		th.Store.EXPECT().GetCardDependencies("card2").Return(dependencies, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{"card1"}).Return([]*model.Block{}, model.NewErrNotAllFound("block", []string{"card1"}))
+
+		result, err := th.App.GetCardDependencies("card2")
+		require.NoError(t, err)
+		require.False(t, result.Blocked)
+	})
+}
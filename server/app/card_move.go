@@ -0,0 +1,240 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// PreviewCardMove returns how the property values of a card would be
+// mapped to the target board of a move.
+func (a *App) PreviewCardMove(cardID string, req *model.CardMoveRequest) (*model.CardMoveMapping, error) {
+	_, _, _, mapping, err := a.getCardMoveMapping(cardID, req)
+	return mapping, err
+}
+
+// MoveCardWithMapping copies a card, its content blocks, comments and
+// files to another board, mapping its properties by name and its select
+// options by value. The source card is then deleted, or archived and
+// linked to the copy if the request asks for a redirect.
+func (a *App) MoveCardWithMapping(cardID, userID string, req *model.CardMoveRequest, disableNotify bool) (*model.CardMoveResult, error) {
+	_, newCard, mapping, err := a.moveCardWithMapping(cardID, userID, req, disableNotify)
+	if err != nil {
+		return nil, err
+	}
+
+	movedCard, err := a.GetCardByID(newCard.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CardMoveResult{Card: movedCard, Mapping: mapping}, nil
+}
+
+// moveCardWithMapping moves a card and returns the source card before the
+// move and its copy on the target board. The copies, the options created
+// on the target board and the change of the source card are written in a
+// single transaction.
+func (a *App) moveCardWithMapping(cardID, userID string, req *model.CardMoveRequest, disableNotify bool) (*model.Block, *model.Block, *model.CardMoveMapping, error) {
+	card, source, target, mapping, err := a.getCardMoveMapping(cardID, req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	children, err := a.store.GetBlocksWithParent(source.ID, card.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	now := utils.GetMillis()
+	blocks := make([]*model.Block, 0, len(children)+1)
+	for _, block := range append([]*model.Block{card}, children...) {
+		copied := *block
+		copied.Fields = make(map[string]interface{}, len(block.Fields))
+		for k, v := range block.Fields {
+			copied.Fields[k] = v
+		}
+		copied.BoardID = target.ID
+		copied.CreateAt = now
+		copied.UpdateAt = now
+		copied.ArchiveAt = 0
+		blocks = append(blocks, &copied)
+	}
+
+	newCard := blocks[0]
+	newCard.ParentID = target.ID
+	newCard.Fields["properties"] = mapping.MapProperties(getCardProperties(card))
+	newCard.Fields[model.CardFieldMovedFromCardID] = card.ID
+	newCard.Fields[model.CardFieldMovedFromBoardID] = source.ID
+	delete(newCard.Fields, model.CardFieldMovedToCardID)
+	delete(newCard.Fields, model.CardFieldMovedToBoardID)
+
+	blocks = model.GenerateBlockIDs(blocks, a.logger)
+	for _, block := range blocks {
+		if block.Type == model.TypeCard {
+			newCard = block
+			break
+		}
+	}
+
+	move := &model.CardMoveBlocks{
+		TargetBoardID: target.ID,
+		Blocks:        blocks,
+		SourceCardID:  card.ID,
+		ArchiveSource: req.SourceAction == model.CardMoveSourceRedirect,
+	}
+
+	// the copies are validated against the target board with the created
+	// options
+	patchedTarget := target
+	if created := mapping.CreatedOptions(); len(created) > 0 {
+		move.TargetBoardPatch = propertyOptionsPatch(target, created)
+		patched := *target
+		patchedTarget = move.TargetBoardPatch.Patch(&patched)
+	}
+	if err = a.validateCardBlocks(map[string]*model.Board{target.ID: patchedTarget}, blocks); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// the files are copied before the blocks referencing them are written,
+	// and the card is not moved without them
+	newFileNames, err := a.CopyCardFiles(source.ID, blocks, false)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot copy the files of card %s: %w", card.ID, err)
+	}
+	for _, block := range blocks {
+		if block.Type != model.TypeImage && block.Type != model.TypeAttachment {
+			continue
+		}
+		fileID, ok := block.Fields["fileId"].(string)
+		if !ok {
+			if fileID, ok = block.Fields["attachmentId"].(string); !ok {
+				continue
+			}
+		}
+		newFileName, ok := newFileNames[fileID]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("file %s of card %s was not copied", fileID, card.ID)
+		}
+		block.Fields["fileId"] = newFileName
+		delete(block.Fields, "attachmentId")
+	}
+
+	var refs []cardRelationReference
+	if move.ArchiveSource {
+		move.SourcePatch = &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				model.CardFieldMovedToCardID:  newCard.ID,
+				model.CardFieldMovedToBoardID: target.ID,
+			},
+		}
+	} else {
+		// the references are kept on the deleted card, so undoing the move
+		// restores them
+		if refs, err = a.removeCardRelations(card, userID); err != nil {
+			return nil, nil, nil, err
+		}
+		if len(refs) > 0 {
+			move.SourcePatch = &model.BlockPatch{
+				UpdatedFields: map[string]interface{}{relationReferencesField: refs},
+			}
+		}
+	}
+
+	updatedTarget, err := a.store.MoveCardBlocks(move, userID)
+	if err != nil {
+		a.restoreRelationReferences(card.ID, refs, userID)
+		return nil, nil, nil, err
+	}
+
+	a.notifyCardMoved(source, target, updatedTarget, card, blocks, move.ArchiveSource, userID, disableNotify)
+	return card, newCard, mapping, nil
+}
+
+// notifyCardMoved updates the computed properties of the copy of a moved
+// card and broadcasts the changes of both boards.
+func (a *App) notifyCardMoved(source, target, updatedTarget *model.Board, card *model.Block, blocks []*model.Block, archived bool, userID string, disableNotify bool) {
+	if updatedTarget != nil {
+		target = updatedTarget
+	}
+	for _, block := range blocks {
+		if block.Type == model.TypeCard {
+			a.updateComputedProperties(target, block, userID)
+			a.syncRelationBackReferences(target, nil, block, userID)
+		}
+	}
+
+	var sourceCard *model.Block
+	if archived {
+		var err error
+		if sourceCard, err = a.store.GetBlock(card.ID); err != nil {
+			a.logger.Error("Unable to get the source of a moved card", mlog.String("card_id", card.ID), mlog.Err(err))
+		}
+	}
+
+	a.metrics.IncrementBlocksInserted(len(blocks))
+	a.blockChangeNotifier.Enqueue(func() error {
+		if updatedTarget != nil {
+			a.wsAdapter.BroadcastBoardChange(target.TeamID, updatedTarget)
+		}
+		for _, block := range blocks {
+			a.wsAdapter.BroadcastBlockChange(target.TeamID, block)
+			a.webhook.NotifyUpdate(block)
+			if !disableNotify {
+				a.notifyBlockChanged(notify.Add, block, nil, userID)
+			}
+		}
+
+		if archived {
+			if sourceCard != nil {
+				a.wsAdapter.BroadcastCardArchivedStateChange(source.TeamID, sourceCard)
+				a.webhook.NotifyUpdate(sourceCard)
+			}
+			return nil
+		}
+		a.wsAdapter.BroadcastBlockDelete(source.TeamID, card.ID, source.ID)
+		a.metrics.IncrementBlocksDeleted(1)
+		if !disableNotify {
+			a.notifyBlockChanged(notify.Delete, card, card, userID)
+		}
+		return nil
+	})
+}
+
+func (a *App) getCardMoveMapping(cardID string, req *model.CardMoveRequest) (*model.Block, *model.Board, *model.Board, *model.CardMoveMapping, error) {
+	if err := req.IsValid(); err != nil {
+		return nil, nil, nil, nil, model.NewErrBadRequest(err.Error())
+	}
+
+	card, err := a.getCardBlock(cardID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if card.BoardID == req.TargetBoardID {
+		return nil, nil, nil, nil, model.NewErrBadRequest(fmt.Sprintf("card %s is already in board %s", cardID, req.TargetBoardID))
+	}
+
+	source, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	target, err := a.store.GetBoard(req.TargetBoardID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if target.TeamID != source.TeamID {
+		return nil, nil, nil, nil, model.NewErrBadRequest(fmt.Sprintf("board %s is not in the team of card %s", target.ID, cardID))
+	}
+
+	mapping, err := model.NewCardMoveMapping(card, source, target, req.CreateMissingOptions)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return card, source, target, mapping, nil
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestMoveCardWithMapping(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	source := &model.Board{ID: "source-board", TeamID: "team-id"}
+	target := &model.Board{ID: "target-board", TeamID: "team-id"}
+	card := &model.Block{
+		ID:       "card-id",
+		BoardID:  source.ID,
+		ParentID: source.ID,
+		Type:     model.TypeCard,
+		Fields:   map[string]interface{}{"properties": map[string]interface{}{}},
+	}
+	image := &model.Block{
+		ID:       "image-id",
+		BoardID:  source.ID,
+		ParentID: card.ID,
+		Type:     model.TypeImage,
+		Fields:   map[string]interface{}{"fileId": "7fileid.png"},
+	}
+
+	t.Run("the card is not moved if its files can't be copied", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock(card.ID).Return(card, nil)
+		th.Store.EXPECT().GetBoard(source.ID).Return(source, nil).AnyTimes()
+		th.Store.EXPECT().GetBoard(target.ID).Return(target, nil).AnyTimes()
+		th.Store.EXPECT().GetBlocksWithParent(source.ID, card.ID).Return([]*model.Block{image}, nil)
+		th.Store.EXPECT().GetFileInfo("fileid").Return(nil, model.NewErrNotFound("file info"))
+		th.Store.EXPECT().SaveFileInfo(gomock.Any()).Return(errors.New("cannot save"))
+		th.Store.EXPECT().MoveCardBlocks(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := th.App.MoveCardWithMapping(card.ID, "user-id", &model.CardMoveRequest{TargetBoardID: target.ID}, true)
+		require.ErrorContains(t, err, "cannot copy the files of card card-id")
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// SetCardRecurrence attaches a recurrence rule to a template card,
+// replacing any previous one. The next occurrence is computed in the
+// timezone of the user creating the cards.
+func (a *App) SetCardRecurrence(recurrence *model.CardRecurrence) (*model.CardRecurrence, error) {
+	card, err := a.getCardBlock(recurrence.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if isTemplate, _ := card.Fields["isTemplate"].(bool); !isTemplate {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("card %s is not a template", card.ID))
+	}
+	recurrence.BoardID = card.BoardID
+
+	if err = recurrence.IsValid(); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	if recurrence.DatePropertyID != "" {
+		board, err2 := a.store.GetBoard(card.BoardID)
+		if err2 != nil {
+			return nil, err2
+		}
+		schema, err2 := model.ParsePropertySchema(board)
+		if err2 != nil {
+			return nil, err2
+		}
+		if propDef, ok := schema[recurrence.DatePropertyID]; !ok || propDef.Type != "date" {
+			return nil, model.NewErrBadRequest(fmt.Sprintf("property %s is not a date property", recurrence.DatePropertyID))
+		}
+	}
+
+	now := utils.GetMillis()
+	if recurrence.StartAt == 0 {
+		recurrence.StartAt = now
+	}
+
+	rule, _ := model.ParseRecurrenceRule(recurrence.Rule)
+	nextRunAt, ok := nextRecurrenceRun(rule, a.recurrenceStart(recurrence), now-1)
+	if !ok {
+		return nil, model.NewErrBadRequest("the recurrence rule has no next occurrence")
+	}
+	recurrence.NextRunAt = nextRunAt
+
+	if err = a.store.UpsertCardRecurrence(recurrence); err != nil {
+		return nil, err
+	}
+	return recurrence, nil
+}
+
+// GetCardRecurrence returns the recurrence of a template card.
+func (a *App) GetCardRecurrence(cardID string) (*model.CardRecurrence, error) {
+	return a.store.GetCardRecurrence(cardID)
+}
+
+// DeleteCardRecurrence stops creating copies of a template card.
+func (a *App) DeleteCardRecurrence(cardID string) error {
+	return a.store.DeleteCardRecurrence(cardID)
+}
+
+// CreateDueRecurringCards creates the cards of the recurrences with an
+// occurrence due at the given time. Each occurrence is claimed in the
+// store before the card is created so that it is created once, even
+// with several servers or after a restart, and released if the card
+// cannot be created. Occurrences missed while the
+// server was down result in a single card for the latest one. The
+// recurrences of users who can no longer modify the cards of the board
+// are deleted.
+func (a *App) CreateDueRecurringCards(now time.Time) {
+	nowMillis := utils.GetMillisForTime(now)
+	recurrences, err := a.store.GetDueCardRecurrences(nowMillis)
+	if err != nil {
+		a.logger.Error("CreateDueRecurringCards cannot get the due recurrences", mlog.Err(err))
+		return
+	}
+
+	for _, recurrence := range recurrences {
+		if err := a.createRecurringCard(recurrence, nowMillis); err != nil {
+			a.logger.Error("CreateDueRecurringCards cannot create the recurring card",
+				mlog.String("card_id", recurrence.CardID),
+				mlog.Err(err),
+			)
+		}
+	}
+}
+
+func (a *App) createRecurringCard(recurrence *model.CardRecurrence, now int64) error {
+	rule, err := model.ParseRecurrenceRule(recurrence.Rule)
+	if err != nil {
+		return err
+	}
+
+	// the latest due occurrence is the one the new card is created for
+	start := a.recurrenceStart(recurrence)
+	runAt := recurrence.NextRunAt
+	nextRunAt, ok := nextRecurrenceRun(rule, start, recurrence.NextRunAt)
+	for ok && nextRunAt <= now {
+		runAt = nextRunAt
+		nextRunAt, ok = nextRecurrenceRun(rule, start, nextRunAt)
+	}
+	if !ok {
+		a.logger.Info("Card recurrence has no more occurrences, deleting it", mlog.String("card_id", recurrence.CardID))
+		return a.store.DeleteCardRecurrence(recurrence.CardID)
+	}
+
+	// the cards are created as the user who set the recurrence, so it
+	// stops if that user can no longer modify the cards of the board
+	if !a.permissions.HasPermissionToBoard(recurrence.CreatedBy, recurrence.BoardID, model.PermissionManageBoardCards) {
+		a.logger.Info("Creator of card recurrence cannot modify the board cards, deleting it",
+			mlog.String("card_id", recurrence.CardID),
+			mlog.String("user_id", recurrence.CreatedBy),
+		)
+		return a.store.DeleteCardRecurrence(recurrence.CardID)
+	}
+
+	claimed, err := a.store.ClaimCardRecurrence(recurrence.CardID, recurrence.NextRunAt, runAt, nextRunAt)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	blocks, err := a.DuplicateBlock(recurrence.BoardID, recurrence.CardID, recurrence.CreatedBy, false)
+	if model.IsErrNotFound(err) {
+		a.logger.Info("Template card of recurrence not found, deleting it", mlog.String("card_id", recurrence.CardID))
+		return a.store.DeleteCardRecurrence(recurrence.CardID)
+	}
+	if err != nil {
+		// the occurrence is released, so that the next run creates it
+		if _, releaseErr := a.store.ClaimCardRecurrence(recurrence.CardID, nextRunAt, recurrence.LastRunAt, recurrence.NextRunAt); releaseErr != nil {
+			a.logger.Error("Cannot release the card recurrence occurrence",
+				mlog.String("card_id", recurrence.CardID),
+				mlog.Err(releaseErr),
+			)
+		}
+		return err
+	}
+
+	card := blocks[0]
+	a.logger.Debug("Recurring card created",
+		mlog.String("template_id", recurrence.CardID),
+		mlog.String("card_id", card.ID),
+		mlog.Int("run_at", runAt),
+	)
+
+	if recurrence.DatePropertyID == "" {
+		return nil
+	}
+
+	date, err := json.Marshal(map[string]int64{"from": runAt})
+	if err != nil {
+		return err
+	}
+	props := getCardProperties(card)
+	props[recurrence.DatePropertyID] = string(date)
+	patch := &model.BlockPatch{
+		UpdatedFields: map[string]interface{}{"properties": props},
+	}
+	_, err = a.PatchBlockAndNotify(card.ID, patch, recurrence.CreatedBy, true)
+	return err
+}
+
+// recurrenceStart returns the start of a recurrence in the timezone of
+// the user creating the cards, where its occurrences are computed.
+func (a *App) recurrenceStart(recurrence *model.CardRecurrence) time.Time {
+	loc := a.getUserLocation(recurrence.CreatedBy)
+	return utils.GetTimeForMillis(recurrence.StartAt).In(loc)
+}
+
+// nextRecurrenceRun returns the first occurrence of the rule after the
+// given time, in milliseconds.
+func nextRecurrenceRun(rule *model.RecurrenceRule, start time.Time, after int64) (int64, bool) {
+	next, ok := rule.Next(start, utils.GetTimeForMillis(after))
+	if !ok {
+		return 0, false
+	}
+	return utils.GetMillisForTime(next), true
+}
+
+// getUserLocation returns the location of the timezone of a user, or
+// UTC if it is not available.
+func (a *App) getUserLocation(userID string) *time.Location {
+	timezone, err := a.store.GetUserTimezone(userID)
+	if err != nil || timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		a.logger.Debug("Cannot load the user timezone", mlog.String("user_id", userID), mlog.String("timezone", timezone), mlog.Err(err))
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func TestCreateRecurringCard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := (24 * time.Hour).Milliseconds()
+	recurrence := &model.CardRecurrence{
+		CardID:    "template-id",
+		BoardID:   testBoardID,
+		Rule:      "FREQ=DAILY",
+		CreatedBy: "user-id",
+		StartAt:   utils.GetMillisForTime(start),
+		LastRunAt: utils.GetMillisForTime(start),
+		NextRunAt: utils.GetMillisForTime(start) + day,
+	}
+
+	board := &model.Board{ID: testBoardID, TeamID: "team-id"}
+
+	t.Run("the recurrence is deleted when its creator cannot modify the board", func(t *testing.T) {
+		th.Store.EXPECT().GetUserTimezone("user-id").Return("", nil)
+		th.PermissionsStore.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.API.EXPECT().HasPermissionToTeam("user-id", "team-id", model.PermissionViewTeam).Return(false)
+		th.Store.EXPECT().DeleteCardRecurrence("template-id").Return(nil)
+
+		err := th.App.createRecurringCard(recurrence, recurrence.NextRunAt)
+		require.NoError(t, err)
+	})
+
+	t.Run("the occurrence is released when the card cannot be created", func(t *testing.T) {
+		expectBoardEditor(th, "user-id", board)
+		th.Store.EXPECT().GetUserTimezone("user-id").Return("", nil)
+		th.Store.EXPECT().ClaimCardRecurrence("template-id", recurrence.NextRunAt, recurrence.NextRunAt, recurrence.NextRunAt+day).Return(true, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(nil, errors.New("database unavailable"))
+		th.Store.EXPECT().ClaimCardRecurrence("template-id", recurrence.NextRunAt+day, recurrence.LastRunAt, recurrence.NextRunAt).Return(true, nil)
+
+		err := th.App.createRecurringCard(recurrence, recurrence.NextRunAt)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// GetCardTemplateVariables returns the variables of a template card.
+func (a *App) GetCardTemplateVariables(templateID string) (*model.CardTemplateVariables, error) {
+	_, blocks, err := a.getCardTemplateBlocks(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CardTemplateVariables{
+		Variables: model.FindTemplateVariables(blocks),
+		BuiltIn:   model.BuiltInTemplateVariables(),
+	}, nil
+}
+
+// CreateCardFromTemplate creates a card and its content from a template
+// card and returns the created blocks, the card first. The variables of
+// the titles and of the default property values are replaced, and the
+// default values are applied relative to now in the timezone of the user.
+func (a *App) CreateCardFromTemplate(templateID, userID string, variables map[string]string, disableNotify bool) ([]*model.Block, error) {
+	template, blocks, err := a.getCardTemplateBlocks(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range model.FindTemplateVariables(blocks) {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) != 0 {
+		return nil, model.NewErrBadRequest("missing template variables: " + strings.Join(missing, ", "))
+	}
+
+	board, err := a.store.GetBoard(template.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(a.getUserLocation(userID))
+	values := a.templateVariableValues(board, userID, variables, now)
+
+	properties, err := a.templateDefaultProperties(board, template, values, now)
+	if err != nil {
+		return nil, err
+	}
+
+	nowMillis := utils.GetMillisForTime(now)
+	for _, block := range blocks {
+		if model.IsTemplateTextBlock(block) {
+			block.Title = model.ReplaceTemplateVariables(block.Title, values)
+		}
+		block.CreateAt = nowMillis
+		block.UpdateAt = nowMillis
+		block.ArchiveAt = 0
+	}
+
+	card := blocks[0]
+	card.Fields["isTemplate"] = false
+	delete(card.Fields, model.CardFieldTemplateDefaults)
+	cardProperties := getCardProperties(card)
+	for propertyID, value := range properties {
+		cardProperties[propertyID] = value
+	}
+	card.Fields["properties"] = cardProperties
+
+	blocks = model.GenerateBlockIDs(blocks, a.logger)
+	inserted, err := a.InsertBlocksAndNotify(blocks, userID, disableNotify)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.CopyAndUpdateCardFiles(board.ID, userID, inserted, false); err != nil {
+		return nil, err
+	}
+
+	a.logger.Debug("Card created from template",
+		mlog.String("template_id", templateID),
+		mlog.String("card_id", inserted[0].ID),
+	)
+	return inserted, nil
+}
+
+// getCardTemplateBlocks returns a template card and the blocks to copy
+// to create a card from it, the card first.
+func (a *App) getCardTemplateBlocks(templateID string) (*model.Block, []*model.Block, error) {
+	template, err := a.getCardBlock(templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isTemplate, _ := template.Fields["isTemplate"].(bool); !isTemplate {
+		return nil, nil, model.NewErrBadRequest(fmt.Sprintf("card %s is not a template", templateID))
+	}
+
+	subtree, err := a.store.GetSubTree2(template.BoardID, templateID, model.QuerySubtreeOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blocks := []*model.Block{template}
+	for _, block := range subtree {
+		if block.ID == templateID || block.Type == model.TypeComment {
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	return template, blocks, nil
+}
+
+// templateVariableValues returns the values of the variables of a
+// template, the built-in variables taking precedence.
+func (a *App) templateVariableValues(board *model.Board, userID string, variables map[string]string, now time.Time) map[string]string {
+	values := make(map[string]string, len(variables)+len(model.BuiltInTemplateVariables()))
+	for name, value := range variables {
+		values[name] = value
+	}
+
+	username := userID
+	if user, err := a.store.GetUserByID(userID); err == nil {
+		username = user.Username
+	}
+
+	values[model.TemplateVariableDate] = now.Format("2006-01-02")
+	values[model.TemplateVariableTime] = now.Format("15:04")
+	values[model.TemplateVariableDateTime] = now.Format("2006-01-02 15:04")
+	values[model.TemplateVariableUser] = username
+	values[model.TemplateVariableBoard] = board.Title
+	return values
+}
+
+// templateDefaultProperties returns the default property values of the
+// cards created from a template. The values of the date properties are
+// relative to now.
+func (a *App) templateDefaultProperties(board *model.Board, template *model.Block, values map[string]string, now time.Time) (map[string]interface{}, error) {
+	defaults := model.TemplateDefaults(template)
+	properties := make(map[string]interface{}, len(defaults))
+	if len(defaults) == 0 {
+		return properties, nil
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	for propertyID, value := range defaults {
+		propDef, ok := schema[propertyID]
+		if !ok {
+			a.logger.Debug("Ignoring the template default of a missing property",
+				mlog.String("template_id", template.ID),
+				mlog.String("property_id", propertyID),
+			)
+			continue
+		}
+
+		value = model.ReplaceTemplateVariables(value, values)
+		if propDef.Type != "date" {
+			properties[propertyID] = value
+			continue
+		}
+
+		t, err := model.ParseRelativeTime(value, now)
+		if err != nil {
+			return nil, model.NewErrBadRequest(fmt.Sprintf("invalid default value of property %s: %s", propDef.Name, err))
+		}
+		date, err := json.Marshal(map[string]int64{"from": utils.GetMillisForTime(t)})
+		if err != nil {
+			return nil, err
+		}
+		properties[propertyID] = string(date)
+	}
+	return properties, nil
+}
@@ -37,12 +37,15 @@ func (a *App) CreateCard(card *model.Card, boardID string, userID string, disabl
 	return newCard, nil
 }
 
-func (a *App) GetCardsForBoard(boardID string, page int, perPage int) ([]*model.Card, error) {
+// GetCardsForBoard returns a page of the cards of a board. The archived
+// cards are only returned if includeArchived is true.
+func (a *App) GetCardsForBoard(boardID string, page int, perPage int, includeArchived bool) ([]*model.Card, error) {
 	opts := model.QueryBlocksOptions{
-		BoardID:   boardID,
-		BlockType: model.TypeCard,
-		Page:      page,
-		PerPage:   perPage,
+		BoardID:         boardID,
+		BlockType:       model.TypeCard,
+		Page:            page,
+		PerPage:         perPage,
+		ExcludeArchived: !includeArchived,
 	}
 
 	blocks, err := a.store.GetBlocks(opts)
@@ -59,6 +62,10 @@ func (a *App) GetCardsForBoard(boardID string, page int, perPage int) ([]*model.
 			cards = append(cards, card)
 		}
 	}
+
+	if err := a.setCardsBlockedState(boardID, cards); err != nil {
+		return nil, err
+	}
 	return cards, nil
 }
 
@@ -92,5 +99,11 @@ func (a *App) GetCardByID(cardID string) (*model.Card, error) {
 		return nil, err
 	}
 
+	dependencies, err := a.GetCardDependencies(cardID)
+	if err != nil {
+		return nil, err
+	}
+	card.Blocked = dependencies.Blocked
+
 	return card, nil
 }
@@ -84,26 +84,64 @@ func TestGetCards(t *testing.T) {
 
 	t.Run("success scenario", func(t *testing.T) {
 		opts := model.QueryBlocksOptions{
-			BoardID:   board.ID,
-			BlockType: model.TypeCard,
+			BoardID:         board.ID,
+			BlockType:       model.TypeCard,
+			ExcludeArchived: true,
 		}
 
 		th.Store.EXPECT().GetBlocks(opts).Return(blocks, nil)
+		th.Store.EXPECT().GetCardDependenciesForBoard(board.ID).Return([]*model.CardDependency{}, nil)
 
-		cards, err := th.App.GetCardsForBoard(board.ID, 0, 0)
+		cards, err := th.App.GetCardsForBoard(board.ID, 0, 0, false)
 		require.NoError(t, err)
 		assert.Len(t, cards, cardCount)
 	})
 
+	t.Run("blocked cards", func(t *testing.T) {
+		opts := model.QueryBlocksOptions{
+			BoardID:         board.ID,
+			BlockType:       model.TypeCard,
+			ExcludeArchived: true,
+		}
+		dependencies := []*model.CardDependency{
+			{BlockerID: blocks[0].ID, BlockedID: blocks[1].ID},
+			{BlockerID: blocks[2].ID, BlockedID: blocks[3].ID},
+		}
+		board.Properties = map[string]interface{}{
+			model.BoardPropertyCompletedOptionIDs: []interface{}{"done-option"},
+		}
+		completedBlocker := &model.Block{
+			ID:      blocks[2].ID,
+			BoardID: board.ID,
+			Type:    model.TypeCard,
+			Fields: map[string]interface{}{
+				"properties": map[string]interface{}{"status": "done-option"},
+			},
+		}
+
+		th.Store.EXPECT().GetBlocks(opts).Return(blocks, nil)
+		th.Store.EXPECT().GetCardDependenciesForBoard(board.ID).Return(dependencies, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{blocks[0].ID, blocks[2].ID}).Return([]*model.Block{blocks[0], completedBlocker}, nil)
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+
+		cards, err := th.App.GetCardsForBoard(board.ID, 0, 0, false)
+		require.NoError(t, err)
+		require.Len(t, cards, cardCount)
+		require.False(t, cards[0].Blocked)
+		require.True(t, cards[1].Blocked)
+		require.False(t, cards[3].Blocked)
+	})
+
 	t.Run("error scenario", func(t *testing.T) {
 		opts := model.QueryBlocksOptions{
-			BoardID:   board.ID,
-			BlockType: model.TypeCard,
+			BoardID:         board.ID,
+			BlockType:       model.TypeCard,
+			ExcludeArchived: true,
 		}
 
 		th.Store.EXPECT().GetBlocks(opts).Return(nil, blockError{"error"})
 
-		cards, err := th.App.GetCardsForBoard(board.ID, 0, 0)
+		cards, err := th.App.GetCardsForBoard(board.ID, 0, 0, false)
 		require.Error(t, err)
 		require.Nil(t, cards)
 	})
@@ -199,6 +237,7 @@ func TestGetCard(t *testing.T) {
 
 	t.Run("success scenario", func(t *testing.T) {
 		th.Store.EXPECT().GetBlock(block.ID).Return(block, nil)
+		th.Store.EXPECT().GetCardDependencies(block.ID).Return([]*model.CardDependency{}, nil)
 
 		card, err := th.App.GetCardByID(block.ID)
 
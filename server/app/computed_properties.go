@@ -0,0 +1,137 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// updateComputedProperties evaluates the formula and rollup properties
+// of a card and stores their values if they changed. The card is
+// updated in place so callers see the stored values. It returns true
+// if the card was changed.
+func (a *App) updateComputedProperties(board *model.Board, card *model.Block, modifiedBy string) bool {
+	if card == nil || card.Type != model.TypeCard {
+		return false
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Error("updateComputedProperties cannot parse the board schema", mlog.String("board_id", board.ID), mlog.Err(err))
+		return false
+	}
+	if !schema.HasComputedProperties() {
+		return false
+	}
+
+	children := []*model.Block{}
+	if schema.HasRollupProperties() {
+		if children, err = a.store.GetBlocksWithParent(card.BoardID, card.ID); err != nil {
+			a.logger.Error("updateComputedProperties cannot get the card content", mlog.String("card_id", card.ID), mlog.Err(err))
+			return false
+		}
+	}
+
+	values, errs := model.ComputeCardProperties(schema, card, children)
+	for propertyID, err := range errs {
+		a.logger.Debug("updateComputedProperties cannot compute property",
+			mlog.String("card_id", card.ID),
+			mlog.String("property_id", propertyID),
+			mlog.Err(err),
+		)
+	}
+
+	props := getCardProperties(card)
+	changed := false
+	for propertyID, propDef := range schema {
+		if !propDef.IsComputed() {
+			continue
+		}
+		value, ok := values[propertyID]
+		current, exists := props[propertyID]
+		switch {
+		case ok && (!exists || current != value):
+			props[propertyID] = value
+			changed = true
+		case !ok && exists:
+			delete(props, propertyID)
+			changed = true
+		}
+	}
+	if !changed {
+		return false
+	}
+
+	patch := &model.BlockPatch{
+		UpdatedFields: map[string]interface{}{"properties": props},
+	}
+	if err := a.store.PatchBlock(card.ID, patch, modifiedBy); err != nil {
+		a.logger.Error("updateComputedProperties cannot patch the card", mlog.String("card_id", card.ID), mlog.Err(err))
+		return false
+	}
+
+	updated, err := a.store.GetBlock(card.ID)
+	if err != nil {
+		a.logger.Error("updateComputedProperties cannot get the patched card", mlog.String("card_id", card.ID), mlog.Err(err))
+		return false
+	}
+	*card = *updated
+	return true
+}
+
+// updateParentRollups updates the computed properties of the card that
+// contains a content block, as its rollups depend on the card content.
+func (a *App) updateParentRollups(board *model.Board, block *model.Block, modifiedBy string) {
+	if block.Type == model.TypeCard || block.ParentID == "" || block.ParentID == block.BoardID {
+		return
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil || !schema.HasRollupProperties() {
+		return
+	}
+
+	card, err := a.store.GetBlock(block.ParentID)
+	if model.IsErrNotFound(err) {
+		return
+	}
+	if err != nil {
+		a.logger.Error("updateParentRollups cannot get the parent card", mlog.String("card_id", block.ParentID), mlog.Err(err))
+		return
+	}
+
+	if a.updateComputedProperties(board, card, modifiedBy) {
+		a.blockChangeNotifier.Enqueue(func() error {
+			a.wsAdapter.BroadcastBlockChange(board.TeamID, card)
+			return nil
+		})
+	}
+}
+
+// updateBoardComputedProperties recomputes the computed properties of
+// all the cards of a board, typically after its schema changes.
+func (a *App) updateBoardComputedProperties(board *model.Board, modifiedBy string) {
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil || !schema.HasComputedProperties() {
+		return
+	}
+
+	cards, err := a.store.GetBlocksWithType(board.ID, model.TypeCard)
+	if err != nil {
+		a.logger.Error("updateBoardComputedProperties cannot get the board cards", mlog.String("board_id", board.ID), mlog.Err(err))
+		return
+	}
+
+	for _, card := range cards {
+		if a.updateComputedProperties(board, card, modifiedBy) {
+			updated := card
+			a.blockChangeNotifier.Enqueue(func() error {
+				a.wsAdapter.BroadcastBlockChange(board.TeamID, updated)
+				return nil
+			})
+		}
+	}
+}
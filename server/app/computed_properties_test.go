@@ -0,0 +1,113 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func computedTestBoard() *model.Board {
+	return &model.Board{
+		ID:     testBoardID,
+		TeamID: "team-id",
+		CardProperties: []map[string]interface{}{
+			{"id": "points", "name": "Points", "type": "number"},
+			{"id": "double", "name": "Double", "type": model.PropTypeFormula, "formula": `prop("Points") * 2`},
+			{"id": "done", "name": "Done", "type": model.PropTypeRollup, "rollup": model.RollupCheckboxCompletion},
+		},
+	}
+}
+
+func TestUpdateComputedProperties(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := computedTestBoard()
+	th.Store.EXPECT().GetMembersForBoard(gomock.Any()).AnyTimes()
+
+	t.Run("stores the computed values", func(t *testing.T) {
+		card := relationTestCard("card1", testBoardID, map[string]interface{}{"points": "21"})
+		children := []*model.Block{
+			{ID: "check1", ParentID: "card1", Type: model.TypeCheckbox, Fields: map[string]interface{}{"value": true}},
+			{ID: "check2", ParentID: "card1", Type: model.TypeCheckbox, Fields: map[string]interface{}{"value": false}},
+		}
+		updated := relationTestCard("card1", testBoardID, map[string]interface{}{
+			"points": "21",
+			"double": "42",
+			"done":   "50",
+		})
+
+		th.Store.EXPECT().GetBlocksWithParent(testBoardID, "card1").Return(children, nil)
+		th.Store.EXPECT().PatchBlock("card1", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"points": "21", "double": "42", "done": "50"},
+			},
+		}, "user-id-1").Return(nil)
+		th.Store.EXPECT().GetBlock("card1").Return(updated, nil)
+
+		require.True(t, th.App.updateComputedProperties(board, card, "user-id-1"))
+		require.Equal(t, updated, card)
+	})
+
+	t.Run("does not patch the card if the values didn't change", func(t *testing.T) {
+		card := relationTestCard("card1", testBoardID, map[string]interface{}{
+			"points": "21",
+			"double": "42",
+		})
+
+		th.Store.EXPECT().GetBlocksWithParent(testBoardID, "card1").Return([]*model.Block{}, nil)
+
+		require.False(t, th.App.updateComputedProperties(board, card, "user-id-1"))
+	})
+
+	t.Run("overwrites values set by clients", func(t *testing.T) {
+		card := relationTestCard("card1", testBoardID, map[string]interface{}{
+			"double": "1000",
+			"done":   "100",
+		})
+
+		th.Store.EXPECT().GetBlocksWithParent(testBoardID, "card1").Return([]*model.Block{}, nil)
+		th.Store.EXPECT().PatchBlock("card1", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"double": "0"},
+			},
+		}, "user-id-1").Return(nil)
+		th.Store.EXPECT().GetBlock("card1").Return(card, nil)
+
+		require.True(t, th.App.updateComputedProperties(board, card, "user-id-1"))
+	})
+}
+
+func TestUpdateParentRollups(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := computedTestBoard()
+	th.Store.EXPECT().GetMembersForBoard(gomock.Any()).AnyTimes()
+
+	t.Run("updates the rollups of the parent card", func(t *testing.T) {
+		checkbox := &model.Block{ID: "check1", ParentID: "card1", BoardID: testBoardID, Type: model.TypeCheckbox, Fields: map[string]interface{}{"value": true}}
+		card := relationTestCard("card1", testBoardID, map[string]interface{}{"double": "0"})
+		updated := relationTestCard("card1", testBoardID, map[string]interface{}{"double": "0", "done": "100"})
+
+		th.Store.EXPECT().GetBlock("card1").Return(card, nil)
+		th.Store.EXPECT().GetBlocksWithParent(testBoardID, "card1").Return([]*model.Block{checkbox}, nil)
+		th.Store.EXPECT().PatchBlock("card1", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"double": "0", "done": "100"},
+			},
+		}, "user-id-1").Return(nil)
+		th.Store.EXPECT().GetBlock("card1").Return(updated, nil)
+
+		th.App.updateParentRollups(board, checkbox, "user-id-1")
+	})
+
+	t.Run("ignores blocks without a parent card", func(t *testing.T) {
+		view := &model.Block{ID: "view1", ParentID: testBoardID, BoardID: testBoardID, Type: model.TypeView}
+
+		th.App.updateParentRollups(board, view, "user-id-1")
+	})
+}
@@ -0,0 +1,481 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const csvImportOptionColor = "propColorDefault"
+
+// csvColumn is a column of an imported CSV file mapped to a property, or
+// to the card title.
+type csvColumn struct {
+	index  int
+	name   string
+	propID string
+}
+
+// csvImportRow is a valid row of an imported CSV file.
+type csvImportRow struct {
+	card       *model.Block
+	title      string
+	hasTitle   bool
+	properties map[string]interface{}
+}
+
+// csvUserFinder finds the users of a team by username, email or id.
+type csvUserFinder map[string]*model.User
+
+func (f csvUserFinder) FindUser(name string) (*model.User, error) {
+	return f[strings.ToLower(name)], nil
+}
+
+// ImportCSV imports the rows of a CSV file as cards of a board. The
+// values are parsed with the definitions of the properties mapped to the
+// columns, and the rows with errors are reported. Nothing is written for
+// dry runs, or if any row has an error and the invalid rows are not
+// skipped. Otherwise the created options and cards and the updated cards
+// are written in a single transaction, so either all the valid rows are
+// imported or none.
+func (a *App) ImportCSV(boardID, userID string, r io.Reader, opts *model.CSVImportOptions, disableNotify bool) (*model.CSVImportResult, error) {
+	if err := opts.IsValid(); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	if opts.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(opts.Delimiter)
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, model.NewErrBadRequest("the CSV file is empty")
+	}
+	if err != nil {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("cannot read the CSV header: %s", err))
+	}
+
+	result := &model.CSVImportResult{
+		DryRun:  opts.DryRun,
+		Columns: map[string]string{},
+		Errors:  []model.CSVImportRowError{},
+	}
+	columns, err := csvImportColumns(header, schema, opts, result)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyColumn *csvColumn
+	if opts.KeyColumn != "" {
+		for i := range columns {
+			if columns[i].name == opts.KeyColumn {
+				keyColumn = &columns[i]
+			}
+		}
+		if keyColumn == nil {
+			return nil, model.NewErrBadRequest(fmt.Sprintf("key column %s is not imported", opts.KeyColumn))
+		}
+	}
+
+	users, err := a.csvImportUsers(board.TeamID, columns, schema)
+	if err != nil {
+		return nil, err
+	}
+	cardsByKey, err := a.csvImportCardsByKey(board.ID, keyColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	created := map[string][]model.PropDefOption{}
+	keyRows := map[string]int{}
+	rows := []csvImportRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Rows++
+			result.Skipped++
+			result.Errors = append(result.Errors, model.CSVImportRowError{Row: parseErr.StartLine, Reason: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isEmptyCSVRecord(record) {
+			continue
+		}
+
+		result.Rows++
+		if result.Rows > model.CSVImportMaxRows {
+			return nil, fmt.Errorf("more than %d rows: %w", model.CSVImportMaxRows, model.ErrRequestEntityTooLarge)
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := csvImportRow{properties: map[string]interface{}{}}
+		rowErrors := []model.CSVImportRowError{}
+		var key interface{}
+		for _, column := range columns {
+			isKey := keyColumn != nil && column.index == keyColumn.index
+			cell := ""
+			if column.index < len(record) {
+				cell = unescapeCSVFormula(record[column.index])
+			}
+
+			if column.propID == model.CSVImportColumnTitle {
+				row.title, row.hasTitle = strings.TrimSpace(cell), true
+				if isKey && row.title != "" {
+					key = row.title
+				}
+				continue
+			}
+
+			propDef := schema[column.propID]
+			if opts.CreateMissingOptions {
+				for _, value := range propDef.MissingOptionValues(cell) {
+					if _, ok := propDef.FindOption(value); ok {
+						continue
+					}
+					option := model.PropDefOption{
+						ID:    utils.NewID(utils.IDTypeNone),
+						Index: len(propDef.Options),
+						Value: value,
+						Color: csvImportOptionColor,
+					}
+					propDef.Options[option.ID] = option
+					created[propDef.ID] = append(created[propDef.ID], option)
+				}
+			}
+
+			value, err := propDef.ParseValue(cell, users)
+			if err != nil {
+				rowErrors = append(rowErrors, model.CSVImportRowError{Row: line, Column: column.name, Value: cell, Reason: err.Error()})
+				continue
+			}
+			if value == nil {
+				continue
+			}
+			row.properties[column.propID] = value
+			if isKey {
+				key = value
+			}
+		}
+
+		if keyColumn != nil && len(rowErrors) == 0 {
+			rowErrors = append(rowErrors, row.matchKey(line, keyColumn.name, key, keyRows, cardsByKey)...)
+		}
+		if len(rowErrors) != 0 {
+			result.Skipped++
+			result.Errors = append(result.Errors, rowErrors...)
+			continue
+		}
+
+		if row.card != nil {
+			result.Updated++
+		} else {
+			result.Created++
+		}
+		rows = append(rows, row)
+	}
+
+	if len(created) != 0 {
+		result.CreatedOptions = map[string][]string{}
+		for propID, options := range created {
+			for _, option := range options {
+				result.CreatedOptions[propID] = append(result.CreatedOptions[propID], option.Value)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if len(result.Errors) != 0 && !opts.SkipInvalidRows {
+		result.Created, result.Updated, result.Skipped = 0, 0, result.Rows
+		result.CreatedOptions = nil
+		return result, nil
+	}
+
+	if err = a.writeCSVImportRows(board, userID, rows, created, disableNotify); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// csvImportColumns maps the columns of a CSV header to the properties of
+// a board, and records the mapping in the result.
+func csvImportColumns(header []string, schema model.PropSchema, opts *model.CSVImportOptions, result *model.CSVImportResult) ([]csvColumn, error) {
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	found := map[string]bool{}
+	mapped := map[string]string{}
+	columns := []csvColumn{}
+	for i, name := range header {
+		name = strings.TrimSpace(unescapeCSVFormula(name))
+		found[name] = true
+
+		propID, ok := opts.Columns[name]
+		if !ok {
+			propID = csvImportPropertyID(name, schema)
+		}
+		if propID == "" {
+			result.IgnoredColumns = append(result.IgnoredColumns, name)
+			continue
+		}
+
+		if propID != model.CSVImportColumnTitle {
+			propDef, ok := schema[propID]
+			if !ok {
+				return nil, model.NewErrBadRequest(fmt.Sprintf("unknown property %s for column %s", propID, name))
+			}
+			if !propDef.IsParseable() {
+				return nil, model.NewErrBadRequest(fmt.Sprintf("property %s of column %s can't be imported", propDef.Name, name))
+			}
+		}
+		if other, ok := mapped[propID]; ok {
+			return nil, model.NewErrBadRequest(fmt.Sprintf("columns %s and %s are mapped to the same property", other, name))
+		}
+		mapped[propID] = name
+
+		columns = append(columns, csvColumn{index: i, name: name, propID: propID})
+		result.Columns[name] = propID
+	}
+
+	for name := range opts.Columns {
+		if !found[name] {
+			return nil, model.NewErrBadRequest(fmt.Sprintf("column %s not found", name))
+		}
+	}
+	return columns, nil
+}
+
+// csvImportPropertyID returns the id of the first importable property
+// named like a column, "title" for the title column, or an empty string.
+func csvImportPropertyID(name string, schema model.PropSchema) string {
+	var found *model.PropDef
+	for _, propDef := range schema {
+		propDef := propDef
+		if !propDef.IsParseable() || !strings.EqualFold(strings.TrimSpace(propDef.Name), name) {
+			continue
+		}
+		if found == nil || propDef.Index < found.Index {
+			found = &propDef
+		}
+	}
+	if found != nil {
+		return found.ID
+	}
+	if strings.EqualFold(name, model.CSVImportColumnTitle) {
+		return model.CSVImportColumnTitle
+	}
+	return ""
+}
+
+// csvImportUsers returns the users of the team if a person property is
+// imported.
+func (a *App) csvImportUsers(teamID string, columns []csvColumn, schema model.PropSchema) (csvUserFinder, error) {
+	hasPersonColumn := false
+	for _, column := range columns {
+		if propType := schema[column.propID].Type; propType == "person" || propType == "multiPerson" {
+			hasPersonColumn = true
+		}
+	}
+	if !hasPersonColumn {
+		return nil, nil
+	}
+
+	users, err := a.store.GetUsersByTeam(teamID, "", true, false)
+	if err != nil {
+		return nil, err
+	}
+	finder := csvUserFinder{}
+	for _, user := range users {
+		finder[strings.ToLower(user.ID)] = user
+		finder[strings.ToLower(user.Username)] = user
+		if user.Email != "" {
+			finder[strings.ToLower(user.Email)] = user
+		}
+	}
+	return finder, nil
+}
+
+// csvImportCardsByKey returns the cards of a board by the value of the
+// key column property.
+func (a *App) csvImportCardsByKey(boardID string, keyColumn *csvColumn) (map[string][]*model.Block, error) {
+	if keyColumn == nil {
+		return nil, nil
+	}
+
+	cards, err := a.store.GetBlocks(model.QueryBlocksOptions{BoardID: boardID, BlockType: model.TypeCard})
+	if err != nil {
+		return nil, err
+	}
+
+	cardsByKey := map[string][]*model.Block{}
+	for _, card := range cards {
+		var value interface{}
+		if keyColumn.propID == model.CSVImportColumnTitle {
+			value = card.Title
+		} else if props, ok := card.Fields["properties"].(map[string]interface{}); ok {
+			value = props[keyColumn.propID]
+		}
+		if key := csvImportKey(value); key != "" {
+			cardsByKey[key] = append(cardsByKey[key], card)
+		}
+	}
+	return cardsByKey, nil
+}
+
+// matchKey finds the card updated by a row, and reports the rows without
+// key and the keys used by several rows or cards.
+func (row *csvImportRow) matchKey(line int, column string, value interface{}, keyRows map[string]int, cardsByKey map[string][]*model.Block) []model.CSVImportRowError {
+	key := csvImportKey(value)
+	if key == "" {
+		return []model.CSVImportRowError{{Row: line, Column: column, Reason: "missing key value"}}
+	}
+	if firstLine, ok := keyRows[key]; ok {
+		return []model.CSVImportRowError{{
+			Row:    line,
+			Column: column,
+			Value:  fmt.Sprint(value),
+			Reason: fmt.Sprintf("duplicate key value, already used in row %d", firstLine),
+		}}
+	}
+	keyRows[key] = line
+
+	switch cards := cardsByKey[key]; len(cards) {
+	case 0:
+	case 1:
+		row.card = cards[0]
+	default:
+		return []model.CSVImportRowError{{
+			Row:    line,
+			Column: column,
+			Value:  fmt.Sprint(value),
+			Reason: fmt.Sprintf("the key value matches %d cards", len(cards)),
+		}}
+	}
+	return nil
+}
+
+// writeCSVImportRows adds the created options to the board, creates the
+// cards of the new rows and updates the cards matched by the other ones,
+// in a single store transaction.
+func (a *App) writeCSVImportRows(board *model.Board, userID string, rows []csvImportRow, created map[string][]model.PropDefOption, disableNotify bool) error {
+	imp := &model.CSVImportBlocks{
+		BoardID:      board.ID,
+		Blocks:       []*model.Block{},
+		BlockPatches: &model.BlockPatchBatch{},
+	}
+
+	// the rows are validated against the board with the created options
+	patchedBoard := board
+	if len(created) != 0 {
+		imp.BoardPatch = propertyOptionsPatch(board, created)
+		patched := *board
+		patchedBoard = imp.BoardPatch.Patch(&patched)
+	}
+
+	now := utils.GetMillis()
+	oldBlocks := []*model.Block{}
+	for _, row := range rows {
+		if row.card == nil {
+			imp.Blocks = append(imp.Blocks, model.Card2Block(&model.Card{
+				ID:           utils.NewID(utils.IDTypeCard),
+				BoardID:      board.ID,
+				CreatedBy:    userID,
+				ModifiedBy:   userID,
+				Title:        row.title,
+				ContentOrder: []string{},
+				Properties:   row.properties,
+				CreateAt:     now,
+				UpdateAt:     now,
+			}))
+			continue
+		}
+
+		// the properties are replaced as a whole by the patches
+		properties := getCardProperties(row.card)
+		for propID, value := range row.properties {
+			properties[propID] = value
+		}
+		patch := model.BlockPatch{UpdatedFields: map[string]interface{}{"properties": properties}}
+		if row.hasTitle && row.title != "" {
+			title := row.title
+			patch.Title = &title
+		}
+		if err := a.validateCardPatch(patchedBoard, row.card, &patch); err != nil {
+			return err
+		}
+		oldBlocks = append(oldBlocks, row.card)
+		imp.BlockPatches.BlockIDs = append(imp.BlockPatches.BlockIDs, row.card.ID)
+		imp.BlockPatches.BlockPatches = append(imp.BlockPatches.BlockPatches, patch)
+	}
+
+	if err := a.validateCardBlocks(map[string]*model.Board{board.ID: patchedBoard}, imp.Blocks); err != nil {
+		return err
+	}
+
+	updatedBoard, err := a.store.ImportCSVBlocks(imp, userID)
+	if err != nil {
+		return err
+	}
+
+	if updatedBoard != nil {
+		board = updatedBoard
+		a.updateBoardComputedProperties(updatedBoard, userID)
+		a.blockChangeNotifier.Enqueue(func() error {
+			a.wsAdapter.BroadcastBoardChange(updatedBoard.TeamID, updatedBoard)
+			return nil
+		})
+	}
+	if len(imp.Blocks) != 0 {
+		a.notifyBlocksInserted(board, imp.Blocks, userID, disableNotify)
+	}
+	if len(oldBlocks) != 0 {
+		a.notifyBlocksPatched(board.TeamID, oldBlocks, imp.BlockPatches.BlockIDs, userID, disableNotify)
+	}
+	return nil
+}
+
+// csvImportKey returns the key of a title or property value, ignoring
+// case and surrounding spaces.
+func csvImportKey(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(fmt.Sprint(value)))
+}
+
+func isEmptyCSVRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
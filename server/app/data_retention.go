@@ -0,0 +1,138 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const dataRetentionBatchSize = 1000
+
+func (a *App) GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, error) {
+	return a.store.GetRetentionPolicies(teamID)
+}
+
+// UpsertRetentionPolicy creates or replaces the retention policy of a team
+// or of one of its boards.
+func (a *App) UpsertRetentionPolicy(policy *model.RetentionPolicy) (*model.RetentionPolicy, error) {
+	if err := policy.IsValid(); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	if policy.BoardID != "" {
+		board, err := a.store.GetBoard(policy.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		if board.TeamID != policy.TeamID {
+			return nil, model.NewErrBadRequest("board ID=" + policy.BoardID + " is not part of team ID=" + policy.TeamID)
+		}
+	}
+
+	if err := a.store.UpsertRetentionPolicy(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (a *App) DeleteRetentionPolicy(teamID, boardID string) error {
+	return a.store.DeleteRetentionPolicy(teamID, boardID)
+}
+
+// globalRetentionDate returns the date before which the last change of a
+// board without a retention policy makes it expire, or zero if the global
+// data retention is disabled.
+func (a *App) globalRetentionDate(now time.Time) int64 {
+	if !a.config.EnableDataRetention || a.config.DataRetentionDays <= 0 {
+		return 0
+	}
+	return utils.GetMillisForTime(now.AddDate(0, 0, -a.config.DataRetentionDays))
+}
+
+// PreviewDataRetention returns the boards that a data retention run would
+// delete at the given time, optionally limited to a team.
+func (a *App) PreviewDataRetention(now time.Time, teamID string) ([]*model.RetentionCandidate, error) {
+	return a.previewDataRetention(now, a.globalRetentionDate(now), teamID)
+}
+
+func (a *App) previewDataRetention(now time.Time, globalRetentionDate int64, teamID string) ([]*model.RetentionCandidate, error) {
+	candidates, err := a.store.GetDataRetentionCandidates(globalRetentionDate, utils.GetMillisForTime(now))
+	if err != nil {
+		return nil, err
+	}
+	if teamID == "" {
+		return candidates, nil
+	}
+
+	teamCandidates := []*model.RetentionCandidate{}
+	for _, candidate := range candidates {
+		if candidate.TeamID == teamID {
+			teamCandidates = append(teamCandidates, candidate)
+		}
+	}
+	return teamCandidates, nil
+}
+
+// RunDataRetention deletes the boards that expired at the given time,
+// according to the global retention and the retention policies, and the
+// expired audit records. It records an audit entry for each deleted board
+// and returns them.
+func (a *App) RunDataRetention(now time.Time) ([]*model.RetentionCandidate, error) {
+	return a.runDataRetention(now, a.globalRetentionDate(now))
+}
+
+// RunDataRetentionPolicies is RunDataRetention without the global
+// retention, for when it is run by the Mattermost server jobs.
+func (a *App) RunDataRetentionPolicies(now time.Time) ([]*model.RetentionCandidate, error) {
+	return a.runDataRetention(now, 0)
+}
+
+func (a *App) runDataRetention(now time.Time, globalRetentionDate int64) ([]*model.RetentionCandidate, error) {
+	if err := a.purgeExpiredAuditRecords(now); err != nil {
+		return nil, err
+	}
+
+	candidates, err := a.previewDataRetention(now, globalRetentionDate, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	boardIDs := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		boardIDs = append(boardIDs, candidate.BoardID)
+	}
+	if _, err := a.store.DeleteDataRetentionBoards(boardIDs, dataRetentionBatchSize); err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		a.auditDataRetentionPurge(candidate)
+	}
+	return candidates, nil
+}
+
+func (a *App) auditDataRetentionPurge(candidate *model.RetentionCandidate) {
+	if a.audit == nil {
+		return
+	}
+
+	rec := &audit.Record{
+		Event:  "dataRetentionPurge",
+		Status: audit.Success,
+		UserID: model.SystemUserID,
+		Meta:   []audit.Meta{{K: audit.KeyTeamID, V: candidate.TeamID}},
+	}
+	rec.AddMeta("boardID", candidate.BoardID)
+	rec.AddMeta("scope", candidate.Scope)
+	rec.AddMeta("retentionDate", candidate.RetentionDate)
+	rec.AddMeta("lastUpdateAt", candidate.LastUpdateAt)
+	a.audit.LogRecord(audit.LevelModify, rec)
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func TestPreviewDataRetention(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	nowMillis := utils.GetMillisForTime(now)
+	candidates := []*model.RetentionCandidate{
+		{BoardID: "board-1", TeamID: "team-1"},
+		{BoardID: "board-2", TeamID: "team-2"},
+	}
+
+	t.Run("without the global data retention", func(t *testing.T) {
+		th.App.config.EnableDataRetention = false
+		th.Store.EXPECT().GetDataRetentionCandidates(int64(0), nowMillis).Return(candidates, nil)
+
+		result, err := th.App.PreviewDataRetention(now, "")
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+	})
+
+	t.Run("with the global data retention, for a team", func(t *testing.T) {
+		th.App.config.EnableDataRetention = true
+		th.App.config.DataRetentionDays = 30
+		globalRetentionDate := utils.GetMillisForTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+		th.Store.EXPECT().GetDataRetentionCandidates(globalRetentionDate, nowMillis).Return(candidates, nil)
+
+		result, err := th.App.PreviewDataRetention(now, "team-2")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, "board-2", result[0].BoardID)
+	})
+}
+
+func TestUpsertRetentionPolicy(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("the board must be part of the team", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(testBoardID).Return(&model.Board{ID: testBoardID, TeamID: "other-team"}, nil)
+		th.Store.EXPECT().UpsertRetentionPolicy(gomock.Any()).Times(0)
+
+		_, err := th.App.UpsertRetentionPolicy(&model.RetentionPolicy{TeamID: "team-id", BoardID: testBoardID, RetentionDays: 1})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("negative retention days are rejected", func(t *testing.T) {
+		_, err := th.App.UpsertRetentionPolicy(&model.RetentionPolicy{TeamID: "team-id", RetentionDays: -1})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestRunDataRetentionPolicies(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	nowMillis := utils.GetMillisForTime(now)
+
+	// the global retention is left to the Mattermost server jobs
+	th.App.config.EnableDataRetention = true
+	th.App.config.DataRetentionDays = 30
+	th.App.config.AuditDBRetentionDays = 30
+	auditBefore := utils.GetMillisForTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	th.Store.EXPECT().DeleteAuditRecords(auditBefore).Return(int64(0), nil)
+	th.Store.EXPECT().GetDataRetentionCandidates(int64(0), nowMillis).
+		Return([]*model.RetentionCandidate{{BoardID: "board-1", TeamID: "team-1", Scope: model.RetentionScopeBoard}}, nil)
+	th.Store.EXPECT().DeleteDataRetentionBoards([]string{"board-1"}, gomock.Any()).Return(int64(3), nil)
+
+	candidates, err := th.App.RunDataRetentionPolicies(now)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// dueDateReminderWindow is how long after its time a reminder can still
+// be sent, so dates far in the past don't trigger reminders.
+const dueDateReminderWindow = 24 * time.Hour
+
+// SendDueDateReminders notifies the assignees and subscribers of the
+// cards with an upcoming or overdue date. Each reminder is recorded
+// before it is sent so it is sent once, even with several servers.
+func (a *App) SendDueDateReminders(now time.Time) {
+	if a.notifications == nil {
+		return
+	}
+
+	nowMillis := utils.GetMillisForTime(now)
+	if _, err := a.store.DeleteDueDateRemindersBefore(nowMillis - 2*dueDateReminderWindow.Milliseconds()); err != nil {
+		a.logger.Error("SendDueDateReminders cannot delete the old reminders", mlog.Err(err))
+	}
+
+	boards, err := a.store.GetBoardsWithPropertyType("date")
+	if err != nil {
+		a.logger.Error("SendDueDateReminders cannot get the boards with dates", mlog.Err(err))
+		return
+	}
+
+	for _, board := range boards {
+		if err := a.sendBoardDueDateReminders(board, nowMillis); err != nil {
+			a.logger.Error("SendDueDateReminders cannot send the board reminders", mlog.String("board_id", board.ID), mlog.Err(err))
+		}
+	}
+}
+
+func (a *App) sendBoardDueDateReminders(board *model.Board, now int64) error {
+	leadTimes, ok := board.GetReminderLeadTimes()
+	if !ok {
+		leadTimes = a.config.DueDateReminderLeadTimes
+	}
+	if len(leadTimes) == 0 {
+		return nil
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+
+	dateProps := []model.PropDef{}
+	personPropIDs := []string{}
+	for _, propDef := range schema {
+		switch propDef.Type {
+		case "date":
+			dateProps = append(dateProps, propDef)
+		case "person", "multiPerson":
+			personPropIDs = append(personPropIDs, propDef.ID)
+		}
+	}
+	sort.Slice(dateProps, func(i, j int) bool { return dateProps[i].Index < dateProps[j].Index })
+
+	// only the cards with a date that can have a due reminder are loaded
+	minLeadTime, maxLeadTime := leadTimes[0], leadTimes[0]
+	for _, leadTime := range leadTimes {
+		minLeadTime = min(minLeadTime, leadTime)
+		maxLeadTime = max(maxLeadTime, leadTime)
+	}
+	from := now - dueDateReminderWindow.Milliseconds() + (time.Duration(minLeadTime) * time.Minute).Milliseconds()
+	to := now + (time.Duration(maxLeadTime) * time.Minute).Milliseconds()
+
+	datePropIDs := make([]string, 0, len(dateProps))
+	for _, propDef := range dateProps {
+		datePropIDs = append(datePropIDs, propDef.ID)
+	}
+	cards, err := a.store.GetCardsWithDatesBetween(board.ID, datePropIDs, from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, card := range cards {
+		if isTemplate, _ := card.Fields["isTemplate"].(bool); isTemplate {
+			continue
+		}
+		if model.IsCardCompleted(board, card) {
+			continue
+		}
+
+		props := getCardProperties(card)
+		for _, propDef := range dateProps {
+			dueAt, ok := model.GetDueDate(props[propDef.ID])
+			if !ok {
+				continue
+			}
+
+			dueLeads := dueLeadTimes(leadTimes, dueAt, now)
+			if len(dueLeads) == 0 {
+				continue
+			}
+
+			userIDs, err := a.getReminderUserIDs(board, card, props, personPropIDs)
+			if err != nil {
+				return err
+			}
+			if len(userIDs) == 0 {
+				continue
+			}
+
+			sent, err := a.recordDueDateReminders(card.ID, propDef.ID, dueAt, dueLeads, now)
+			if err != nil {
+				return err
+			}
+			if !sent {
+				continue
+			}
+
+			a.notifications.DueDateReminder(notify.DueDateReminderEvent{
+				TeamID:       board.TeamID,
+				Board:        board,
+				Card:         card,
+				PropertyID:   propDef.ID,
+				PropertyName: propDef.Name,
+				DueAt:        dueAt,
+				Overdue:      now >= dueAt,
+				UserIDs:      userIDs,
+			})
+		}
+	}
+	return nil
+}
+
+// dueLeadTimes returns the lead times whose reminder is due at the given
+// time.
+func dueLeadTimes(leadTimes []int, dueAt, now int64) []int {
+	due := []int{}
+	for _, leadTime := range leadTimes {
+		remindAt := dueAt - (time.Duration(leadTime) * time.Minute).Milliseconds()
+		if now >= remindAt && now < remindAt+dueDateReminderWindow.Milliseconds() {
+			due = append(due, leadTime)
+		}
+	}
+	return due
+}
+
+// recordDueDateReminders records the reminders of a card date and
+// returns true if any of them was not sent yet. A single reminder is
+// sent for several lead times due at once.
+func (a *App) recordDueDateReminders(cardID, propertyID string, dueAt int64, leadTimes []int, now int64) (bool, error) {
+	sent := false
+	for _, leadTime := range leadTimes {
+		reminder := &model.DueDateReminder{
+			CardID:     cardID,
+			PropertyID: propertyID,
+			DueAt:      dueAt,
+			LeadTime:   leadTime,
+			SentAt:     now,
+		}
+		added, err := a.store.AddDueDateReminder(reminder)
+		if err != nil {
+			return false, err
+		}
+		sent = sent || added
+	}
+	return sent, nil
+}
+
+// getReminderUserIDs returns the users assigned to a card through its
+// person properties and the users subscribed to it, who can still view
+// its board.
+func (a *App) getReminderUserIDs(board *model.Board, card *model.Block, props map[string]interface{}, personPropIDs []string) ([]string, error) {
+	userIDs := []string{}
+	addUserID := func(userID string) {
+		if userID == "" || containsString(userIDs, userID) {
+			return
+		}
+		if a.permissions.HasPermissionToBoard(userID, board.ID, model.PermissionViewBoard) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	for _, propID := range personPropIDs {
+		switch value := props[propID].(type) {
+		case string:
+			addUserID(value)
+		case []interface{}:
+			for _, userIDIface := range value {
+				if userID, ok := userIDIface.(string); ok {
+					addUserID(userID)
+				}
+			}
+		}
+	}
+
+	subscribers, err := a.store.GetSubscribersForBlock(card.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, subscriber := range subscribers {
+		if subscriber.SubscriberType == model.SubTypeUser {
+			addUserID(subscriber.SubscriberID)
+		}
+	}
+	return userIDs, nil
+}
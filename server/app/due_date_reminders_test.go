@@ -0,0 +1,141 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+type testReminderBackend struct {
+	events []notify.DueDateReminderEvent
+}
+
+func (b *testReminderBackend) Start() error                                 { return nil }
+func (b *testReminderBackend) ShutDown() error                              { return nil }
+func (b *testReminderBackend) BlockChanged(_ notify.BlockChangeEvent) error { return nil }
+func (b *testReminderBackend) Name() string                                 { return "testReminders" }
+
+func (b *testReminderBackend) DueDateReminder(evt notify.DueDateReminderEvent) error {
+	b.events = append(b.events, evt)
+	return nil
+}
+
+func TestSendDueDateReminders(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	backend := &testReminderBackend{}
+	notifications, err := notify.New(th.logger, backend)
+	require.NoError(t, err)
+	th.App.notifications = notifications
+	th.App.config.DueDateReminderLeadTimes = []int{60, 0}
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	nowMillis := utils.GetMillisForTime(now)
+	dateValue := func(t time.Time) string {
+		return fmt.Sprintf(`{"from":%d}`, utils.GetMillisForTime(t))
+	}
+
+	board := &model.Board{
+		ID:     testBoardID,
+		TeamID: "team-id",
+		Title:  "board",
+		Properties: map[string]interface{}{
+			model.BoardPropertyCompletedOptionIDs: []interface{}{"done-option"},
+		},
+		CardProperties: []map[string]interface{}{
+			{"id": "due", "name": "Due", "type": "date"},
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "status", "name": "Status", "type": "select"},
+		},
+	}
+
+	upcoming := relationTestCard("upcoming", testBoardID, map[string]interface{}{
+		"due":   dateValue(now.Add(30 * time.Minute)),
+		"owner": "user-1",
+	})
+	overdue := relationTestCard("overdue", testBoardID, map[string]interface{}{
+		"due": dateValue(now.Add(-2 * time.Hour)),
+	})
+	later := relationTestCard("later", testBoardID, map[string]interface{}{
+		"due":   dateValue(now.Add(48 * time.Hour)),
+		"owner": "user-1",
+	})
+	ancient := relationTestCard("ancient", testBoardID, map[string]interface{}{
+		"due":   dateValue(now.Add(-72 * time.Hour)),
+		"owner": "user-1",
+	})
+	completed := relationTestCard("completed", testBoardID, map[string]interface{}{
+		"due":    dateValue(now.Add(30 * time.Minute)),
+		"owner":  "user-1",
+		"status": "done-option",
+	})
+
+	th.Store.EXPECT().DeleteDueDateRemindersBefore(nowMillis - 2*dueDateReminderWindow.Milliseconds()).AnyTimes()
+	th.Store.EXPECT().GetBoardsWithPropertyType("date").AnyTimes().Return([]*model.Board{board}, nil)
+	// the store narrows the cards down to the reminder window, the due
+	// dates are checked again by the app
+	from := nowMillis - dueDateReminderWindow.Milliseconds()
+	to := nowMillis + time.Hour.Milliseconds()
+	th.Store.EXPECT().GetCardsWithDatesBetween(testBoardID, []string{"due"}, from, to).AnyTimes().
+		Return([]*model.Block{upcoming, overdue, later, ancient, completed}, nil)
+	th.Store.EXPECT().GetSubscribersForBlock("upcoming").AnyTimes().Return([]*model.Subscriber{
+		{SubscriberType: model.SubTypeUser, SubscriberID: "user-2"},
+		{SubscriberType: model.SubTypeUser, SubscriberID: "user-1"},
+		{SubscriberType: model.SubTypeUser, SubscriberID: "former-member"},
+		{SubscriberType: model.SubTypeChannel, SubscriberID: "channel-1"},
+	}, nil)
+
+	for _, userID := range []string{"user-1", "user-2", "user-3"} {
+		expectBoardEditor(th, userID, board)
+	}
+	// users who can no longer view the board get no reminders
+	th.PermissionsStore.EXPECT().GetMemberForBoard(testBoardID, "former-member").AnyTimes().
+		Return(nil, model.NewErrNotFound("member"))
+	th.API.EXPECT().HasPermissionToTeam("former-member", board.TeamID, model.PermissionViewTeam).AnyTimes().Return(true)
+	th.Store.EXPECT().GetSubscribersForBlock("overdue").AnyTimes().Return([]*model.Subscriber{
+		{SubscriberType: model.SubTypeUser, SubscriberID: "user-3"},
+	}, nil)
+
+	sent := map[string]bool{}
+	th.Store.EXPECT().AddDueDateReminder(gomock.Any()).AnyTimes().DoAndReturn(func(reminder *model.DueDateReminder) (bool, error) {
+		key := fmt.Sprintf("%s-%s-%d-%d", reminder.CardID, reminder.PropertyID, reminder.DueAt, reminder.LeadTime)
+		if sent[key] {
+			return false, nil
+		}
+		sent[key] = true
+		return true, nil
+	})
+
+	th.App.SendDueDateReminders(now)
+	require.Len(t, backend.events, 2)
+
+	require.Equal(t, "upcoming", backend.events[0].Card.ID)
+	require.False(t, backend.events[0].Overdue)
+	require.Equal(t, "Due", backend.events[0].PropertyName)
+	require.Equal(t, []string{"user-1", "user-2"}, backend.events[0].UserIDs)
+
+	require.Equal(t, "overdue", backend.events[1].Card.ID)
+	require.True(t, backend.events[1].Overdue)
+	require.Equal(t, []string{"user-3"}, backend.events[1].UserIDs)
+
+	// running the job again doesn't send the reminders twice
+	th.App.SendDueDateReminders(now)
+	require.Len(t, backend.events, 2)
+
+	t.Run("boards can disable the reminders", func(t *testing.T) {
+		board.Properties[model.BoardPropertyReminderLeadTimes] = []interface{}{}
+		sent = map[string]bool{}
+		backend.events = nil
+
+		th.App.SendDueDateReminders(now)
+		require.Empty(t, backend.events)
+	})
+}
@@ -80,6 +80,7 @@ func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.Exp
 	}
 
 	var files []string
+	cardIDs := map[string]bool{}
 	// write the board's blocks
 	// TODO: paginate this
 	blocks, err := a.GetBlocksForBoard(board.ID)
@@ -91,6 +92,9 @@ func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.Exp
 		if err = a.writeArchiveBlockLine(w, block); err != nil {
 			return err
 		}
+		if block.Type == model.TypeCard {
+			cardIDs[block.ID] = true
+		}
 		if block.Type == model.TypeImage || block.Type == model.TypeAttachment {
 			filename, err2 := extractFilename(block)
 			if err2 != nil {
@@ -111,6 +115,21 @@ func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.Exp
 		}
 	}
 
+	// only the dependencies between cards of the board can be imported
+	dependencies, err := a.store.GetCardDependenciesForBoard(board.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, dependency := range dependencies {
+		if !cardIDs[dependency.BlockerID] || !cardIDs[dependency.BlockedID] {
+			continue
+		}
+		if err = a.writeArchiveCardDependencyLine(w, dependency); err != nil {
+			return err
+		}
+	}
+
 	// write the files
 	for _, filename := range files {
 		if err := a.writeArchiveFile(zw, filename, board.ID, opt); err != nil {
@@ -120,6 +139,31 @@ func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.Exp
 	return nil
 }
 
+// writeArchiveCardDependencyLine writes a single card dependency to the archive.
+func (a *App) writeArchiveCardDependencyLine(w io.Writer, dependency *model.CardDependency) error {
+	cd, err := json.Marshal(&dependency)
+	if err != nil {
+		return err
+	}
+	line := model.ArchiveLine{
+		Type: "cardDependency",
+		Data: cd,
+	}
+
+	cd, err = json.Marshal(&line)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(cd)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(newline)
+	return err
+}
+
 // writeArchiveBoardMemberLine writes a single boardMember to the archive.
 func (a *App) writeArchiveBoardMemberLine(w io.Writer, boardMember *model.BoardMember) error {
 	bm, err := json.Marshal(&boardMember)
@@ -199,12 +243,17 @@ func (a *App) writeArchiveBoardLine(w io.Writer, board model.Board) error {
 
 // writeArchiveFile writes a single file to the archive.
 func (a *App) writeArchiveFile(zw *zip.Writer, filename string, boardID string, opt model.ExportArchiveOptions) error {
-	dest, err := zw.Create(boardID + "/" + filename)
+	return a.writeBoardFile(zw, boardID+"/"+filename, opt.TeamID, boardID, filename)
+}
+
+// writeBoardFile copies a file of a board to the zip entry at path.
+func (a *App) writeBoardFile(zw *zip.Writer, path string, teamID string, boardID string, filename string) error {
+	dest, err := zw.Create(path)
 	if err != nil {
 		return err
 	}
 
-	_, fileReader, err := a.GetFile(opt.TeamID, boardID, filename)
+	_, fileReader, err := a.GetFile(teamID, boardID, filename)
 	if err != nil && !model.IsErrNotFound(err) {
 		return err
 	}
@@ -212,7 +261,7 @@ func (a *App) writeArchiveFile(zw *zip.Writer, filename string, boardID string,
 		// just log this; image file is missing but we'll still export an equivalent board
 		a.logger.Error("image file missing for export",
 			mlog.String("filename", filename),
-			mlog.String("team_id", opt.TeamID),
+			mlog.String("team_id", teamID),
 			mlog.String("board_id", boardID),
 		)
 		return nil
@@ -0,0 +1,42 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// CompactHistory merges the consecutive changes a user made to a block or
+// a board within the compaction window into a single history entry. The
+// history is left untouched when the license enables the compliance
+// export, which requires the full history.
+func (a *App) CompactHistory(now time.Time) {
+	if a.config.HistoryCompactionWindowSeconds <= 0 {
+		return
+	}
+
+	license := a.store.GetLicense()
+	if license != nil && license.Features.Compliance != nil && *license.Features.Compliance {
+		a.logger.Debug("CompactHistory skipped, the compliance export requires the full history")
+		return
+	}
+
+	window := int64(a.config.HistoryCompactionWindowSeconds) * 1000
+	before := utils.GetMillisForTime(now.Add(-time.Duration(a.config.HistoryCompactionMinAgeHours) * time.Hour))
+	removed, err := a.store.CompactHistory(window, before)
+	if err != nil {
+		a.logger.Error("CompactHistory cannot compact the history", mlog.Err(err))
+		return
+	}
+	for table, count := range removed {
+		a.metrics.IncrementHistoryRowsCompacted(table, count)
+		if count > 0 {
+			a.logger.Info("Compacted history", mlog.String("table", table), mlog.Int("removed", count))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/mattermost/focalboard/server/utils"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestCompactHistory(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("compacts the history older than the minimum age", func(t *testing.T) {
+		th.App.config.HistoryCompactionWindowSeconds = 300
+		th.App.config.HistoryCompactionMinAgeHours = 24
+		before := utils.GetMillisForTime(time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC))
+		th.Store.EXPECT().GetLicense().Return(nil)
+		th.Store.EXPECT().CompactHistory(int64(300000), before).Return(map[string]int64{"blocks_history": 4, "boards_history": 0}, nil)
+
+		th.App.CompactHistory(now)
+	})
+
+	t.Run("keeps the full history for compliance", func(t *testing.T) {
+		th.App.config.HistoryCompactionWindowSeconds = 300
+		th.Store.EXPECT().GetLicense().Return(&mmModel.License{
+			Features: &mmModel.Features{Compliance: mmModel.NewBool(true)},
+		})
+		th.Store.EXPECT().CompactHistory(gomock.Any(), gomock.Any()).Times(0)
+
+		th.App.CompactHistory(now)
+	})
+
+	t.Run("does nothing when disabled", func(t *testing.T) {
+		th.App.config.HistoryCompactionWindowSeconds = 0
+		th.Store.EXPECT().CompactHistory(gomock.Any(), gomock.Any()).Times(0)
+
+		th.App.CompactHistory(now)
+	})
+}
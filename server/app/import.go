@@ -165,6 +165,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 	now := utils.GetMillis()
 	var boardID string
 	var boardMembers []*model.BoardMember
+	var cardDependencies []*model.CardDependency
 
 	lineNum := 1
 	firstLine := true
@@ -234,6 +235,12 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 						return nil, fmt.Errorf("invalid board Member in archive line %d: %w", lineNum, err2)
 					}
 					boardMembers = append(boardMembers, boardMember)
+				case "cardDependency":
+					var dependency *model.CardDependency
+					if err2 := json.Unmarshal(archiveLine.Data, &dependency); err2 != nil {
+						return nil, fmt.Errorf("invalid card dependency in archive line %d: %w", lineNum, err2)
+					}
+					cardDependencies = append(cardDependencies, dependency)
 				default:
 					return nil, model.NewErrUnsupportedArchiveLineType(lineNum, archiveLine.Type)
 				}
@@ -255,6 +262,13 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 
 	a.fixBoardsandBlocks(boardsAndBlocks, opt)
 
+	// the block IDs are regenerated in place, keep the blocks by their
+	// archive ID to translate the card dependencies
+	blocksByArchiveID := map[string]*model.Block{}
+	for _, block := range boardsAndBlocks.Blocks {
+		blocksByArchiveID[block.ID] = block
+	}
+
 	var err error
 	boardsAndBlocks, err = model.GenerateBoardsAndBlocksIDs(boardsAndBlocks, a.logger)
 	if err != nil {
@@ -266,6 +280,25 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 		return nil, fmt.Errorf("error inserting archive blocks: %w", err)
 	}
 
+	for _, dependency := range cardDependencies {
+		blocker, ok := blocksByArchiveID[dependency.BlockerID]
+		if !ok {
+			continue
+		}
+		blocked, ok := blocksByArchiveID[dependency.BlockedID]
+		if !ok {
+			continue
+		}
+		newDependency := &model.CardDependency{
+			BlockerID: blocker.ID,
+			BlockedID: blocked.ID,
+			CreatedBy: opt.ModifiedBy,
+		}
+		if err2 := a.store.AddCardDependency(newDependency); err2 != nil {
+			return nil, fmt.Errorf("cannot add card dependency: %w", err2)
+		}
+	}
+
 	// add users to all the new boards (if not the fake system user).
 	for _, board := range boardsAndBlocks.Boards {
 		// make sure an admin user gets added
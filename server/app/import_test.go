@@ -201,6 +201,8 @@ func TestApp_ImportArchive(t *testing.T) {
 		th.Store.EXPECT().GetBlock(blockIDs[0]).Return(imageBlock, nil)
 		th.Store.EXPECT().GetBlock(blockIDs[1]).Return(attachmentBlock, nil)
 		th.Store.EXPECT().GetMembersForBoard("board-id").AnyTimes().Return([]*model.BoardMember{}, nil)
+		// the board schema is checked for rollups depending on the patched blocks
+		th.Store.EXPECT().GetBoard("board-id").AnyTimes().Return(board, nil)
 
 		th.Store.EXPECT().PatchBlocks(&blockPatchesBatch, "my-userid")
 		th.App.fixImagesAttachments(boardMap, fileMap, "test-team", "my-userid")
@@ -23,4 +23,18 @@ func (a *App) Shutdown() {
 			a.logger.Warn("blockChangeNotifier shutdown timed out")
 		}
 	}
+	if a.webhookQueue != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), blockChangeNotifierShutdownTimeout)
+		defer cancel()
+		if !a.webhookQueue.Shutdown(ctx) {
+			a.logger.Warn("webhookQueue shutdown timed out")
+		}
+	}
+	if a.bulkOperationQueue != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), blockChangeNotifierShutdownTimeout)
+		defer cancel()
+		if !a.bulkOperationQueue.Shutdown(ctx) {
+			a.logger.Warn("bulkOperationQueue shutdown timed out")
+		}
+	}
 }
@@ -0,0 +1,238 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *App) propertyValidationMode() model.PropertyValidationMode {
+	if a.config == nil || a.config.CardPropertyValidation == "" {
+		return model.PropertyValidationOff
+	}
+	return model.PropertyValidationMode(a.config.CardPropertyValidation)
+}
+
+// validateCardProperties checks the property values of a card before it
+// is written. In strict mode the invalid values are an error, in lenient
+// mode they are reverted to their old value or removed from props. The
+// relations are checked in every mode.
+func (a *App) validateCardProperties(board *model.Board, cardID string, props, oldProps map[string]interface{}) error {
+	if board == nil || len(props) == 0 {
+		return nil
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Warn("Cannot parse the property schema to validate card properties", mlog.String("board_id", board.ID), mlog.Err(err))
+		return nil
+	}
+
+	if err = a.checkRelationValues(schema, props, oldProps); err != nil {
+		return err
+	}
+
+	mode := a.propertyValidationMode()
+	if mode != model.PropertyValidationStrict && mode != model.PropertyValidationLenient {
+		return nil
+	}
+
+	valueErrs := schema.ValidateProperties(cardID, props, oldProps)
+	if len(valueErrs) == 0 {
+		return nil
+	}
+
+	if mode == model.PropertyValidationStrict {
+		return model.NewErrInvalidPropertyValues(valueErrs)
+	}
+
+	for _, valueErr := range valueErrs {
+		a.logger.Debug("Dropping invalid card property value",
+			mlog.String("card_id", cardID),
+			mlog.String("property_id", valueErr.PropertyID),
+			mlog.String("reason", valueErr.Reason),
+		)
+		if oldValue, ok := oldProps[valueErr.PropertyID]; ok {
+			props[valueErr.PropertyID] = oldValue
+		} else {
+			delete(props, valueErr.PropertyID)
+		}
+	}
+	return nil
+}
+
+// checkRelationValues checks that the cards added to the relation
+// properties are cards of the related board, so that relations can't be
+// used to reach the cards of other boards.
+func (a *App) checkRelationValues(schema model.PropSchema, props, oldProps map[string]interface{}) error {
+	for propID, value := range props {
+		propDef, ok := schema[propID]
+		if !ok || propDef.Type != model.PropTypeRelation {
+			continue
+		}
+		ids, err := model.GetRelationIDs(value)
+		if err != nil {
+			// malformed values are handled by the validation mode
+			continue
+		}
+		oldIDs, _ := model.GetRelationIDs(oldProps[propID])
+
+		added := []string{}
+		for _, id := range ids {
+			if !containsString(oldIDs, id) {
+				added = append(added, id)
+			}
+		}
+		if len(added) == 0 {
+			continue
+		}
+
+		cards, err := a.store.GetBlocksByIDs(added)
+		if err != nil && !model.IsErrNotFound(err) {
+			return err
+		}
+		related := make(map[string]bool, len(cards))
+		for _, card := range cards {
+			if card.Type == model.TypeCard && card.BoardID == propDef.RelationBoardID {
+				related[card.ID] = true
+			}
+		}
+		for _, id := range added {
+			if !related[id] {
+				return model.NewErrBadRequest(fmt.Sprintf("%s is not a card of the board of property %s", id, propID))
+			}
+		}
+	}
+	return nil
+}
+
+// validateCardBlocks validates the properties of the new card blocks,
+// and stops at the first board with invalid values in strict mode.
+func (a *App) validateCardBlocks(boards map[string]*model.Board, blocks []*model.Block) error {
+	for _, block := range blocks {
+		if block.Type != model.TypeCard {
+			continue
+		}
+		props, ok := block.Fields["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := a.validateCardProperties(boards[block.BoardID], block.ID, props, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCardPatch validates the properties updated by a patch of a
+// card block.
+func (a *App) validateCardPatch(board *model.Board, oldBlock *model.Block, patch *model.BlockPatch) error {
+	if oldBlock == nil || oldBlock.Type != model.TypeCard || patch == nil {
+		return nil
+	}
+	props, ok := patch.UpdatedFields["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	oldProps, _ := oldBlock.Fields["properties"].(map[string]interface{})
+	return a.validateCardProperties(board, oldBlock.ID, props, oldProps)
+}
+
+// validateCardPatches validates the properties updated by a batch of
+// block patches.
+func (a *App) validateCardPatches(oldBlocks []*model.Block, blockPatches *model.BlockPatchBatch) error {
+	oldBlocksMap := make(map[string]*model.Block, len(oldBlocks))
+	for _, block := range oldBlocks {
+		oldBlocksMap[block.ID] = block
+	}
+
+	boards := map[string]*model.Board{}
+	for i, blockID := range blockPatches.BlockIDs {
+		oldBlock, ok := oldBlocksMap[blockID]
+		if !ok || oldBlock.Type != model.TypeCard || i >= len(blockPatches.BlockPatches) {
+			continue
+		}
+		if _, ok := blockPatches.BlockPatches[i].UpdatedFields["properties"]; !ok {
+			continue
+		}
+
+		board, ok := boards[oldBlock.BoardID]
+		if !ok {
+			var err error
+			if board, err = a.store.GetBoard(oldBlock.BoardID); err != nil {
+				return err
+			}
+			boards[board.ID] = board
+		}
+
+		if err := a.validateCardPatch(board, oldBlock, &blockPatches.BlockPatches[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePatchBoardsAndBlocks validates the properties updated by the
+// block patches against the schemas of the boards as patched.
+func (a *App) validatePatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, oldBlocks []*model.Block) error {
+	updatesProperties := false
+	for _, patch := range pbab.BlockPatches {
+		if patch != nil && patch.UpdatedFields["properties"] != nil {
+			updatesProperties = true
+			break
+		}
+	}
+	if !updatesProperties {
+		return nil
+	}
+
+	boards := map[string]*model.Board{}
+	for i, boardID := range pbab.BoardIDs {
+		board, err := a.store.GetBoard(boardID)
+		if err != nil {
+			return err
+		}
+		if i < len(pbab.BoardPatches) && pbab.BoardPatches[i] != nil {
+			// the board may be shared with the store cache, so the
+			// patch is applied to a copy
+			patched := *board
+			patched.Properties = make(map[string]interface{}, len(board.Properties))
+			for key, value := range board.Properties {
+				patched.Properties[key] = value
+			}
+			board = pbab.BoardPatches[i].Patch(&patched)
+		}
+		boards[boardID] = board
+	}
+
+	oldBlocksMap := make(map[string]*model.Block, len(oldBlocks))
+	for _, block := range oldBlocks {
+		oldBlocksMap[block.ID] = block
+	}
+
+	for i, blockID := range pbab.BlockIDs {
+		oldBlock, ok := oldBlocksMap[blockID]
+		if !ok || i >= len(pbab.BlockPatches) || pbab.BlockPatches[i] == nil {
+			continue
+		}
+
+		board, ok := boards[oldBlock.BoardID]
+		if !ok {
+			var err error
+			if board, err = a.store.GetBoard(oldBlock.BoardID); err != nil {
+				return err
+			}
+			boards[board.ID] = board
+		}
+
+		if err := a.validateCardPatch(board, oldBlock, pbab.BlockPatches[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
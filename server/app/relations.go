@@ -0,0 +1,338 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// relationReferencesField is the card field where the references
+// removed from other cards when the card is deleted are kept, so they
+// can be restored if the card is undeleted.
+const relationReferencesField = "relationReferences"
+
+type cardRelationReference struct {
+	CardID     string `json:"cardId"`
+	PropertyID string `json:"propertyId"`
+}
+
+func getCardProperties(card *model.Block) map[string]interface{} {
+	props := map[string]interface{}{}
+	if card == nil {
+		return props
+	}
+	if cardProps, ok := card.Fields["properties"].(map[string]interface{}); ok {
+		for k, v := range cardProps {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+// getRelationIDs returns the related card IDs of a property, ignoring
+// malformed values.
+func getRelationIDs(props map[string]interface{}, propertyID string) []string {
+	ids, err := model.GetRelationIDs(props[propertyID])
+	if err != nil {
+		return []string{}
+	}
+	return ids
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// syncRelationBackReferences updates the back reference properties of
+// the cards added to or removed from the relation properties of a card.
+func (a *App) syncRelationBackReferences(board *model.Board, oldCard, newCard *model.Block, modifiedBy string) {
+	if newCard == nil || newCard.Type != model.TypeCard {
+		return
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Error("syncRelationBackReferences cannot parse the board schema", mlog.String("board_id", board.ID), mlog.Err(err))
+		return
+	}
+
+	oldProps := getCardProperties(oldCard)
+	newProps := getCardProperties(newCard)
+	for _, propDef := range schema {
+		if propDef.Type != model.PropTypeRelation || propDef.BackReferenceID == "" {
+			continue
+		}
+
+		oldIDs := getRelationIDs(oldProps, propDef.ID)
+		newIDs := getRelationIDs(newProps, propDef.ID)
+		for _, id := range newIDs {
+			if !containsString(oldIDs, id) {
+				a.updateRelationValue(id, propDef.RelationBoardID, propDef.BackReferenceID, newCard.ID, true, modifiedBy)
+			}
+		}
+		for _, id := range oldIDs {
+			if !containsString(newIDs, id) {
+				a.updateRelationValue(id, propDef.RelationBoardID, propDef.BackReferenceID, newCard.ID, false, modifiedBy)
+			}
+		}
+	}
+}
+
+// updateRelationValue adds or removes a related card ID from a relation
+// property of a card. Cards or properties that no longer exist are
+// ignored, as are the cards that aren't on boardID, if not empty, or that
+// the user can't modify.
+func (a *App) updateRelationValue(cardID, boardID, propertyID, relatedID string, add bool, modifiedBy string) {
+	card, err := a.store.GetBlock(cardID)
+	if model.IsErrNotFound(err) {
+		return
+	}
+	if err != nil {
+		a.logger.Error("updateRelationValue cannot get the card", mlog.String("card_id", cardID), mlog.Err(err))
+		return
+	}
+	if card.Type != model.TypeCard || (boardID != "" && card.BoardID != boardID) {
+		return
+	}
+	if !a.permissions.HasPermissionToBoard(modifiedBy, card.BoardID, model.PermissionManageBoardCards) {
+		a.logger.Debug("updateRelationValue skipping a card the user cannot modify",
+			mlog.String("card_id", card.ID),
+			mlog.String("user_id", modifiedBy),
+		)
+		return
+	}
+
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		a.logger.Error("updateRelationValue cannot get the board", mlog.String("board_id", card.BoardID), mlog.Err(err))
+		return
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Error("updateRelationValue cannot parse the board schema", mlog.String("board_id", board.ID), mlog.Err(err))
+		return
+	}
+	if propDef, ok := schema[propertyID]; !ok || propDef.Type != model.PropTypeRelation {
+		return
+	}
+
+	props := getCardProperties(card)
+	ids := getRelationIDs(props, propertyID)
+	if add == containsString(ids, relatedID) {
+		return
+	}
+
+	if add {
+		ids = append(ids, relatedID)
+	} else {
+		ids = removeString(ids, relatedID)
+	}
+	setRelationIDs(props, propertyID, ids)
+
+	if err := a.patchCardProperties(board, card.ID, props, modifiedBy); err != nil {
+		a.logger.Error("updateRelationValue cannot patch the card", mlog.String("card_id", card.ID), mlog.Err(err))
+	}
+}
+
+func removeString(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func setRelationIDs(props map[string]interface{}, propertyID string, ids []string) {
+	if len(ids) == 0 {
+		delete(props, propertyID)
+		return
+	}
+	props[propertyID] = ids
+}
+
+// patchCardProperties replaces the properties of a card and broadcasts
+// the change.
+func (a *App) patchCardProperties(board *model.Board, cardID string, props map[string]interface{}, modifiedBy string) error {
+	patch := &model.BlockPatch{
+		UpdatedFields: map[string]interface{}{"properties": props},
+	}
+	if err := a.store.PatchBlock(cardID, patch, modifiedBy); err != nil {
+		return err
+	}
+
+	card, err := a.store.GetBlock(cardID)
+	if err != nil {
+		return err
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBlockChange(board.TeamID, card)
+		return nil
+	})
+	return nil
+}
+
+// removeCardRelations removes a card from the relation properties of
+// every card that references it, and returns the removed references.
+// Only the boards with relations to the board of the card are searched.
+func (a *App) removeCardRelations(card *model.Block, modifiedBy string) ([]cardRelationReference, error) {
+	relationBoards, err := a.store.GetBoardsWithPropertyType(model.PropTypeRelation)
+	if err != nil {
+		return nil, err
+	}
+	boardIDs := []string{}
+	for _, board := range relationBoards {
+		for _, prop := range board.CardProperties {
+			if prop["type"] == model.PropTypeRelation && prop["relationBoardId"] == card.BoardID {
+				boardIDs = append(boardIDs, board.ID)
+				break
+			}
+		}
+	}
+	if len(boardIDs) == 0 {
+		return []cardRelationReference{}, nil
+	}
+
+	cards, err := a.store.GetCardsReferencingCard(card.ID, boardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []cardRelationReference{}
+	removed := 0
+	boards := map[string]*model.Board{}
+	schemas := map[string]model.PropSchema{}
+	for _, refCard := range cards {
+		schema, ok := schemas[refCard.BoardID]
+		if !ok {
+			board, err := a.store.GetBoard(refCard.BoardID)
+			if err != nil {
+				return nil, err
+			}
+			if schema, err = model.ParsePropertySchema(board); err != nil {
+				return nil, err
+			}
+			boards[board.ID] = board
+			schemas[board.ID] = schema
+		}
+
+		props := getCardProperties(refCard)
+		changed := false
+		for _, propDef := range schema {
+			if propDef.Type != model.PropTypeRelation || propDef.RelationBoardID != card.BoardID {
+				continue
+			}
+			ids := getRelationIDs(props, propDef.ID)
+			if !containsString(ids, card.ID) {
+				continue
+			}
+			setRelationIDs(props, propDef.ID, removeString(ids, card.ID))
+			refs = append(refs, cardRelationReference{CardID: refCard.ID, PropertyID: propDef.ID})
+			changed = true
+		}
+
+		if changed {
+			if err := a.patchCardProperties(boards[refCard.BoardID], refCard.ID, props, modifiedBy); err != nil {
+				a.restoreRelationReferences(card.ID, refs[:removed], modifiedBy)
+				return nil, err
+			}
+			removed = len(refs)
+		}
+	}
+	return refs, nil
+}
+
+// restoreCardRelations adds an undeleted card back to the relation
+// properties it was removed from when it was deleted.
+func (a *App) restoreCardRelations(card *model.Block, modifiedBy string) (*model.Block, error) {
+	refsIface, ok := card.Fields[relationReferencesField]
+	if !ok {
+		return card, nil
+	}
+
+	data, err := json.Marshal(refsIface)
+	if err != nil {
+		return nil, err
+	}
+	var refs []cardRelationReference
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return nil, err
+	}
+
+	a.restoreRelationReferences(card.ID, refs, modifiedBy)
+
+	patch := &model.BlockPatch{DeletedFields: []string{relationReferencesField}}
+	if err := a.store.PatchBlock(card.ID, patch, modifiedBy); err != nil {
+		return nil, err
+	}
+	return a.store.GetBlock(card.ID)
+}
+
+// restoreRelationReferences adds a card back to the relation properties
+// it was removed from by removeCardRelations.
+func (a *App) restoreRelationReferences(cardID string, refs []cardRelationReference, modifiedBy string) {
+	for _, ref := range refs {
+		a.updateRelationValue(ref.CardID, "", ref.PropertyID, cardID, true, modifiedBy)
+	}
+}
+
+// removeBoardRelations removes the cards of a deleted board from the
+// relation properties of the cards of the other boards.
+func (a *App) removeBoardRelations(boardID, modifiedBy string) error {
+	relationBoards, err := a.store.GetBoardsWithPropertyType(model.PropTypeRelation)
+	if err != nil {
+		return err
+	}
+
+	for _, board := range relationBoards {
+		if board.ID == boardID {
+			continue
+		}
+		schema, err := model.ParsePropertySchema(board)
+		if err != nil {
+			return err
+		}
+		propIDs := []string{}
+		for _, propDef := range schema {
+			if propDef.Type == model.PropTypeRelation && propDef.RelationBoardID == boardID {
+				propIDs = append(propIDs, propDef.ID)
+			}
+		}
+		if len(propIDs) == 0 {
+			continue
+		}
+
+		cards, err := a.store.GetBlocks(model.QueryBlocksOptions{BoardID: board.ID, BlockType: model.TypeCard})
+		if err != nil {
+			return err
+		}
+		for _, card := range cards {
+			props := getCardProperties(card)
+			changed := false
+			for _, propID := range propIDs {
+				if _, ok := props[propID]; ok {
+					delete(props, propID)
+					changed = true
+				}
+			}
+			if changed {
+				if err := a.patchCardProperties(board, card.ID, props, modifiedBy); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
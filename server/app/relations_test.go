@@ -0,0 +1,267 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func relationTestBoard(boardID string) *model.Board {
+	return &model.Board{
+		ID:     boardID,
+		TeamID: "team-id",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":              "relation-prop",
+				"name":            "Related",
+				"type":            model.PropTypeRelation,
+				"relationBoardId": boardID,
+				"backReferenceId": "backref-prop",
+			},
+			{
+				"id":   "backref-prop",
+				"name": "Related by",
+				"type": model.PropTypeRelation,
+			},
+		},
+	}
+}
+
+func relationTestCard(id, boardID string, props map[string]interface{}) *model.Block {
+	return &model.Block{
+		ID:      id,
+		BoardID: boardID,
+		Type:    model.TypeCard,
+		Fields:  map[string]interface{}{"properties": props},
+	}
+}
+
+// expectBoardEditor makes the user an editor of the board for the
+// permission checks.
+func expectBoardEditor(th *TestHelper, userID string, board *model.Board) {
+	th.PermissionsStore.EXPECT().GetBoard(board.ID).Return(board, nil).AnyTimes()
+	th.PermissionsStore.EXPECT().GetMemberForBoard(board.ID, userID).
+		Return(&model.BoardMember{BoardID: board.ID, UserID: userID, SchemeEditor: true}, nil).AnyTimes()
+	th.API.EXPECT().HasPermissionToTeam(userID, board.TeamID, model.PermissionViewTeam).Return(true).AnyTimes()
+	th.API.EXPECT().HasPermissionToTeam(userID, board.TeamID, model.PermissionManageTeam).Return(false).AnyTimes()
+}
+
+func TestSyncRelationBackReferences(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := relationTestBoard(testBoardID)
+	th.Store.EXPECT().GetMembersForBoard(gomock.Any()).AnyTimes()
+	expectBoardEditor(th, "user-id-1", board)
+
+	t.Run("adds the back reference to the related cards", func(t *testing.T) {
+		oldCard := relationTestCard("card1", testBoardID, map[string]interface{}{})
+		newCard := relationTestCard("card1", testBoardID, map[string]interface{}{
+			"relation-prop": []interface{}{"card2"},
+		})
+		related := relationTestCard("card2", testBoardID, map[string]interface{}{})
+
+		th.Store.EXPECT().GetBlock("card2").Return(related, nil).Times(2)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().PatchBlock("card2", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"backref-prop": []string{"card1"}},
+			},
+		}, "user-id-1").Return(nil)
+
+		th.App.syncRelationBackReferences(board, oldCard, newCard, "user-id-1")
+	})
+
+	t.Run("removes the back reference from the unrelated cards", func(t *testing.T) {
+		oldCard := relationTestCard("card1", testBoardID, map[string]interface{}{
+			"relation-prop": []interface{}{"card2"},
+		})
+		newCard := relationTestCard("card1", testBoardID, map[string]interface{}{})
+		related := relationTestCard("card2", testBoardID, map[string]interface{}{
+			"backref-prop": []interface{}{"card1", "card3"},
+		})
+
+		th.Store.EXPECT().GetBlock("card2").Return(related, nil).Times(2)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().PatchBlock("card2", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"backref-prop": []string{"card3"}},
+			},
+		}, "user-id-1").Return(nil)
+
+		th.App.syncRelationBackReferences(board, oldCard, newCard, "user-id-1")
+	})
+
+	t.Run("ignores the cards of other boards", func(t *testing.T) {
+		newCard := relationTestCard("card1", testBoardID, map[string]interface{}{
+			"relation-prop": []interface{}{"card2"},
+		})
+		other := relationTestCard("card2", "other-board-id", map[string]interface{}{})
+
+		th.Store.EXPECT().GetBlock("card2").Return(other, nil)
+
+		th.App.syncRelationBackReferences(board, nil, newCard, "user-id-1")
+	})
+
+	t.Run("ignores the cards the user cannot modify", func(t *testing.T) {
+		newCard := relationTestCard("card1", testBoardID, map[string]interface{}{
+			"relation-prop": []interface{}{"card2"},
+		})
+		related := relationTestCard("card2", testBoardID, map[string]interface{}{})
+
+		th.Store.EXPECT().GetBlock("card2").Return(related, nil)
+		th.PermissionsStore.EXPECT().GetMemberForBoard(testBoardID, "user-id-2").Return(nil, model.NewErrNotFound("member"))
+		th.API.EXPECT().HasPermissionToTeam("user-id-2", board.TeamID, model.PermissionViewTeam).Return(true)
+
+		th.App.syncRelationBackReferences(board, nil, newCard, "user-id-2")
+	})
+
+	t.Run("ignores deleted related cards", func(t *testing.T) {
+		newCard := relationTestCard("card1", testBoardID, map[string]interface{}{
+			"relation-prop": []interface{}{"missing"},
+		})
+
+		th.Store.EXPECT().GetBlock("missing").Return(nil, model.NewErrNotFound("missing"))
+
+		th.App.syncRelationBackReferences(board, nil, newCard, "user-id-1")
+	})
+}
+
+func TestRemoveAndRestoreCardRelations(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := relationTestBoard(testBoardID)
+	th.Store.EXPECT().GetMembersForBoard(gomock.Any()).AnyTimes()
+	expectBoardEditor(th, "user-id-1", board)
+
+	t.Run("removes the card from the referencing cards", func(t *testing.T) {
+		card := relationTestCard("card1", testBoardID, map[string]interface{}{})
+		referencing := relationTestCard("card2", testBoardID, map[string]interface{}{
+			"relation-prop": []interface{}{"card1", "card3"},
+		})
+
+		th.Store.EXPECT().GetBoardsWithPropertyType(model.PropTypeRelation).Return([]*model.Board{board}, nil)
+		th.Store.EXPECT().GetCardsReferencingCard("card1", []string{testBoardID}).Return([]*model.Block{referencing}, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().PatchBlock("card2", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"relation-prop": []string{"card3"}},
+			},
+		}, "user-id-1").Return(nil)
+		th.Store.EXPECT().GetBlock("card2").Return(referencing, nil)
+
+		refs, err := th.App.removeCardRelations(card, "user-id-1")
+		require.NoError(t, err)
+		require.Equal(t, []cardRelationReference{{CardID: "card2", PropertyID: "relation-prop"}}, refs)
+	})
+
+	t.Run("restores the recorded references", func(t *testing.T) {
+		card := relationTestCard("card1", testBoardID, map[string]interface{}{})
+		card.Fields[relationReferencesField] = []interface{}{
+			map[string]interface{}{"cardId": "card2", "propertyId": "relation-prop"},
+		}
+		referencing := relationTestCard("card2", testBoardID, map[string]interface{}{
+			"relation-prop": []interface{}{"card3"},
+		})
+		restored := relationTestCard("card1", testBoardID, map[string]interface{}{})
+
+		th.Store.EXPECT().GetBlock("card2").Return(referencing, nil).Times(2)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().PatchBlock("card2", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"relation-prop": []string{"card3", "card1"}},
+			},
+		}, "user-id-1").Return(nil)
+		th.Store.EXPECT().PatchBlock("card1", &model.BlockPatch{
+			DeletedFields: []string{relationReferencesField},
+		}, "user-id-1").Return(nil)
+		th.Store.EXPECT().GetBlock("card1").Return(restored, nil)
+
+		block, err := th.App.restoreCardRelations(card, "user-id-1")
+		require.NoError(t, err)
+		require.Equal(t, restored, block)
+	})
+}
+
+func TestDeleteCardRelationsRollback(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := relationTestBoard(testBoardID)
+	th.Store.EXPECT().GetMembersForBoard(gomock.Any()).AnyTimes()
+	expectBoardEditor(th, "user-id-1", board)
+
+	t.Run("restores the references when the card cannot be deleted", func(t *testing.T) {
+		card := relationTestCard("card1", testBoardID, map[string]interface{}{})
+		referencing := relationTestCard("card2", testBoardID, map[string]interface{}{
+			"relation-prop": []interface{}{"card1", "card3"},
+		})
+		removed := relationTestCard("card2", testBoardID, map[string]interface{}{
+			"relation-prop": []interface{}{"card3"},
+		})
+
+		th.Store.EXPECT().GetBlock("card1").Return(card, nil)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil).Times(3)
+		th.Store.EXPECT().GetBoardsWithPropertyType(model.PropTypeRelation).Return([]*model.Board{board}, nil)
+		th.Store.EXPECT().GetCardsReferencingCard("card1", []string{testBoardID}).Return([]*model.Block{referencing}, nil)
+		th.Store.EXPECT().PatchBlock("card2", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"relation-prop": []string{"card3"}},
+			},
+		}, "user-id-1").Return(nil)
+		th.Store.EXPECT().GetBlock("card2").Return(removed, nil).Times(3)
+		th.Store.EXPECT().PatchBlock("card1", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				relationReferencesField: []cardRelationReference{{CardID: "card2", PropertyID: "relation-prop"}},
+			},
+		}, "user-id-1").Return(nil)
+		th.Store.EXPECT().DeleteBlock("card1", "user-id-1").Return(blockError{"error"})
+		th.Store.EXPECT().PatchBlock("card2", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"relation-prop": []string{"card3", "card1"}},
+			},
+		}, "user-id-1").Return(nil)
+
+		err := th.App.DeleteBlock("card1", "user-id-1")
+		require.Error(t, err)
+	})
+}
+
+func TestRemoveBoardRelations(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	deletedBoard := relationTestBoard("deleted-board")
+	board := relationTestBoard(testBoardID)
+	board.CardProperties = append(board.CardProperties, map[string]interface{}{
+		"id":              "deleted-relation-prop",
+		"name":            "Deleted",
+		"type":            model.PropTypeRelation,
+		"relationBoardId": deletedBoard.ID,
+	})
+	th.Store.EXPECT().GetMembersForBoard(gomock.Any()).AnyTimes()
+
+	t.Run("removes the cards of the deleted board from other boards", func(t *testing.T) {
+		referencing := relationTestCard("card1", testBoardID, map[string]interface{}{
+			"relation-prop":         []interface{}{"card2"},
+			"deleted-relation-prop": []interface{}{"deleted-card"},
+		})
+		unrelated := relationTestCard("card2", testBoardID, map[string]interface{}{})
+
+		th.Store.EXPECT().GetBoardsWithPropertyType(model.PropTypeRelation).Return([]*model.Board{deletedBoard, board}, nil)
+		th.Store.EXPECT().GetBlocks(model.QueryBlocksOptions{BoardID: testBoardID, BlockType: model.TypeCard}).
+			Return([]*model.Block{referencing, unrelated}, nil)
+		th.Store.EXPECT().PatchBlock("card1", &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{
+				"properties": map[string]interface{}{"relation-prop": []interface{}{"card2"}},
+			},
+		}, "user-id-1").Return(nil)
+		th.Store.EXPECT().GetBlock("card1").Return(referencing, nil)
+
+		require.NoError(t, th.App.removeBoardRelations(deletedBoard.ID, "user-id-1"))
+	})
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+const (
+	// xlsxMaxCellLength is the maximum number of characters of a cell.
+	xlsxMaxCellLength = 32767
+
+	// xlsxMaxSheetNameLength is the maximum length of a sheet name.
+	xlsxMaxSheetNameLength = 31
+)
+
+// tableCell is a cell of an exported table.
+type tableCell struct {
+	value  string
+	number bool
+}
+
+// tableWriter writes the rows of an exported table. The rows are
+// written as they come so that large tables are streamed.
+type tableWriter interface {
+	WriteHeader(names []string) error
+	WriteRow(cells []tableCell) error
+	Close() error
+}
+
+func newTableWriter(w io.Writer, format model.BoardExportFormat, title string) (tableWriter, error) {
+	switch format {
+	case model.BoardExportFormatCSV:
+		return newCSVTableWriter(w)
+	case model.BoardExportFormatXLSX:
+		return newXLSXTableWriter(w, title)
+	}
+	return nil, model.NewErrBadRequest(fmt.Sprintf("unsupported export format %s", format))
+}
+
+// csvFormulaPrefixes are the first characters of the cells that
+// spreadsheet applications evaluate as formulas.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvTableWriter writes a CSV table, starting with a byte order mark so
+// that spreadsheet applications detect the UTF-8 encoding. Text cells
+// that could be evaluated as formulas are prefixed with a quote.
+type csvTableWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVTableWriter(w io.Writer) (*csvTableWriter, error) {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvTableWriter{writer: csv.NewWriter(w)}, nil
+}
+
+func (t *csvTableWriter) WriteHeader(names []string) error {
+	record := make([]string, len(names))
+	for i, name := range names {
+		record[i] = escapeCSVFormula(tableCell{value: name})
+	}
+	return t.writer.Write(record)
+}
+
+func (t *csvTableWriter) WriteRow(cells []tableCell) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = escapeCSVFormula(cell)
+	}
+	return t.writer.Write(record)
+}
+
+// escapeCSVFormula prefixes the cells starting like a formula with a
+// quote, except the numbers, which are kept as is so that negative
+// numbers stay numbers.
+func escapeCSVFormula(cell tableCell) string {
+	if cell.value == "" || !strings.ContainsRune(csvFormulaPrefixes, rune(cell.value[0])) {
+		return cell.value
+	}
+	if _, err := strconv.ParseFloat(cell.value, 64); cell.number && err == nil {
+		return cell.value
+	}
+	return "'" + cell.value
+}
+
+// unescapeCSVFormula removes the quote added to a cell by
+// escapeCSVFormula.
+func unescapeCSVFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func (t *csvTableWriter) Close() error {
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+// xlsxTableWriter writes an Excel workbook with a single sheet. The static
+// parts of the package are written first, and the rows of the sheet are
+// streamed to the last zip entry.
+type xlsxTableWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	rows  int
+}
+
+func newXLSXTableWriter(w io.Writer, title string) (*xlsxTableWriter, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, xmlEscape(xlsxSheetName(title)))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.WriteString(sheet, xlsxSheetStart); err != nil {
+		return nil, err
+	}
+	return &xlsxTableWriter{zip: zw, sheet: sheet}, nil
+}
+
+func (t *xlsxTableWriter) WriteHeader(names []string) error {
+	cells := make([]tableCell, len(names))
+	for i, name := range names {
+		cells[i] = tableCell{value: name}
+	}
+	return t.writeRow(cells, true)
+}
+
+func (t *xlsxTableWriter) WriteRow(cells []tableCell) error {
+	return t.writeRow(cells, false)
+}
+
+func (t *xlsxTableWriter) writeRow(cells []tableCell, bold bool) error {
+	t.rows++
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<row r="%d">`, t.rows)
+	for i, cell := range cells {
+		ref := xlsxColumnName(i) + fmt.Sprint(t.rows)
+		number, err := strconv.ParseFloat(cell.value, 64)
+		switch {
+		case cell.value == "":
+			continue
+		case cell.number && err == nil && !math.IsInf(number, 0) && !math.IsNaN(number):
+			fmt.Fprintf(&sb, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(number, 'f', -1, 64))
+		default:
+			style := ""
+			if bold {
+				style = ` s="1"`
+			}
+			fmt.Fprintf(&sb, `<c r="%s" t="inlineStr"%s><is><t xml:space="preserve">%s</t></is></c>`,
+				ref, style, xmlEscape(truncateRunes(cell.value, xlsxMaxCellLength)))
+		}
+	}
+	sb.WriteString("</row>")
+
+	_, err := io.WriteString(t.sheet, sb.String())
+	return err
+}
+
+func (t *xlsxTableWriter) Close() error {
+	if _, err := io.WriteString(t.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+	return t.zip.Close()
+}
+
+// xlsxColumnName returns the letters of a column, starting at A for 0.
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxSheetName returns a valid sheet name for a board title.
+func xlsxSheetName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return ' '
+		}
+		return r
+	}, title)
+	name = strings.Trim(truncateRunes(strings.TrimSpace(name), xlsxMaxSheetNameLength), "'")
+	if name == "" {
+		return "Cards"
+	}
+	return name
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
+
+// xmlEscape escapes a text, replacing the characters not allowed in XML.
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles has the default cell style and a bold one for the header.
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+// xlsxSheetStart freezes the header row of the sheet.
+const xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+	`<sheetData>`
+
+const xlsxSheetEnd = `</sheetData></worksheet>`
@@ -0,0 +1,27 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestXLSXColumnName(t *testing.T) {
+	require.Equal(t, "A", xlsxColumnName(0))
+	require.Equal(t, "Z", xlsxColumnName(25))
+	require.Equal(t, "AA", xlsxColumnName(26))
+	require.Equal(t, "AZ", xlsxColumnName(51))
+	require.Equal(t, "BA", xlsxColumnName(52))
+	require.Equal(t, "ZZ", xlsxColumnName(701))
+	require.Equal(t, "AAA", xlsxColumnName(702))
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	require.Equal(t, "Cards", xlsxSheetName(" "))
+	require.Equal(t, "Q1 plan  draft", xlsxSheetName("Q1 plan [draft]"))
+	require.Equal(t, "quoted", xlsxSheetName("'quoted'"))
+	require.Len(t, []rune(xlsxSheetName("a very long board title that doesn't fit")), 31)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// GetTrash returns the deleted boards of a team and the deleted cards of
+// its boards that the user can view, the most recently deleted first.
+func (a *App) GetTrash(userID, teamID string) ([]*model.TrashItem, error) {
+	items := []*model.TrashItem{}
+
+	deletedBoards, err := a.store.GetDeletedBoards(teamID)
+	if err != nil {
+		return nil, err
+	}
+	for _, board := range deletedBoards {
+		if a.permissions.HasPermissionToBoard(userID, board.ID, model.PermissionViewBoard) {
+			items = append(items, model.TrashItemFromBoard(board))
+		}
+	}
+
+	boards, err := a.store.GetBoardsForUserAndTeam(userID, teamID, true)
+	if err != nil {
+		return nil, err
+	}
+	boardIDs := []string{}
+	for _, board := range boards {
+		if a.permissions.HasPermissionToBoard(userID, board.ID, model.PermissionViewBoard) {
+			boardIDs = append(boardIDs, board.ID)
+		}
+	}
+
+	cards, err := a.store.GetDeletedBlocks(boardIDs, model.TypeCard)
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range cards {
+		items = append(items, model.TrashItemFromCard(card, teamID))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt > items[j].DeletedAt
+	})
+	return items, nil
+}
+
+// getDeletedBoard returns the last version of a board, if it is deleted.
+func (a *App) getDeletedBoard(boardID string) (*model.Board, error) {
+	boards, err := a.store.GetBoardHistory(boardID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(boards) == 0 || boards[0].DeleteAt == 0 {
+		return nil, model.NewErrNotFound("deleted board ID=" + boardID)
+	}
+	if _, err := a.store.GetBoard(boardID); !model.IsErrNotFound(err) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, model.NewErrNotFound("deleted board ID=" + boardID)
+	}
+	return boards[0], nil
+}
+
+// getDeletedCard returns the last version of a card of a board, if it is
+// deleted.
+func (a *App) getDeletedCard(boardID, cardID string) (*model.Block, error) {
+	blocks, err := a.store.GetBlockHistory(cardID, model.QueryBlockHistoryOptions{Limit: 1, Descending: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 || blocks[0].DeleteAt == 0 || blocks[0].BoardID != boardID || blocks[0].Type != model.TypeCard {
+		return nil, model.NewErrNotFound("deleted card ID=" + cardID)
+	}
+	if _, err := a.store.GetBlock(cardID); !model.IsErrNotFound(err) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, model.NewErrNotFound("deleted card ID=" + cardID)
+	}
+	return blocks[0], nil
+}
+
+// RestoreTrashedBoard restores a deleted board with its cards.
+func (a *App) RestoreTrashedBoard(boardID, userID string) (*model.Board, error) {
+	if _, err := a.getDeletedBoard(boardID); err != nil {
+		return nil, err
+	}
+	if err := a.UndeleteBoard(boardID, userID); err != nil {
+		return nil, err
+	}
+	return a.store.GetBoard(boardID)
+}
+
+// RestoreTrashedCard restores a deleted card of a board with its content.
+func (a *App) RestoreTrashedCard(boardID, cardID, userID string) (*model.Block, error) {
+	if _, err := a.getDeletedCard(boardID, cardID); err != nil {
+		return nil, err
+	}
+	return a.UndeleteBlock(cardID, userID)
+}
+
+// PurgeTrashedBoard permanently removes a deleted board and all its data.
+func (a *App) PurgeTrashedBoard(boardID string) error {
+	if _, err := a.getDeletedBoard(boardID); err != nil {
+		return err
+	}
+	return a.store.PurgeBoard(boardID)
+}
+
+// PurgeTrashedCard permanently removes a deleted card of a board with its
+// content and history.
+func (a *App) PurgeTrashedCard(boardID, cardID string) error {
+	if _, err := a.getDeletedCard(boardID, cardID); err != nil {
+		return err
+	}
+	return a.store.PurgeBlock(cardID)
+}
+
+// PurgeExpiredTrash permanently removes the boards and blocks deleted for
+// longer than the trash retention period. Nothing is purged with a
+// compliance license, as the purge removes the history of the items.
+func (a *App) PurgeExpiredTrash(now time.Time) {
+	if a.config.TrashRetentionDays <= 0 {
+		return
+	}
+
+	license := a.store.GetLicense()
+	if license != nil && license.Features.Compliance != nil && *license.Features.Compliance {
+		a.logger.Debug("PurgeExpiredTrash skipped, the compliance export requires the full history")
+		return
+	}
+
+	deletedBefore := utils.GetMillisForTime(now.AddDate(0, 0, -a.config.TrashRetentionDays))
+	purged, err := a.store.PurgeTrash(deletedBefore)
+	if err != nil {
+		a.logger.Error("PurgeExpiredTrash cannot purge the trash", mlog.Int("purged", purged), mlog.Err(err))
+		return
+	}
+	if purged > 0 {
+		a.logger.Info("Purged expired trash", mlog.Int("purged", purged))
+	}
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("purges the items deleted before the retention period", func(t *testing.T) {
+		th.App.config.TrashRetentionDays = 30
+		cutoff := utils.GetMillisForTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+		th.Store.EXPECT().GetLicense().Return(nil)
+		th.Store.EXPECT().PurgeTrash(cutoff).Return(int64(3), nil)
+
+		th.App.PurgeExpiredTrash(now)
+	})
+
+	t.Run("keeps the trash for compliance", func(t *testing.T) {
+		th.App.config.TrashRetentionDays = 30
+		th.Store.EXPECT().GetLicense().Return(&mmModel.License{
+			Features: &mmModel.Features{Compliance: mmModel.NewBool(true)},
+		})
+		th.Store.EXPECT().PurgeTrash(gomock.Any()).Times(0)
+
+		th.App.PurgeExpiredTrash(now)
+	})
+
+	t.Run("does nothing when disabled", func(t *testing.T) {
+		th.App.config.TrashRetentionDays = 0
+		th.Store.EXPECT().PurgeTrash(gomock.Any()).Times(0)
+
+		th.App.PurgeExpiredTrash(now)
+	})
+}
+
+func TestPurgeTrashedCard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	deleted := &model.Block{ID: "card-id", BoardID: testBoardID, Type: model.TypeCard, DeleteAt: 100}
+
+	t.Run("purges a deleted card", func(t *testing.T) {
+		th.Store.EXPECT().GetBlockHistory("card-id", gomock.Any()).Return([]*model.Block{deleted}, nil)
+		th.Store.EXPECT().GetBlock("card-id").Return(nil, model.NewErrNotFound("card-id"))
+		th.Store.EXPECT().PurgeBlock("card-id").Return(nil)
+
+		require.NoError(t, th.App.PurgeTrashedCard(testBoardID, "card-id"))
+	})
+
+	t.Run("a card of another board is not found", func(t *testing.T) {
+		th.Store.EXPECT().GetBlockHistory("card-id", gomock.Any()).Return([]*model.Block{deleted}, nil)
+
+		err := th.App.PurgeTrashedCard("other-board", "card-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("a card that is not deleted is not found", func(t *testing.T) {
+		th.Store.EXPECT().GetBlockHistory("card-id", gomock.Any()).Return([]*model.Block{deleted}, nil)
+		th.Store.EXPECT().GetBlock("card-id").Return(&model.Block{ID: "card-id"}, nil)
+
+		err := th.App.PurgeTrashedCard(testBoardID, "card-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
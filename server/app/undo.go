@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// RecordUndoEntry adds a change made by a user to their undo stack on a
+// board. Failing to record a change doesn't fail the change itself.
+func (a *App) RecordUndoEntry(userID, boardID string, operation model.UndoOperation, boardChange *model.BoardChange, blockChanges []model.BlockChange) {
+	if a.config == nil || a.config.UndoStackSize <= 0 || userID == "" || userID == model.SystemUserID {
+		return
+	}
+
+	if blockChanges == nil {
+		blockChanges = []model.BlockChange{}
+	}
+	entry := &model.UndoEntry{
+		ID:           utils.NewID(utils.IDTypeNone),
+		UserID:       userID,
+		BoardID:      boardID,
+		Operation:    operation,
+		BoardChange:  boardChange,
+		BlockChanges: blockChanges,
+	}
+	if entry.IsEmpty() {
+		return
+	}
+
+	if err := a.store.InsertUndoEntry(entry, a.config.UndoStackSize); err != nil {
+		a.logger.Error("Unable to record undo entry",
+			mlog.String("user_id", userID),
+			mlog.String("board_id", boardID),
+			mlog.Err(err),
+		)
+	}
+}
+
+// RecordUndoBlockPatches records the patch of some blocks, grouped by
+// board, from their versions before the patch.
+func (a *App) RecordUndoBlockPatches(userID string, oldBlocks []*model.Block) {
+	changes := map[string][]model.BlockChange{}
+	boardIDs := []string{}
+	for _, oldBlock := range oldBlocks {
+		block, err := a.store.GetBlock(oldBlock.ID)
+		if err != nil {
+			a.logger.Error("Unable to record undo entry", mlog.String("block_id", oldBlock.ID), mlog.Err(err))
+			return
+		}
+		if _, ok := changes[block.BoardID]; !ok {
+			boardIDs = append(boardIDs, block.BoardID)
+		}
+		changes[block.BoardID] = append(changes[block.BoardID], model.BlockChange{Before: oldBlock, After: block})
+	}
+
+	for _, boardID := range boardIDs {
+		a.RecordUndoEntry(userID, boardID, model.UndoOperationPatchBlocks, nil, changes[boardID])
+	}
+}
+
+func (a *App) GetUndoState(userID, boardID string) (*model.UndoState, error) {
+	return a.store.GetUndoState(userID, boardID)
+}
+
+// Undo reverts the last change of a user on a board.
+func (a *App) Undo(userID, boardID string, force bool) (*model.UndoResult, error) {
+	return a.applyUndoEntry(userID, boardID, false, force)
+}
+
+// Redo applies again the last change undone by a user on a board.
+func (a *App) Redo(userID, boardID string, force bool) (*model.UndoResult, error) {
+	return a.applyUndoEntry(userID, boardID, true, force)
+}
+
+func (a *App) applyUndoEntry(userID, boardID string, redo, force bool) (*model.UndoResult, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.store.ApplyUndoEntry(userID, boardID, redo, force)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Board != nil {
+		board = result.Board
+		a.updateBoardComputedProperties(board, userID)
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		if result.Board != nil {
+			a.wsAdapter.BroadcastBoardChange(board.TeamID, board)
+		}
+		for _, block := range result.Blocks {
+			a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
+			a.webhook.NotifyUpdate(block)
+		}
+		for _, blockID := range result.DeletedBlockIDs {
+			a.wsAdapter.BroadcastBlockDelete(board.TeamID, blockID, boardID)
+		}
+		return nil
+	})
+
+	a.metrics.IncrementBlocksPatched(len(result.Blocks))
+	return result, nil
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/mattermost/focalboard/server/api"
@@ -396,6 +398,551 @@ func (c *Client) GetCard(cardID string) (*model.Card, *Response) {
 	return card, BuildResponse(r)
 }
 
+func (c *Client) GetCardDependencies(cardID string) (*model.CardDependencies, *Response) {
+	r, err := c.DoAPIGet(c.GetCardRoute(cardID)+"/dependencies", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var dependencies *model.CardDependencies
+	if err := json.NewDecoder(r.Body).Decode(&dependencies); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return dependencies, BuildResponse(r)
+}
+
+func (c *Client) AddCardBlocker(cardID, blockerID string) (*model.CardDependency, *Response) {
+	dependency := &model.CardDependency{BlockerID: blockerID}
+	r, err := c.DoAPIPost(c.GetCardRoute(cardID)+"/blockers", toJSON(dependency))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var dependencyNew *model.CardDependency
+	if err := json.NewDecoder(r.Body).Decode(&dependencyNew); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return dependencyNew, BuildResponse(r)
+}
+
+func (c *Client) DeleteCardBlocker(cardID, blockerID string) *Response {
+	r, err := c.DoAPIDelete(fmt.Sprintf("%s/blockers/%s", c.GetCardRoute(cardID), blockerID), "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+func (c *Client) GetCardRecurrence(cardID string) (*model.CardRecurrence, *Response) {
+	r, err := c.DoAPIGet(c.GetCardRoute(cardID)+"/recurrence", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var recurrence *model.CardRecurrence
+	if err := json.NewDecoder(r.Body).Decode(&recurrence); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return recurrence, BuildResponse(r)
+}
+
+func (c *Client) SetCardRecurrence(cardID string, recurrence *model.CardRecurrence) (*model.CardRecurrence, *Response) {
+	r, err := c.DoAPIPut(c.GetCardRoute(cardID)+"/recurrence", toJSON(recurrence))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var recurrenceNew *model.CardRecurrence
+	if err := json.NewDecoder(r.Body).Decode(&recurrenceNew); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return recurrenceNew, BuildResponse(r)
+}
+
+func (c *Client) DeleteCardRecurrence(cardID string) *Response {
+	r, err := c.DoAPIDelete(c.GetCardRoute(cardID)+"/recurrence", "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+func (c *Client) GetAutomationRulesRoute(boardID string) string {
+	return fmt.Sprintf("%s/automations", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetAutomationRules(boardID string) ([]*model.AutomationRule, *Response) {
+	r, err := c.DoAPIGet(c.GetAutomationRulesRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var rules []*model.AutomationRule
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return rules, BuildResponse(r)
+}
+
+func (c *Client) CreateAutomationRule(boardID string, rule *model.AutomationRule) (*model.AutomationRule, *Response) {
+	r, err := c.DoAPIPost(c.GetAutomationRulesRoute(boardID), toJSON(rule))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var ruleNew *model.AutomationRule
+	if err := json.NewDecoder(r.Body).Decode(&ruleNew); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return ruleNew, BuildResponse(r)
+}
+
+func (c *Client) UpdateAutomationRule(boardID string, rule *model.AutomationRule) (*model.AutomationRule, *Response) {
+	r, err := c.DoAPIPut(c.GetAutomationRulesRoute(boardID)+"/"+rule.ID, toJSON(rule))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var ruleNew *model.AutomationRule
+	if err := json.NewDecoder(r.Body).Decode(&ruleNew); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return ruleNew, BuildResponse(r)
+}
+
+func (c *Client) DeleteAutomationRule(boardID, ruleID string) *Response {
+	r, err := c.DoAPIDelete(c.GetAutomationRulesRoute(boardID)+"/"+ruleID, "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+func (c *Client) TestAutomationRule(boardID, ruleID, cardID string) (*model.AutomationExecution, *Response) {
+	r, err := c.DoAPIPost(c.GetAutomationRulesRoute(boardID)+"/"+ruleID+"/test", toJSON(map[string]string{"cardId": cardID}))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var execution *model.AutomationExecution
+	if err := json.NewDecoder(r.Body).Decode(&execution); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return execution, BuildResponse(r)
+}
+
+func (c *Client) GetAutomationExecutions(boardID, ruleID string) ([]*model.AutomationExecution, *Response) {
+	r, err := c.DoAPIGet(c.GetAutomationRulesRoute(boardID)+"/"+ruleID+"/executions", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var executions []*model.AutomationExecution
+	if err := json.NewDecoder(r.Body).Decode(&executions); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return executions, BuildResponse(r)
+}
+
+func (c *Client) GetArchivedPropertyValuesRoute(boardID string) string {
+	return fmt.Sprintf("%s/archived-values", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetArchivedPropertyValues(boardID string) ([]*model.ArchivedPropertyValue, *Response) {
+	r, err := c.DoAPIGet(c.GetArchivedPropertyValuesRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var values []*model.ArchivedPropertyValue
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return values, BuildResponse(r)
+}
+
+func (c *Client) RestoreArchivedPropertyValues(boardID, propertyID string) ([]*model.Block, *Response) {
+	r, err := c.DoAPIPost(c.GetArchivedPropertyValuesRoute(boardID)+"/"+propertyID+"/restore", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	return model.BlocksFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBoardVersions(boardID string, before int64, perPage int) ([]*model.BoardVersion, *Response) {
+	route := fmt.Sprintf("%s/versions?per_page=%d", c.GetBoardRoute(boardID), perPage)
+	if before != 0 {
+		route += fmt.Sprintf("&before=%d", before)
+	}
+
+	r, err := c.DoAPIGet(route, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var versions []*model.BoardVersion
+	if err := json.NewDecoder(r.Body).Decode(&versions); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return versions, BuildResponse(r)
+}
+
+func (c *Client) GetBoardSnapshot(boardID string, at int64) (*model.BoardSnapshot, *Response) {
+	r, err := c.DoAPIGet(fmt.Sprintf("%s/snapshot?at=%d", c.GetBoardRoute(boardID), at), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var snapshot *model.BoardSnapshot
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return snapshot, BuildResponse(r)
+}
+
+func (c *Client) DiffBoardSnapshots(boardID string, from, to int64) (*model.BoardSnapshotDiff, *Response) {
+	r, err := c.DoAPIGet(fmt.Sprintf("%s/diff?from=%d&to=%d", c.GetBoardRoute(boardID), from, to), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var diff *model.BoardSnapshotDiff
+	if err := json.NewDecoder(r.Body).Decode(&diff); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return diff, BuildResponse(r)
+}
+
+func (c *Client) RestoreBoardSnapshot(boardID string, req *model.BoardSnapshotRestoreRequest) (*model.BoardSnapshotRestore, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/restore", toJSON(req))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var restore *model.BoardSnapshotRestore
+	if err := json.NewDecoder(r.Body).Decode(&restore); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return restore, BuildResponse(r)
+}
+
+func (c *Client) GetUndoState(boardID string) (*model.UndoState, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/undo", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var state *model.UndoState
+	if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return state, BuildResponse(r)
+}
+
+func (c *Client) Undo(boardID string, force bool) (*model.UndoResult, *Response) {
+	return c.applyUndoEntry(boardID, "undo", force)
+}
+
+func (c *Client) Redo(boardID string, force bool) (*model.UndoResult, *Response) {
+	return c.applyUndoEntry(boardID, "redo", force)
+}
+
+func (c *Client) applyUndoEntry(boardID, action string, force bool) (*model.UndoResult, *Response) {
+	route := c.GetBoardRoute(boardID) + "/" + action
+	if force {
+		route += "?force=true"
+	}
+
+	r, err := c.DoAPIPost(route, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.UndoResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
+func (c *Client) GetTrash(teamID string) ([]*model.TrashItem, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/trash", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var items []*model.TrashItem
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return items, BuildResponse(r)
+}
+
+func (c *Client) getTrashedBoardRoute(boardID string) string {
+	return "/trash/boards/" + boardID
+}
+
+func (c *Client) RestoreTrashedBoard(boardID string) (*model.Board, *Response) {
+	r, err := c.DoAPIPost(c.getTrashedBoardRoute(boardID)+"/restore", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var board *model.Board
+	if err := json.NewDecoder(r.Body).Decode(&board); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return board, BuildResponse(r)
+}
+
+func (c *Client) PurgeTrashedBoard(boardID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.getTrashedBoardRoute(boardID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) RestoreTrashedCard(boardID, cardID string) (*model.Block, *Response) {
+	r, err := c.DoAPIPost(c.getTrashedBoardRoute(boardID)+"/cards/"+cardID+"/restore", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var block *model.Block
+	if err := json.NewDecoder(r.Body).Decode(&block); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return block, BuildResponse(r)
+}
+
+func (c *Client) PurgeTrashedCard(boardID, cardID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.getTrashedBoardRoute(boardID)+"/cards/"+cardID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, *Response) {
+	r, err := c.DoAPIGet("/retentionpolicies?team_id="+teamID, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var policies []*model.RetentionPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policies); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return policies, BuildResponse(r)
+}
+
+func (c *Client) PreviewDataRetention(teamID string) ([]*model.RetentionCandidate, *Response) {
+	r, err := c.DoAPIGet("/retentionpolicies/preview?team_id="+teamID, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var candidates []*model.RetentionCandidate
+	if err := json.NewDecoder(r.Body).Decode(&candidates); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return candidates, BuildResponse(r)
+}
+
+func (c *Client) UpsertTeamRetentionPolicy(teamID string, retentionDays int) (*model.RetentionPolicy, *Response) {
+	return c.upsertRetentionPolicy(c.GetTeamRoute(teamID)+"/retentionpolicy", retentionDays)
+}
+
+func (c *Client) UpsertBoardRetentionPolicy(boardID string, retentionDays int) (*model.RetentionPolicy, *Response) {
+	return c.upsertRetentionPolicy(c.GetBoardRoute(boardID)+"/retentionpolicy", retentionDays)
+}
+
+func (c *Client) upsertRetentionPolicy(route string, retentionDays int) (*model.RetentionPolicy, *Response) {
+	r, err := c.DoAPIPut(route, toJSON(&model.RetentionPolicy{RetentionDays: retentionDays}))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var policy *model.RetentionPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return policy, BuildResponse(r)
+}
+
+func (c *Client) DeleteTeamRetentionPolicy(teamID string) (bool, *Response) {
+	return c.deleteRetentionPolicy(c.GetTeamRoute(teamID) + "/retentionpolicy")
+}
+
+func (c *Client) DeleteBoardRetentionPolicy(boardID string) (bool, *Response) {
+	return c.deleteRetentionPolicy(c.GetBoardRoute(boardID) + "/retentionpolicy")
+}
+
+func (c *Client) deleteRetentionPolicy(route string) (bool, *Response) {
+	r, err := c.DoAPIDelete(route, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetBackups() ([]*model.BackupInfo, *Response) {
+	r, err := c.DoAPIGet("/backups", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var backups []*model.BackupInfo
+	if err := json.NewDecoder(r.Body).Decode(&backups); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return backups, BuildResponse(r)
+}
+
+func (c *Client) CreateBackup() (*model.BackupInfo, *Response) {
+	r, err := c.DoAPIPost("/backups", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var backup *model.BackupInfo
+	if err := json.NewDecoder(r.Body).Decode(&backup); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return backup, BuildResponse(r)
+}
+
+func auditRecordsQuery(opts model.QueryAuditRecordsOptions) string {
+	values := url.Values{}
+	for key, value := range map[string]string{
+		"user_id":  opts.UserID,
+		"event":    opts.Event,
+		"board_id": opts.BoardID,
+		"status":   opts.Status,
+		"cursor":   opts.Cursor,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if opts.Since != 0 {
+		values.Set("since", strconv.FormatInt(opts.Since, 10))
+	}
+	if opts.Until != 0 {
+		values.Set("until", strconv.FormatInt(opts.Until, 10))
+	}
+	if opts.PerPage != 0 {
+		values.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
+
+func (c *Client) GetAuditRecords(opts model.QueryAuditRecordsOptions) (*model.AuditRecordsResponse, *Response) {
+	r, err := c.DoAPIGet("/audit_records"+auditRecordsQuery(opts), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var response *model.AuditRecordsResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return response, BuildResponse(r)
+}
+
+func (c *Client) ExportAuditRecords(opts model.QueryAuditRecordsOptions) ([]byte, *Response) {
+	r, err := c.DoAPIGet("/audit_records/export"+auditRecordsQuery(opts), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
 //
 // Boards and blocks.
 //
@@ -1027,3 +1574,224 @@ func (c *Client) UnhideBoard(teamID, categoryID, boardID string) *Response {
 	defer closeBody(r)
 	return BuildResponse(r)
 }
+
+func (c *Client) ArchiveCard(cardID string) (*model.Card, *Response) {
+	return c.setCardArchived(cardID, "archive")
+}
+
+func (c *Client) UnarchiveCard(cardID string) (*model.Card, *Response) {
+	return c.setCardArchived(cardID, "unarchive")
+}
+
+func (c *Client) setCardArchived(cardID, action string) (*model.Card, *Response) {
+	r, err := c.DoAPIPost(c.GetCardRoute(cardID)+"/"+action, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var card *model.Card
+	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return card, BuildResponse(r)
+}
+
+func (c *Client) ArchiveBoard(boardID string) (*model.Board, *Response) {
+	return c.setBoardArchived(boardID, "archive")
+}
+
+func (c *Client) UnarchiveBoard(boardID string) (*model.Board, *Response) {
+	return c.setBoardArchived(boardID, "unarchive")
+}
+
+func (c *Client) setBoardArchived(boardID, action string) (*model.Board, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/"+action, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetCardsIncludingArchived(boardID string, page int, perPage int) ([]*model.Card, *Response) {
+	url := fmt.Sprintf("%s/cards?page=%d&per_page=%d&include_archived=true", c.GetBoardRoute(boardID), page, perPage)
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var cards []*model.Card
+	if err := json.NewDecoder(r.Body).Decode(&cards); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return cards, BuildResponse(r)
+}
+
+func (c *Client) GetBoardsForTeamIncludingArchived(teamID string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards?include_archived=true", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetAllBlocksForBoardIncludingArchived(boardID string) ([]*model.Block, *Response) {
+	r, err := c.DoAPIGet(c.GetAllBlocksRoute(boardID)+"&include_archived=true", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BlocksFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetCardTemplateVariables(templateID string) (*model.CardTemplateVariables, *Response) {
+	r, err := c.DoAPIGet(c.GetCardRoute(templateID)+"/template_variables", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var variables *model.CardTemplateVariables
+	if err := json.NewDecoder(r.Body).Decode(&variables); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return variables, BuildResponse(r)
+}
+
+func (c *Client) CreateCardFromTemplate(templateID string, variables map[string]string) (*model.Card, *Response) {
+	request := model.CardFromTemplateRequest{Variables: variables}
+	r, err := c.DoAPIPost(c.GetCardRoute(templateID)+"/instantiate", toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var card *model.Card
+	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return card, BuildResponse(r)
+}
+
+func (c *Client) StartBulkOperation(boardID string, request *model.BulkOperationRequest) (*model.BulkOperation, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/cards/bulk", toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var operation *model.BulkOperation
+	if err := json.NewDecoder(r.Body).Decode(&operation); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return operation, BuildResponse(r)
+}
+
+func (c *Client) GetBulkOperation(operationID string) (*model.BulkOperation, *Response) {
+	r, err := c.DoAPIGet("/bulk_operations/"+operationID, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var operation *model.BulkOperation
+	if err := json.NewDecoder(r.Body).Decode(&operation); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return operation, BuildResponse(r)
+}
+
+func (c *Client) PreviewCardMove(cardID string, request *model.CardMoveRequest) (*model.CardMoveMapping, *Response) {
+	r, err := c.DoAPIPost(c.GetCardRoute(cardID)+"/move/preview", toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var mapping *model.CardMoveMapping
+	if err := json.NewDecoder(r.Body).Decode(&mapping); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return mapping, BuildResponse(r)
+}
+
+func (c *Client) MoveCard(cardID string, request *model.CardMoveRequest) (*model.CardMoveResult, *Response) {
+	r, err := c.DoAPIPost(c.GetCardRoute(cardID)+"/move", toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.CardMoveResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
+func (c *Client) ImportCSV(boardID string, data io.Reader, opts *model.CSVImportOptions) (*model.CSVImportResult, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "cards.csv")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	if opts != nil {
+		if err = writer.WriteField(api.CSVImportOptionsFormKey, toJSON(opts)); err != nil {
+			return nil, &Response{Error: err}
+		}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetBoardRoute(boardID)+"/import/csv", body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.CSVImportResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
+func (c *Client) ExportBoard(boardID string, format model.BoardExportFormat, viewID string) ([]byte, *Response) {
+	query := url.Values{"format": {string(format)}}
+	if viewID != "" {
+		query.Set("view", viewID)
+	}
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/export?"+query.Encode(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
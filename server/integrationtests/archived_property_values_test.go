@@ -0,0 +1,73 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchivedPropertyValues(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	notesProperty := map[string]interface{}{"id": "notes", "name": "Notes", "type": "text"}
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID:         testTeamID,
+		Type:           model.BoardTypeOpen,
+		CardProperties: []map[string]interface{}{notesProperty},
+	})
+	th.CheckOK(resp)
+
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{
+		BoardID:    board.ID,
+		Title:      "card",
+		Properties: map[string]any{"notes": "some notes"},
+	}, true)
+	th.CheckOK(resp)
+
+	_, resp = th.Client.PatchBoard(board.ID, &model.BoardPatch{
+		DeletedCardProperties: []string{"notes"},
+		ArchiveRemovedValues:  true,
+	})
+	th.CheckOK(resp)
+
+	fetched, resp := th.Client.GetCard(card.ID)
+	th.CheckOK(resp)
+	require.NotContains(t, fetched.Properties, "notes")
+
+	t.Run("list archived values", func(t *testing.T) {
+		values, resp := th.Client.GetArchivedPropertyValues(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, values, 1)
+		require.Equal(t, card.ID, values[0].CardID)
+		require.Equal(t, "some notes", values[0].Value)
+
+		_, resp = th.Client2.GetArchivedPropertyValues(board.ID)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("restore needs the property on the board", func(t *testing.T) {
+		_, resp := th.Client.RestoreArchivedPropertyValues(board.ID, "notes")
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("restore values", func(t *testing.T) {
+		_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{notesProperty},
+		})
+		th.CheckOK(resp)
+
+		restored, resp := th.Client.RestoreArchivedPropertyValues(board.ID, "notes")
+		th.CheckOK(resp)
+		require.Len(t, restored, 1)
+
+		fetched, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "some notes", fetched.Properties["notes"])
+
+		values, resp := th.Client.GetArchivedPropertyValues(board.ID)
+		th.CheckOK(resp)
+		require.Empty(t, values)
+	})
+}
@@ -0,0 +1,109 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiving(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypePrivate,
+		Title:  "board",
+	})
+	th.CheckOK(resp)
+
+	// waits to avoid hitting the pk uniqueness constraint in history
+	wait := func() { time.Sleep(10 * time.Millisecond) }
+
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: "finished"}, true)
+	th.CheckOK(resp)
+	other, resp := th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: "ongoing"}, true)
+	th.CheckOK(resp)
+	wait()
+
+	t.Run("users without access can't archive", func(t *testing.T) {
+		_, resp := th.Client2.ArchiveCard(card.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.ArchiveBoard(board.ID)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("archive a card", func(t *testing.T) {
+		archived, resp := th.Client.ArchiveCard(card.ID)
+		th.CheckOK(resp)
+		require.NotZero(t, archived.ArchiveAt)
+		wait()
+
+		cards, resp := th.Client.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		require.Equal(t, other.ID, cards[0].ID)
+
+		cards, resp = th.Client.GetCardsIncludingArchived(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 2)
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.NotContains(t, blockIDs(blocks), card.ID)
+
+		blocks, resp = th.Client.GetAllBlocksForBoardIncludingArchived(board.ID)
+		th.CheckOK(resp)
+		require.Contains(t, blockIDs(blocks), card.ID)
+	})
+
+	t.Run("unarchive a card", func(t *testing.T) {
+		unarchived, resp := th.Client.UnarchiveCard(card.ID)
+		th.CheckOK(resp)
+		require.Zero(t, unarchived.ArchiveAt)
+		wait()
+
+		cards, resp := th.Client.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 2)
+	})
+
+	t.Run("archive a board", func(t *testing.T) {
+		archived, resp := th.Client.ArchiveBoard(board.ID)
+		th.CheckOK(resp)
+		require.NotZero(t, archived.ArchiveAt)
+		wait()
+
+		boards, resp := th.Client.GetBoardsForTeam(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, boards)
+
+		boards, resp = th.Client.GetBoardsForTeamIncludingArchived(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, boards, 1)
+
+		// the archived boards are still searchable
+		boards, resp = th.Client.SearchBoardsForUser(testTeamID, "board", model.BoardSearchFieldTitle)
+		th.CheckOK(resp)
+		require.Len(t, boards, 1)
+
+		unarchived, resp := th.Client.UnarchiveBoard(board.ID)
+		th.CheckOK(resp)
+		require.Zero(t, unarchived.ArchiveAt)
+
+		boards, resp = th.Client.GetBoardsForTeam(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, boards, 1)
+	})
+}
+
+func blockIDs(blocks []*model.Block) []string {
+	ids := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		ids = append(ids, block.ID)
+	}
+	return ids
+}
@@ -0,0 +1,86 @@
+package integrationtests
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRecords(t *testing.T) {
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	th.Client = clients.TeamMember
+
+	boards := []*model.Board{}
+	for i := 0; i < 3; i++ {
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		_, resp := th.Client.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+		boards = append(boards, board)
+	}
+
+	deleted := model.QueryAuditRecordsOptions{UserID: userTeamMemberID, Event: "deleteBoard"}
+	require.Eventually(t, func() bool {
+		response, resp := clients.Admin.GetAuditRecords(deleted)
+		return resp.Error == nil && len(response.Results) == 3
+	}, 5*time.Second, 50*time.Millisecond)
+
+	t.Run("only system admins can read the audit records", func(t *testing.T) {
+		_, resp := clients.TeamMember.GetAuditRecords(deleted)
+		th.CheckForbidden(resp)
+
+		_, resp = clients.TeamMember.ExportAuditRecords(deleted)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("filter by board and status", func(t *testing.T) {
+		response, resp := clients.Admin.GetAuditRecords(model.QueryAuditRecordsOptions{
+			Event:   "deleteBoard",
+			BoardID: boards[1].ID,
+			Status:  "success",
+		})
+		th.CheckOK(resp)
+		require.Len(t, response.Results, 1)
+		require.False(t, response.HasNext)
+		require.Equal(t, userTeamMemberID, response.Results[0].UserID)
+		require.Equal(t, boards[1].ID, response.Results[0].Meta["boardID"])
+	})
+
+	t.Run("paginate with a cursor", func(t *testing.T) {
+		opts := deleted
+		opts.PerPage = 2
+		first, resp := clients.Admin.GetAuditRecords(opts)
+		th.CheckOK(resp)
+		require.Len(t, first.Results, 2)
+		require.True(t, first.HasNext)
+		require.Equal(t, boards[2].ID, first.Results[0].BoardID)
+
+		opts.Cursor = first.NextCursor
+		second, resp := clients.Admin.GetAuditRecords(opts)
+		th.CheckOK(resp)
+		require.Len(t, second.Results, 1)
+		require.False(t, second.HasNext)
+		require.Equal(t, boards[0].ID, second.Results[0].BoardID)
+
+		opts.Cursor = "not a cursor"
+		_, resp = clients.Admin.GetAuditRecords(opts)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("export as CSV", func(t *testing.T) {
+		data, resp := clients.Admin.ExportAuditRecords(deleted)
+		th.CheckOK(resp)
+
+		rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+		require.Equal(t, "board_id", rows[0][12])
+		require.Equal(t, boards[2].ID, rows[1][12])
+		require.Equal(t, "deleteBoard", rows[3][4])
+	})
+}
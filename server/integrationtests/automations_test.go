@@ -0,0 +1,324 @@
+package integrationtests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationRules(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "done", "value": "Done"},
+			}},
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "due", "name": "Due", "type": "date"},
+		},
+	})
+	th.CheckOK(resp)
+
+	getCard := func(cardID string) *model.Card {
+		card, resp := th.Client.GetCard(cardID)
+		th.CheckOK(resp)
+		return card
+	}
+
+	t.Run("invalid rules", func(t *testing.T) {
+		_, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: "unknown"},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionPostComment, Text: "hi"}},
+		})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: "unknown"}},
+		})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerDateReached, PropertyID: "status"},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionPostComment, Text: "hi"}},
+		})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("run rules on card changes", func(t *testing.T) {
+		created, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Title:   "assign new cards",
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			Actions: []model.AutomationAction{
+				{Type: model.AutomationActionAssignPerson, PropertyID: "owner", Value: th.GetUser1().ID},
+				{Type: model.AutomationActionSetProperty, PropertyID: "status", Value: "todo"},
+			},
+		})
+		th.CheckOK(resp)
+		require.NotEmpty(t, created.ID)
+		require.Equal(t, th.GetUser1().ID, created.CreatedBy)
+
+		done, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Title:   "checklist when done",
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "status", Value: "done"},
+			Actions: []model.AutomationAction{
+				{Type: model.AutomationActionAddChecklist, Items: []string{"review", "ship"}},
+				{Type: model.AutomationActionPostComment, Text: "Card done"},
+			},
+		})
+		th.CheckOK(resp)
+
+		rules, resp := th.Client.GetAutomationRules(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, rules, 2)
+
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "new card"}, false)
+		th.CheckOK(resp)
+
+		require.Eventually(t, func() bool {
+			props := getCard(card.ID).Properties
+			return props["owner"] == th.GetUser1().ID && props["status"] == "todo"
+		}, 5*time.Second, 50*time.Millisecond)
+
+		_, resp = th.Client.PatchCard(card.ID, &model.CardPatch{
+			UpdatedProperties: map[string]any{"status": "done"},
+		}, false)
+		th.CheckOK(resp)
+
+		require.Eventually(t, func() bool {
+			return len(getCard(card.ID).ContentOrder) == 2
+		}, 5*time.Second, 50*time.Millisecond)
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		comments := 0
+		checkboxes := 0
+		for _, block := range blocks {
+			if block.ParentID != card.ID {
+				continue
+			}
+			switch block.Type {
+			case model.TypeComment:
+				comments++
+				require.Equal(t, "Card done", block.Title)
+			case model.TypeCheckbox:
+				checkboxes++
+			}
+		}
+		require.Equal(t, 1, comments)
+		require.Equal(t, 2, checkboxes)
+
+		executions, resp := th.Client.GetAutomationExecutions(board.ID, done.ID)
+		th.CheckOK(resp)
+		require.Len(t, executions, 1)
+		require.Equal(t, model.AutomationExecutionSuccess, executions[0].Status)
+		require.Equal(t, card.ID, executions[0].CardID)
+		require.Len(t, executions[0].Results, 2)
+
+		// disabled rules don't run
+		created.Enabled = false
+		_, resp = th.Client.UpdateAutomationRule(board.ID, created)
+		th.CheckOK(resp)
+		done.Enabled = false
+		_, resp = th.Client.UpdateAutomationRule(board.ID, done)
+		th.CheckOK(resp)
+
+		other, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "other card"}, false)
+		th.CheckOK(resp)
+		time.Sleep(200 * time.Millisecond)
+		require.Empty(t, getCard(other.ID).Properties["owner"])
+
+		th.CheckOK(th.Client.DeleteAutomationRule(board.ID, created.ID))
+		th.CheckOK(th.Client.DeleteAutomationRule(board.ID, done.ID))
+		th.CheckNotFound(th.Client.DeleteAutomationRule(board.ID, done.ID))
+	})
+
+	t.Run("rules triggering each other don't loop", func(t *testing.T) {
+		toDone, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "status", Value: "todo"},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: "status", Value: "done"}},
+		})
+		th.CheckOK(resp)
+		toTodo, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "status", Value: "done"},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: "status", Value: "todo"}},
+		})
+		th.CheckOK(resp)
+
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "looping card"}, false)
+		th.CheckOK(resp)
+		_, resp = th.Client.PatchCard(card.ID, &model.CardPatch{
+			UpdatedProperties: map[string]any{"status": "todo"},
+		}, false)
+		th.CheckOK(resp)
+
+		// todo -> done by the first rule, done -> todo by the second one,
+		// then the first rule already ran for the card and the chain stops
+		require.Eventually(t, func() bool {
+			executions, resp := th.Client.GetAutomationExecutions(board.ID, toTodo.ID)
+			th.CheckOK(resp)
+			return len(executions) == 1
+		}, 5*time.Second, 50*time.Millisecond)
+		time.Sleep(200 * time.Millisecond)
+
+		executions, resp := th.Client.GetAutomationExecutions(board.ID, toDone.ID)
+		th.CheckOK(resp)
+		require.Len(t, executions, 1)
+		require.Equal(t, "todo", getCard(card.ID).Properties["status"])
+
+		th.CheckOK(th.Client.DeleteAutomationRule(board.ID, toDone.ID))
+		th.CheckOK(th.Client.DeleteAutomationRule(board.ID, toTodo.ID))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		called := false
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer ts.Close()
+
+		rule, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Enabled: false,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCommentAdded},
+			Actions: []model.AutomationAction{
+				{Type: model.AutomationActionSetProperty, PropertyID: "status", Value: "done"},
+				{Type: model.AutomationActionCallWebhook, URL: ts.URL},
+			},
+		})
+		th.CheckOK(resp)
+
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "dry run card"}, false)
+		th.CheckOK(resp)
+
+		execution, resp := th.Client.TestAutomationRule(board.ID, rule.ID, card.ID)
+		th.CheckOK(resp)
+		require.True(t, execution.DryRun)
+		require.Equal(t, model.AutomationExecutionSuccess, execution.Status)
+		require.Len(t, execution.Results, 2)
+		require.Equal(t, `set Status to "Done"`, execution.Results[0].Detail)
+
+		require.False(t, called)
+		require.Empty(t, getCard(card.ID).Properties["status"])
+
+		executions, resp := th.Client.GetAutomationExecutions(board.ID, rule.ID)
+		th.CheckOK(resp)
+		require.Empty(t, executions)
+	})
+
+	t.Run("date reached and webhook", func(t *testing.T) {
+		var mux sync.Mutex
+		payloads := []map[string]interface{}{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			mux.Lock()
+			payloads = append(payloads, payload)
+			mux.Unlock()
+		}))
+		defer ts.Close()
+
+		rule, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerDateReached, PropertyID: "due"},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionCallWebhook, URL: ts.URL}},
+		})
+		th.CheckOK(resp)
+
+		now := time.Now()
+		due := utils.GetMillisForTime(now.Add(-time.Hour))
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "due card",
+			Properties: map[string]any{"due": fmt.Sprintf(`{"from":%d}`, due)},
+		}, false)
+		th.CheckOK(resp)
+		_, resp = th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "later card",
+			Properties: map[string]any{"due": fmt.Sprintf(`{"from":%d}`, utils.GetMillisForTime(now.Add(time.Hour)))},
+		}, false)
+		th.CheckOK(resp)
+
+		th.Server.App().RunDateReachedAutomations(now)
+		th.Server.App().RunDateReachedAutomations(now)
+
+		// the webhooks are called in the background
+		require.Eventually(t, func() bool {
+			mux.Lock()
+			defer mux.Unlock()
+			return len(payloads) != 0
+		}, 5*time.Second, 10*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+
+		mux.Lock()
+		defer mux.Unlock()
+		require.Len(t, payloads, 1)
+		require.Equal(t, rule.ID, payloads[0]["ruleId"])
+		require.Equal(t, card.ID, payloads[0]["card"].(map[string]interface{})["id"])
+	})
+
+	t.Run("webhooks to hosts not allowed", func(t *testing.T) {
+		_, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionCallWebhook, URL: "http://169.254.169.254/latest/meta-data"}},
+		})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("move to another board", func(t *testing.T) {
+		destBoard, resp := th.Client.CreateBoard(&model.Board{
+			TeamID: testTeamID,
+			Type:   model.BoardTypeOpen,
+		})
+		th.CheckOK(resp)
+
+		rule, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Enabled: true,
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCommentAdded},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionMoveToBoard, BoardID: destBoard.ID}},
+		})
+		th.CheckOK(resp)
+
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "card to move"}, false)
+		th.CheckOK(resp)
+		_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: card.ID,
+			Type:     model.TypeComment,
+			Title:    "please move",
+			CreateAt: utils.GetMillis(),
+			UpdateAt: utils.GetMillis(),
+		}}, false)
+		th.CheckOK(resp)
+
+		require.Eventually(t, func() bool {
+			blocks, resp := th.Client.GetAllBlocksForBoard(destBoard.ID)
+			return resp.Error == nil && len(blocks) == 2
+		}, 5*time.Second, 50*time.Millisecond)
+
+		_, resp = th.Client.GetCard(card.ID)
+		th.CheckBadRequest(resp)
+
+		th.CheckOK(th.Client.DeleteAutomationRule(board.ID, rule.ID))
+	})
+}
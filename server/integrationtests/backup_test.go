@@ -0,0 +1,56 @@
+package integrationtests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackups(t *testing.T) {
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	th.Client = clients.TeamMember
+	th.Server.Config().BackupPath = t.TempDir()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: "backed up card"}, true)
+	th.CheckOK(resp)
+
+	t.Run("only system admins can manage the backups", func(t *testing.T) {
+		_, resp := clients.TeamMember.CreateBackup()
+		th.CheckForbidden(resp)
+
+		_, resp = clients.TeamMember.GetBackups()
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("create and restore a backup", func(t *testing.T) {
+		backup, resp := clients.Admin.CreateBackup()
+		th.CheckOK(resp)
+		require.NotZero(t, backup.Size)
+
+		backups, resp := clients.Admin.GetBackups()
+		th.CheckOK(resp)
+		require.Len(t, backups, 1)
+		require.Equal(t, backup.Name, backups[0].Name)
+
+		_, resp = th.Client.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+		_, resp = th.Client.GetBoard(board.ID, "")
+		th.CheckNotFound(resp)
+
+		manifest, err := th.Server.App().RestoreBackup(filepath.Join(th.Server.Config().BackupPath, backup.Name))
+		require.NoError(t, err)
+		require.Equal(t, th.Server.Store().DBType(), manifest.DBType)
+		require.Equal(t, int64(1), manifest.Tables["boards"])
+
+		_, resp = th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		restored, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "backed up card", restored.Title)
+	})
+}
@@ -0,0 +1,301 @@
+package integrationtests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportBoardTable(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypePrivate,
+		Title:  "Road/map",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do"},
+					map[string]interface{}{"id": "done", "value": "Done"},
+				},
+			},
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "due", "name": "Due", "type": "date"},
+		},
+	})
+	th.CheckOK(resp)
+
+	user1 := th.GetUser1()
+	createCard := func(title string, props map[string]any) {
+		_, resp := th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: title, Properties: props}, true)
+		th.CheckOK(resp)
+	}
+	createCard("Alpha", map[string]any{"status": "todo", "estimate": "3", "owner": user1.ID, "due": `{"from":1705276800000}`})
+	createCard("Beta", map[string]any{"status": "done", "estimate": "-5"})
+	createCard("Gamma", map[string]any{"status": "todo", "estimate": "8"})
+	createCard("=Delta", map[string]any{"status": "todo"})
+
+	now := utils.GetMillis()
+	view := &model.Block{
+		ID:       utils.NewID(utils.IDTypeView),
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeView,
+		Title:    "Open items",
+		CreateAt: now,
+		UpdateAt: now,
+		Fields: map[string]interface{}{
+			"viewType":           "table",
+			"visiblePropertyIds": []interface{}{"estimate", "status"},
+			"sortOptions":        []interface{}{map[string]interface{}{"propertyId": "estimate", "reversed": true}},
+			"filter": map[string]interface{}{
+				"operation": "and",
+				"filters": []interface{}{
+					map[string]interface{}{"propertyId": "status", "condition": "includes", "values": []interface{}{"todo"}},
+				},
+			},
+		},
+	}
+	inserted, resp := th.Client.InsertBlocks(board.ID, []*model.Block{view}, true)
+	th.CheckOK(resp)
+	view = inserted[0]
+
+	readCSV := func(data []byte) [][]string {
+		require.True(t, bytes.HasPrefix(data, []byte("\ufeff")))
+		records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff")))).ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+
+	t.Run("users without access can't export", func(t *testing.T) {
+		_, resp := th.Client2.ExportBoard(board.ID, model.BoardExportFormatCSV, "")
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		_, resp := th.Client.ExportBoard(board.ID, "pdf", "")
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.ExportBoard(board.ID, model.BoardExportFormatCSV, utils.NewID(utils.IDTypeView))
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("export all the cards as CSV", func(t *testing.T) {
+		data, resp := th.Client.ExportBoard(board.ID, model.BoardExportFormatCSV, "")
+		th.CheckOK(resp)
+		require.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		require.Contains(t, resp.Header.Get("Content-Disposition"), "Road-map-")
+
+		records := readCSV(data)
+		require.Len(t, records, 5)
+		require.Equal(t, []string{"Title", "Status", "Estimate", "Owner", "Due"}, records[0])
+
+		rows := map[string][]string{}
+		for _, record := range records[1:] {
+			rows[record[0]] = record
+		}
+		require.Equal(t, []string{"Alpha", "TO DO", "3", user1.Username, "January 15, 2024"}, rows["Alpha"])
+		// negative numbers are not escaped
+		require.Equal(t, []string{"Beta", "DONE", "-5", "", ""}, rows["Beta"])
+	})
+
+	t.Run("export a view as CSV", func(t *testing.T) {
+		data, resp := th.Client.ExportBoard(board.ID, model.BoardExportFormatCSV, view.ID)
+		th.CheckOK(resp)
+
+		require.Equal(t, [][]string{
+			{"Title", "Estimate", "Status"},
+			{"Gamma", "8", "TO DO"},
+			{"Alpha", "3", "TO DO"},
+			{"'=Delta", "", "TO DO"},
+		}, readCSV(data))
+	})
+
+	t.Run("export a view as XLSX", func(t *testing.T) {
+		data, resp := th.Client.ExportBoard(board.ID, model.BoardExportFormatXLSX, view.ID)
+		th.CheckOK(resp)
+		require.Equal(t, model.BoardExportFormatXLSX.ContentType(), resp.Header.Get("Content-Type"))
+
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		files := map[string]string{}
+		for _, f := range archive.File {
+			r, err := f.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			files[f.Name] = string(content)
+		}
+
+		require.Contains(t, files, "[Content_Types].xml")
+		require.Contains(t, files["xl/workbook.xml"], `name="Road map"`)
+
+		sheet := files["xl/worksheets/sheet1.xml"]
+		require.Contains(t, sheet, `<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">Title</t></is></c>`)
+		require.Contains(t, sheet, `<c r="B2"><v>8</v></c>`)
+		require.Equal(t, 4, strings.Count(sheet, "<row "))
+		require.Less(t, strings.Index(sheet, "Gamma"), strings.Index(sheet, "Alpha"))
+	})
+}
+
+func TestExportBoardSite(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID:      testTeamID,
+		Type:        model.BoardTypePrivate,
+		Title:       "Launch plan",
+		Description: "Everything for the *launch*",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do"},
+					map[string]interface{}{"id": "done", "value": "Done"},
+				},
+			},
+		},
+	})
+	th.CheckOK(resp)
+
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{
+		BoardID:    board.ID,
+		Title:      "Write [docs] | guides",
+		Properties: map[string]any{"status": "todo"},
+	}, true)
+	th.CheckOK(resp)
+	_, resp = th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: "Ship", Properties: map[string]any{"status": "done"}}, true)
+	th.CheckOK(resp)
+
+	upload, resp := th.Client.TeamUploadFile(testTeamID, board.ID, bytes.NewReader([]byte("fake image")))
+	th.CheckOK(resp)
+
+	now := utils.GetMillis()
+	newBlock := func(blockType model.BlockType, title string, fields map[string]interface{}) *model.Block {
+		now++
+		return &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: card.ID,
+			Type:     blockType,
+			Title:    title,
+			CreateAt: now,
+			UpdateAt: now,
+			Fields:   fields,
+		}
+	}
+	view := newBlock(model.TypeView, "Open items", map[string]interface{}{
+		"viewType":           "table",
+		"visiblePropertyIds": []interface{}{"status"},
+		"filter": map[string]interface{}{
+			"operation": "and",
+			"filters": []interface{}{
+				map[string]interface{}{"propertyId": "status", "condition": "includes", "values": []interface{}{"todo"}},
+			},
+		},
+	})
+	view.ParentID = board.ID
+	inserted, resp := th.Client.InsertBlocks(board.ID, []*model.Block{
+		view,
+		newBlock(model.TypeCheckbox, "Review", map[string]interface{}{"value": true}),
+		newBlock(model.TypeText, "Some **notes**\n\n<script>alert(1)</script>", nil),
+		newBlock(model.TypeDivider, "", nil),
+		newBlock(model.TypeImage, "", map[string]interface{}{"fileId": upload.FileID}),
+		newBlock(model.TypeComment, "Looks good", nil),
+	}, true)
+	th.CheckOK(resp)
+	view = inserted[0]
+
+	// the text comes first in the card order, the other blocks by creation
+	_, resp = th.Client.PatchBlock(board.ID, card.ID, &model.BlockPatch{
+		UpdatedFields: map[string]interface{}{"contentOrder": []interface{}{inserted[2].ID}},
+	}, true)
+	th.CheckOK(resp)
+
+	readZip := func(data []byte) map[string]string {
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		files := map[string]string{}
+		for _, f := range archive.File {
+			r, err := f.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			files[f.Name] = string(content)
+		}
+		return files
+	}
+	cardPage := "cards/write-docs-guides-" + card.ID
+	viewPage := "views/open-items-" + view.ID
+
+	t.Run("users without access can't export", func(t *testing.T) {
+		_, resp := th.Client2.ExportBoard(board.ID, model.BoardExportFormatMarkdown, "")
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("export the board as Markdown", func(t *testing.T) {
+		data, resp := th.Client.ExportBoard(board.ID, model.BoardExportFormatMarkdown, "")
+		th.CheckOK(resp)
+		require.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+		require.Contains(t, resp.Header.Get("Content-Disposition"), ".zip")
+
+		files := readZip(data)
+		require.Len(t, files, 5)
+		require.Equal(t, "fake image", files["files/"+upload.FileID])
+
+		index := files["index.md"]
+		require.Contains(t, index, "# Launch plan\n\nEverything for the *launch*")
+		require.Contains(t, index, "- [Open items]("+viewPage+".md)")
+		require.Contains(t, index, `- [Write \[docs\] \| guides](`+cardPage+".md)")
+		require.Less(t, strings.Index(index, "Ship"), strings.Index(index, "Write"))
+
+		require.Contains(t, files[viewPage+".md"], "| Title | Status |")
+		require.Contains(t, files[viewPage+".md"], "| [Write \\[docs\\] \\| guides](../"+cardPage+".md) | TO DO |")
+		require.NotContains(t, files[viewPage+".md"], "Ship")
+
+		page := files[cardPage+".md"]
+		require.Contains(t, page, "| Status | TO DO |")
+		require.Contains(t, page, "- [x] Review")
+		require.Contains(t, page, "![](../files/"+upload.FileID+")")
+		require.Contains(t, page, "## Comments\n\n**"+th.GetUser1().Username+"**")
+		require.Contains(t, page, "Looks good")
+		require.Less(t, strings.Index(page, "Some **notes**"), strings.Index(page, "- [x] Review"))
+		require.Less(t, strings.Index(page, "---"), strings.Index(page, "![]"))
+	})
+
+	t.Run("export a view as HTML", func(t *testing.T) {
+		data, resp := th.Client.ExportBoard(board.ID, model.BoardExportFormatHTML, view.ID)
+		th.CheckOK(resp)
+
+		files := readZip(data)
+		require.Contains(t, files, "index.html")
+		require.Contains(t, files, viewPage+".html")
+		require.Contains(t, files, "files/"+upload.FileID)
+		require.NotContains(t, files["index.html"], "Ship")
+
+		page := files[cardPage+".html"]
+		require.Contains(t, page, "<title>Write [docs] | guides</title>")
+		require.Contains(t, page, "<strong>notes</strong>")
+		require.Contains(t, page, `<input checked="" disabled="" type="checkbox"`)
+		require.Contains(t, page, `<img src="../files/`+upload.FileID+`" alt="">`)
+		require.Contains(t, page, `<a href="../index.html">Launch plan</a>`)
+		require.NotContains(t, page, "<script>")
+		require.Contains(t, files[viewPage+".html"], `<a href="../`+cardPage+`.html">`)
+	})
+}
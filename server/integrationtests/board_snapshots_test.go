@@ -0,0 +1,91 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardSnapshots(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		Title:  "first title",
+	})
+	th.CheckOK(resp)
+
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: "card"}, true)
+	th.CheckOK(resp)
+
+	time.Sleep(10 * time.Millisecond)
+	first := utils.GetMillis()
+	time.Sleep(10 * time.Millisecond)
+
+	newTitle := "renamed title"
+	_, resp = th.Client.PatchBoard(board.ID, &model.BoardPatch{Title: &newTitle})
+	th.CheckOK(resp)
+	_, resp = th.Client.DeleteBlock(board.ID, card.ID, true)
+	th.CheckOK(resp)
+
+	time.Sleep(10 * time.Millisecond)
+	second := utils.GetMillis()
+	time.Sleep(10 * time.Millisecond)
+
+	t.Run("list versions", func(t *testing.T) {
+		versions, resp := th.Client.GetBoardVersions(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.NotEmpty(t, versions)
+		require.Greater(t, versions[0].UpdateAt, first)
+
+		_, resp = th.Client2.GetBoardVersions(board.ID, 0, 10)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("get snapshot", func(t *testing.T) {
+		snapshot, resp := th.Client.GetBoardSnapshot(board.ID, first)
+		th.CheckOK(resp)
+		require.Equal(t, "first title", snapshot.Board.Title)
+		require.Len(t, snapshot.Blocks, 1)
+		require.Equal(t, card.ID, snapshot.Blocks[0].ID)
+
+		_, resp = th.Client.GetBoardSnapshot(board.ID, board.CreateAt-1)
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("diff snapshots", func(t *testing.T) {
+		diff, resp := th.Client.DiffBoardSnapshots(board.ID, first, second)
+		th.CheckOK(resp)
+		require.Equal(t, "first title", diff.BoardBefore.Title)
+		require.Equal(t, "renamed title", diff.BoardAfter.Title)
+		require.Empty(t, diff.Added)
+		require.Len(t, diff.Deleted, 1)
+		require.Equal(t, card.ID, diff.Deleted[0].ID)
+
+		_, resp = th.Client.DiffBoardSnapshots(board.ID, second, first)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("restore board", func(t *testing.T) {
+		_, resp := th.Client2.RestoreBoardSnapshot(board.ID, &model.BoardSnapshotRestoreRequest{At: first})
+		th.CheckForbidden(resp)
+
+		restore, resp := th.Client.RestoreBoardSnapshot(board.ID, &model.BoardSnapshotRestoreRequest{At: first})
+		th.CheckOK(resp)
+		require.Equal(t, "first title", restore.Board.Title)
+		require.Len(t, restore.Blocks, 1)
+
+		fetched, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "card", fetched.Title)
+
+		fetchedBoard, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		require.Equal(t, "first title", fetchedBoard.Title)
+	})
+}
@@ -0,0 +1,286 @@
+package integrationtests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkOperations(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	th.Server.Config().UndoStackSize = 100
+	defer func() { th.Server.Config().UndoStackSize = 0 }()
+
+	statusProperty := map[string]interface{}{
+		"id":   "status",
+		"name": "Status",
+		"type": "select",
+		"options": []interface{}{
+			map[string]interface{}{"id": "todo", "value": "To do"},
+			map[string]interface{}{"id": "done", "value": "Done"},
+		},
+	}
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID:         testTeamID,
+		Type:           model.BoardTypePrivate,
+		Title:          "board",
+		CardProperties: []map[string]interface{}{statusProperty},
+	})
+	th.CheckOK(resp)
+	target, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypePrivate,
+		Title:  "target",
+	})
+	th.CheckOK(resp)
+
+	// waits to avoid hitting the pk uniqueness constraint in history
+	wait := func() { time.Sleep(10 * time.Millisecond) }
+
+	createCard := func(title, status string) *model.Card {
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{
+			BoardID:    board.ID,
+			Title:      title,
+			Properties: map[string]any{"status": status},
+		}, true)
+		th.CheckOK(resp)
+		return card
+	}
+	first := createCard("first", "todo")
+	second := createCard("second", "todo")
+	third := createCard("third", "done")
+	wait()
+
+	todoFilter := &model.CardFilter{PropertyID: "status", Condition: model.CardFilterConditionIncludes, Values: []string{"todo"}}
+	thirdFilter := &model.CardFilter{PropertyID: model.CardFilterPropertyTitle, Condition: model.CardFilterConditionIs, Values: []string{"third"}}
+
+	status := func(cardID string) interface{} {
+		card, resp := th.Client.GetCard(cardID)
+		th.CheckOK(resp)
+		return card.Properties["status"]
+	}
+
+	t.Run("users without access can't run operations", func(t *testing.T) {
+		_, resp := th.Client2.StartBulkOperation(board.ID, &model.BulkOperationRequest{Type: model.BulkOperationArchive})
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("invalid operations", func(t *testing.T) {
+		_, resp := th.Client.StartBulkOperation(board.ID, &model.BulkOperationRequest{Type: "rename"})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.StartBulkOperation(board.ID, &model.BulkOperationRequest{
+			Type:       model.BulkOperationSetProperty,
+			PropertyID: "priority",
+			Value:      "high",
+		})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("set a property and undo it", func(t *testing.T) {
+		operation, resp := th.Client.StartBulkOperation(board.ID, &model.BulkOperationRequest{
+			Filter:     todoFilter,
+			Type:       model.BulkOperationSetProperty,
+			PropertyID: "status",
+			Value:      "done",
+		})
+		th.CheckOK(resp)
+		require.Equal(t, model.BulkOperationStatusCompleted, operation.Status)
+		require.Equal(t, 2, operation.Total)
+		require.Equal(t, 2, operation.Processed)
+		require.Equal(t, "done", status(first.ID))
+		require.Equal(t, "done", status(second.ID))
+		wait()
+
+		fetched, resp := th.Client.GetBulkOperation(operation.ID)
+		th.CheckOK(resp)
+		require.Equal(t, operation.ID, fetched.ID)
+
+		_, resp = th.Client2.GetBulkOperation(operation.ID)
+		th.CheckForbidden(resp)
+
+		result, resp := th.Client.Undo(board.ID, false)
+		th.CheckOK(resp)
+		require.Len(t, result.Entry.BlockChanges, 2)
+		require.Equal(t, "todo", status(first.ID))
+		require.Equal(t, "todo", status(second.ID))
+		require.Equal(t, "done", status(third.ID))
+		wait()
+	})
+
+	t.Run("clear a property", func(t *testing.T) {
+		_, resp := th.Client.StartBulkOperation(board.ID, &model.BulkOperationRequest{
+			Filter:     thirdFilter,
+			Type:       model.BulkOperationClearProperty,
+			PropertyID: "status",
+		})
+		th.CheckOK(resp)
+		require.Nil(t, status(third.ID))
+		wait()
+
+		_, resp = th.Client.Undo(board.ID, false)
+		th.CheckOK(resp)
+		require.Equal(t, "done", status(third.ID))
+		wait()
+	})
+
+	t.Run("move cards to another board and undo it", func(t *testing.T) {
+		request := &model.BulkOperationRequest{
+			Filter:        thirdFilter,
+			Type:          model.BulkOperationMoveToBoard,
+			TargetBoardID: target.ID,
+		}
+		_, resp := th.Client2.StartBulkOperation(board.ID, request)
+		th.CheckForbidden(resp)
+
+		operation, resp := th.Client.StartBulkOperation(board.ID, request)
+		th.CheckOK(resp)
+		require.Equal(t, 1, operation.Processed)
+
+		_, resp = th.Client.GetCard(third.ID)
+		th.CheckBadRequest(resp)
+		moved, resp := th.Client.GetCards(target.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, moved, 1)
+		require.Equal(t, "third", moved[0].Title)
+		wait()
+
+		// each board has its own undo entry
+		_, resp = th.Client.Undo(board.ID, false)
+		th.CheckOK(resp)
+
+		restored, resp := th.Client.GetCard(third.ID)
+		th.CheckOK(resp)
+		require.Equal(t, board.ID, restored.BoardID)
+		moved, resp = th.Client.GetCards(target.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, moved, 1)
+		wait()
+
+		result, resp := th.Client.Undo(target.ID, false)
+		th.CheckOK(resp)
+		require.Equal(t, model.UndoOperationInsertBlocks, result.Entry.Operation)
+		moved, resp = th.Client.GetCards(target.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Empty(t, moved)
+		wait()
+	})
+
+	t.Run("archive and delete cards and undo it", func(t *testing.T) {
+		_, resp := th.Client.StartBulkOperation(board.ID, &model.BulkOperationRequest{
+			Filter: todoFilter,
+			Type:   model.BulkOperationArchive,
+		})
+		th.CheckOK(resp)
+
+		cards, resp := th.Client.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		wait()
+
+		_, resp = th.Client.Undo(board.ID, false)
+		th.CheckOK(resp)
+
+		cards, resp = th.Client.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 3)
+		wait()
+
+		operation, resp := th.Client.StartBulkOperation(board.ID, &model.BulkOperationRequest{
+			Filter: todoFilter,
+			Type:   model.BulkOperationDelete,
+		})
+		th.CheckOK(resp)
+		require.Equal(t, 2, operation.Processed)
+
+		cards, resp = th.Client.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		wait()
+
+		result, resp := th.Client.Undo(board.ID, false)
+		th.CheckOK(resp)
+		require.Equal(t, model.UndoOperationDeleteBlock, result.Entry.Operation)
+
+		cards, resp = th.Client.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 3)
+		wait()
+	})
+
+	t.Run("subscribe users to cards", func(t *testing.T) {
+		user := th.GetUser1()
+
+		_, resp := th.Client.StartBulkOperation(board.ID, &model.BulkOperationRequest{
+			Filter:       todoFilter,
+			Type:         model.BulkOperationAddSubscriber,
+			SubscriberID: user.ID,
+		})
+		th.CheckOK(resp)
+
+		subs, resp := th.Client.GetSubscriptions(user.ID)
+		th.CheckOK(resp)
+		subscribed := []string{}
+		for _, sub := range subs {
+			subscribed = append(subscribed, sub.BlockID)
+		}
+		require.ElementsMatch(t, []string{first.ID, second.ID}, subscribed)
+
+		// users without access to the board can't be subscribed
+		_, resp = th.Client.StartBulkOperation(board.ID, &model.BulkOperationRequest{
+			Type:         model.BulkOperationAddSubscriber,
+			SubscriberID: th.GetUser2().ID,
+		})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("large operations run in the background", func(t *testing.T) {
+		now := utils.GetMillis()
+		blocks := []*model.Block{}
+		for i := 0; i < 120; i++ {
+			blocks = append(blocks, &model.Block{
+				ID:       utils.NewID(utils.IDTypeCard),
+				BoardID:  board.ID,
+				ParentID: board.ID,
+				Type:     model.TypeCard,
+				Title:    fmt.Sprintf("bulk %d", i),
+				CreateAt: now,
+				UpdateAt: now,
+			})
+		}
+		_, resp := th.Client.InsertBlocks(board.ID, blocks, true)
+		th.CheckOK(resp)
+		wait()
+
+		operation, resp := th.Client.StartBulkOperation(board.ID, &model.BulkOperationRequest{
+			Filter: &model.CardFilter{PropertyID: model.CardFilterPropertyTitle, Condition: model.CardFilterConditionStartsWith, Values: []string{"bulk"}},
+			Type:   model.BulkOperationArchive,
+		})
+		require.NoError(t, resp.Error)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		require.Equal(t, model.BulkOperationStatusRunning, operation.Status)
+		require.Equal(t, 120, operation.Total)
+
+		require.Eventually(t, func() bool {
+			fetched, resp := th.Client.GetBulkOperation(operation.ID)
+			th.CheckOK(resp)
+			return fetched.IsDone()
+		}, 10*time.Second, 50*time.Millisecond)
+
+		fetched, resp := th.Client.GetBulkOperation(operation.ID)
+		th.CheckOK(resp)
+		require.Equal(t, model.BulkOperationStatusCompleted, fetched.Status)
+		require.Equal(t, 120, fetched.Processed)
+
+		cards, resp := th.Client.GetCards(board.ID, 0, 200)
+		th.CheckOK(resp)
+		require.Len(t, cards, 3)
+	})
+}
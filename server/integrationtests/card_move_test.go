@@ -0,0 +1,152 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveCard(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	source, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypePrivate,
+		Title:  "source",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do"},
+					map[string]interface{}{"id": "done", "value": "Done"},
+				},
+			},
+		},
+	})
+	th.CheckOK(resp)
+	target, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypePrivate,
+		Title:  "target",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "state",
+				"name": "status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "closed", "value": "done"},
+				},
+			},
+		},
+	})
+	th.CheckOK(resp)
+
+	createCard := func(title, status string) *model.Card {
+		card, resp := th.Client.CreateCard(source.ID, &model.Card{
+			BoardID:    source.ID,
+			Title:      title,
+			Properties: map[string]any{"status": status},
+		}, true)
+		th.CheckOK(resp)
+
+		now := utils.GetMillis()
+		_, resp = th.Client.InsertBlocks(source.ID, []*model.Block{{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  source.ID,
+			ParentID: card.ID,
+			Type:     model.TypeComment,
+			Title:    "comment on " + title,
+			CreateAt: now,
+			UpdateAt: now,
+		}}, true)
+		th.CheckOK(resp)
+
+		// waits to avoid hitting the pk uniqueness constraint in history
+		time.Sleep(10 * time.Millisecond)
+		return card
+	}
+
+	t.Run("users without access can't move cards", func(t *testing.T) {
+		card := createCard("forbidden", "done")
+		request := &model.CardMoveRequest{TargetBoardID: target.ID}
+
+		_, resp := th.Client2.PreviewCardMove(card.ID, request)
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.MoveCard(card.ID, request)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		card := createCard("invalid", "done")
+
+		_, resp := th.Client.MoveCard(card.ID, &model.CardMoveRequest{})
+		th.CheckBadRequest(resp)
+		_, resp = th.Client.MoveCard(card.ID, &model.CardMoveRequest{TargetBoardID: source.ID})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("preview and move a card", func(t *testing.T) {
+		card := createCard("todo card", "todo")
+		request := &model.CardMoveRequest{TargetBoardID: target.ID, CreateMissingOptions: true}
+
+		mapping, resp := th.Client.PreviewCardMove(card.ID, request)
+		th.CheckOK(resp)
+		require.Len(t, mapping.Properties, 1)
+		require.Equal(t, "state", mapping.Properties[0].TargetPropertyID)
+		require.True(t, mapping.Properties[0].Options[0].Created)
+
+		// the preview doesn't change the target board
+		fetchedTarget, resp := th.Client.GetBoard(target.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, fetchedTarget.CardProperties[0]["options"], 1)
+
+		result, resp := th.Client.MoveCard(card.ID, request)
+		th.CheckOK(resp)
+		require.Equal(t, target.ID, result.Card.BoardID)
+		require.Equal(t, card.ID, result.Card.MovedFromCardID)
+		require.Equal(t, source.ID, result.Card.MovedFromBoardID)
+
+		fetchedTarget, resp = th.Client.GetBoard(target.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, fetchedTarget.CardProperties[0]["options"], 2)
+		require.Equal(t, result.Mapping.Properties[0].Options[0].TargetOptionID, result.Card.Properties["state"])
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(target.ID)
+		th.CheckOK(resp)
+		var comments []*model.Block
+		for _, block := range blocks {
+			if block.Type == model.TypeComment && block.ParentID == result.Card.ID {
+				comments = append(comments, block)
+			}
+		}
+		require.Len(t, comments, 1)
+		require.Equal(t, "comment on todo card", comments[0].Title)
+
+		// the source card is deleted
+		_, resp = th.Client.GetCard(card.ID)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("move a card leaving a redirect", func(t *testing.T) {
+		card := createCard("done card", "done")
+
+		result, resp := th.Client.MoveCard(card.ID, &model.CardMoveRequest{
+			TargetBoardID: target.ID,
+			SourceAction:  model.CardMoveSourceRedirect,
+		})
+		th.CheckOK(resp)
+		require.Equal(t, "closed", result.Card.Properties["state"])
+
+		redirect, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.NotZero(t, redirect.ArchiveAt)
+		require.Equal(t, result.Card.ID, redirect.MovedToCardID)
+		require.Equal(t, target.ID, redirect.MovedToBoardID)
+	})
+}
@@ -0,0 +1,98 @@
+package integrationtests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCardFromTemplate(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypePrivate,
+		Title:  "Team board",
+		CardProperties: []map[string]interface{}{
+			{"id": "due", "name": "Due", "type": "date"},
+			{"id": "owner", "name": "Owner", "type": "text"},
+		},
+	})
+	th.CheckOK(resp)
+
+	template, resp := th.Client.CreateCard(board.ID, &model.Card{
+		BoardID:          board.ID,
+		Title:            "Release {{version}} on {{board}}",
+		IsTemplate:       true,
+		TemplateDefaults: map[string]string{"due": "+3d", "owner": "{{user}}"},
+	}, true)
+	th.CheckOK(resp)
+
+	text := &model.Block{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  board.ID,
+		ParentID: template.ID,
+		Type:     model.TypeText,
+		Title:    "Checklist for {{version}}, written on {{date}}",
+		CreateAt: utils.GetMillis(),
+		UpdateAt: utils.GetMillis(),
+	}
+	_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{text}, true)
+	th.CheckOK(resp)
+
+	t.Run("list the variables", func(t *testing.T) {
+		variables, resp := th.Client.GetCardTemplateVariables(template.ID)
+		th.CheckOK(resp)
+		require.Equal(t, []string{"version"}, variables.Variables)
+		require.Contains(t, variables.BuiltIn, model.TemplateVariableUser)
+	})
+
+	t.Run("the custom variables are required", func(t *testing.T) {
+		_, resp := th.Client.CreateCardFromTemplate(template.ID, nil)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("users without access can't create cards", func(t *testing.T) {
+		_, resp := th.Client2.CreateCardFromTemplate(template.ID, map[string]string{"version": "1.0"})
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("create a card", func(t *testing.T) {
+		before := utils.GetMillis()
+		card, resp := th.Client.CreateCardFromTemplate(template.ID, map[string]string{"version": "1.0"})
+		th.CheckOK(resp)
+		require.NotEqual(t, template.ID, card.ID)
+		require.Equal(t, "Release 1.0 on Team board", card.Title)
+		require.False(t, card.IsTemplate)
+		require.Empty(t, card.TemplateDefaults)
+		require.Equal(t, th.GetUser1().Username, card.Properties["owner"])
+
+		var due map[string]int64
+		require.NoError(t, json.Unmarshal([]byte(card.Properties["due"].(string)), &due))
+		require.GreaterOrEqual(t, due["from"], before+(3*24*time.Hour).Milliseconds()-time.Hour.Milliseconds())
+
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		var content *model.Block
+		for _, block := range blocks {
+			if block.ParentID == card.ID {
+				content = block
+			}
+		}
+		require.NotNil(t, content)
+		require.Equal(t, "Checklist for 1.0, written on "+time.Now().UTC().Format("2006-01-02"), content.Title)
+	})
+
+	t.Run("only templates can be instantiated", func(t *testing.T) {
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: "card"}, true)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.CreateCardFromTemplate(card.ID, nil)
+		th.CheckBadRequest(resp)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/utils"
@@ -269,6 +270,139 @@ func TestGetCard(t *testing.T) {
 	})
 }
 
+func TestCardDependencies(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	_, cards := th.CreateBoardAndCards(testTeamID, model.BoardTypeOpen, 3)
+	card1, card2, card3 := cards[0], cards[1], cards[2]
+
+	t.Run("add blockers", func(t *testing.T) {
+		dependency, resp := th.Client.AddCardBlocker(card2.ID, card1.ID)
+		th.CheckOK(resp)
+		require.Equal(t, card1.ID, dependency.BlockerID)
+		require.Equal(t, card2.ID, dependency.BlockedID)
+		require.Equal(t, th.GetUser1().ID, dependency.CreatedBy)
+
+		_, resp = th.Client.AddCardBlocker(card3.ID, card2.ID)
+		th.CheckOK(resp)
+
+		dependencies, resp := th.Client.GetCardDependencies(card2.ID)
+		th.CheckOK(resp)
+		require.True(t, dependencies.Blocked)
+		require.Len(t, dependencies.BlockedBy, 1)
+		require.Len(t, dependencies.Blocking, 1)
+
+		cardFetched, resp := th.Client.GetCard(card2.ID)
+		th.CheckOK(resp)
+		require.True(t, cardFetched.Blocked)
+	})
+
+	t.Run("cycles are rejected", func(t *testing.T) {
+		_, resp := th.Client.AddCardBlocker(card1.ID, card3.ID)
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.AddCardBlocker(card1.ID, card1.ID)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("delete blockers", func(t *testing.T) {
+		resp := th.Client.DeleteCardBlocker(card2.ID, card1.ID)
+		th.CheckOK(resp)
+
+		dependencies, resp := th.Client.GetCardDependencies(card2.ID)
+		th.CheckOK(resp)
+		require.False(t, dependencies.Blocked)
+		require.Empty(t, dependencies.BlockedBy)
+
+		resp = th.Client.DeleteCardBlocker(card2.ID, card1.ID)
+		th.CheckNotFound(resp)
+	})
+}
+
+func TestCardRecurrence(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		CardProperties: []map[string]interface{}{
+			{"id": "due-date", "name": "Due date", "type": "date"},
+		},
+	})
+	th.CheckOK(resp)
+
+	template, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "weekly chore", IsTemplate: true}, true)
+	th.CheckOK(resp)
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "not a template"}, true)
+	th.CheckOK(resp)
+
+	t.Run("invalid recurrences", func(t *testing.T) {
+		_, resp := th.Client.SetCardRecurrence(card.ID, &model.CardRecurrence{Rule: "FREQ=DAILY"})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.SetCardRecurrence(template.ID, &model.CardRecurrence{Rule: "FREQ=YEARLY"})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.SetCardRecurrence(template.ID, &model.CardRecurrence{Rule: "FREQ=DAILY", DatePropertyID: "unknown"})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("create the recurring cards", func(t *testing.T) {
+		start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+		recurrence, resp := th.Client.SetCardRecurrence(template.ID, &model.CardRecurrence{
+			Rule:           "FREQ=DAILY;BYHOUR=9",
+			DatePropertyID: "due-date",
+			StartAt:        utils.GetMillisForTime(start),
+		})
+		th.CheckOK(resp)
+		require.Equal(t, board.ID, recurrence.BoardID)
+		require.Equal(t, th.GetUser1().ID, recurrence.CreatedBy)
+		require.Greater(t, recurrence.NextRunAt, utils.GetMillis())
+
+		rRecurrence, resp := th.Client.GetCardRecurrence(template.ID)
+		th.CheckOK(resp)
+		require.Equal(t, recurrence.NextRunAt, rRecurrence.NextRunAt)
+
+		cardsBefore, resp := th.Client.GetCards(board.ID, 0, -1)
+		th.CheckOK(resp)
+
+		// two days later, a single card is created for the latest occurrence,
+		// and running the job again doesn't create it twice
+		now := utils.GetTimeForMillis(recurrence.NextRunAt).Add(36 * time.Hour)
+		th.Server.App().CreateDueRecurringCards(now)
+		th.Server.App().CreateDueRecurringCards(now)
+
+		cards, resp := th.Client.GetCards(board.ID, 0, -1)
+		th.CheckOK(resp)
+		require.Len(t, cards, len(cardsBefore)+1)
+
+		var newCard *model.Card
+		for _, c := range cards {
+			if c.Title == template.Title && !c.IsTemplate {
+				newCard = c
+			}
+		}
+		require.NotNil(t, newCard)
+		lastRunAt := recurrence.NextRunAt + (24 * time.Hour).Milliseconds()
+		require.Equal(t, fmt.Sprintf(`{"from":%d}`, lastRunAt), newCard.Properties["due-date"])
+
+		rRecurrence, resp = th.Client.GetCardRecurrence(template.ID)
+		th.CheckOK(resp)
+		require.Equal(t, lastRunAt, rRecurrence.LastRunAt)
+		require.Equal(t, lastRunAt+(24*time.Hour).Milliseconds(), rRecurrence.NextRunAt)
+	})
+
+	t.Run("delete the recurrence", func(t *testing.T) {
+		resp := th.Client.DeleteCardRecurrence(template.ID)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.GetCardRecurrence(template.ID)
+		th.CheckNotFound(resp)
+	})
+}
+
 // Helpers.
 func reverse(src []string) []string {
 	out := make([]string, 0, len(src))
@@ -123,17 +123,22 @@ func getTestConfig() (*config.Configuration, error) {
 	}`
 
 	return &config.Configuration{
-		ServerRoot:        "http://localhost:8888",
-		Port:              8888,
-		DBType:            dbType,
-		DBConfigString:    connectionString,
-		DBTablePrefix:     "test_",
-		WebPath:           "./pack",
-		FilesDriver:       "local",
-		FilesPath:         "./files",
-		LoggingCfgJSON:    logging,
-		SessionExpireTime: int64(30 * time.Second),
-		AuthMode:          "native",
+		ServerRoot:            "http://localhost:8888",
+		Port:                  8888,
+		DBType:                dbType,
+		DBConfigString:        connectionString,
+		DBTablePrefix:         "test_",
+		WebPath:               "./pack",
+		FilesDriver:           "local",
+		FilesPath:             "./files",
+		LoggingCfgJSON:        logging,
+		SessionExpireTime:     int64(30 * time.Second),
+		AuthMode:              "native",
+		EnableAutomationRules: true,
+		AuditDBEnabled:        true,
+
+		AutomationWebhookAllowedHosts:  []string{"127.0.0.1"},
+		AutomationWebhookAllowInternal: true,
 	}, nil
 }
 
@@ -541,6 +546,11 @@ func (th *TestHelper) CheckNotFound(r *client.Response) {
 	require.Error(th.T, r.Error)
 }
 
+func (th *TestHelper) CheckConflict(r *client.Response) {
+	require.Equal(th.T, http.StatusConflict, r.StatusCode)
+	require.Error(th.T, r.Error)
+}
+
 func (th *TestHelper) CheckUnauthorized(r *client.Response) {
 	require.Equal(th.T, http.StatusUnauthorized, r.StatusCode)
 	require.Error(th.T, r.Error)
@@ -0,0 +1,153 @@
+package integrationtests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportCSV(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypePrivate,
+		Title:  "board",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do"},
+					map[string]interface{}{"id": "done", "value": "Done"},
+				},
+			},
+			{"id": "due", "name": "Due", "type": "date"},
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "ref", "name": "Reference", "type": "text"},
+		},
+	})
+	th.CheckOK(resp)
+
+	user1 := th.GetUser1()
+	importCSV := func(data string, opts *model.CSVImportOptions) *model.CSVImportResult {
+		result, resp := th.Client.ImportCSV(board.ID, strings.NewReader(data), opts)
+		th.CheckOK(resp)
+		return result
+	}
+	cardsByRef := func() map[string]*model.Card {
+		cards, resp := th.Client.GetCards(board.ID, 0, 100)
+		th.CheckOK(resp)
+		byRef := map[string]*model.Card{}
+		for _, card := range cards {
+			ref, _ := card.Properties["ref"].(string)
+			byRef[ref] = card
+		}
+		return byRef
+	}
+
+	data := "Title,Status,Due,Owner,Reference,Notes\n" +
+		"First,To do,2024-01-15," + user1.Username + ",A-1,ignored\n" +
+		"Second,Blocked,someday,nobody,A-2,\n" +
+		"Third,done,,,A-3,\n"
+
+	t.Run("users without access can't import", func(t *testing.T) {
+		_, resp := th.Client2.ImportCSV(board.ID, strings.NewReader(data), nil)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("dry run reports the errors of each row", func(t *testing.T) {
+		result := importCSV(data, &model.CSVImportOptions{DryRun: true})
+		require.True(t, result.DryRun)
+		require.Equal(t, 3, result.Rows)
+		require.Equal(t, 2, result.Created)
+		require.Equal(t, 1, result.Skipped)
+		require.Equal(t, []string{"Notes"}, result.IgnoredColumns)
+		require.Equal(t, "title", result.Columns["Title"])
+
+		require.Len(t, result.Errors, 3)
+		for _, rowErr := range result.Errors {
+			require.Equal(t, 3, rowErr.Row)
+		}
+		require.Equal(t, "Status", result.Errors[0].Column)
+		require.Equal(t, "Due", result.Errors[1].Column)
+		require.Equal(t, "Owner", result.Errors[2].Column)
+
+		require.Empty(t, cardsByRef())
+	})
+
+	t.Run("nothing is written if a row has errors", func(t *testing.T) {
+		result := importCSV(data, nil)
+		require.Equal(t, 0, result.Created)
+		require.Equal(t, 3, result.Skipped)
+		require.Empty(t, cardsByRef())
+	})
+
+	t.Run("import with created options", func(t *testing.T) {
+		fixed := strings.Replace(data, "someday,nobody", ",", 1)
+		result := importCSV(fixed, &model.CSVImportOptions{CreateMissingOptions: true})
+		require.Empty(t, result.Errors)
+		require.Equal(t, 3, result.Created)
+		require.Equal(t, map[string][]string{"status": {"Blocked"}}, result.CreatedOptions)
+
+		cards := cardsByRef()
+		require.Len(t, cards, 3)
+		require.Equal(t, "First", cards["A-1"].Title)
+		require.Equal(t, "todo", cards["A-1"].Properties["status"])
+		require.Equal(t, `{"from":1705276800000}`, cards["A-1"].Properties["due"])
+		require.Equal(t, user1.ID, cards["A-1"].Properties["owner"])
+		require.Equal(t, "done", cards["A-3"].Properties["status"])
+
+		updated, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, updated.CardProperties[0]["options"], 3)
+		blockedID := cards["A-2"].Properties["status"]
+		require.NotEmpty(t, blockedID)
+		require.NotContains(t, []interface{}{"todo", "done"}, blockedID)
+	})
+
+	t.Run("upsert on a key column", func(t *testing.T) {
+		upsert := "Reference,Status,Title\n" +
+			"A-1,Done,\n" +
+			"A-4,To do,Fourth\n" +
+			"A-4,Done,Duplicate\n"
+		opts := &model.CSVImportOptions{KeyColumn: "Reference", SkipInvalidRows: true}
+
+		result := importCSV(upsert, opts)
+		require.Equal(t, 1, result.Updated)
+		require.Equal(t, 1, result.Created)
+		require.Equal(t, 1, result.Skipped)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, 4, result.Errors[0].Row)
+
+		cards := cardsByRef()
+		require.Len(t, cards, 4)
+		require.Equal(t, "First", cards["A-1"].Title)
+		require.Equal(t, "done", cards["A-1"].Properties["status"])
+		require.Equal(t, user1.ID, cards["A-1"].Properties["owner"])
+		require.Equal(t, "Fourth", cards["A-4"].Title)
+	})
+
+	t.Run("invalid mappings", func(t *testing.T) {
+		_, resp := th.Client.ImportCSV(board.ID, strings.NewReader(data), &model.CSVImportOptions{
+			Columns: map[string]string{"Notes": "unknown"},
+		})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.ImportCSV(board.ID, strings.NewReader(data), &model.CSVImportOptions{KeyColumn: "Notes"})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.ImportCSV(board.ID, strings.NewReader(""), nil)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("the quote of exported formulas is removed", func(t *testing.T) {
+		result := importCSV("Title,Reference\n'=1+2,A-5\n", nil)
+		require.Equal(t, 1, result.Created)
+		require.Equal(t, "=1+2", cardsByRef()["A-5"].Title)
+	})
+}
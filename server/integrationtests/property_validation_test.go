@@ -0,0 +1,115 @@
+package integrationtests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardPropertyValidation(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "done", "value": "Done"},
+			}},
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+		},
+	})
+	th.CheckOK(resp)
+
+	// the client returns the body of the error responses as its error
+	propertyErrors := func(t *testing.T, err error) []model.PropertyValueError {
+		var errResponse model.ErrorResponse
+		payload := strings.TrimPrefix(err.Error(), "payload: ")
+		require.NoError(t, json.Unmarshal([]byte(payload), &errResponse))
+		return errResponse.PropertyErrors
+	}
+
+	t.Run("strict mode rejects invalid values", func(t *testing.T) {
+		th.Server.Config().CardPropertyValidation = string(model.PropertyValidationStrict)
+		defer func() { th.Server.Config().CardPropertyValidation = "" }()
+
+		card := &model.Card{
+			BoardID:    board.ID,
+			Title:      "invalid card",
+			Properties: map[string]any{"status": "unknown", "estimate": "3"},
+		}
+		_, resp := th.Client.CreateCard(board.ID, card, true)
+		th.CheckBadRequest(resp)
+		errs := propertyErrors(t, resp.Error)
+		require.Len(t, errs, 1)
+		require.Equal(t, "status", errs[0].PropertyID)
+		require.Equal(t, "unknown", errs[0].Value)
+
+		card.Properties = map[string]any{"status": "todo", "estimate": "3"}
+		created, resp := th.Client.CreateCard(board.ID, card, true)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.PatchCard(created.ID, &model.CardPatch{
+			UpdatedProperties: map[string]any{"estimate": "three", "missing": "value"},
+		}, true)
+		th.CheckBadRequest(resp)
+		errs = propertyErrors(t, resp.Error)
+		require.Len(t, errs, 2)
+		require.Equal(t, "estimate", errs[0].PropertyID)
+		require.Equal(t, "missing", errs[1].PropertyID)
+
+		fetched, resp := th.Client.GetCard(created.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "3", fetched.Properties["estimate"])
+	})
+
+	t.Run("lenient mode strips invalid values", func(t *testing.T) {
+		th.Server.Config().CardPropertyValidation = string(model.PropertyValidationLenient)
+		defer func() { th.Server.Config().CardPropertyValidation = "" }()
+
+		card := &model.Card{
+			BoardID:    board.ID,
+			Title:      "lenient card",
+			Properties: map[string]any{"status": "unknown", "estimate": "5"},
+		}
+		created, resp := th.Client.CreateCard(board.ID, card, true)
+		th.CheckOK(resp)
+		require.NotContains(t, created.Properties, "status")
+		require.Equal(t, "5", created.Properties["estimate"])
+
+		_, resp = th.Client.PatchCard(created.ID, &model.CardPatch{
+			UpdatedProperties: map[string]any{"status": "done", "estimate": "five"},
+		}, true)
+		th.CheckOK(resp)
+
+		fetched, resp := th.Client.GetCard(created.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "done", fetched.Properties["status"])
+		require.Equal(t, "5", fetched.Properties["estimate"])
+	})
+
+	t.Run("blocks are validated", func(t *testing.T) {
+		th.Server.Config().CardPropertyValidation = string(model.PropertyValidationStrict)
+		defer func() { th.Server.Config().CardPropertyValidation = "" }()
+
+		block := &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			Type:     model.TypeCard,
+			CreateAt: 1,
+			UpdateAt: 1,
+			Fields:   map[string]interface{}{"properties": map[string]interface{}{"estimate": true}},
+		}
+		_, resp := th.Client.InsertBlocks(board.ID, []*model.Block{block}, true)
+		th.CheckBadRequest(resp)
+		errs := propertyErrors(t, resp.Error)
+		require.Len(t, errs, 1)
+		require.Equal(t, "estimate", errs[0].PropertyID)
+	})
+}
@@ -0,0 +1,51 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationValues(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	createBoard := func(title string, props []map[string]interface{}) *model.Board {
+		board, resp := th.Client.CreateBoard(&model.Board{
+			TeamID:         testTeamID,
+			Type:           model.BoardTypePrivate,
+			Title:          title,
+			CardProperties: props,
+		})
+		th.CheckOK(resp)
+		return board
+	}
+	relatedBoard := createBoard("Related", nil)
+	otherBoard := createBoard("Other", nil)
+	board := createBoard("Tasks", []map[string]interface{}{
+		{"id": "related", "name": "Related", "type": model.PropTypeRelation, "relationBoardId": relatedBoard.ID},
+	})
+
+	related, resp := th.Client.CreateCard(relatedBoard.ID, &model.Card{Title: "related card"}, true)
+	th.CheckOK(resp)
+	other, resp := th.Client.CreateCard(otherBoard.ID, &model.Card{Title: "other card"}, true)
+	th.CheckOK(resp)
+
+	t.Run("cards of the related board can be related", func(t *testing.T) {
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "task",
+			Properties: map[string]any{"related": []string{related.ID}},
+		}, true)
+		th.CheckOK(resp)
+		require.Equal(t, []interface{}{related.ID}, card.Properties["related"])
+	})
+
+	t.Run("cards of other boards can't be related", func(t *testing.T) {
+		_, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "task",
+			Properties: map[string]any{"related": []string{other.ID}},
+		}, true)
+		th.CheckBadRequest(resp)
+	})
+}
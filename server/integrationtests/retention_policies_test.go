@@ -0,0 +1,91 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPolicies(t *testing.T) {
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	th.Client = clients.TeamMember
+
+	scratch := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	regulatory := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	for _, board := range []*model.Board{scratch, regulatory} {
+		_, resp := th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: "card"}, true)
+		th.CheckOK(resp)
+	}
+
+	t.Run("only admins can manage the policies", func(t *testing.T) {
+		_, resp := clients.TeamMember.GetRetentionPolicies(testTeamID)
+		th.CheckForbidden(resp)
+
+		_, resp = clients.TeamMember.UpsertTeamRetentionPolicy(testTeamID, 30)
+		th.CheckForbidden(resp)
+
+		_, resp = clients.TeamMember.UpsertBoardRetentionPolicy(scratch.ID, 1)
+		th.CheckForbidden(resp)
+
+		_, resp = clients.TeamMember.PreviewDataRetention("")
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("create, list and delete policies", func(t *testing.T) {
+		policy, resp := clients.Admin.UpsertTeamRetentionPolicy(testTeamID, 30)
+		th.CheckOK(resp)
+		require.Equal(t, testTeamID, policy.TeamID)
+		require.Equal(t, 30, policy.RetentionDays)
+		require.Equal(t, userAdmin, policy.ModifiedBy)
+
+		_, resp = clients.Admin.UpsertBoardRetentionPolicy(regulatory.ID, 2555)
+		th.CheckOK(resp)
+
+		_, resp = clients.Admin.UpsertBoardRetentionPolicy(scratch.ID, -1)
+		th.CheckBadRequest(resp)
+
+		policies, resp := clients.Admin.GetRetentionPolicies(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, policies, 2)
+
+		_, resp = clients.Admin.DeleteTeamRetentionPolicy(testTeamID)
+		th.CheckOK(resp)
+		_, resp = clients.Admin.DeleteTeamRetentionPolicy(testTeamID)
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("preview and run the data retention", func(t *testing.T) {
+		_, resp := clients.Admin.UpsertTeamRetentionPolicy(testTeamID, 5)
+		th.CheckOK(resp)
+
+		// nothing expired yet
+		candidates, resp := clients.Admin.PreviewDataRetention(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, candidates)
+
+		later := time.Now().AddDate(0, 0, 10)
+		candidates, err := th.Server.App().PreviewDataRetention(later, testTeamID)
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		require.Equal(t, scratch.ID, candidates[0].BoardID)
+		require.Equal(t, model.RetentionScopeTeam, candidates[0].Scope)
+
+		deleted, err := th.Server.App().RunDataRetention(later)
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+
+		_, resp = clients.Admin.GetBoard(scratch.ID, "")
+		th.CheckNotFound(resp)
+		_, resp = clients.Admin.GetBoard(regulatory.ID, "")
+		th.CheckOK(resp)
+		purges := model.QueryAuditRecordsOptions{Event: "dataRetentionPurge", BoardID: scratch.ID}
+		require.Eventually(t, func() bool {
+			response, resp := clients.Admin.GetAuditRecords(purges)
+			return resp.Error == nil && len(response.Results) == 1
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
@@ -0,0 +1,78 @@
+package integrationtests
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/server"
+	"github.com/mattermost/focalboard/server/services/permissions/localpermissions"
+	"github.com/mattermost/focalboard/server/services/store/cachelayer"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/mattermost/focalboard/server/ws"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+type fakeClusterAPI struct{}
+
+func (a *fakeClusterAPI) PublishWebSocketEvent(event string, payload map[string]interface{}, broadcast *mmModel.WebsocketBroadcast) {
+}
+
+func (a *fakeClusterAPI) PublishPluginClusterEvent(ev mmModel.PluginClusterEvent, opts mmModel.PluginClusterEventSendOptions) error {
+	return nil
+}
+
+func TestStoreCacheClusterInvalidation(t *testing.T) {
+	cfg, err := getTestConfig()
+	require.NoError(t, err)
+	defer os.Remove(cfg.DBConfigString)
+	defer os.RemoveAll(cfg.FilesPath)
+
+	logger := mlog.CreateConsoleTestLogger(t)
+	innerStore, err := server.NewStore(cfg, false, logger)
+	require.NoError(t, err)
+	cacheLayer := cachelayer.New(innerStore, cachelayer.Params{Logger: logger})
+	defer cacheLayer.Shutdown()
+
+	pluginAdapter := ws.NewPluginAdapter(&fakeClusterAPI{}, nil, cacheLayer, logger)
+	_, err = server.New(server.Params{
+		Cfg:                cfg,
+		DBStore:            cacheLayer,
+		Logger:             logger,
+		PermissionsService: localpermissions.New(cacheLayer, logger),
+		WSAdapter:          pluginAdapter,
+	})
+	require.NoError(t, err)
+
+	board, err := cacheLayer.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		Title:  "cached",
+	}, "user-id")
+	require.NoError(t, err)
+	_, err = cacheLayer.GetBoard(board.ID)
+	require.NoError(t, err)
+
+	// waits to avoid hitting the pk uniqueness constraint in history
+	time.Sleep(10 * time.Millisecond)
+
+	// another node of the cluster changes the board
+	title := "changed"
+	_, err = innerStore.PatchBoard(board.ID, &model.BoardPatch{Title: &title}, "user-id")
+	require.NoError(t, err)
+
+	cached, err := cacheLayer.GetBoard(board.ID)
+	require.NoError(t, err)
+	require.Equal(t, "cached", cached.Title)
+
+	pluginAdapter.HandleClusterEvent(mmModel.PluginClusterEvent{Id: cachelayer.ClusterEventInvalidate})
+
+	fetched, err := cacheLayer.GetBoard(board.ID)
+	require.NoError(t, err)
+	require.Equal(t, "changed", fetched.Title)
+}
@@ -0,0 +1,109 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypePrivate,
+		Title:  "board",
+	})
+	th.CheckOK(resp)
+
+	// waits to avoid hitting the pk uniqueness constraint in history
+	wait := func() { time.Sleep(10 * time.Millisecond) }
+
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: "card"}, true)
+	th.CheckOK(resp)
+	wait()
+
+	_, resp = th.Client.DeleteBlock(board.ID, card.ID, true)
+	th.CheckOK(resp)
+	wait()
+
+	t.Run("deleted cards are listed", func(t *testing.T) {
+		items, resp := th.Client.GetTrash(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, items, 1)
+		require.Equal(t, model.TrashItemTypeCard, items[0].Type)
+		require.Equal(t, card.ID, items[0].ID)
+		require.Equal(t, th.GetUser1().ID, items[0].DeletedBy)
+		require.NotZero(t, items[0].DeletedAt)
+	})
+
+	t.Run("users without access don't see the items", func(t *testing.T) {
+		items, resp := th.Client2.GetTrash(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, items)
+
+		_, resp = th.Client2.RestoreTrashedCard(board.ID, card.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.PurgeTrashedCard(board.ID, card.ID)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("restore a card", func(t *testing.T) {
+		restored, resp := th.Client.RestoreTrashedCard(board.ID, card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, card.ID, restored.ID)
+		wait()
+
+		_, resp = th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+
+		// a card that is not deleted can't be restored or purged
+		_, resp = th.Client.RestoreTrashedCard(board.ID, card.ID)
+		th.CheckNotFound(resp)
+		_, resp = th.Client.PurgeTrashedCard(board.ID, card.ID)
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("restore a board", func(t *testing.T) {
+		_, resp := th.Client.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+		wait()
+
+		items, resp := th.Client.GetTrash(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, items, 1)
+		require.Equal(t, model.TrashItemTypeBoard, items[0].Type)
+		require.Equal(t, board.ID, items[0].ID)
+
+		restored, resp := th.Client.RestoreTrashedBoard(board.ID)
+		th.CheckOK(resp)
+		require.Equal(t, board.ID, restored.ID)
+		wait()
+
+		_, resp = th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+	})
+
+	t.Run("purge a board", func(t *testing.T) {
+		_, resp := th.Client.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+		wait()
+
+		_, resp = th.Client2.PurgeTrashedBoard(board.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client.PurgeTrashedBoard(board.ID)
+		th.CheckOK(resp)
+
+		items, resp := th.Client.GetTrash(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, items)
+
+		_, resp = th.Client.RestoreTrashedBoard(board.ID)
+		th.CheckForbidden(resp)
+	})
+}
@@ -0,0 +1,105 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndo(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	th.Server.Config().UndoStackSize = 100
+	defer func() { th.Server.Config().UndoStackSize = 0 }()
+
+	board, resp := th.Client.CreateBoard(&model.Board{
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		Title:  "title",
+	})
+	th.CheckOK(resp)
+
+	// waits to avoid hitting the pk uniqueness constraint in history
+	wait := func() { time.Sleep(10 * time.Millisecond) }
+
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{BoardID: board.ID, Title: "card"}, true)
+	th.CheckOK(resp)
+	wait()
+
+	newTitle := "renamed card"
+	_, resp = th.Client.PatchCard(card.ID, &model.CardPatch{Title: &newTitle}, true)
+	th.CheckOK(resp)
+	wait()
+
+	t.Run("nothing to redo", func(t *testing.T) {
+		state, resp := th.Client.GetUndoState(board.ID)
+		th.CheckOK(resp)
+		require.NotNil(t, state.Undo)
+		require.Equal(t, model.UndoOperationPatchBlocks, state.Undo.Operation)
+		require.Nil(t, state.Redo)
+
+		_, resp = th.Client.Redo(board.ID, false)
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("other users can't undo", func(t *testing.T) {
+		_, resp := th.Client2.Undo(board.ID, false)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("undo and redo a card patch", func(t *testing.T) {
+		result, resp := th.Client.Undo(board.ID, false)
+		th.CheckOK(resp)
+		require.Equal(t, model.UndoOperationPatchBlocks, result.Entry.Operation)
+
+		fetched, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "card", fetched.Title)
+		wait()
+
+		_, resp = th.Client.Redo(board.ID, false)
+		th.CheckOK(resp)
+
+		fetched, resp = th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, newTitle, fetched.Title)
+		wait()
+	})
+
+	t.Run("undo a board patch", func(t *testing.T) {
+		boardTitle := "renamed board"
+		_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{Title: &boardTitle})
+		th.CheckOK(resp)
+		wait()
+
+		result, resp := th.Client.Undo(board.ID, false)
+		th.CheckOK(resp)
+		require.Equal(t, "title", result.Board.Title)
+
+		fetched, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		require.Equal(t, "title", fetched.Title)
+		wait()
+	})
+
+	t.Run("undo a card creation", func(t *testing.T) {
+		// the board patch was undone, so the card patch is next
+		_, resp := th.Client.Undo(board.ID, false)
+		th.CheckOK(resp)
+		wait()
+
+		result, resp := th.Client.Undo(board.ID, false)
+		th.CheckOK(resp)
+		require.Equal(t, model.UndoOperationInsertBlocks, result.Entry.Operation)
+		require.Equal(t, []string{card.ID}, result.DeletedBlockIDs)
+
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		for _, block := range blocks {
+			require.NotEqual(t, card.ID, block.ID)
+		}
+	})
+}
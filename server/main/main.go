@@ -65,6 +65,11 @@ func main() {
 		"",
 		"Location of the JSON config file",
 	)
+	pBackup := flag.Bool("backup", false, "Create a backup in the backup directory and exit")
+	pRestore := flag.String("restore", "", "Restore the backup archive at this location and exit")
+	pMigrateToDBType := flag.String("migrate-to-dbtype", "", "Copy the database to a database of this type and exit")
+	pMigrateToDBConfig := flag.String("migrate-to-dbconfig", "", "Config of the database to copy the database to")
+	pMigrateToDBTablePrefix := flag.String("migrate-to-dbtableprefix", "", "Table prefix of the database to copy the database to")
 	flag.Parse()
 
 	config, err := config.ReadConfigFile(*pConfigFilePath)
@@ -131,6 +136,18 @@ func main() {
 		config.Port = *pPort
 	}
 
+	if *pMigrateToDBType != "" {
+		targetConfig := *config
+		targetConfig.DBType = *pMigrateToDBType
+		targetConfig.DBConfigString = *pMigrateToDBConfig
+		targetConfig.DBTablePrefix = *pMigrateToDBTablePrefix
+		targetConfig.DBReplicaConfigStrings = nil
+		if err := runMigrateDatabaseCommand(config, &targetConfig, logger); err != nil {
+			logger.Fatal("Database migration ERROR", mlog.Err(err))
+		}
+		return
+	}
+
 	db, err := server.NewStore(config, singleUser, logger)
 	if err != nil {
 		logger.Fatal("server.NewStore ERROR", mlog.Err(err))
@@ -151,6 +168,15 @@ func main() {
 		logger.Fatal("server.New ERROR", mlog.Err(err))
 	}
 
+	if *pBackup || *pRestore != "" {
+		err = runBackupCommand(server, *pBackup, *pRestore, logger)
+		_ = db.Shutdown()
+		if err != nil {
+			logger.Fatal("Backup command ERROR", mlog.Err(err))
+		}
+		return
+	}
+
 	if err := server.Start(); err != nil {
 		logger.Fatal("server.Start ERROR", mlog.Err(err))
 	}
@@ -165,6 +191,47 @@ func main() {
 	_ = server.Shutdown()
 }
 
+// runBackupCommand creates a backup or restores a backup archive without
+// starting the server. The store migrations have already run, so a
+// restore checks the archive against the latest schema version.
+func runBackupCommand(srv *server.Server, backup bool, restorePath string, logger *mlog.Logger) error {
+	if backup {
+		info, err := srv.App().CreateBackup(time.Now())
+		if err != nil {
+			return err
+		}
+		logger.Info("Backup created", mlog.String("name", info.Name), mlog.Int("size", info.Size))
+		return nil
+	}
+
+	manifest, err := srv.App().RestoreBackup(restorePath)
+	if err != nil {
+		return err
+	}
+	logger.Info("Backup restored",
+		mlog.String("path", restorePath),
+		mlog.Int("schema_version", manifest.SchemaVersion),
+		mlog.Int("files", manifest.Files),
+	)
+	return nil
+}
+
+// runMigrateDatabaseCommand copies the configured database to the target
+// database, which can be of another type. The server must be stopped
+// while the command runs.
+func runMigrateDatabaseCommand(source, target *config.Configuration, logger *mlog.Logger) error {
+	tables, err := server.MigrateDatabase(source, target, logger)
+	for _, table := range tables {
+		logger.Info("Database migration",
+			mlog.String("table", table.Table),
+			mlog.Int("source_rows", table.SourceRows),
+			mlog.Int("target_rows", table.TargetRows),
+			mlog.Bool("resumed", table.Resumed),
+		)
+	}
+	return err
+}
+
 // StartServer starts the server
 //
 //export StartServer
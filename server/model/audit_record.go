@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const (
+	AuditRecordsDefaultPerPage = 100
+	AuditRecordsMaxPerPage     = 1000
+)
+
+var ErrInvalidAuditCursor = errors.New("invalid audit records cursor")
+
+// AuditRecord is an audit record stored in the database.
+// swagger:model
+type AuditRecord struct {
+	// The id of the record
+	// required: true
+	ID string `json:"id"`
+
+	// The time the record was stored, in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The audit level of the record: auth, mod or read
+	// required: true
+	Level string `json:"level"`
+
+	// The audited event
+	// required: true
+	Event string `json:"event"`
+
+	// The status of the event: attempt, success or fail
+	// required: true
+	Status string `json:"status"`
+
+	// The id of the user that caused the event
+	// required: false
+	UserID string `json:"userId"`
+
+	// The id of the session of the user
+	// required: false
+	SessionID string `json:"sessionId"`
+
+	// The user agent of the client
+	// required: false
+	Client string `json:"client"`
+
+	// The IP address of the client
+	// required: false
+	IPAddress string `json:"ipAddress"`
+
+	// The path of the API called
+	// required: false
+	APIPath string `json:"apiPath"`
+
+	// The id of the team of the event, if any
+	// required: false
+	TeamID string `json:"teamId"`
+
+	// The id of the board of the event, if any
+	// required: false
+	BoardID string `json:"boardId"`
+
+	// The metadata of the event
+	// required: false
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// AuditRecordFromRecord converts a record of the audit service to a
+// record that can be stored in the database.
+func AuditRecordFromRecord(level string, rec *audit.Record) *AuditRecord {
+	record := &AuditRecord{
+		ID:        utils.NewID(utils.IDTypeNone),
+		CreateAt:  utils.GetMillis(),
+		Level:     level,
+		Event:     rec.Event,
+		Status:    rec.Status,
+		UserID:    rec.UserID,
+		SessionID: rec.SessionID,
+		Client:    rec.Client,
+		IPAddress: rec.IPAddress,
+		APIPath:   rec.APIPath,
+		Meta:      map[string]interface{}{},
+	}
+
+	for _, meta := range rec.Meta {
+		switch meta.K {
+		case audit.KeyTeamID, "teamID":
+			record.TeamID = fmt.Sprint(meta.V)
+		case "boardID", "board_id":
+			record.BoardID = fmt.Sprint(meta.V)
+		}
+		record.Meta[meta.K] = meta.V
+	}
+	return record
+}
+
+// Cursor returns the cursor to pass to get the records that follow this
+// one.
+func (r *AuditRecord) Cursor() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(r.CreateAt, 10) + ":" + r.ID))
+}
+
+// ParseAuditCursor returns the creation time and the id of the record a
+// cursor points to.
+func ParseAuditCursor(cursor string) (int64, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", ErrInvalidAuditCursor
+	}
+	strCreateAt, id, ok := strings.Cut(string(data), ":")
+	if !ok || id == "" {
+		return 0, "", ErrInvalidAuditCursor
+	}
+	createAt, err := strconv.ParseInt(strCreateAt, 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidAuditCursor
+	}
+	return createAt, id, nil
+}
+
+// AuditRecordsResponse is the response body to a request for audit records.
+// swagger:model
+type AuditRecordsResponse struct {
+	// True if there is a next page for pagination
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The cursor to pass to get the next page, if any
+	// required: false
+	NextCursor string `json:"nextCursor,omitempty"`
+
+	// The array of audit records, the most recent first
+	// required: true
+	Results []*AuditRecord `json:"results"`
+}
+
+type QueryAuditRecordsOptions struct {
+	UserID  string // if not empty then filter for the records of a user
+	Event   string // if not empty then filter for the records of an event
+	BoardID string // if not empty then filter for the records of a board
+	Status  string // if not empty then filter for the records with a status
+	Since   int64  // if non-zero then filter for the records created at or after Since
+	Until   int64  // if non-zero then filter for the records created before Until
+	Cursor  string // if not empty then select the records following the cursor
+	PerPage int    // number of records per page (default=100)
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+type AutomationTriggerType string
+
+const (
+	// AutomationTriggerCardCreated fires when a card is added to the board.
+	AutomationTriggerCardCreated AutomationTriggerType = "cardCreated"
+	// AutomationTriggerPropertyChanged fires when a property of a card
+	// changes, optionally to a given value.
+	AutomationTriggerPropertyChanged AutomationTriggerType = "propertyChanged"
+	// AutomationTriggerDateReached fires when the date of a date property
+	// of a card is reached.
+	AutomationTriggerDateReached AutomationTriggerType = "dateReached"
+	// AutomationTriggerCommentAdded fires when a comment is added to a card.
+	AutomationTriggerCommentAdded AutomationTriggerType = "commentAdded"
+)
+
+type AutomationActionType string
+
+const (
+	AutomationActionSetProperty  AutomationActionType = "setProperty"
+	AutomationActionAssignPerson AutomationActionType = "assignPerson"
+	AutomationActionMoveToBoard  AutomationActionType = "moveToBoard"
+	AutomationActionAddChecklist AutomationActionType = "addChecklist"
+	AutomationActionPostComment  AutomationActionType = "postComment"
+	AutomationActionCallWebhook  AutomationActionType = "callWebhook"
+)
+
+type AutomationExecutionStatus string
+
+const (
+	AutomationExecutionRunning AutomationExecutionStatus = "running"
+	AutomationExecutionSuccess AutomationExecutionStatus = "success"
+	AutomationExecutionError   AutomationExecutionStatus = "error"
+)
+
+// AutomationTrigger is the event starting an automation rule
+// swagger:model
+type AutomationTrigger struct {
+	// The trigger type
+	// required: true
+	Type AutomationTriggerType `json:"type"`
+
+	// The id of the property, for the propertyChanged and dateReached triggers
+	// required: false
+	PropertyID string `json:"propertyId,omitempty"`
+
+	// The value the property changes to for the propertyChanged trigger, any value if empty
+	// required: false
+	Value string `json:"value,omitempty"`
+}
+
+// AutomationAction is an action run by an automation rule
+// swagger:model
+type AutomationAction struct {
+	// The action type
+	// required: true
+	Type AutomationActionType `json:"type"`
+
+	// The id of the property, for the setProperty and assignPerson actions
+	// required: false
+	PropertyID string `json:"propertyId,omitempty"`
+
+	// The value to set for the setProperty action, or the id of the user for the assignPerson action
+	// required: false
+	Value string `json:"value,omitempty"`
+
+	// The id of the destination board for the moveToBoard action
+	// required: false
+	BoardID string `json:"boardId,omitempty"`
+
+	// The checklist items for the addChecklist action
+	// required: false
+	Items []string `json:"items,omitempty"`
+
+	// The text of the comment for the postComment action
+	// required: false
+	Text string `json:"text,omitempty"`
+
+	// The url called for the callWebhook action
+	// required: false
+	URL string `json:"url,omitempty"`
+}
+
+// AutomationRule runs actions on the cards of a board when a trigger fires
+// swagger:model
+type AutomationRule struct {
+	// The id of the rule
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the board of the rule
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The title of the rule
+	// required: false
+	Title string `json:"title"`
+
+	// Indicates if the rule is run
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// The event starting the rule
+	// required: true
+	Trigger AutomationTrigger `json:"trigger"`
+
+	// The actions run, in order
+	// required: true
+	Actions []AutomationAction `json:"actions"`
+
+	// The id of the user that created or last updated the rule, the actions are run as this user
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The id of the user that last modified the rule
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// AutomationActionResult is the outcome of an action of an automation rule
+// swagger:model
+type AutomationActionResult struct {
+	// The action type
+	// required: true
+	Type AutomationActionType `json:"type"`
+
+	// A description of what the action did, or would do for a dry run
+	// required: true
+	Detail string `json:"detail"`
+
+	// The error of the action, if it failed
+	// required: false
+	Error string `json:"error,omitempty"`
+}
+
+// AutomationExecution is an entry of the execution log of an automation rule
+// swagger:model
+type AutomationExecution struct {
+	// The id of the execution
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the rule
+	// required: true
+	RuleID string `json:"ruleId"`
+
+	// The id of the board of the rule
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The id of the card the rule ran on
+	// required: true
+	CardID string `json:"cardId"`
+
+	// Identifies the occurrence of the trigger, so that it runs the rule
+	// once. Empty for triggers that can run the rule several times.
+	// required: false
+	TriggerKey string `json:"triggerKey,omitempty"`
+
+	// The status of the execution
+	// required: true
+	Status AutomationExecutionStatus `json:"status"`
+
+	// Indicates if the actions were only simulated
+	// required: false
+	DryRun bool `json:"dryRun"`
+
+	// The outcome of each action
+	// required: true
+	Results []AutomationActionResult `json:"results"`
+
+	// The time of the execution in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// ErrInvalidAutomationRule is returned when an automation rule is not valid.
+type ErrInvalidAutomationRule struct {
+	msg string
+}
+
+func (e ErrInvalidAutomationRule) Error() string {
+	return fmt.Sprintf("invalid automation rule: %s", e.msg)
+}
+
+func (r *AutomationRule) IsValid() error {
+	if r == nil {
+		return ErrInvalidAutomationRule{"cannot be nil"}
+	}
+	if r.ID == "" {
+		return ErrInvalidAutomationRule{"missing id"}
+	}
+	if r.BoardID == "" {
+		return ErrInvalidAutomationRule{"missing board id"}
+	}
+	if r.CreatedBy == "" {
+		return ErrInvalidAutomationRule{"missing created by"}
+	}
+	if err := r.Trigger.IsValid(); err != nil {
+		return err
+	}
+	if len(r.Actions) == 0 {
+		return ErrInvalidAutomationRule{"missing actions"}
+	}
+	for _, action := range r.Actions {
+		if err := action.IsValid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t AutomationTrigger) IsValid() error {
+	switch t.Type {
+	case AutomationTriggerCardCreated, AutomationTriggerCommentAdded:
+	case AutomationTriggerPropertyChanged, AutomationTriggerDateReached:
+		if t.PropertyID == "" {
+			return ErrInvalidAutomationRule{fmt.Sprintf("missing property id for trigger %s", t.Type)}
+		}
+	default:
+		return ErrInvalidAutomationRule{fmt.Sprintf("unknown trigger %q", t.Type)}
+	}
+	return nil
+}
+
+func (a AutomationAction) IsValid() error {
+	switch a.Type {
+	case AutomationActionSetProperty:
+		if a.PropertyID == "" {
+			return ErrInvalidAutomationRule{"missing property id for action setProperty"}
+		}
+	case AutomationActionAssignPerson:
+		if a.PropertyID == "" || a.Value == "" {
+			return ErrInvalidAutomationRule{"missing property id or user id for action assignPerson"}
+		}
+	case AutomationActionMoveToBoard:
+		if a.BoardID == "" {
+			return ErrInvalidAutomationRule{"missing board id for action moveToBoard"}
+		}
+	case AutomationActionAddChecklist:
+		if len(a.Items) == 0 {
+			return ErrInvalidAutomationRule{"missing items for action addChecklist"}
+		}
+	case AutomationActionPostComment:
+		if a.Text == "" {
+			return ErrInvalidAutomationRule{"missing text for action postComment"}
+		}
+	case AutomationActionCallWebhook:
+		u, err := url.Parse(a.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidAutomationRule{fmt.Sprintf("invalid url %q for action callWebhook", a.URL)}
+		}
+	default:
+		return ErrInvalidAutomationRule{fmt.Sprintf("unknown action %q", a.Type)}
+	}
+	return nil
+}
+
+func AutomationRuleFromJSON(data io.Reader) (*AutomationRule, error) {
+	var rule AutomationRule
+	if err := json.NewDecoder(data).Decode(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
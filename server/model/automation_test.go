@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationRuleIsValid(t *testing.T) {
+	validRule := func() *AutomationRule {
+		return &AutomationRule{
+			ID:        "rule-id",
+			BoardID:   "board-id",
+			CreatedBy: "user-id",
+			Trigger:   AutomationTrigger{Type: AutomationTriggerCardCreated},
+			Actions:   []AutomationAction{{Type: AutomationActionPostComment, Text: "hello"}},
+		}
+	}
+	require.NoError(t, validRule().IsValid())
+
+	testCases := []struct {
+		name   string
+		modify func(r *AutomationRule)
+	}{
+		{"missing board id", func(r *AutomationRule) { r.BoardID = "" }},
+		{"unknown trigger", func(r *AutomationRule) { r.Trigger.Type = "unknown" }},
+		{"property trigger without property", func(r *AutomationRule) { r.Trigger.Type = AutomationTriggerPropertyChanged }},
+		{"no actions", func(r *AutomationRule) { r.Actions = nil }},
+		{"unknown action", func(r *AutomationRule) { r.Actions[0].Type = "unknown" }},
+		{"assign without user", func(r *AutomationRule) {
+			r.Actions[0] = AutomationAction{Type: AutomationActionAssignPerson, PropertyID: "owner"}
+		}},
+		{"empty checklist", func(r *AutomationRule) { r.Actions[0] = AutomationAction{Type: AutomationActionAddChecklist} }},
+		{"webhook without http url", func(r *AutomationRule) {
+			r.Actions[0] = AutomationAction{Type: AutomationActionCallWebhook, URL: "file:///etc/passwd"}
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := validRule()
+			tc.modify(rule)
+			require.ErrorAs(t, rule.IsValid(), &ErrInvalidAutomationRule{})
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// BackupFormatVersion is the version of the backup archives written by
+// this server.
+const BackupFormatVersion = 1
+
+// BackupManifest describes the content of a backup archive.
+// swagger:model
+type BackupManifest struct {
+	// The version of the archive format
+	// required: true
+	FormatVersion int `json:"formatVersion"`
+
+	// The backup creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The type of the database that was backed up
+	// required: true
+	DBType string `json:"dbType"`
+
+	// The schema version of the database, from its applied migrations
+	// required: true
+	SchemaVersion int `json:"schemaVersion"`
+
+	// The prefix of the database tables
+	// required: false
+	TablePrefix string `json:"tablePrefix"`
+
+	// The number of rows of each table, by table name without prefix
+	// required: true
+	Tables map[string]int64 `json:"tables"`
+
+	// The number of files copied from the file store
+	// required: true
+	Files int `json:"files"`
+}
+
+// BackupInfo describes a backup archive of the backup directory.
+// swagger:model
+type BackupInfo struct {
+	// The file name of the archive
+	// required: true
+	Name string `json:"name"`
+
+	// The size of the archive in bytes
+	// required: true
+	Size int64 `json:"size"`
+
+	// The modification time of the archive in milliseconds since the
+	// current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+func BackupManifestFromJSON(data io.Reader) (*BackupManifest, error) {
+	var manifest *BackupManifest
+	if err := json.NewDecoder(data).Decode(&manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// DatabaseDumpWriter receives the rows of the tables during a logical
+// dump of the database. Rows are keyed by column name.
+type DatabaseDumpWriter interface {
+	WriteRow(table string, row map[string]interface{}) error
+}
+
+// DatabaseDumpReader gives access to the rows of the tables of a logical
+// dump of the database. Table names have no prefix.
+type DatabaseDumpReader interface {
+	Tables() []string
+	ReadRows(table string, fn func(row map[string]interface{}) error) error
+}
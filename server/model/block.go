@@ -69,6 +69,10 @@ type Block struct {
 	// required: false
 	DeleteAt int64 `json:"deleteAt"`
 
+	// The archived time in miliseconds since the current epoch. Set to indicate this card is archived
+	// required: false
+	ArchiveAt int64 `json:"archiveAt"`
+
 	// Deprecated. The workspace id that the block belongs to
 	// required: false
 	WorkspaceID string `json:"-"`
@@ -211,6 +215,8 @@ type QueryBlocksOptions struct {
 	BlockType BlockType // if not empty and not `TypeUnknown` then filter for records of specified block type
 	Page      int       // page number to select when paginating
 	PerPage   int       // number of blocks per page (default=-1, meaning unlimited)
+
+	ExcludeArchived bool // if true then filter out the archived blocks
 }
 
 // QuerySubtreeOptions are query options that can be passed to GetSubTree methods.
@@ -278,6 +284,7 @@ func (b *Block) GetLimited() *Block {
 		CreateAt:    b.CreateAt,
 		UpdateAt:    b.UpdateAt,
 		DeleteAt:    b.DeleteAt,
+		ArchiveAt:   b.ArchiveAt,
 		WorkspaceID: b.WorkspaceID,
 		Limited:     true,
 	}
@@ -290,3 +297,26 @@ func (b *Block) GetLimited() *Block {
 
 	return newBlock
 }
+
+// FilterArchivedBlocks returns the blocks that are neither archived cards
+// nor the content of an archived card.
+func FilterArchivedBlocks(blocks []*Block) []*Block {
+	archived := map[string]bool{}
+	for _, block := range blocks {
+		if block.Type == TypeCard && block.ArchiveAt != 0 {
+			archived[block.ID] = true
+		}
+	}
+	if len(archived) == 0 {
+		return blocks
+	}
+
+	filtered := make([]*Block, 0, len(blocks)-len(archived))
+	for _, block := range blocks {
+		if archived[block.ID] || archived[block.ParentID] {
+			continue
+		}
+		filtered = append(filtered, block)
+	}
+	return filtered
+}
@@ -307,3 +307,21 @@ func TestStampModificationMetadata(t *testing.T) {
 		assert.NotEmpty(t, blocks[0].UpdateAt)
 	})
 }
+
+func TestFilterArchivedBlocks(t *testing.T) {
+	board := &Block{ID: "board", Type: TypeBoard}
+	card := &Block{ID: "card", ParentID: "board", Type: TypeCard}
+	archived := &Block{ID: "archived", ParentID: "board", Type: TypeCard, ArchiveAt: 100}
+	comment := &Block{ID: "comment", ParentID: "archived", Type: TypeComment}
+	text := &Block{ID: "text", ParentID: "card", Type: TypeText}
+
+	t.Run("removes the archived cards and their content", func(t *testing.T) {
+		blocks := FilterArchivedBlocks([]*Block{board, card, archived, comment, text})
+		require.Equal(t, []*Block{board, card, text}, blocks)
+	})
+
+	t.Run("keeps the blocks without archived cards", func(t *testing.T) {
+		blocks := FilterArchivedBlocks([]*Block{board, card, text})
+		require.Equal(t, []*Block{board, card, text}, blocks)
+	})
+}
@@ -103,6 +103,10 @@ type Board struct {
 	// The deleted time in miliseconds since the current epoch. Set to indicate this block is deleted
 	// required: false
 	DeleteAt int64 `json:"deleteAt"`
+
+	// The archived time in miliseconds since the current epoch. Set to indicate this board is archived
+	// required: false
+	ArchiveAt int64 `json:"archiveAt"`
 }
 
 // GetPropertyString returns the value of the specified property as a string,
@@ -166,6 +170,17 @@ type BoardPatch struct {
 	// The board removed card properties
 	// required: false
 	DeletedCardProperties []string `json:"deletedCardProperties"`
+
+	// The replacement of the removed select options, by property id
+	// and removed option id. The card values of the removed options
+	// without a replacement are deleted
+	// required: false
+	RemappedOptions map[string]map[string]string `json:"remappedOptions,omitempty"`
+
+	// Archives the card values of the removed properties and options
+	// so they can be restored
+	// required: false
+	ArchiveRemovedValues bool `json:"archiveRemovedValues,omitempty"`
 }
 
 // BoardMember stores the information of the membership of a user on a board
@@ -232,6 +247,17 @@ type BoardMetadata struct {
 	LastModifiedBy string `json:"lastModifiedBy"`
 }
 
+// FilterArchivedBoards returns the boards that aren't archived.
+func FilterArchivedBoards(boards []*Board) []*Board {
+	filtered := make([]*Board, 0, len(boards))
+	for _, board := range boards {
+		if board.ArchiveAt == 0 {
+			filtered = append(filtered, board)
+		}
+	}
+	return filtered
+}
+
 func BoardFromJSON(data io.Reader) *Board {
 	var board *Board
 	_ = json.NewDecoder(data).Decode(&board)
@@ -0,0 +1,67 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// BoardExportFormat is the file format of a board export.
+type BoardExportFormat string
+
+const (
+	// BoardExportFormatCSV exports the cards of a board as a CSV table.
+	BoardExportFormatCSV BoardExportFormat = "csv"
+
+	// BoardExportFormatXLSX exports the cards of a board as an Excel
+	// workbook.
+	BoardExportFormatXLSX BoardExportFormat = "xlsx"
+
+	// BoardExportFormatMarkdown exports a board as a zip of Markdown
+	// pages.
+	BoardExportFormatMarkdown BoardExportFormat = "markdown"
+
+	// BoardExportFormatHTML exports a board as a zip of HTML pages.
+	BoardExportFormatHTML BoardExportFormat = "html"
+)
+
+// IsValid returns true if the format is supported.
+func (f BoardExportFormat) IsValid() bool {
+	return f.ContentType() != ""
+}
+
+// ContentType returns the MIME type of the exported files.
+func (f BoardExportFormat) ContentType() string {
+	switch f {
+	case BoardExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case BoardExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case BoardExportFormatMarkdown, BoardExportFormatHTML:
+		return "application/zip"
+	}
+	return ""
+}
+
+// Extension returns the file extension of the exported files.
+func (f BoardExportFormat) Extension() string {
+	if f.IsTable() {
+		return string(f)
+	}
+	return "zip"
+}
+
+// IsTable returns true if the format exports the cards as a table, and
+// false if it exports pages.
+func (f BoardExportFormat) IsTable() bool {
+	return f == BoardExportFormatCSV || f == BoardExportFormatXLSX
+}
+
+// BoardExportOptions provides options when exporting a board.
+type BoardExportOptions struct {
+	BoardID string
+
+	// ViewID is the view whose filter, sort and visible properties are
+	// applied. Empty exports all the cards and properties, and all the
+	// views for the page formats.
+	ViewID string
+
+	Format BoardExportFormat
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"reflect"
+	"sort"
+)
+
+// BoardVersion is a point in the history of a board where the board or
+// some of its blocks changed
+// swagger:model
+type BoardVersion struct {
+	// The time of the changes, in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The id of the user that made the changes
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// Indicates if the board itself changed
+	// required: true
+	BoardChanged bool `json:"boardChanged"`
+
+	// The number of blocks that changed
+	// required: true
+	BlockCount int `json:"blockCount"`
+}
+
+// BoardSnapshot is a board and its blocks as they were at a given time
+// swagger:model
+type BoardSnapshot struct {
+	// The time of the snapshot, in miliseconds since the current epoch
+	// required: true
+	At int64 `json:"at"`
+
+	// The board
+	// required: true
+	Board *Board `json:"board"`
+
+	// The blocks of the board
+	// required: true
+	Blocks []*Block `json:"blocks"`
+}
+
+// BlockChange is a block before and after a change
+// swagger:model
+type BlockChange struct {
+	// The block before the change
+	// required: true
+	Before *Block `json:"before"`
+
+	// The block after the change
+	// required: true
+	After *Block `json:"after"`
+}
+
+// BoardSnapshotDiff holds the changes between two snapshots of a board
+// swagger:model
+type BoardSnapshotDiff struct {
+	// The time of the older snapshot
+	// required: true
+	From int64 `json:"from"`
+
+	// The time of the newer snapshot
+	// required: true
+	To int64 `json:"to"`
+
+	// The board before the changes, if the board changed
+	// required: false
+	BoardBefore *Board `json:"boardBefore,omitempty"`
+
+	// The board after the changes, if the board changed
+	// required: false
+	BoardAfter *Board `json:"boardAfter,omitempty"`
+
+	// The blocks added between the snapshots
+	// required: true
+	Added []*Block `json:"added"`
+
+	// The blocks deleted between the snapshots
+	// required: true
+	Deleted []*Block `json:"deleted"`
+
+	// The blocks modified between the snapshots
+	// required: true
+	Modified []BlockChange `json:"modified"`
+}
+
+// BoardSnapshotRestoreRequest is the request to restore a board, or
+// some of its cards, as they were at a given time
+// swagger:model
+type BoardSnapshotRestoreRequest struct {
+	// The time to restore, in miliseconds since the current epoch
+	// required: true
+	At int64 `json:"at"`
+
+	// The ids of the cards to restore with their content. If empty,
+	// the whole board is restored
+	// required: false
+	CardIDs []string `json:"cardIds"`
+}
+
+// BoardSnapshotRestore holds the changes made by a restore
+// swagger:model
+type BoardSnapshotRestore struct {
+	// The board, if it was restored
+	// required: false
+	Board *Board `json:"board,omitempty"`
+
+	// The restored blocks
+	// required: true
+	Blocks []*Block `json:"blocks"`
+
+	// The ids of the blocks deleted by the restore
+	// required: true
+	DeletedBlockIDs []string `json:"deletedBlockIds"`
+}
+
+// BoardContentEqual compares the content of two versions of a board,
+// ignoring the membership, sharing and modification fields.
+func BoardContentEqual(a, b *Board) bool {
+	return a.Title == b.Title &&
+		a.Description == b.Description &&
+		a.Icon == b.Icon &&
+		a.ShowDescription == b.ShowDescription &&
+		reflect.DeepEqual(a.Properties, b.Properties) &&
+		reflect.DeepEqual(a.CardProperties, b.CardProperties)
+}
+
+// BlockContentEqual compares the content of two versions of a block,
+// ignoring the modification fields.
+func BlockContentEqual(a, b *Block) bool {
+	return a.ParentID == b.ParentID &&
+		a.Type == b.Type &&
+		a.Title == b.Title &&
+		a.Schema == b.Schema &&
+		a.ArchiveAt == b.ArchiveAt &&
+		reflect.DeepEqual(a.Fields, b.Fields)
+}
+
+// DiffBoardSnapshots returns the changes from one snapshot of a board to
+// a newer one. The blocks are sorted by id.
+func DiffBoardSnapshots(from, to *BoardSnapshot) *BoardSnapshotDiff {
+	diff := &BoardSnapshotDiff{
+		From:     from.At,
+		To:       to.At,
+		Added:    []*Block{},
+		Deleted:  []*Block{},
+		Modified: []BlockChange{},
+	}
+
+	if !BoardContentEqual(from.Board, to.Board) {
+		diff.BoardBefore = from.Board
+		diff.BoardAfter = to.Board
+	}
+
+	fromBlocks := make(map[string]*Block, len(from.Blocks))
+	for _, block := range from.Blocks {
+		fromBlocks[block.ID] = block
+	}
+
+	toIDs := make(map[string]bool, len(to.Blocks))
+	for _, block := range to.Blocks {
+		toIDs[block.ID] = true
+		before, ok := fromBlocks[block.ID]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, block)
+		case !BlockContentEqual(before, block):
+			diff.Modified = append(diff.Modified, BlockChange{Before: before, After: block})
+		}
+	}
+
+	for _, block := range from.Blocks {
+		if !toIDs[block.ID] {
+			diff.Deleted = append(diff.Deleted, block)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].ID < diff.Added[j].ID })
+	sort.Slice(diff.Deleted, func(i, j int) bool { return diff.Deleted[i].ID < diff.Deleted[j].ID })
+	sort.Slice(diff.Modified, func(i, j int) bool { return diff.Modified[i].After.ID < diff.Modified[j].After.ID })
+	return diff
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffBoardSnapshots(t *testing.T) {
+	board := &Board{ID: "board", Title: "title"}
+	kept := &Block{ID: "kept", Title: "kept", ModifiedBy: "user1"}
+	keptUpdated := &Block{ID: "kept", Title: "kept", ModifiedBy: "user2", UpdateAt: 10}
+	modified := &Block{ID: "modified", Title: "before"}
+	modifiedAfter := &Block{ID: "modified", Title: "after"}
+	deleted := &Block{ID: "deleted"}
+	added := &Block{ID: "added"}
+
+	t.Run("block changes", func(t *testing.T) {
+		from := &BoardSnapshot{At: 1, Board: board, Blocks: []*Block{kept, modified, deleted}}
+		to := &BoardSnapshot{At: 2, Board: board, Blocks: []*Block{added, keptUpdated, modifiedAfter}}
+
+		diff := DiffBoardSnapshots(from, to)
+		require.Equal(t, int64(1), diff.From)
+		require.Equal(t, int64(2), diff.To)
+		require.Nil(t, diff.BoardBefore)
+		require.Nil(t, diff.BoardAfter)
+		require.Equal(t, []*Block{added}, diff.Added)
+		require.Equal(t, []*Block{deleted}, diff.Deleted)
+		require.Equal(t, []BlockChange{{Before: modified, After: modifiedAfter}}, diff.Modified)
+	})
+
+	t.Run("board changes", func(t *testing.T) {
+		renamed := &Board{ID: "board", Title: "renamed"}
+		diff := DiffBoardSnapshots(&BoardSnapshot{Board: board}, &BoardSnapshot{Board: renamed})
+		require.Equal(t, board, diff.BoardBefore)
+		require.Equal(t, renamed, diff.BoardAfter)
+		require.Empty(t, diff.Added)
+		require.Empty(t, diff.Deleted)
+		require.Empty(t, diff.Modified)
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidBulkOperation = errors.New("invalid bulk operation")
+
+type BulkOperationType string
+
+const (
+	BulkOperationSetProperty   BulkOperationType = "setProperty"
+	BulkOperationClearProperty BulkOperationType = "clearProperty"
+	BulkOperationMoveToBoard   BulkOperationType = "moveToBoard"
+	BulkOperationArchive       BulkOperationType = "archive"
+	BulkOperationDelete        BulkOperationType = "delete"
+	BulkOperationAddSubscriber BulkOperationType = "addSubscriber"
+)
+
+type BulkOperationStatus string
+
+const (
+	BulkOperationStatusRunning   BulkOperationStatus = "running"
+	BulkOperationStatusCompleted BulkOperationStatus = "completed"
+	BulkOperationStatusFailed    BulkOperationStatus = "failed"
+)
+
+// BulkOperationRequest applies an operation to every card of a board
+// matching a filter
+// swagger:model
+type BulkOperationRequest struct {
+	// The filter that selects the cards. A missing filter selects all the
+	// cards of the board
+	// required: false
+	Filter *CardFilter `json:"filter,omitempty"`
+
+	// The operation: setProperty, clearProperty, moveToBoard, archive,
+	// delete or addSubscriber
+	// required: true
+	Type BulkOperationType `json:"type"`
+
+	// The property to set or clear
+	// required: false
+	PropertyID string `json:"propertyId,omitempty"`
+
+	// The value to set: an option id, a text, or a list of option ids
+	// required: false
+	Value interface{} `json:"value,omitempty"`
+
+	// The board to move the cards to
+	// required: false
+	TargetBoardID string `json:"targetBoardId,omitempty"`
+
+	// The user to subscribe to the cards
+	// required: false
+	SubscriberID string `json:"subscriberId,omitempty"`
+}
+
+// IsValid checks that the request has the fields its operation needs.
+func (r *BulkOperationRequest) IsValid() error {
+	if r.Filter != nil {
+		if err := r.Filter.IsValid(); err != nil {
+			return err
+		}
+	}
+
+	switch r.Type {
+	case BulkOperationSetProperty:
+		if r.PropertyID == "" {
+			return fmt.Errorf("%w: missing property", ErrInvalidBulkOperation)
+		}
+		if !isBulkPropertyValue(r.Value) {
+			return fmt.Errorf("%w: the value must be a string or a list of strings", ErrInvalidBulkOperation)
+		}
+	case BulkOperationClearProperty:
+		if r.PropertyID == "" {
+			return fmt.Errorf("%w: missing property", ErrInvalidBulkOperation)
+		}
+	case BulkOperationMoveToBoard:
+		if r.TargetBoardID == "" {
+			return fmt.Errorf("%w: missing target board", ErrInvalidBulkOperation)
+		}
+	case BulkOperationAddSubscriber:
+		if r.SubscriberID == "" {
+			return fmt.Errorf("%w: missing subscriber", ErrInvalidBulkOperation)
+		}
+	case BulkOperationArchive, BulkOperationDelete:
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidBulkOperation, r.Type)
+	}
+	return nil
+}
+
+func isBulkPropertyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string, []string:
+		return true
+	case []interface{}:
+		for _, item := range v {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// IsUndoable returns true if the changes of the operation are recorded
+// in the undo stack. Subscriptions aren't part of the board content.
+func (r *BulkOperationRequest) IsUndoable() bool {
+	return r.Type != BulkOperationAddSubscriber
+}
+
+// BulkOperation is the progress of a bulk operation on the cards of a
+// board. Large operations run in the background
+// swagger:model
+type BulkOperation struct {
+	// The id of the operation
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the board of the cards
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The id of the user that started the operation
+	// required: true
+	UserID string `json:"userId"`
+
+	// The operation applied to the cards
+	// required: true
+	Type BulkOperationType `json:"type"`
+
+	// The status of the operation: running, completed or failed
+	// required: true
+	Status BulkOperationStatus `json:"status"`
+
+	// The number of cards that matched the filter
+	// required: true
+	Total int `json:"total"`
+
+	// The number of cards processed so far
+	// required: true
+	Processed int `json:"processed"`
+
+	// The reason of the failure of the operation
+	// required: false
+	Error string `json:"error,omitempty"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last update time in miliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// IsDone returns true if the operation doesn't run anymore.
+func (o *BulkOperation) IsDone() bool {
+	return o.Status != BulkOperationStatusRunning
+}
@@ -84,6 +84,34 @@ type Card struct {
 	// The deleted time in milliseconds since the current epoch. Set to indicate this card is deleted
 	// required: false
 	DeleteAt int64 `json:"deleteAt"`
+
+	// The archived time in milliseconds since the current epoch. Set to indicate this card is archived
+	// required: false
+	ArchiveAt int64 `json:"archiveAt"`
+
+	// True if the card is blocked by a card that is not completed. Computed by the server
+	// required: false
+	Blocked bool `json:"blocked"`
+
+	// For template cards, a map of property ids to the default values of the cards created from the template
+	// required: false
+	TemplateDefaults map[string]string `json:"templateDefaults,omitempty"`
+
+	// For cards moved to another board and kept as a redirect, the id of the moved card
+	// required: false
+	MovedToCardID string `json:"movedToCardId,omitempty"`
+
+	// For cards moved to another board and kept as a redirect, the id of the board of the moved card
+	// required: false
+	MovedToBoardID string `json:"movedToBoardId,omitempty"`
+
+	// For moved cards, the id of the card they were moved from
+	// required: false
+	MovedFromCardID string `json:"movedFromCardId,omitempty"`
+
+	// For moved cards, the id of the board they were moved from
+	// required: false
+	MovedFromBoardID string `json:"movedFromBoardId,omitempty"`
 }
 
 // Populate populates a Card with default values.
@@ -137,6 +165,17 @@ func (c *Card) CheckValid() error {
 	return nil
 }
 
+// movedFields returns the fields linking a moved card to its copy or its
+// origin.
+func (c *Card) movedFields() map[string]string {
+	return map[string]string{
+		CardFieldMovedToCardID:    c.MovedToCardID,
+		CardFieldMovedToBoardID:   c.MovedToBoardID,
+		CardFieldMovedFromCardID:  c.MovedFromCardID,
+		CardFieldMovedFromBoardID: c.MovedFromBoardID,
+	}
+}
+
 // CardPatch is a patch for modifying cards
 // swagger:model
 type CardPatch struct {
@@ -155,6 +194,10 @@ type CardPatch struct {
 	// A map of property ids to property option ids to be updated
 	// required: false
 	UpdatedProperties map[string]any `json:"updatedProperties"`
+
+	// The default property values of the cards created from the template, replacing the existing ones
+	// required: false
+	TemplateDefaults map[string]string `json:"templateDefaults"`
 }
 
 // Patch returns an updated version of the card.
@@ -181,6 +224,10 @@ func (p *CardPatch) Patch(card *Card) *Card {
 		card.Properties[propID] = propVal
 	}
 
+	if p.TemplateDefaults != nil {
+		card.TemplateDefaults = p.TemplateDefaults
+	}
+
 	return card
 }
 
@@ -200,6 +247,14 @@ func Card2Block(card *Card) *Block {
 	fields["icon"] = card.Icon
 	fields["isTemplate"] = card.IsTemplate
 	fields["properties"] = card.Properties
+	if len(card.TemplateDefaults) != 0 {
+		fields[CardFieldTemplateDefaults] = card.TemplateDefaults
+	}
+	for field, value := range card.movedFields() {
+		if value != "" {
+			fields[field] = value
+		}
+	}
 
 	return &Block{
 		ID:         card.ID,
@@ -213,6 +268,7 @@ func Card2Block(card *Card) *Block {
 		CreateAt:   card.CreateAt,
 		UpdateAt:   card.UpdateAt,
 		DeleteAt:   card.DeleteAt,
+		ArchiveAt:  card.ArchiveAt,
 		BoardID:    card.BoardID,
 	}
 }
@@ -284,7 +340,15 @@ func Block2Card(block *Block) (*Card, error) {
 		CreateAt:     block.CreateAt,
 		UpdateAt:     block.UpdateAt,
 		DeleteAt:     block.DeleteAt,
+		ArchiveAt:    block.ArchiveAt,
+	}
+	if defaults := TemplateDefaults(block); len(defaults) != 0 {
+		card.TemplateDefaults = defaults
 	}
+	card.MovedToCardID, _ = block.Fields[CardFieldMovedToCardID].(string)
+	card.MovedToBoardID, _ = block.Fields[CardFieldMovedToBoardID].(string)
+	card.MovedFromCardID, _ = block.Fields[CardFieldMovedFromCardID].(string)
+	card.MovedFromBoardID, _ = block.Fields[CardFieldMovedFromBoardID].(string)
 	card.Populate()
 	return card, nil
 }
@@ -316,6 +380,9 @@ func CardPatch2BlockPatch(cardPatch *CardPatch) (*BlockPatch, error) {
 	if len(properties) != 0 {
 		updatedFields["properties"] = cardPatch.UpdatedProperties
 	}
+	if cardPatch.TemplateDefaults != nil {
+		updatedFields[CardFieldTemplateDefaults] = cardPatch.TemplateDefaults
+	}
 
 	blockPatch.UpdatedFields = updatedFields
 
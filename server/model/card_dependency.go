@@ -0,0 +1,155 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BoardPropertyCompletedOptionIDs is the board property holding the ids of the
+// select options that mark a card as completed, so it stops blocking other cards.
+const BoardPropertyCompletedOptionIDs = "completedOptionIds"
+
+var ErrCardDependencyCycle = errors.New("card dependency would create a cycle")
+
+// ErrInvalidCardDependency is returned when a card dependency is not valid.
+type ErrInvalidCardDependency struct {
+	msg string
+}
+
+func (e ErrInvalidCardDependency) Error() string {
+	return fmt.Sprintf("invalid card dependency: %s", e.msg)
+}
+
+// CardDependency is a "blocks / blocked by" relationship between two cards.
+// swagger:model
+type CardDependency struct {
+	// The id of the card that blocks the other one
+	// required: true
+	BlockerID string `json:"blockerId"`
+
+	// The id of the card blocked by the other one
+	// required: true
+	BlockedID string `json:"blockedId"`
+
+	// The id of the user that created the dependency
+	// required: false
+	CreatedBy string `json:"createdBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: false
+	CreateAt int64 `json:"createAt"`
+}
+
+func (d *CardDependency) IsValid() error {
+	if d == nil {
+		return ErrInvalidCardDependency{"cannot be nil"}
+	}
+	if d.BlockerID == "" {
+		return ErrInvalidCardDependency{"missing blocker id"}
+	}
+	if d.BlockedID == "" {
+		return ErrInvalidCardDependency{"missing blocked id"}
+	}
+	if d.BlockerID == d.BlockedID {
+		return ErrInvalidCardDependency{"a card cannot block itself"}
+	}
+	return nil
+}
+
+func CardDependencyFromJSON(data io.Reader) (*CardDependency, error) {
+	var dependency CardDependency
+	if err := json.NewDecoder(data).Decode(&dependency); err != nil {
+		return nil, err
+	}
+	return &dependency, nil
+}
+
+// CardDependencies contains the dependencies of a card.
+// swagger:model
+type CardDependencies struct {
+	// The id of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The dependencies on the cards that block this card
+	// required: true
+	BlockedBy []*CardDependency `json:"blockedBy"`
+
+	// The dependencies on the cards blocked by this card
+	// required: true
+	Blocking []*CardDependency `json:"blocking"`
+
+	// True if any of the cards blocking this one is not completed
+	// required: true
+	Blocked bool `json:"blocked"`
+}
+
+// CardBlockedStateChange is sent when the blocked state of a card changes
+// because one of its blockers was completed or reopened.
+// swagger:model
+type CardBlockedStateChange struct {
+	// The id of the board of the card
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The id of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The id of the blocker card that changed
+	// required: true
+	BlockerID string `json:"blockerId"`
+
+	// True if the card is still blocked by other cards
+	// required: true
+	Blocked bool `json:"blocked"`
+}
+
+// GetCompletedOptionIDs returns the ids of the select options that mark
+// the cards of the board as completed.
+func (b *Board) GetCompletedOptionIDs() []string {
+	ids := []string{}
+	switch value := b.Properties[BoardPropertyCompletedOptionIDs].(type) {
+	case []string:
+		ids = append(ids, value...)
+	case []interface{}:
+		for _, idIface := range value {
+			if id, ok := idIface.(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// IsCardCompleted returns true if any select property of the card has
+// one of the completed options of its board.
+func IsCardCompleted(board *Board, card *Block) bool {
+	completedIDs := board.GetCompletedOptionIDs()
+	if len(completedIDs) == 0 {
+		return false
+	}
+
+	props, ok := card.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, value := range props {
+		optionID, ok := value.(string)
+		if !ok {
+			continue
+		}
+		for _, id := range completedIDs {
+			if optionID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CardFilterPropertyTitle is the property id used by the filters to
+// match the title of the cards.
+const CardFilterPropertyTitle = "title"
+
+var ErrInvalidCardFilter = errors.New("invalid card filter")
+
+type CardFilterOperation string
+
+const (
+	CardFilterOperationAnd CardFilterOperation = "and"
+	CardFilterOperationOr  CardFilterOperation = "or"
+)
+
+type CardFilterCondition string
+
+const (
+	CardFilterConditionIncludes      CardFilterCondition = "includes"
+	CardFilterConditionNotIncludes   CardFilterCondition = "notIncludes"
+	CardFilterConditionIsEmpty       CardFilterCondition = "isEmpty"
+	CardFilterConditionIsNotEmpty    CardFilterCondition = "isNotEmpty"
+	CardFilterConditionIsSet         CardFilterCondition = "isSet"
+	CardFilterConditionIsNotSet      CardFilterCondition = "isNotSet"
+	CardFilterConditionIs            CardFilterCondition = "is"
+	CardFilterConditionContains      CardFilterCondition = "contains"
+	CardFilterConditionNotContains   CardFilterCondition = "notContains"
+	CardFilterConditionStartsWith    CardFilterCondition = "startsWith"
+	CardFilterConditionNotStartsWith CardFilterCondition = "notStartsWith"
+	CardFilterConditionEndsWith      CardFilterCondition = "endsWith"
+	CardFilterConditionNotEndsWith   CardFilterCondition = "notEndsWith"
+	CardFilterConditionIsBefore      CardFilterCondition = "isBefore"
+	CardFilterConditionIsAfter       CardFilterCondition = "isAfter"
+)
+
+// CardFilter selects cards by their title and property values, with the
+// same format as the filters of the board views. A filter is either a
+// group, with an operation and a list of filters, or a clause, with a
+// property, a condition and the values to compare with
+// swagger:model
+type CardFilter struct {
+	// The operation that combines the filters of a group, "and" or "or"
+	// required: false
+	Operation CardFilterOperation `json:"operation,omitempty"`
+
+	// The filters of a group
+	// required: false
+	Filters []*CardFilter `json:"filters,omitempty"`
+
+	// The property compared by a clause, or "title" for the card title
+	// required: false
+	PropertyID string `json:"propertyId,omitempty"`
+
+	// The condition of a clause
+	// required: false
+	Condition CardFilterCondition `json:"condition,omitempty"`
+
+	// The values compared by a clause: option ids, text or dates
+	// required: false
+	Values []string `json:"values,omitempty"`
+}
+
+// IsGroup returns true if the filter is a group of filters.
+func (f *CardFilter) IsGroup() bool {
+	return f.Condition == ""
+}
+
+// IsValid checks the operations and conditions of the filter and of its
+// nested filters.
+func (f *CardFilter) IsValid() error {
+	if f.IsGroup() {
+		switch f.Operation {
+		case "", CardFilterOperationAnd, CardFilterOperationOr:
+		default:
+			return fmt.Errorf("%w: unknown operation %q", ErrInvalidCardFilter, f.Operation)
+		}
+		for _, filter := range f.Filters {
+			if filter == nil {
+				return fmt.Errorf("%w: empty filter", ErrInvalidCardFilter)
+			}
+			if err := filter.IsValid(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if f.PropertyID == "" {
+		return fmt.Errorf("%w: missing property", ErrInvalidCardFilter)
+	}
+
+	switch f.Condition {
+	case CardFilterConditionIncludes, CardFilterConditionNotIncludes,
+		CardFilterConditionIsEmpty, CardFilterConditionIsNotEmpty,
+		CardFilterConditionIsSet, CardFilterConditionIsNotSet:
+	case CardFilterConditionIs, CardFilterConditionContains, CardFilterConditionNotContains,
+		CardFilterConditionStartsWith, CardFilterConditionNotStartsWith,
+		CardFilterConditionEndsWith, CardFilterConditionNotEndsWith:
+		if len(f.Values) == 0 {
+			return fmt.Errorf("%w: condition %q needs a value", ErrInvalidCardFilter, f.Condition)
+		}
+	case CardFilterConditionIsBefore, CardFilterConditionIsAfter:
+		if len(f.Values) == 0 {
+			return fmt.Errorf("%w: condition %q needs a value", ErrInvalidCardFilter, f.Condition)
+		}
+		if _, ok := filterDate(f.Values[0]); !ok {
+			return fmt.Errorf("%w: invalid date %q", ErrInvalidCardFilter, f.Values[0])
+		}
+	default:
+		return fmt.Errorf("%w: unknown condition %q", ErrInvalidCardFilter, f.Condition)
+	}
+	return nil
+}
+
+// Matches returns true if a card block satisfies the filter. A nil filter
+// or an empty group matches every card.
+func (f *CardFilter) Matches(card *Block) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.IsGroup() {
+		if len(f.Filters) == 0 {
+			return true
+		}
+		for _, filter := range f.Filters {
+			matches := filter.Matches(card)
+			if f.Operation == CardFilterOperationOr && matches {
+				return true
+			}
+			if f.Operation != CardFilterOperationOr && !matches {
+				return false
+			}
+		}
+		return f.Operation != CardFilterOperationOr
+	}
+
+	values := cardFilterValues(card, f.PropertyID)
+
+	switch f.Condition {
+	case CardFilterConditionIncludes:
+		return len(f.Values) == 0 || containsAny(values, f.Values)
+	case CardFilterConditionNotIncludes:
+		return len(f.Values) == 0 || !containsAny(values, f.Values)
+	case CardFilterConditionIsEmpty, CardFilterConditionIsNotSet:
+		return len(values) == 0
+	case CardFilterConditionIsNotEmpty, CardFilterConditionIsSet:
+		return len(values) != 0
+	case CardFilterConditionIsBefore, CardFilterConditionIsAfter:
+		if len(values) == 0 {
+			return false
+		}
+		date, ok := filterDate(values[0])
+		limit, limitOK := filterDate(f.Values[0])
+		if !ok || !limitOK {
+			return false
+		}
+		if f.Condition == CardFilterConditionIsBefore {
+			return date < limit
+		}
+		return date > limit
+	}
+
+	// the remaining conditions compare text, ignoring the case
+	text := strings.ToLower(strings.Join(values, ", "))
+	value := strings.ToLower(f.Values[0])
+	switch f.Condition {
+	case CardFilterConditionIs:
+		return text == value
+	case CardFilterConditionContains:
+		return strings.Contains(text, value)
+	case CardFilterConditionNotContains:
+		return !strings.Contains(text, value)
+	case CardFilterConditionStartsWith:
+		return strings.HasPrefix(text, value)
+	case CardFilterConditionNotStartsWith:
+		return !strings.HasPrefix(text, value)
+	case CardFilterConditionEndsWith:
+		return strings.HasSuffix(text, value)
+	case CardFilterConditionNotEndsWith:
+		return !strings.HasSuffix(text, value)
+	}
+	return false
+}
+
+// FilterCards returns the card blocks that match the filter.
+func FilterCards(cards []*Block, filter *CardFilter) []*Block {
+	matched := []*Block{}
+	for _, card := range cards {
+		if card.Type == TypeCard && filter.Matches(card) {
+			matched = append(matched, card)
+		}
+	}
+	return matched
+}
+
+// cardFilterValues returns the non empty values of a card property as
+// strings. Multi-select and person lists return one value per item.
+func cardFilterValues(card *Block, propertyID string) []string {
+	if propertyID == CardFilterPropertyTitle {
+		if card.Title == "" {
+			return nil
+		}
+		return []string{card.Title}
+	}
+
+	properties, ok := card.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var values []string
+	switch v := properties[propertyID].(type) {
+	case string:
+		if v != "" {
+			values = append(values, v)
+		}
+	case []interface{}:
+		for _, item := range v {
+			if s := fmt.Sprint(item); item != nil && s != "" {
+				values = append(values, s)
+			}
+		}
+	case []string:
+		for _, s := range v {
+			if s != "" {
+				values = append(values, s)
+			}
+		}
+	case nil:
+	default:
+		values = append(values, fmt.Sprint(v))
+	}
+	return values
+}
+
+func containsAny(values, candidates []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if value == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filterDate parses a date given either as milliseconds since the epoch
+// or as the JSON value of a date property, returning its start.
+func filterDate(value string) (int64, bool) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return millis, true
+	}
+
+	var date struct {
+		From *int64 `json:"from"`
+	}
+	if err := json.Unmarshal([]byte(value), &date); err != nil || date.From == nil {
+		return 0, false
+	}
+	return *date.From, true
+}
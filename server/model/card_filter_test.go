@@ -0,0 +1,135 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardFilterMatches(t *testing.T) {
+	card := &Block{
+		ID:    "card1",
+		Type:  TypeCard,
+		Title: "Fix the Login page",
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{
+				"status": "done",
+				"tags":   []interface{}{"bug", "ui"},
+				"due":    `{"from":1700000000000}`,
+			},
+		},
+	}
+
+	clause := func(propertyID string, condition CardFilterCondition, values ...string) *CardFilter {
+		return &CardFilter{PropertyID: propertyID, Condition: condition, Values: values}
+	}
+
+	testCases := []struct {
+		name     string
+		filter   *CardFilter
+		expected bool
+	}{
+		{"nil filter", nil, true},
+		{"empty group", &CardFilter{Operation: CardFilterOperationAnd}, true},
+		{"includes option", clause("status", CardFilterConditionIncludes, "todo", "done"), true},
+		{"includes missing option", clause("status", CardFilterConditionIncludes, "todo"), false},
+		{"includes multi-select option", clause("tags", CardFilterConditionIncludes, "ui"), true},
+		{"not includes", clause("tags", CardFilterConditionNotIncludes, "bug"), false},
+		{"is empty", clause("priority", CardFilterConditionIsEmpty), true},
+		{"is not empty", clause("status", CardFilterConditionIsNotEmpty), true},
+		{"is set", clause("priority", CardFilterConditionIsSet), false},
+		{"title is", clause(CardFilterPropertyTitle, CardFilterConditionIs, "fix the login page"), true},
+		{"title contains", clause(CardFilterPropertyTitle, CardFilterConditionContains, "LOGIN"), true},
+		{"title not contains", clause(CardFilterPropertyTitle, CardFilterConditionNotContains, "login"), false},
+		{"title starts with", clause(CardFilterPropertyTitle, CardFilterConditionStartsWith, "fix"), true},
+		{"title ends with", clause(CardFilterPropertyTitle, CardFilterConditionEndsWith, "fix"), false},
+		{"is before", clause("due", CardFilterConditionIsBefore, "1800000000000"), true},
+		{"is after", clause("due", CardFilterConditionIsAfter, `{"from":1800000000000}`), false},
+		{"missing date", clause("start", CardFilterConditionIsAfter, "0"), false},
+		{
+			"and group",
+			&CardFilter{
+				Operation: CardFilterOperationAnd,
+				Filters: []*CardFilter{
+					clause("status", CardFilterConditionIncludes, "done"),
+					clause("tags", CardFilterConditionIncludes, "feature"),
+				},
+			},
+			false,
+		},
+		{
+			"or group",
+			&CardFilter{
+				Operation: CardFilterOperationOr,
+				Filters: []*CardFilter{
+					clause("status", CardFilterConditionIncludes, "todo"),
+					clause("tags", CardFilterConditionIncludes, "bug"),
+				},
+			},
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.filter.Matches(card))
+		})
+	}
+}
+
+func TestCardFilterIsValid(t *testing.T) {
+	valid := &CardFilter{
+		Operation: CardFilterOperationOr,
+		Filters: []*CardFilter{
+			{PropertyID: "status", Condition: CardFilterConditionIncludes, Values: []string{"done"}},
+			{PropertyID: "due", Condition: CardFilterConditionIsBefore, Values: []string{"1700000000000"}},
+		},
+	}
+	require.NoError(t, valid.IsValid())
+
+	invalid := []*CardFilter{
+		{Operation: "xor"},
+		{Filters: []*CardFilter{nil}},
+		{Condition: CardFilterConditionIsEmpty},
+		{PropertyID: "status", Condition: "matches"},
+		{PropertyID: "title", Condition: CardFilterConditionContains},
+		{PropertyID: "due", Condition: CardFilterConditionIsAfter, Values: []string{"tomorrow"}},
+	}
+	for _, filter := range invalid {
+		require.ErrorIs(t, filter.IsValid(), ErrInvalidCardFilter)
+	}
+}
+
+func TestBulkOperationRequestIsValid(t *testing.T) {
+	valid := []*BulkOperationRequest{
+		{Type: BulkOperationSetProperty, PropertyID: "status", Value: "done"},
+		{Type: BulkOperationSetProperty, PropertyID: "tags", Value: []interface{}{"bug"}},
+		{Type: BulkOperationClearProperty, PropertyID: "status"},
+		{Type: BulkOperationMoveToBoard, TargetBoardID: "board2"},
+		{Type: BulkOperationArchive},
+		{Type: BulkOperationDelete},
+		{Type: BulkOperationAddSubscriber, SubscriberID: "user1"},
+	}
+	for _, req := range valid {
+		require.NoError(t, req.IsValid(), req.Type)
+	}
+
+	invalid := []*BulkOperationRequest{
+		{Type: "rename"},
+		{Type: BulkOperationSetProperty, Value: "done"},
+		{Type: BulkOperationSetProperty, PropertyID: "status", Value: 3.0},
+		{Type: BulkOperationSetProperty, PropertyID: "tags", Value: []interface{}{"bug", 2.0}},
+		{Type: BulkOperationClearProperty},
+		{Type: BulkOperationMoveToBoard},
+		{Type: BulkOperationAddSubscriber},
+	}
+	for _, req := range invalid {
+		require.ErrorIs(t, req.IsValid(), ErrInvalidBulkOperation, req.Type)
+	}
+
+	req := &BulkOperationRequest{Type: BulkOperationArchive, Filter: &CardFilter{Operation: "xor"}}
+	require.ErrorIs(t, req.IsValid(), ErrInvalidCardFilter)
+}
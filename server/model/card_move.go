@@ -0,0 +1,342 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const (
+	// CardFieldMovedToCardID and CardFieldMovedToBoardID link a card left
+	// as a redirect to its copy on another board.
+	CardFieldMovedToCardID  = "movedToCardId"
+	CardFieldMovedToBoardID = "movedToBoardId"
+
+	// CardFieldMovedFromCardID and CardFieldMovedFromBoardID link a moved
+	// card to the card it was copied from.
+	CardFieldMovedFromCardID  = "movedFromCardId"
+	CardFieldMovedFromBoardID = "movedFromBoardId"
+)
+
+var ErrInvalidCardMove = errors.New("invalid card move")
+
+type CardMoveSourceAction string
+
+const (
+	// CardMoveSourceDelete deletes the source card after the move.
+	CardMoveSourceDelete CardMoveSourceAction = "delete"
+
+	// CardMoveSourceRedirect archives the source card and links it to
+	// the moved card.
+	CardMoveSourceRedirect CardMoveSourceAction = "redirect"
+)
+
+// CardMoveRequest moves a card to another board
+// swagger:model
+type CardMoveRequest struct {
+	// The board to move the card to
+	// required: true
+	TargetBoardID string `json:"targetBoardId"`
+
+	// Adds the select options of the card missing on the target board
+	// instead of dropping its values
+	// required: false
+	CreateMissingOptions bool `json:"createMissingOptions"`
+
+	// What happens to the source card: delete (default) or redirect
+	// required: false
+	SourceAction CardMoveSourceAction `json:"sourceAction,omitempty"`
+}
+
+// IsValid checks the target board and the source action of the request.
+func (r *CardMoveRequest) IsValid() error {
+	if r.TargetBoardID == "" {
+		return fmt.Errorf("%w: missing target board", ErrInvalidCardMove)
+	}
+	switch r.SourceAction {
+	case "", CardMoveSourceDelete, CardMoveSourceRedirect:
+	default:
+		return fmt.Errorf("%w: unknown source action %q", ErrInvalidCardMove, r.SourceAction)
+	}
+	return nil
+}
+
+// CardMoveOptionMapping maps a select option of a card to an option of
+// the target board
+// swagger:model
+type CardMoveOptionMapping struct {
+	// The id of the option on the source board
+	// required: true
+	SourceOptionID string `json:"sourceOptionId"`
+
+	// The value of the option
+	// required: true
+	Value string `json:"value"`
+
+	// The color of the option
+	// required: false
+	Color string `json:"color,omitempty"`
+
+	// The id of the option on the target board, empty if the value is
+	// dropped
+	// required: false
+	TargetOptionID string `json:"targetOptionId,omitempty"`
+
+	// Indicates that the option is created on the target board
+	// required: false
+	Created bool `json:"created,omitempty"`
+}
+
+// CardMovePropertyMapping maps a property of a card to a property of the
+// target board
+// swagger:model
+type CardMovePropertyMapping struct {
+	// The id of the property on the source board
+	// required: true
+	SourcePropertyID string `json:"sourcePropertyId"`
+
+	// The name of the property
+	// required: true
+	Name string `json:"name"`
+
+	// The type of the property
+	// required: true
+	Type string `json:"type"`
+
+	// The id of the property on the target board, empty if the value is
+	// dropped
+	// required: false
+	TargetPropertyID string `json:"targetPropertyId,omitempty"`
+
+	// The mapping of the select options of the card value
+	// required: false
+	Options []CardMoveOptionMapping `json:"options,omitempty"`
+}
+
+// CardMoveMapping maps the property values of a card to the properties of
+// another board. Properties are matched by name and type, and options by
+// value
+// swagger:model
+type CardMoveMapping struct {
+	// The id of the board of the card
+	// required: true
+	SourceBoardID string `json:"sourceBoardId"`
+
+	// The id of the target board
+	// required: true
+	TargetBoardID string `json:"targetBoardId"`
+
+	// The mapping of each property the card has a value for
+	// required: true
+	Properties []CardMovePropertyMapping `json:"properties"`
+}
+
+// CardMoveResult is the card copied to the target board and the mapping
+// applied to its properties
+// swagger:model
+type CardMoveResult struct {
+	// The moved card
+	// required: true
+	Card *Card `json:"card"`
+
+	// The mapping of the card properties
+	// required: true
+	Mapping *CardMoveMapping `json:"mapping"`
+}
+
+// CardMoveBlocks are the writes of a card move, applied by the store in a
+// single transaction.
+type CardMoveBlocks struct {
+	// The board the card is moved to
+	TargetBoardID string
+
+	// Adds the options created by the mapping to the target board, if any
+	TargetBoardPatch *BoardPatch
+
+	// The copies of the card and its content on the target board
+	Blocks []*Block
+
+	// The card that is moved
+	SourceCardID string
+
+	// Applied to the source card before it is archived or deleted, if any
+	SourcePatch *BlockPatch
+
+	// Archives the source card instead of deleting it
+	ArchiveSource bool
+}
+
+// NewCardMoveMapping maps the property values of a card from its board to
+// the target board. Missing options get a new id on the target board if
+// createMissingOptions is set, and are dropped otherwise.
+func NewCardMoveMapping(card *Block, source, target *Board, createMissingOptions bool) (*CardMoveMapping, error) {
+	sourceSchema, err := ParsePropertySchema(source)
+	if err != nil {
+		return nil, err
+	}
+	targetSchema, err := ParsePropertySchema(target)
+	if err != nil {
+		return nil, err
+	}
+
+	targetByName := map[string]PropDef{}
+	for _, propDef := range targetSchema {
+		key := mappingKey(propDef.Name)
+		// the first property of a name wins, like in the board views
+		if existing, ok := targetByName[key]; !ok || propDef.Index < existing.Index {
+			targetByName[key] = propDef
+		}
+	}
+
+	props, _ := card.Fields["properties"].(map[string]interface{})
+	sourceDefs := make([]PropDef, 0, len(props))
+	for propID := range props {
+		if propDef, ok := sourceSchema[propID]; ok && !propDef.IsComputed() {
+			sourceDefs = append(sourceDefs, propDef)
+		}
+	}
+	sort.Slice(sourceDefs, func(i, j int) bool { return sourceDefs[i].Index < sourceDefs[j].Index })
+
+	mapping := &CardMoveMapping{
+		SourceBoardID: source.ID,
+		TargetBoardID: target.ID,
+		Properties:    make([]CardMovePropertyMapping, 0, len(sourceDefs)),
+	}
+	for _, sourceDef := range sourceDefs {
+		propMapping := CardMovePropertyMapping{
+			SourcePropertyID: sourceDef.ID,
+			Name:             sourceDef.Name,
+			Type:             sourceDef.Type,
+		}
+
+		targetDef, ok := targetByName[mappingKey(sourceDef.Name)]
+		if ok && targetDef.Type == sourceDef.Type &&
+			(sourceDef.Type != PropTypeRelation || targetDef.RelationBoardID == sourceDef.RelationBoardID) {
+			propMapping.TargetPropertyID = targetDef.ID
+		}
+
+		if sourceDef.Type == "select" || sourceDef.Type == "multiSelect" {
+			seen := map[string]bool{}
+			created := map[string]string{}
+			for _, optionID := range optionIDs(props[sourceDef.ID]) {
+				option, ok := sourceDef.Options[optionID]
+				if !ok || seen[optionID] {
+					continue
+				}
+				seen[optionID] = true
+
+				optionMapping := CardMoveOptionMapping{
+					SourceOptionID: optionID,
+					Value:          option.Value,
+					Color:          option.Color,
+				}
+				if propMapping.TargetPropertyID != "" {
+					if targetOption, ok := targetDef.FindOption(option.Value); ok {
+						optionMapping.TargetOptionID = targetOption.ID
+					} else if createdID, ok := created[mappingKey(option.Value)]; ok {
+						optionMapping.TargetOptionID = createdID
+					} else if createMissingOptions {
+						optionMapping.TargetOptionID = utils.NewID(utils.IDTypeNone)
+						optionMapping.Created = true
+						created[mappingKey(option.Value)] = optionMapping.TargetOptionID
+					}
+				}
+				propMapping.Options = append(propMapping.Options, optionMapping)
+			}
+		}
+		mapping.Properties = append(mapping.Properties, propMapping)
+	}
+	return mapping, nil
+}
+
+// MapProperties returns the property values of a card on the target
+// board. The values of unmapped properties and options are dropped.
+func (m *CardMoveMapping) MapProperties(props map[string]interface{}) map[string]interface{} {
+	mapped := map[string]interface{}{}
+	for _, propMapping := range m.Properties {
+		if propMapping.TargetPropertyID == "" {
+			continue
+		}
+		value := props[propMapping.SourcePropertyID]
+
+		if propMapping.Type == "select" || propMapping.Type == "multiSelect" {
+			optionMap := map[string]string{}
+			for _, optionMapping := range propMapping.Options {
+				if optionMapping.TargetOptionID != "" {
+					optionMap[optionMapping.SourceOptionID] = optionMapping.TargetOptionID
+				}
+			}
+
+			if propMapping.Type == "select" {
+				if optionID, ok := optionMap[fmt.Sprint(value)]; ok {
+					mapped[propMapping.TargetPropertyID] = optionID
+				}
+				continue
+			}
+
+			values := []interface{}{}
+			for _, sourceID := range optionIDs(value) {
+				if optionID, ok := optionMap[sourceID]; ok {
+					values = append(values, optionID)
+				}
+			}
+			if len(values) != 0 {
+				mapped[propMapping.TargetPropertyID] = values
+			}
+			continue
+		}
+
+		mapped[propMapping.TargetPropertyID] = value
+	}
+	return mapped
+}
+
+// CreatedOptions returns the options to add to the target board, by
+// property id.
+func (m *CardMoveMapping) CreatedOptions() map[string][]PropDefOption {
+	created := map[string][]PropDefOption{}
+	for _, propMapping := range m.Properties {
+		for _, optionMapping := range propMapping.Options {
+			if optionMapping.Created {
+				created[propMapping.TargetPropertyID] = append(created[propMapping.TargetPropertyID], PropDefOption{
+					ID:    optionMapping.TargetOptionID,
+					Value: optionMapping.Value,
+					Color: optionMapping.Color,
+				})
+			}
+		}
+	}
+	return created
+}
+
+func mappingKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// optionIDs returns the option ids of a select or multi-select value.
+func optionIDs(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		ids := make([]string, 0, len(v))
+		for _, item := range v {
+			if id, ok := item.(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	return nil
+}
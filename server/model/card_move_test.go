@@ -0,0 +1,113 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCardMoveMapping(t *testing.T) {
+	source := &Board{
+		ID: "source",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do", "color": "propColorRed"},
+					map[string]interface{}{"id": "done", "value": "Done"},
+				},
+			},
+			{
+				"id":   "tags",
+				"name": "Tags",
+				"type": "multiSelect",
+				"options": []interface{}{
+					map[string]interface{}{"id": "bug", "value": "Bug"},
+					map[string]interface{}{"id": "ui", "value": "UI"},
+				},
+			},
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "owner", "name": "Owner", "type": "person"},
+		},
+	}
+	target := &Board{
+		ID: "target",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "state",
+				"name": " status ",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "closed", "value": "done"},
+				},
+			},
+			{
+				"id":   "labels",
+				"name": "Tags",
+				"type": "multiSelect",
+				"options": []interface{}{
+					map[string]interface{}{"id": "defect", "value": "BUG"},
+				},
+			},
+			{"id": "points", "name": "Estimate", "type": "number"},
+			{"id": "owner", "name": "Owner", "type": "text"},
+		},
+	}
+	card := &Block{
+		ID:      "card",
+		BoardID: "source",
+		Type:    TypeCard,
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{
+				"status":   "todo",
+				"tags":     []interface{}{"bug", "ui"},
+				"estimate": "3",
+				"owner":    "user1",
+				"deleted":  "value",
+			},
+		},
+	}
+
+	t.Run("drops missing options", func(t *testing.T) {
+		mapping, err := NewCardMoveMapping(card, source, target, false)
+		require.NoError(t, err)
+		require.Len(t, mapping.Properties, 4)
+		require.Empty(t, mapping.CreatedOptions())
+
+		mapped := mapping.MapProperties(card.Fields["properties"].(map[string]interface{}))
+		require.Equal(t, map[string]interface{}{
+			"labels": []interface{}{"defect"},
+			"points": "3",
+		}, mapped)
+	})
+
+	t.Run("creates missing options", func(t *testing.T) {
+		mapping, err := NewCardMoveMapping(card, source, target, true)
+		require.NoError(t, err)
+
+		created := mapping.CreatedOptions()
+		require.Len(t, created, 2)
+		require.Len(t, created["state"], 1)
+		require.Equal(t, "To do", created["state"][0].Value)
+		require.Equal(t, "propColorRed", created["state"][0].Color)
+		require.Len(t, created["labels"], 1)
+		require.Equal(t, "UI", created["labels"][0].Value)
+
+		mapped := mapping.MapProperties(card.Fields["properties"].(map[string]interface{}))
+		require.Equal(t, created["state"][0].ID, mapped["state"])
+		require.Equal(t, []interface{}{"defect", created["labels"][0].ID}, mapped["labels"])
+		require.NotContains(t, mapped, "owner")
+	})
+}
+
+func TestCardMoveRequestIsValid(t *testing.T) {
+	require.NoError(t, (&CardMoveRequest{TargetBoardID: "board"}).IsValid())
+	require.NoError(t, (&CardMoveRequest{TargetBoardID: "board", SourceAction: CardMoveSourceRedirect}).IsValid())
+	require.ErrorIs(t, (&CardMoveRequest{}).IsValid(), ErrInvalidCardMove)
+	require.ErrorIs(t, (&CardMoveRequest{TargetBoardID: "board", SourceAction: "keep"}).IsValid(), ErrInvalidCardMove)
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CardFieldTemplateDefaults is the field of a template card holding the
+	// default property values of the cards created from it.
+	CardFieldTemplateDefaults = "templateDefaults"
+
+	TemplateVariableDate     = "date"
+	TemplateVariableTime     = "time"
+	TemplateVariableDateTime = "datetime"
+	TemplateVariableUser     = "user"
+	TemplateVariableBoard    = "board"
+)
+
+var (
+	ErrInvalidRelativeTime = errors.New("invalid relative time")
+
+	templateVariablePattern = regexp.MustCompile(`{{\s*([A-Za-z0-9_.-]+)\s*}}`)
+	relativeTimePattern     = regexp.MustCompile(`^([+-])\s*(\d+)\s*([a-z]+)$`)
+)
+
+// CardFromTemplateRequest is the request body to create a card from a
+// template card.
+// swagger:model
+type CardFromTemplateRequest struct {
+	// The values of the custom variables of the template, by name
+	// required: false
+	Variables map[string]string `json:"variables"`
+}
+
+// CardTemplateVariables lists the variables of a template card.
+// swagger:model
+type CardTemplateVariables struct {
+	// The names of the custom variables that must be provided to create a card
+	// required: true
+	Variables []string `json:"variables"`
+
+	// The names of the variables provided by the server
+	// required: true
+	BuiltIn []string `json:"builtIn"`
+}
+
+// BuiltInTemplateVariables returns the names of the variables that are
+// provided by the server when a card is created from a template.
+func BuiltInTemplateVariables() []string {
+	return []string{
+		TemplateVariableDate,
+		TemplateVariableTime,
+		TemplateVariableDateTime,
+		TemplateVariableUser,
+		TemplateVariableBoard,
+	}
+}
+
+// IsTemplateTextBlock tells if the variables of a template block are
+// replaced when a card is created from it.
+func IsTemplateTextBlock(block *Block) bool {
+	switch block.Type {
+	case TypeCard, TypeText, TypeCheckbox:
+		return true
+	}
+	return false
+}
+
+// FindTemplateVariables returns the sorted names of the custom variables
+// used in the titles of the blocks and in the default values of a
+// template card.
+func FindTemplateVariables(blocks []*Block) []string {
+	builtIn := map[string]bool{}
+	for _, name := range BuiltInTemplateVariables() {
+		builtIn[name] = true
+	}
+
+	found := map[string]bool{}
+	addVariables := func(s string) {
+		for _, match := range templateVariablePattern.FindAllStringSubmatch(s, -1) {
+			if !builtIn[match[1]] {
+				found[match[1]] = true
+			}
+		}
+	}
+
+	for _, block := range blocks {
+		if !IsTemplateTextBlock(block) {
+			continue
+		}
+		addVariables(block.Title)
+		for _, value := range TemplateDefaults(block) {
+			addVariables(value)
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReplaceTemplateVariables replaces the variables of a string with their
+// values. The variables without a value are left unchanged.
+func ReplaceTemplateVariables(s string, values map[string]string) string {
+	return templateVariablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := templateVariablePattern.FindStringSubmatch(match)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// TemplateDefaults returns the default property values of a template
+// card, by property id.
+func TemplateDefaults(block *Block) map[string]string {
+	defaults := map[string]string{}
+	switch fieldDefaults := block.Fields[CardFieldTemplateDefaults].(type) {
+	case map[string]string:
+		for propertyID, value := range fieldDefaults {
+			defaults[propertyID] = value
+		}
+	case map[string]interface{}:
+		for propertyID, value := range fieldDefaults {
+			if s, ok := value.(string); ok {
+				defaults[propertyID] = s
+			}
+		}
+	}
+	return defaults
+}
+
+// ParseRelativeTime returns the time an expression relative to now
+// points to. The expressions are "now", "today", which is the start of
+// the day, or an offset such as "+3d" or "-1 week", with the units
+// hours, days, weeks and months.
+func ParseRelativeTime(expr string, now time.Time) (time.Time, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	switch expr {
+	case "now":
+		return now, nil
+	case "today":
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), nil
+	}
+
+	match := relativeTimePattern.FindStringSubmatch(expr)
+	if match == nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidRelativeTime, expr)
+	}
+	amount, err := strconv.Atoi(match[2])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidRelativeTime, expr)
+	}
+	if match[1] == "-" {
+		amount = -amount
+	}
+
+	switch match[3] {
+	case "h", "hour", "hours":
+		return now.Add(time.Duration(amount) * time.Hour), nil
+	case "d", "day", "days":
+		return now.AddDate(0, 0, amount), nil
+	case "w", "week", "weeks":
+		return now.AddDate(0, 0, 7*amount), nil
+	case "mo", "month", "months":
+		return now.AddDate(0, amount, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: unknown unit in %q", ErrInvalidRelativeTime, expr)
+}
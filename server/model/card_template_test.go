@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelativeTime(t *testing.T) {
+	now := time.Date(2024, 1, 31, 15, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"now", now},
+		{"today", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"+3d", time.Date(2024, 2, 3, 15, 30, 0, 0, time.UTC)},
+		{"+3 days", time.Date(2024, 2, 3, 15, 30, 0, 0, time.UTC)},
+		{"-1w", time.Date(2024, 1, 24, 15, 30, 0, 0, time.UTC)},
+		{"+2h", time.Date(2024, 1, 31, 17, 30, 0, 0, time.UTC)},
+		{" +1 Month ", time.Date(2024, 3, 2, 15, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			result, err := ParseRelativeTime(tc.expr, now)
+			require.NoError(t, err)
+			require.True(t, tc.expected.Equal(result), "expected %s, got %s", tc.expected, result)
+		})
+	}
+
+	for _, expr := range []string{"", "tomorrow", "3d", "+d", "+3 years"} {
+		t.Run("invalid "+expr, func(t *testing.T) {
+			_, err := ParseRelativeTime(expr, now)
+			require.ErrorIs(t, err, ErrInvalidRelativeTime)
+		})
+	}
+}
+
+func TestTemplateVariables(t *testing.T) {
+	card := &Block{
+		ID:    "card",
+		Type:  TypeCard,
+		Title: "Standup {{date}} for {{ team }}",
+		Fields: map[string]interface{}{
+			CardFieldTemplateDefaults: map[string]interface{}{"owner": "{{lead}}", "due": "+3d"},
+		},
+	}
+	text := &Block{ID: "text", Type: TypeText, Title: "Notes by {{user}} about {{topic}}"}
+	image := &Block{ID: "image", Type: TypeImage, Title: "{{ignored}}"}
+
+	t.Run("find the custom variables", func(t *testing.T) {
+		require.Equal(t, []string{"lead", "team", "topic"}, FindTemplateVariables([]*Block{card, text, image}))
+	})
+
+	t.Run("replace the variables", func(t *testing.T) {
+		values := map[string]string{"date": "2024-01-31", "team": "core"}
+		require.Equal(t, "Standup 2024-01-31 for core", ReplaceTemplateVariables(card.Title, values))
+		require.Equal(t, "Notes by {{user}} about {{topic}}", ReplaceTemplateVariables(text.Title, values))
+	})
+
+	t.Run("read the template defaults", func(t *testing.T) {
+		require.Equal(t, map[string]string{"owner": "{{lead}}", "due": "+3d"}, TemplateDefaults(card))
+		require.Empty(t, TemplateDefaults(text))
+	})
+}
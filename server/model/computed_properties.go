@@ -0,0 +1,238 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const (
+	// PropTypeFormula is the type of the read only properties computed
+	// from a formula over the other properties of the card.
+	PropTypeFormula = "formula"
+
+	// PropTypeRollup is the type of the read only properties computed
+	// from the content of the card.
+	PropTypeRollup = "rollup"
+
+	// RollupCheckboxCompletion is the percentage of checked checkboxes.
+	RollupCheckboxCompletion = "checkboxCompletion"
+
+	// RollupCommentCount is the number of comments.
+	RollupCommentCount = "commentCount"
+)
+
+// IsComputed returns true if the property value is computed by the
+// server and cannot be set by clients.
+func (pd PropDef) IsComputed() bool {
+	return pd.Type == PropTypeFormula || pd.Type == PropTypeRollup
+}
+
+// HasComputedProperties returns true if any property of the schema is
+// computed.
+func (s PropSchema) HasComputedProperties() bool {
+	for _, propDef := range s {
+		if propDef.IsComputed() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRollupProperties returns true if any property of the schema is
+// computed from the content of the cards.
+func (s PropSchema) HasRollupProperties() bool {
+	for _, propDef := range s {
+		if propDef.Type == PropTypeRollup {
+			return true
+		}
+	}
+	return false
+}
+
+// ComputeCardProperties computes the values of the formula and rollup
+// properties of a card, formatted as they are stored in the card
+// properties. The children are the content blocks of the card, used by
+// the rollups. Properties that cannot be computed are returned in the
+// errors map and have no value.
+func ComputeCardProperties(schema PropSchema, card *Block, children []*Block) (map[string]string, map[string]error) {
+	c := &cardComputation{
+		schema:   schema,
+		card:     card,
+		children: children,
+		props:    map[string]interface{}{},
+		values:   map[string]interface{}{},
+		visiting: map[string]bool{},
+	}
+	if cardProps, ok := card.Fields["properties"].(map[string]interface{}); ok {
+		c.props = cardProps
+	}
+
+	values := map[string]string{}
+	errs := map[string]error{}
+	for id, propDef := range schema {
+		if !propDef.IsComputed() {
+			continue
+		}
+		value, err := c.computedValue(propDef)
+		if err != nil {
+			errs[id] = err
+			continue
+		}
+		if value != nil {
+			values[id] = FormatFormulaValue(value)
+		}
+	}
+	return values, errs
+}
+
+type cardComputation struct {
+	schema   PropSchema
+	card     *Block
+	children []*Block
+	props    map[string]interface{}
+	values   map[string]interface{}
+	visiting map[string]bool
+}
+
+func (c *cardComputation) computedValue(propDef PropDef) (interface{}, error) {
+	if value, ok := c.values[propDef.ID]; ok {
+		return value, nil
+	}
+	if c.visiting[propDef.ID] {
+		return nil, fmt.Errorf("property %s: %w", propDef.Name, ErrFormulaCycle)
+	}
+	c.visiting[propDef.ID] = true
+	defer delete(c.visiting, propDef.ID)
+
+	var value interface{}
+	var err error
+	switch propDef.Type {
+	case PropTypeFormula:
+		if strings.TrimSpace(propDef.Formula) == "" {
+			break
+		}
+		value, err = evaluateFormula(propDef.Formula, c.lookup)
+	case PropTypeRollup:
+		value, err = c.rollup(propDef)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.values[propDef.ID] = value
+	return value, nil
+}
+
+func (c *cardComputation) rollup(propDef PropDef) (interface{}, error) {
+	switch propDef.Rollup {
+	case RollupCheckboxCompletion:
+		total, checked := 0, 0
+		for _, child := range c.children {
+			if child.Type != TypeCheckbox || child.DeleteAt != 0 {
+				continue
+			}
+			total++
+			if value, ok := child.Fields["value"].(bool); ok && value {
+				checked++
+			}
+		}
+		if total == 0 {
+			return nil, nil
+		}
+		return math.Round(float64(checked) * 100 / float64(total)), nil
+	case RollupCommentCount:
+		count := 0
+		for _, child := range c.children {
+			if child.Type == TypeComment && child.DeleteAt == 0 {
+				count++
+			}
+		}
+		return float64(count), nil
+	}
+	return nil, fmt.Errorf("unknown rollup %s: %w", propDef.Rollup, ErrInvalidPropSchema)
+}
+
+// lookup returns the value of a property referenced by a formula, by
+// name or by id.
+func (c *cardComputation) lookup(name string) (interface{}, error) {
+	propDef, ok := c.schema[name]
+	if !ok {
+		found := false
+		for _, pd := range c.schema {
+			if strings.EqualFold(pd.Name, name) {
+				propDef, found = pd, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown property %s: %w", name, ErrInvalidFormula)
+		}
+	}
+
+	if propDef.IsComputed() {
+		return c.computedValue(propDef)
+	}
+
+	switch propDef.Type {
+	case "createdTime":
+		return utils.GetTimeForMillis(c.card.CreateAt), nil
+	case "updatedTime":
+		return utils.GetTimeForMillis(c.card.UpdateAt), nil
+	}
+
+	v, ok := c.props[propDef.ID]
+	if !ok || v == nil {
+		return nil, nil
+	}
+
+	switch propDef.Type {
+	case "number":
+		n, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprintf("%v", v)), 64)
+		if err != nil {
+			return nil, nil
+		}
+		return n, nil
+	case "checkbox":
+		return fmt.Sprintf("%v", v) == "true", nil
+	case "date":
+		s, ok := v.(string)
+		if !ok {
+			return nil, nil
+		}
+		var m map[string]int64
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			return nil, nil
+		}
+		from, ok := m["from"]
+		if !ok {
+			return nil, nil
+		}
+		return utils.GetTimeForMillis(from), nil
+	case "select":
+		if opt, ok := propDef.Options[fmt.Sprintf("%v", v)]; ok {
+			return opt.Value, nil
+		}
+		return nil, nil
+	case "multiSelect":
+		ids, _ := v.([]interface{})
+		values := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if opt, ok := propDef.Options[fmt.Sprintf("%v", id)]; ok {
+				values = append(values, opt.Value)
+			}
+		}
+		return strings.Join(values, ", "), nil
+	}
+
+	if value, err := propDef.GetValue(v, nil); err == nil {
+		return value, nil
+	}
+	return nil, nil
+}
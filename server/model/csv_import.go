@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	// CSVImportColumnTitle maps a CSV column to the title of the cards.
+	CSVImportColumnTitle = "title"
+
+	// CSVImportMaxRows is the maximum number of rows of an imported file.
+	CSVImportMaxRows = 10000
+)
+
+var ErrInvalidCSVImport = errors.New("invalid CSV import")
+
+// CSVImportOptions configures the import of a CSV file into a board
+// swagger:model
+type CSVImportOptions struct {
+	// The property id of each CSV column, by column name. Use "title" for
+	// the card title and an empty id to ignore a column. Columns missing
+	// from the map are matched to the properties by name, and the column
+	// named title to the card title
+	// required: false
+	Columns map[string]string `json:"columns,omitempty"`
+
+	// The column identifying the cards to update. Cards with the same value
+	// for the mapped property are updated, and the other rows create cards
+	// required: false
+	KeyColumn string `json:"keyColumn,omitempty"`
+
+	// Adds the select options missing on the board instead of reporting
+	// the values as errors
+	// required: false
+	CreateMissingOptions bool `json:"createMissingOptions,omitempty"`
+
+	// Reports the rows with errors without writing anything
+	// required: false
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Imports the valid rows when other rows have errors. By default,
+	// nothing is written if any row has an error
+	// required: false
+	SkipInvalidRows bool `json:"skipInvalidRows,omitempty"`
+
+	// The field delimiter, a comma by default
+	// required: false
+	Delimiter string `json:"delimiter,omitempty"`
+}
+
+// IsValid checks the delimiter of the options.
+func (o *CSVImportOptions) IsValid() error {
+	if o.Delimiter == "" {
+		return nil
+	}
+	r, size := utf8.DecodeRuneInString(o.Delimiter)
+	if size != len(o.Delimiter) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return fmt.Errorf("%w: invalid delimiter %q", ErrInvalidCSVImport, o.Delimiter)
+	}
+	return nil
+}
+
+// CSVImportRowError describes a value of a CSV file that can't be
+// imported
+// swagger:model
+type CSVImportRowError struct {
+	// The line of the row in the file, starting at 1 for the header
+	// required: true
+	Row int `json:"row"`
+
+	// The column of the value, empty for errors of the whole row
+	// required: false
+	Column string `json:"column,omitempty"`
+
+	// The invalid value
+	// required: false
+	Value string `json:"value,omitempty"`
+
+	// Why the value can't be imported
+	// required: true
+	Reason string `json:"reason"`
+}
+
+// CSVImportResult reports the import of a CSV file into a board
+// swagger:model
+type CSVImportResult struct {
+	// Indicates that the import was a dry run and nothing was written
+	// required: true
+	DryRun bool `json:"dryRun"`
+
+	// The number of rows of the file, without the header
+	// required: true
+	Rows int `json:"rows"`
+
+	// The number of created cards
+	// required: true
+	Created int `json:"created"`
+
+	// The number of updated cards
+	// required: true
+	Updated int `json:"updated"`
+
+	// The number of rows not imported because of errors
+	// required: true
+	Skipped int `json:"skipped"`
+
+	// The property id of each imported column, by column name
+	// required: true
+	Columns map[string]string `json:"columns"`
+
+	// The columns not matching any property
+	// required: false
+	IgnoredColumns []string `json:"ignoredColumns,omitempty"`
+
+	// The values of the options added to the board, by property id
+	// required: false
+	CreatedOptions map[string][]string `json:"createdOptions,omitempty"`
+
+	// The errors of the rows
+	// required: true
+	Errors []CSVImportRowError `json:"errors"`
+}
+
+// CSVImportBlocks are the writes of a CSV import, applied by the store in
+// a single transaction.
+type CSVImportBlocks struct {
+	// The board the rows are imported into
+	BoardID string
+
+	// Adds the options created by the import to the board, if any
+	BoardPatch *BoardPatch
+
+	// The cards created by the import
+	Blocks []*Block
+
+	// The updates of the cards matched by the key column
+	BlockPatches *BlockPatchBatch
+}
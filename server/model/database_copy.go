@@ -0,0 +1,20 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// DatabaseCopyTable is the outcome of the copy of a table from a source
+// database to a target database.
+type DatabaseCopyTable struct {
+	// The name of the table, without prefix
+	Table string `json:"table"`
+
+	// The number of rows of the table in the source database
+	SourceRows int64 `json:"sourceRows"`
+
+	// The number of rows of the table in the target database once copied
+	TargetRows int64 `json:"targetRows"`
+
+	// True if the table had been copied by a previous run
+	Resumed bool `json:"resumed"`
+}
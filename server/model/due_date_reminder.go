@@ -0,0 +1,94 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// BoardPropertyReminderLeadTimes is the board property holding the lead
+// times, in minutes before the due date, of the due date reminders of its
+// cards. A lead time of zero sends the reminder when the card is due, and
+// an empty list disables the reminders of the board.
+const BoardPropertyReminderLeadTimes = "reminderLeadTimes"
+
+// DueDateReminder records a reminder sent for a card date, so it is not
+// sent twice.
+type DueDateReminder struct {
+	CardID     string `json:"cardId"`
+	PropertyID string `json:"propertyId"`
+	DueAt      int64  `json:"dueAt"`
+	LeadTime   int    `json:"leadTime"`
+	SentAt     int64  `json:"sentAt"`
+}
+
+// ErrInvalidDueDateReminder is returned when a due date reminder is not valid.
+type ErrInvalidDueDateReminder struct {
+	msg string
+}
+
+func (e ErrInvalidDueDateReminder) Error() string {
+	return fmt.Sprintf("invalid due date reminder: %s", e.msg)
+}
+
+func (r *DueDateReminder) IsValid() error {
+	if r == nil {
+		return ErrInvalidDueDateReminder{"cannot be nil"}
+	}
+	if r.CardID == "" {
+		return ErrInvalidDueDateReminder{"missing card id"}
+	}
+	if r.PropertyID == "" {
+		return ErrInvalidDueDateReminder{"missing property id"}
+	}
+	if r.LeadTime < 0 {
+		return ErrInvalidDueDateReminder{"negative lead time"}
+	}
+	return nil
+}
+
+// GetReminderLeadTimes returns the reminder lead times of the board, in
+// minutes, and false if the board doesn't define them.
+func (b *Board) GetReminderLeadTimes() ([]int, bool) {
+	value, ok := b.Properties[BoardPropertyReminderLeadTimes]
+	if !ok {
+		return nil, false
+	}
+
+	leadTimes := []int{}
+	switch value := value.(type) {
+	case []int:
+		leadTimes = append(leadTimes, value...)
+	case []interface{}:
+		for _, leadTimeIface := range value {
+			// numbers decoded from JSON are float64
+			if leadTime, ok := leadTimeIface.(float64); ok && leadTime >= 0 {
+				leadTimes = append(leadTimes, int(leadTime))
+			}
+		}
+	default:
+		return nil, false
+	}
+	return leadTimes, true
+}
+
+// GetDueDate returns the due date of a date property value, in
+// milliseconds. For date ranges it is the end of the range.
+func GetDueDate(value interface{}) (int64, bool) {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return 0, false
+	}
+
+	var m map[string]int64
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return 0, false
+	}
+	if to, ok := m["to"]; ok && to != 0 {
+		return to, true
+	}
+	from, ok := m["from"]
+	return from, ok && from != 0
+}
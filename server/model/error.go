@@ -160,6 +160,23 @@ func (ni *ErrNotImplemented) Error() string {
 	return ni.msg
 }
 
+// ErrConflict can be returned when an operation conflicts with the
+// current state of a resource.
+type ErrConflict struct {
+	reason string
+}
+
+// NewErrConflict creates a new ErrConflict instance.
+func NewErrConflict(reason string) *ErrConflict {
+	return &ErrConflict{
+		reason: reason,
+	}
+}
+
+func (c *ErrConflict) Error() string {
+	return c.reason
+}
+
 // IsErrBadRequest returns true if `err` is or wraps one of:
 // - model.ErrBadRequest
 // - model.ErrViewsLimitReached
@@ -168,7 +185,8 @@ func (ni *ErrNotImplemented) Error() string {
 // - model.ErrBoardMemberIsLastAdmin
 // - model.ErrBoardIDMismatch
 // - model.ErrBlockTitleSizeLimitExceeded
-// - model.ErrBlockFieldsSizeLimitExceeded.
+// - model.ErrBlockFieldsSizeLimitExceeded
+// - model.ErrInvalidPropertyValues.
 func IsErrBadRequest(err error) bool {
 	if err == nil {
 		return false
@@ -212,6 +230,12 @@ func IsErrBadRequest(err error) bool {
 		return true
 	}
 
+	// check if this is a model.ErrInvalidPropertyValues
+	var ipv *ErrInvalidPropertyValues
+	if errors.As(err, &ipv) {
+		return true
+	}
+
 	// check if this is a model.ErrBlockTitleSizeLimitExceeded
 	return errors.Is(err, ErrBlockFieldsSizeLimitExceeded)
 }
@@ -311,6 +335,18 @@ func IsErrRequestEntityTooLarge(err error) bool {
 	return errors.Is(err, ErrRequestEntityTooLarge)
 }
 
+// IsErrConflict returns true if `err` is or wraps one of:
+// - model.ErrConflict.
+func IsErrConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// check if this is a model.ErrConflict
+	var c *ErrConflict
+	return errors.As(err, &c)
+}
+
 // IsErrNotImplemented returns true if `err` is or wraps one of:
 // - model.ErrNotImplemented
 // - model.ErrInsufficientLicense.
@@ -10,4 +10,8 @@ type ErrorResponse struct {
 	// The error code
 	// required: false
 	ErrorCode int `json:"errorCode"`
+
+	// The invalid card property values, if any
+	// required: false
+	PropertyErrors []PropertyValueError `json:"propertyErrors,omitempty"`
 }
@@ -0,0 +1,576 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalidFormula = errors.New("invalid formula")
+var ErrFormulaCycle = errors.New("formula references itself")
+
+// formulaLookup resolves the value of a property referenced by a
+// formula with prop("name").
+type formulaLookup func(name string) (interface{}, error)
+
+type formulaTokenKind int
+
+const (
+	tokenEOF formulaTokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type formulaToken struct {
+	kind  formulaTokenKind
+	value string
+}
+
+func tokenizeFormula(formula string) ([]formulaToken, error) {
+	tokens := []formulaToken{}
+	runes := []rune(formula)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, formulaToken{tokenNumber, string(runes[start:i])})
+		case r == '"':
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string: %w", ErrInvalidFormula)
+			}
+			i++
+			tokens = append(tokens, formulaToken{tokenString, sb.String()})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, formulaToken{tokenIdent, string(runes[start:i])})
+		case r == '(':
+			tokens = append(tokens, formulaToken{tokenLParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, formulaToken{tokenRParen, ")"})
+			i++
+		case r == ',':
+			tokens = append(tokens, formulaToken{tokenComma, ","})
+			i++
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", ">=", "<=", "&&", "||":
+					op = two
+				}
+			}
+			if !strings.Contains("+-*/%<>!", op) && len(op) == 1 {
+				return nil, fmt.Errorf("unexpected character %q: %w", r, ErrInvalidFormula)
+			}
+			tokens = append(tokens, formulaToken{tokenOperator, op})
+			i += len(op)
+		}
+	}
+	return append(tokens, formulaToken{kind: tokenEOF}), nil
+}
+
+// formulaParser evaluates a formula while parsing it, using recursive
+// descent with the usual operator precedence.
+type formulaParser struct {
+	tokens []formulaToken
+	pos    int
+	lookup formulaLookup
+
+	// skip is set while parsing the branch of an if that is not taken,
+	// so it is validated without resolving properties or failing.
+	skip bool
+}
+
+// evaluateFormula evaluates a formula, resolving the properties it
+// references through lookup. The result is a float64, string, bool,
+// time.Time or nil.
+func evaluateFormula(formula string, lookup formulaLookup) (interface{}, error) {
+	tokens, err := tokenizeFormula(formula)
+	if err != nil {
+		return nil, err
+	}
+	p := &formulaParser{tokens: tokens, lookup: lookup}
+	value, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q: %w", p.peek().value, ErrInvalidFormula)
+	}
+	return value, nil
+}
+
+func (p *formulaParser) peek() formulaToken {
+	return p.tokens[p.pos]
+}
+
+func (p *formulaParser) next() formulaToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *formulaParser) acceptOperator(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.value == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *formulaParser) expect(kind formulaTokenKind, what string) error {
+	if p.next().kind != kind {
+		return fmt.Errorf("expected %s: %w", what, ErrInvalidFormula)
+	}
+	return nil
+}
+
+func (p *formulaParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = formulaBool(left) || formulaBool(right)
+	}
+}
+
+func (p *formulaParser) parseAnd() (interface{}, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = formulaBool(left) && formulaBool(right)
+	}
+}
+
+func (p *formulaParser) parseComparison() (interface{}, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("==", "!=", ">=", "<=", ">", "<")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		cmp := compareFormulaValues(left, right)
+		switch op {
+		case "==":
+			left = cmp == 0
+		case "!=":
+			left = cmp != 0
+		case ">=":
+			left = cmp >= 0
+		case "<=":
+			left = cmp <= 0
+		case ">":
+			left = cmp > 0
+		case "<":
+			left = cmp < 0
+		}
+	}
+}
+
+func (p *formulaParser) parseAdditive() (interface{}, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		_, leftIsString := left.(string)
+		_, rightIsString := right.(string)
+		switch {
+		case op == "+" && (leftIsString || rightIsString):
+			left = FormatFormulaValue(left) + FormatFormulaValue(right)
+		case op == "+":
+			left = formulaNumber(left) + formulaNumber(right)
+		default:
+			left = formulaNumber(left) - formulaNumber(right)
+		}
+	}
+}
+
+func (p *formulaParser) parseMultiplicative() (interface{}, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l, r := formulaNumber(left), formulaNumber(right)
+		switch op {
+		case "*":
+			left = l * r
+		case "/":
+			if r == 0 {
+				if p.skip {
+					left = 0.0
+					continue
+				}
+				return nil, fmt.Errorf("division by zero: %w", ErrInvalidFormula)
+			}
+			left = l / r
+		case "%":
+			if r == 0 {
+				if p.skip {
+					left = 0.0
+					continue
+				}
+				return nil, fmt.Errorf("division by zero: %w", ErrInvalidFormula)
+			}
+			left = math.Mod(l, r)
+		}
+	}
+}
+
+func (p *formulaParser) parseUnary() (interface{}, error) {
+	if op, ok := p.acceptOperator("-", "!"); ok {
+		value, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "!" {
+			return !formulaBool(value), nil
+		}
+		return -formulaNumber(value), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *formulaParser) parsePrimary() (interface{}, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q: %w", t.value, ErrInvalidFormula)
+		}
+		return n, nil
+	case tokenString:
+		return t.value, nil
+	case tokenLParen:
+		value, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case tokenIdent:
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		if err := p.expect(tokenLParen, "("); err != nil {
+			return nil, err
+		}
+		if t.value == "if" {
+			return p.parseIf()
+		}
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		return p.callFunction(t.value, args)
+	}
+	return nil, fmt.Errorf("unexpected %q: %w", t.value, ErrInvalidFormula)
+}
+
+func (p *formulaParser) parseArguments() ([]interface{}, error) {
+	args := []interface{}{}
+	if p.peek().kind == tokenRParen {
+		p.next()
+		return args, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		switch p.next().kind {
+		case tokenComma:
+			continue
+		case tokenRParen:
+			return args, nil
+		default:
+			return nil, fmt.Errorf("expected , or ): %w", ErrInvalidFormula)
+		}
+	}
+}
+
+// parseIf parses the arguments of an if, only evaluating the branch
+// selected by the condition.
+func (p *formulaParser) parseIf() (interface{}, error) {
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(tokenComma, ","); err != nil {
+		return nil, err
+	}
+
+	skip := p.skip
+	defer func() { p.skip = skip }()
+
+	taken := formulaBool(cond)
+	p.skip = skip || !taken
+	whenTrue, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(tokenComma, ","); err != nil {
+		return nil, err
+	}
+	p.skip = skip || taken
+	whenFalse, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(tokenRParen, ")"); err != nil {
+		return nil, err
+	}
+
+	if taken {
+		return whenTrue, nil
+	}
+	return whenFalse, nil
+}
+
+func (p *formulaParser) callFunction(name string, args []interface{}) (interface{}, error) {
+	checkArgs := func(min, max int) error {
+		if len(args) < min || (max >= 0 && len(args) > max) {
+			return fmt.Errorf("wrong number of arguments for %s: %w", name, ErrInvalidFormula)
+		}
+		return nil
+	}
+
+	switch name {
+	case "prop":
+		if err := checkArgs(1, 1); err != nil {
+			return nil, err
+		}
+		if p.skip {
+			return nil, nil
+		}
+		return p.lookup(FormatFormulaValue(args[0]))
+	case "concat":
+		var sb strings.Builder
+		for _, arg := range args {
+			sb.WriteString(FormatFormulaValue(arg))
+		}
+		return sb.String(), nil
+	case "length":
+		if err := checkArgs(1, 1); err != nil {
+			return nil, err
+		}
+		return float64(len([]rune(FormatFormulaValue(args[0])))), nil
+	case "empty":
+		if err := checkArgs(1, 1); err != nil {
+			return nil, err
+		}
+		return args[0] == nil || args[0] == "", nil
+	case "round":
+		if err := checkArgs(1, 2); err != nil {
+			return nil, err
+		}
+		precision := 0.0
+		if len(args) == 2 {
+			precision = formulaNumber(args[1])
+		}
+		factor := math.Pow(10, precision)
+		return math.Round(formulaNumber(args[0])*factor) / factor, nil
+	case "abs":
+		if err := checkArgs(1, 1); err != nil {
+			return nil, err
+		}
+		return math.Abs(formulaNumber(args[0])), nil
+	case "min", "max":
+		if err := checkArgs(1, -1); err != nil {
+			return nil, err
+		}
+		result := formulaNumber(args[0])
+		for _, arg := range args[1:] {
+			n := formulaNumber(arg)
+			if (name == "min" && n < result) || (name == "max" && n > result) {
+				result = n
+			}
+		}
+		return result, nil
+	case "dateBetween":
+		if err := checkArgs(3, 3); err != nil {
+			return nil, err
+		}
+		return formulaDateBetween(args[0], args[1], FormatFormulaValue(args[2]))
+	}
+	return nil, fmt.Errorf("unknown function %s: %w", name, ErrInvalidFormula)
+}
+
+func formulaDateBetween(a, b interface{}, unit string) (interface{}, error) {
+	dateA, okA := a.(time.Time)
+	dateB, okB := b.(time.Time)
+	if !okA || !okB {
+		// missing dates result in an empty value
+		return nil, nil
+	}
+
+	diff := dateA.Sub(dateB)
+	switch unit {
+	case "minutes":
+		return math.Trunc(diff.Minutes()), nil
+	case "hours":
+		return math.Trunc(diff.Hours()), nil
+	case "days":
+		return math.Trunc(diff.Hours() / 24), nil
+	case "weeks":
+		return math.Trunc(diff.Hours() / (24 * 7)), nil
+	}
+	return nil, fmt.Errorf("unknown date unit %s: %w", unit, ErrInvalidFormula)
+}
+
+func formulaNumber(v interface{}) float64 {
+	switch value := v.(type) {
+	case float64:
+		return value
+	case bool:
+		if value {
+			return 1
+		}
+		return 0
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return n
+	case time.Time:
+		return float64(value.UnixMilli())
+	}
+	return 0
+}
+
+func formulaBool(v interface{}) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		return value != "" && value != "false"
+	case time.Time:
+		return !value.IsZero()
+	}
+	return false
+}
+
+func compareFormulaValues(a, b interface{}) int {
+	_, aIsString := a.(string)
+	_, bIsString := b.(string)
+	if aIsString && bIsString {
+		return strings.Compare(a.(string), b.(string))
+	}
+
+	l, r := formulaNumber(a), formulaNumber(b)
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+// FormatFormulaValue returns the string representation of a computed
+// property value, as it is stored in the card properties.
+func FormatFormulaValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		// rounding hides floating point artifacts such as 0.1 + 0.2
+		return strconv.FormatFloat(math.Round(value*1e9)/1e9, 'f', -1, 64)
+	case time.Time:
+		return value.Format("January 02, 2006")
+	}
+	return fmt.Sprintf("%v", v)
+}
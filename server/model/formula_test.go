@@ -0,0 +1,148 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EvaluateFormula(t *testing.T) {
+	props := map[string]interface{}{
+		"Points":   3.0,
+		"Estimate": 2.0,
+		"Name":     "Task",
+		"Empty":    nil,
+	}
+	lookup := func(name string) (interface{}, error) {
+		value, ok := props[name]
+		if !ok {
+			return nil, ErrInvalidFormula
+		}
+		return value, nil
+	}
+
+	tests := []struct {
+		formula  string
+		expected interface{}
+	}{
+		{`1 + 2 * 3`, 7.0},
+		{`(1 + 2) * 3`, 9.0},
+		{`-2 + 10 % 4`, 0.0},
+		{`prop("Points") / prop("Estimate")`, 1.5},
+		{`prop("Points") > prop("Estimate") && !false`, true},
+		{`prop("Points") == 3 || prop("Estimate") == 3`, true},
+		{`if(prop("Points") > 5, "big", "small")`, "small"},
+		{`if(prop("Empty") == 0, 0, 10 / prop("Empty"))`, 0.0},
+		{`concat(prop("Name"), " #", prop("Points"))`, "Task #3"},
+		{`prop("Name") + " " + 1`, "Task 1"},
+		{`round(10 / 3, 2)`, 3.33},
+		{`max(1, prop("Points"), 2)`, 3.0},
+		{`length("hello")`, 5.0},
+		{`empty(prop("Empty"))`, true},
+		{`"a \"quoted\" text"`, `a "quoted" text`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.formula, func(t *testing.T) {
+			value, err := evaluateFormula(tc.formula, lookup)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+
+	invalid := []string{
+		`1 +`,
+		`(1 + 2`,
+		`unknown(1)`,
+		`prop("Missing")`,
+		`1 / 0`,
+		`"unterminated`,
+		`1 = 2`,
+		`round()`,
+	}
+	for _, formula := range invalid {
+		t.Run(formula, func(t *testing.T) {
+			_, err := evaluateFormula(formula, lookup)
+			require.ErrorIs(t, err, ErrInvalidFormula)
+		})
+	}
+}
+
+func Test_FormatFormulaValue(t *testing.T) {
+	assert.Equal(t, "", FormatFormulaValue(nil))
+	assert.Equal(t, "0.3", FormatFormulaValue(0.1+0.2))
+	assert.Equal(t, "42", FormatFormulaValue(42.0))
+	assert.Equal(t, "true", FormatFormulaValue(true))
+	assert.Equal(t, "text", FormatFormulaValue("text"))
+}
+
+func Test_ComputeCardProperties(t *testing.T) {
+	board := &Board{
+		ID: "board_id_1",
+		CardProperties: []map[string]interface{}{
+			{"id": "points", "name": "Points", "type": "number"},
+			{"id": "done", "name": "Done", "type": "checkbox"},
+			{
+				"id": "status", "name": "Status", "type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "opt_1", "value": "Doing"},
+				},
+			},
+			{"id": "start", "name": "Start", "type": "date"},
+			{"id": "end", "name": "End", "type": "date"},
+			{"id": "double", "name": "Double", "type": PropTypeFormula, "formula": `prop("Points") * 2`},
+			{"id": "quad", "name": "Quad", "type": PropTypeFormula, "formula": `prop("Double") * 2`},
+			{"id": "label", "name": "Label", "type": PropTypeFormula, "formula": `if(prop("Done"), "done", concat("is ", prop("Status")))`},
+			{"id": "duration", "name": "Duration", "type": PropTypeFormula, "formula": `dateBetween(prop("End"), prop("Start"), "days")`},
+			{"id": "loop1", "name": "Loop1", "type": PropTypeFormula, "formula": `prop("Loop2")`},
+			{"id": "loop2", "name": "Loop2", "type": PropTypeFormula, "formula": `prop("Loop1")`},
+			{"id": "completion", "name": "Completion", "type": PropTypeRollup, "rollup": RollupCheckboxCompletion},
+			{"id": "comments", "name": "Comments", "type": PropTypeRollup, "rollup": RollupCommentCount},
+			{"id": "progress", "name": "Progress", "type": PropTypeFormula, "formula": `concat(prop("Completion"), "%")`},
+		},
+	}
+	schema, err := ParsePropertySchema(board)
+	require.NoError(t, err)
+	require.True(t, schema.HasComputedProperties())
+	require.True(t, schema.HasRollupProperties())
+
+	card := &Block{
+		ID:   "card_id_1",
+		Type: TypeCard,
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{
+				"points": "4",
+				"status": "opt_1",
+				"start":  `{"from":1642161600000}`,
+				"end":    `{"from":1642420800000}`,
+				"double": "a stale value",
+			},
+		},
+	}
+	children := []*Block{
+		{ID: "check_1", Type: TypeCheckbox, Fields: map[string]interface{}{"value": true}},
+		{ID: "check_2", Type: TypeCheckbox, Fields: map[string]interface{}{"value": false}},
+		{ID: "check_3", Type: TypeCheckbox, Fields: map[string]interface{}{"value": true}},
+		{ID: "check_4", Type: TypeCheckbox, Fields: map[string]interface{}{"value": true}, DeleteAt: 1},
+		{ID: "comment_1", Type: TypeComment},
+		{ID: "text_1", Type: TypeText},
+	}
+
+	values, errs := ComputeCardProperties(schema, card, children)
+	assert.Equal(t, map[string]string{
+		"double":     "8",
+		"quad":       "16",
+		"label":      "is Doing",
+		"duration":   "3",
+		"completion": "67",
+		"comments":   "1",
+		"progress":   "67%",
+	}, values)
+	require.Len(t, errs, 2)
+	assert.ErrorIs(t, errs["loop1"], ErrFormulaCycle)
+	assert.ErrorIs(t, errs["loop2"], ErrFormulaCycle)
+}
@@ -34,6 +34,21 @@ func (m *MockPropValueResolver) EXPECT() *MockPropValueResolverMockRecorder {
 	return m.recorder
 }
 
+// GetBlocksByIDs mocks base method.
+func (m *MockPropValueResolver) GetBlocksByIDs(arg0 []string) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocksByIDs", arg0)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocksByIDs indicates an expected call of GetBlocksByIDs.
+func (mr *MockPropValueResolverMockRecorder) GetBlocksByIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocksByIDs", reflect.TypeOf((*MockPropValueResolver)(nil).GetBlocksByIDs), arg0)
+}
+
 // GetUserByID mocks base method.
 func (m *MockPropValueResolver) GetUserByID(arg0 string) (*model.User, error) {
 	m.ctrl.T.Helper()
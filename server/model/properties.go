@@ -21,10 +21,17 @@ var ErrInvalidPropertyValue = errors.New("invalid property value")
 var ErrInvalidPropertyValueType = errors.New("invalid property value type")
 var ErrInvalidDate = errors.New("invalid date property")
 
+const (
+	// PropTypeRelation is the type of the properties holding the ids
+	// of one or more related cards.
+	PropTypeRelation = "relation"
+)
+
 // PropValueResolver allows PropDef.GetValue to further decode property values, such as
 // looking up usernames from ids.
 type PropValueResolver interface {
 	GetUserByID(userID string) (*User, error)
+	GetBlocksByIDs(ids []string) ([]*Block, error)
 }
 
 // BlockProperties is a map of Prop's keyed by property id.
@@ -56,6 +63,22 @@ type PropDef struct {
 	Name    string                   `json:"name"`
 	Type    string                   `json:"type"`
 	Options map[string]PropDefOption `json:"options"`
+
+	// RelationBoardID is the board of the related cards for relation
+	// properties.
+	RelationBoardID string `json:"relationBoardId,omitempty"`
+
+	// BackReferenceID is the id of the relation property on the
+	// related board that mirrors this one, if any.
+	BackReferenceID string `json:"backReferenceId,omitempty"`
+
+	// Formula is the expression computing the value of formula
+	// properties.
+	Formula string `json:"formula,omitempty"`
+
+	// Rollup is the kind of aggregation over the card content computing
+	// the value of rollup properties.
+	Rollup string `json:"rollup,omitempty"`
 }
 
 // GetValue resolves the value of a property if the passed value is an ID for an option,
@@ -148,10 +171,70 @@ func (pd PropDef) GetValue(v interface{}, resolver PropValueResolver) (string, e
 			sb.WriteString(strings.ToUpper(opt.Value))
 		}
 		return sb.String(), nil
+
+	case PropTypeRelation:
+		// v is a slice of card IDs
+		cardIDs, err := GetRelationIDs(v)
+		if err != nil {
+			return "", fmt.Errorf("relation property type: %w", err)
+		}
+		if resolver != nil && len(cardIDs) > 0 {
+			cards, err := resolver.GetBlocksByIDs(cardIDs)
+			if err != nil && !IsErrNotFound(err) {
+				return "", err
+			}
+
+			// only the titles of the cards of the related board are
+			// shown, as the readers may not see the other boards
+			titles := make(map[string]string, len(cards))
+			for _, card := range cards {
+				if card.BoardID == pd.RelationBoardID {
+					titles[card.ID] = card.Title
+				}
+			}
+
+			values := make([]string, len(cardIDs))
+			for i, cardID := range cardIDs {
+				title, ok := titles[cardID]
+				if !ok {
+					title = cardID
+				}
+				values[i] = title
+			}
+			return strings.Join(values, ", "), nil
+		}
+		return strings.Join(cardIDs, ", "), nil
 	}
 	return fmt.Sprintf("%v", v), nil
 }
 
+// GetRelationIDs returns the card IDs stored in a relation property
+// value, which can be a single ID or a slice of them.
+func GetRelationIDs(v interface{}) ([]string, error) {
+	switch value := v.(type) {
+	case nil:
+		return []string{}, nil
+	case string:
+		if value == "" {
+			return []string{}, nil
+		}
+		return []string{value}, nil
+	case []string:
+		return value, nil
+	case []interface{}:
+		ids := make([]string, 0, len(value))
+		for _, idIface := range value {
+			id, ok := idIface.(string)
+			if !ok {
+				return nil, ErrInvalidPropertyValueType
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+	return nil, ErrInvalidPropertyValueType
+}
+
 func (pd PropDef) ParseDate(s string) (string, error) {
 	// s is a JSON snippet of the form: {"from":1642161600000, "to":1642161600000} in milliseconds UTC
 	// The UI does not yet support date ranges.
@@ -180,11 +263,15 @@ func ParsePropertySchema(board *Board) (PropSchema, error) {
 
 	for i, prop := range board.CardProperties {
 		pd := PropDef{
-			ID:      getMapString("id", prop),
-			Index:   i,
-			Name:    getMapString("name", prop),
-			Type:    getMapString("type", prop),
-			Options: make(map[string]PropDefOption),
+			ID:              getMapString("id", prop),
+			Index:           i,
+			Name:            getMapString("name", prop),
+			Type:            getMapString("type", prop),
+			Options:         make(map[string]PropDefOption),
+			RelationBoardID: getMapString("relationBoardId", prop),
+			BackReferenceID: getMapString("backReferenceId", prop),
+			Formula:         getMapString("formula", prop),
+			Rollup:          getMapString("rollup", prop),
 		}
 		optsIface, ok := prop["options"]
 		if ok {
@@ -30,6 +30,25 @@ func (r MockResolver) GetUserByID(userID string) (*User, error) {
 	return nil, nil
 }
 
+func (r MockResolver) GetBlocksByIDs(ids []string) ([]*Block, error) {
+	blocks := []*Block{}
+	for _, id := range ids {
+		switch id {
+		case "card_id_1":
+			blocks = append(blocks, &Block{ID: id, BoardID: "board_id_1", Type: TypeCard, Title: "Card 1"})
+		case "card_id_2":
+			blocks = append(blocks, &Block{ID: id, BoardID: "board_id_1", Type: TypeCard, Title: "Card 2"})
+		case "card_id_other":
+			blocks = append(blocks, &Block{ID: id, BoardID: "board_id_2", Type: TypeCard, Title: "Private card"})
+		}
+	}
+
+	if len(blocks) != len(ids) {
+		return blocks, NewErrNotAllFound("block", ids)
+	}
+	return blocks, nil
+}
+
 func Test_parsePropertySchema(t *testing.T) {
 	board := &Board{
 		ID:     utils.NewID(utils.IDTypeBoard),
@@ -89,6 +108,43 @@ func Test_GetValue(t *testing.T) {
 	require.Equal(t, "michael_scott, jim_halpert", value)
 }
 
+func Test_GetValueRelation(t *testing.T) {
+	resolver := MockResolver{}
+
+	propDef := PropDef{
+		Type:            PropTypeRelation,
+		RelationBoardID: "board_id_1",
+	}
+
+	value, err := propDef.GetValue([]interface{}{"card_id_1", "card_id_2"}, resolver)
+	require.NoError(t, err)
+	require.Equal(t, "Card 1, Card 2", value)
+
+	// trying with a single card id
+	value, err = propDef.GetValue("card_id_2", resolver)
+	require.NoError(t, err)
+	require.Equal(t, "Card 2", value)
+
+	// trying with an unknown card
+	value, err = propDef.GetValue([]interface{}{"card_id_1", "card_id_unknown"}, resolver)
+	require.NoError(t, err)
+	require.Equal(t, "Card 1, card_id_unknown", value)
+
+	// trying with a card of another board
+	value, err = propDef.GetValue([]interface{}{"card_id_1", "card_id_other"}, resolver)
+	require.NoError(t, err)
+	require.Equal(t, "Card 1, card_id_other", value)
+
+	// trying without resolver
+	value, err = propDef.GetValue([]interface{}{"card_id_1", "card_id_2"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "card_id_1, card_id_2", value)
+
+	// trying with an invalid value
+	_, err = propDef.GetValue([]interface{}{1, 2}, resolver)
+	require.ErrorIs(t, err, ErrInvalidPropertyValueType)
+}
+
 const (
 	cardPropertiesExample = `[
 	   {
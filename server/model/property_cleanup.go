@@ -0,0 +1,154 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// ArchivedPropertyValue is a card property value removed along with its
+// property or option, kept so it can be restored
+// swagger:model
+type ArchivedPropertyValue struct {
+	// The id of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The id of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The id of the property
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// The value of the property before its removal
+	// required: true
+	Value interface{} `json:"value"`
+
+	// The id of the user that removed the value
+	// required: true
+	ArchivedBy string `json:"archivedBy"`
+
+	// The removal time in miliseconds since the current epoch
+	// required: true
+	ArchivedAt int64 `json:"archivedAt"`
+}
+
+// PropertyCleanup describes how to update the card values after the
+// properties or options they refer to are removed from the board.
+type PropertyCleanup struct {
+	// RemovedProperties are the ids of the removed properties.
+	RemovedProperties map[string]bool
+
+	// RemovedOptions maps the removed option ids of each property to
+	// their replacement option, or to an empty string if the values
+	// are removed.
+	RemovedOptions map[string]map[string]string
+}
+
+// NewPropertyCleanup compares the schemas of a board before and after a
+// change. A removed option is replaced by its remapped option if the
+// option is still in the new schema.
+func NewPropertyCleanup(oldSchema, newSchema PropSchema, remappedOptions map[string]map[string]string) *PropertyCleanup {
+	cleanup := &PropertyCleanup{
+		RemovedProperties: map[string]bool{},
+		RemovedOptions:    map[string]map[string]string{},
+	}
+
+	for propID, oldDef := range oldSchema {
+		newDef, ok := newSchema[propID]
+		if !ok {
+			cleanup.RemovedProperties[propID] = true
+			continue
+		}
+		if oldDef.Type != newDef.Type || (newDef.Type != "select" && newDef.Type != "multiSelect") {
+			continue
+		}
+
+		for optionID := range oldDef.Options {
+			if _, ok := newDef.Options[optionID]; ok {
+				continue
+			}
+			replacement := remappedOptions[propID][optionID]
+			if _, ok := newDef.Options[replacement]; !ok {
+				replacement = ""
+			}
+			if cleanup.RemovedOptions[propID] == nil {
+				cleanup.RemovedOptions[propID] = map[string]string{}
+			}
+			cleanup.RemovedOptions[propID][optionID] = replacement
+		}
+	}
+	return cleanup
+}
+
+// IsEmpty returns true if no card values need to be updated.
+func (c *PropertyCleanup) IsEmpty() bool {
+	return len(c.RemovedProperties) == 0 && len(c.RemovedOptions) == 0
+}
+
+// Apply removes or remaps the values of the card properties, and
+// returns the previous values of the properties it changed.
+func (c *PropertyCleanup) Apply(props map[string]interface{}) map[string]interface{} {
+	oldValues := map[string]interface{}{}
+
+	for propID, value := range props {
+		if c.RemovedProperties[propID] {
+			oldValues[propID] = value
+			delete(props, propID)
+			continue
+		}
+
+		removedOptions, ok := c.RemovedOptions[propID]
+		if !ok {
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			replacement, removed := removedOptions[v]
+			if !removed {
+				continue
+			}
+			oldValues[propID] = value
+			if replacement == "" {
+				delete(props, propID)
+			} else {
+				props[propID] = replacement
+			}
+
+		case []interface{}:
+			changed := false
+			optionIDs := make([]interface{}, 0, len(v))
+			seen := map[string]bool{}
+			for _, item := range v {
+				optionID, ok := item.(string)
+				if !ok {
+					optionIDs = append(optionIDs, item)
+					continue
+				}
+				if replacement, removed := removedOptions[optionID]; removed {
+					changed = true
+					if replacement == "" {
+						continue
+					}
+					optionID = replacement
+				}
+				// a replacement can already be in the values
+				if seen[optionID] {
+					continue
+				}
+				seen[optionID] = true
+				optionIDs = append(optionIDs, optionID)
+			}
+			if !changed {
+				continue
+			}
+			oldValues[propID] = value
+			if len(optionIDs) == 0 {
+				delete(props, propID)
+			} else {
+				props[propID] = optionIDs
+			}
+		}
+	}
+	return oldValues
+}
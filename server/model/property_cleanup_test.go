@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyCleanup(t *testing.T) {
+	oldSchema := PropSchema{
+		"status": PropDef{ID: "status", Type: "select", Options: map[string]PropDefOption{
+			"todo":  {ID: "todo"},
+			"doing": {ID: "doing"},
+			"done":  {ID: "done"},
+		}},
+		"tags": PropDef{ID: "tags", Type: "multiSelect", Options: map[string]PropDefOption{
+			"a": {ID: "a"},
+			"b": {ID: "b"},
+			"c": {ID: "c"},
+		}},
+		"notes": PropDef{ID: "notes", Type: "text"},
+	}
+	newSchema := PropSchema{
+		"status": PropDef{ID: "status", Type: "select", Options: map[string]PropDefOption{
+			"todo": {ID: "todo"},
+			"done": {ID: "done"},
+		}},
+		"tags": PropDef{ID: "tags", Type: "multiSelect", Options: map[string]PropDefOption{
+			"a": {ID: "a"},
+		}},
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		require.True(t, NewPropertyCleanup(oldSchema, oldSchema, nil).IsEmpty())
+	})
+
+	t.Run("removed properties and options", func(t *testing.T) {
+		cleanup := NewPropertyCleanup(oldSchema, newSchema, map[string]map[string]string{
+			"status": {"doing": "todo"},
+			"tags":   {"b": "a", "c": "missing"},
+		})
+		require.False(t, cleanup.IsEmpty())
+		require.Equal(t, map[string]bool{"notes": true}, cleanup.RemovedProperties)
+		require.Equal(t, map[string]string{"doing": "todo"}, cleanup.RemovedOptions["status"])
+		require.Equal(t, map[string]string{"b": "a", "c": ""}, cleanup.RemovedOptions["tags"])
+
+		props := map[string]interface{}{
+			"status": "doing",
+			"tags":   []interface{}{"a", "b", "c"},
+			"notes":  "some notes",
+		}
+		oldValues := cleanup.Apply(props)
+		require.Equal(t, map[string]interface{}{
+			"status": "todo",
+			"tags":   []interface{}{"a"},
+		}, props)
+		require.Equal(t, map[string]interface{}{
+			"status": "doing",
+			"tags":   []interface{}{"a", "b", "c"},
+			"notes":  "some notes",
+		}, oldValues)
+	})
+
+	t.Run("values without removed options are kept", func(t *testing.T) {
+		cleanup := NewPropertyCleanup(oldSchema, newSchema, nil)
+		props := map[string]interface{}{
+			"status": "done",
+			"tags":   []interface{}{"a"},
+		}
+		require.Empty(t, cleanup.Apply(props))
+		require.Equal(t, "done", props["status"])
+		require.Equal(t, []interface{}{"a"}, props["tags"])
+	})
+
+	t.Run("values of only removed options are deleted", func(t *testing.T) {
+		cleanup := NewPropertyCleanup(oldSchema, newSchema, nil)
+		props := map[string]interface{}{
+			"status": "doing",
+			"tags":   []interface{}{"b", "c"},
+		}
+		oldValues := cleanup.Apply(props)
+		require.Len(t, oldValues, 2)
+		require.Empty(t, props)
+	})
+}
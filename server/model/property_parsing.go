@@ -0,0 +1,234 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PropValueParser allows PropDef.ParseValue to resolve display values,
+// such as looking up user ids from usernames.
+type PropValueParser interface {
+	// FindUser returns the user with the passed username, email or id,
+	// or nil if there is none.
+	FindUser(name string) (*User, error)
+}
+
+// dateRangeSeparator separates the start and the end of date ranges, as
+// returned by PropDef.ParseDate.
+const dateRangeSeparator = "->"
+
+// dateLayouts are the formats accepted for date values, in UTC.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"01/02/2006",
+	"January 02, 2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+// ParseValue converts the display value of a property, like the ones
+// found in a CSV file, to the value stored in the card properties.
+// Options are looked up by value and users by username or email. Multiple
+// values are separated by commas. Empty values return nil.
+func (pd PropDef) ParseValue(s string, parser PropValueParser) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	switch pd.Type {
+	case "text", "url", "email", "phone":
+		return s, nil
+
+	case "number":
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrInvalidPropertyValue, s)
+		}
+		return s, nil
+
+	case "checkbox":
+		switch strings.ToLower(s) {
+		case "true", "yes", "y", "x", "1":
+			return "true", nil
+		case "false", "no", "n", "0":
+			return "false", nil
+		}
+		return nil, fmt.Errorf("%w: %q is not a checkbox value", ErrInvalidPropertyValue, s)
+
+	case "select":
+		option, ok := pd.FindOption(s)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown option %q", ErrInvalidPropertyValue, s)
+		}
+		return option.ID, nil
+
+	case "multiSelect":
+		optionIDs := []interface{}{}
+		for _, value := range splitValues(s) {
+			option, ok := pd.FindOption(value)
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown option %q", ErrInvalidPropertyValue, value)
+			}
+			optionIDs = append(optionIDs, option.ID)
+		}
+		return optionIDs, nil
+
+	case "date":
+		return pd.ParseDateValue(s)
+
+	case "person":
+		return findUserID(s, parser)
+
+	case "multiPerson":
+		userIDs := []interface{}{}
+		for _, name := range splitValues(s) {
+			userID, err := findUserID(name, parser)
+			if err != nil {
+				return nil, err
+			}
+			userIDs = append(userIDs, userID)
+		}
+		return userIDs, nil
+	}
+
+	return nil, fmt.Errorf("%w: values of %s properties can't be parsed", ErrInvalidProperty, pd.Type)
+}
+
+// IsParseable returns true if the values of the property can be parsed
+// by ParseValue. Computed properties, relations and the properties set by
+// the server can't.
+func (pd PropDef) IsParseable() bool {
+	switch pd.Type {
+	case "text", "url", "email", "phone", "number", "checkbox",
+		"select", "multiSelect", "date", "person", "multiPerson":
+		return true
+	}
+	return false
+}
+
+// FindOption returns the option of the property with the passed value,
+// ignoring case and surrounding spaces. If several options have the value,
+// the first one is returned.
+func (pd PropDef) FindOption(value string) (PropDefOption, bool) {
+	var found PropDefOption
+	ok := false
+	for _, option := range pd.Options {
+		if !strings.EqualFold(strings.TrimSpace(option.Value), strings.TrimSpace(value)) {
+			continue
+		}
+		if !ok || option.Index < found.Index {
+			found, ok = option, true
+		}
+	}
+	return found, ok
+}
+
+// MissingOptionValues returns the values of a select or multi-select
+// display value that have no option in the property.
+func (pd PropDef) MissingOptionValues(s string) []string {
+	if pd.Type != "select" && pd.Type != "multiSelect" {
+		return nil
+	}
+
+	values := []string{strings.TrimSpace(s)}
+	if pd.Type == "multiSelect" {
+		values = splitValues(s)
+	}
+
+	missing := []string{}
+	for _, value := range values {
+		if _, ok := pd.FindOption(value); value != "" && !ok {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+// ParseDateValue converts a date, or a date range of the form
+// "start -> end", to a date property value. Dates can be times in
+// milliseconds, date property values or dates in one of the usual layouts,
+// interpreted in UTC.
+func (pd PropDef) ParseDateValue(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		if reason := validateDateValue(s); reason != "" {
+			return "", fmt.Errorf("%w: %s", ErrInvalidDate, reason)
+		}
+		return s, nil
+	}
+
+	value := map[string]int64{}
+	parts := strings.SplitN(s, dateRangeSeparator, 2)
+	for i, part := range parts {
+		millis, err := parseDateMillis(strings.TrimSpace(part))
+		if err != nil {
+			return "", err
+		}
+		if i == 0 {
+			value["from"] = millis
+		} else {
+			value["to"] = millis
+		}
+	}
+	if to, ok := value["to"]; ok && to < value["from"] {
+		return "", fmt.Errorf("%w: %q ends before it starts", ErrInvalidDate, s)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func parseDateMillis(s string) (int64, error) {
+	if millis, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return millis, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q is not a date", ErrInvalidDate, s)
+}
+
+func findUserID(name string, parser PropValueParser) (string, error) {
+	if parser == nil {
+		return name, nil
+	}
+
+	user, err := parser.FindUser(strings.TrimPrefix(name, "@"))
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", fmt.Errorf("%w: unknown user %q", ErrInvalidPropertyValue, name)
+	}
+	return user.ID, nil
+}
+
+// splitValues splits a comma separated display value, dropping the empty
+// values.
+func splitValues(s string) []string {
+	values := []string{}
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
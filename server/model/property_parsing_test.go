@@ -0,0 +1,85 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testValueParser map[string]*User
+
+func (p testValueParser) FindUser(name string) (*User, error) {
+	return p[strings.ToLower(name)], nil
+}
+
+func TestPropDefParseValue(t *testing.T) {
+	options := map[string]PropDefOption{
+		"todo": {ID: "todo", Index: 0, Value: "To do"},
+		"done": {ID: "done", Index: 1, Value: "Done"},
+	}
+	parser := testValueParser{
+		"alice":           {ID: "user1", Username: "alice"},
+		"bob@example.com": {ID: "user2", Username: "bob"},
+	}
+
+	testCases := []struct {
+		name     string
+		propType string
+		value    string
+		expected interface{}
+		err      error
+	}{
+		{"empty value", "text", "  ", nil, nil},
+		{"text", "text", " hello ", "hello", nil},
+		{"number", "number", "3.5", "3.5", nil},
+		{"invalid number", "number", "three", nil, ErrInvalidPropertyValue},
+		{"checkbox", "checkbox", "Yes", "true", nil},
+		{"invalid checkbox", "checkbox", "maybe", nil, ErrInvalidPropertyValue},
+		{"select", "select", "done", "done", nil},
+		{"unknown option", "select", "Blocked", nil, ErrInvalidPropertyValue},
+		{"multi-select", "multiSelect", "to do, Done", []interface{}{"todo", "done"}, nil},
+		{"date", "date", "2024-01-15", `{"from":1705276800000}`, nil},
+		{"date range", "date", "January 15, 2024 -> January 16, 2024", `{"from":1705276800000,"to":1705363200000}`, nil},
+		{"date in milliseconds", "date", "1705276800000", `{"from":1705276800000}`, nil},
+		{"date value", "date", `{"from":1705276800000}`, `{"from":1705276800000}`, nil},
+		{"invalid date", "date", "someday", nil, ErrInvalidDate},
+		{"reversed date range", "date", "2024-01-16 -> 2024-01-15", nil, ErrInvalidDate},
+		{"person", "person", "@alice", "user1", nil},
+		{"unknown person", "person", "dave", nil, ErrInvalidPropertyValue},
+		{"multi-person", "multiPerson", "alice, bob@example.com", []interface{}{"user1", "user2"}, nil},
+		{"relation", PropTypeRelation, "card1", nil, ErrInvalidProperty},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			propDef := PropDef{ID: "prop", Type: tc.propType, Options: options}
+			value, err := propDef.ParseValue(tc.value, parser)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestPropDefMissingOptionValues(t *testing.T) {
+	options := map[string]PropDefOption{
+		"todo": {ID: "todo", Value: "To do"},
+	}
+
+	sel := PropDef{Type: "select", Options: options}
+	require.Empty(t, sel.MissingOptionValues("TO DO"))
+	require.Equal(t, []string{"Blocked, urgent"}, sel.MissingOptionValues(" Blocked, urgent "))
+
+	multi := PropDef{Type: "multiSelect", Options: options}
+	require.Equal(t, []string{"Blocked", "urgent"}, multi.MissingOptionValues("to do, Blocked,, urgent"))
+
+	text := PropDef{Type: "text"}
+	require.Empty(t, text.MissingOptionValues("Blocked"))
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type PropertyValidationMode string
+
+const (
+	// PropertyValidationOff doesn't check the card property values.
+	PropertyValidationOff PropertyValidationMode = "off"
+	// PropertyValidationLenient drops the invalid card property values
+	// from the writes.
+	PropertyValidationLenient PropertyValidationMode = "lenient"
+	// PropertyValidationStrict rejects the writes with invalid card
+	// property values.
+	PropertyValidationStrict PropertyValidationMode = "strict"
+)
+
+// PropertyValueError describes an invalid card property value
+// swagger:model
+type PropertyValueError struct {
+	// The id of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The id of the property
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// The invalid value
+	// required: true
+	Value interface{} `json:"value"`
+
+	// Why the value is invalid
+	// required: true
+	Reason string `json:"reason"`
+}
+
+// ErrInvalidPropertyValues is returned when card property values don't
+// match the property schema of their board.
+type ErrInvalidPropertyValues struct {
+	Errors []PropertyValueError
+}
+
+func NewErrInvalidPropertyValues(errors []PropertyValueError) *ErrInvalidPropertyValues {
+	return &ErrInvalidPropertyValues{Errors: errors}
+}
+
+func (e *ErrInvalidPropertyValues) Error() string {
+	reasons := make([]string, 0, len(e.Errors))
+	for _, valueErr := range e.Errors {
+		reasons = append(reasons, fmt.Sprintf("card %s property %s: %s", valueErr.CardID, valueErr.PropertyID, valueErr.Reason))
+	}
+	return "invalid property values: " + strings.Join(reasons, "; ")
+}
+
+// ValidateProperties checks card property values against the schema and
+// returns an error per invalid value, sorted by property id. Values equal
+// to the ones in oldProps are not checked, so values made invalid by
+// schema changes don't prevent other changes to the card.
+func (s PropSchema) ValidateProperties(cardID string, props, oldProps map[string]interface{}) []PropertyValueError {
+	errors := []PropertyValueError{}
+	for propID, value := range props {
+		if oldValue, ok := oldProps[propID]; ok && reflect.DeepEqual(value, oldValue) {
+			continue
+		}
+
+		var reason string
+		if propDef, ok := s[propID]; ok {
+			reason = propDef.validateValue(value)
+		} else {
+			reason = "unknown property"
+		}
+
+		if reason != "" {
+			errors = append(errors, PropertyValueError{
+				CardID:     cardID,
+				PropertyID: propID,
+				Value:      value,
+				Reason:     reason,
+			})
+		}
+	}
+
+	sort.Slice(errors, func(i, j int) bool { return errors[i].PropertyID < errors[j].PropertyID })
+	return errors
+}
+
+// validateValue returns why a value is not valid for the property, or
+// an empty string if it is. Empty values are valid for all the types.
+func (pd PropDef) validateValue(value interface{}) string {
+	if value == nil || value == "" {
+		return ""
+	}
+
+	switch pd.Type {
+	case "text", "url", "email", "phone":
+		if _, ok := value.(string); !ok {
+			return "must be a string"
+		}
+
+	case "number":
+		switch v := value.(type) {
+		case float64:
+		case string:
+			if _, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+				return "must be a number"
+			}
+		default:
+			return "must be a number"
+		}
+
+	case "checkbox":
+		switch v := value.(type) {
+		case bool:
+		case string:
+			if v != "true" && v != "false" {
+				return "must be true or false"
+			}
+		default:
+			return "must be true or false"
+		}
+
+	case "select":
+		optionID, ok := value.(string)
+		if !ok {
+			return "must be an option id"
+		}
+		if _, ok := pd.Options[optionID]; !ok {
+			return fmt.Sprintf("unknown option %s", optionID)
+		}
+
+	case "multiSelect":
+		optionIDs, ok := stringValues(value)
+		if !ok {
+			return "must be a list of option ids"
+		}
+		for _, optionID := range optionIDs {
+			if _, ok := pd.Options[optionID]; !ok {
+				return fmt.Sprintf("unknown option %s", optionID)
+			}
+		}
+
+	case "person":
+		if _, ok := value.(string); !ok {
+			return "must be a user id"
+		}
+
+	case "multiPerson", PropTypeRelation:
+		if _, ok := stringValues(value); !ok {
+			return "must be a list of ids"
+		}
+
+	case "date":
+		s, ok := value.(string)
+		if !ok {
+			return "must be a date"
+		}
+		return validateDateValue(s)
+	}
+
+	// the other types are computed or not constrained
+	return ""
+}
+
+// stringValues returns the strings of a single or multiple value
+// property value.
+func stringValues(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, true
+	case []string:
+		return v, true
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// validateDateValue checks a date property value of the form
+// {"from":1642161600000, "to":1642161600000}, in milliseconds.
+func validateDateValue(s string) string {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return "malformed date"
+	}
+
+	from, hasFrom := m["from"]
+	to, hasTo := m["to"]
+	if !hasFrom && !hasTo {
+		return "missing date"
+	}
+	for key, raw := range m {
+		switch key {
+		case "from", "to":
+			var millis int64
+			if err := json.Unmarshal(raw, &millis); err != nil {
+				return fmt.Sprintf("date %s must be a time in milliseconds", key)
+			}
+		case "includeTime", "timeZone":
+		default:
+			return fmt.Sprintf("unknown date field %s", key)
+		}
+	}
+
+	if hasFrom && hasTo {
+		var fromMillis, toMillis int64
+		_ = json.Unmarshal(from, &fromMillis)
+		_ = json.Unmarshal(to, &toMillis)
+		if toMillis < fromMillis {
+			return "date end before date start"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProperties(t *testing.T) {
+	schema := PropSchema{
+		"text":     PropDef{ID: "text", Type: "text"},
+		"number":   PropDef{ID: "number", Type: "number"},
+		"checkbox": PropDef{ID: "checkbox", Type: "checkbox"},
+		"select": PropDef{ID: "select", Type: "select", Options: map[string]PropDefOption{
+			"opt1": {ID: "opt1", Value: "One"},
+			"opt2": {ID: "opt2", Value: "Two"},
+		}},
+		"multi": PropDef{ID: "multi", Type: "multiSelect", Options: map[string]PropDefOption{
+			"opt1": {ID: "opt1", Value: "One"},
+		}},
+		"people":  PropDef{ID: "people", Type: "multiPerson"},
+		"date":    PropDef{ID: "date", Type: "date"},
+		"formula": PropDef{ID: "formula", Type: PropTypeFormula},
+	}
+
+	t.Run("valid values", func(t *testing.T) {
+		props := map[string]interface{}{
+			"text":     "hello",
+			"number":   "12.5",
+			"checkbox": "true",
+			"select":   "opt2",
+			"multi":    []interface{}{"opt1"},
+			"people":   []interface{}{"user1", "user2"},
+			"date":     `{"from":1642161600000,"to":1642248000000,"includeTime":true}`,
+			"formula":  42.0,
+		}
+		require.Empty(t, schema.ValidateProperties("card", props, nil))
+	})
+
+	t.Run("empty values", func(t *testing.T) {
+		props := map[string]interface{}{
+			"number": "",
+			"select": "",
+			"date":   "",
+			"people": nil,
+		}
+		require.Empty(t, schema.ValidateProperties("card", props, nil))
+	})
+
+	testCases := []struct {
+		name   string
+		propID string
+		value  interface{}
+	}{
+		{"unknown property", "missing", "value"},
+		{"text not a string", "text", 12.0},
+		{"number not a number", "number", "twelve"},
+		{"checkbox not a bool", "checkbox", "yes"},
+		{"unknown select option", "select", "opt3"},
+		{"select not an id", "select", []interface{}{"opt1"}},
+		{"unknown multi select option", "multi", []interface{}{"opt1", "opt2"}},
+		{"people not ids", "people", []interface{}{12.0}},
+		{"malformed date", "date", "tomorrow"},
+		{"date without times", "date", `{"includeTime":true}`},
+		{"date ending before start", "date", `{"from":1642248000000,"to":1642161600000}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			props := map[string]interface{}{tc.propID: tc.value}
+			errs := schema.ValidateProperties("card", props, nil)
+			require.Len(t, errs, 1)
+			require.Equal(t, "card", errs[0].CardID)
+			require.Equal(t, tc.propID, errs[0].PropertyID)
+			require.Equal(t, tc.value, errs[0].Value)
+			require.NotEmpty(t, errs[0].Reason)
+		})
+	}
+
+	t.Run("unchanged values are not checked", func(t *testing.T) {
+		oldProps := map[string]interface{}{"select": "removed-option", "text": "old"}
+		props := map[string]interface{}{"select": "removed-option", "text": 12.0}
+		errs := schema.ValidateProperties("card", props, oldProps)
+		require.Len(t, errs, 1)
+		require.Equal(t, "text", errs[0].PropertyID)
+	})
+
+	t.Run("errors are sorted by property", func(t *testing.T) {
+		props := map[string]interface{}{"text": 1.0, "number": "x", "checkbox": "y"}
+		errs := schema.ValidateProperties("card", props, nil)
+		require.Len(t, errs, 3)
+		require.Equal(t, "checkbox", errs[0].PropertyID)
+		require.Equal(t, "number", errs[1].PropertyID)
+		require.Equal(t, "text", errs[2].PropertyID)
+	})
+}
+
+func TestErrInvalidPropertyValuesIsBadRequest(t *testing.T) {
+	err := NewErrInvalidPropertyValues([]PropertyValueError{{CardID: "card", PropertyID: "prop", Reason: "unknown property"}})
+	require.True(t, IsErrBadRequest(err))
+	require.Contains(t, err.Error(), "card card property prop: unknown property")
+}
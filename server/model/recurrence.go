@@ -0,0 +1,278 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RecurrenceDaily   = "DAILY"
+	RecurrenceWeekly  = "WEEKLY"
+	RecurrenceMonthly = "MONTHLY"
+
+	// maxRecurrenceSearchDays bounds the search for the next occurrence of
+	// a rule, so rules that never match (e.g. monthly on day 31 every 12
+	// months starting in a short month) cannot loop forever.
+	maxRecurrenceSearchDays = 366 * 10
+)
+
+var ErrInvalidRecurrenceRule = errors.New("invalid recurrence rule")
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// RecurrenceRule is the supported subset of the iCalendar RRULE format:
+//
+//	FREQ=DAILY[;INTERVAL=n]
+//	FREQ=WEEKLY[;INTERVAL=n][;BYDAY=MO,WE,FR]
+//	FREQ=MONTHLY[;INTERVAL=n][;BYMONTHDAY=d]
+//
+// BYHOUR and BYMINUTE set the time of the day of the occurrences,
+// midnight by default. Monthly rules skip the months without the given
+// day, as in RFC 5545.
+type RecurrenceRule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int
+	ByHour     int
+	ByMinute   int
+}
+
+// ParseRecurrenceRule parses a rule in the RRULE subset described by
+// RecurrenceRule. An optional "RRULE:" prefix is accepted.
+func ParseRecurrenceRule(rule string) (*RecurrenceRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(strings.ToUpper(rule)), "RRULE:")
+	if rule == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRecurrenceRule)
+	}
+
+	r := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRecurrenceRule, part)
+		}
+
+		var err error
+		switch key {
+		case "FREQ":
+			if value != RecurrenceDaily && value != RecurrenceWeekly && value != RecurrenceMonthly {
+				return nil, fmt.Errorf("%w: unsupported frequency %q", ErrInvalidRecurrenceRule, value)
+			}
+			r.Freq = value
+		case "INTERVAL":
+			r.Interval, err = parseRecurrenceNumber(key, value, 1, 1000)
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := recurrenceWeekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported day %q", ErrInvalidRecurrenceRule, day)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseRecurrenceNumber(key, value, 1, 31)
+		case "BYHOUR":
+			r.ByHour, err = parseRecurrenceNumber(key, value, 0, 23)
+		case "BYMINUTE":
+			r.ByMinute, err = parseRecurrenceNumber(key, value, 0, 59)
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %q", ErrInvalidRecurrenceRule, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("%w: missing frequency", ErrInvalidRecurrenceRule)
+	}
+	if len(r.ByDay) != 0 && r.Freq != RecurrenceWeekly {
+		return nil, fmt.Errorf("%w: BYDAY requires a weekly frequency", ErrInvalidRecurrenceRule)
+	}
+	if r.ByMonthDay != 0 && r.Freq != RecurrenceMonthly {
+		return nil, fmt.Errorf("%w: BYMONTHDAY requires a monthly frequency", ErrInvalidRecurrenceRule)
+	}
+	return r, nil
+}
+
+func parseRecurrenceNumber(key, value string, minValue, maxValue int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < minValue || n > maxValue {
+		return 0, fmt.Errorf("%w: %s must be between %d and %d", ErrInvalidRecurrenceRule, key, minValue, maxValue)
+	}
+	return n, nil
+}
+
+// Next returns the first occurrence of the rule strictly after `after`.
+// The occurrences are anchored to `start`, which defines the first
+// period of the intervals and the default day of weekly and monthly
+// rules, and they are computed in the location of `start`. It returns
+// false if no occurrence was found.
+func (r *RecurrenceRule) Next(start, after time.Time) (time.Time, bool) {
+	loc := start.Location()
+	after = after.In(loc)
+	startDay := civilDay(start)
+
+	day := civilDay(after)
+	if day.Before(startDay) {
+		day = startDay
+	}
+
+	for i := 0; i < maxRecurrenceSearchDays; i, day = i+1, day.AddDate(0, 0, 1) {
+		if !r.matches(startDay, day) {
+			continue
+		}
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(), r.ByHour, r.ByMinute, 0, 0, loc)
+		if occurrence.After(after) && !occurrence.Before(start) {
+			return occurrence, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *RecurrenceRule) matches(startDay, day time.Time) bool {
+	switch r.Freq {
+	case RecurrenceDaily:
+		return daysBetween(startDay, day)%r.Interval == 0
+	case RecurrenceWeekly:
+		weeks := daysBetween(weekStart(startDay), weekStart(day)) / 7
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == startDay.Weekday()
+		}
+		for _, weekday := range r.ByDay {
+			if day.Weekday() == weekday {
+				return true
+			}
+		}
+		return false
+	case RecurrenceMonthly:
+		months := (day.Year()-startDay.Year())*12 + int(day.Month()) - int(startDay.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		monthDay := r.ByMonthDay
+		if monthDay == 0 {
+			monthDay = startDay.Day()
+		}
+		return day.Day() == monthDay
+	}
+	return false
+}
+
+// civilDay returns the calendar day of t, as a UTC midnight so day
+// arithmetic is not affected by daylight saving changes.
+func civilDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// weekStart returns the Monday of the week of the civil day, weeks
+// starting on Monday as in the RRULE default.
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// CardRecurrence creates copies of a template card following a
+// recurrence rule.
+// swagger:model
+type CardRecurrence struct {
+	// The id of the template card to copy
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The id of the board of the card
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The recurrence rule, a subset of the RRULE format
+	// required: true
+	Rule string `json:"rule"`
+
+	// The id of the date property set to the occurrence on the new cards
+	// required: false
+	DatePropertyID string `json:"datePropertyId"`
+
+	// The id of the user creating the cards, whose timezone is used
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The time of the first occurrence in milliseconds since the current epoch
+	// required: false
+	StartAt int64 `json:"startAt"`
+
+	// The time of the next occurrence in milliseconds since the current epoch
+	// required: false
+	NextRunAt int64 `json:"nextRunAt"`
+
+	// The time of the last occurrence in milliseconds since the current epoch
+	// required: false
+	LastRunAt int64 `json:"lastRunAt"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: false
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in milliseconds since the current epoch
+	// required: false
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// ErrInvalidCardRecurrence is returned when a card recurrence is not valid.
+type ErrInvalidCardRecurrence struct {
+	msg string
+}
+
+func (e ErrInvalidCardRecurrence) Error() string {
+	return fmt.Sprintf("invalid card recurrence: %s", e.msg)
+}
+
+func (r *CardRecurrence) IsValid() error {
+	if r == nil {
+		return ErrInvalidCardRecurrence{"cannot be nil"}
+	}
+	if r.CardID == "" {
+		return ErrInvalidCardRecurrence{"missing card id"}
+	}
+	if r.BoardID == "" {
+		return ErrInvalidCardRecurrence{"missing board id"}
+	}
+	if r.CreatedBy == "" {
+		return ErrInvalidCardRecurrence{"missing created by"}
+	}
+	if _, err := ParseRecurrenceRule(r.Rule); err != nil {
+		return ErrInvalidCardRecurrence{err.Error()}
+	}
+	return nil
+}
+
+func CardRecurrenceFromJSON(data io.Reader) (*CardRecurrence, error) {
+	var recurrence CardRecurrence
+	if err := json.NewDecoder(data).Decode(&recurrence); err != nil {
+		return nil, err
+	}
+	return &recurrence, nil
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	t.Run("valid rules", func(t *testing.T) {
+		rule, err := ParseRecurrenceRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;BYHOUR=9;BYMINUTE=30")
+		require.NoError(t, err)
+		assert.Equal(t, &RecurrenceRule{
+			Freq:     RecurrenceWeekly,
+			Interval: 2,
+			ByDay:    []time.Weekday{time.Monday, time.Friday},
+			ByHour:   9,
+			ByMinute: 30,
+		}, rule)
+
+		rule, err = ParseRecurrenceRule("freq=monthly;bymonthday=15")
+		require.NoError(t, err)
+		assert.Equal(t, RecurrenceMonthly, rule.Freq)
+		assert.Equal(t, 1, rule.Interval)
+		assert.Equal(t, 15, rule.ByMonthDay)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		invalidRules := []string{
+			"",
+			"INTERVAL=2",
+			"FREQ=YEARLY",
+			"FREQ=DAILY;INTERVAL=0",
+			"FREQ=DAILY;BYDAY=MO",
+			"FREQ=WEEKLY;BYDAY=XX",
+			"FREQ=WEEKLY;BYMONTHDAY=1",
+			"FREQ=MONTHLY;BYMONTHDAY=32",
+			"FREQ=DAILY;BYHOUR=24",
+			"FREQ=DAILY;COUNT=3",
+			"FREQ",
+		}
+		for _, rule := range invalidRules {
+			_, err := ParseRecurrenceRule(rule)
+			assert.ErrorIs(t, err, ErrInvalidRecurrenceRule, rule)
+		}
+	})
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	date := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, loc)
+	}
+
+	testCases := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		after    time.Time
+		expected time.Time
+	}{
+		{
+			name:     "daily",
+			rule:     "FREQ=DAILY;BYHOUR=9",
+			start:    date(2024, 3, 1, 0, 0),
+			after:    date(2024, 3, 5, 9, 0),
+			expected: date(2024, 3, 6, 9, 0),
+		},
+		{
+			name:     "daily every three days",
+			rule:     "FREQ=DAILY;INTERVAL=3",
+			start:    date(2024, 3, 1, 0, 0),
+			after:    date(2024, 3, 2, 0, 0),
+			expected: date(2024, 3, 4, 0, 0),
+		},
+		{
+			name:     "first occurrence is not before start",
+			rule:     "FREQ=DAILY;BYHOUR=9",
+			start:    date(2024, 3, 1, 10, 0),
+			after:    date(2024, 2, 1, 0, 0),
+			expected: date(2024, 3, 2, 9, 0),
+		},
+		{
+			name:     "weekly on the start day",
+			rule:     "FREQ=WEEKLY",
+			start:    date(2024, 3, 6, 0, 0), // Wednesday
+			after:    date(2024, 3, 6, 0, 0),
+			expected: date(2024, 3, 13, 0, 0),
+		},
+		{
+			name:     "weekly on some days",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,FR",
+			start:    date(2024, 3, 6, 0, 0),
+			after:    date(2024, 3, 8, 12, 0),
+			expected: date(2024, 3, 11, 0, 0),
+		},
+		{
+			name:     "every other week",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			start:    date(2024, 3, 4, 0, 0),
+			after:    date(2024, 3, 4, 1, 0),
+			expected: date(2024, 3, 18, 0, 0),
+		},
+		{
+			name:     "monthly on a day",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=15;BYHOUR=8",
+			start:    date(2024, 1, 1, 0, 0),
+			after:    date(2024, 3, 15, 8, 0),
+			expected: date(2024, 4, 15, 8, 0),
+		},
+		{
+			name:     "monthly skips short months",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=31",
+			start:    date(2024, 1, 1, 0, 0),
+			after:    date(2024, 3, 31, 1, 0),
+			expected: date(2024, 5, 31, 0, 0),
+		},
+		{
+			name:     "daylight saving change keeps the local time",
+			rule:     "FREQ=DAILY;BYHOUR=9",
+			start:    date(2024, 3, 1, 0, 0),
+			after:    date(2024, 3, 30, 10, 0),
+			expected: date(2024, 3, 31, 9, 0),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tc.rule)
+			require.NoError(t, err)
+
+			next, ok := rule.Next(tc.start, tc.after)
+			require.True(t, ok)
+			assert.True(t, tc.expected.Equal(next), "expected %s, got %s", tc.expected, next)
+		})
+	}
+
+	t.Run("after in another location", func(t *testing.T) {
+		rule, err := ParseRecurrenceRule("FREQ=DAILY;BYHOUR=9")
+		require.NoError(t, err)
+
+		next, ok := rule.Next(date(2024, 3, 1, 0, 0), time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC))
+		require.True(t, ok)
+		assert.True(t, date(2024, 3, 6, 9, 0).Equal(next))
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// RetentionScope tells which policy decided the retention of a board.
+type RetentionScope string
+
+const (
+	RetentionScopeGlobal RetentionScope = "global"
+	RetentionScopeTeam   RetentionScope = "team"
+	RetentionScopeBoard  RetentionScope = "board"
+)
+
+// ErrInvalidRetentionPolicy is returned when a retention policy is not valid.
+type ErrInvalidRetentionPolicy struct {
+	msg string
+}
+
+func (e ErrInvalidRetentionPolicy) Error() string {
+	return fmt.Sprintf("invalid retention policy: %s", e.msg)
+}
+
+// RetentionPolicy is the number of days the boards of a team, or a single
+// board, are kept after their last change. A board policy takes precedence
+// over the policy of its team, which takes precedence over the global one.
+// swagger:model
+type RetentionPolicy struct {
+	// The id of the team
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The id of the board, empty for the policy of the whole team
+	// required: false
+	BoardID string `json:"boardId"`
+
+	// The number of days the boards are kept after their last change.
+	// Zero keeps them forever
+	// required: true
+	RetentionDays int `json:"retentionDays"`
+
+	// The id of the user that last changed the policy
+	// required: false
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: false
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in milliseconds since the current epoch
+	// required: false
+	UpdateAt int64 `json:"updateAt"`
+}
+
+func (p *RetentionPolicy) IsValid() error {
+	if p == nil {
+		return ErrInvalidRetentionPolicy{"cannot be nil"}
+	}
+	if p.TeamID == "" {
+		return ErrInvalidRetentionPolicy{"missing team id"}
+	}
+	if p.RetentionDays < 0 {
+		return ErrInvalidRetentionPolicy{"retention days cannot be negative"}
+	}
+	return nil
+}
+
+// Scope returns whether the policy applies to a board or to a team.
+func (p *RetentionPolicy) Scope() RetentionScope {
+	if p.BoardID != "" {
+		return RetentionScopeBoard
+	}
+	return RetentionScopeTeam
+}
+
+// RetentionDate returns the date before which the last change of a board
+// makes it expire, or zero if the policy keeps the boards forever.
+func (p *RetentionPolicy) RetentionDate(now int64) int64 {
+	if p.RetentionDays == 0 {
+		return 0
+	}
+	return now - int64(p.RetentionDays)*int64(24*time.Hour/time.Millisecond)
+}
+
+func RetentionPolicyFromJSON(data io.Reader) (*RetentionPolicy, error) {
+	var policy RetentionPolicy
+	if err := json.NewDecoder(data).Decode(&policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// RetentionCandidate is a board that expired and is deleted by the next
+// data retention run
+// swagger:model
+type RetentionCandidate struct {
+	// The id of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The id of the team of the board
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The title of the board
+	// required: true
+	Title string `json:"title"`
+
+	// The time of the last change of the board content in milliseconds
+	// since the current epoch
+	// required: true
+	LastUpdateAt int64 `json:"lastUpdateAt"`
+
+	// The policy that made the board expire: global, team or board
+	// required: true
+	Scope RetentionScope `json:"scope"`
+
+	// The date before which the last change made the board expire, in
+	// milliseconds since the current epoch
+	// required: true
+	RetentionDate int64 `json:"retentionDate"`
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// TrashItemType is the kind of a deleted item.
+type TrashItemType string
+
+const (
+	TrashItemTypeBoard TrashItemType = "board"
+	TrashItemTypeCard  TrashItemType = "card"
+)
+
+// TrashItem is a deleted board or card that can be restored or purged
+// swagger:model
+type TrashItem struct {
+	// The kind of the item, board or card
+	// required: true
+	Type TrashItemType `json:"type"`
+
+	// The id of the deleted board or card
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the board of the item
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The id of the team of the board
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The title of the item
+	// required: true
+	Title string `json:"title"`
+
+	// The icon of the item
+	// required: false
+	Icon string `json:"icon,omitempty"`
+
+	// The id of the user that deleted the item
+	// required: true
+	DeletedBy string `json:"deletedBy"`
+
+	// The deletion time in miliseconds since the current epoch
+	// required: true
+	DeletedAt int64 `json:"deletedAt"`
+}
+
+// TrashItemFromBoard returns the trash item of a deleted board.
+func TrashItemFromBoard(board *Board) *TrashItem {
+	return &TrashItem{
+		Type:      TrashItemTypeBoard,
+		ID:        board.ID,
+		BoardID:   board.ID,
+		TeamID:    board.TeamID,
+		Title:     board.Title,
+		Icon:      board.Icon,
+		DeletedBy: board.ModifiedBy,
+		DeletedAt: board.DeleteAt,
+	}
+}
+
+// TrashItemFromCard returns the trash item of a deleted card block.
+func TrashItemFromCard(block *Block, teamID string) *TrashItem {
+	icon, _ := block.Fields["icon"].(string)
+	return &TrashItem{
+		Type:      TrashItemTypeCard,
+		ID:        block.ID,
+		BoardID:   block.BoardID,
+		TeamID:    teamID,
+		Title:     block.Title,
+		Icon:      icon,
+		DeletedBy: block.ModifiedBy,
+		DeletedAt: block.DeleteAt,
+	}
+}
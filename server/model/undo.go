@@ -0,0 +1,102 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// UndoOperation is the kind of change recorded in an undo entry.
+type UndoOperation string
+
+const (
+	UndoOperationInsertBlocks UndoOperation = "insertBlocks"
+	UndoOperationPatchBlocks  UndoOperation = "patchBlocks"
+	UndoOperationDeleteBlock  UndoOperation = "deleteBlock"
+	UndoOperationPatchBoard   UndoOperation = "patchBoard"
+)
+
+// BoardChange is a board before and after a change
+// swagger:model
+type BoardChange struct {
+	// The board before the change
+	// required: true
+	Before *Board `json:"before"`
+
+	// The board after the change
+	// required: true
+	After *Board `json:"after"`
+}
+
+// UndoEntry is a change made by a user to a board, with the state
+// needed to revert it and to apply it again
+// swagger:model
+type UndoEntry struct {
+	// The id of the entry
+	// required: true
+	ID string `json:"id"`
+
+	// The id of the user that made the change
+	// required: true
+	UserID string `json:"userId"`
+
+	// The id of the board that was changed
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The kind of change
+	// required: true
+	Operation UndoOperation `json:"operation"`
+
+	// The change of the board, if the board changed
+	// required: false
+	BoardChange *BoardChange `json:"boardChange,omitempty"`
+
+	// The changes of the blocks. A nil Before means the block was
+	// inserted, a nil After means it was deleted
+	// required: true
+	BlockChanges []BlockChange `json:"blockChanges"`
+
+	// The creation time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The time the change was undone in miliseconds since the current
+	// epoch, or zero if it wasn't
+	// required: true
+	UndoneAt int64 `json:"undoneAt"`
+}
+
+// IsEmpty returns true if the entry doesn't hold any change.
+func (e *UndoEntry) IsEmpty() bool {
+	return e.BoardChange == nil && len(e.BlockChanges) == 0
+}
+
+// UndoState tells if a user can undo or redo changes on a board
+// swagger:model
+type UndoState struct {
+	// The next entry to undo, if any
+	// required: false
+	Undo *UndoEntry `json:"undo,omitempty"`
+
+	// The next entry to redo, if any
+	// required: false
+	Redo *UndoEntry `json:"redo,omitempty"`
+}
+
+// UndoResult holds the changes made by an undo or a redo
+// swagger:model
+type UndoResult struct {
+	// The entry that was undone or redone
+	// required: true
+	Entry *UndoEntry `json:"entry"`
+
+	// The board, if it changed
+	// required: false
+	Board *Board `json:"board,omitempty"`
+
+	// The inserted or updated blocks
+	// required: true
+	Blocks []*Block `json:"blocks"`
+
+	// The ids of the deleted blocks
+	// required: true
+	DeletedBlockIDs []string `json:"deletedBlockIds"`
+}
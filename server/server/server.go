@@ -25,6 +25,7 @@ import (
 	"github.com/mattermost/focalboard/server/services/notify/notifylogger"
 	"github.com/mattermost/focalboard/server/services/scheduler"
 	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/services/store/cachelayer"
 	"github.com/mattermost/focalboard/server/services/store/sqlstore"
 	"github.com/mattermost/focalboard/server/services/telemetry"
 	"github.com/mattermost/focalboard/server/services/webhook"
@@ -38,8 +39,17 @@ import (
 )
 
 const (
-	cleanupSessionTaskFrequency = 10 * time.Minute
-	updateMetricsTaskFrequency  = 15 * time.Minute
+	cleanupSessionTaskFrequency   = 10 * time.Minute
+	updateMetricsTaskFrequency    = 15 * time.Minute
+	recurringCardsTaskFrequency   = 1 * time.Minute
+	dueDateRemindersTaskFrequency = 5 * time.Minute
+	automationsTaskFrequency      = 5 * time.Minute
+	purgeTrashTaskFrequency       = 1 * time.Hour
+	dataRetentionTaskFrequency    = 1 * time.Hour
+	historyCompactionFrequency    = 1 * time.Hour
+	staleBulkOperationsFrequency  = 10 * time.Minute
+
+	databaseCopyBatchSize = 500
 
 	minSessionExpiryTime = int64(60 * 60 * 24 * 31) // 31 days
 
@@ -58,6 +68,14 @@ type Server struct {
 	metricsServer          *metrics.Service
 	metricsService         *metrics.Metrics
 	metricsUpdaterTask     *scheduler.ScheduledTask
+	recurringCardsTask     *scheduler.ScheduledTask
+	dueDateRemindersTask   *scheduler.ScheduledTask
+	automationsTask        *scheduler.ScheduledTask
+	purgeTrashTask         *scheduler.ScheduledTask
+	dataRetentionTask      *scheduler.ScheduledTask
+	backupTask             *scheduler.ScheduledTask
+	historyCompactionTask  *scheduler.ScheduledTask
+	bulkOperationsTask     *scheduler.ScheduledTask
 	auditService           *audit.Audit
 	notificationService    *notify.Service
 	servicesStartStopMutex sync.Mutex
@@ -114,6 +132,18 @@ func New(params Params) (*Server, error) {
 	}
 	metricsService := metrics.NewMetrics(instanceInfo)
 
+	if cacheLayer, ok := params.DBStore.(*cachelayer.CacheLayer); ok {
+		cacheLayer.SetMetrics(metricsService)
+		if params.ServicesAPI != nil {
+			cacheLayer.SetClusterPublisher(params.ServicesAPI)
+		}
+		// the invalidations of the other nodes are received through the
+		// cluster events of the plugin
+		if pluginAdapter, ok := wsAdapter.(ws.PluginAdapterInterface); ok {
+			pluginAdapter.SetClusterEventHandler(cachelayer.ClusterEventInvalidate, cacheLayer.HandleClusterEvent)
+		}
+	}
+
 	// Init audit
 	auditService, errAudit := audit.NewAudit()
 	if errAudit != nil {
@@ -139,10 +169,15 @@ func New(params Params) (*Server, error) {
 		Logger:           params.Logger,
 		Permissions:      params.PermissionsService,
 		ServicesAPI:      params.ServicesAPI,
+		Audit:            auditService,
 		SkipTemplateInit: utils.IsRunningUnitTests(),
 	}
 	app := app.New(params.Cfg, wsAdapter, appServices)
 
+	if params.Cfg.AuditDBEnabled {
+		auditService.AddRecordWriter(app.NewAuditRecordWriter())
+	}
+
 	focalboardAPI := api.NewAPI(app, params.SingleUserToken, params.Cfg.AuthMode, params.PermissionsService, params.Logger, auditService)
 
 	// Local router for admin APIs
@@ -209,6 +244,25 @@ func New(params Params) (*Server, error) {
 }
 
 func NewStore(config *config.Configuration, isSingleUser bool, logger mlog.LoggerIFace) (store.Store, error) {
+	var db store.Store
+	db, err := newSQLStore(config, isSingleUser, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.StoreCacheEnabled {
+		db = cachelayer.New(db, cachelayer.Params{
+			Size:   config.StoreCacheSize,
+			TTL:    time.Duration(config.StoreCacheTTLSeconds) * time.Second,
+			Logger: logger,
+		})
+	}
+	return db, nil
+}
+
+// newSQLStore connects to the database of the configuration and runs its
+// migrations.
+func newSQLStore(config *config.Configuration, isSingleUser bool, logger mlog.LoggerIFace) (*sqlstore.SQLStore, error) {
 	sqlDB, err := sql.Open(config.DBType, config.DBConfigString)
 	if err != nil {
 		logger.Error("connectDatabase failed", mlog.Err(err))
@@ -229,14 +283,31 @@ func NewStore(config *config.Configuration, isSingleUser bool, logger mlog.Logge
 		Logger:           logger,
 		DB:               sqlDB,
 		IsSingleUser:     isSingleUser,
+
+		ReplicaConnectionStrings: config.DBReplicaConfigStrings,
 	}
 
-	var db store.Store
-	db, err = sqlstore.New(storeParams)
+	return sqlstore.New(storeParams)
+}
+
+// MigrateDatabase copies the content of the database of the source
+// configuration into the database of the target configuration, after
+// running the migrations of both. Running it again after a failure
+// resumes the copy.
+func MigrateDatabase(source, target *config.Configuration, logger mlog.LoggerIFace) ([]*appModel.DatabaseCopyTable, error) {
+	sourceStore, err := newSQLStore(source, false, logger)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("cannot open the source database: %w", err)
 	}
-	return db, nil
+	defer sourceStore.Shutdown()
+
+	targetStore, err := newSQLStore(target, false, logger)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open the target database: %w", err)
+	}
+	defer targetStore.Shutdown()
+
+	return sqlstore.CopyDatabase(sourceStore, targetStore, databaseCopyBatchSize)
 }
 
 func (s *Server) Start() error {
@@ -294,6 +365,58 @@ func (s *Server) Start() error {
 	// metricsUpdater()   Calling this immediately causes integration unit tests to fail.
 	s.metricsUpdaterTask = scheduler.CreateRecurringTask("updateMetrics", metricsUpdater, updateMetricsTaskFrequency)
 
+	// the operations left running by a previous run of the server are
+	// failed at startup, then the ones of stopped servers periodically
+	s.app.FailStaleBulkOperations(time.Now())
+	s.bulkOperationsTask = scheduler.CreateRecurringTask("failStaleBulkOperations", func() {
+		s.app.FailStaleBulkOperations(time.Now())
+	}, staleBulkOperationsFrequency)
+
+	s.recurringCardsTask = scheduler.CreateRecurringTask("createRecurringCards", func() {
+		s.app.CreateDueRecurringCards(time.Now())
+	}, recurringCardsTaskFrequency)
+
+	if s.config.EnableDueDateReminders {
+		s.dueDateRemindersTask = scheduler.CreateRecurringTask("sendDueDateReminders", func() {
+			s.app.SendDueDateReminders(time.Now())
+		}, dueDateRemindersTaskFrequency)
+	}
+
+	if s.config.EnableAutomationRules {
+		s.automationsTask = scheduler.CreateRecurringTask("runDateReachedAutomations", func() {
+			s.app.RunDateReachedAutomations(time.Now())
+		}, automationsTaskFrequency)
+	}
+
+	if s.config.TrashRetentionDays > 0 {
+		s.purgeTrashTask = scheduler.CreateRecurringTask("purgeTrash", func() {
+			s.app.PurgeExpiredTrash(time.Now())
+		}, purgeTrashTaskFrequency)
+	}
+
+	// when running as a product, the global data retention is run by the
+	// Mattermost server jobs, and only the retention policies and the
+	// audit records purge are run here
+	if s.config.AuthMode != MattermostAuthMod {
+		s.dataRetentionTask = scheduler.CreateRecurringTask("runDataRetention", s.runDataRetention, dataRetentionTaskFrequency)
+	} else {
+		s.dataRetentionTask = scheduler.CreateRecurringTask("runDataRetentionPolicies", s.runDataRetentionPolicies, dataRetentionTaskFrequency)
+	}
+
+	if s.config.BackupFrequencyHours > 0 {
+		s.backupTask = scheduler.CreateRecurringTask("createBackup", func() {
+			if _, err := s.app.CreateBackup(time.Now()); err != nil {
+				s.logger.Error("Unable to create the scheduled backup", mlog.Err(err))
+			}
+		}, time.Duration(s.config.BackupFrequencyHours)*time.Hour)
+	}
+
+	if s.config.HistoryCompactionWindowSeconds > 0 {
+		s.historyCompactionTask = scheduler.CreateRecurringTask("compactHistory", func() {
+			s.app.CompactHistory(time.Now())
+		}, historyCompactionFrequency)
+	}
+
 	if s.config.Telemetry {
 		firstRun := utils.GetMillis()
 		s.telemetry.RunTelemetryJob(firstRun)
@@ -317,6 +440,21 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// runDataRetention deletes the expired boards.
+func (s *Server) runDataRetention() {
+	if _, err := s.app.RunDataRetention(time.Now()); err != nil {
+		s.logger.Error("Unable to run the data retention", mlog.Err(err))
+	}
+}
+
+// runDataRetentionPolicies deletes the boards expired according to their
+// retention policies.
+func (s *Server) runDataRetentionPolicies() {
+	if _, err := s.app.RunDataRetentionPolicies(time.Now()); err != nil {
+		s.logger.Error("Unable to run the data retention policies", mlog.Err(err))
+	}
+}
+
 func (s *Server) Shutdown() error {
 	if err := s.webServer.Shutdown(); err != nil {
 		return err
@@ -335,6 +473,38 @@ func (s *Server) Shutdown() error {
 		s.metricsUpdaterTask.Cancel()
 	}
 
+	if s.recurringCardsTask != nil {
+		s.recurringCardsTask.Cancel()
+	}
+
+	if s.dueDateRemindersTask != nil {
+		s.dueDateRemindersTask.Cancel()
+	}
+
+	if s.automationsTask != nil {
+		s.automationsTask.Cancel()
+	}
+
+	if s.purgeTrashTask != nil {
+		s.purgeTrashTask.Cancel()
+	}
+
+	if s.dataRetentionTask != nil {
+		s.dataRetentionTask.Cancel()
+	}
+
+	if s.backupTask != nil {
+		s.backupTask.Cancel()
+	}
+
+	if s.historyCompactionTask != nil {
+		s.historyCompactionTask.Cancel()
+	}
+
+	if s.bulkOperationsTask != nil {
+		s.bulkOperationsTask.Cancel()
+	}
+
 	if err := s.telemetry.Shutdown(); err != nil {
 		s.logger.Warn("Error occurred when shutting down telemetry", mlog.Err(err))
 	}
@@ -31,6 +31,7 @@ var (
 // Audit provides auditing service.
 type Audit struct {
 	auditLogger *mlog.Logger
+	writers     []*recordWriterTarget
 }
 
 // NewAudit creates a new Audit instance which can be configured via `(*Audit).Configure`.
@@ -58,9 +59,19 @@ func (a *Audit) Configure(cfgFile string, cfgEscaped string) error {
 	return a.auditLogger.Configure(cfgFile, cfgEscaped, nil)
 }
 
+// AddRecordWriter adds a target that stores the records. The records are
+// queued and written in the background; they are dropped when more than
+// DefMaxQueueSize records are waiting.
+func (a *Audit) AddRecordWriter(writer RecordWriter) {
+	a.writers = append(a.writers, newRecordWriterTarget(writer, a.auditLogger, DefMaxQueueSize))
+}
+
 // Shutdown shuts down the audit service after making best efforts to flush any
 // remaining records.
 func (a *Audit) Shutdown() error {
+	for _, writer := range a.writers {
+		writer.shutdown()
+	}
 	return a.auditLogger.Shutdown()
 }
 
@@ -81,4 +92,8 @@ func (a *Audit) LogRecord(level mlog.Level, rec *Record) {
 	}
 
 	a.auditLogger.Log(level, "audit "+rec.Event, fields...)
+
+	for _, writer := range a.writers {
+		writer.enqueue(level, rec)
+	}
 }
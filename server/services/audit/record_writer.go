@@ -0,0 +1,83 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package audit
+
+import (
+	"sync"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// RecordWriter is an audit target that stores the records, for instance
+// in a database.
+type RecordWriter interface {
+	WriteRecord(level mlog.Level, rec *Record) error
+}
+
+type queuedRecord struct {
+	level mlog.Level
+	rec   Record
+}
+
+// recordWriterTarget queues the records and writes them in the background
+// so the audited requests don't wait for them to be stored.
+type recordWriterTarget struct {
+	writer RecordWriter
+	logger *mlog.Logger
+	queue  chan queuedRecord
+	done   chan struct{}
+
+	mux    sync.RWMutex
+	closed bool
+}
+
+func newRecordWriterTarget(writer RecordWriter, logger *mlog.Logger, queueSize int) *recordWriterTarget {
+	t := &recordWriterTarget{
+		writer: writer,
+		logger: logger,
+		queue:  make(chan queuedRecord, queueSize),
+		done:   make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+func (t *recordWriterTarget) run() {
+	defer close(t.done)
+	for queued := range t.queue {
+		queued := queued
+		if err := t.writer.WriteRecord(queued.level, &queued.rec); err != nil {
+			t.logger.Error("Cannot write audit record", mlog.String(KeyEvent, queued.rec.Event), mlog.Err(err))
+		}
+	}
+}
+
+// enqueue adds a copy of a record to the queue, or drops it when the
+// queue is full.
+func (t *recordWriterTarget) enqueue(level mlog.Level, rec *Record) {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	if t.closed {
+		return
+	}
+
+	queued := queuedRecord{level: level, rec: *rec}
+	queued.rec.Meta = append([]Meta{}, rec.Meta...)
+	select {
+	case t.queue <- queued:
+	default:
+		t.logger.Warn("Audit record queue full, dropping record", mlog.String(KeyEvent, rec.Event))
+	}
+}
+
+// shutdown writes the queued records and stops the target.
+func (t *recordWriterTarget) shutdown() {
+	t.mux.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.queue)
+	}
+	t.mux.Unlock()
+	<-t.done
+}
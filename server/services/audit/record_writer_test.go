@@ -0,0 +1,57 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package audit
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+type memoryRecordWriter struct {
+	mux     sync.Mutex
+	records []Record
+	levels  []mlog.Level
+}
+
+func (w *memoryRecordWriter) WriteRecord(level mlog.Level, rec *Record) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.records = append(w.records, *rec)
+	w.levels = append(w.levels, level)
+	return nil
+}
+
+func TestAudit_AddRecordWriter(t *testing.T) {
+	audit, err := NewAudit()
+	require.NoError(t, err)
+
+	writer := &memoryRecordWriter{}
+	audit.AddRecordWriter(writer)
+
+	rec := &Record{Event: "deleteBoard", Status: Fail, UserID: "user-id"}
+	rec.AddMeta("boardID", "board-id")
+	audit.LogRecord(LevelModify, rec)
+
+	// later changes of the record are not written
+	rec.Success()
+	rec.AddMeta("extra", true)
+	audit.LogRecord(LevelRead, &Record{Event: "getBoard", Status: Success})
+
+	require.NoError(t, audit.Shutdown())
+
+	require.Len(t, writer.records, 2)
+	require.Equal(t, "deleteBoard", writer.records[0].Event)
+	require.Equal(t, Fail, writer.records[0].Status)
+	require.Equal(t, []Meta{{K: "boardID", V: "board-id"}}, writer.records[0].Meta)
+	require.Equal(t, LevelModify, writer.levels[0])
+	require.Equal(t, "getBoard", writer.records[1].Event)
+
+	// the records logged after the shutdown are dropped
+	audit.LogRecord(LevelModify, rec)
+	require.Len(t, writer.records, 2)
+}
@@ -32,8 +32,12 @@ type Configuration struct {
 	Port                     int               `json:"port" mapstructure:"port"`
 	DBType                   string            `json:"dbtype" mapstructure:"dbtype"`
 	DBConfigString           string            `json:"dbconfig" mapstructure:"dbconfig"`
+	DBReplicaConfigStrings   []string          `json:"dbreplicaconfigs" mapstructure:"dbreplicaconfigs"`
 	DBPingAttempts           int               `json:"dbpingattempts" mapstructure:"dbpingattempts"`
 	DBTablePrefix            string            `json:"dbtableprefix" mapstructure:"dbtableprefix"`
+	StoreCacheEnabled        bool              `json:"store_cache_enabled" mapstructure:"store_cache_enabled"`
+	StoreCacheSize           int               `json:"store_cache_size" mapstructure:"store_cache_size"`
+	StoreCacheTTLSeconds     int               `json:"store_cache_ttl_seconds" mapstructure:"store_cache_ttl_seconds"`
 	UseSSL                   bool              `json:"useSSL" mapstructure:"useSSL"`
 	SecureCookie             bool              `json:"secureCookie" mapstructure:"secureCookie"`
 	WebPath                  string            `json:"webpath" mapstructure:"webpath"`
@@ -67,8 +71,60 @@ type Configuration struct {
 	AuditCfgFile string `json:"audit_cfg_file" mapstructure:"audit_cfg_file"`
 	AuditCfgJSON string `json:"audit_cfg_json" mapstructure:"audit_cfg_json"`
 
+	// AuditDBEnabled stores the audit records in the database, where the
+	// system admins can query and export them.
+	AuditDBEnabled bool `json:"audit_db_enabled" mapstructure:"audit_db_enabled"`
+
+	// AuditDBRetentionDays is the number of days the audit records are
+	// kept in the database. Zero keeps them forever.
+	AuditDBRetentionDays int `json:"audit_db_retention_days" mapstructure:"audit_db_retention_days"`
+
 	NotifyFreqCardSeconds  int `json:"notify_freq_card_seconds" mapstructure:"notify_freq_card_seconds"`
 	NotifyFreqBoardSeconds int `json:"notify_freq_board_seconds" mapstructure:"notify_freq_board_seconds"`
+
+	EnableDueDateReminders   bool  `json:"enable_due_date_reminders" mapstructure:"enable_due_date_reminders"`
+	DueDateReminderLeadTimes []int `json:"due_date_reminder_lead_times" mapstructure:"due_date_reminder_lead_times"`
+
+	EnableAutomationRules bool `json:"enable_automation_rules" mapstructure:"enable_automation_rules"`
+
+	// AutomationWebhookAllowedHosts are the hosts the callWebhook actions
+	// of the automation rules can call. Empty disables the webhook actions.
+	AutomationWebhookAllowedHosts []string `json:"automation_webhook_allowed_hosts" mapstructure:"automation_webhook_allowed_hosts"`
+
+	// AutomationWebhookAllowInternal allows the webhook actions to connect
+	// to loopback, private and link-local addresses.
+	AutomationWebhookAllowInternal bool `json:"automation_webhook_allow_internal" mapstructure:"automation_webhook_allow_internal"`
+
+	// CardPropertyValidation is one of off, lenient or strict.
+	CardPropertyValidation string `json:"card_property_validation" mapstructure:"card_property_validation"`
+
+	// UndoStackSize is the number of changes each user can undo on a
+	// board. Zero disables the server side undo.
+	UndoStackSize int `json:"undo_stack_size" mapstructure:"undo_stack_size"`
+
+	// TrashRetentionDays is the number of days deleted boards and cards
+	// stay in the trash before being purged. Zero keeps them forever.
+	TrashRetentionDays int `json:"trash_retention_days" mapstructure:"trash_retention_days"`
+
+	// BackupPath is the directory where the backup archives are written.
+	BackupPath string `json:"backup_path" mapstructure:"backup_path"`
+
+	// BackupFrequencyHours is the number of hours between two scheduled
+	// backups. Zero disables the scheduled backups.
+	BackupFrequencyHours int `json:"backup_frequency_hours" mapstructure:"backup_frequency_hours"`
+
+	// BackupRetentionCount is the number of backup archives kept in the
+	// backup directory. Zero keeps them all.
+	BackupRetentionCount int `json:"backup_retention_count" mapstructure:"backup_retention_count"`
+
+	// HistoryCompactionWindowSeconds is the window within which the
+	// consecutive changes of a user to a block or a board are merged into
+	// a single history entry. Zero disables the history compaction.
+	HistoryCompactionWindowSeconds int `json:"history_compaction_window_seconds" mapstructure:"history_compaction_window_seconds"`
+
+	// HistoryCompactionMinAgeHours is the age a history entry must reach
+	// before it can be compacted.
+	HistoryCompactionMinAgeHours int `json:"history_compaction_min_age_hours" mapstructure:"history_compaction_min_age_hours"`
 }
 
 // ReadConfigFile read the configuration from the filesystem.
@@ -86,7 +142,11 @@ func ReadConfigFile(configFilePath string) (*Configuration, error) {
 	viper.SetDefault("Port", DefaultPort)
 	viper.SetDefault("DBType", "sqlite3")
 	viper.SetDefault("DBConfigString", "./focalboard.db")
+	viper.SetDefault("DBReplicaConfigStrings", nil)
 	viper.SetDefault("DBTablePrefix", "")
+	viper.SetDefault("StoreCacheEnabled", false)
+	viper.SetDefault("StoreCacheSize", 10000)
+	viper.SetDefault("StoreCacheTTLSeconds", 60)
 	viper.SetDefault("SecureCookie", false)
 	viper.SetDefault("WebPath", "./pack")
 	viper.SetDefault("FilesPath", "./files")
@@ -104,6 +164,21 @@ func ReadConfigFile(configFilePath string) (*Configuration, error) {
 	viper.SetDefault("NotifyFreqCardSeconds", 120)    // 2 minutes after last card edit
 	viper.SetDefault("NotifyFreqBoardSeconds", 86400) // 1 day after last card edit
 	viper.SetDefault("EnableDataRetention", false)
+	viper.SetDefault("AuditDBEnabled", false)
+	viper.SetDefault("AuditDBRetentionDays", 90)
+	viper.SetDefault("EnableDueDateReminders", true)
+	viper.SetDefault("DueDateReminderLeadTimes", []int{1440, 0}) // 1 day before and when due, in minutes
+	viper.SetDefault("EnableAutomationRules", true)
+	viper.SetDefault("AutomationWebhookAllowedHosts", nil)
+	viper.SetDefault("AutomationWebhookAllowInternal", false)
+	viper.SetDefault("CardPropertyValidation", "lenient")
+	viper.SetDefault("UndoStackSize", 100)
+	viper.SetDefault("TrashRetentionDays", 0)
+	viper.SetDefault("BackupPath", "./backups")
+	viper.SetDefault("BackupFrequencyHours", 0)
+	viper.SetDefault("BackupRetentionCount", 7)
+	viper.SetDefault("HistoryCompactionWindowSeconds", 0)
+	viper.SetDefault("HistoryCompactionMinAgeHours", 24)
 	viper.SetDefault("FeatureFlags", map[string]string{})
 	viper.SetDefault("DataRetentionDays", 365) // 1 year is default
 	viper.SetDefault("PrometheusAddress", "")
@@ -13,6 +13,7 @@ const (
 	MetricsSubsystemBoards = "boards"
 	MetricsSubsystemTeams  = "teams"
 	MetricsSubsystemSystem = "system"
+	MetricsSubsystemStore  = "store"
 
 	MetricsCloudInstallationLabel = "installationId"
 )
@@ -44,6 +45,11 @@ type Metrics struct {
 	teamCount  prometheus.Gauge
 
 	blockLastActivity prometheus.Gauge
+
+	storeCacheHitCount  *prometheus.CounterVec
+	storeCacheMissCount *prometheus.CounterVec
+
+	historyRowsCompactedCount *prometheus.CounterVec
 }
 
 // NewMetrics Factory method to create a new metrics collector.
@@ -172,6 +178,33 @@ func NewMetrics(info InstanceInfo) *Metrics {
 	})
 	m.registry.MustRegister(m.blockLastActivity)
 
+	m.storeCacheHitCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemStore,
+		Name:        "cache_hit_total",
+		Help:        "Total number of store cache hits.",
+		ConstLabels: additionalLabels,
+	}, []string{"Method"})
+	m.registry.MustRegister(m.storeCacheHitCount)
+
+	m.storeCacheMissCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemStore,
+		Name:        "cache_miss_total",
+		Help:        "Total number of store cache misses.",
+		ConstLabels: additionalLabels,
+	}, []string{"Method"})
+	m.registry.MustRegister(m.storeCacheMissCount)
+
+	m.historyRowsCompactedCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemStore,
+		Name:        "history_rows_compacted_total",
+		Help:        "Total number of history rows removed by the history compaction.",
+		ConstLabels: additionalLabels,
+	}, []string{"Table"})
+	m.registry.MustRegister(m.historyRowsCompactedCount)
+
 	return m
 }
 
@@ -231,3 +264,21 @@ func (m *Metrics) ObserveTeamCount(count int64) {
 		m.teamCount.Set(float64(count))
 	}
 }
+
+func (m *Metrics) IncrementStoreCacheHit(method string) {
+	if m != nil {
+		m.storeCacheHitCount.WithLabelValues(method).Inc()
+	}
+}
+
+func (m *Metrics) IncrementStoreCacheMiss(method string) {
+	if m != nil {
+		m.storeCacheMissCount.WithLabelValues(method).Inc()
+	}
+}
+
+func (m *Metrics) IncrementHistoryRowsCompacted(table string, num int64) {
+	if m != nil {
+		m.historyRowsCompactedCount.WithLabelValues(table).Add(float64(num))
+	}
+}
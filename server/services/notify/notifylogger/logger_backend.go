@@ -54,6 +54,23 @@ func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
 	return nil
 }
 
+func (b *Backend) DueDateReminder(evt notify.DueDateReminderEvent) error {
+	var board string
+	if evt.Board != nil {
+		board = evt.Board.Title
+	}
+
+	b.logger.Log(b.level, "Due date reminder event",
+		mlog.String("board", board),
+		mlog.String("card", evt.Card.Title),
+		mlog.String("property", evt.PropertyName),
+		mlog.Int("due_at", evt.DueAt),
+		mlog.Bool("overdue", evt.Overdue),
+		mlog.Int("user_count", len(evt.UserIDs)),
+	)
+	return nil
+}
+
 func (b *Backend) Name() string {
 	return backendName
 }
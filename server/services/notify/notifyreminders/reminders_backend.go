@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifyreminders
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/wiggin77/merror"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	backendName = "notifyReminders"
+)
+
+// ReminderDelivery provides an interface for delivering due date reminders.
+type ReminderDelivery interface {
+	ReminderDeliver(userID string, evt notify.DueDateReminderEvent) error
+}
+
+type BackendParams struct {
+	Delivery ReminderDelivery
+	Logger   mlog.LoggerIFace
+}
+
+// Backend provides the notification backend for due date reminders.
+type Backend struct {
+	delivery ReminderDelivery
+	logger   mlog.LoggerIFace
+}
+
+func New(params BackendParams) *Backend {
+	return &Backend{
+		delivery: params.Delivery,
+		logger:   params.Logger,
+	}
+}
+
+func (b *Backend) Start() error {
+	return nil
+}
+
+func (b *Backend) ShutDown() error {
+	_ = b.logger.Flush()
+	return nil
+}
+
+func (b *Backend) Name() string {
+	return backendName
+}
+
+// BlockChanged is a no-op, reminders are not sent on block changes.
+func (b *Backend) BlockChanged(_ notify.BlockChangeEvent) error {
+	return nil
+}
+
+// DueDateReminder delivers the reminder to each of the users of the event.
+func (b *Backend) DueDateReminder(evt notify.DueDateReminderEvent) error {
+	merr := merror.New()
+	for _, userID := range evt.UserIDs {
+		if err := b.delivery.ReminderDeliver(userID, evt); err != nil {
+			merr.Append(fmt.Errorf("cannot deliver reminder to user %s: %w", userID, err))
+			continue
+		}
+		b.logger.Debug("Due date reminder delivered",
+			mlog.String("user_id", userID),
+			mlog.String("card_id", evt.Card.ID),
+		)
+	}
+	return merr.ErrorOrNil()
+}
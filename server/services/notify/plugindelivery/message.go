@@ -7,12 +7,16 @@ import (
 	"fmt"
 
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
 )
 
 const (
 	// TODO: localize these when i18n is available.
 	defCommentTemplate     = "@%s mentioned you in a comment on the card [%s](%s) in board [%s](%s)\n> %s"
 	defDescriptionTemplate = "@%s mentioned you in the card [%s](%s) in board [%s](%s)\n> %s"
+	defUpcomingTemplate    = "The card [%s](%s) in board [%s](%s) is due on %s (%s)"
+	defOverdueTemplate     = "The card [%s](%s) in board [%s](%s) is overdue since %s (%s)"
+	reminderDateFormat     = "January 02, 2006 15:04 MST"
 )
 
 func formatMessage(author string, extract string, card string, link string, block *model.Block, boardLink string, board string) string {
@@ -22,3 +26,12 @@ func formatMessage(author string, extract string, card string, link string, bloc
 	}
 	return fmt.Sprintf(template, author, card, link, board, boardLink, extract)
 }
+
+func formatReminderMessage(card string, link string, board string, boardLink string, property string, dueAt int64, overdue bool) string {
+	template := defUpcomingTemplate
+	if overdue {
+		template = defOverdueTemplate
+	}
+	date := utils.GetTimeForMillis(dueAt).UTC().Format(reminderDateFormat)
+	return fmt.Sprintf(template, card, link, board, boardLink, date, property)
+}
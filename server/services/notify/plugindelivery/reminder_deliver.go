@@ -0,0 +1,32 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugindelivery
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+)
+
+// ReminderDeliver sends a due date reminder to a user via the plugin API.
+func (pd *PluginDelivery) ReminderDeliver(userID string, evt notify.DueDateReminderEvent) error {
+	channel, err := pd.getDirectChannel(evt.TeamID, userID, pd.botID)
+	if err != nil {
+		return fmt.Errorf("cannot get direct channel: %w", err)
+	}
+	link := utils.MakeCardLink(pd.serverRoot, evt.Board.TeamID, evt.Board.ID, evt.Card.ID)
+	boardLink := utils.MakeBoardLink(pd.serverRoot, evt.Board.TeamID, evt.Board.ID)
+
+	post := &mm_model.Post{
+		UserId:    pd.botID,
+		ChannelId: channel.Id,
+		Message:   formatReminderMessage(evt.Card.Title, link, evt.Board.Title, boardLink, evt.PropertyName, evt.DueAt, evt.Overdue),
+	}
+
+	_, err = pd.api.CreatePost(post)
+	return err
+}
@@ -30,6 +30,18 @@ type BlockChangeEvent struct {
 	ModifiedBy   *model.BoardMember
 }
 
+// DueDateReminderEvent is sent when the date of a card is upcoming or overdue.
+type DueDateReminderEvent struct {
+	TeamID       string
+	Board        *model.Board
+	Card         *model.Block
+	PropertyID   string
+	PropertyName string
+	DueAt        int64
+	Overdue      bool
+	UserIDs      []string
+}
+
 // Backend provides an interface for sending notifications.
 type Backend interface {
 	Start() error
@@ -38,6 +50,12 @@ type Backend interface {
 	Name() string
 }
 
+// ReminderBackend is implemented by the backends that can send due date
+// reminders.
+type ReminderBackend interface {
+	DueDateReminder(evt DueDateReminderEvent) error
+}
+
 // Service is a service that sends notifications based on block activity using one or more backends.
 type Service struct {
 	mux      sync.RWMutex
@@ -106,3 +124,24 @@ func (s *Service) BlockChanged(evt BlockChangeEvent) {
 		}
 	}
 }
+
+// DueDateReminder should be called when a card date is upcoming or overdue.
+// The backends able to send reminders are informed of the event.
+func (s *Service) DueDateReminder(evt DueDateReminderEvent) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	for _, backend := range s.backends {
+		reminderBackend, ok := backend.(ReminderBackend)
+		if !ok {
+			continue
+		}
+		if err := reminderBackend.DueDateReminder(evt); err != nil {
+			s.logger.Error("Error delivering due date reminder",
+				mlog.String("backend", backend.Name()),
+				mlog.String("card_id", evt.Card.ID),
+				mlog.Err(err),
+			)
+		}
+	}
+}
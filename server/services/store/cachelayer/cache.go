@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cachelayer
+
+import (
+	"container/list"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	key      string
+	value    []byte
+	expireAt time.Time
+}
+
+// lruCache is a size bounded LRU cache whose entries expire after a TTL.
+// Values are stored serialized so every read returns a fresh copy that
+// the caller is free to modify.
+type lruCache struct {
+	mut        sync.Mutex
+	size       int
+	ttl        time.Duration
+	items      map[string]*list.Element
+	evictList  *list.List
+	currentGen uint64
+	now        func() time.Time
+}
+
+func newLRUCache(size int, ttl time.Duration) *lruCache {
+	return &lruCache{
+		size:      size,
+		ttl:       ttl,
+		items:     map[string]*list.Element{},
+		evictList: list.New(),
+		now:       time.Now,
+	}
+}
+
+func cacheKey(method string, params ...string) string {
+	return method + ":" + strings.Join(params, ":")
+}
+
+// get unmarshals the cached value for key into dst and returns true if
+// a non expired entry exists.
+func (c *lruCache) get(key string, dst interface{}) bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if c.now().After(entry.expireAt) {
+		c.removeElement(elem)
+		return false
+	}
+
+	if err := json.Unmarshal(entry.value, dst); err != nil {
+		c.removeElement(elem)
+		return false
+	}
+
+	c.evictList.MoveToFront(elem)
+	return true
+}
+
+// generation returns the current cache generation. It has to be
+// retrieved before reading from the underlying store and passed to set,
+// so values read before an invalidation are never cached.
+func (c *lruCache) generation() uint64 {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.currentGen
+}
+
+func (c *lruCache) set(generation uint64, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if generation != c.currentGen {
+		return
+	}
+
+	entry := &cacheEntry{key: key, value: data, expireAt: c.now().Add(c.ttl)}
+	if elem, ok := c.items[key]; ok {
+		elem.Value = entry
+		c.evictList.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.evictList.PushFront(entry)
+	for c.evictList.Len() > c.size {
+		c.removeElement(c.evictList.Back())
+	}
+}
+
+// purge removes all the entries of the cache and starts a new
+// generation.
+func (c *lruCache) purge() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.items = map[string]*list.Element{}
+	c.evictList.Init()
+	c.currentGen++
+}
+
+func (c *lruCache) len() int {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.evictList.Len()
+}
+
+func (c *lruCache) removeElement(elem *list.Element) {
+	c.evictList.Remove(elem)
+	delete(c.items, elem.Value.(*cacheEntry).key)
+}
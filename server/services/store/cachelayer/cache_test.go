@@ -0,0 +1,53 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cachelayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	t.Run("evicts the least recently used entry", func(t *testing.T) {
+		cache := newLRUCache(2, time.Minute)
+		cache.set(cache.generation(), "a", "A")
+		cache.set(cache.generation(), "b", "B")
+
+		var value string
+		require.True(t, cache.get("a", &value))
+		cache.set(cache.generation(), "c", "C")
+
+		require.Equal(t, 2, cache.len())
+		require.True(t, cache.get("a", &value))
+		require.Equal(t, "A", value)
+		require.False(t, cache.get("b", &value))
+		require.True(t, cache.get("c", &value))
+	})
+
+	t.Run("entries expire after the TTL", func(t *testing.T) {
+		now := time.Now()
+		cache := newLRUCache(2, time.Minute)
+		cache.now = func() time.Time { return now }
+		cache.set(cache.generation(), "a", "A")
+
+		var value string
+		require.True(t, cache.get("a", &value))
+
+		now = now.Add(2 * time.Minute)
+		require.False(t, cache.get("a", &value))
+		require.Equal(t, 0, cache.len())
+	})
+
+	t.Run("values read before a purge are not stored", func(t *testing.T) {
+		cache := newLRUCache(2, time.Minute)
+		generation := cache.generation()
+		cache.purge()
+		cache.set(generation, "a", "A")
+
+		var value string
+		require.False(t, cache.get("a", &value))
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cachelayer
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/services/metrics"
+	"github.com/mattermost/focalboard/server/services/store"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// ClusterEventInvalidate is the plugin cluster event sent to the
+	// other nodes when the cache of one of them is invalidated.
+	ClusterEventInvalidate = "boards_store_cache_invalidate"
+
+	DefaultSize = 10000
+	DefaultTTL  = time.Minute
+)
+
+// clusterPublisher is the interface required by the CacheLayer to
+// notify other nodes of the cluster about invalidations. You can use
+// plugin-api or product-api adapter implementations.
+type clusterPublisher interface {
+	PublishPluginClusterEvent(ev mmModel.PluginClusterEvent, opts mmModel.PluginClusterEventSendOptions) error
+}
+
+type Params struct {
+	Size   int
+	TTL    time.Duration
+	Logger mlog.LoggerIFace
+}
+
+// CacheLayer is a read-through cache decorator for the store. The
+// results of the methods annotated with @cache in the Store interface
+// are kept in memory until they expire or any mutating method is called.
+type CacheLayer struct {
+	store     store.Store
+	cache     *lruCache
+	logger    mlog.LoggerIFace
+	metrics   *metrics.Metrics
+	publisher clusterPublisher
+}
+
+// New creates a new cache layer wrapping the given store.
+func New(s store.Store, params Params) *CacheLayer {
+	size := params.Size
+	if size <= 0 {
+		size = DefaultSize
+	}
+	ttl := params.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return &CacheLayer{
+		store:  s,
+		cache:  newLRUCache(size, ttl),
+		logger: params.Logger,
+	}
+}
+
+// SetMetrics sets the metrics service used to report cache hits and
+// misses.
+func (s *CacheLayer) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
+// SetClusterPublisher sets the publisher used to broadcast
+// invalidations to the rest of the cluster.
+func (s *CacheLayer) SetClusterPublisher(publisher clusterPublisher) {
+	s.publisher = publisher
+}
+
+// HandleClusterEvent purges the cache when another node of the cluster
+// reports an invalidation.
+func (s *CacheLayer) HandleClusterEvent(ev mmModel.PluginClusterEvent) {
+	if ev.Id != ClusterEventInvalidate {
+		return
+	}
+	s.cache.purge()
+}
+
+// Invalidate purges the cache of this node and notifies the rest of
+// the cluster.
+func (s *CacheLayer) Invalidate() {
+	s.invalidate()
+}
+
+func (s *CacheLayer) invalidate() {
+	s.cache.purge()
+
+	if s.publisher == nil {
+		return
+	}
+
+	ev := mmModel.PluginClusterEvent{Id: ClusterEventInvalidate}
+	opts := mmModel.PluginClusterEventSendOptions{SendType: mmModel.PluginClusterEventSendTypeReliable}
+	if err := s.publisher.PublishPluginClusterEvent(ev, opts); err != nil {
+		s.logger.Error("error publishing store cache invalidation", mlog.Err(err))
+	}
+}
+
+// Shutdown purges the cache and closes the underlying store.
+func (s *CacheLayer) Shutdown() error {
+	s.cache.purge()
+	return s.store.Shutdown()
+}
+
+// DBType returns the DB driver used by the underlying store.
+func (s *CacheLayer) DBType() string {
+	return s.store.DBType()
+}
+
+// DBVersion returns the version of the underlying database.
+func (s *CacheLayer) DBVersion() string {
+	return s.store.DBVersion()
+}
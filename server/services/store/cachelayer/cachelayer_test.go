@@ -0,0 +1,132 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cachelayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store/mockstore"
+	"github.com/mattermost/focalboard/server/services/store/storetests"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func TestCacheLayer(t *testing.T) {
+	t.Run("BlocksStore", func(t *testing.T) { storetests.StoreTestBlocksStore(t, SetupTests) })
+	t.Run("BoardStore", func(t *testing.T) { storetests.StoreTestBoardStore(t, SetupTests) })
+	t.Run("BoardsAndBlocksStore", func(t *testing.T) { storetests.StoreTestBoardsAndBlocksStore(t, SetupTests) })
+	t.Run("DataRetention", func(t *testing.T) { storetests.StoreTestDataRetention(t, SetupTests) })
+}
+
+type mockPublisher struct {
+	events []mmModel.PluginClusterEvent
+}
+
+func (p *mockPublisher) PublishPluginClusterEvent(ev mmModel.PluginClusterEvent, opts mmModel.PluginClusterEventSendOptions) error {
+	p.events = append(p.events, ev)
+	return nil
+}
+
+func setupMockCacheLayer(t *testing.T) (*CacheLayer, *mockstore.MockStore) {
+	ctrl := gomock.NewController(t)
+	mockStore := mockstore.NewMockStore(ctrl)
+	cacheLayer := New(mockStore, Params{Size: 10, TTL: time.Minute, Logger: mlog.CreateConsoleTestLogger(t)})
+	return cacheLayer, mockStore
+}
+
+func TestCachedMethods(t *testing.T) {
+	board := &model.Board{ID: "board-id", Title: "Board"}
+
+	t.Run("results are served from the cache after the first call", func(t *testing.T) {
+		cacheLayer, mockStore := setupMockCacheLayer(t)
+		mockStore.EXPECT().GetBoard("board-id").Return(board, nil).Times(1)
+
+		result, err := cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+		require.Equal(t, board, result)
+
+		result, err = cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+		require.Equal(t, board, result)
+	})
+
+	t.Run("cached results are copies", func(t *testing.T) {
+		cacheLayer, mockStore := setupMockCacheLayer(t)
+		mockStore.EXPECT().GetBoard("board-id").Return(board, nil).Times(1)
+
+		result, err := cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+		result.Title = "Modified"
+
+		result, err = cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+		require.Equal(t, "Board", result.Title)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		cacheLayer, mockStore := setupMockCacheLayer(t)
+		mockStore.EXPECT().GetMemberForBoard("board-id", "user-id").Return(nil, model.NewErrNotFound("member")).Times(2)
+
+		_, err := cacheLayer.GetMemberForBoard("board-id", "user-id")
+		require.True(t, model.IsErrNotFound(err))
+		_, err = cacheLayer.GetMemberForBoard("board-id", "user-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("mutating methods invalidate the cache", func(t *testing.T) {
+		cacheLayer, mockStore := setupMockCacheLayer(t)
+		publisher := &mockPublisher{}
+		cacheLayer.SetClusterPublisher(publisher)
+
+		mockStore.EXPECT().GetBoard("board-id").Return(board, nil).Times(2)
+		mockStore.EXPECT().DeleteBlock("block-id", "user-id").Return(nil)
+
+		_, err := cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+		require.NoError(t, cacheLayer.DeleteBlock("block-id", "user-id"))
+		_, err = cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+
+		require.Len(t, publisher.events, 1)
+		require.Equal(t, ClusterEventInvalidate, publisher.events[0].Id)
+	})
+
+	t.Run("read only methods don't invalidate the cache", func(t *testing.T) {
+		cacheLayer, mockStore := setupMockCacheLayer(t)
+		mockStore.EXPECT().GetBoard("board-id").Return(board, nil).Times(1)
+		mockStore.EXPECT().GetBlock("block-id").Return(&model.Block{ID: "block-id"}, nil)
+
+		_, err := cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+		_, err = cacheLayer.GetBlock("block-id")
+		require.NoError(t, err)
+		_, err = cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("cluster events invalidate the cache", func(t *testing.T) {
+		cacheLayer, mockStore := setupMockCacheLayer(t)
+		publisher := &mockPublisher{}
+		cacheLayer.SetClusterPublisher(publisher)
+		mockStore.EXPECT().GetBoard("board-id").Return(board, nil).Times(2)
+
+		_, err := cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+		cacheLayer.HandleClusterEvent(mmModel.PluginClusterEvent{Id: "other_event"})
+		_, err = cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+		cacheLayer.HandleClusterEvent(mmModel.PluginClusterEvent{Id: ClusterEventInvalidate})
+		_, err = cacheLayer.GetBoard("board-id")
+		require.NoError(t, err)
+
+		// received events must not be broadcasted back
+		require.Empty(t, publisher.events)
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cachelayer
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/services/store/sqlstore"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func SetupTests(t *testing.T) (store.Store, func()) {
+	origUnitTesting := os.Getenv("FOCALBOARD_UNIT_TESTING")
+	os.Setenv("FOCALBOARD_UNIT_TESTING", "1")
+
+	dbType, connectionString, err := sqlstore.PrepareNewTestDatabase()
+	require.NoError(t, err)
+
+	logger, _ := mlog.NewLogger()
+
+	sqlDB, err := sql.Open(dbType, connectionString)
+	require.NoError(t, err)
+	err = sqlDB.Ping()
+	require.NoError(t, err)
+
+	storeParams := sqlstore.Params{
+		DBType:           dbType,
+		ConnectionString: connectionString,
+		DBPingAttempts:   5,
+		TablePrefix:      "test_",
+		Logger:           logger,
+		DB:               sqlDB,
+	}
+	sqlStore, err := sqlstore.New(storeParams)
+	require.NoError(t, err)
+
+	cacheLayer := New(sqlStore, Params{Size: 100, TTL: time.Minute, Logger: logger})
+
+	tearDown := func() {
+		defer func() { _ = logger.Shutdown() }()
+		err = cacheLayer.Shutdown()
+		require.Nil(t, err)
+		if err = os.Remove(connectionString); err == nil {
+			logger.Debug("Removed test database", mlog.String("file", connectionString))
+		}
+		os.Setenv("FOCALBOARD_UNIT_TESTING", origUnitTesting)
+	}
+
+	return cacheLayer, tearDown
+}
@@ -0,0 +1,866 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Code generated by "make generate" from the Store interface
+// DO NOT EDIT
+
+// To cache the results of a read only method, prefix its entry in the
+// Store interface with a @cache comment before running `make
+// generate`. Any method that is not read only invalidates the cache,
+// unless it is prefixed with a @keepCache comment because it only
+// changes data the cached methods don't read.
+
+package cachelayer
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func (s *CacheLayer) AddAutomationExecution(execution *model.AutomationExecution) (bool, error) {
+	defer s.invalidate()
+	return s.store.AddAutomationExecution(execution)
+}
+
+func (s *CacheLayer) AddCardDependency(dependency *model.CardDependency) error {
+	defer s.invalidate()
+	return s.store.AddCardDependency(dependency)
+}
+
+func (s *CacheLayer) AddDueDateReminder(reminder *model.DueDateReminder) (bool, error) {
+	defer s.invalidate()
+	return s.store.AddDueDateReminder(reminder)
+}
+
+func (s *CacheLayer) AddUpdateCategoryBoard(userID string, categoryID string, boardIDs []string) error {
+	defer s.invalidate()
+	return s.store.AddUpdateCategoryBoard(userID, categoryID, boardIDs)
+}
+
+func (s *CacheLayer) ApplyUndoEntry(userID string, boardID string, redo bool, force bool) (*model.UndoResult, error) {
+	defer s.invalidate()
+	return s.store.ApplyUndoEntry(userID, boardID, redo, force)
+}
+
+func (s *CacheLayer) BackupDatabaseFile(path string) (map[string]int64, error) {
+	defer s.invalidate()
+	return s.store.BackupDatabaseFile(path)
+}
+
+func (s *CacheLayer) CanSeeUser(seerID string, seenID string) (bool, error) {
+	return s.store.CanSeeUser(seerID, seenID)
+}
+
+func (s *CacheLayer) ClaimCardRecurrence(cardID string, dueAt int64, runAt int64, nextRunAt int64) (bool, error) {
+	defer s.invalidate()
+	return s.store.ClaimCardRecurrence(cardID, dueAt, runAt, nextRunAt)
+}
+
+func (s *CacheLayer) CleanUpSessions(expireTime int64) error {
+	defer s.invalidate()
+	return s.store.CleanUpSessions(expireTime)
+}
+
+func (s *CacheLayer) CompactHistory(window int64, before int64) (map[string]int64, error) {
+	defer s.invalidate()
+	return s.store.CompactHistory(window, before)
+}
+
+func (s *CacheLayer) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
+	defer s.invalidate()
+	return s.store.CreateBoardsAndBlocks(bab, userID)
+}
+
+func (s *CacheLayer) CreateBoardsAndBlocksWithAdmin(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	defer s.invalidate()
+	return s.store.CreateBoardsAndBlocksWithAdmin(bab, userID)
+}
+
+func (s *CacheLayer) CreateCategory(category model.Category) error {
+	defer s.invalidate()
+	return s.store.CreateCategory(category)
+}
+
+func (s *CacheLayer) CreateSession(session *model.Session) error {
+	defer s.invalidate()
+	return s.store.CreateSession(session)
+}
+
+func (s *CacheLayer) CreateSubscription(sub *model.Subscription) (*model.Subscription, error) {
+	defer s.invalidate()
+	return s.store.CreateSubscription(sub)
+}
+
+func (s *CacheLayer) CreateUser(user *model.User) (*model.User, error) {
+	defer s.invalidate()
+	return s.store.CreateUser(user)
+}
+
+func (s *CacheLayer) DeleteAuditRecords(before int64) (int64, error) {
+	return s.store.DeleteAuditRecords(before)
+}
+
+func (s *CacheLayer) DeleteAutomationExecutionsBefore(createAt int64) (int64, error) {
+	defer s.invalidate()
+	return s.store.DeleteAutomationExecutionsBefore(createAt)
+}
+
+func (s *CacheLayer) DeleteAutomationRule(ruleID string) error {
+	defer s.invalidate()
+	return s.store.DeleteAutomationRule(ruleID)
+}
+
+func (s *CacheLayer) DeleteBlock(blockID string, modifiedBy string) error {
+	defer s.invalidate()
+	return s.store.DeleteBlock(blockID, modifiedBy)
+}
+
+func (s *CacheLayer) DeleteBlockRecord(blockID string, modifiedBy string) error {
+	defer s.invalidate()
+	return s.store.DeleteBlockRecord(blockID, modifiedBy)
+}
+
+func (s *CacheLayer) DeleteBoard(boardID string, userID string) error {
+	defer s.invalidate()
+	return s.store.DeleteBoard(boardID, userID)
+}
+
+func (s *CacheLayer) DeleteBoardRecord(boardID string, modifiedBy string) error {
+	defer s.invalidate()
+	return s.store.DeleteBoardRecord(boardID, modifiedBy)
+}
+
+func (s *CacheLayer) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error {
+	defer s.invalidate()
+	return s.store.DeleteBoardsAndBlocks(dbab, userID)
+}
+
+func (s *CacheLayer) DeleteCardDependency(blockerID string, blockedID string) error {
+	defer s.invalidate()
+	return s.store.DeleteCardDependency(blockerID, blockedID)
+}
+
+func (s *CacheLayer) DeleteCardRecurrence(cardID string) error {
+	defer s.invalidate()
+	return s.store.DeleteCardRecurrence(cardID)
+}
+
+func (s *CacheLayer) DeleteCategory(categoryID string, userID string, teamID string) error {
+	defer s.invalidate()
+	return s.store.DeleteCategory(categoryID, userID, teamID)
+}
+
+func (s *CacheLayer) DeleteDataRetentionBoards(boardIDs []string, batchSize int64) (int64, error) {
+	defer s.invalidate()
+	return s.store.DeleteDataRetentionBoards(boardIDs, batchSize)
+}
+
+func (s *CacheLayer) DeleteDueDateRemindersBefore(sentAt int64) (int64, error) {
+	defer s.invalidate()
+	return s.store.DeleteDueDateRemindersBefore(sentAt)
+}
+
+func (s *CacheLayer) DeleteMember(boardID string, userID string) error {
+	defer s.invalidate()
+	return s.store.DeleteMember(boardID, userID)
+}
+
+func (s *CacheLayer) DeleteNotificationHint(blockID string) error {
+	defer s.invalidate()
+	return s.store.DeleteNotificationHint(blockID)
+}
+
+func (s *CacheLayer) DeleteRetentionPolicy(teamID string, boardID string) error {
+	defer s.invalidate()
+	return s.store.DeleteRetentionPolicy(teamID, boardID)
+}
+
+func (s *CacheLayer) DeleteSession(sessionID string) error {
+	defer s.invalidate()
+	return s.store.DeleteSession(sessionID)
+}
+
+func (s *CacheLayer) DeleteSubscription(blockID string, subscriberID string) error {
+	defer s.invalidate()
+	return s.store.DeleteSubscription(blockID, subscriberID)
+}
+
+func (s *CacheLayer) DumpDatabase(w model.DatabaseDumpWriter) (map[string]int64, error) {
+	defer s.invalidate()
+	return s.store.DumpDatabase(w)
+}
+
+func (s *CacheLayer) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error) {
+	defer s.invalidate()
+	return s.store.DuplicateBlock(boardID, blockID, userID, asTemplate)
+}
+
+func (s *CacheLayer) DuplicateBoard(boardID string, userID string, toTeam string, asTemplate bool) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	defer s.invalidate()
+	return s.store.DuplicateBoard(boardID, userID, toTeam, asTemplate)
+}
+
+func (s *CacheLayer) FailStaleBulkOperations(updatedBefore int64, message string) (int64, error) {
+	return s.store.FailStaleBulkOperations(updatedBefore, message)
+}
+
+func (s *CacheLayer) GetActiveUserCount(updatedSecondsAgo int64) (int, error) {
+	return s.store.GetActiveUserCount(updatedSecondsAgo)
+}
+
+func (s *CacheLayer) GetAllTeams() ([]*model.Team, error) {
+	return s.store.GetAllTeams()
+}
+
+func (s *CacheLayer) GetArchivedPropertyValues(boardID string) ([]*model.ArchivedPropertyValue, error) {
+	return s.store.GetArchivedPropertyValues(boardID)
+}
+
+func (s *CacheLayer) GetAuditRecords(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+	return s.store.GetAuditRecords(opts)
+}
+
+func (s *CacheLayer) GetAutomationExecutions(ruleID string, limit uint64) ([]*model.AutomationExecution, error) {
+	return s.store.GetAutomationExecutions(ruleID, limit)
+}
+
+func (s *CacheLayer) GetAutomationRule(ruleID string) (*model.AutomationRule, error) {
+	return s.store.GetAutomationRule(ruleID)
+}
+
+func (s *CacheLayer) GetAutomationRulesForBoard(boardID string) ([]*model.AutomationRule, error) {
+	return s.store.GetAutomationRulesForBoard(boardID)
+}
+
+func (s *CacheLayer) GetBackupTables() ([]string, error) {
+	return s.store.GetBackupTables()
+}
+
+func (s *CacheLayer) GetBlock(blockID string) (*model.Block, error) {
+	return s.store.GetBlock(blockID)
+}
+
+func (s *CacheLayer) GetBlockCountsByType() (map[string]int64, error) {
+	return s.store.GetBlockCountsByType()
+}
+
+func (s *CacheLayer) GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	return s.store.GetBlockHistory(blockID, opts)
+}
+
+func (s *CacheLayer) GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	return s.store.GetBlockHistoryDescendants(boardID, opts)
+}
+
+func (s *CacheLayer) GetBlockHistoryNewestChildren(parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
+	return s.store.GetBlockHistoryNewestChildren(parentID, opts)
+}
+
+func (s *CacheLayer) GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error) {
+	return s.store.GetBlocks(opts)
+}
+
+func (s *CacheLayer) GetBlocksByIDs(ids []string) ([]*model.Block, error) {
+	return s.store.GetBlocksByIDs(ids)
+}
+
+func (s *CacheLayer) GetBlocksComplianceHistory(opts model.QueryBlocksComplianceHistoryOptions) ([]*model.BlockHistory, bool, error) {
+	return s.store.GetBlocksComplianceHistory(opts)
+}
+
+func (s *CacheLayer) GetBlocksForBoard(boardID string) ([]*model.Block, error) {
+	key := cacheKey("GetBlocksForBoard", boardID)
+	var result []*model.Block
+	if s.cache.get(key, &result) {
+		s.metrics.IncrementStoreCacheHit("GetBlocksForBoard")
+		return result, nil
+	}
+	s.metrics.IncrementStoreCacheMiss("GetBlocksForBoard")
+
+	generation := s.cache.generation()
+	result, err := s.store.GetBlocksForBoard(boardID)
+	if err != nil {
+		return result, err
+	}
+	s.cache.set(generation, key, result)
+	return result, nil
+}
+
+func (s *CacheLayer) GetBlocksWithParent(boardID string, parentID string) ([]*model.Block, error) {
+	return s.store.GetBlocksWithParent(boardID, parentID)
+}
+
+func (s *CacheLayer) GetBlocksWithParentAndType(boardID string, parentID string, blockType string) ([]*model.Block, error) {
+	return s.store.GetBlocksWithParentAndType(boardID, parentID, blockType)
+}
+
+func (s *CacheLayer) GetBlocksWithType(boardID string, blockType string) ([]*model.Block, error) {
+	return s.store.GetBlocksWithType(boardID, blockType)
+}
+
+func (s *CacheLayer) GetBoard(id string) (*model.Board, error) {
+	key := cacheKey("GetBoard", id)
+	var result *model.Board
+	if s.cache.get(key, &result) {
+		s.metrics.IncrementStoreCacheHit("GetBoard")
+		return result, nil
+	}
+	s.metrics.IncrementStoreCacheMiss("GetBoard")
+
+	generation := s.cache.generation()
+	result, err := s.store.GetBoard(id)
+	if err != nil {
+		return result, err
+	}
+	s.cache.set(generation, key, result)
+	return result, nil
+}
+
+func (s *CacheLayer) GetBoardAndCard(block *model.Block) (*model.Board, *model.Block, error) {
+	return s.store.GetBoardAndCard(block)
+}
+
+func (s *CacheLayer) GetBoardAndCardByID(blockID string) (*model.Board, *model.Block, error) {
+	return s.store.GetBoardAndCardByID(blockID)
+}
+
+func (s *CacheLayer) GetBoardCount() (int64, error) {
+	return s.store.GetBoardCount()
+}
+
+func (s *CacheLayer) GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error) {
+	return s.store.GetBoardHistory(boardID, opts)
+}
+
+func (s *CacheLayer) GetBoardMemberHistory(boardID string, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error) {
+	return s.store.GetBoardMemberHistory(boardID, userID, limit)
+}
+
+func (s *CacheLayer) GetBoardSnapshot(boardID string, at int64) (*model.BoardSnapshot, error) {
+	return s.store.GetBoardSnapshot(boardID, at)
+}
+
+func (s *CacheLayer) GetBoardVersions(boardID string, before int64, limit uint64) ([]*model.BoardVersion, error) {
+	return s.store.GetBoardVersions(boardID, before, limit)
+}
+
+func (s *CacheLayer) GetBoardsComplianceHistory(opts model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error) {
+	return s.store.GetBoardsComplianceHistory(opts)
+}
+
+func (s *CacheLayer) GetBoardsForCompliance(opts model.QueryBoardsForComplianceOptions) ([]*model.Board, bool, error) {
+	return s.store.GetBoardsForCompliance(opts)
+}
+
+func (s *CacheLayer) GetBoardsForUserAndTeam(userID string, teamID string, includePublicBoards bool) ([]*model.Board, error) {
+	return s.store.GetBoardsForUserAndTeam(userID, teamID, includePublicBoards)
+}
+
+func (s *CacheLayer) GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error) {
+	return s.store.GetBoardsInTeamByIds(boardIDs, teamID)
+}
+
+func (s *CacheLayer) GetBoardsWithPropertyType(propertyType string) ([]*model.Board, error) {
+	return s.store.GetBoardsWithPropertyType(propertyType)
+}
+
+func (s *CacheLayer) GetBulkOperation(operationID string) (*model.BulkOperation, error) {
+	return s.store.GetBulkOperation(operationID)
+}
+
+func (s *CacheLayer) GetCardDependencies(cardID string) ([]*model.CardDependency, error) {
+	return s.store.GetCardDependencies(cardID)
+}
+
+func (s *CacheLayer) GetCardDependenciesForBoard(boardID string) ([]*model.CardDependency, error) {
+	return s.store.GetCardDependenciesForBoard(boardID)
+}
+
+func (s *CacheLayer) GetCardLimitTimestamp() (int64, error) {
+	return s.store.GetCardLimitTimestamp()
+}
+
+func (s *CacheLayer) GetCardRecurrence(cardID string) (*model.CardRecurrence, error) {
+	return s.store.GetCardRecurrence(cardID)
+}
+
+func (s *CacheLayer) GetCardsReferencingCard(cardID string, boardIDs []string) ([]*model.Block, error) {
+	return s.store.GetCardsReferencingCard(cardID, boardIDs)
+}
+
+func (s *CacheLayer) GetCardsWithDatesBetween(boardID string, propertyIDs []string, from int64, to int64) ([]*model.Block, error) {
+	return s.store.GetCardsWithDatesBetween(boardID, propertyIDs, from, to)
+}
+
+func (s *CacheLayer) GetCategory(id string) (*model.Category, error) {
+	return s.store.GetCategory(id)
+}
+
+func (s *CacheLayer) GetChannel(teamID string, channelID string) (*mmModel.Channel, error) {
+	return s.store.GetChannel(teamID, channelID)
+}
+
+func (s *CacheLayer) GetDataRetentionCandidates(globalRetentionDate int64, now int64) ([]*model.RetentionCandidate, error) {
+	return s.store.GetDataRetentionCandidates(globalRetentionDate, now)
+}
+
+func (s *CacheLayer) GetDeletedBlocks(boardIDs []string, blockType model.BlockType) ([]*model.Block, error) {
+	return s.store.GetDeletedBlocks(boardIDs, blockType)
+}
+
+func (s *CacheLayer) GetDeletedBoards(teamID string) ([]*model.Board, error) {
+	return s.store.GetDeletedBoards(teamID)
+}
+
+func (s *CacheLayer) GetDueCardRecurrences(now int64) ([]*model.CardRecurrence, error) {
+	return s.store.GetDueCardRecurrences(now)
+}
+
+func (s *CacheLayer) GetEnabledAutomationRulesWithTrigger(triggerType model.AutomationTriggerType) ([]*model.AutomationRule, error) {
+	return s.store.GetEnabledAutomationRulesWithTrigger(triggerType)
+}
+
+func (s *CacheLayer) GetFileInfo(id string) (*mmModel.FileInfo, error) {
+	return s.store.GetFileInfo(id)
+}
+
+func (s *CacheLayer) GetLicense() *mmModel.License {
+	return s.store.GetLicense()
+}
+
+func (s *CacheLayer) GetMemberForBoard(boardID string, userID string) (*model.BoardMember, error) {
+	key := cacheKey("GetMemberForBoard", boardID, userID)
+	var result *model.BoardMember
+	if s.cache.get(key, &result) {
+		s.metrics.IncrementStoreCacheHit("GetMemberForBoard")
+		return result, nil
+	}
+	s.metrics.IncrementStoreCacheMiss("GetMemberForBoard")
+
+	generation := s.cache.generation()
+	result, err := s.store.GetMemberForBoard(boardID, userID)
+	if err != nil {
+		return result, err
+	}
+	s.cache.set(generation, key, result)
+	return result, nil
+}
+
+func (s *CacheLayer) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
+	key := cacheKey("GetMembersForBoard", boardID)
+	var result []*model.BoardMember
+	if s.cache.get(key, &result) {
+		s.metrics.IncrementStoreCacheHit("GetMembersForBoard")
+		return result, nil
+	}
+	s.metrics.IncrementStoreCacheMiss("GetMembersForBoard")
+
+	generation := s.cache.generation()
+	result, err := s.store.GetMembersForBoard(boardID)
+	if err != nil {
+		return result, err
+	}
+	s.cache.set(generation, key, result)
+	return result, nil
+}
+
+func (s *CacheLayer) GetMembersForUser(userID string) ([]*model.BoardMember, error) {
+	return s.store.GetMembersForUser(userID)
+}
+
+func (s *CacheLayer) GetNextNotificationHint(remove bool) (*model.NotificationHint, error) {
+	return s.store.GetNextNotificationHint(remove)
+}
+
+func (s *CacheLayer) GetNotificationHint(blockID string) (*model.NotificationHint, error) {
+	return s.store.GetNotificationHint(blockID)
+}
+
+func (s *CacheLayer) GetRegisteredUserCount() (int, error) {
+	return s.store.GetRegisteredUserCount()
+}
+
+func (s *CacheLayer) GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, error) {
+	return s.store.GetRetentionPolicies(teamID)
+}
+
+func (s *CacheLayer) GetSchemaVersion() (int, error) {
+	return s.store.GetSchemaVersion()
+}
+
+func (s *CacheLayer) GetSession(token string, expireTime int64) (*model.Session, error) {
+	return s.store.GetSession(token, expireTime)
+}
+
+func (s *CacheLayer) GetSharing(rootID string) (*model.Sharing, error) {
+	return s.store.GetSharing(rootID)
+}
+
+func (s *CacheLayer) GetSubTree2(boardID string, blockID string, opts model.QuerySubtreeOptions) ([]*model.Block, error) {
+	return s.store.GetSubTree2(boardID, blockID, opts)
+}
+
+func (s *CacheLayer) GetSubscribersCountForBlock(blockID string) (int, error) {
+	return s.store.GetSubscribersCountForBlock(blockID)
+}
+
+func (s *CacheLayer) GetSubscribersForBlock(blockID string) ([]*model.Subscriber, error) {
+	return s.store.GetSubscribersForBlock(blockID)
+}
+
+func (s *CacheLayer) GetSubscription(blockID string, subscriberID string) (*model.Subscription, error) {
+	return s.store.GetSubscription(blockID, subscriberID)
+}
+
+func (s *CacheLayer) GetSubscriptions(subscriberID string) ([]*model.Subscription, error) {
+	return s.store.GetSubscriptions(subscriberID)
+}
+
+func (s *CacheLayer) GetSystemSetting(key string) (string, error) {
+	return s.store.GetSystemSetting(key)
+}
+
+func (s *CacheLayer) GetSystemSettings() (map[string]string, error) {
+	return s.store.GetSystemSettings()
+}
+
+func (s *CacheLayer) GetTeam(ID string) (*model.Team, error) {
+	return s.store.GetTeam(ID)
+}
+
+func (s *CacheLayer) GetTeamCount() (int64, error) {
+	return s.store.GetTeamCount()
+}
+
+func (s *CacheLayer) GetTeamsForUser(userID string) ([]*model.Team, error) {
+	return s.store.GetTeamsForUser(userID)
+}
+
+func (s *CacheLayer) GetTemplateBoards(teamID string, userID string) ([]*model.Board, error) {
+	return s.store.GetTemplateBoards(teamID, userID)
+}
+
+func (s *CacheLayer) GetUndoState(userID string, boardID string) (*model.UndoState, error) {
+	return s.store.GetUndoState(userID, boardID)
+}
+
+func (s *CacheLayer) GetUsedCardsCount() (int, error) {
+	return s.store.GetUsedCardsCount()
+}
+
+func (s *CacheLayer) GetUserByEmail(email string) (*model.User, error) {
+	return s.store.GetUserByEmail(email)
+}
+
+func (s *CacheLayer) GetUserByID(userID string) (*model.User, error) {
+	return s.store.GetUserByID(userID)
+}
+
+func (s *CacheLayer) GetUserByUsername(username string) (*model.User, error) {
+	return s.store.GetUserByUsername(username)
+}
+
+func (s *CacheLayer) GetUserCategories(userID string, teamID string) ([]model.Category, error) {
+	return s.store.GetUserCategories(userID, teamID)
+}
+
+func (s *CacheLayer) GetUserCategoryBoards(userID string, teamID string) ([]model.CategoryBoards, error) {
+	return s.store.GetUserCategoryBoards(userID, teamID)
+}
+
+func (s *CacheLayer) GetUserPreferences(userID string) (mmModel.Preferences, error) {
+	return s.store.GetUserPreferences(userID)
+}
+
+func (s *CacheLayer) GetUserTimezone(userID string) (string, error) {
+	return s.store.GetUserTimezone(userID)
+}
+
+func (s *CacheLayer) GetUsersByTeam(teamID string, asGuestID string, showEmail bool, showName bool) ([]*model.User, error) {
+	return s.store.GetUsersByTeam(teamID, asGuestID, showEmail, showName)
+}
+
+func (s *CacheLayer) GetUsersList(userIDs []string, showEmail bool, showName bool) ([]*model.User, error) {
+	return s.store.GetUsersList(userIDs, showEmail, showName)
+}
+
+func (s *CacheLayer) ImportCSVBlocks(imp *model.CSVImportBlocks, userID string) (*model.Board, error) {
+	defer s.invalidate()
+	return s.store.ImportCSVBlocks(imp, userID)
+}
+
+func (s *CacheLayer) InsertAuditRecord(record *model.AuditRecord) error {
+	return s.store.InsertAuditRecord(record)
+}
+
+func (s *CacheLayer) InsertAutomationRule(rule *model.AutomationRule) error {
+	defer s.invalidate()
+	return s.store.InsertAutomationRule(rule)
+}
+
+func (s *CacheLayer) InsertBlock(block *model.Block, userID string) error {
+	defer s.invalidate()
+	return s.store.InsertBlock(block, userID)
+}
+
+func (s *CacheLayer) InsertBlocks(blocks []*model.Block, userID string) error {
+	defer s.invalidate()
+	return s.store.InsertBlocks(blocks, userID)
+}
+
+func (s *CacheLayer) InsertBoard(board *model.Board, userID string) (*model.Board, error) {
+	defer s.invalidate()
+	return s.store.InsertBoard(board, userID)
+}
+
+func (s *CacheLayer) InsertBoardWithAdmin(board *model.Board, userID string) (*model.Board, *model.BoardMember, error) {
+	defer s.invalidate()
+	return s.store.InsertBoardWithAdmin(board, userID)
+}
+
+func (s *CacheLayer) InsertBulkOperation(operation *model.BulkOperation) error {
+	return s.store.InsertBulkOperation(operation)
+}
+
+func (s *CacheLayer) InsertUndoEntry(entry *model.UndoEntry, maxEntries int) error {
+	defer s.invalidate()
+	return s.store.InsertUndoEntry(entry, maxEntries)
+}
+
+func (s *CacheLayer) MoveCardBlocks(move *model.CardMoveBlocks, userID string) (*model.Board, error) {
+	defer s.invalidate()
+	return s.store.MoveCardBlocks(move, userID)
+}
+
+func (s *CacheLayer) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
+	defer s.invalidate()
+	return s.store.PatchBlock(blockID, blockPatch, userID)
+}
+
+func (s *CacheLayer) PatchBlocks(blockPatches *model.BlockPatchBatch, userID string) error {
+	defer s.invalidate()
+	return s.store.PatchBlocks(blockPatches, userID)
+}
+
+func (s *CacheLayer) PatchBoard(boardID string, boardPatch *model.BoardPatch, userID string) (*model.Board, error) {
+	defer s.invalidate()
+	return s.store.PatchBoard(boardID, boardPatch, userID)
+}
+
+func (s *CacheLayer) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
+	defer s.invalidate()
+	return s.store.PatchBoardsAndBlocks(pbab, userID)
+}
+
+func (s *CacheLayer) PatchUserPreferences(userID string, patch model.UserPreferencesPatch) (mmModel.Preferences, error) {
+	defer s.invalidate()
+	return s.store.PatchUserPreferences(userID, patch)
+}
+
+func (s *CacheLayer) PostMessage(message string, postType string, channelID string) error {
+	defer s.invalidate()
+	return s.store.PostMessage(message, postType, channelID)
+}
+
+func (s *CacheLayer) PurgeBlock(blockID string) error {
+	defer s.invalidate()
+	return s.store.PurgeBlock(blockID)
+}
+
+func (s *CacheLayer) PurgeBoard(boardID string) error {
+	defer s.invalidate()
+	return s.store.PurgeBoard(boardID)
+}
+
+func (s *CacheLayer) PurgeTrash(deletedBefore int64) (int64, error) {
+	defer s.invalidate()
+	return s.store.PurgeTrash(deletedBefore)
+}
+
+func (s *CacheLayer) RefreshSession(session *model.Session) error {
+	defer s.invalidate()
+	return s.store.RefreshSession(session)
+}
+
+func (s *CacheLayer) RemoveDefaultTemplates(boards []*model.Board) error {
+	defer s.invalidate()
+	return s.store.RemoveDefaultTemplates(boards)
+}
+
+func (s *CacheLayer) ReorderCategories(userID string, teamID string, newCategoryOrder []string) ([]string, error) {
+	defer s.invalidate()
+	return s.store.ReorderCategories(userID, teamID, newCategoryOrder)
+}
+
+func (s *CacheLayer) ReorderCategoryBoards(categoryID string, newBoardsOrder []string) ([]string, error) {
+	defer s.invalidate()
+	return s.store.ReorderCategoryBoards(categoryID, newBoardsOrder)
+}
+
+func (s *CacheLayer) RestoreArchivedPropertyValues(boardID string, propertyID string, userID string) ([]*model.Block, error) {
+	defer s.invalidate()
+	return s.store.RestoreArchivedPropertyValues(boardID, propertyID, userID)
+}
+
+func (s *CacheLayer) RestoreBoardSnapshot(boardID string, at int64, cardIDs []string, userID string) (*model.BoardSnapshotRestore, error) {
+	defer s.invalidate()
+	return s.store.RestoreBoardSnapshot(boardID, at, cardIDs, userID)
+}
+
+func (s *CacheLayer) RestoreDatabase(dump model.DatabaseDumpReader) (map[string]int64, error) {
+	defer s.invalidate()
+	return s.store.RestoreDatabase(dump)
+}
+
+func (s *CacheLayer) RestoreDatabaseFile(path string) (map[string]int64, error) {
+	defer s.invalidate()
+	return s.store.RestoreDatabaseFile(path)
+}
+
+func (s *CacheLayer) RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error) {
+	defer s.invalidate()
+	return s.store.RunDataRetention(globalRetentionDate, batchSize)
+}
+
+func (s *CacheLayer) SaveFileInfo(fileInfo *mmModel.FileInfo) error {
+	defer s.invalidate()
+	return s.store.SaveFileInfo(fileInfo)
+}
+
+func (s *CacheLayer) SaveMember(bm *model.BoardMember) (*model.BoardMember, error) {
+	defer s.invalidate()
+	return s.store.SaveMember(bm)
+}
+
+func (s *CacheLayer) SearchBoardsForUser(term string, searchField model.BoardSearchField, userID string, includePublicBoards bool) ([]*model.Board, error) {
+	return s.store.SearchBoardsForUser(term, searchField, userID, includePublicBoards)
+}
+
+func (s *CacheLayer) SearchBoardsForUserInTeam(teamID string, term string, userID string) ([]*model.Board, error) {
+	return s.store.SearchBoardsForUserInTeam(teamID, term, userID)
+}
+
+func (s *CacheLayer) SearchUserChannels(teamID string, userID string, query string) ([]*mmModel.Channel, error) {
+	return s.store.SearchUserChannels(teamID, userID, query)
+}
+
+func (s *CacheLayer) SearchUsersByTeam(teamID string, searchQuery string, asGuestID string, excludeBots bool, showEmail bool, showName bool) ([]*model.User, error) {
+	return s.store.SearchUsersByTeam(teamID, searchQuery, asGuestID, excludeBots, showEmail, showName)
+}
+
+func (s *CacheLayer) SendMessage(message string, postType string, receipts []string) error {
+	defer s.invalidate()
+	return s.store.SendMessage(message, postType, receipts)
+}
+
+func (s *CacheLayer) SetBlockArchiveAt(blockID string, archiveAt int64, userID string) (*model.Block, error) {
+	defer s.invalidate()
+	return s.store.SetBlockArchiveAt(blockID, archiveAt, userID)
+}
+
+func (s *CacheLayer) SetBoardArchiveAt(boardID string, archiveAt int64, userID string) (*model.Board, error) {
+	defer s.invalidate()
+	return s.store.SetBoardArchiveAt(boardID, archiveAt, userID)
+}
+
+func (s *CacheLayer) SetBoardVisibility(userID string, categoryID string, boardID string, visible bool) error {
+	defer s.invalidate()
+	return s.store.SetBoardVisibility(userID, categoryID, boardID, visible)
+}
+
+func (s *CacheLayer) SetSystemSetting(key string, value string) error {
+	defer s.invalidate()
+	return s.store.SetSystemSetting(key, value)
+}
+
+func (s *CacheLayer) UndeleteBlock(blockID string, modifiedBy string) error {
+	defer s.invalidate()
+	return s.store.UndeleteBlock(blockID, modifiedBy)
+}
+
+func (s *CacheLayer) UndeleteBoard(boardID string, modifiedBy string) error {
+	defer s.invalidate()
+	return s.store.UndeleteBoard(boardID, modifiedBy)
+}
+
+func (s *CacheLayer) UpdateAutomationExecution(execution *model.AutomationExecution) error {
+	defer s.invalidate()
+	return s.store.UpdateAutomationExecution(execution)
+}
+
+func (s *CacheLayer) UpdateAutomationRule(rule *model.AutomationRule) error {
+	defer s.invalidate()
+	return s.store.UpdateAutomationRule(rule)
+}
+
+func (s *CacheLayer) UpdateBulkOperation(operation *model.BulkOperation) error {
+	return s.store.UpdateBulkOperation(operation)
+}
+
+func (s *CacheLayer) UpdateCardLimitTimestamp(cardLimit int) (int64, error) {
+	defer s.invalidate()
+	return s.store.UpdateCardLimitTimestamp(cardLimit)
+}
+
+func (s *CacheLayer) UpdateCategory(category model.Category) error {
+	defer s.invalidate()
+	return s.store.UpdateCategory(category)
+}
+
+func (s *CacheLayer) UpdateSession(session *model.Session) error {
+	defer s.invalidate()
+	return s.store.UpdateSession(session)
+}
+
+func (s *CacheLayer) UpdateSubscribersNotifiedAt(blockID string, notifiedAt int64) error {
+	defer s.invalidate()
+	return s.store.UpdateSubscribersNotifiedAt(blockID, notifiedAt)
+}
+
+func (s *CacheLayer) UpdateUser(user *model.User) (*model.User, error) {
+	defer s.invalidate()
+	return s.store.UpdateUser(user)
+}
+
+func (s *CacheLayer) UpdateUserPassword(username string, password string) error {
+	defer s.invalidate()
+	return s.store.UpdateUserPassword(username, password)
+}
+
+func (s *CacheLayer) UpdateUserPasswordByID(userID string, password string) error {
+	defer s.invalidate()
+	return s.store.UpdateUserPasswordByID(userID, password)
+}
+
+func (s *CacheLayer) UpsertCardRecurrence(recurrence *model.CardRecurrence) error {
+	defer s.invalidate()
+	return s.store.UpsertCardRecurrence(recurrence)
+}
+
+func (s *CacheLayer) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
+	defer s.invalidate()
+	return s.store.UpsertNotificationHint(hint, notificationFreq)
+}
+
+func (s *CacheLayer) UpsertRetentionPolicy(policy *model.RetentionPolicy) error {
+	defer s.invalidate()
+	return s.store.UpsertRetentionPolicy(policy)
+}
+
+func (s *CacheLayer) UpsertSharing(sharing model.Sharing) error {
+	defer s.invalidate()
+	return s.store.UpsertSharing(sharing)
+}
+
+func (s *CacheLayer) UpsertTeamSettings(team model.Team) error {
+	defer s.invalidate()
+	return s.store.UpsertTeamSettings(team)
+}
+
+func (s *CacheLayer) UpsertTeamSignupToken(team model.Team) error {
+	defer s.invalidate()
+	return s.store.UpsertTeamSignupToken(team)
+}
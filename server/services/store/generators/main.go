@@ -20,6 +20,9 @@ import (
 
 const (
 	WithTransactionComment = "@withTransaction"
+	CacheComment           = "@cache"
+	ReadFromPrimaryComment = "@readFromPrimary"
+	KeepCacheComment       = "@keepCache"
 	ErrorType              = "error"
 	StringType             = "string"
 	IntType                = "int"
@@ -44,10 +47,27 @@ func isBool(typeName string) bool {
 	return typeName == BoolType
 }
 
+// readOnlyMethodPrefixes lists the prefixes of the Store methods that
+// don't modify the database. Any other method invalidates the cache
+// layer when called.
+var readOnlyMethodPrefixes = []string{"Get", "Search", "Can"}
+
+func isReadOnly(methodName string) bool {
+	for _, prefix := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(methodName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func main() {
 	if err := buildTransactionalStore(); err != nil {
 		log.Fatal(err)
 	}
+	if err := buildCacheLayer(); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func buildTransactionalStore() error {
@@ -63,6 +83,19 @@ func buildTransactionalStore() error {
 	return os.WriteFile(path.Join("sqlstore/public_methods.go"), formatedCode, 0644) //nolint:gosec
 }
 
+func buildCacheLayer() error {
+	code, err := generateLayer("CacheLayer", "cache_layer.go.tmpl")
+	if err != nil {
+		return err
+	}
+	formatedCode, err := format.Source(code)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join("cachelayer/public_methods.go"), formatedCode, 0644) //nolint:gosec
+}
+
 type methodParam struct {
 	Name string
 	Type string
@@ -72,6 +105,10 @@ type methodData struct {
 	Params          []methodParam
 	Results         []string
 	WithTransaction bool
+	Cache           bool
+	ReadOnly        bool
+	ReadFromPrimary bool
+	KeepCache       bool
 }
 
 type storeMetadata struct {
@@ -89,6 +126,9 @@ func extractMethodMetadata(method *ast.Field, src []byte) methodData {
 	params := []methodParam{}
 	results := []string{}
 	withTransaction := false
+	cache := false
+	readFromPrimary := false
+	keepCache := false
 	ast.Inspect(method.Type, func(expr ast.Node) bool {
 		//nolint:gocritic
 		switch e := expr.(type) {
@@ -97,7 +137,15 @@ func extractMethodMetadata(method *ast.Field, src []byte) methodData {
 				for _, comment := range method.Doc.List {
 					if strings.Contains(comment.Text, WithTransactionComment) {
 						withTransaction = true
-						break
+					}
+					if strings.Contains(comment.Text, CacheComment) {
+						cache = true
+					}
+					if strings.Contains(comment.Text, ReadFromPrimaryComment) {
+						readFromPrimary = true
+					}
+					if strings.Contains(comment.Text, KeepCacheComment) {
+						keepCache = true
 					}
 				}
 			}
@@ -116,7 +164,33 @@ func extractMethodMetadata(method *ast.Field, src []byte) methodData {
 		}
 		return true
 	})
-	return methodData{Params: params, Results: results, WithTransaction: withTransaction}
+	return methodData{
+		Params:          params,
+		Results:         results,
+		WithTransaction: withTransaction,
+		Cache:           cache,
+		ReadOnly:        isReadOnly(method.Names[0].Name),
+		ReadFromPrimary: readFromPrimary,
+		KeepCache:       keepCache,
+	}
+}
+
+// checkCacheableMethod verifies that a method annotated with @cache
+// can be handled by the cache layer: it has to be read only, receive
+// only string parameters and return a value and an error.
+func checkCacheableMethod(methodName string, data methodData) error {
+	if !data.ReadOnly {
+		return fmt.Errorf("method %s cannot be cached as it is not read only", methodName)
+	}
+	for _, param := range data.Params {
+		if !isString(param.Type) {
+			return fmt.Errorf("method %s cannot be cached as param %s is not a string", methodName, param.Name)
+		}
+	}
+	if len(data.Results) != 2 || !isError(data.Results[1]) {
+		return fmt.Errorf("method %s cannot be cached as it must return a value and an error", methodName)
+	}
+	return nil
 }
 
 func extractStoreMetadata() (*storeMetadata, error) {
@@ -139,6 +213,7 @@ func extractStoreMetadata() (*storeMetadata, error) {
 
 	metadata := storeMetadata{Methods: map[string]methodData{}}
 
+	var inspectErr error
 	ast.Inspect(f, func(n ast.Node) bool {
 		//nolint:gocritic
 		switch x := n.(type) {
@@ -150,12 +225,22 @@ func extractStoreMetadata() (*storeMetadata, error) {
 						continue
 					}
 
-					metadata.Methods[methodName] = extractMethodMetadata(method, src)
+					data := extractMethodMetadata(method, src)
+					if data.Cache {
+						if err := checkCacheableMethod(methodName, data); err != nil {
+							inspectErr = err
+							return false
+						}
+					}
+					metadata.Methods[methodName] = data
 				}
 			}
 		}
 		return true
 	})
+	if inspectErr != nil {
+		return nil, inspectErr
+	}
 
 	return &metadata, nil
 }
@@ -36,6 +36,50 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddAutomationExecution mocks base method.
+func (m *MockStore) AddAutomationExecution(arg0 *model.AutomationExecution) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAutomationExecution", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAutomationExecution indicates an expected call of AddAutomationExecution.
+func (mr *MockStoreMockRecorder) AddAutomationExecution(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAutomationExecution", reflect.TypeOf((*MockStore)(nil).AddAutomationExecution), arg0)
+}
+
+// AddCardDependency mocks base method.
+func (m *MockStore) AddCardDependency(arg0 *model.CardDependency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCardDependency", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCardDependency indicates an expected call of AddCardDependency.
+func (mr *MockStoreMockRecorder) AddCardDependency(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCardDependency", reflect.TypeOf((*MockStore)(nil).AddCardDependency), arg0)
+}
+
+// AddDueDateReminder mocks base method.
+func (m *MockStore) AddDueDateReminder(arg0 *model.DueDateReminder) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDueDateReminder", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDueDateReminder indicates an expected call of AddDueDateReminder.
+func (mr *MockStoreMockRecorder) AddDueDateReminder(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDueDateReminder", reflect.TypeOf((*MockStore)(nil).AddDueDateReminder), arg0)
+}
+
 // AddUpdateCategoryBoard mocks base method.
 func (m *MockStore) AddUpdateCategoryBoard(arg0, arg1 string, arg2 []string) error {
 	m.ctrl.T.Helper()
@@ -50,6 +94,36 @@ func (mr *MockStoreMockRecorder) AddUpdateCategoryBoard(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUpdateCategoryBoard", reflect.TypeOf((*MockStore)(nil).AddUpdateCategoryBoard), arg0, arg1, arg2)
 }
 
+// ApplyUndoEntry mocks base method.
+func (m *MockStore) ApplyUndoEntry(arg0, arg1 string, arg2, arg3 bool) (*model.UndoResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyUndoEntry", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.UndoResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyUndoEntry indicates an expected call of ApplyUndoEntry.
+func (mr *MockStoreMockRecorder) ApplyUndoEntry(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyUndoEntry", reflect.TypeOf((*MockStore)(nil).ApplyUndoEntry), arg0, arg1, arg2, arg3)
+}
+
+// BackupDatabaseFile mocks base method.
+func (m *MockStore) BackupDatabaseFile(arg0 string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackupDatabaseFile", arg0)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackupDatabaseFile indicates an expected call of BackupDatabaseFile.
+func (mr *MockStoreMockRecorder) BackupDatabaseFile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackupDatabaseFile", reflect.TypeOf((*MockStore)(nil).BackupDatabaseFile), arg0)
+}
+
 // CanSeeUser mocks base method.
 func (m *MockStore) CanSeeUser(arg0, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +139,21 @@ func (mr *MockStoreMockRecorder) CanSeeUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSeeUser", reflect.TypeOf((*MockStore)(nil).CanSeeUser), arg0, arg1)
 }
 
+// ClaimCardRecurrence mocks base method.
+func (m *MockStore) ClaimCardRecurrence(arg0 string, arg1, arg2, arg3 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimCardRecurrence", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimCardRecurrence indicates an expected call of ClaimCardRecurrence.
+func (mr *MockStoreMockRecorder) ClaimCardRecurrence(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimCardRecurrence", reflect.TypeOf((*MockStore)(nil).ClaimCardRecurrence), arg0, arg1, arg2, arg3)
+}
+
 // CleanUpSessions mocks base method.
 func (m *MockStore) CleanUpSessions(arg0 int64) error {
 	m.ctrl.T.Helper()
@@ -79,6 +168,21 @@ func (mr *MockStoreMockRecorder) CleanUpSessions(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanUpSessions", reflect.TypeOf((*MockStore)(nil).CleanUpSessions), arg0)
 }
 
+// CompactHistory mocks base method.
+func (m *MockStore) CompactHistory(arg0, arg1 int64) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactHistory", arg0, arg1)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompactHistory indicates an expected call of CompactHistory.
+func (mr *MockStoreMockRecorder) CompactHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactHistory", reflect.TypeOf((*MockStore)(nil).CompactHistory), arg0, arg1)
+}
+
 // CreateBoardsAndBlocks mocks base method.
 func (m *MockStore) CreateBoardsAndBlocks(arg0 *model.BoardsAndBlocks, arg1 string) (*model.BoardsAndBlocks, error) {
 	m.ctrl.T.Helper()
@@ -196,6 +300,50 @@ func (mr *MockStoreMockRecorder) DBVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DBVersion", reflect.TypeOf((*MockStore)(nil).DBVersion))
 }
 
+// DeleteAuditRecords mocks base method.
+func (m *MockStore) DeleteAuditRecords(arg0 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuditRecords", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAuditRecords indicates an expected call of DeleteAuditRecords.
+func (mr *MockStoreMockRecorder) DeleteAuditRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuditRecords", reflect.TypeOf((*MockStore)(nil).DeleteAuditRecords), arg0)
+}
+
+// DeleteAutomationExecutionsBefore mocks base method.
+func (m *MockStore) DeleteAutomationExecutionsBefore(arg0 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAutomationExecutionsBefore", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAutomationExecutionsBefore indicates an expected call of DeleteAutomationExecutionsBefore.
+func (mr *MockStoreMockRecorder) DeleteAutomationExecutionsBefore(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAutomationExecutionsBefore", reflect.TypeOf((*MockStore)(nil).DeleteAutomationExecutionsBefore), arg0)
+}
+
+// DeleteAutomationRule mocks base method.
+func (m *MockStore) DeleteAutomationRule(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAutomationRule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAutomationRule indicates an expected call of DeleteAutomationRule.
+func (mr *MockStoreMockRecorder) DeleteAutomationRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAutomationRule", reflect.TypeOf((*MockStore)(nil).DeleteAutomationRule), arg0)
+}
+
 // DeleteBlock mocks base method.
 func (m *MockStore) DeleteBlock(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -266,6 +414,34 @@ func (mr *MockStoreMockRecorder) DeleteBoardsAndBlocks(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBoardsAndBlocks", reflect.TypeOf((*MockStore)(nil).DeleteBoardsAndBlocks), arg0, arg1)
 }
 
+// DeleteCardDependency mocks base method.
+func (m *MockStore) DeleteCardDependency(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCardDependency", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCardDependency indicates an expected call of DeleteCardDependency.
+func (mr *MockStoreMockRecorder) DeleteCardDependency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCardDependency", reflect.TypeOf((*MockStore)(nil).DeleteCardDependency), arg0, arg1)
+}
+
+// DeleteCardRecurrence mocks base method.
+func (m *MockStore) DeleteCardRecurrence(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCardRecurrence", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCardRecurrence indicates an expected call of DeleteCardRecurrence.
+func (mr *MockStoreMockRecorder) DeleteCardRecurrence(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCardRecurrence", reflect.TypeOf((*MockStore)(nil).DeleteCardRecurrence), arg0)
+}
+
 // DeleteCategory mocks base method.
 func (m *MockStore) DeleteCategory(arg0, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -280,6 +456,36 @@ func (mr *MockStoreMockRecorder) DeleteCategory(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockStore)(nil).DeleteCategory), arg0, arg1, arg2)
 }
 
+// DeleteDataRetentionBoards mocks base method.
+func (m *MockStore) DeleteDataRetentionBoards(arg0 []string, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDataRetentionBoards", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDataRetentionBoards indicates an expected call of DeleteDataRetentionBoards.
+func (mr *MockStoreMockRecorder) DeleteDataRetentionBoards(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataRetentionBoards", reflect.TypeOf((*MockStore)(nil).DeleteDataRetentionBoards), arg0, arg1)
+}
+
+// DeleteDueDateRemindersBefore mocks base method.
+func (m *MockStore) DeleteDueDateRemindersBefore(arg0 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDueDateRemindersBefore", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDueDateRemindersBefore indicates an expected call of DeleteDueDateRemindersBefore.
+func (mr *MockStoreMockRecorder) DeleteDueDateRemindersBefore(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDueDateRemindersBefore", reflect.TypeOf((*MockStore)(nil).DeleteDueDateRemindersBefore), arg0)
+}
+
 // DeleteMember mocks base method.
 func (m *MockStore) DeleteMember(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -308,6 +514,20 @@ func (mr *MockStoreMockRecorder) DeleteNotificationHint(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationHint", reflect.TypeOf((*MockStore)(nil).DeleteNotificationHint), arg0)
 }
 
+// DeleteRetentionPolicy mocks base method.
+func (m *MockStore) DeleteRetentionPolicy(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRetentionPolicy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRetentionPolicy indicates an expected call of DeleteRetentionPolicy.
+func (mr *MockStoreMockRecorder) DeleteRetentionPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRetentionPolicy", reflect.TypeOf((*MockStore)(nil).DeleteRetentionPolicy), arg0, arg1)
+}
+
 // DeleteSession mocks base method.
 func (m *MockStore) DeleteSession(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -336,6 +556,21 @@ func (mr *MockStoreMockRecorder) DeleteSubscription(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockStore)(nil).DeleteSubscription), arg0, arg1)
 }
 
+// DumpDatabase mocks base method.
+func (m *MockStore) DumpDatabase(arg0 model.DatabaseDumpWriter) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DumpDatabase", arg0)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DumpDatabase indicates an expected call of DumpDatabase.
+func (mr *MockStoreMockRecorder) DumpDatabase(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DumpDatabase", reflect.TypeOf((*MockStore)(nil).DumpDatabase), arg0)
+}
+
 // DuplicateBlock mocks base method.
 func (m *MockStore) DuplicateBlock(arg0, arg1, arg2 string, arg3 bool) ([]*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -367,6 +602,21 @@ func (mr *MockStoreMockRecorder) DuplicateBoard(arg0, arg1, arg2, arg3 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DuplicateBoard", reflect.TypeOf((*MockStore)(nil).DuplicateBoard), arg0, arg1, arg2, arg3)
 }
 
+// FailStaleBulkOperations mocks base method.
+func (m *MockStore) FailStaleBulkOperations(arg0 int64, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStaleBulkOperations", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStaleBulkOperations indicates an expected call of FailStaleBulkOperations.
+func (mr *MockStoreMockRecorder) FailStaleBulkOperations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStaleBulkOperations", reflect.TypeOf((*MockStore)(nil).FailStaleBulkOperations), arg0, arg1)
+}
+
 // GetActiveUserCount mocks base method.
 func (m *MockStore) GetActiveUserCount(arg0 int64) (int, error) {
 	m.ctrl.T.Helper()
@@ -397,6 +647,97 @@ func (mr *MockStoreMockRecorder) GetAllTeams() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTeams", reflect.TypeOf((*MockStore)(nil).GetAllTeams))
 }
 
+// GetArchivedPropertyValues mocks base method.
+func (m *MockStore) GetArchivedPropertyValues(arg0 string) ([]*model.ArchivedPropertyValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivedPropertyValues", arg0)
+	ret0, _ := ret[0].([]*model.ArchivedPropertyValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivedPropertyValues indicates an expected call of GetArchivedPropertyValues.
+func (mr *MockStoreMockRecorder) GetArchivedPropertyValues(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivedPropertyValues", reflect.TypeOf((*MockStore)(nil).GetArchivedPropertyValues), arg0)
+}
+
+// GetAuditRecords mocks base method.
+func (m *MockStore) GetAuditRecords(arg0 model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditRecords", arg0)
+	ret0, _ := ret[0].([]*model.AuditRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuditRecords indicates an expected call of GetAuditRecords.
+func (mr *MockStoreMockRecorder) GetAuditRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditRecords", reflect.TypeOf((*MockStore)(nil).GetAuditRecords), arg0)
+}
+
+// GetAutomationExecutions mocks base method.
+func (m *MockStore) GetAutomationExecutions(arg0 string, arg1 uint64) ([]*model.AutomationExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutomationExecutions", arg0, arg1)
+	ret0, _ := ret[0].([]*model.AutomationExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutomationExecutions indicates an expected call of GetAutomationExecutions.
+func (mr *MockStoreMockRecorder) GetAutomationExecutions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutomationExecutions", reflect.TypeOf((*MockStore)(nil).GetAutomationExecutions), arg0, arg1)
+}
+
+// GetAutomationRule mocks base method.
+func (m *MockStore) GetAutomationRule(arg0 string) (*model.AutomationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutomationRule", arg0)
+	ret0, _ := ret[0].(*model.AutomationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutomationRule indicates an expected call of GetAutomationRule.
+func (mr *MockStoreMockRecorder) GetAutomationRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutomationRule", reflect.TypeOf((*MockStore)(nil).GetAutomationRule), arg0)
+}
+
+// GetAutomationRulesForBoard mocks base method.
+func (m *MockStore) GetAutomationRulesForBoard(arg0 string) ([]*model.AutomationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutomationRulesForBoard", arg0)
+	ret0, _ := ret[0].([]*model.AutomationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutomationRulesForBoard indicates an expected call of GetAutomationRulesForBoard.
+func (mr *MockStoreMockRecorder) GetAutomationRulesForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutomationRulesForBoard", reflect.TypeOf((*MockStore)(nil).GetAutomationRulesForBoard), arg0)
+}
+
+// GetBackupTables mocks base method.
+func (m *MockStore) GetBackupTables() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBackupTables")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBackupTables indicates an expected call of GetBackupTables.
+func (mr *MockStoreMockRecorder) GetBackupTables() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBackupTables", reflect.TypeOf((*MockStore)(nil).GetBackupTables))
+}
+
 // GetBlock mocks base method.
 func (m *MockStore) GetBlock(arg0 string) (*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -671,6 +1012,36 @@ func (mr *MockStoreMockRecorder) GetBoardMemberHistory(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardMemberHistory", reflect.TypeOf((*MockStore)(nil).GetBoardMemberHistory), arg0, arg1, arg2)
 }
 
+// GetBoardSnapshot mocks base method.
+func (m *MockStore) GetBoardSnapshot(arg0 string, arg1 int64) (*model.BoardSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(*model.BoardSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardSnapshot indicates an expected call of GetBoardSnapshot.
+func (mr *MockStoreMockRecorder) GetBoardSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardSnapshot", reflect.TypeOf((*MockStore)(nil).GetBoardSnapshot), arg0, arg1)
+}
+
+// GetBoardVersions mocks base method.
+func (m *MockStore) GetBoardVersions(arg0 string, arg1 int64, arg2 uint64) ([]*model.BoardVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardVersions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.BoardVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardVersions indicates an expected call of GetBoardVersions.
+func (mr *MockStoreMockRecorder) GetBoardVersions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardVersions", reflect.TypeOf((*MockStore)(nil).GetBoardVersions), arg0, arg1, arg2)
+}
+
 // GetBoardsComplianceHistory mocks base method.
 func (m *MockStore) GetBoardsComplianceHistory(arg0 model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error) {
 	m.ctrl.T.Helper()
@@ -733,6 +1104,66 @@ func (mr *MockStoreMockRecorder) GetBoardsInTeamByIds(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsInTeamByIds", reflect.TypeOf((*MockStore)(nil).GetBoardsInTeamByIds), arg0, arg1)
 }
 
+// GetBoardsWithPropertyType mocks base method.
+func (m *MockStore) GetBoardsWithPropertyType(arg0 string) ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardsWithPropertyType", arg0)
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardsWithPropertyType indicates an expected call of GetBoardsWithPropertyType.
+func (mr *MockStoreMockRecorder) GetBoardsWithPropertyType(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsWithPropertyType", reflect.TypeOf((*MockStore)(nil).GetBoardsWithPropertyType), arg0)
+}
+
+// GetBulkOperation mocks base method.
+func (m *MockStore) GetBulkOperation(arg0 string) (*model.BulkOperation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBulkOperation", arg0)
+	ret0, _ := ret[0].(*model.BulkOperation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBulkOperation indicates an expected call of GetBulkOperation.
+func (mr *MockStoreMockRecorder) GetBulkOperation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkOperation", reflect.TypeOf((*MockStore)(nil).GetBulkOperation), arg0)
+}
+
+// GetCardDependencies mocks base method.
+func (m *MockStore) GetCardDependencies(arg0 string) ([]*model.CardDependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardDependencies", arg0)
+	ret0, _ := ret[0].([]*model.CardDependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardDependencies indicates an expected call of GetCardDependencies.
+func (mr *MockStoreMockRecorder) GetCardDependencies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardDependencies", reflect.TypeOf((*MockStore)(nil).GetCardDependencies), arg0)
+}
+
+// GetCardDependenciesForBoard mocks base method.
+func (m *MockStore) GetCardDependenciesForBoard(arg0 string) ([]*model.CardDependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardDependenciesForBoard", arg0)
+	ret0, _ := ret[0].([]*model.CardDependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardDependenciesForBoard indicates an expected call of GetCardDependenciesForBoard.
+func (mr *MockStoreMockRecorder) GetCardDependenciesForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardDependenciesForBoard", reflect.TypeOf((*MockStore)(nil).GetCardDependenciesForBoard), arg0)
+}
+
 // GetCardLimitTimestamp mocks base method.
 func (m *MockStore) GetCardLimitTimestamp() (int64, error) {
 	m.ctrl.T.Helper()
@@ -748,6 +1179,51 @@ func (mr *MockStoreMockRecorder) GetCardLimitTimestamp() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardLimitTimestamp", reflect.TypeOf((*MockStore)(nil).GetCardLimitTimestamp))
 }
 
+// GetCardRecurrence mocks base method.
+func (m *MockStore) GetCardRecurrence(arg0 string) (*model.CardRecurrence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardRecurrence", arg0)
+	ret0, _ := ret[0].(*model.CardRecurrence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardRecurrence indicates an expected call of GetCardRecurrence.
+func (mr *MockStoreMockRecorder) GetCardRecurrence(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardRecurrence", reflect.TypeOf((*MockStore)(nil).GetCardRecurrence), arg0)
+}
+
+// GetCardsReferencingCard mocks base method.
+func (m *MockStore) GetCardsReferencingCard(arg0 string, arg1 []string) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardsReferencingCard", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardsReferencingCard indicates an expected call of GetCardsReferencingCard.
+func (mr *MockStoreMockRecorder) GetCardsReferencingCard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardsReferencingCard", reflect.TypeOf((*MockStore)(nil).GetCardsReferencingCard), arg0, arg1)
+}
+
+// GetCardsWithDatesBetween mocks base method.
+func (m *MockStore) GetCardsWithDatesBetween(arg0 string, arg1 []string, arg2, arg3 int64) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardsWithDatesBetween", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardsWithDatesBetween indicates an expected call of GetCardsWithDatesBetween.
+func (mr *MockStoreMockRecorder) GetCardsWithDatesBetween(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardsWithDatesBetween", reflect.TypeOf((*MockStore)(nil).GetCardsWithDatesBetween), arg0, arg1, arg2, arg3)
+}
+
 // GetCategory mocks base method.
 func (m *MockStore) GetCategory(arg0 string) (*model.Category, error) {
 	m.ctrl.T.Helper()
@@ -778,6 +1254,81 @@ func (mr *MockStoreMockRecorder) GetChannel(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannel", reflect.TypeOf((*MockStore)(nil).GetChannel), arg0, arg1)
 }
 
+// GetDataRetentionCandidates mocks base method.
+func (m *MockStore) GetDataRetentionCandidates(arg0, arg1 int64) ([]*model.RetentionCandidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataRetentionCandidates", arg0, arg1)
+	ret0, _ := ret[0].([]*model.RetentionCandidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataRetentionCandidates indicates an expected call of GetDataRetentionCandidates.
+func (mr *MockStoreMockRecorder) GetDataRetentionCandidates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataRetentionCandidates", reflect.TypeOf((*MockStore)(nil).GetDataRetentionCandidates), arg0, arg1)
+}
+
+// GetDeletedBlocks mocks base method.
+func (m *MockStore) GetDeletedBlocks(arg0 []string, arg1 model.BlockType) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBlocks", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBlocks indicates an expected call of GetDeletedBlocks.
+func (mr *MockStoreMockRecorder) GetDeletedBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBlocks", reflect.TypeOf((*MockStore)(nil).GetDeletedBlocks), arg0, arg1)
+}
+
+// GetDeletedBoards mocks base method.
+func (m *MockStore) GetDeletedBoards(arg0 string) ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBoards", arg0)
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBoards indicates an expected call of GetDeletedBoards.
+func (mr *MockStoreMockRecorder) GetDeletedBoards(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBoards", reflect.TypeOf((*MockStore)(nil).GetDeletedBoards), arg0)
+}
+
+// GetDueCardRecurrences mocks base method.
+func (m *MockStore) GetDueCardRecurrences(arg0 int64) ([]*model.CardRecurrence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueCardRecurrences", arg0)
+	ret0, _ := ret[0].([]*model.CardRecurrence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueCardRecurrences indicates an expected call of GetDueCardRecurrences.
+func (mr *MockStoreMockRecorder) GetDueCardRecurrences(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueCardRecurrences", reflect.TypeOf((*MockStore)(nil).GetDueCardRecurrences), arg0)
+}
+
+// GetEnabledAutomationRulesWithTrigger mocks base method.
+func (m *MockStore) GetEnabledAutomationRulesWithTrigger(arg0 model.AutomationTriggerType) ([]*model.AutomationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnabledAutomationRulesWithTrigger", arg0)
+	ret0, _ := ret[0].([]*model.AutomationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnabledAutomationRulesWithTrigger indicates an expected call of GetEnabledAutomationRulesWithTrigger.
+func (mr *MockStoreMockRecorder) GetEnabledAutomationRulesWithTrigger(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnabledAutomationRulesWithTrigger", reflect.TypeOf((*MockStore)(nil).GetEnabledAutomationRulesWithTrigger), arg0)
+}
+
 // GetFileInfo mocks base method.
 func (m *MockStore) GetFileInfo(arg0 string) (*model0.FileInfo, error) {
 	m.ctrl.T.Helper()
@@ -897,6 +1448,36 @@ func (mr *MockStoreMockRecorder) GetRegisteredUserCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredUserCount", reflect.TypeOf((*MockStore)(nil).GetRegisteredUserCount))
 }
 
+// GetRetentionPolicies mocks base method.
+func (m *MockStore) GetRetentionPolicies(arg0 string) ([]*model.RetentionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRetentionPolicies", arg0)
+	ret0, _ := ret[0].([]*model.RetentionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRetentionPolicies indicates an expected call of GetRetentionPolicies.
+func (mr *MockStoreMockRecorder) GetRetentionPolicies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRetentionPolicies", reflect.TypeOf((*MockStore)(nil).GetRetentionPolicies), arg0)
+}
+
+// GetSchemaVersion mocks base method.
+func (m *MockStore) GetSchemaVersion() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchemaVersion")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchemaVersion indicates an expected call of GetSchemaVersion.
+func (mr *MockStoreMockRecorder) GetSchemaVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchemaVersion", reflect.TypeOf((*MockStore)(nil).GetSchemaVersion))
+}
+
 // GetSession mocks base method.
 func (m *MockStore) GetSession(arg0 string, arg1 int64) (*model.Session, error) {
 	m.ctrl.T.Helper()
@@ -1092,6 +1673,21 @@ func (mr *MockStoreMockRecorder) GetTemplateBoards(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBoards", reflect.TypeOf((*MockStore)(nil).GetTemplateBoards), arg0, arg1)
 }
 
+// GetUndoState mocks base method.
+func (m *MockStore) GetUndoState(arg0, arg1 string) (*model.UndoState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUndoState", arg0, arg1)
+	ret0, _ := ret[0].(*model.UndoState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUndoState indicates an expected call of GetUndoState.
+func (mr *MockStoreMockRecorder) GetUndoState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUndoState", reflect.TypeOf((*MockStore)(nil).GetUndoState), arg0, arg1)
+}
+
 // GetUsedCardsCount mocks base method.
 func (m *MockStore) GetUsedCardsCount() (int, error) {
 	m.ctrl.T.Helper()
@@ -1242,6 +1838,49 @@ func (mr *MockStoreMockRecorder) GetUsersList(arg0, arg1, arg2 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersList", reflect.TypeOf((*MockStore)(nil).GetUsersList), arg0, arg1, arg2)
 }
 
+// ImportCSVBlocks mocks base method.
+func (m *MockStore) ImportCSVBlocks(arg0 *model.CSVImportBlocks, arg1 string) (*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCSVBlocks", arg0, arg1)
+	ret0, _ := ret[0].(*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCSVBlocks indicates an expected call of ImportCSVBlocks.
+func (mr *MockStoreMockRecorder) ImportCSVBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCSVBlocks", reflect.TypeOf((*MockStore)(nil).ImportCSVBlocks), arg0, arg1)
+}
+
+// InsertAuditRecord mocks base method.
+func (m *MockStore) InsertAuditRecord(arg0 *model.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditRecord", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAuditRecord indicates an expected call of InsertAuditRecord.
+func (mr *MockStoreMockRecorder) InsertAuditRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditRecord", reflect.TypeOf((*MockStore)(nil).InsertAuditRecord), arg0)
+}
+
+// InsertAutomationRule mocks base method.
+func (m *MockStore) InsertAutomationRule(arg0 *model.AutomationRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAutomationRule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAutomationRule indicates an expected call of InsertAutomationRule.
+func (mr *MockStoreMockRecorder) InsertAutomationRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAutomationRule", reflect.TypeOf((*MockStore)(nil).InsertAutomationRule), arg0)
+}
+
 // InsertBlock mocks base method.
 func (m *MockStore) InsertBlock(arg0 *model.Block, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -1301,6 +1940,49 @@ func (mr *MockStoreMockRecorder) InsertBoardWithAdmin(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBoardWithAdmin", reflect.TypeOf((*MockStore)(nil).InsertBoardWithAdmin), arg0, arg1)
 }
 
+// InsertBulkOperation mocks base method.
+func (m *MockStore) InsertBulkOperation(arg0 *model.BulkOperation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBulkOperation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertBulkOperation indicates an expected call of InsertBulkOperation.
+func (mr *MockStoreMockRecorder) InsertBulkOperation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBulkOperation", reflect.TypeOf((*MockStore)(nil).InsertBulkOperation), arg0)
+}
+
+// InsertUndoEntry mocks base method.
+func (m *MockStore) InsertUndoEntry(arg0 *model.UndoEntry, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUndoEntry", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUndoEntry indicates an expected call of InsertUndoEntry.
+func (mr *MockStoreMockRecorder) InsertUndoEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUndoEntry", reflect.TypeOf((*MockStore)(nil).InsertUndoEntry), arg0, arg1)
+}
+
+// MoveCardBlocks mocks base method.
+func (m *MockStore) MoveCardBlocks(arg0 *model.CardMoveBlocks, arg1 string) (*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveCardBlocks", arg0, arg1)
+	ret0, _ := ret[0].(*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveCardBlocks indicates an expected call of MoveCardBlocks.
+func (mr *MockStoreMockRecorder) MoveCardBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveCardBlocks", reflect.TypeOf((*MockStore)(nil).MoveCardBlocks), arg0, arg1)
+}
+
 // PatchBlock mocks base method.
 func (m *MockStore) PatchBlock(arg0 string, arg1 *model.BlockPatch, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -1388,6 +2070,49 @@ func (mr *MockStoreMockRecorder) PostMessage(arg0, arg1, arg2 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockStore)(nil).PostMessage), arg0, arg1, arg2)
 }
 
+// PurgeBlock mocks base method.
+func (m *MockStore) PurgeBlock(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeBlock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeBlock indicates an expected call of PurgeBlock.
+func (mr *MockStoreMockRecorder) PurgeBlock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBlock", reflect.TypeOf((*MockStore)(nil).PurgeBlock), arg0)
+}
+
+// PurgeBoard mocks base method.
+func (m *MockStore) PurgeBoard(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeBoard", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeBoard indicates an expected call of PurgeBoard.
+func (mr *MockStoreMockRecorder) PurgeBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBoard", reflect.TypeOf((*MockStore)(nil).PurgeBoard), arg0)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockStore) PurgeTrash(arg0 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockStoreMockRecorder) PurgeTrash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockStore)(nil).PurgeTrash), arg0)
+}
+
 // RefreshSession mocks base method.
 func (m *MockStore) RefreshSession(arg0 *model.Session) error {
 	m.ctrl.T.Helper()
@@ -1446,6 +2171,66 @@ func (mr *MockStoreMockRecorder) ReorderCategoryBoards(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCategoryBoards", reflect.TypeOf((*MockStore)(nil).ReorderCategoryBoards), arg0, arg1)
 }
 
+// RestoreArchivedPropertyValues mocks base method.
+func (m *MockStore) RestoreArchivedPropertyValues(arg0, arg1, arg2 string) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreArchivedPropertyValues", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreArchivedPropertyValues indicates an expected call of RestoreArchivedPropertyValues.
+func (mr *MockStoreMockRecorder) RestoreArchivedPropertyValues(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreArchivedPropertyValues", reflect.TypeOf((*MockStore)(nil).RestoreArchivedPropertyValues), arg0, arg1, arg2)
+}
+
+// RestoreBoardSnapshot mocks base method.
+func (m *MockStore) RestoreBoardSnapshot(arg0 string, arg1 int64, arg2 []string, arg3 string) (*model.BoardSnapshotRestore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBoardSnapshot", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.BoardSnapshotRestore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBoardSnapshot indicates an expected call of RestoreBoardSnapshot.
+func (mr *MockStoreMockRecorder) RestoreBoardSnapshot(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBoardSnapshot", reflect.TypeOf((*MockStore)(nil).RestoreBoardSnapshot), arg0, arg1, arg2, arg3)
+}
+
+// RestoreDatabase mocks base method.
+func (m *MockStore) RestoreDatabase(arg0 model.DatabaseDumpReader) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreDatabase", arg0)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreDatabase indicates an expected call of RestoreDatabase.
+func (mr *MockStoreMockRecorder) RestoreDatabase(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDatabase", reflect.TypeOf((*MockStore)(nil).RestoreDatabase), arg0)
+}
+
+// RestoreDatabaseFile mocks base method.
+func (m *MockStore) RestoreDatabaseFile(arg0 string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreDatabaseFile", arg0)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreDatabaseFile indicates an expected call of RestoreDatabaseFile.
+func (mr *MockStoreMockRecorder) RestoreDatabaseFile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDatabaseFile", reflect.TypeOf((*MockStore)(nil).RestoreDatabaseFile), arg0)
+}
+
 // RunDataRetention mocks base method.
 func (m *MockStore) RunDataRetention(arg0, arg1 int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1564,6 +2349,36 @@ func (mr *MockStoreMockRecorder) SendMessage(arg0, arg1, arg2 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockStore)(nil).SendMessage), arg0, arg1, arg2)
 }
 
+// SetBlockArchiveAt mocks base method.
+func (m *MockStore) SetBlockArchiveAt(arg0 string, arg1 int64, arg2 string) (*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBlockArchiveAt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBlockArchiveAt indicates an expected call of SetBlockArchiveAt.
+func (mr *MockStoreMockRecorder) SetBlockArchiveAt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBlockArchiveAt", reflect.TypeOf((*MockStore)(nil).SetBlockArchiveAt), arg0, arg1, arg2)
+}
+
+// SetBoardArchiveAt mocks base method.
+func (m *MockStore) SetBoardArchiveAt(arg0 string, arg1 int64, arg2 string) (*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBoardArchiveAt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBoardArchiveAt indicates an expected call of SetBoardArchiveAt.
+func (mr *MockStoreMockRecorder) SetBoardArchiveAt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBoardArchiveAt", reflect.TypeOf((*MockStore)(nil).SetBoardArchiveAt), arg0, arg1, arg2)
+}
+
 // SetBoardVisibility mocks base method.
 func (m *MockStore) SetBoardVisibility(arg0, arg1, arg2 string, arg3 bool) error {
 	m.ctrl.T.Helper()
@@ -1634,6 +2449,48 @@ func (mr *MockStoreMockRecorder) UndeleteBoard(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteBoard", reflect.TypeOf((*MockStore)(nil).UndeleteBoard), arg0, arg1)
 }
 
+// UpdateAutomationExecution mocks base method.
+func (m *MockStore) UpdateAutomationExecution(arg0 *model.AutomationExecution) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAutomationExecution", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAutomationExecution indicates an expected call of UpdateAutomationExecution.
+func (mr *MockStoreMockRecorder) UpdateAutomationExecution(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAutomationExecution", reflect.TypeOf((*MockStore)(nil).UpdateAutomationExecution), arg0)
+}
+
+// UpdateAutomationRule mocks base method.
+func (m *MockStore) UpdateAutomationRule(arg0 *model.AutomationRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAutomationRule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAutomationRule indicates an expected call of UpdateAutomationRule.
+func (mr *MockStoreMockRecorder) UpdateAutomationRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAutomationRule", reflect.TypeOf((*MockStore)(nil).UpdateAutomationRule), arg0)
+}
+
+// UpdateBulkOperation mocks base method.
+func (m *MockStore) UpdateBulkOperation(arg0 *model.BulkOperation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBulkOperation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBulkOperation indicates an expected call of UpdateBulkOperation.
+func (mr *MockStoreMockRecorder) UpdateBulkOperation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBulkOperation", reflect.TypeOf((*MockStore)(nil).UpdateBulkOperation), arg0)
+}
+
 // UpdateCardLimitTimestamp mocks base method.
 func (m *MockStore) UpdateCardLimitTimestamp(arg0 int) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1734,6 +2591,20 @@ func (mr *MockStoreMockRecorder) UpdateUserPasswordByID(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordByID", reflect.TypeOf((*MockStore)(nil).UpdateUserPasswordByID), arg0, arg1)
 }
 
+// UpsertCardRecurrence mocks base method.
+func (m *MockStore) UpsertCardRecurrence(arg0 *model.CardRecurrence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCardRecurrence", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertCardRecurrence indicates an expected call of UpsertCardRecurrence.
+func (mr *MockStoreMockRecorder) UpsertCardRecurrence(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCardRecurrence", reflect.TypeOf((*MockStore)(nil).UpsertCardRecurrence), arg0)
+}
+
 // UpsertNotificationHint mocks base method.
 func (m *MockStore) UpsertNotificationHint(arg0 *model.NotificationHint, arg1 time.Duration) (*model.NotificationHint, error) {
 	m.ctrl.T.Helper()
@@ -1749,6 +2620,20 @@ func (mr *MockStoreMockRecorder) UpsertNotificationHint(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationHint", reflect.TypeOf((*MockStore)(nil).UpsertNotificationHint), arg0, arg1)
 }
 
+// UpsertRetentionPolicy mocks base method.
+func (m *MockStore) UpsertRetentionPolicy(arg0 *model.RetentionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRetentionPolicy", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertRetentionPolicy indicates an expected call of UpsertRetentionPolicy.
+func (mr *MockStoreMockRecorder) UpsertRetentionPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRetentionPolicy", reflect.TypeOf((*MockStore)(nil).UpsertRetentionPolicy), arg0)
+}
+
 // UpsertSharing mocks base method.
 func (m *MockStore) UpsertSharing(arg0 model.Sharing) error {
 	m.ctrl.T.Helper()
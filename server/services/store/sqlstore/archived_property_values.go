@@ -0,0 +1,208 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var archivedPropertyValueFields = []string{
+	"board_id",
+	"card_id",
+	"property_id",
+	"value",
+	"archived_by",
+	"archived_at",
+}
+
+func (s *SQLStore) archivedPropertyValuesFromRows(rows *sql.Rows) ([]*model.ArchivedPropertyValue, error) {
+	values := []*model.ArchivedPropertyValue{}
+
+	for rows.Next() {
+		var value model.ArchivedPropertyValue
+		var valueJSON []byte
+		err := rows.Scan(
+			&value.BoardID,
+			&value.CardID,
+			&value.PropertyID,
+			&valueJSON,
+			&value.ArchivedBy,
+			&value.ArchivedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(valueJSON) != 0 {
+			if err := json.Unmarshal(valueJSON, &value.Value); err != nil {
+				return nil, err
+			}
+		}
+		values = append(values, &value)
+	}
+	return values, nil
+}
+
+// archivePropertyValue keeps a removed card value. If the property of
+// the card already has an archived value, the oldest one is kept.
+func (s *SQLStore) archivePropertyValue(db sq.BaseRunner, value *model.ArchivedPropertyValue) error {
+	valueJSON, err := json.Marshal(value.Value)
+	if err != nil {
+		return err
+	}
+
+	if value.ArchivedAt == 0 {
+		value.ArchivedAt = utils.GetMillis()
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"archived_property_values").
+		Columns(archivedPropertyValueFields...).
+		Values(value.BoardID, value.CardID, value.PropertyID, valueJSON, value.ArchivedBy, value.ArchivedAt)
+
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE board_id = board_id")
+	} else {
+		query = query.Suffix("ON CONFLICT (board_id, card_id, property_id) DO NOTHING")
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot archive property value",
+			mlog.String("card_id", value.CardID),
+			mlog.String("property_id", value.PropertyID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getArchivedPropertyValues(db sq.BaseRunner, boardID string) ([]*model.ArchivedPropertyValue, error) {
+	query := s.getQueryBuilder(db).
+		Select(archivedPropertyValueFields...).
+		From(s.tablePrefix+"archived_property_values").
+		Where(sq.Eq{"board_id": boardID}).
+		OrderBy("property_id", "archived_at", "card_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch archived property values", mlog.String("board_id", boardID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.archivedPropertyValuesFromRows(rows)
+}
+
+// restoreArchivedPropertyValues writes the archived values of a property
+// back to the cards that still exist, and removes them from the archive.
+// It returns the updated cards.
+func (s *SQLStore) restoreArchivedPropertyValues(db sq.BaseRunner, boardID, propertyID, userID string) ([]*model.Block, error) {
+	query := s.getQueryBuilder(db).
+		Select(archivedPropertyValueFields...).
+		From(s.tablePrefix + "archived_property_values").
+		Where(sq.Eq{"board_id": boardID}).
+		Where(sq.Eq{"property_id": propertyID}).
+		OrderBy("card_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch archived property values", mlog.String("board_id", boardID), mlog.Err(err))
+		return nil, err
+	}
+	values, err := s.archivedPropertyValuesFromRows(rows)
+	s.CloseRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := []*model.Block{}
+	for _, value := range values {
+		card, err := s.getBlock(db, value.CardID)
+		if model.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if card.BoardID != boardID {
+			continue
+		}
+
+		if card.Fields == nil {
+			card.Fields = map[string]interface{}{}
+		}
+		props, ok := card.Fields["properties"].(map[string]interface{})
+		if !ok {
+			props = map[string]interface{}{}
+		}
+		props[propertyID] = value.Value
+		card.Fields["properties"] = props
+
+		if err := s.insertBlock(db, card, userID); err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+
+	deleteQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "archived_property_values").
+		Where(sq.Eq{"board_id": boardID}).
+		Where(sq.Eq{"property_id": propertyID})
+	if _, err := deleteQuery.Exec(); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// cleanupCardProperties removes or remaps the card values of the
+// properties and options removed from a board, archiving the previous
+// values if requested.
+func (s *SQLStore) cleanupCardProperties(db sq.BaseRunner, boardID string, cleanup *model.PropertyCleanup, archive bool, userID string) error {
+	cards, err := s.getBlocksWithType(db, boardID, string(model.TypeCard))
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetMillis()
+	for _, card := range cards {
+		props, ok := card.Fields["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		oldValues := cleanup.Apply(props)
+		if len(oldValues) == 0 {
+			continue
+		}
+
+		if archive {
+			for propertyID, value := range oldValues {
+				archived := &model.ArchivedPropertyValue{
+					BoardID:    boardID,
+					CardID:     card.ID,
+					PropertyID: propertyID,
+					Value:      value,
+					ArchivedBy: userID,
+					ArchivedAt: now,
+				}
+				if err := s.archivePropertyValue(db, archived); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := s.insertBlock(db, card, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
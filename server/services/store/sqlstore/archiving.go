@@ -0,0 +1,36 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// setBlockArchiveAt archives a block at the given time, or unarchives it
+// if archiveAt is zero, and returns the updated block.
+func (s *SQLStore) setBlockArchiveAt(db sq.BaseRunner, blockID string, archiveAt int64, userID string) (*model.Block, error) {
+	block, err := s.getBlock(db, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	block.ArchiveAt = archiveAt
+	if err := s.insertBlock(db, block, userID); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// setBoardArchiveAt archives a board at the given time, or unarchives it
+// if archiveAt is zero, and returns the updated board.
+func (s *SQLStore) setBoardArchiveAt(db sq.BaseRunner, boardID string, archiveAt int64, userID string) (*model.Board, error) {
+	board, err := s.getBoard(db, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	board.ArchiveAt = archiveAt
+	return s.insertBoard(db, board, userID)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var auditRecordFields = []string{
+	"id",
+	"create_at",
+	"level",
+	"event",
+	"status",
+	"user_id",
+	"session_id",
+	"client",
+	"ip_address",
+	"api_path",
+	"team_id",
+	"board_id",
+	"meta",
+}
+
+// auditMetaJSON serializes the metadata of an audit record. The values
+// that can't be serialized are stored as their string representation.
+func auditMetaJSON(meta map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(meta)
+	if err == nil {
+		return data, nil
+	}
+
+	converted := make(map[string]interface{}, len(meta))
+	for k, v := range meta {
+		if _, err := json.Marshal(v); err != nil {
+			converted[k] = fmt.Sprint(v)
+			continue
+		}
+		converted[k] = v
+	}
+	return json.Marshal(converted)
+}
+
+func (s *SQLStore) insertAuditRecord(db sq.BaseRunner, record *model.AuditRecord) error {
+	metaJSON, err := auditMetaJSON(record.Meta)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"audit_records").
+		Columns(auditRecordFields...).
+		Values(
+			record.ID,
+			record.CreateAt,
+			record.Level,
+			record.Event,
+			record.Status,
+			record.UserID,
+			record.SessionID,
+			record.Client,
+			record.IPAddress,
+			record.APIPath,
+			record.TeamID,
+			record.BoardID,
+			metaJSON,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert audit record", mlog.String("event", record.Event), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) auditRecordsFromRows(rows *sql.Rows) ([]*model.AuditRecord, error) {
+	records := []*model.AuditRecord{}
+
+	for rows.Next() {
+		var record model.AuditRecord
+		var metaJSON []byte
+		err := rows.Scan(
+			&record.ID,
+			&record.CreateAt,
+			&record.Level,
+			&record.Event,
+			&record.Status,
+			&record.UserID,
+			&record.SessionID,
+			&record.Client,
+			&record.IPAddress,
+			&record.APIPath,
+			&record.TeamID,
+			&record.BoardID,
+			&metaJSON,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		record.Meta = map[string]interface{}{}
+		if len(metaJSON) != 0 {
+			if err := json.Unmarshal(metaJSON, &record.Meta); err != nil {
+				return nil, err
+			}
+		}
+		records = append(records, &record)
+	}
+	return records, nil
+}
+
+// getAuditRecords returns the audit records matching the options, the
+// most recent first, and whether more records follow them.
+func (s *SQLStore) getAuditRecords(db sq.BaseRunner, opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+	query := s.getQueryBuilder(db).
+		Select(auditRecordFields...).
+		From(s.tablePrefix+"audit_records").
+		OrderBy("create_at DESC", "id DESC")
+
+	if opts.UserID != "" {
+		query = query.Where(sq.Eq{"user_id": opts.UserID})
+	}
+	if opts.Event != "" {
+		query = query.Where(sq.Eq{"event": opts.Event})
+	}
+	if opts.BoardID != "" {
+		query = query.Where(sq.Eq{"board_id": opts.BoardID})
+	}
+	if opts.Status != "" {
+		query = query.Where(sq.Eq{"status": opts.Status})
+	}
+	if opts.Since != 0 {
+		query = query.Where(sq.GtOrEq{"create_at": opts.Since})
+	}
+	if opts.Until != 0 {
+		query = query.Where(sq.Lt{"create_at": opts.Until})
+	}
+	if opts.Cursor != "" {
+		createAt, id, err := model.ParseAuditCursor(opts.Cursor)
+		if err != nil {
+			return nil, false, model.NewErrBadRequest(err.Error())
+		}
+		query = query.Where(sq.Or{
+			sq.Lt{"create_at": createAt},
+			sq.And{sq.Eq{"create_at": createAt}, sq.Lt{"id": id}},
+		})
+	}
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(uint64(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch audit records", mlog.Err(err))
+		return nil, false, err
+	}
+	defer s.CloseRows(rows)
+
+	records, err := s.auditRecordsFromRows(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var hasMore bool
+	if opts.PerPage > 0 && len(records) > opts.PerPage {
+		records = records[0:opts.PerPage]
+		hasMore = true
+	}
+	return records, hasMore, nil
+}
+
+// deleteAuditRecords removes the audit records created before a time and
+// returns the number of records removed.
+func (s *SQLStore) deleteAuditRecords(db sq.BaseRunner, before int64) (int64, error) {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "audit_records").
+		Where(sq.Lt{"create_at": before}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot delete audit records", mlog.Err(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,396 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var automationRuleFields = []string{
+	"id",
+	"board_id",
+	"title",
+	"enabled",
+	"trigger_type",
+	"trigger_data",
+	"actions",
+	"created_by",
+	"modified_by",
+	"create_at",
+	"update_at",
+}
+
+var automationExecutionFields = []string{
+	"id",
+	"rule_id",
+	"board_id",
+	"card_id",
+	"trigger_key",
+	"status",
+	"results",
+	"create_at",
+}
+
+func (s *SQLStore) automationRulesFromRows(rows *sql.Rows) ([]*model.AutomationRule, error) {
+	rules := []*model.AutomationRule{}
+
+	for rows.Next() {
+		var rule model.AutomationRule
+		var title sql.NullString
+		var enabled sql.NullBool
+		var triggerType string
+		var triggerJSON []byte
+		var actionsJSON []byte
+		err := rows.Scan(
+			&rule.ID,
+			&rule.BoardID,
+			&title,
+			&enabled,
+			&triggerType,
+			&triggerJSON,
+			&actionsJSON,
+			&rule.CreatedBy,
+			&rule.ModifiedBy,
+			&rule.CreateAt,
+			&rule.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rule.Title = title.String
+		rule.Enabled = enabled.Bool
+
+		if err := json.Unmarshal(triggerJSON, &rule.Trigger); err != nil {
+			return nil, err
+		}
+		rule.Trigger.Type = model.AutomationTriggerType(triggerType)
+		if err := json.Unmarshal(actionsJSON, &rule.Actions); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+	return rules, nil
+}
+
+func (s *SQLStore) automationExecutionsFromRows(rows *sql.Rows) ([]*model.AutomationExecution, error) {
+	executions := []*model.AutomationExecution{}
+
+	for rows.Next() {
+		var execution model.AutomationExecution
+		var triggerKey sql.NullString
+		var resultsJSON []byte
+		err := rows.Scan(
+			&execution.ID,
+			&execution.RuleID,
+			&execution.BoardID,
+			&execution.CardID,
+			&triggerKey,
+			&execution.Status,
+			&resultsJSON,
+			&execution.CreateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		execution.TriggerKey = triggerKey.String
+
+		execution.Results = []model.AutomationActionResult{}
+		if len(resultsJSON) != 0 {
+			if err := json.Unmarshal(resultsJSON, &execution.Results); err != nil {
+				return nil, err
+			}
+		}
+		executions = append(executions, &execution)
+	}
+	return executions, nil
+}
+
+func (s *SQLStore) insertAutomationRule(db sq.BaseRunner, rule *model.AutomationRule) error {
+	if err := rule.IsValid(); err != nil {
+		return err
+	}
+
+	triggerJSON, err := json.Marshal(rule.Trigger)
+	if err != nil {
+		return err
+	}
+	actionsJSON, err := json.Marshal(rule.Actions)
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetMillis()
+	rule.CreateAt = now
+	rule.UpdateAt = now
+	if rule.ModifiedBy == "" {
+		rule.ModifiedBy = rule.CreatedBy
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"automation_rules").
+		Columns(automationRuleFields...).
+		Values(
+			rule.ID,
+			rule.BoardID,
+			rule.Title,
+			rule.Enabled,
+			rule.Trigger.Type,
+			triggerJSON,
+			actionsJSON,
+			rule.CreatedBy,
+			rule.ModifiedBy,
+			rule.CreateAt,
+			rule.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert automation rule", mlog.String("rule_id", rule.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) updateAutomationRule(db sq.BaseRunner, rule *model.AutomationRule) error {
+	if err := rule.IsValid(); err != nil {
+		return err
+	}
+
+	triggerJSON, err := json.Marshal(rule.Trigger)
+	if err != nil {
+		return err
+	}
+	actionsJSON, err := json.Marshal(rule.Actions)
+	if err != nil {
+		return err
+	}
+
+	rule.UpdateAt = utils.GetMillis()
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"automation_rules").
+		Set("title", rule.Title).
+		Set("enabled", rule.Enabled).
+		Set("trigger_type", rule.Trigger.Type).
+		Set("trigger_data", triggerJSON).
+		Set("actions", actionsJSON).
+		Set("modified_by", rule.ModifiedBy).
+		Set("update_at", rule.UpdateAt).
+		Where(sq.Eq{"id": rule.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot update automation rule", mlog.String("rule_id", rule.ID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("automation rule ID=" + rule.ID)
+	}
+	return nil
+}
+
+func (s *SQLStore) getAutomationRule(db sq.BaseRunner, ruleID string) (*model.AutomationRule, error) {
+	query := s.getQueryBuilder(db).
+		Select(automationRuleFields...).
+		From(s.tablePrefix + "automation_rules").
+		Where(sq.Eq{"id": ruleID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch automation rule", mlog.String("rule_id", ruleID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	rules, err := s.automationRulesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, model.NewErrNotFound("automation rule ID=" + ruleID)
+	}
+	return rules[0], nil
+}
+
+func (s *SQLStore) getAutomationRulesForBoard(db sq.BaseRunner, boardID string) ([]*model.AutomationRule, error) {
+	query := s.getQueryBuilder(db).
+		Select(automationRuleFields...).
+		From(s.tablePrefix+"automation_rules").
+		Where(sq.Eq{"board_id": boardID}).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch automation rules for board", mlog.String("board_id", boardID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.automationRulesFromRows(rows)
+}
+
+// getEnabledAutomationRulesWithTrigger returns the enabled rules of all
+// the boards with the given trigger type.
+func (s *SQLStore) getEnabledAutomationRulesWithTrigger(db sq.BaseRunner, triggerType model.AutomationTriggerType) ([]*model.AutomationRule, error) {
+	query := s.getQueryBuilder(db).
+		Select(automationRuleFields...).
+		From(s.tablePrefix+"automation_rules").
+		Where(sq.Eq{"trigger_type": triggerType}).
+		Where(sq.Eq{"enabled": true}).
+		OrderBy("board_id", "create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch automation rules with trigger", mlog.String("trigger_type", string(triggerType)), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.automationRulesFromRows(rows)
+}
+
+func (s *SQLStore) deleteAutomationRule(db sq.BaseRunner, ruleID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "automation_rules").
+		Where(sq.Eq{"id": ruleID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return model.NewErrNotFound("automation rule ID=" + ruleID)
+	}
+
+	deleteQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "automation_executions").
+		Where(sq.Eq{"rule_id": ruleID})
+	if _, err := deleteQuery.Exec(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// addAutomationExecution records an execution of a rule. It returns false
+// if the rule already ran for the same card and trigger key.
+func (s *SQLStore) addAutomationExecution(db sq.BaseRunner, execution *model.AutomationExecution) (bool, error) {
+	resultsJSON, err := json.Marshal(execution.Results)
+	if err != nil {
+		return false, err
+	}
+
+	if execution.CreateAt == 0 {
+		execution.CreateAt = utils.GetMillis()
+	}
+
+	var triggerKey interface{}
+	if execution.TriggerKey != "" {
+		triggerKey = execution.TriggerKey
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"automation_executions").
+		Columns(automationExecutionFields...).
+		Values(
+			execution.ID,
+			execution.RuleID,
+			execution.BoardID,
+			execution.CardID,
+			triggerKey,
+			execution.Status,
+			resultsJSON,
+			execution.CreateAt,
+		)
+
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE id = id")
+	} else {
+		query = query.Suffix("ON CONFLICT DO NOTHING")
+	}
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot add automation execution", mlog.String("rule_id", execution.RuleID), mlog.Err(err))
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// updateAutomationExecution records the outcome of an execution.
+func (s *SQLStore) updateAutomationExecution(db sq.BaseRunner, execution *model.AutomationExecution) error {
+	resultsJSON, err := json.Marshal(execution.Results)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"automation_executions").
+		Set("status", execution.Status).
+		Set("results", resultsJSON).
+		Where(sq.Eq{"id": execution.ID})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot update automation execution", mlog.String("execution_id", execution.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// getAutomationExecutions returns the latest executions of a rule, the
+// most recent first.
+func (s *SQLStore) getAutomationExecutions(db sq.BaseRunner, ruleID string, limit uint64) ([]*model.AutomationExecution, error) {
+	query := s.getQueryBuilder(db).
+		Select(automationExecutionFields...).
+		From(s.tablePrefix+"automation_executions").
+		Where(sq.Eq{"rule_id": ruleID}).
+		OrderBy("create_at DESC", "id DESC")
+
+	if limit != 0 {
+		query = query.Limit(limit)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch automation executions", mlog.String("rule_id", ruleID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.automationExecutionsFromRows(rows)
+}
+
+func (s *SQLStore) deleteAutomationExecutionsBefore(db sq.BaseRunner, createAt int64) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "automation_executions").
+		Where(sq.Lt{"create_at": createAt})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot delete automation executions", mlog.Err(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
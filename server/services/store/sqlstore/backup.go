@@ -0,0 +1,337 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var ErrBackupFileNotSupported = errors.New("database file backups are only supported by SQLite")
+
+// isBackupTable tells if a table, without prefix, holds data that a
+// backup must contain. The migrations tables are excluded as they are
+// recreated by the migrations themselves.
+func isBackupTable(table string) bool {
+	return table != "schema_migrations" &&
+		table != sqliteMigrationsTableName &&
+		table != tempSchemaMigrationTableName &&
+		!strings.HasPrefix(table, "sqlite_")
+}
+
+// getBackupTables returns the names, without prefix, of the tables of
+// the store.
+func (s *SQLStore) getBackupTables(db sq.BaseRunner) ([]string, error) {
+	var query sq.SelectBuilder
+
+	switch s.dbType {
+	case model.MysqlDBType, model.PostgresDBType:
+		query = s.getQueryBuilder(db).
+			Select("table_name").
+			From("INFORMATION_SCHEMA.TABLES").
+			Where(sq.Eq{"table_schema": s.schemaName})
+	case model.SqliteDBType:
+		query = s.getQueryBuilder(db).
+			Select("name").
+			From("sqlite_master").
+			Where(sq.Eq{"type": "table"})
+	default:
+		return nil, ErrUnsupportedDatabaseType
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch the backup tables", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return tablesFromRows(rows, s.tablePrefix)
+}
+
+func tablesFromRows(rows *sql.Rows, prefix string) ([]string, error) {
+	tables := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if table := strings.TrimPrefix(name, prefix); isBackupTable(table) {
+			tables = append(tables, table)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// readRows calls fn with each row of the result set, keyed by column
+// name. Byte slices are converted to strings so the rows can be encoded
+// and inserted in any database.
+func readRows(rows *sql.Rows, fn func(row map[string]interface{}) error) (int64, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return count, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		if err := fn(row); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// dumpDatabase writes all the rows of the store tables and returns the
+// number of rows of each table. The tables are read in a single read only
+// transaction, so that the dump is a consistent snapshot of the database
+// while it stays online.
+func (s *SQLStore) dumpDatabase(db sq.BaseRunner, w model.DatabaseDumpWriter) (map[string]int64, error) {
+	if sqlDB, ok := db.(*sql.DB); ok {
+		tx, err := sqlDB.BeginTx(context.Background(), s.snapshotTxOptions())
+		if err != nil {
+			s.logger.Error("Cannot start the dump transaction", mlog.Err(err))
+			return nil, err
+		}
+		// nothing is written, so the transaction is always rolled back
+		defer func() {
+			if err := tx.Rollback(); err != nil {
+				s.logger.Error("Cannot end the dump transaction", mlog.Err(err))
+			}
+		}()
+		db = tx
+	}
+
+	tables, err := s.getBackupTables(db)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		rows, err := s.getQueryBuilder(db).
+			Select("*").
+			From(s.tablePrefix + table).
+			Query()
+		if err != nil {
+			s.logger.Error("Cannot dump table", mlog.String("table", table), mlog.Err(err))
+			return nil, err
+		}
+
+		count, err := readRows(rows, func(row map[string]interface{}) error {
+			return w.WriteRow(table, row)
+		})
+		s.CloseRows(rows)
+		if err != nil {
+			s.logger.Error("Cannot dump table", mlog.String("table", table), mlog.Err(err))
+			return nil, err
+		}
+		counts[table] = count
+	}
+	return counts, nil
+}
+
+// snapshotTxOptions returns the options of a transaction whose reads
+// all see the same snapshot of the database. The repeatable read
+// isolation takes the snapshot at the first read for both PostgreSQL and
+// MySQL, and SQLite transactions always read a snapshot.
+func (s *SQLStore) snapshotTxOptions() *sql.TxOptions {
+	if s.dbType == model.SqliteDBType {
+		return nil
+	}
+	return &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+}
+
+// backupDatabaseFile writes a consistent copy of a SQLite database to
+// path, which must not exist, while the database stays online. It
+// returns the number of rows of each table of the copy.
+func (s *SQLStore) backupDatabaseFile(db sq.BaseRunner, path string) (map[string]int64, error) {
+	if s.dbType != model.SqliteDBType {
+		return nil, ErrBackupFileNotSupported
+	}
+
+	if _, err := db.Exec("VACUUM INTO ?", path); err != nil {
+		s.logger.Error("Cannot backup the database file", mlog.String("path", path), mlog.Err(err))
+		return nil, err
+	}
+
+	dump, err := openSQLiteFileDump(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dump.Close()
+
+	counts := make(map[string]int64, len(dump.tables))
+	for _, table := range dump.tables {
+		var count int64
+		if err := dump.db.QueryRow("SELECT COUNT(*) FROM " + dump.prefix + table).Scan(&count); err != nil {
+			return nil, err
+		}
+		counts[table] = count
+	}
+	return counts, nil
+}
+
+// restoreDatabase replaces the content of the tables of the dump with its
+// rows and returns the number of rows restored in each table. The tables
+// that are not part of the dump are left untouched.
+func (s *SQLStore) restoreDatabase(db sq.BaseRunner, dump model.DatabaseDumpReader) (map[string]int64, error) {
+	tables, err := s.getBackupTables(db)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		existing[table] = true
+	}
+
+	counts := map[string]int64{}
+	for _, table := range dump.Tables() {
+		if !existing[table] {
+			return nil, model.NewErrBadRequest("the backup table " + table + " does not exist in the database")
+		}
+
+		if _, err := s.getQueryBuilder(db).Delete(s.tablePrefix + table).Exec(); err != nil {
+			s.logger.Error("Cannot clear table before restore", mlog.String("table", table), mlog.Err(err))
+			return nil, err
+		}
+
+		var count int64
+		err := dump.ReadRows(table, func(row map[string]interface{}) error {
+			columns := make([]string, 0, len(row))
+			for column := range row {
+				columns = append(columns, column)
+			}
+			sort.Strings(columns)
+
+			// some columns are reserved words, like schema for MySQL
+			values := make([]interface{}, len(columns))
+			escaped := make([]string, len(columns))
+			for i, column := range columns {
+				values[i] = row[column]
+				escaped[i] = s.escapeField(column)
+			}
+
+			if _, err := s.getQueryBuilder(db).
+				Insert(s.tablePrefix + table).
+				Columns(escaped...).
+				Values(values...).
+				Exec(); err != nil {
+				return err
+			}
+			count++
+			return nil
+		})
+		if err != nil {
+			s.logger.Error("Cannot restore table", mlog.String("table", table), mlog.Err(err))
+			return nil, err
+		}
+		counts[table] = count
+	}
+	return counts, nil
+}
+
+// sqliteFileDump reads the tables of a SQLite database file, such as
+// the ones written by backupDatabaseFile.
+type sqliteFileDump struct {
+	db     *sql.DB
+	prefix string
+	tables []string
+}
+
+// openSQLiteFileDump opens a SQLite database file read only. The prefix
+// of its tables is the one of its system settings table.
+func openSQLiteFileDump(path string) (*sqliteFileDump, error) {
+	db, err := sql.Open(model.SqliteDBType, "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+
+	dump := &sqliteFileDump{db: db}
+	if err := dump.db.QueryRow(
+		"SELECT SUBSTR(name, 1, LENGTH(name) - LENGTH('system_settings')) FROM sqlite_master WHERE type = 'table' AND name LIKE '%system_settings'",
+	).Scan(&dump.prefix); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot find the system settings table of %s: %w", path, err)
+	}
+
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table'")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	defer rows.Close()
+
+	if dump.tables, err = tablesFromRows(rows, dump.prefix); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return dump, nil
+}
+
+func (d *sqliteFileDump) Close() error {
+	return d.db.Close()
+}
+
+func (d *sqliteFileDump) Tables() []string {
+	return d.tables
+}
+
+func (d *sqliteFileDump) ReadRows(table string, fn func(row map[string]interface{}) error) error {
+	rows, err := d.db.Query("SELECT * FROM " + d.prefix + table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	_, err = readRows(rows, fn)
+	return err
+}
+
+// restoreDatabaseFile restores the content of a SQLite database file into
+// the store, whatever its database type and table prefix.
+func (s *SQLStore) restoreDatabaseFile(db sq.BaseRunner, path string) (map[string]int64, error) {
+	dump, err := openSQLiteFileDump(path)
+	if err != nil {
+		s.logger.Error("Cannot read the backup database file", mlog.String("path", path), mlog.Err(err))
+		return nil, err
+	}
+	defer dump.Close()
+
+	return s.restoreDatabase(db, dump)
+}
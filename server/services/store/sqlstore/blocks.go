@@ -50,6 +50,7 @@ func (s *SQLStore) blockFields(tableAlias string) []string {
 		tableAlias + "update_at",
 		tableAlias + "delete_at",
 		"COALESCE(" + tableAlias + "board_id, '0')",
+		tableAlias + "archive_at",
 	}
 }
 
@@ -70,12 +71,17 @@ func (s *SQLStore) getBlocks(db sq.BaseRunner, opts model.QueryBlocksOptions) ([
 		query = query.Where(sq.Eq{"type": opts.BlockType})
 	}
 
+	if opts.ExcludeArchived {
+		query = query.Where(sq.Eq{"archive_at": 0})
+	}
+
 	if opts.Page != 0 {
 		query = query.Offset(uint64(opts.Page * opts.PerPage))
 	}
 
 	if opts.PerPage > 0 {
-		query = query.Limit(uint64(opts.PerPage))
+		// pages need a stable order to not skip or repeat blocks
+		query = query.Limit(uint64(opts.PerPage)).OrderBy("create_at", "id")
 	}
 
 	rows, err := query.Query()
@@ -179,6 +185,35 @@ func (s *SQLStore) getBlocksForBoard(db sq.BaseRunner, boardID string) ([]*model
 	return s.getBlocks(db, opts)
 }
 
+// getCardsReferencingCard returns the non deleted cards of the given
+// boards that contain the given card ID in their fields, such as in
+// relation properties. The match is done on the JSON encoded fields, so
+// callers are expected to check where the ID is referenced.
+func (s *SQLStore) getCardsReferencingCard(db sq.BaseRunner, cardID string, boardIDs []string) ([]*model.Block, error) {
+	if len(boardIDs) == 0 {
+		return []*model.Block{}, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("")...).
+		From(s.tablePrefix+"blocks").
+		Where(sq.Eq{"board_id": boardIDs}).
+		Where(sq.Eq{"type": model.TypeCard}).
+		Where(sq.Eq{"delete_at": 0}).
+		Where(sq.NotEq{"id": cardID}).
+		Where(s.elementInColumn("fields"), `"`+cardID+`"`)
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getCardsReferencingCard ERROR`, mlog.Err(err))
+
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.blocksFromRows(rows)
+}
+
 func (s *SQLStore) blocksFromRows(rows *sql.Rows) ([]*model.Block, error) {
 	results := []*model.Block{}
 
@@ -201,7 +236,8 @@ func (s *SQLStore) blocksFromRows(rows *sql.Rows) ([]*model.Block, error) {
 			&block.CreateAt,
 			&block.UpdateAt,
 			&block.DeleteAt,
-			&block.BoardID)
+			&block.BoardID,
+			&block.ArchiveAt)
 		if err != nil {
 			// handle this error
 			s.logger.Error(`ERROR blocksFromRows`, mlog.Err(err))
@@ -260,6 +296,7 @@ func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID stri
 			"update_at",
 			"delete_at",
 			"board_id",
+			"archive_at",
 		)
 
 	insertQueryValues := map[string]interface{}{
@@ -276,6 +313,7 @@ func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID stri
 		"create_at":             utils.GetMillis(),
 		"update_at":             block.UpdateAt,
 		"board_id":              block.BoardID,
+		"archive_at":            block.ArchiveAt,
 	}
 
 	if existingBlock != nil {
@@ -290,7 +328,8 @@ func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID stri
 			Set("title", block.Title).
 			Set("fields", fieldsJSON).
 			Set("update_at", block.UpdateAt).
-			Set("delete_at", block.DeleteAt)
+			Set("delete_at", block.DeleteAt).
+			Set("archive_at", block.ArchiveAt)
 
 		if _, err := query.Exec(); err != nil {
 			s.logger.Error(`InsertBlock error occurred while updating existing block`, mlog.String("blockID", block.ID), mlog.Err(err))
@@ -391,6 +430,7 @@ func (s *SQLStore) deleteBlockAndChildren(db sq.BaseRunner, blockID string, modi
 			"update_at",
 			"delete_at",
 			"created_by",
+			"archive_at",
 		).
 		Values(
 			block.BoardID,
@@ -405,6 +445,7 @@ func (s *SQLStore) deleteBlockAndChildren(db sq.BaseRunner, blockID string, modi
 			now,
 			now,
 			block.CreatedBy,
+			block.ArchiveAt,
 		)
 
 	if _, err := insertQuery.Exec(); err != nil {
@@ -487,6 +528,7 @@ func (s *SQLStore) undeleteBlock(db sq.BaseRunner, blockID string, modifiedBy st
 		"update_at",
 		"delete_at",
 		"created_by",
+		"archive_at",
 	}
 
 	values := []interface{}{
@@ -503,6 +545,7 @@ func (s *SQLStore) undeleteBlock(db sq.BaseRunner, blockID string, modifiedBy st
 		now,
 		0,
 		block.CreatedBy,
+		block.ArchiveAt,
 	}
 	insertHistoryQuery := s.getQueryBuilder(db).Insert(s.tablePrefix + "blocks_history").
 		Columns(columns...).
@@ -922,6 +965,7 @@ func (s *SQLStore) deleteBlockChildren(db sq.BaseRunner, boardID string, parentI
 			s.castInt(now, "update_at"),
 			s.castInt(now, "delete_at"),
 			"created_by",
+			"archive_at",
 		).
 		From(s.tablePrefix + "blocks").
 		Where(sq.Eq{"board_id": boardID})
@@ -945,6 +989,7 @@ func (s *SQLStore) deleteBlockChildren(db sq.BaseRunner, boardID string, parentI
 			"update_at",
 			"delete_at",
 			"created_by",
+			"archive_at",
 		).Select(selectQuery)
 
 	if _, err := insertQuery.Exec(); err != nil {
@@ -1033,6 +1078,7 @@ func (s *SQLStore) undeleteBlockChildren(db sq.BaseRunner, boardID string, paren
 			s.castInt(utils.GetMillis(), "update_at"),
 			s.castInt(0, "delete_at"),
 			"bh.created_by",
+			"bh.archive_at",
 		).
 		From(fmt.Sprintf(`
 				%sblocks_history AS bh,
@@ -1056,6 +1102,7 @@ func (s *SQLStore) undeleteBlockChildren(db sq.BaseRunner, boardID string, paren
 		"update_at",
 		"delete_at",
 		"created_by",
+		"archive_at",
 	}
 
 	insertQuery := s.getQueryBuilder(db).Insert(s.tablePrefix + "blocks").
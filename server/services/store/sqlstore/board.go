@@ -41,6 +41,7 @@ func boardFields(tableAlias string) []string {
 		tableAlias + "create_at",
 		tableAlias + "update_at",
 		tableAlias + "delete_at",
+		tableAlias + "archive_at",
 	}
 }
 
@@ -64,6 +65,7 @@ func boardHistoryFields() []string {
 		"COALESCE(create_at, 0)",
 		"COALESCE(update_at, 0)",
 		"COALESCE(delete_at, 0)",
+		"COALESCE(archive_at, 0)",
 	}
 
 	return fields
@@ -107,6 +109,7 @@ func (s *SQLStore) boardsFromRows(rows *sql.Rows) ([]*model.Board, error) {
 			&board.CreateAt,
 			&board.UpdateAt,
 			&board.DeleteAt,
+			&board.ArchiveAt,
 		)
 		if err != nil {
 			s.logger.Error("boardsFromRows scan error", mlog.Err(err))
@@ -355,6 +358,7 @@ func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID stri
 		"create_at":        board.CreateAt,
 		"update_at":        board.UpdateAt,
 		"delete_at":        board.DeleteAt,
+		"archive_at":       board.ArchiveAt,
 	}
 
 	if existingBoard != nil {
@@ -373,7 +377,8 @@ func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID stri
 			Set("properties", propertiesBytes).
 			Set("card_properties", cardPropertiesBytes).
 			Set("update_at", board.UpdateAt).
-			Set("delete_at", board.DeleteAt)
+			Set("delete_at", board.DeleteAt).
+			Set("archive_at", board.ArchiveAt)
 
 		if _, err := query.Exec(); err != nil {
 			s.logger.Error(`InsertBoard error occurred while updating existing board`, mlog.String("boardID", board.ID), mlog.Err(err))
@@ -407,8 +412,28 @@ func (s *SQLStore) patchBoard(db sq.BaseRunner, boardID string, boardPatch *mode
 		return nil, err
 	}
 
+	// the card values are only cleaned up if both schemas are valid
+	oldSchema, oldSchemaErr := model.ParsePropertySchema(existingBoard)
+
 	board := boardPatch.Patch(existingBoard)
-	return s.insertBoard(db, board, userID)
+	board, err = s.insertBoard(db, board, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldSchemaErr != nil || (len(boardPatch.UpdatedCardProperties) == 0 && len(boardPatch.DeletedCardProperties) == 0) {
+		return board, nil
+	}
+
+	if newSchema, schemaErr := model.ParsePropertySchema(board); schemaErr == nil {
+		cleanup := model.NewPropertyCleanup(oldSchema, newSchema, boardPatch.RemappedOptions)
+		if !cleanup.IsEmpty() {
+			if err := s.cleanupCardProperties(db, boardID, cleanup, boardPatch.ArchiveRemovedValues, userID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return board, nil
 }
 
 func (s *SQLStore) deleteBoard(db sq.BaseRunner, boardID, userID string) error {
@@ -451,6 +476,7 @@ func (s *SQLStore) deleteBoardAndChildren(db sq.BaseRunner, boardID, userID stri
 		"create_at":        board.CreateAt,
 		"update_at":        now,
 		"delete_at":        now,
+		"archive_at":       board.ArchiveAt,
 	}
 
 	// writing board history
@@ -706,6 +732,51 @@ func (s *SQLStore) searchBoardsForUser(db sq.BaseRunner, term string, searchFiel
 	return s.boardsFromRows(rows)
 }
 
+// getBoardsWithPropertyType returns the boards, excluding templates,
+// with at least one card property of the given type.
+func (s *SQLStore) getBoardsWithPropertyType(db sq.BaseRunner, propertyType string) ([]*model.Board, error) {
+	// the LIKE condition only narrows down the boards, the property
+	// types are checked once the boards are loaded
+	var cardProperties string
+	switch s.dbType {
+	case model.PostgresDBType:
+		cardProperties = "card_properties::text"
+	case model.MysqlDBType:
+		cardProperties = "CAST(card_properties AS CHAR)"
+	default:
+		cardProperties = "card_properties"
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(boardFields("")...).
+		From(s.tablePrefix+"boards").
+		Where(sq.Eq{"is_template": false}).
+		Where(cardProperties+" LIKE ?", "%\""+propertyType+"\"%")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardsWithPropertyType ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	boards, err := s.boardsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*model.Board{}
+	for _, board := range boards {
+		for _, prop := range board.CardProperties {
+			if prop["type"] == propertyType {
+				result = append(result, board)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
 // searchBoardsForUserInTeam returns all boards that match with the
 // term that are either private and which the user is a member of, or
 // they're open, regardless of the user membership.
@@ -833,6 +904,7 @@ func (s *SQLStore) undeleteBoard(db sq.BaseRunner, boardID string, modifiedBy st
 		"create_at",
 		"update_at",
 		"delete_at",
+		"archive_at",
 	}
 
 	values := []interface{}{
@@ -854,6 +926,7 @@ func (s *SQLStore) undeleteBoard(db sq.BaseRunner, boardID string, modifiedBy st
 		board.CreateAt,
 		now,
 		0,
+		board.ArchiveAt,
 	}
 	insertHistoryQuery := s.getQueryBuilder(db).Insert(s.tablePrefix + "boards_history").
 		Columns(columns...).
@@ -0,0 +1,329 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// getBoardVersions returns the latest changes to a board and its blocks
+// before a given time, grouped by time and user, the newest first.
+func (s *SQLStore) getBoardVersions(db sq.BaseRunner, boardID string, before int64, limit uint64) ([]*model.BoardVersion, error) {
+	blocksQuery := s.getQueryBuilder(db).
+		Select("update_at", "modified_by", "COUNT(*)").
+		From(s.tablePrefix+"blocks_history").
+		Where(sq.Eq{"board_id": boardID}).
+		GroupBy("update_at", "modified_by").
+		OrderBy("update_at DESC").
+		Limit(limit)
+
+	boardsQuery := s.getQueryBuilder(db).
+		Select("update_at", "modified_by", "COUNT(*)").
+		From(s.tablePrefix+"boards_history").
+		Where(sq.Eq{"id": boardID}).
+		GroupBy("update_at", "modified_by").
+		OrderBy("update_at DESC").
+		Limit(limit)
+
+	if before != 0 {
+		blocksQuery = blocksQuery.Where(sq.Lt{"update_at": before})
+		boardsQuery = boardsQuery.Where(sq.Lt{"update_at": before})
+	}
+
+	type versionKey struct {
+		updateAt   int64
+		modifiedBy string
+	}
+	versions := map[versionKey]*model.BoardVersion{}
+
+	for i, query := range []sq.SelectBuilder{blocksQuery, boardsQuery} {
+		isBoardQuery := i == 1
+		rows, err := query.Query()
+		if err != nil {
+			s.logger.Error("Cannot fetch board versions", mlog.String("board_id", boardID), mlog.Err(err))
+			return nil, err
+		}
+
+		for rows.Next() {
+			var key versionKey
+			var count int
+			if err := rows.Scan(&key.updateAt, &key.modifiedBy, &count); err != nil {
+				s.CloseRows(rows)
+				return nil, err
+			}
+
+			version, ok := versions[key]
+			if !ok {
+				version = &model.BoardVersion{UpdateAt: key.updateAt, ModifiedBy: key.modifiedBy}
+				versions[key] = version
+			}
+			if isBoardQuery {
+				version.BoardChanged = true
+			} else {
+				version.BlockCount += count
+			}
+		}
+		s.CloseRows(rows)
+	}
+
+	result := make([]*model.BoardVersion, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, version)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].UpdateAt == result[j].UpdateAt {
+			return result[i].ModifiedBy < result[j].ModifiedBy
+		}
+		return result[i].UpdateAt > result[j].UpdateAt
+	})
+
+	// each query is limited, so the merged versions are too
+	if limit != 0 && uint64(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// getBoardSnapshot rebuilds a board and its blocks as they were at the
+// given time from their history.
+func (s *SQLStore) getBoardSnapshot(db sq.BaseRunner, boardID string, at int64) (*model.BoardSnapshot, error) {
+	boards, err := s.getBoardHistory(db, boardID, model.QueryBoardHistoryOptions{BeforeUpdateAt: at + 1, Limit: 1, Descending: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(boards) == 0 || boards[0].DeleteAt != 0 {
+		return nil, model.NewErrNotFound(fmt.Sprintf("board ID=%s at %d", boardID, at))
+	}
+
+	// as we're joining 2 queries, we need to avoid numbered
+	// placeholders until the join is done, so we use the default
+	// question mark placeholder here
+	builder := s.getQueryBuilder(db).PlaceholderFormat(sq.Question)
+
+	// the latest version of each block that was part of the board, even
+	// if it was moved to another board later
+	boardBlockIDs := builder.
+		Select("DISTINCT bh3.id").
+		From(s.tablePrefix + "blocks_history AS bh3").
+		Where(sq.Eq{"bh3.board_id": boardID})
+
+	boardBlockIDsQuery, boardBlockIDsArgs, err := boardBlockIDs.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("getBoardSnapshot unable to generate block ids subquery: %w", err)
+	}
+
+	sub := builder.
+		Select("bh2.id", "MAX(bh2.insert_at) AS max_insert_at").
+		From(s.tablePrefix+"blocks_history AS bh2").
+		Where("bh2.id IN ("+boardBlockIDsQuery+")", boardBlockIDsArgs...).
+		Where(sq.LtOrEq{"bh2.update_at": at}).
+		GroupBy("bh2.id")
+
+	subQuery, subArgs, err := sub.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("getBoardSnapshot unable to generate subquery: %w", err)
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("bh")...).
+		From(s.tablePrefix+"blocks_history AS bh").
+		InnerJoin("("+subQuery+") AS sub ON bh.id=sub.id AND bh.insert_at=sub.max_insert_at", subArgs...)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("getBoardSnapshot unable to generate sql: %w", err)
+	}
+
+	// if we're using postgres or sqlite, we need to replace the
+	// question mark placeholder with the numbered dollar one, now
+	// that the full query is built
+	if s.dbType == model.PostgresDBType || s.dbType == model.SqliteDBType {
+		var rErr error
+		sql, rErr = sq.Dollar.ReplacePlaceholders(sql)
+		if rErr != nil {
+			return nil, fmt.Errorf("getBoardSnapshot unable to replace sql placeholders: %w", rErr)
+		}
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		s.logger.Error(`getBoardSnapshot ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	versions, err := s.blocksFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*model.Block, 0, len(versions))
+	for _, block := range versions {
+		if block.BoardID == boardID && block.DeleteAt == 0 {
+			blocks = append(blocks, block)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].ID < blocks[j].ID })
+
+	return &model.BoardSnapshot{At: at, Board: boards[0], Blocks: blocks}, nil
+}
+
+// restoreBoardSnapshot writes the content of a board and its blocks as
+// they were at the given time as new changes. If cardIDs is not empty,
+// only those cards and their children are restored.
+func (s *SQLStore) restoreBoardSnapshot(db sq.BaseRunner, boardID string, at int64, cardIDs []string, userID string) (*model.BoardSnapshotRestore, error) {
+	snapshot, err := s.getBoardSnapshot(db, boardID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.getBoard(db, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make(map[string]bool, len(cardIDs))
+	for _, cardID := range cardIDs {
+		cards[cardID] = true
+	}
+	inScope := func(block *model.Block) bool {
+		return len(cards) == 0 || cards[block.ID] || cards[block.ParentID]
+	}
+
+	result := &model.BoardSnapshotRestore{Blocks: []*model.Block{}, DeletedBlockIDs: []string{}}
+
+	if len(cards) == 0 && !model.BoardContentEqual(board, snapshot.Board) {
+		copyBoardContent(board, snapshot.Board)
+		if result.Board, err = s.insertBoard(db, board, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	currentBlocks, err := s.getBlocks(db, model.QueryBlocksOptions{BoardID: boardID})
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*model.Block, len(currentBlocks))
+	for _, block := range currentBlocks {
+		current[block.ID] = block
+	}
+
+	snapshotIDs := make(map[string]bool, len(snapshot.Blocks))
+	for _, block := range snapshot.Blocks {
+		if !inScope(block) {
+			continue
+		}
+		snapshotIDs[block.ID] = true
+
+		if currentBlock, ok := current[block.ID]; ok {
+			if model.BlockContentEqual(currentBlock, block) {
+				continue
+			}
+			if err := s.insertBlock(db, block, userID); err != nil {
+				return nil, err
+			}
+		} else {
+			// blocks moved to another board since are not restored
+			if _, err := s.getBlock(db, block.ID); !model.IsErrNotFound(err) {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err := s.insertBlockVersion(db, block, userID); err != nil {
+				return nil, err
+			}
+		}
+		result.Blocks = append(result.Blocks, block)
+	}
+
+	// the children are deleted before their parents so they are all
+	// part of the result
+	toDelete := map[string]bool{}
+	for _, block := range currentBlocks {
+		if inScope(block) && !snapshotIDs[block.ID] {
+			toDelete[block.ID] = true
+		}
+	}
+	sort.SliceStable(currentBlocks, func(i, j int) bool {
+		return toDelete[currentBlocks[i].ParentID] && !toDelete[currentBlocks[j].ParentID]
+	})
+
+	for _, block := range currentBlocks {
+		if !toDelete[block.ID] {
+			continue
+		}
+		if err := s.deleteBlock(db, block.ID, userID); err != nil {
+			return nil, err
+		}
+		result.DeletedBlockIDs = append(result.DeletedBlockIDs, block.ID)
+	}
+
+	return result, nil
+}
+
+// copyBoardContent sets the content of a board from another version of
+// it, leaving the membership and sharing fields untouched.
+func copyBoardContent(board, from *model.Board) {
+	board.Title = from.Title
+	board.Description = from.Description
+	board.Icon = from.Icon
+	board.ShowDescription = from.ShowDescription
+	board.Properties = from.Properties
+	if board.Properties == nil {
+		board.Properties = map[string]interface{}{}
+	}
+	board.CardProperties = from.CardProperties
+}
+
+// insertBlockVersion inserts a deleted block back with the content of
+// one of its versions, keeping its creation fields.
+func (s *SQLStore) insertBlockVersion(db sq.BaseRunner, block *model.Block, userID string) error {
+	if err := block.IsValid(); err != nil {
+		return fmt.Errorf("error validating block %s: %w", block.ID, err)
+	}
+
+	fieldsJSON, err := json.Marshal(block.Fields)
+	if err != nil {
+		return err
+	}
+
+	block.UpdateAt = utils.GetMillis()
+	block.ModifiedBy = userID
+	block.DeleteAt = 0
+
+	values := map[string]interface{}{
+		"channel_id":            "",
+		"id":                    block.ID,
+		"parent_id":             block.ParentID,
+		s.escapeField("schema"): block.Schema,
+		"type":                  block.Type,
+		"title":                 block.Title,
+		"fields":                fieldsJSON,
+		"delete_at":             block.DeleteAt,
+		"created_by":            block.CreatedBy,
+		"modified_by":           block.ModifiedBy,
+		"create_at":             block.CreateAt,
+		"update_at":             block.UpdateAt,
+		"board_id":              block.BoardID,
+		"archive_at":            block.ArchiveAt,
+	}
+
+	for _, table := range []string{"blocks", "blocks_history"} {
+		query := s.getQueryBuilder(db).Insert(s.tablePrefix + table).SetMap(values)
+		if _, err := query.Exec(); err != nil {
+			s.logger.Error("Cannot insert block version", mlog.String("block_id", block.ID), mlog.String("table", table), mlog.Err(err))
+			return err
+		}
+	}
+	return nil
+}
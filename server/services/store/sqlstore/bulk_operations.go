@@ -0,0 +1,130 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var bulkOperationFields = []string{
+	"id",
+	"board_id",
+	"user_id",
+	"type",
+	"status",
+	"total",
+	"processed",
+	"error",
+	"create_at",
+	"update_at",
+}
+
+func (s *SQLStore) insertBulkOperation(db sq.BaseRunner, operation *model.BulkOperation) error {
+	now := utils.GetMillis()
+	if operation.CreateAt == 0 {
+		operation.CreateAt = now
+	}
+	operation.UpdateAt = now
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"bulk_operations").
+		Columns(bulkOperationFields...).
+		Values(
+			operation.ID,
+			operation.BoardID,
+			operation.UserID,
+			operation.Type,
+			operation.Status,
+			operation.Total,
+			operation.Processed,
+			operation.Error,
+			operation.CreateAt,
+			operation.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert bulk operation", mlog.String("board_id", operation.BoardID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// updateBulkOperation records the progress and the status of an
+// operation.
+func (s *SQLStore) updateBulkOperation(db sq.BaseRunner, operation *model.BulkOperation) error {
+	operation.UpdateAt = utils.GetMillis()
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"bulk_operations").
+		Set("status", operation.Status).
+		Set("total", operation.Total).
+		Set("processed", operation.Processed).
+		Set("error", operation.Error).
+		Set("update_at", operation.UpdateAt).
+		Where(sq.Eq{"id": operation.ID})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot update bulk operation", mlog.String("operation_id", operation.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getBulkOperation(db sq.BaseRunner, operationID string) (*model.BulkOperation, error) {
+	query := s.getQueryBuilder(db).
+		Select(bulkOperationFields...).
+		From(s.tablePrefix + "bulk_operations").
+		Where(sq.Eq{"id": operationID})
+
+	row := query.QueryRow()
+
+	var operation model.BulkOperation
+	var errorMessage sql.NullString
+	err := row.Scan(
+		&operation.ID,
+		&operation.BoardID,
+		&operation.UserID,
+		&operation.Type,
+		&operation.Status,
+		&operation.Total,
+		&operation.Processed,
+		&errorMessage,
+		&operation.CreateAt,
+		&operation.UpdateAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, model.NewErrNotFound("bulk operation ID=" + operationID)
+	}
+	if err != nil {
+		s.logger.Error("Cannot fetch bulk operation", mlog.String("operation_id", operationID), mlog.Err(err))
+		return nil, err
+	}
+	operation.Error = errorMessage.String
+	return &operation, nil
+}
+
+// failStaleBulkOperations marks as failed the running operations last
+// updated before the given time, and returns their number.
+func (s *SQLStore) failStaleBulkOperations(db sq.BaseRunner, updatedBefore int64, message string) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"bulk_operations").
+		Set("status", model.BulkOperationStatusFailed).
+		Set("error", message).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"status": model.BulkOperationStatusRunning}).
+		Where(sq.Lt{"update_at": updatedBefore})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot fail stale bulk operations", mlog.Err(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var cardDependencyFields = []string{
+	"blocker_id",
+	"blocked_id",
+	"created_by",
+	"create_at",
+}
+
+func (s *SQLStore) cardDependenciesFromRows(rows *sql.Rows) ([]*model.CardDependency, error) {
+	dependencies := []*model.CardDependency{}
+
+	for rows.Next() {
+		var dependency model.CardDependency
+		var createdBy sql.NullString
+		err := rows.Scan(
+			&dependency.BlockerID,
+			&dependency.BlockedID,
+			&createdBy,
+			&dependency.CreateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		dependency.CreatedBy = createdBy.String
+		dependencies = append(dependencies, &dependency)
+	}
+	return dependencies, nil
+}
+
+// addCardDependency stores a new dependency between two cards. Adding
+// an existing dependency is not considered an error.
+func (s *SQLStore) addCardDependency(db sq.BaseRunner, dependency *model.CardDependency) error {
+	if err := dependency.IsValid(); err != nil {
+		return err
+	}
+
+	if dependency.CreateAt == 0 {
+		dependency.CreateAt = model.GetMillis()
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"card_dependencies").
+		Columns(cardDependencyFields...).
+		Values(
+			dependency.BlockerID,
+			dependency.BlockedID,
+			dependency.CreatedBy,
+			dependency.CreateAt,
+		)
+
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE blocker_id = blocker_id")
+	} else {
+		query = query.Suffix("ON CONFLICT (blocker_id, blocked_id) DO NOTHING")
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot add card dependency",
+			mlog.String("blocker_id", dependency.BlockerID),
+			mlog.String("blocked_id", dependency.BlockedID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// deleteCardDependency removes the dependency between two cards.
+func (s *SQLStore) deleteCardDependency(db sq.BaseRunner, blockerID, blockedID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "card_dependencies").
+		Where(sq.Eq{"blocker_id": blockerID}).
+		Where(sq.Eq{"blocked_id": blockedID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		message := fmt.Sprintf("card dependency BlockerID=%s BlockedID=%s", blockerID, blockedID)
+		return model.NewErrNotFound(message)
+	}
+
+	return nil
+}
+
+// getCardDependencies returns the dependencies where the card is either
+// the blocker or the blocked one.
+func (s *SQLStore) getCardDependencies(db sq.BaseRunner, cardID string) ([]*model.CardDependency, error) {
+	query := s.getQueryBuilder(db).
+		Select(cardDependencyFields...).
+		From(s.tablePrefix+"card_dependencies").
+		Where(sq.Or{
+			sq.Eq{"blocker_id": cardID},
+			sq.Eq{"blocked_id": cardID},
+		}).
+		OrderBy("create_at", "blocker_id", "blocked_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch card dependencies", mlog.String("card_id", cardID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.cardDependenciesFromRows(rows)
+}
+
+// getCardDependenciesForBoard returns the dependencies where any of the
+// cards belongs to the board.
+func (s *SQLStore) getCardDependenciesForBoard(db sq.BaseRunner, boardID string) ([]*model.CardDependency, error) {
+	// the subquery keeps the question placeholders, they are replaced
+	// when the main query is built
+	boardCards := sq.
+		Select("id").
+		From(s.tablePrefix + "blocks").
+		Where(sq.Eq{"board_id": boardID}).
+		Where(sq.Eq{"type": model.TypeCard})
+
+	boardCardsSQL, boardCardsArgs, err := boardCards.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	args := append(append([]interface{}{}, boardCardsArgs...), boardCardsArgs...)
+	query := s.getQueryBuilder(db).
+		Select(cardDependencyFields...).
+		From(s.tablePrefix+"card_dependencies").
+		Where("blocker_id IN ("+boardCardsSQL+") OR blocked_id IN ("+boardCardsSQL+")", args...).
+		OrderBy("create_at", "blocker_id", "blocked_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch card dependencies for board", mlog.String("board_id", boardID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.cardDependenciesFromRows(rows)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+// moveCardBlocks inserts the copies of a moved card on the target board,
+// then archives or deletes the source card. It returns the patched target
+// board, or nil if the move doesn't patch it.
+func (s *SQLStore) moveCardBlocks(db sq.BaseRunner, move *model.CardMoveBlocks, userID string) (*model.Board, error) {
+	var target *model.Board
+	if move.TargetBoardPatch != nil {
+		var err error
+		if target, err = s.patchBoard(db, move.TargetBoardID, move.TargetBoardPatch, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.insertBlocks(db, move.Blocks, userID); err != nil {
+		return nil, err
+	}
+
+	if move.SourcePatch != nil {
+		if err := s.patchBlock(db, move.SourceCardID, move.SourcePatch, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if move.ArchiveSource {
+		if _, err := s.setBlockArchiveAt(db, move.SourceCardID, utils.GetMillis(), userID); err != nil {
+			return nil, err
+		}
+		return target, nil
+	}
+
+	if err := s.deleteBlock(db, move.SourceCardID, userID); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
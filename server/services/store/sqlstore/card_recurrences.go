@@ -0,0 +1,196 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var cardRecurrenceFields = []string{
+	"card_id",
+	"board_id",
+	"rule",
+	"date_property_id",
+	"created_by",
+	"start_at",
+	"next_run_at",
+	"last_run_at",
+	"create_at",
+	"update_at",
+}
+
+func (s *SQLStore) cardRecurrencesFromRows(rows *sql.Rows) ([]*model.CardRecurrence, error) {
+	recurrences := []*model.CardRecurrence{}
+
+	for rows.Next() {
+		var recurrence model.CardRecurrence
+		var datePropertyID sql.NullString
+		var lastRunAt sql.NullInt64
+		err := rows.Scan(
+			&recurrence.CardID,
+			&recurrence.BoardID,
+			&recurrence.Rule,
+			&datePropertyID,
+			&recurrence.CreatedBy,
+			&recurrence.StartAt,
+			&recurrence.NextRunAt,
+			&lastRunAt,
+			&recurrence.CreateAt,
+			&recurrence.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		recurrence.DatePropertyID = datePropertyID.String
+		recurrence.LastRunAt = lastRunAt.Int64
+		recurrences = append(recurrences, &recurrence)
+	}
+	return recurrences, nil
+}
+
+// upsertCardRecurrence creates or replaces the recurrence of a card.
+func (s *SQLStore) upsertCardRecurrence(db sq.BaseRunner, recurrence *model.CardRecurrence) error {
+	if err := recurrence.IsValid(); err != nil {
+		return err
+	}
+
+	now := utils.GetMillis()
+	if recurrence.CreateAt == 0 {
+		recurrence.CreateAt = now
+	}
+	recurrence.UpdateAt = now
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"card_recurrences").
+		Columns(cardRecurrenceFields...).
+		Values(
+			recurrence.CardID,
+			recurrence.BoardID,
+			recurrence.Rule,
+			recurrence.DatePropertyID,
+			recurrence.CreatedBy,
+			recurrence.StartAt,
+			recurrence.NextRunAt,
+			recurrence.LastRunAt,
+			recurrence.CreateAt,
+			recurrence.UpdateAt,
+		)
+
+	updates := "rule = ?, date_property_id = ?, created_by = ?, start_at = ?, next_run_at = ?, update_at = ?"
+	updateArgs := []interface{}{
+		recurrence.Rule,
+		recurrence.DatePropertyID,
+		recurrence.CreatedBy,
+		recurrence.StartAt,
+		recurrence.NextRunAt,
+		recurrence.UpdateAt,
+	}
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE "+updates, updateArgs...)
+	} else {
+		query = query.Suffix("ON CONFLICT (card_id) DO UPDATE SET "+updates, updateArgs...)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot upsert card recurrence", mlog.String("card_id", recurrence.CardID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// getCardRecurrence returns the recurrence of a card.
+func (s *SQLStore) getCardRecurrence(db sq.BaseRunner, cardID string) (*model.CardRecurrence, error) {
+	query := s.getQueryBuilder(db).
+		Select(cardRecurrenceFields...).
+		From(s.tablePrefix + "card_recurrences").
+		Where(sq.Eq{"card_id": cardID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch card recurrence", mlog.String("card_id", cardID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	recurrences, err := s.cardRecurrencesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(recurrences) == 0 {
+		return nil, model.NewErrNotFound("card recurrence CardID=" + cardID)
+	}
+	return recurrences[0], nil
+}
+
+// deleteCardRecurrence removes the recurrence of a card.
+func (s *SQLStore) deleteCardRecurrence(db sq.BaseRunner, cardID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "card_recurrences").
+		Where(sq.Eq{"card_id": cardID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return model.NewErrNotFound("card recurrence CardID=" + cardID)
+	}
+
+	return nil
+}
+
+// getDueCardRecurrences returns the recurrences with an occurrence at or
+// before the given time.
+func (s *SQLStore) getDueCardRecurrences(db sq.BaseRunner, now int64) ([]*model.CardRecurrence, error) {
+	query := s.getQueryBuilder(db).
+		Select(cardRecurrenceFields...).
+		From(s.tablePrefix+"card_recurrences").
+		Where(sq.LtOrEq{"next_run_at": now}).
+		OrderBy("next_run_at", "card_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch due card recurrences", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.cardRecurrencesFromRows(rows)
+}
+
+// claimCardRecurrence moves the next occurrence of a recurrence from
+// dueAt to nextRunAt, recording runAt as the last one. It returns false
+// if the occurrence was already claimed, e.g. by another server.
+func (s *SQLStore) claimCardRecurrence(db sq.BaseRunner, cardID string, dueAt, runAt, nextRunAt int64) (bool, error) {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"card_recurrences").
+		Set("next_run_at", nextRunAt).
+		Set("last_run_at", runAt).
+		Where(sq.Eq{"card_id": cardID}).
+		Where(sq.Eq{"next_run_at": dueAt})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot claim card recurrence", mlog.String("card_id", cardID), mlog.Err(err))
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
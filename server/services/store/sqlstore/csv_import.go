@@ -0,0 +1,33 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// importCSVBlocks patches the board with the options created by a CSV
+// import, inserts the created cards and patches the updated ones. It
+// returns the patched board, or nil if the import doesn't patch it.
+func (s *SQLStore) importCSVBlocks(db sq.BaseRunner, imp *model.CSVImportBlocks, userID string) (*model.Board, error) {
+	var board *model.Board
+	if imp.BoardPatch != nil {
+		var err error
+		if board, err = s.patchBoard(db, imp.BoardID, imp.BoardPatch, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.insertBlocks(db, imp.Blocks, userID); err != nil {
+		return nil, err
+	}
+
+	if imp.BlockPatches != nil {
+		if err := s.patchBlocks(db, imp.BlockPatches, userID); err != nil {
+			return nil, err
+		}
+	}
+	return board, nil
+}
@@ -12,6 +12,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	_ "github.com/lib/pq" // postgres driver
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -22,51 +23,87 @@ type RetentionTableDeletionInfo struct {
 	BoardIDColumn string
 }
 
+// dataRetentionPrimaryKeys are the keys used to delete the rows of a
+// board table in batches, when they are not "id".
+var dataRetentionPrimaryKeys = map[string][]string{
+	"board_members":            {"board_id"},
+	"board_members_history":    {"board_id"},
+	"card_recurrences":         {"card_id"},
+	"archived_property_values": {"board_id"},
+	"retention_policies":       {"board_id"},
+}
+
+// dataRetentionTables returns the tables holding the data of a board that
+// is deleted when the board expires, the same as when the board is purged
+// from the trash.
+func dataRetentionTables() []RetentionTableDeletionInfo {
+	tables := make([]RetentionTableDeletionInfo, 0, len(trashBoardTables))
+	for _, t := range trashBoardTables {
+		primaryKeys, ok := dataRetentionPrimaryKeys[t.table]
+		if !ok {
+			primaryKeys = []string{"id"}
+		}
+		tables = append(tables, RetentionTableDeletionInfo{
+			Table:         t.table,
+			PrimaryKeys:   primaryKeys,
+			BoardIDColumn: t.column,
+		})
+	}
+	return tables
+}
+
 func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64, batchSize int64) (int64, error) {
 	s.logger.Info("Start Boards Data Retention",
 		mlog.String("Global Retention Date", time.Unix(globalRetentionDate/1000, 0).String()),
 		mlog.Int("Raw Date", globalRetentionDate))
-	deleteTables := []RetentionTableDeletionInfo{
-		{
-			Table:         "blocks",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "board_id",
-		},
-		{
-			Table:         "blocks_history",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "board_id",
-		},
-		{
-			Table:         "boards",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "id",
-		},
-		{
-			Table:         "boards_history",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "id",
-		},
-		{
-			Table:         "board_members",
-			PrimaryKeys:   []string{"board_id"},
-			BoardIDColumn: "board_id",
-		},
-		{
-			Table:         "board_members_history",
-			PrimaryKeys:   []string{"board_id"},
-			BoardIDColumn: "board_id",
-		},
-		{
-			Table:         "sharing",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "id",
-		},
-		{
-			Table:         "category_boards",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "board_id",
-		},
+
+	candidates, err := s.getDataRetentionCandidates(db, globalRetentionDate, utils.GetMillis())
+	if err != nil {
+		return 0, err
+	}
+
+	deleteIds := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		deleteIds = append(deleteIds, candidate.BoardID)
+	}
+
+	totalAffected, err := s.deleteDataRetentionBoards(db, deleteIds, batchSize)
+	if err != nil {
+		return totalAffected, err
+	}
+	s.logger.Info("Complete Boards Data Retention",
+		mlog.Int("Total deletion ids", len(deleteIds)),
+		mlog.Int("TotalAffected", totalAffected))
+	return totalAffected, nil
+}
+
+// getDataRetentionCandidates returns the boards whose last change is older
+// than their retention date. The retention date of a board comes from its
+// board policy, else from its team policy, else it is the global one. A
+// zero global retention date keeps the boards without a policy.
+func (s *SQLStore) getDataRetentionCandidates(db sq.BaseRunner, globalRetentionDate int64, now int64) ([]*model.RetentionCandidate, error) {
+	policies, err := s.getRetentionPolicies(db, "")
+	if err != nil {
+		return nil, err
+	}
+
+	teamPolicies := map[string]*model.RetentionPolicy{}
+	boardPolicies := map[string]*model.RetentionPolicy{}
+	latestRetentionDate := globalRetentionDate
+	for _, policy := range policies {
+		if policy.BoardID != "" {
+			boardPolicies[policy.BoardID] = policy
+		} else {
+			teamPolicies[policy.TeamID] = policy
+		}
+		if date := policy.RetentionDate(now); date > latestRetentionDate {
+			latestRetentionDate = date
+		}
+	}
+
+	candidates := []*model.RetentionCandidate{}
+	if latestRetentionDate == 0 {
+		return candidates, nil
 	}
 
 	subBuilder := s.getQueryBuilder(db).
@@ -77,38 +114,119 @@ func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64,
 	subQuery, _, _ := subBuilder.ToSql()
 
 	builder := s.getQueryBuilder(db).
-		Select("id").
+		Select("id", "team_id", "COALESCE(title, '')", "maxDate").
 		From(s.tablePrefix + "boards").
 		LeftJoin("( " + subQuery + " ) As subquery ON (subquery.board_id = id)").
-		Where(sq.Lt{"maxDate": globalRetentionDate}).
+		Where(sq.Lt{"maxDate": latestRetentionDate}).
 		Where(sq.NotEq{"team_id": "0"}).
-		Where(sq.Eq{"is_template": false})
+		Where(sq.Eq{"is_template": false}).
+		OrderBy("maxDate")
 
 	rows, err := builder.Query()
 	if err != nil {
 		s.logger.Error(`dataRetention subquery ERROR`, mlog.Err(err))
-		return 0, err
+		return nil, err
 	}
 	defer s.CloseRows(rows)
-	deleteIds, err := idsFromRows(rows)
+
+	for rows.Next() {
+		var candidate model.RetentionCandidate
+		if err := rows.Scan(&candidate.BoardID, &candidate.TeamID, &candidate.Title, &candidate.LastUpdateAt); err != nil {
+			return nil, err
+		}
+
+		candidate.Scope = model.RetentionScopeGlobal
+		candidate.RetentionDate = globalRetentionDate
+		policy, ok := boardPolicies[candidate.BoardID]
+		if !ok {
+			policy, ok = teamPolicies[candidate.TeamID]
+		}
+		if ok {
+			candidate.Scope = policy.Scope()
+			candidate.RetentionDate = policy.RetentionDate(now)
+		}
+
+		if candidate.LastUpdateAt < candidate.RetentionDate {
+			candidates = append(candidates, &candidate)
+		}
+	}
+	return candidates, nil
+}
+
+// deleteDataRetentionBoards deletes the data of expired boards.
+func (s *SQLStore) deleteDataRetentionBoards(db sq.BaseRunner, boardIDs []string, batchSize int64) (int64, error) {
+	var totalAffected int64
+	if len(boardIDs) == 0 {
+		return 0, nil
+	}
+
+	// the card data is found through the blocks, so it goes first
+	cardIDs, err := s.getDataRetentionCardIDs(db, boardIDs)
 	if err != nil {
 		return 0, err
 	}
+	totalAffected, err = s.deleteDataRetentionCards(db, cardIDs, batchSize)
+	if err != nil {
+		return totalAffected, err
+	}
+
+	for _, table := range dataRetentionTables() {
+		affected, err := s.genericRetentionPoliciesDeletion(db, table, boardIDs, batchSize)
+		if err != nil {
+			return totalAffected, err
+		}
+		totalAffected += affected
+	}
+	return totalAffected, nil
+}
+
+// getDataRetentionCardIDs returns the ids of all the blocks of the boards,
+// including the deleted ones.
+func (s *SQLStore) getDataRetentionCardIDs(db sq.BaseRunner, boardIDs []string) ([]string, error) {
+	rows, err := s.getQueryBuilder(db).
+		Select("DISTINCT id").
+		From(s.tablePrefix + "blocks_history").
+		Where(sq.Eq{"board_id": boardIDs}).
+		Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch the blocks of the expired boards", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
 
-	totalAffected := 0
-	if len(deleteIds) > 0 {
-		for _, table := range deleteTables {
-			affected, err := s.genericRetentionPoliciesDeletion(db, table, deleteIds, batchSize)
-			if err != nil {
-				return int64(totalAffected), err
+	return idsFromRows(rows)
+}
+
+// deleteDataRetentionCards deletes the data of the cards of expired
+// boards held in the tables without a board column, batchSize cards at
+// a time.
+func (s *SQLStore) deleteDataRetentionCards(db sq.BaseRunner, cardIDs []string, batchSize int64) (int64, error) {
+	var totalAffected int64
+	for len(cardIDs) > 0 {
+		batch := cardIDs
+		if batchSize > 0 && int64(len(batch)) > batchSize {
+			batch = cardIDs[:batchSize]
+		}
+		cardIDs = cardIDs[len(batch):]
+
+		for _, t := range trashCardTables {
+			for _, column := range t.columns {
+				result, err := s.getQueryBuilder(db).
+					Delete(s.tablePrefix + t.table).
+					Where(sq.Eq{column: batch}).
+					Exec()
+				if err != nil {
+					return totalAffected, errors.Wrap(err, "failed to delete "+t.table)
+				}
+				affected, err := result.RowsAffected()
+				if err != nil {
+					return totalAffected, errors.Wrap(err, "failed to get rows affected for "+t.table)
+				}
+				totalAffected += affected
 			}
-			totalAffected += int(affected)
 		}
 	}
-	s.logger.Info("Complete Boards Data Retention",
-		mlog.Int("Total deletion ids", len(deleteIds)),
-		mlog.Int("TotalAffected", totalAffected))
-	return int64(totalAffected), nil
+	return totalAffected, nil
 }
 
 func idsFromRows(rows *sql.Rows) ([]string, error) {
@@ -170,7 +288,7 @@ func (s *SQLStore) genericRetentionPoliciesDeletion(
 			return 0, errors.Wrap(err, "failed to get rows affected for "+info.Table)
 		}
 		totalRowsAffected += batchRowsAffected
-		if batchRowsAffected != batchSize {
+		if batchSize <= 0 || batchRowsAffected != batchSize {
 			break
 		}
 	}
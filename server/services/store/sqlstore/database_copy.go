@@ -0,0 +1,270 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// databaseCopyDoneKeyPrefix prefixes the system settings of the target
+// database that record the tables already copied, so an interrupted
+// copy can resume.
+const databaseCopyDoneKeyPrefix = "DatabaseCopyDone_"
+
+const defaultDatabaseCopyBatchSize = 500
+
+var ErrDatabaseCopyTargetNotEmpty = errors.New("the target database already has boards and no copy to resume")
+
+// databaseCopyOrder lists the tables in the order they are copied, so the
+// rows other tables refer to are copied first. The tables that are not
+// listed are copied afterwards, by name.
+var databaseCopyOrder = []string{
+	"boards",
+	"blocks",
+	"boards_history",
+	"blocks_history",
+	"board_members",
+	"board_members_history",
+	"sessions",
+	"categories",
+	"category_boards",
+	"file_info",
+	"subscriptions",
+	"preferences",
+}
+
+// databaseCopyTables sorts the tables in the copy order.
+func databaseCopyTables(tables []string) []string {
+	rank := make(map[string]int, len(databaseCopyOrder))
+	for i, table := range databaseCopyOrder {
+		rank[table] = i
+	}
+
+	sorted := append([]string{}, tables...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, iOK := rank[sorted[i]]
+		rj, jOK := rank[sorted[j]]
+		switch {
+		case iOK && jOK:
+			return ri < rj
+		case iOK != jOK:
+			return iOK
+		default:
+			return sorted[i] < sorted[j]
+		}
+	})
+	return sorted
+}
+
+// CopyDatabase copies the content of the source store into the target
+// store, whatever their database types and table prefixes. Both stores
+// must have run their migrations up to the same schema version. Each
+// table is copied in batches of batchSize rows and its row count is
+// verified before it is recorded as done in the target, so running the
+// copy again after a failure resumes it from the first table not done.
+//
+// The source database must not be modified during the copy.
+func CopyDatabase(source, target *SQLStore, batchSize int) ([]*model.DatabaseCopyTable, error) {
+	if batchSize <= 0 {
+		batchSize = defaultDatabaseCopyBatchSize
+	}
+
+	sourceVersion, err := source.getSchemaVersion(source.db)
+	if err != nil {
+		return nil, err
+	}
+	targetVersion, err := target.getSchemaVersion(target.db)
+	if err != nil {
+		return nil, err
+	}
+	if sourceVersion != targetVersion {
+		return nil, fmt.Errorf("the source schema version %d differs from the target schema version %d", sourceVersion, targetVersion)
+	}
+
+	tables, err := source.getBackupTables(source.db)
+	if err != nil {
+		return nil, err
+	}
+	targetTables, err := target.getBackupTables(target.db)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(targetTables))
+	for _, table := range targetTables {
+		existing[table] = true
+	}
+	for _, table := range tables {
+		if !existing[table] {
+			return nil, fmt.Errorf("the table %s does not exist in the target database", table)
+		}
+	}
+
+	settings, err := target.getSystemSettings(target.db)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDatabaseCopyTarget(target, settings); err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.DatabaseCopyTable, 0, len(tables))
+	for _, table := range databaseCopyTables(tables) {
+		result := &model.DatabaseCopyTable{Table: table}
+		results = append(results, result)
+
+		if done, ok := settings[databaseCopyDoneKeyPrefix+table]; ok {
+			count, _ := strconv.ParseInt(done, 10, 64)
+			result.SourceRows = count
+			result.TargetRows = count
+			result.Resumed = true
+			continue
+		}
+
+		if err := copyDatabaseTable(source, target, table, batchSize, result); err != nil {
+			return results, fmt.Errorf("cannot copy table %s: %w", table, err)
+		}
+
+		if err := target.setSystemSetting(target.db, databaseCopyDoneKeyPrefix+table, strconv.FormatInt(result.TargetRows, 10)); err != nil {
+			return results, err
+		}
+		target.logger.Info("Table copied", mlog.String("table", table), mlog.Int("rows", result.TargetRows))
+	}
+	return results, nil
+}
+
+// checkDatabaseCopyTarget ensures that the target database is empty,
+// unless a previous copy is being resumed.
+func checkDatabaseCopyTarget(target *SQLStore, settings map[string]string) error {
+	for id := range settings {
+		if strings.HasPrefix(id, databaseCopyDoneKeyPrefix) {
+			return nil
+		}
+	}
+
+	count, err := target.countDatabaseCopyRows(target.db, "boards")
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDatabaseCopyTargetNotEmpty
+	}
+	return nil
+}
+
+// databaseCopyRowsFilter excludes the copy progress from the system
+// settings, so it is neither copied nor cleared.
+func databaseCopyRowsFilter(table string) sq.Sqlizer {
+	if table != "system_settings" {
+		return sq.Expr("1 = 1")
+	}
+	return sq.NotLike{"id": databaseCopyDoneKeyPrefix + "%"}
+}
+
+func (s *SQLStore) countDatabaseCopyRows(db sq.BaseRunner, table string) (int64, error) {
+	var count int64
+	err := s.getQueryBuilder(db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + table).
+		Where(databaseCopyRowsFilter(table)).
+		QueryRow().
+		Scan(&count)
+	return count, err
+}
+
+// copyDatabaseTable replaces the rows of a table of the target with the
+// ones of the source, then verifies that both have the same row count.
+func copyDatabaseTable(source, target *SQLStore, table string, batchSize int, result *model.DatabaseCopyTable) error {
+	// a table left half copied by an interrupted run is copied again
+	if _, err := target.getQueryBuilder(target.db).
+		Delete(target.tablePrefix + table).
+		Where(databaseCopyRowsFilter(table)).
+		Exec(); err != nil {
+		return err
+	}
+
+	rows, err := source.getQueryBuilder(source.db).
+		Select("*").
+		From(source.tablePrefix + table).
+		Where(databaseCopyRowsFilter(table)).
+		Query()
+	if err != nil {
+		return err
+	}
+	defer source.CloseRows(rows)
+
+	batch := make([]map[string]interface{}, 0, batchSize)
+	_, err = readRows(rows, func(row map[string]interface{}) error {
+		batch = append(batch, row)
+		if len(batch) < batchSize {
+			return nil
+		}
+		if err := target.insertDatabaseCopyBatch(table, batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		if err := target.insertDatabaseCopyBatch(table, batch); err != nil {
+			return err
+		}
+	}
+
+	if result.SourceRows, err = source.countDatabaseCopyRows(source.db, table); err != nil {
+		return err
+	}
+	if result.TargetRows, err = target.countDatabaseCopyRows(target.db, table); err != nil {
+		return err
+	}
+	if result.SourceRows != result.TargetRows {
+		return fmt.Errorf("the target has %d rows instead of %d", result.TargetRows, result.SourceRows)
+	}
+	return nil
+}
+
+// insertDatabaseCopyBatch inserts a batch of rows with a single statement.
+func (s *SQLStore) insertDatabaseCopyBatch(table string, batch []map[string]interface{}) error {
+	_, err := s.databaseCopyInsert(table, batch).Exec()
+	return err
+}
+
+// databaseCopyInsert returns the insert of a batch of rows, with the
+// columns of the first row. The columns are escaped as some are reserved
+// words, like schema for MySQL.
+func (s *SQLStore) databaseCopyInsert(table string, batch []map[string]interface{}) sq.InsertBuilder {
+	columns := make([]string, 0, len(batch[0]))
+	for column := range batch[0] {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	escaped := make([]string, len(columns))
+	for i, column := range columns {
+		escaped[i] = s.escapeField(column)
+	}
+
+	query := s.getQueryBuilder(s.db).
+		Insert(s.tablePrefix + table).
+		Columns(escaped...)
+	for _, row := range batch {
+		values := make([]interface{}, len(columns))
+		for i, column := range columns {
+			values[i] = row[column]
+		}
+		query = query.Values(values...)
+	}
+	return query
+}
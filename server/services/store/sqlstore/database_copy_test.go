@@ -0,0 +1,166 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func setupDatabaseCopyTarget(t *testing.T, prefix string) *SQLStore {
+	connectionString := filepath.Join(t.TempDir(), "target.db")
+	sqlDB, err := sql.Open(model.SqliteDBType, connectionString)
+	require.NoError(t, err)
+
+	target, err := New(Params{
+		DBType:           model.SqliteDBType,
+		ConnectionString: connectionString,
+		DBPingAttempts:   5,
+		TablePrefix:      prefix,
+		Logger:           mlog.CreateConsoleTestLogger(t),
+		DB:               sqlDB,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = target.Shutdown() })
+	return target
+}
+
+func TestCopyDatabase(t *testing.T) {
+	store, tearDown := SetupTests(t)
+	defer tearDown()
+	source := store.(*SQLStore)
+
+	board, _, err := source.InsertBoardWithAdmin(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: "team-id",
+		Type:   model.BoardTypeOpen,
+		Title:  "copied board",
+	}, "user-id")
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, source.InsertBlock(&model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Title:    "copied card",
+			Fields:   map[string]interface{}{"isTemplate": false},
+		}, "user-id"))
+	}
+
+	t.Run("copy to another prefix", func(t *testing.T) {
+		target := setupDatabaseCopyTarget(t, "copy_")
+
+		results, err := CopyDatabase(source, target, 2)
+		require.NoError(t, err)
+
+		tables, err := source.getBackupTables(source.db)
+		require.NoError(t, err)
+		require.Len(t, results, len(tables))
+		require.Equal(t, "boards", results[0].Table)
+		require.Equal(t, "blocks", results[1].Table)
+		for _, result := range results {
+			require.False(t, result.Resumed)
+			require.Equal(t, result.SourceRows, result.TargetRows, result.Table)
+		}
+
+		copied, err := target.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, "copied board", copied.Title)
+
+		blocks, err := target.GetBlocksForBoard(board.ID)
+		require.NoError(t, err)
+		require.Len(t, blocks, 5)
+
+		members, err := target.GetMembersForBoard(board.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+	})
+
+	t.Run("resume an interrupted copy", func(t *testing.T) {
+		target := setupDatabaseCopyTarget(t, "")
+
+		_, err := CopyDatabase(source, target, 100)
+		require.NoError(t, err)
+
+		// simulate a run interrupted while copying the blocks
+		_, err = target.db.Exec("DELETE FROM system_settings WHERE id = ?", databaseCopyDoneKeyPrefix+"blocks")
+		require.NoError(t, err)
+		_, err = target.db.Exec("DELETE FROM blocks WHERE id IN (SELECT id FROM blocks LIMIT 2)")
+		require.NoError(t, err)
+
+		results, err := CopyDatabase(source, target, 100)
+		require.NoError(t, err)
+		for _, result := range results {
+			require.Equal(t, result.Table != "blocks", result.Resumed, result.Table)
+			require.Equal(t, result.SourceRows, result.TargetRows, result.Table)
+		}
+
+		blocks, err := target.GetBlocksForBoard(board.ID)
+		require.NoError(t, err)
+		require.Len(t, blocks, 5)
+	})
+
+	t.Run("the target must be empty", func(t *testing.T) {
+		target := setupDatabaseCopyTarget(t, "")
+		_, _, err := target.InsertBoardWithAdmin(&model.Board{
+			ID:     utils.NewID(utils.IDTypeBoard),
+			TeamID: "team-id",
+			Type:   model.BoardTypeOpen,
+		}, "user-id")
+		require.NoError(t, err)
+
+		_, err = CopyDatabase(source, target, 100)
+		require.ErrorIs(t, err, ErrDatabaseCopyTargetNotEmpty)
+	})
+
+	t.Run("copy a column named with a reserved word", func(t *testing.T) {
+		target := setupDatabaseCopyTarget(t, "reserved_")
+
+		createTable := func(store *SQLStore) {
+			_, err := store.db.Exec(fmt.Sprintf("CREATE TABLE %scopy_reserved (id VARCHAR(36) PRIMARY KEY, %s INTEGER)",
+				store.tablePrefix, store.escapeField("order")))
+			require.NoError(t, err)
+		}
+		createTable(source)
+		createTable(target)
+		_, err := source.getQueryBuilder(source.db).
+			Insert(source.tablePrefix+"copy_reserved").
+			Columns("id", source.escapeField("order")).
+			Values("row-1", 7).
+			Exec()
+		require.NoError(t, err)
+
+		_, err = CopyDatabase(source, target, 100)
+		require.NoError(t, err)
+
+		var order int
+		require.NoError(t, target.db.QueryRow(`SELECT "order" FROM reserved_copy_reserved WHERE id = 'row-1'`).Scan(&order))
+		require.Equal(t, 7, order)
+	})
+}
+
+func TestDatabaseCopyInsert(t *testing.T) {
+	batch := []map[string]interface{}{
+		{"id": "block-1", "schema": 1},
+		{"id": "block-2", "schema": 1},
+	}
+
+	mysql := &SQLStore{dbType: model.MysqlDBType, tablePrefix: "focalboard_"}
+	query, args, err := mysql.databaseCopyInsert("blocks", batch).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO focalboard_blocks (`id`,`schema`) VALUES (?,?),(?,?)", query)
+	require.Equal(t, []interface{}{"block-1", 1, "block-2", 1}, args)
+
+	postgres := &SQLStore{dbType: model.PostgresDBType}
+	query, _, err = postgres.databaseCopyInsert("blocks", batch).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO blocks ("id","schema") VALUES ($1,$2),($3,$4)`, query)
+}
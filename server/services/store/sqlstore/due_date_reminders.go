@@ -0,0 +1,118 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// addDueDateReminder records a due date reminder as sent. It returns
+// false if the reminder was already recorded, e.g. by another server.
+func (s *SQLStore) addDueDateReminder(db sq.BaseRunner, reminder *model.DueDateReminder) (bool, error) {
+	if err := reminder.IsValid(); err != nil {
+		return false, err
+	}
+
+	if reminder.SentAt == 0 {
+		reminder.SentAt = utils.GetMillis()
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"due_date_reminders").
+		Columns("card_id", "property_id", "due_at", "lead_time", "sent_at").
+		Values(reminder.CardID, reminder.PropertyID, reminder.DueAt, reminder.LeadTime, reminder.SentAt)
+
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE card_id = card_id")
+	} else {
+		query = query.Suffix("ON CONFLICT (card_id, property_id, due_at, lead_time) DO NOTHING")
+	}
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot add due date reminder",
+			mlog.String("card_id", reminder.CardID),
+			mlog.String("property_id", reminder.PropertyID),
+			mlog.Err(err),
+		)
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// deleteDueDateRemindersBefore removes the reminders sent before the
+// given time, as they cannot be sent again once their date is past.
+func (s *SQLStore) deleteDueDateRemindersBefore(db sq.BaseRunner, sentAt int64) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "due_date_reminders").
+		Where(sq.Lt{"sent_at": sentAt})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot delete due date reminders", mlog.Err(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// getCardsWithDatesBetween returns the cards of a board with a value of
+// one of the date properties starting or ending between from and to,
+// both included. The due dates are checked once the cards are loaded.
+func (s *SQLStore) getCardsWithDatesBetween(db sq.BaseRunner, boardID string, propertyIDs []string, from, to int64) ([]*model.Block, error) {
+	if len(propertyIDs) == 0 {
+		return []*model.Block{}, nil
+	}
+
+	conditions := sq.Or{}
+	for _, propertyID := range propertyIDs {
+		for _, key := range []string{"from", "to"} {
+			conditions = append(conditions, s.cardDateBetween(propertyID, key, from, to))
+		}
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("")...).
+		From(s.tablePrefix + "blocks").
+		Where(sq.Eq{"board_id": boardID}).
+		Where(sq.Eq{"type": model.TypeCard}).
+		Where(conditions)
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getCardsWithDatesBetween ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.blocksFromRows(rows)
+}
+
+// cardDateBetween returns the condition on a key of the date property
+// values of the cards, which are JSON encoded strings. Values that are
+// not valid JSON don't match.
+func (s *SQLStore) cardDateBetween(propertyID, key string, from, to int64) sq.Sqlizer {
+	path := fmt.Sprintf(`$.properties."%s"`, propertyID)
+	switch s.dbType {
+	case model.PostgresDBType:
+		pattern := fmt.Sprintf(`"%s"\s*:\s*(\d+)`, key)
+		return sq.Expr("CAST(substring(fields->'properties'->>? from ?) AS BIGINT) BETWEEN ? AND ?", propertyID, pattern, from, to)
+	case model.MysqlDBType:
+		value := "JSON_UNQUOTE(JSON_EXTRACT(fields, ?))"
+		return sq.Expr("CASE WHEN JSON_VALID("+value+") THEN JSON_EXTRACT("+value+", ?) END BETWEEN ? AND ?", path, path, "$."+key, from, to)
+	default:
+		value := "json_extract(fields, ?)"
+		return sq.Expr("CASE WHEN json_valid("+value+") THEN json_extract("+value+", ?) END BETWEEN ? AND ?", path, path, "$."+key, from, to)
+	}
+}
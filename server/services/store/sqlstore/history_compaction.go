@@ -0,0 +1,180 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const historyCompactionBatchSize = 500
+
+// historyCompactionTables are the history tables that are compacted.
+var historyCompactionTables = []string{"blocks_history", "boards_history"}
+
+// historyEntry is the part of a history row needed to compact it. The
+// key holds the insert_at value as returned by the driver, so it can be
+// used to delete the row.
+type historyEntry struct {
+	id         string
+	modifiedBy string
+	updateAt   int64
+	deleteAt   int64
+	key        interface{}
+}
+
+// historyKeyField returns the insert_at column in a form that can be
+// compared with itself. SQLite stores it as text, which the driver would
+// otherwise parse as a time with a different format.
+func (s *SQLStore) historyKeyField() string {
+	if s.dbType == model.SqliteDBType {
+		return "insert_at || ''"
+	}
+	return "insert_at"
+}
+
+// compactHistory merges the consecutive history entries of each block and
+// board made by the same user within window milliseconds into the last of
+// them. The first and last entries of each history and the deletions are
+// always kept, as well as the entries updated after before. It returns the
+// number of rows removed by table.
+func (s *SQLStore) compactHistory(db sq.BaseRunner, window int64, before int64) (map[string]int64, error) {
+	removed := map[string]int64{}
+	if window <= 0 {
+		return removed, nil
+	}
+
+	for _, table := range historyCompactionTables {
+		count, err := s.compactHistoryTable(db, table, window, before)
+		if err != nil {
+			s.logger.Error("Cannot compact history", mlog.String("table", table), mlog.Err(err))
+			return nil, err
+		}
+		removed[table] = count
+	}
+	return removed, nil
+}
+
+func (s *SQLStore) compactHistoryTable(db sq.BaseRunner, table string, window int64, before int64) (int64, error) {
+	// only the histories with entries between the first and the last one
+	// can be compacted
+	rows, err := s.getQueryBuilder(db).
+		Select("id").
+		From(s.tablePrefix + table).
+		Where(sq.Lt{"update_at": before}).
+		GroupBy("id").
+		Having("COUNT(*) > 2").
+		Query()
+	if err != nil {
+		return 0, err
+	}
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			s.CloseRows(rows)
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	s.CloseRows(rows)
+
+	var removed int64
+	for start := 0; start < len(ids); start += historyCompactionBatchSize {
+		end := start + historyCompactionBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		entries, err := s.getHistoryEntries(db, table, ids[start:end])
+		if err != nil {
+			return removed, err
+		}
+
+		for _, entry := range compactableHistoryEntries(entries, window, before) {
+			result, err := s.getQueryBuilder(db).
+				Delete(s.tablePrefix + table).
+				Where(sq.Eq{"id": entry.id}).
+				Where(sq.Eq{"insert_at": entry.key}).
+				Exec()
+			if err != nil {
+				return removed, err
+			}
+			count, err := result.RowsAffected()
+			if err != nil {
+				return removed, err
+			}
+			removed += count
+		}
+	}
+	return removed, nil
+}
+
+// getHistoryEntries returns the history entries of the ids, grouped by id
+// and from the oldest to the newest.
+func (s *SQLStore) getHistoryEntries(db sq.BaseRunner, table string, ids []string) ([]historyEntry, error) {
+	rows, err := s.getQueryBuilder(db).
+		Select("id", "COALESCE(modified_by, '')", "COALESCE(update_at, 0)", "COALESCE(delete_at, 0)", s.historyKeyField()).
+		From(s.tablePrefix+table).
+		Where(sq.Eq{"id": ids}).
+		OrderBy("id", "insert_at", "update_at").
+		Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	entries := []historyEntry{}
+	for rows.Next() {
+		var entry historyEntry
+		if err := rows.Scan(&entry.id, &entry.modifiedBy, &entry.updateAt, &entry.deleteAt, &entry.key); err != nil {
+			return nil, err
+		}
+		if b, ok := entry.key.([]byte); ok {
+			entry.key = string(b)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// compactableHistoryEntries returns the entries to remove from histories
+// sorted by id and from the oldest to the newest entry. An entry is
+// removed when the next one of the same history comes from the same user
+// within the window started by the first entry of their run of changes.
+func compactableHistoryEntries(entries []historyEntry, window int64, before int64) []historyEntry {
+	compactable := []historyEntry{}
+
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && entries[end].id == entries[start].id {
+			end++
+		}
+		history := entries[start:end]
+		start = end
+
+		runStart := history[0].updateAt
+		for i := 0; i < len(history)-1; i++ {
+			current, next := history[i], history[i+1]
+			if current.modifiedBy == next.modifiedBy &&
+				current.deleteAt == 0 &&
+				next.deleteAt == 0 &&
+				next.updateAt < before &&
+				next.updateAt-runStart <= window {
+				// the first state of the history is always kept
+				if i > 0 {
+					compactable = append(compactable, current)
+				}
+				continue
+			}
+			// the current entry closes its run and the next one opens a
+			// new run
+			runStart = next.updateAt
+		}
+	}
+	return compactable
+}
@@ -38,6 +38,7 @@ const (
 	deDuplicateCategoryBoards                = 35
 
 	tempSchemaMigrationTableName = "temp_schema_migration"
+	sqliteMigrationsTableName    = "db_migrations"
 )
 
 // migrations in MySQL need to run with the multiStatements flag
@@ -247,6 +248,31 @@ func (s *SQLStore) runMigrationSequence(engine *morph.Morph, driver drivers.Driv
 	return nil
 }
 
+// migrationsTableName returns the name of the table where the migration
+// engine records the applied migrations. The SQLite driver doesn't
+// receive the engine options, so it uses the default name.
+func (s *SQLStore) migrationsTableName() string {
+	if s.dbType == model.SqliteDBType {
+		return sqliteMigrationsTableName
+	}
+	return s.tablePrefix + "schema_migrations"
+}
+
+// getSchemaVersion returns the version of the last migration applied to
+// the database.
+func (s *SQLStore) getSchemaVersion(db sq.BaseRunner) (int, error) {
+	query := s.getQueryBuilder(db).
+		Select("COALESCE(MAX(version), 0)").
+		From(s.migrationsTableName())
+
+	var version int
+	if err := query.QueryRow().Scan(&version); err != nil {
+		s.logger.Error("Cannot fetch the schema version", mlog.Err(err))
+		return 0, err
+	}
+	return version, nil
+}
+
 func (s *SQLStore) ensureMigrationsAppliedUpToVersion(engine *morph.Morph, driver drivers.Driver, version int) error {
 	applied, err := driver.AppliedMigrations()
 	if err != nil {
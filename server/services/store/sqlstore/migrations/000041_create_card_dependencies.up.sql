@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}card_dependencies (
+    blocker_id VARCHAR(36) NOT NULL,
+    blocked_id VARCHAR(36) NOT NULL,
+    created_by VARCHAR(36),
+    create_at BIGINT,
+    PRIMARY KEY (blocker_id, blocked_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "card_dependencies" "blocked_id" }}
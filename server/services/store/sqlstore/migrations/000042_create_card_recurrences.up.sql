@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}card_recurrences (
+    card_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    rule VARCHAR(255) NOT NULL,
+    date_property_id VARCHAR(36),
+    created_by VARCHAR(36) NOT NULL,
+    start_at BIGINT NOT NULL,
+    next_run_at BIGINT NOT NULL,
+    last_run_at BIGINT,
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (card_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "card_recurrences" "next_run_at" }}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}due_date_reminders (
+    card_id VARCHAR(36) NOT NULL,
+    property_id VARCHAR(36) NOT NULL,
+    due_at BIGINT NOT NULL,
+    lead_time INT NOT NULL,
+    sent_at BIGINT,
+    PRIMARY KEY (card_id, property_id, due_at, lead_time)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "due_date_reminders" "sent_at" }}
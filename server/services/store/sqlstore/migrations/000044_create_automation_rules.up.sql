@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}automation_rules (
+    id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    title VARCHAR(255),
+    enabled BOOLEAN,
+    trigger_type VARCHAR(36) NOT NULL,
+    trigger_data {{if .postgres}}JSON{{else}}TEXT{{end}},
+    actions {{if .postgres}}JSON{{else}}TEXT{{end}},
+    created_by VARCHAR(36) NOT NULL,
+    modified_by VARCHAR(36) NOT NULL,
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "automation_rules" "board_id" }}
+{{ createIndexIfNeeded "automation_rules" "trigger_type" }}
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}automation_executions (
+    id VARCHAR(36) NOT NULL,
+    rule_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NOT NULL,
+    trigger_key VARCHAR(100),
+    status VARCHAR(36) NOT NULL,
+    results {{if .postgres}}JSON{{else}}TEXT{{end}},
+    create_at BIGINT,
+    PRIMARY KEY (id),
+    UNIQUE (rule_id, card_id, trigger_key)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{ createIndexIfNeeded "automation_executions" "rule_id, create_at" }}
+{{ createIndexIfNeeded "automation_executions" "create_at" }}
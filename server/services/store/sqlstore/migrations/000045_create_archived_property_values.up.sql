@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}archived_property_values (
+    board_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NOT NULL,
+    property_id VARCHAR(36) NOT NULL,
+    value {{if .postgres}}JSON{{else}}TEXT{{end}},
+    archived_by VARCHAR(36) NOT NULL,
+    archived_at BIGINT NOT NULL,
+    PRIMARY KEY (board_id, card_id, property_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "archived_property_values" "board_id, property_id" }}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}undo_log (
+    id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    operation VARCHAR(32) NOT NULL,
+    changes {{if .postgres}}JSON{{else}}TEXT{{end}},
+    create_at BIGINT NOT NULL,
+    undone_at BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "undo_log" "user_id, board_id" }}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}retention_policies (
+    team_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL DEFAULT '',
+    retention_days INTEGER NOT NULL,
+    modified_by VARCHAR(36),
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (team_id, board_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "retention_policies" "board_id" }}
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}audit_records (
+    id VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    level VARCHAR(32),
+    event VARCHAR(128),
+    status VARCHAR(32),
+    user_id VARCHAR(36),
+    session_id VARCHAR(36),
+    client TEXT,
+    ip_address VARCHAR(64),
+    api_path TEXT,
+    team_id VARCHAR(36),
+    board_id VARCHAR(36),
+    meta {{if .postgres}}JSON{{else}}TEXT{{end}},
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "audit_records" "create_at" }}
+{{ createIndexIfNeeded "audit_records" "user_id" }}
+{{ createIndexIfNeeded "audit_records" "event" }}
+{{ createIndexIfNeeded "audit_records" "board_id" }}
@@ -0,0 +1,5 @@
+{{- /* addColumnIfNeeded tableName columnName datatype constraint */ -}}
+{{ addColumnIfNeeded "blocks" "archive_at" "BIGINT" "NOT NULL DEFAULT 0"}}
+{{ addColumnIfNeeded "blocks_history" "archive_at" "BIGINT" "NOT NULL DEFAULT 0"}}
+{{ addColumnIfNeeded "boards" "archive_at" "BIGINT" "NOT NULL DEFAULT 0"}}
+{{ addColumnIfNeeded "boards_history" "archive_at" "BIGINT" "NOT NULL DEFAULT 0"}}
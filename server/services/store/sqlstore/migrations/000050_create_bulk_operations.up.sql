@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}bulk_operations (
+    id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    type VARCHAR(36) NOT NULL,
+    status VARCHAR(36) NOT NULL,
+    total INTEGER,
+    processed INTEGER,
+    error TEXT,
+    create_at BIGINT,
+    update_at BIGINT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "bulk_operations" "board_id" }}
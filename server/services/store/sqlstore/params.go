@@ -3,6 +3,7 @@ package sqlstore
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	mmModel "github.com/mattermost/mattermost/server/public/model"
 
@@ -27,6 +28,11 @@ type Params struct {
 	ServicesAPI      servicesAPI
 	SkipMigrations   bool
 	ConfigFn         func() *mmModel.Config
+
+	// ReplicaConnectionStrings are the DSNs of the read only replicas
+	// used to serve the read only methods of the store.
+	ReplicaConnectionStrings   []string
+	ReplicaHealthCheckInterval time.Duration
 }
 
 type ErrStoreParam struct {
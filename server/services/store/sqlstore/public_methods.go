@@ -8,7 +8,8 @@
 // prefix it with a @withTransaction comment if you need it to be
 // transactional and then add a private method in the store itself
 // with db sq.BaseRunner as the first parameter before running `make
-// generate`
+// generate`. Read only methods run against the replicas unless they
+// are annotated with @readFromPrimary.
 
 package sqlstore
 
@@ -22,6 +23,21 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
+func (s *SQLStore) AddAutomationExecution(execution *model.AutomationExecution) (bool, error) {
+	return s.addAutomationExecution(s.db, execution)
+
+}
+
+func (s *SQLStore) AddCardDependency(dependency *model.CardDependency) error {
+	return s.addCardDependency(s.db, dependency)
+
+}
+
+func (s *SQLStore) AddDueDateReminder(reminder *model.DueDateReminder) (bool, error) {
+	return s.addDueDateReminder(s.db, reminder)
+
+}
+
 func (s *SQLStore) AddUpdateCategoryBoard(userID string, categoryID string, boardIDs []string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.addUpdateCategoryBoard(s.db, userID, categoryID, boardIDs)
@@ -46,8 +62,42 @@ func (s *SQLStore) AddUpdateCategoryBoard(userID string, categoryID string, boar
 
 }
 
+func (s *SQLStore) ApplyUndoEntry(userID string, boardID string, redo bool, force bool) (*model.UndoResult, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.applyUndoEntry(s.db, userID, boardID, redo, force)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.applyUndoEntry(tx, userID, boardID, redo, force)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "ApplyUndoEntry"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) BackupDatabaseFile(path string) (map[string]int64, error) {
+	return s.backupDatabaseFile(s.db, path)
+
+}
+
 func (s *SQLStore) CanSeeUser(seerID string, seenID string) (bool, error) {
-	return s.canSeeUser(s.db, seerID, seenID)
+	return s.canSeeUser(s.replica(), seerID, seenID)
+
+}
+
+func (s *SQLStore) ClaimCardRecurrence(cardID string, dueAt int64, runAt int64, nextRunAt int64) (bool, error) {
+	return s.claimCardRecurrence(s.db, cardID, dueAt, runAt, nextRunAt)
 
 }
 
@@ -56,6 +106,11 @@ func (s *SQLStore) CleanUpSessions(expireTime int64) error {
 
 }
 
+func (s *SQLStore) CompactHistory(window int64, before int64) (map[string]int64, error) {
+	return s.compactHistory(s.db, window, before)
+
+}
+
 func (s *SQLStore) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.createBoardsAndBlocks(s.db, bab, userID)
@@ -143,6 +198,40 @@ func (s *SQLStore) CreateUser(user *model.User) (*model.User, error) {
 
 }
 
+func (s *SQLStore) DeleteAuditRecords(before int64) (int64, error) {
+	return s.deleteAuditRecords(s.db, before)
+
+}
+
+func (s *SQLStore) DeleteAutomationExecutionsBefore(createAt int64) (int64, error) {
+	return s.deleteAutomationExecutionsBefore(s.db, createAt)
+
+}
+
+func (s *SQLStore) DeleteAutomationRule(ruleID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteAutomationRule(s.db, ruleID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteAutomationRule(tx, ruleID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteAutomationRule"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) DeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.deleteBlock(s.db, blockID, modifiedBy)
@@ -225,11 +314,50 @@ func (s *SQLStore) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, user
 
 }
 
+func (s *SQLStore) DeleteCardDependency(blockerID string, blockedID string) error {
+	return s.deleteCardDependency(s.db, blockerID, blockedID)
+
+}
+
+func (s *SQLStore) DeleteCardRecurrence(cardID string) error {
+	return s.deleteCardRecurrence(s.db, cardID)
+
+}
+
 func (s *SQLStore) DeleteCategory(categoryID string, userID string, teamID string) error {
 	return s.deleteCategory(s.db, categoryID, userID, teamID)
 
 }
 
+func (s *SQLStore) DeleteDataRetentionBoards(boardIDs []string, batchSize int64) (int64, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteDataRetentionBoards(s.db, boardIDs, batchSize)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return 0, txErr
+	}
+	result, err := s.deleteDataRetentionBoards(tx, boardIDs, batchSize)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteDataRetentionBoards"))
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) DeleteDueDateRemindersBefore(sentAt int64) (int64, error) {
+	return s.deleteDueDateRemindersBefore(s.db, sentAt)
+
+}
+
 func (s *SQLStore) DeleteMember(boardID string, userID string) error {
 	return s.deleteMember(s.db, boardID, userID)
 
@@ -240,6 +368,11 @@ func (s *SQLStore) DeleteNotificationHint(blockID string) error {
 
 }
 
+func (s *SQLStore) DeleteRetentionPolicy(teamID string, boardID string) error {
+	return s.deleteRetentionPolicy(s.db, teamID, boardID)
+
+}
+
 func (s *SQLStore) DeleteSession(sessionID string) error {
 	return s.deleteSession(s.db, sessionID)
 
@@ -250,6 +383,11 @@ func (s *SQLStore) DeleteSubscription(blockID string, subscriberID string) error
 
 }
 
+func (s *SQLStore) DumpDatabase(w model.DatabaseDumpWriter) (map[string]int64, error) {
+	return s.dumpDatabase(s.db, w)
+
+}
+
 func (s *SQLStore) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.duplicateBlock(s.db, boardID, blockID, userID, asTemplate)
@@ -298,13 +436,48 @@ func (s *SQLStore) DuplicateBoard(boardID string, userID string, toTeam string,
 
 }
 
+func (s *SQLStore) FailStaleBulkOperations(updatedBefore int64, message string) (int64, error) {
+	return s.failStaleBulkOperations(s.db, updatedBefore, message)
+
+}
+
 func (s *SQLStore) GetActiveUserCount(updatedSecondsAgo int64) (int, error) {
-	return s.getActiveUserCount(s.db, updatedSecondsAgo)
+	return s.getActiveUserCount(s.replica(), updatedSecondsAgo)
 
 }
 
 func (s *SQLStore) GetAllTeams() ([]*model.Team, error) {
-	return s.getAllTeams(s.db)
+	return s.getAllTeams(s.replica())
+
+}
+
+func (s *SQLStore) GetArchivedPropertyValues(boardID string) ([]*model.ArchivedPropertyValue, error) {
+	return s.getArchivedPropertyValues(s.replica(), boardID)
+
+}
+
+func (s *SQLStore) GetAuditRecords(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+	return s.getAuditRecords(s.replica(), opts)
+
+}
+
+func (s *SQLStore) GetAutomationExecutions(ruleID string, limit uint64) ([]*model.AutomationExecution, error) {
+	return s.getAutomationExecutions(s.replica(), ruleID, limit)
+
+}
+
+func (s *SQLStore) GetAutomationRule(ruleID string) (*model.AutomationRule, error) {
+	return s.getAutomationRule(s.db, ruleID)
+
+}
+
+func (s *SQLStore) GetAutomationRulesForBoard(boardID string) ([]*model.AutomationRule, error) {
+	return s.getAutomationRulesForBoard(s.replica(), boardID)
+
+}
+
+func (s *SQLStore) GetBackupTables() ([]string, error) {
+	return s.getBackupTables(s.db)
 
 }
 
@@ -314,27 +487,27 @@ func (s *SQLStore) GetBlock(blockID string) (*model.Block, error) {
 }
 
 func (s *SQLStore) GetBlockCountsByType() (map[string]int64, error) {
-	return s.getBlockCountsByType(s.db)
+	return s.getBlockCountsByType(s.replica())
 
 }
 
 func (s *SQLStore) GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
-	return s.getBlockHistory(s.db, blockID, opts)
+	return s.getBlockHistory(s.replica(), blockID, opts)
 
 }
 
 func (s *SQLStore) GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
-	return s.getBlockHistoryDescendants(s.db, boardID, opts)
+	return s.getBlockHistoryDescendants(s.replica(), boardID, opts)
 
 }
 
 func (s *SQLStore) GetBlockHistoryNewestChildren(parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
-	return s.getBlockHistoryNewestChildren(s.db, parentID, opts)
+	return s.getBlockHistoryNewestChildren(s.replica(), parentID, opts)
 
 }
 
 func (s *SQLStore) GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error) {
-	return s.getBlocks(s.db, opts)
+	return s.getBlocks(s.replica(), opts)
 
 }
 
@@ -344,77 +517,107 @@ func (s *SQLStore) GetBlocksByIDs(ids []string) ([]*model.Block, error) {
 }
 
 func (s *SQLStore) GetBlocksComplianceHistory(opts model.QueryBlocksComplianceHistoryOptions) ([]*model.BlockHistory, bool, error) {
-	return s.getBlocksComplianceHistory(s.db, opts)
+	return s.getBlocksComplianceHistory(s.replica(), opts)
 
 }
 
 func (s *SQLStore) GetBlocksForBoard(boardID string) ([]*model.Block, error) {
-	return s.getBlocksForBoard(s.db, boardID)
+	return s.getBlocksForBoard(s.replica(), boardID)
 
 }
 
 func (s *SQLStore) GetBlocksWithParent(boardID string, parentID string) ([]*model.Block, error) {
-	return s.getBlocksWithParent(s.db, boardID, parentID)
+	return s.getBlocksWithParent(s.replica(), boardID, parentID)
 
 }
 
 func (s *SQLStore) GetBlocksWithParentAndType(boardID string, parentID string, blockType string) ([]*model.Block, error) {
-	return s.getBlocksWithParentAndType(s.db, boardID, parentID, blockType)
+	return s.getBlocksWithParentAndType(s.replica(), boardID, parentID, blockType)
 
 }
 
 func (s *SQLStore) GetBlocksWithType(boardID string, blockType string) ([]*model.Block, error) {
-	return s.getBlocksWithType(s.db, boardID, blockType)
+	return s.getBlocksWithType(s.replica(), boardID, blockType)
 
 }
 
 func (s *SQLStore) GetBoard(id string) (*model.Board, error) {
-	return s.getBoard(s.db, id)
+	return s.getBoard(s.replica(), id)
 
 }
 
 func (s *SQLStore) GetBoardAndCard(block *model.Block) (*model.Board, *model.Block, error) {
-	return s.getBoardAndCard(s.db, block)
+	return s.getBoardAndCard(s.replica(), block)
 
 }
 
 func (s *SQLStore) GetBoardAndCardByID(blockID string) (*model.Board, *model.Block, error) {
-	return s.getBoardAndCardByID(s.db, blockID)
+	return s.getBoardAndCardByID(s.replica(), blockID)
 
 }
 
 func (s *SQLStore) GetBoardCount() (int64, error) {
-	return s.getBoardCount(s.db)
+	return s.getBoardCount(s.replica())
 
 }
 
 func (s *SQLStore) GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error) {
-	return s.getBoardHistory(s.db, boardID, opts)
+	return s.getBoardHistory(s.replica(), boardID, opts)
 
 }
 
 func (s *SQLStore) GetBoardMemberHistory(boardID string, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error) {
-	return s.getBoardMemberHistory(s.db, boardID, userID, limit)
+	return s.getBoardMemberHistory(s.replica(), boardID, userID, limit)
+
+}
+
+func (s *SQLStore) GetBoardSnapshot(boardID string, at int64) (*model.BoardSnapshot, error) {
+	return s.getBoardSnapshot(s.replica(), boardID, at)
+
+}
+
+func (s *SQLStore) GetBoardVersions(boardID string, before int64, limit uint64) ([]*model.BoardVersion, error) {
+	return s.getBoardVersions(s.replica(), boardID, before, limit)
 
 }
 
 func (s *SQLStore) GetBoardsComplianceHistory(opts model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error) {
-	return s.getBoardsComplianceHistory(s.db, opts)
+	return s.getBoardsComplianceHistory(s.replica(), opts)
 
 }
 
 func (s *SQLStore) GetBoardsForCompliance(opts model.QueryBoardsForComplianceOptions) ([]*model.Board, bool, error) {
-	return s.getBoardsForCompliance(s.db, opts)
+	return s.getBoardsForCompliance(s.replica(), opts)
 
 }
 
 func (s *SQLStore) GetBoardsForUserAndTeam(userID string, teamID string, includePublicBoards bool) ([]*model.Board, error) {
-	return s.getBoardsForUserAndTeam(s.db, userID, teamID, includePublicBoards)
+	return s.getBoardsForUserAndTeam(s.replica(), userID, teamID, includePublicBoards)
 
 }
 
 func (s *SQLStore) GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error) {
-	return s.getBoardsInTeamByIds(s.db, boardIDs, teamID)
+	return s.getBoardsInTeamByIds(s.replica(), boardIDs, teamID)
+
+}
+
+func (s *SQLStore) GetBoardsWithPropertyType(propertyType string) ([]*model.Board, error) {
+	return s.getBoardsWithPropertyType(s.replica(), propertyType)
+
+}
+
+func (s *SQLStore) GetBulkOperation(operationID string) (*model.BulkOperation, error) {
+	return s.getBulkOperation(s.db, operationID)
+
+}
+
+func (s *SQLStore) GetCardDependencies(cardID string) ([]*model.CardDependency, error) {
+	return s.getCardDependencies(s.db, cardID)
+
+}
+
+func (s *SQLStore) GetCardDependenciesForBoard(boardID string) ([]*model.CardDependency, error) {
+	return s.getCardDependenciesForBoard(s.replica(), boardID)
 
 }
 
@@ -423,23 +626,63 @@ func (s *SQLStore) GetCardLimitTimestamp() (int64, error) {
 
 }
 
+func (s *SQLStore) GetCardRecurrence(cardID string) (*model.CardRecurrence, error) {
+	return s.getCardRecurrence(s.replica(), cardID)
+
+}
+
+func (s *SQLStore) GetCardsReferencingCard(cardID string, boardIDs []string) ([]*model.Block, error) {
+	return s.getCardsReferencingCard(s.replica(), cardID, boardIDs)
+
+}
+
+func (s *SQLStore) GetCardsWithDatesBetween(boardID string, propertyIDs []string, from int64, to int64) ([]*model.Block, error) {
+	return s.getCardsWithDatesBetween(s.replica(), boardID, propertyIDs, from, to)
+
+}
+
 func (s *SQLStore) GetCategory(id string) (*model.Category, error) {
-	return s.getCategory(s.db, id)
+	return s.getCategory(s.replica(), id)
 
 }
 
 func (s *SQLStore) GetChannel(teamID string, channelID string) (*mmModel.Channel, error) {
-	return s.getChannel(s.db, teamID, channelID)
+	return s.getChannel(s.replica(), teamID, channelID)
+
+}
+
+func (s *SQLStore) GetDataRetentionCandidates(globalRetentionDate int64, now int64) ([]*model.RetentionCandidate, error) {
+	return s.getDataRetentionCandidates(s.replica(), globalRetentionDate, now)
+
+}
+
+func (s *SQLStore) GetDeletedBlocks(boardIDs []string, blockType model.BlockType) ([]*model.Block, error) {
+	return s.getDeletedBlocks(s.replica(), boardIDs, blockType)
+
+}
+
+func (s *SQLStore) GetDeletedBoards(teamID string) ([]*model.Board, error) {
+	return s.getDeletedBoards(s.replica(), teamID)
+
+}
+
+func (s *SQLStore) GetDueCardRecurrences(now int64) ([]*model.CardRecurrence, error) {
+	return s.getDueCardRecurrences(s.db, now)
+
+}
+
+func (s *SQLStore) GetEnabledAutomationRulesWithTrigger(triggerType model.AutomationTriggerType) ([]*model.AutomationRule, error) {
+	return s.getEnabledAutomationRulesWithTrigger(s.replica(), triggerType)
 
 }
 
 func (s *SQLStore) GetFileInfo(id string) (*mmModel.FileInfo, error) {
-	return s.getFileInfo(s.db, id)
+	return s.getFileInfo(s.replica(), id)
 
 }
 
 func (s *SQLStore) GetLicense() *mmModel.License {
-	return s.getLicense(s.db)
+	return s.getLicense(s.replica())
 
 }
 
@@ -449,12 +692,12 @@ func (s *SQLStore) GetMemberForBoard(boardID string, userID string) (*model.Boar
 }
 
 func (s *SQLStore) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
-	return s.getMembersForBoard(s.db, boardID)
+	return s.getMembersForBoard(s.replica(), boardID)
 
 }
 
 func (s *SQLStore) GetMembersForUser(userID string) ([]*model.BoardMember, error) {
-	return s.getMembersForUser(s.db, userID)
+	return s.getMembersForUser(s.replica(), userID)
 
 }
 
@@ -469,7 +712,17 @@ func (s *SQLStore) GetNotificationHint(blockID string) (*model.NotificationHint,
 }
 
 func (s *SQLStore) GetRegisteredUserCount() (int, error) {
-	return s.getRegisteredUserCount(s.db)
+	return s.getRegisteredUserCount(s.replica())
+
+}
+
+func (s *SQLStore) GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, error) {
+	return s.getRetentionPolicies(s.replica(), teamID)
+
+}
+
+func (s *SQLStore) GetSchemaVersion() (int, error) {
+	return s.getSchemaVersion(s.db)
 
 }
 
@@ -479,32 +732,32 @@ func (s *SQLStore) GetSession(token string, expireTime int64) (*model.Session, e
 }
 
 func (s *SQLStore) GetSharing(rootID string) (*model.Sharing, error) {
-	return s.getSharing(s.db, rootID)
+	return s.getSharing(s.replica(), rootID)
 
 }
 
 func (s *SQLStore) GetSubTree2(boardID string, blockID string, opts model.QuerySubtreeOptions) ([]*model.Block, error) {
-	return s.getSubTree2(s.db, boardID, blockID, opts)
+	return s.getSubTree2(s.replica(), boardID, blockID, opts)
 
 }
 
 func (s *SQLStore) GetSubscribersCountForBlock(blockID string) (int, error) {
-	return s.getSubscribersCountForBlock(s.db, blockID)
+	return s.getSubscribersCountForBlock(s.replica(), blockID)
 
 }
 
 func (s *SQLStore) GetSubscribersForBlock(blockID string) ([]*model.Subscriber, error) {
-	return s.getSubscribersForBlock(s.db, blockID)
+	return s.getSubscribersForBlock(s.replica(), blockID)
 
 }
 
 func (s *SQLStore) GetSubscription(blockID string, subscriberID string) (*model.Subscription, error) {
-	return s.getSubscription(s.db, blockID, subscriberID)
+	return s.getSubscription(s.replica(), blockID, subscriberID)
 
 }
 
 func (s *SQLStore) GetSubscriptions(subscriberID string) ([]*model.Subscription, error) {
-	return s.getSubscriptions(s.db, subscriberID)
+	return s.getSubscriptions(s.replica(), subscriberID)
 
 }
 
@@ -519,22 +772,27 @@ func (s *SQLStore) GetSystemSettings() (map[string]string, error) {
 }
 
 func (s *SQLStore) GetTeam(ID string) (*model.Team, error) {
-	return s.getTeam(s.db, ID)
+	return s.getTeam(s.replica(), ID)
 
 }
 
 func (s *SQLStore) GetTeamCount() (int64, error) {
-	return s.getTeamCount(s.db)
+	return s.getTeamCount(s.replica())
 
 }
 
 func (s *SQLStore) GetTeamsForUser(userID string) ([]*model.Team, error) {
-	return s.getTeamsForUser(s.db, userID)
+	return s.getTeamsForUser(s.replica(), userID)
 
 }
 
 func (s *SQLStore) GetTemplateBoards(teamID string, userID string) ([]*model.Board, error) {
-	return s.getTemplateBoards(s.db, teamID, userID)
+	return s.getTemplateBoards(s.replica(), teamID, userID)
+
+}
+
+func (s *SQLStore) GetUndoState(userID string, boardID string) (*model.UndoState, error) {
+	return s.getUndoState(s.db, userID, boardID)
 
 }
 
@@ -559,32 +817,66 @@ func (s *SQLStore) GetUserByUsername(username string) (*model.User, error) {
 }
 
 func (s *SQLStore) GetUserCategories(userID string, teamID string) ([]model.Category, error) {
-	return s.getUserCategories(s.db, userID, teamID)
+	return s.getUserCategories(s.replica(), userID, teamID)
 
 }
 
 func (s *SQLStore) GetUserCategoryBoards(userID string, teamID string) ([]model.CategoryBoards, error) {
-	return s.getUserCategoryBoards(s.db, userID, teamID)
+	return s.getUserCategoryBoards(s.replica(), userID, teamID)
 
 }
 
 func (s *SQLStore) GetUserPreferences(userID string) (mmModel.Preferences, error) {
-	return s.getUserPreferences(s.db, userID)
+	return s.getUserPreferences(s.replica(), userID)
 
 }
 
 func (s *SQLStore) GetUserTimezone(userID string) (string, error) {
-	return s.getUserTimezone(s.db, userID)
+	return s.getUserTimezone(s.replica(), userID)
 
 }
 
 func (s *SQLStore) GetUsersByTeam(teamID string, asGuestID string, showEmail bool, showName bool) ([]*model.User, error) {
-	return s.getUsersByTeam(s.db, teamID, asGuestID, showEmail, showName)
+	return s.getUsersByTeam(s.replica(), teamID, asGuestID, showEmail, showName)
 
 }
 
 func (s *SQLStore) GetUsersList(userIDs []string, showEmail bool, showName bool) ([]*model.User, error) {
-	return s.getUsersList(s.db, userIDs, showEmail, showName)
+	return s.getUsersList(s.replica(), userIDs, showEmail, showName)
+
+}
+
+func (s *SQLStore) ImportCSVBlocks(imp *model.CSVImportBlocks, userID string) (*model.Board, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.importCSVBlocks(s.db, imp, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.importCSVBlocks(tx, imp, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "ImportCSVBlocks"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) InsertAuditRecord(record *model.AuditRecord) error {
+	return s.insertAuditRecord(s.db, record)
+
+}
+
+func (s *SQLStore) InsertAutomationRule(rule *model.AutomationRule) error {
+	return s.insertAutomationRule(s.db, rule)
 
 }
 
@@ -665,6 +957,59 @@ func (s *SQLStore) InsertBoardWithAdmin(board *model.Board, userID string) (*mod
 
 }
 
+func (s *SQLStore) InsertBulkOperation(operation *model.BulkOperation) error {
+	return s.insertBulkOperation(s.db, operation)
+
+}
+
+func (s *SQLStore) InsertUndoEntry(entry *model.UndoEntry, maxEntries int) error {
+	if s.dbType == model.SqliteDBType {
+		return s.insertUndoEntry(s.db, entry, maxEntries)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.insertUndoEntry(tx, entry, maxEntries)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "InsertUndoEntry"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) MoveCardBlocks(move *model.CardMoveBlocks, userID string) (*model.Board, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.moveCardBlocks(s.db, move, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.moveCardBlocks(tx, move, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "MoveCardBlocks"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.patchBlock(s.db, blockID, blockPatch, userID)
@@ -771,6 +1116,78 @@ func (s *SQLStore) PostMessage(message string, postType string, channelID string
 
 }
 
+func (s *SQLStore) PurgeBlock(blockID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.purgeBlock(s.db, blockID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.purgeBlock(tx, blockID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "PurgeBlock"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) PurgeBoard(boardID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.purgeBoard(s.db, boardID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.purgeBoard(tx, boardID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "PurgeBoard"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) PurgeTrash(deletedBefore int64) (int64, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.purgeTrash(s.db, deletedBefore)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return 0, txErr
+	}
+	result, err := s.purgeTrash(tx, deletedBefore)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "PurgeTrash"))
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) RefreshSession(session *model.Session) error {
 	return s.refreshSession(s.db, session)
 
@@ -791,6 +1208,102 @@ func (s *SQLStore) ReorderCategoryBoards(categoryID string, newBoardsOrder []str
 
 }
 
+func (s *SQLStore) RestoreArchivedPropertyValues(boardID string, propertyID string, userID string) ([]*model.Block, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.restoreArchivedPropertyValues(s.db, boardID, propertyID, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.restoreArchivedPropertyValues(tx, boardID, propertyID, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RestoreArchivedPropertyValues"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) RestoreBoardSnapshot(boardID string, at int64, cardIDs []string, userID string) (*model.BoardSnapshotRestore, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.restoreBoardSnapshot(s.db, boardID, at, cardIDs, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.restoreBoardSnapshot(tx, boardID, at, cardIDs, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RestoreBoardSnapshot"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) RestoreDatabase(dump model.DatabaseDumpReader) (map[string]int64, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.restoreDatabase(s.db, dump)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.restoreDatabase(tx, dump)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RestoreDatabase"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) RestoreDatabaseFile(path string) (map[string]int64, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.restoreDatabaseFile(s.db, path)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.restoreDatabaseFile(tx, path)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RestoreDatabaseFile"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.runDataRetention(s.db, globalRetentionDate, batchSize)
@@ -826,22 +1339,22 @@ func (s *SQLStore) SaveMember(bm *model.BoardMember) (*model.BoardMember, error)
 }
 
 func (s *SQLStore) SearchBoardsForUser(term string, searchField model.BoardSearchField, userID string, includePublicBoards bool) ([]*model.Board, error) {
-	return s.searchBoardsForUser(s.db, term, searchField, userID, includePublicBoards)
+	return s.searchBoardsForUser(s.replica(), term, searchField, userID, includePublicBoards)
 
 }
 
 func (s *SQLStore) SearchBoardsForUserInTeam(teamID string, term string, userID string) ([]*model.Board, error) {
-	return s.searchBoardsForUserInTeam(s.db, teamID, term, userID)
+	return s.searchBoardsForUserInTeam(s.replica(), teamID, term, userID)
 
 }
 
 func (s *SQLStore) SearchUserChannels(teamID string, userID string, query string) ([]*mmModel.Channel, error) {
-	return s.searchUserChannels(s.db, teamID, userID, query)
+	return s.searchUserChannels(s.replica(), teamID, userID, query)
 
 }
 
 func (s *SQLStore) SearchUsersByTeam(teamID string, searchQuery string, asGuestID string, excludeBots bool, showEmail bool, showName bool) ([]*model.User, error) {
-	return s.searchUsersByTeam(s.db, teamID, searchQuery, asGuestID, excludeBots, showEmail, showName)
+	return s.searchUsersByTeam(s.replica(), teamID, searchQuery, asGuestID, excludeBots, showEmail, showName)
 
 }
 
@@ -850,6 +1363,54 @@ func (s *SQLStore) SendMessage(message string, postType string, receipts []strin
 
 }
 
+func (s *SQLStore) SetBlockArchiveAt(blockID string, archiveAt int64, userID string) (*model.Block, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.setBlockArchiveAt(s.db, blockID, archiveAt, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.setBlockArchiveAt(tx, blockID, archiveAt, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "SetBlockArchiveAt"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) SetBoardArchiveAt(boardID string, archiveAt int64, userID string) (*model.Board, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.setBoardArchiveAt(s.db, boardID, archiveAt, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.setBoardArchiveAt(tx, boardID, archiveAt, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "SetBoardArchiveAt"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) SetBoardVisibility(userID string, categoryID string, boardID string, visible bool) error {
 	return s.setBoardVisibility(s.db, userID, categoryID, boardID, visible)
 
@@ -908,6 +1469,21 @@ func (s *SQLStore) UndeleteBoard(boardID string, modifiedBy string) error {
 
 }
 
+func (s *SQLStore) UpdateAutomationExecution(execution *model.AutomationExecution) error {
+	return s.updateAutomationExecution(s.db, execution)
+
+}
+
+func (s *SQLStore) UpdateAutomationRule(rule *model.AutomationRule) error {
+	return s.updateAutomationRule(s.db, rule)
+
+}
+
+func (s *SQLStore) UpdateBulkOperation(operation *model.BulkOperation) error {
+	return s.updateBulkOperation(s.db, operation)
+
+}
+
 func (s *SQLStore) UpdateCardLimitTimestamp(cardLimit int) (int64, error) {
 	return s.updateCardLimitTimestamp(s.db, cardLimit)
 
@@ -943,11 +1519,21 @@ func (s *SQLStore) UpdateUserPasswordByID(userID string, password string) error
 
 }
 
+func (s *SQLStore) UpsertCardRecurrence(recurrence *model.CardRecurrence) error {
+	return s.upsertCardRecurrence(s.db, recurrence)
+
+}
+
 func (s *SQLStore) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
 	return s.upsertNotificationHint(s.db, hint, notificationFreq)
 
 }
 
+func (s *SQLStore) UpsertRetentionPolicy(policy *model.RetentionPolicy) error {
+	return s.upsertRetentionPolicy(s.db, policy)
+
+}
+
 func (s *SQLStore) UpsertSharing(sharing model.Sharing) error {
 	return s.upsertSharing(s.db, sharing)
 
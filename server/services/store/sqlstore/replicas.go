@@ -0,0 +1,113 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const defaultReplicaHealthCheckInterval = 30 * time.Second
+
+// replicaDB is a read only database replica along with its last known
+// health status.
+type replicaDB struct {
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+func (s *SQLStore) openReplicas(connectionStrings []string) error {
+	for _, connectionString := range connectionStrings {
+		db, err := sql.Open(s.dbType, connectionString)
+		if err != nil {
+			s.closeReplicas()
+			return err
+		}
+		s.replicas = append(s.replicas, &replicaDB{db: db})
+	}
+	s.checkReplicasHealth()
+	return nil
+}
+
+// replica returns a healthy replica to run read only queries, using
+// round robin between them. If no replica is configured or healthy,
+// the primary database is returned.
+func (s *SQLStore) replica() *sql.DB {
+	count := len(s.replicas)
+	if count == 0 {
+		return s.db
+	}
+
+	start := s.replicaCounter.Add(1)
+	for i := 0; i < count; i++ {
+		replica := s.replicas[(start+uint64(i))%uint64(count)]
+		if replica.healthy.Load() {
+			return replica.db
+		}
+	}
+	return s.db
+}
+
+// checkReplicasHealth pings every replica and updates their health
+// status, so queries fail over to the primary while a replica is down.
+func (s *SQLStore) checkReplicasHealth() {
+	for i, replica := range s.replicas {
+		err := replica.db.Ping()
+		wasHealthy := replica.healthy.Swap(err == nil)
+		switch {
+		case err != nil && wasHealthy:
+			s.logger.Error("Database replica health check failed, failing over to the primary",
+				mlog.Int("replica", i),
+				mlog.Err(err),
+			)
+		case err != nil:
+			s.logger.Debug("Database replica is still unhealthy", mlog.Int("replica", i), mlog.Err(err))
+		case !wasHealthy:
+			s.logger.Info("Database replica is healthy", mlog.Int("replica", i))
+		}
+	}
+}
+
+func (s *SQLStore) startReplicasHealthCheck(interval time.Duration) {
+	if len(s.replicas) == 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultReplicaHealthCheckInterval
+	}
+
+	s.stopHealthCheck = make(chan struct{})
+	s.healthCheckDone = make(chan struct{})
+	go func() {
+		defer close(s.healthCheckDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.checkReplicasHealth()
+			case <-s.stopHealthCheck:
+				return
+			}
+		}
+	}()
+}
+
+func (s *SQLStore) stopReplicasHealthCheck() {
+	if s.stopHealthCheck == nil {
+		return
+	}
+	close(s.stopHealthCheck)
+	<-s.healthCheckDone
+	s.stopHealthCheck = nil
+}
+
+func (s *SQLStore) closeReplicas() {
+	for i, replica := range s.replicas {
+		if err := replica.db.Close(); err != nil {
+			s.logger.Error("Cannot close database replica", mlog.Int("replica", i), mlog.Err(err))
+		}
+	}
+	s.replicas = nil
+}
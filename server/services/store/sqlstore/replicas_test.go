@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func newTestStore(t *testing.T, logger mlog.LoggerIFace, replicas []string) (*SQLStore, string) {
+	dbType, connectionString, err := PrepareNewTestDatabase()
+	require.NoError(t, err)
+
+	sqlDB, err := sql.Open(dbType, connectionString)
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Ping())
+
+	store, err := New(Params{
+		DBType:                     dbType,
+		ConnectionString:           connectionString,
+		DBPingAttempts:             5,
+		TablePrefix:                "test_",
+		Logger:                     logger,
+		DB:                         sqlDB,
+		ReplicaConnectionStrings:   replicas,
+		ReplicaHealthCheckInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	return store, connectionString
+}
+
+func setupReplicaTests(t *testing.T) (primary *SQLStore, replica *SQLStore, tearDown func()) {
+	logger := mlog.CreateConsoleTestLogger(t)
+
+	// the replica is created as a standalone store first so its
+	// schema gets migrated
+	replica, replicaConnectionString := newTestStore(t, logger, nil)
+	primary, primaryConnectionString := newTestStore(t, logger, []string{replicaConnectionString})
+
+	tearDown = func() {
+		require.NoError(t, primary.Shutdown())
+		require.NoError(t, replica.Shutdown())
+		_ = os.Remove(primaryConnectionString)
+		_ = os.Remove(replicaConnectionString)
+	}
+	return primary, replica, tearDown
+}
+
+func TestReplicas(t *testing.T) {
+	board := &model.Board{ID: "board-id", TeamID: "team-id", Type: model.BoardTypeOpen}
+
+	t.Run("read only methods use the replica", func(t *testing.T) {
+		primary, replica, tearDown := setupReplicaTests(t)
+		defer tearDown()
+
+		_, err := primary.InsertBoard(board, "user-id")
+		require.NoError(t, err)
+
+		_, err = primary.GetBoard(board.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		_, err = replica.InsertBoard(board, "user-id")
+		require.NoError(t, err)
+
+		rBoard, err := primary.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, board.ID, rBoard.ID)
+	})
+
+	t.Run("methods annotated to read from primary don't use the replica", func(t *testing.T) {
+		primary, _, tearDown := setupReplicaTests(t)
+		defer tearDown()
+
+		block := &model.Block{ID: "block-id", BoardID: board.ID, Type: model.TypeCard}
+		require.NoError(t, primary.InsertBlock(block, "user-id"))
+
+		rBlock, err := primary.GetBlock(block.ID)
+		require.NoError(t, err)
+		require.Equal(t, block.ID, rBlock.ID)
+	})
+
+	t.Run("read only methods fail over to the primary if the replica is unhealthy", func(t *testing.T) {
+		primary, _, tearDown := setupReplicaTests(t)
+		defer tearDown()
+
+		_, err := primary.InsertBoard(board, "user-id")
+		require.NoError(t, err)
+
+		require.NoError(t, primary.replicas[0].db.Close())
+		primary.checkReplicasHealth()
+
+		rBoard, err := primary.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, board.ID, rBoard.ID)
+	})
+
+	t.Run("no replicas configured", func(t *testing.T) {
+		store, connectionString := newTestStore(t, mlog.CreateConsoleTestLogger(t), nil)
+		defer func() {
+			require.NoError(t, store.Shutdown())
+			_ = os.Remove(connectionString)
+		}()
+
+		require.Equal(t, store.db, store.replica())
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var retentionPolicyFields = []string{
+	"team_id",
+	"board_id",
+	"retention_days",
+	"COALESCE(modified_by, '')",
+	"COALESCE(create_at, 0)",
+	"COALESCE(update_at, 0)",
+}
+
+func (s *SQLStore) retentionPoliciesFromRows(rows *sql.Rows) ([]*model.RetentionPolicy, error) {
+	policies := []*model.RetentionPolicy{}
+
+	for rows.Next() {
+		var policy model.RetentionPolicy
+		err := rows.Scan(
+			&policy.TeamID,
+			&policy.BoardID,
+			&policy.RetentionDays,
+			&policy.ModifiedBy,
+			&policy.CreateAt,
+			&policy.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, &policy)
+	}
+	return policies, nil
+}
+
+// upsertRetentionPolicy creates or replaces the retention policy of a team
+// or of a board.
+func (s *SQLStore) upsertRetentionPolicy(db sq.BaseRunner, policy *model.RetentionPolicy) error {
+	if err := policy.IsValid(); err != nil {
+		return err
+	}
+
+	now := utils.GetMillis()
+	if policy.CreateAt == 0 {
+		policy.CreateAt = now
+	}
+	policy.UpdateAt = now
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"retention_policies").
+		Columns("team_id", "board_id", "retention_days", "modified_by", "create_at", "update_at").
+		Values(
+			policy.TeamID,
+			policy.BoardID,
+			policy.RetentionDays,
+			policy.ModifiedBy,
+			policy.CreateAt,
+			policy.UpdateAt,
+		)
+
+	updates := "retention_days = ?, modified_by = ?, update_at = ?"
+	updateArgs := []interface{}{
+		policy.RetentionDays,
+		policy.ModifiedBy,
+		policy.UpdateAt,
+	}
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE "+updates, updateArgs...)
+	} else {
+		query = query.Suffix("ON CONFLICT (team_id, board_id) DO UPDATE SET "+updates, updateArgs...)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot upsert retention policy",
+			mlog.String("team_id", policy.TeamID),
+			mlog.String("board_id", policy.BoardID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// getRetentionPolicies returns the retention policies of a team and of its
+// boards, or all the policies if teamID is empty.
+func (s *SQLStore) getRetentionPolicies(db sq.BaseRunner, teamID string) ([]*model.RetentionPolicy, error) {
+	query := s.getQueryBuilder(db).
+		Select(retentionPolicyFields...).
+		From(s.tablePrefix+"retention_policies").
+		OrderBy("team_id", "board_id")
+
+	if teamID != "" {
+		query = query.Where(sq.Eq{"team_id": teamID})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch retention policies", mlog.String("team_id", teamID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.retentionPoliciesFromRows(rows)
+}
+
+// deleteRetentionPolicy removes the retention policy of a team, if boardID
+// is empty, or of a board.
+func (s *SQLStore) deleteRetentionPolicy(db sq.BaseRunner, teamID, boardID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "retention_policies").
+		Where(sq.Eq{"team_id": teamID}).
+		Where(sq.Eq{"board_id": boardID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot delete retention policy",
+			mlog.String("team_id", teamID),
+			mlog.String("board_id", boardID),
+			mlog.Err(err),
+		)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("retention policy team ID=" + teamID + " board ID=" + boardID)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"sync/atomic"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -30,6 +31,11 @@ type SQLStore struct {
 	isBinaryParam    bool
 	schemaName       string
 	configFn         func() *mmModel.Config
+
+	replicas        []*replicaDB
+	replicaCounter  atomic.Uint64
+	stopHealthCheck chan struct{}
+	healthCheckDone chan struct{}
 }
 
 // MutexFactory is used by the store in plugin mode to generate
@@ -40,7 +46,6 @@ type MutexFactory func(name string) (*cluster.Mutex, error)
 func New(params Params) (*SQLStore, error) {
 	params.Logger.Info("connectDatabase", mlog.String("dbType", params.DBType))
 	store := &SQLStore{
-		// TODO: add replica DB support too.
 		db:               params.DB,
 		dbType:           params.DBType,
 		dbPingAttempts:   params.DBPingAttempts,
@@ -73,6 +78,13 @@ func New(params Params) (*SQLStore, error) {
 			return nil, mErr
 		}
 	}
+
+	if err := store.openReplicas(params.ReplicaConnectionStrings); err != nil {
+		params.Logger.Error(`Cannot open the database replicas`, mlog.Err(err))
+		return nil, err
+	}
+	store.startReplicasHealthCheck(params.ReplicaHealthCheckInterval)
+
 	return store, nil
 }
 
@@ -108,6 +120,8 @@ func (s *SQLStore) computeBinaryParam() (bool, error) {
 
 // Shutdown close the connection with the store.
 func (s *SQLStore) Shutdown() error {
+	s.stopReplicasHealthCheck()
+	s.closeReplicas()
 	return s.db.Close()
 }
 
@@ -22,8 +22,21 @@ func TestSQLStore(t *testing.T) {
 	t.Run("BoardStore", func(t *testing.T) { storetests.StoreTestBoardStore(t, SetupTests) })
 	t.Run("BoardsAndBlocksStore", func(t *testing.T) { storetests.StoreTestBoardsAndBlocksStore(t, SetupTests) })
 	t.Run("SubscriptionStore", func(t *testing.T) { storetests.StoreTestSubscriptionsStore(t, SetupTests) })
+	t.Run("CardDependenciesStore", func(t *testing.T) { storetests.StoreTestCardDependenciesStore(t, SetupTests) })
+	t.Run("CardRecurrencesStore", func(t *testing.T) { storetests.StoreTestCardRecurrencesStore(t, SetupTests) })
+	t.Run("DueDateRemindersStore", func(t *testing.T) { storetests.StoreTestDueDateRemindersStore(t, SetupTests) })
+	t.Run("AutomationRulesStore", func(t *testing.T) { storetests.StoreTestAutomationRulesStore(t, SetupTests) })
+	t.Run("ArchivedPropertyValuesStore", func(t *testing.T) { storetests.StoreTestArchivedPropertyValuesStore(t, SetupTests) })
+	t.Run("BoardSnapshotsStore", func(t *testing.T) { storetests.StoreTestBoardSnapshotsStore(t, SetupTests) })
+	t.Run("UndoLogStore", func(t *testing.T) { storetests.StoreTestUndoLogStore(t, SetupTests) })
+	t.Run("TrashStore", func(t *testing.T) { storetests.StoreTestTrashStore(t, SetupTests) })
 	t.Run("NotificationHintStore", func(t *testing.T) { storetests.StoreTestNotificationHintsStore(t, SetupTests) })
 	t.Run("DataRetention", func(t *testing.T) { storetests.StoreTestDataRetention(t, SetupTests) })
+	t.Run("BackupStore", func(t *testing.T) { storetests.StoreTestBackupStore(t, SetupTests) })
+	t.Run("HistoryCompactionStore", func(t *testing.T) { storetests.StoreTestHistoryCompactionStore(t, SetupTests) })
+	t.Run("AuditRecordsStore", func(t *testing.T) { storetests.StoreTestAuditRecordsStore(t, SetupTests) })
+	t.Run("ArchivingStore", func(t *testing.T) { storetests.StoreTestArchivingStore(t, SetupTests) })
+	t.Run("BulkOperationsStore", func(t *testing.T) { storetests.StoreTestBulkOperationsStore(t, SetupTests) })
 	t.Run("CloudStore", func(t *testing.T) { storetests.StoreTestCloudStore(t, SetupTests) })
 	t.Run("StoreTestFileStore", func(t *testing.T) { storetests.StoreTestFileStore(t, SetupTests) })
 	t.Run("StoreTestCategoryStore", func(t *testing.T) { storetests.StoreTestCategoryStore(t, SetupTests) })
@@ -0,0 +1,312 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// trashBoardTables are the tables holding the data of a board, with the
+// column that references it.
+var trashBoardTables = []struct {
+	table  string
+	column string
+}{
+	{"blocks", "board_id"},
+	{"blocks_history", "board_id"},
+	{"boards", "id"},
+	{"boards_history", "id"},
+	{"board_members", "board_id"},
+	{"board_members_history", "board_id"},
+	{"sharing", "id"},
+	{"category_boards", "board_id"},
+	{"card_recurrences", "board_id"},
+	{"automation_rules", "board_id"},
+	{"automation_executions", "board_id"},
+	{"archived_property_values", "board_id"},
+	{"undo_log", "board_id"},
+	{"retention_policies", "board_id"},
+	{"bulk_operations", "board_id"},
+}
+
+// trashCardTables are the tables holding the data of a card, with the
+// columns that reference it.
+var trashCardTables = []struct {
+	table   string
+	columns []string
+}{
+	{"card_dependencies", []string{"blocker_id", "blocked_id"}},
+	{"card_recurrences", []string{"card_id"}},
+	{"due_date_reminders", []string{"card_id"}},
+	{"automation_executions", []string{"card_id"}},
+	{"archived_property_values", []string{"card_id"}},
+}
+
+// getDeletedBoards returns the last version of the deleted boards of a
+// team, or of all the teams if teamID is empty, the newest first.
+func (s *SQLStore) getDeletedBoards(db sq.BaseRunner, teamID string) ([]*model.Board, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardHistoryFields()...).
+		From(s.tablePrefix + "boards_history").
+		Where(sq.Gt{"delete_at": 0}).
+		Where("id NOT IN (SELECT id FROM " + s.tablePrefix + "boards)").
+		OrderBy("insert_at DESC")
+
+	if teamID != "" {
+		query = query.Where(sq.Eq{"team_id": teamID})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch deleted boards", mlog.String("team_id", teamID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	versions, err := s.boardsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// a board deleted more than once has a version for each deletion
+	seen := map[string]bool{}
+	boards := []*model.Board{}
+	for _, board := range versions {
+		if seen[board.ID] {
+			continue
+		}
+		seen[board.ID] = true
+		boards = append(boards, board)
+	}
+	return boards, nil
+}
+
+// getDeletedBlocks returns the last version of the deleted blocks of some
+// boards, the newest first. If blockType is empty, all the types are
+// returned.
+func (s *SQLStore) getDeletedBlocks(db sq.BaseRunner, boardIDs []string, blockType model.BlockType) ([]*model.Block, error) {
+	if len(boardIDs) == 0 {
+		return []*model.Block{}, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("")...).
+		From(s.tablePrefix + "blocks_history").
+		Where(sq.Eq{"board_id": boardIDs}).
+		Where(sq.Gt{"delete_at": 0}).
+		Where("id NOT IN (SELECT id FROM " + s.tablePrefix + "blocks)").
+		OrderBy("insert_at DESC")
+
+	if blockType != "" {
+		query = query.Where(sq.Eq{"type": blockType})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch deleted blocks", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	versions, err := s.blocksFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	blocks := []*model.Block{}
+	for _, block := range versions {
+		if seen[block.ID] {
+			continue
+		}
+		seen[block.ID] = true
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// purgeBoard permanently removes a deleted board with all its data.
+func (s *SQLStore) purgeBoard(db sq.BaseRunner, boardID string) error {
+	if _, err := s.getBoard(db, boardID); err == nil {
+		return model.NewErrBadRequest(fmt.Sprintf("board ID=%s is not deleted", boardID))
+	} else if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	boards, err := s.getBoardHistory(db, boardID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true})
+	if err != nil {
+		return err
+	}
+	if len(boards) == 0 {
+		return model.NewErrNotFound("board ID=" + boardID)
+	}
+
+	cardIDs, err := s.getHistoryBlockIDs(db, sq.Eq{"board_id": boardID})
+	if err != nil {
+		return err
+	}
+	if err := s.purgeCardData(db, cardIDs); err != nil {
+		return err
+	}
+
+	for _, t := range trashBoardTables {
+		query := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + t.table).
+			Where(sq.Eq{t.column: boardID})
+		if _, err := query.Exec(); err != nil {
+			s.logger.Error("Cannot purge board", mlog.String("board_id", boardID), mlog.String("table", t.table), mlog.Err(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeBlock permanently removes a deleted block, its descendants and
+// all their history.
+func (s *SQLStore) purgeBlock(db sq.BaseRunner, blockID string) error {
+	if _, err := s.getBlock(db, blockID); err == nil {
+		return model.NewErrBadRequest(fmt.Sprintf("block ID=%s is not deleted", blockID))
+	} else if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	blockIDs := []string{blockID}
+	parentIDs := []string{blockID}
+	for len(parentIDs) > 0 {
+		children, err := s.getHistoryBlockIDs(db, sq.Eq{"parent_id": parentIDs})
+		if err != nil {
+			return err
+		}
+		parentIDs = []string{}
+		for _, childID := range children {
+			if childID != blockID {
+				parentIDs = append(parentIDs, childID)
+			}
+		}
+		blockIDs = append(blockIDs, parentIDs...)
+	}
+
+	if err := s.purgeCardData(db, blockIDs); err != nil {
+		return err
+	}
+
+	// the children that were restored or moved since are kept
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "blocks_history").
+		Where(sq.Eq{"id": blockIDs}).
+		Where("id NOT IN (SELECT id FROM " + s.tablePrefix + "blocks)")
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot purge block", mlog.String("block_id", blockID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// getHistoryBlockIDs returns the ids of the blocks with a version that
+// matches the condition and that are not part of the blocks table.
+func (s *SQLStore) getHistoryBlockIDs(db sq.BaseRunner, where sq.Sqlizer) ([]string, error) {
+	query := s.getQueryBuilder(db).
+		Select("DISTINCT id").
+		From(s.tablePrefix + "blocks_history").
+		Where(where).
+		Where("id NOT IN (SELECT id FROM " + s.tablePrefix + "blocks)")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch history block ids", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return idsFromRows(rows)
+}
+
+func (s *SQLStore) purgeCardData(db sq.BaseRunner, cardIDs []string) error {
+	if len(cardIDs) == 0 {
+		return nil
+	}
+
+	for _, t := range trashCardTables {
+		for _, column := range t.columns {
+			query := s.getQueryBuilder(db).
+				Delete(s.tablePrefix + t.table).
+				Where(sq.Eq{column: cardIDs})
+			if _, err := query.Exec(); err != nil {
+				s.logger.Error("Cannot purge card data", mlog.String("table", t.table), mlog.Err(err))
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// purgeTrash permanently removes the boards and blocks deleted before the
+// given time. It returns the number of purged boards and blocks.
+func (s *SQLStore) purgeTrash(db sq.BaseRunner, deletedBefore int64) (int64, error) {
+	var purged int64
+
+	boards, err := s.getDeletedBoards(db, "")
+	if err != nil {
+		return 0, err
+	}
+	for _, board := range boards {
+		if board.DeleteAt >= deletedBefore {
+			continue
+		}
+		if err := s.purgeBoard(db, board.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	boardIDs, err := s.getHistoryBoardIDsWithDeletedBlocks(db, deletedBefore)
+	if err != nil {
+		return purged, err
+	}
+	blocks, err := s.getDeletedBlocks(db, boardIDs, "")
+	if err != nil {
+		return purged, err
+	}
+
+	// the children of a deleted block are purged with it
+	deleted := map[string]bool{}
+	for _, block := range blocks {
+		deleted[block.ID] = true
+	}
+	for _, block := range blocks {
+		if block.DeleteAt >= deletedBefore || deleted[block.ParentID] {
+			continue
+		}
+		if err := s.purgeBlock(db, block.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// getHistoryBoardIDsWithDeletedBlocks returns the ids of the existing
+// boards with blocks deleted before the given time.
+func (s *SQLStore) getHistoryBoardIDsWithDeletedBlocks(db sq.BaseRunner, deletedBefore int64) ([]string, error) {
+	query := s.getQueryBuilder(db).
+		Select("DISTINCT board_id").
+		From(s.tablePrefix + "blocks_history").
+		Where(sq.Gt{"delete_at": 0}).
+		Where(sq.Lt{"delete_at": deletedBefore}).
+		Where("board_id IN (SELECT id FROM " + s.tablePrefix + "boards)")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch boards with deleted blocks", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return idsFromRows(rows)
+}
@@ -0,0 +1,383 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var undoLogFields = []string{
+	"id",
+	"user_id",
+	"board_id",
+	"operation",
+	"changes",
+	"create_at",
+	"undone_at",
+}
+
+// undoChanges is the content of the changes column of the undo log.
+type undoChanges struct {
+	BoardChange  *model.BoardChange  `json:"boardChange,omitempty"`
+	BlockChanges []model.BlockChange `json:"blockChanges"`
+}
+
+func (s *SQLStore) undoEntriesFromRows(rows *sql.Rows) ([]*model.UndoEntry, error) {
+	entries := []*model.UndoEntry{}
+
+	for rows.Next() {
+		var entry model.UndoEntry
+		var changesJSON []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.BoardID,
+			&entry.Operation,
+			&changesJSON,
+			&entry.CreateAt,
+			&entry.UndoneAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var changes undoChanges
+		if len(changesJSON) != 0 {
+			if err := json.Unmarshal(changesJSON, &changes); err != nil {
+				return nil, err
+			}
+		}
+		entry.BoardChange = changes.BoardChange
+		entry.BlockChanges = changes.BlockChanges
+		if entry.BlockChanges == nil {
+			entry.BlockChanges = []model.BlockChange{}
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+func (s *SQLStore) getUndoEntries(db sq.BaseRunner, userID, boardID string) ([]*model.UndoEntry, error) {
+	query := s.getQueryBuilder(db).
+		Select(undoLogFields...).
+		From(s.tablePrefix + "undo_log").
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Eq{"board_id": boardID}).
+		OrderBy("create_at DESC")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch undo entries", mlog.String("user_id", userID), mlog.String("board_id", boardID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.undoEntriesFromRows(rows)
+}
+
+// insertUndoEntry adds an entry on top of the undo stack of a user on a
+// board. As a new change makes the undone entries obsolete, they are
+// removed, and only the newest maxEntries entries are kept.
+func (s *SQLStore) insertUndoEntry(db sq.BaseRunner, entry *model.UndoEntry, maxEntries int) error {
+	entries, err := s.getUndoEntries(db, entry.UserID, entry.BoardID)
+	if err != nil {
+		return err
+	}
+
+	// the creation times are kept unique so the order of the stack is
+	// well defined even for changes made within the same millisecond
+	entry.CreateAt = utils.GetMillis()
+	entry.UndoneAt = 0
+	toDelete := []string{}
+	kept := 1
+	for _, e := range entries {
+		if e.CreateAt >= entry.CreateAt {
+			entry.CreateAt = e.CreateAt + 1
+		}
+		if e.UndoneAt != 0 || kept >= maxEntries {
+			toDelete = append(toDelete, e.ID)
+			continue
+		}
+		kept++
+	}
+
+	if len(toDelete) > 0 {
+		deleteQuery := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + "undo_log").
+			Where(sq.Eq{"id": toDelete})
+		if _, err := deleteQuery.Exec(); err != nil {
+			s.logger.Error("Cannot delete undo entries", mlog.String("user_id", entry.UserID), mlog.Err(err))
+			return err
+		}
+	}
+
+	changesJSON, err := json.Marshal(undoChanges{BoardChange: entry.BoardChange, BlockChanges: entry.BlockChanges})
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"undo_log").
+		Columns(undoLogFields...).
+		Values(entry.ID, entry.UserID, entry.BoardID, entry.Operation, changesJSON, entry.CreateAt, entry.UndoneAt)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot insert undo entry", mlog.String("user_id", entry.UserID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// nextUndo returns the next entry to undo, which is the newest entry
+// that isn't undone. The entries are sorted from the newest.
+func nextUndo(entries []*model.UndoEntry) *model.UndoEntry {
+	for _, entry := range entries {
+		if entry.UndoneAt == 0 {
+			return entry
+		}
+	}
+	return nil
+}
+
+// nextRedo returns the next entry to redo, which is the last undone one.
+func nextRedo(entries []*model.UndoEntry) *model.UndoEntry {
+	var redo *model.UndoEntry
+	for _, entry := range entries {
+		if entry.UndoneAt != 0 && (redo == nil || entry.UndoneAt > redo.UndoneAt) {
+			redo = entry
+		}
+	}
+	return redo
+}
+
+func (s *SQLStore) getUndoState(db sq.BaseRunner, userID, boardID string) (*model.UndoState, error) {
+	entries, err := s.getUndoEntries(db, userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UndoState{Undo: nextUndo(entries), Redo: nextRedo(entries)}, nil
+}
+
+// applyUndoEntry undoes the next entry of the stack of a user on a board,
+// or redoes it. Unless forced, it fails if other users changed the board
+// or the blocks of the entry since it was made or undone.
+func (s *SQLStore) applyUndoEntry(db sq.BaseRunner, userID, boardID string, redo bool, force bool) (*model.UndoResult, error) {
+	entries, err := s.getUndoEntries(db, userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, action := nextUndo(entries), "undo"
+	if redo {
+		entry, action = nextRedo(entries), "redo"
+	}
+	if entry == nil {
+		return nil, model.NewErrNotFound(fmt.Sprintf("nothing to %s on board ID=%s", action, boardID))
+	}
+
+	since := entry.CreateAt
+	if redo {
+		since = entry.UndoneAt
+	}
+
+	if !force {
+		conflicts, cErr := s.getUndoConflicts(db, entry, since)
+		if cErr != nil {
+			return nil, cErr
+		}
+		if len(conflicts) > 0 {
+			return nil, model.NewErrConflict(fmt.Sprintf("cannot %s, changed by other users since: %s", action, strings.Join(conflicts, ", ")))
+		}
+	}
+
+	result := &model.UndoResult{Entry: entry, Blocks: []*model.Block{}, DeletedBlockIDs: []string{}}
+
+	if entry.BoardChange != nil {
+		target := entry.BoardChange.Before
+		if redo {
+			target = entry.BoardChange.After
+		}
+		if target != nil {
+			board, bErr := s.getBoard(db, boardID)
+			if bErr != nil {
+				return nil, bErr
+			}
+			if !model.BoardContentEqual(board, target) {
+				copyBoardContent(board, target)
+				if result.Board, err = s.insertBoard(db, board, userID); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// the changes are undone in reverse order, so the children inserted
+	// after their parents are deleted first
+	for i := range entry.BlockChanges {
+		change := entry.BlockChanges[i]
+		target := change.After
+		if !redo {
+			change = entry.BlockChanges[len(entry.BlockChanges)-1-i]
+			target = change.Before
+		}
+		if err := s.applyBlockState(db, boardID, blockChangeID(change), target, userID, result); err != nil {
+			return nil, err
+		}
+	}
+
+	undoneAt := int64(0)
+	if !redo {
+		undoneAt = utils.GetMillis()
+		for _, e := range entries {
+			if e.UndoneAt >= undoneAt {
+				undoneAt = e.UndoneAt + 1
+			}
+		}
+	}
+	entry.UndoneAt = undoneAt
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"undo_log").
+		Set("undone_at", undoneAt).
+		Where(sq.Eq{"id": entry.ID})
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot update undo entry", mlog.String("id", entry.ID), mlog.Err(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func blockChangeID(change model.BlockChange) string {
+	if change.After != nil {
+		return change.After.ID
+	}
+	if change.Before != nil {
+		return change.Before.ID
+	}
+	return ""
+}
+
+// getUndoConflicts returns the ids of the board and blocks of an entry
+// that were changed by other users after the given time.
+func (s *SQLStore) getUndoConflicts(db sq.BaseRunner, entry *model.UndoEntry, since int64) ([]string, error) {
+	conflicts := []string{}
+
+	if entry.BoardChange != nil {
+		changed, err := s.changedByOthersSince(db, "boards_history", []string{entry.BoardID}, entry.UserID, since)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, changed...)
+	}
+
+	blockIDs := make([]string, 0, len(entry.BlockChanges))
+	for _, change := range entry.BlockChanges {
+		if id := blockChangeID(change); id != "" {
+			blockIDs = append(blockIDs, id)
+		}
+	}
+	if len(blockIDs) > 0 {
+		changed, err := s.changedByOthersSince(db, "blocks_history", blockIDs, entry.UserID, since)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, changed...)
+	}
+	return conflicts, nil
+}
+
+func (s *SQLStore) changedByOthersSince(db sq.BaseRunner, table string, ids []string, userID string, since int64) ([]string, error) {
+	query := s.getQueryBuilder(db).
+		Select("DISTINCT id").
+		From(s.tablePrefix + table).
+		Where(sq.Eq{"id": ids}).
+		Where(sq.Gt{"update_at": since}).
+		Where(sq.NotEq{"modified_by": userID}).
+		OrderBy("id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("Cannot fetch changes by other users", mlog.String("table", table), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	changed := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		changed = append(changed, id)
+	}
+	return changed, nil
+}
+
+// applyBlockState brings a block of a board to the target state: a nil
+// target means the block is deleted, and a deleted block is undeleted
+// with its children before being updated. The blocks of other boards are
+// refused, as the entry is applied with the permissions of its board.
+func (s *SQLStore) applyBlockState(db sq.BaseRunner, boardID, blockID string, target *model.Block, userID string, result *model.UndoResult) error {
+	current, err := s.getBlock(db, blockID)
+	if err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if (exists && current.BoardID != boardID) || (target != nil && target.BoardID != boardID) {
+		return model.NewErrBadRequest(fmt.Sprintf("block %s is not in board %s", blockID, boardID))
+	}
+
+	switch {
+	case target == nil && !exists:
+		return nil
+	case target == nil:
+		if err := s.deleteBlock(db, blockID, userID); err != nil {
+			return err
+		}
+		result.DeletedBlockIDs = append(result.DeletedBlockIDs, blockID)
+		return nil
+	case !exists:
+		if err := s.undeleteBlock(db, blockID, userID); err != nil {
+			return err
+		}
+		block, err := s.getBlock(db, blockID)
+		if model.IsErrNotFound(err) {
+			// the block has no history to be undeleted from
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		children, err := s.getBlocks(db, model.QueryBlocksOptions{BoardID: block.BoardID, ParentID: block.ID})
+		if err != nil {
+			return err
+		}
+		result.Blocks = append(result.Blocks, block)
+		result.Blocks = append(result.Blocks, children...)
+		return nil
+	case model.BlockContentEqual(current, target):
+		return nil
+	}
+
+	block := *target
+	block.CreatedBy = current.CreatedBy
+	block.CreateAt = current.CreateAt
+	if err := s.insertBlock(db, &block, userID); err != nil {
+		return err
+	}
+	result.Blocks = append(result.Blocks, &block)
+	return nil
+}
@@ -13,14 +13,23 @@ import (
 const CardLimitTimestampSystemKey = "card_limit_timestamp"
 
 // Store represents the abstraction of the data storage.
+//
+// Read only methods are served from the database replicas when they
+// are configured. Methods used on read-after-write paths, that can't
+// tolerate the replication lag, are annotated with @readFromPrimary.
+// Methods that only change data the cached methods don't read are
+// annotated with @keepCache so they don't invalidate the cache layer.
 type Store interface {
 	GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error)
 	GetBlocksWithParentAndType(boardID, parentID string, blockType string) ([]*model.Block, error)
 	GetBlocksWithParent(boardID, parentID string) ([]*model.Block, error)
+	// @readFromPrimary
 	GetBlocksByIDs(ids []string) ([]*model.Block, error)
 	GetBlocksWithType(boardID, blockType string) ([]*model.Block, error)
 	GetSubTree2(boardID, blockID string, opts model.QuerySubtreeOptions) ([]*model.Block, error)
+	// @cache
 	GetBlocksForBoard(boardID string) ([]*model.Block, error)
+	GetCardsReferencingCard(cardID string, boardIDs []string) ([]*model.Block, error)
 	// @withTransaction
 	InsertBlock(block *model.Block, userID string) error
 	// @withTransaction
@@ -33,6 +42,7 @@ type Store interface {
 	UndeleteBoard(boardID string, modifiedBy string) error
 	GetBlockCountsByType() (map[string]int64, error)
 	GetBoardCount() (int64, error)
+	// @readFromPrimary
 	GetBlock(blockID string) (*model.Block, error)
 	// @withTransaction
 	PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error
@@ -40,6 +50,10 @@ type Store interface {
 	GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
 	GetBlockHistoryNewestChildren(parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error)
 	GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error)
+	GetBoardVersions(boardID string, before int64, limit uint64) ([]*model.BoardVersion, error)
+	GetBoardSnapshot(boardID string, at int64) (*model.BoardSnapshot, error)
+	// @withTransaction
+	RestoreBoardSnapshot(boardID string, at int64, cardIDs []string, userID string) (*model.BoardSnapshotRestore, error)
 	GetBoardAndCardByID(blockID string) (board *model.Board, card *model.Block, err error)
 	GetBoardAndCard(block *model.Block) (board *model.Board, card *model.Block, err error)
 	// @withTransaction
@@ -48,17 +62,30 @@ type Store interface {
 	DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error)
 	// @withTransaction
 	PatchBlocks(blockPatches *model.BlockPatchBatch, userID string) error
+	// @withTransaction
+	MoveCardBlocks(move *model.CardMoveBlocks, userID string) (*model.Board, error)
+	// @withTransaction
+	ImportCSVBlocks(imp *model.CSVImportBlocks, userID string) (*model.Board, error)
+	// @withTransaction
+	SetBlockArchiveAt(blockID string, archiveAt int64, userID string) (*model.Block, error)
+	// @withTransaction
+	SetBoardArchiveAt(boardID string, archiveAt int64, userID string) (*model.Board, error)
 
 	Shutdown() error
 
+	// @readFromPrimary
 	GetSystemSetting(key string) (string, error)
+	// @readFromPrimary
 	GetSystemSettings() (map[string]string, error)
 	SetSystemSetting(key, value string) error
 
 	GetRegisteredUserCount() (int, error)
+	// @readFromPrimary
 	GetUserByID(userID string) (*model.User, error)
 	GetUsersList(userIDs []string, showEmail, showName bool) ([]*model.User, error)
+	// @readFromPrimary
 	GetUserByEmail(email string) (*model.User, error)
+	// @readFromPrimary
 	GetUserByUsername(username string) (*model.User, error)
 	CreateUser(user *model.User) (*model.User, error)
 	UpdateUser(user *model.User) (*model.User, error)
@@ -70,6 +97,7 @@ type Store interface {
 	GetUserPreferences(userID string) (mmModel.Preferences, error)
 
 	GetActiveUserCount(updatedSecondsAgo int64) (int, error)
+	// @readFromPrimary
 	GetSession(token string, expireTime int64) (*model.Session, error)
 	CreateSession(session *model.Session) error
 	RefreshSession(session *model.Session) error
@@ -92,6 +120,7 @@ type Store interface {
 	InsertBoardWithAdmin(board *model.Board, userID string) (*model.Board, *model.BoardMember, error)
 	// @withTransaction
 	PatchBoard(boardID string, boardPatch *model.BoardPatch, userID string) (*model.Board, error)
+	// @cache
 	GetBoard(id string) (*model.Board, error)
 	GetBoardsForUserAndTeam(userID, teamID string, includePublicBoards bool) ([]*model.Board, error)
 	GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error)
@@ -100,8 +129,11 @@ type Store interface {
 
 	SaveMember(bm *model.BoardMember) (*model.BoardMember, error)
 	DeleteMember(boardID, userID string) error
+	// @cache
+	// @readFromPrimary
 	GetMemberForBoard(boardID, userID string) (*model.BoardMember, error)
 	GetBoardMemberHistory(boardID, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error)
+	// @cache
 	GetMembersForBoard(boardID string) ([]*model.BoardMember, error)
 	GetMembersForUser(userID string) ([]*model.BoardMember, error)
 	CanSeeUser(seerID string, seenID string) (bool, error)
@@ -144,9 +176,62 @@ type Store interface {
 	GetSubscribersCountForBlock(blockID string) (int, error)
 	UpdateSubscribersNotifiedAt(blockID string, notifiedAt int64) error
 
+	AddCardDependency(dependency *model.CardDependency) error
+	DeleteCardDependency(blockerID, blockedID string) error
+	// @readFromPrimary
+	GetCardDependencies(cardID string) ([]*model.CardDependency, error)
+	GetCardDependenciesForBoard(boardID string) ([]*model.CardDependency, error)
+
+	UpsertCardRecurrence(recurrence *model.CardRecurrence) error
+	GetCardRecurrence(cardID string) (*model.CardRecurrence, error)
+	DeleteCardRecurrence(cardID string) error
+	// @readFromPrimary
+	GetDueCardRecurrences(now int64) ([]*model.CardRecurrence, error)
+	ClaimCardRecurrence(cardID string, dueAt, runAt, nextRunAt int64) (bool, error)
+
+	GetBoardsWithPropertyType(propertyType string) ([]*model.Board, error)
+	GetCardsWithDatesBetween(boardID string, propertyIDs []string, from, to int64) ([]*model.Block, error)
+	AddDueDateReminder(reminder *model.DueDateReminder) (bool, error)
+	DeleteDueDateRemindersBefore(sentAt int64) (int64, error)
+
+	InsertAutomationRule(rule *model.AutomationRule) error
+	UpdateAutomationRule(rule *model.AutomationRule) error
+	// @readFromPrimary
+	GetAutomationRule(ruleID string) (*model.AutomationRule, error)
+	GetAutomationRulesForBoard(boardID string) ([]*model.AutomationRule, error)
+	GetEnabledAutomationRulesWithTrigger(triggerType model.AutomationTriggerType) ([]*model.AutomationRule, error)
+	// @withTransaction
+	DeleteAutomationRule(ruleID string) error
+	AddAutomationExecution(execution *model.AutomationExecution) (bool, error)
+	UpdateAutomationExecution(execution *model.AutomationExecution) error
+	GetAutomationExecutions(ruleID string, limit uint64) ([]*model.AutomationExecution, error)
+	DeleteAutomationExecutionsBefore(createAt int64) (int64, error)
+
+	GetArchivedPropertyValues(boardID string) ([]*model.ArchivedPropertyValue, error)
+	// @withTransaction
+	RestoreArchivedPropertyValues(boardID, propertyID, userID string) ([]*model.Block, error)
+
+	// @withTransaction
+	InsertUndoEntry(entry *model.UndoEntry, maxEntries int) error
+	// @readFromPrimary
+	GetUndoState(userID, boardID string) (*model.UndoState, error)
+	// @withTransaction
+	ApplyUndoEntry(userID, boardID string, redo bool, force bool) (*model.UndoResult, error)
+
+	GetDeletedBoards(teamID string) ([]*model.Board, error)
+	GetDeletedBlocks(boardIDs []string, blockType model.BlockType) ([]*model.Block, error)
+	// @withTransaction
+	PurgeBoard(boardID string) error
+	// @withTransaction
+	PurgeBlock(blockID string) error
+	// @withTransaction
+	PurgeTrash(deletedBefore int64) (int64, error)
+
 	UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error)
 	DeleteNotificationHint(blockID string) error
+	// @readFromPrimary
 	GetNotificationHint(blockID string) (*model.NotificationHint, error)
+	// @readFromPrimary
 	GetNextNotificationHint(remove bool) (*model.NotificationHint, error)
 
 	RemoveDefaultTemplates(boards []*model.Board) error
@@ -154,8 +239,45 @@ type Store interface {
 
 	// @withTransaction
 	RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error)
+	UpsertRetentionPolicy(policy *model.RetentionPolicy) error
+	GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, error)
+	DeleteRetentionPolicy(teamID, boardID string) error
+	GetDataRetentionCandidates(globalRetentionDate int64, now int64) ([]*model.RetentionCandidate, error)
+	// @withTransaction
+	DeleteDataRetentionBoards(boardIDs []string, batchSize int64) (int64, error)
+
+	CompactHistory(window int64, before int64) (map[string]int64, error)
+
+	// @keepCache
+	InsertAuditRecord(record *model.AuditRecord) error
+	GetAuditRecords(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error)
+	// @keepCache
+	DeleteAuditRecords(before int64) (int64, error)
+
+	// @keepCache
+	InsertBulkOperation(operation *model.BulkOperation) error
+	// @keepCache
+	UpdateBulkOperation(operation *model.BulkOperation) error
+	// @readFromPrimary
+	GetBulkOperation(operationID string) (*model.BulkOperation, error)
+	// @keepCache
+	FailStaleBulkOperations(updatedBefore int64, message string) (int64, error)
+
+	// @readFromPrimary
+	GetSchemaVersion() (int, error)
+	// @readFromPrimary
+	GetBackupTables() ([]string, error)
+	// @readFromPrimary
+	DumpDatabase(w model.DatabaseDumpWriter) (map[string]int64, error)
+	BackupDatabaseFile(path string) (map[string]int64, error)
+	// @withTransaction
+	RestoreDatabase(dump model.DatabaseDumpReader) (map[string]int64, error)
+	// @withTransaction
+	RestoreDatabaseFile(path string) (map[string]int64, error)
 
+	// @readFromPrimary
 	GetUsedCardsCount() (int, error)
+	// @readFromPrimary
 	GetCardLimitTimestamp() (int64, error)
 	UpdateCardLimitTimestamp(cardLimit int) (int64, error)
 
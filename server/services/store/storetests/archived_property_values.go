@@ -0,0 +1,156 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestArchivedPropertyValuesStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("PatchBoardCleansCardValues", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPatchBoardCleansCardValues(t, store)
+	})
+
+	t.Run("RestoreArchivedPropertyValues", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testRestoreArchivedPropertyValues(t, store)
+	})
+}
+
+func createBoardWithStatusCard(t *testing.T, store store.Store) (*model.Board, *model.Block) {
+	board, err := store.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To do"},
+				map[string]interface{}{"id": "doing", "value": "Doing"},
+			}},
+			{"id": "notes", "name": "Notes", "type": "text"},
+		},
+	}, testUserID)
+	require.NoError(t, err)
+
+	card := &model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{"status": "doing", "notes": "some notes"},
+		},
+	}
+	require.NoError(t, store.InsertBlock(card, testUserID))
+
+	// wait to avoid hitting pk uniqueness constraint in history
+	time.Sleep(10 * time.Millisecond)
+	return board, card
+}
+
+func getCardProperties(t *testing.T, store store.Store, cardID string) map[string]interface{} {
+	card, err := store.GetBlock(cardID)
+	require.NoError(t, err)
+	props, ok := card.Fields["properties"].(map[string]interface{})
+	require.True(t, ok)
+	return props
+}
+
+func testPatchBoardCleansCardValues(t *testing.T, store store.Store) {
+	t.Run("removed values are deleted", func(t *testing.T) {
+		board, card := createBoardWithStatusCard(t, store)
+
+		_, err := store.PatchBoard(board.ID, &model.BoardPatch{
+			DeletedCardProperties: []string{"notes"},
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do"},
+				}},
+			},
+		}, testUserID)
+		require.NoError(t, err)
+
+		require.Empty(t, getCardProperties(t, store, card.ID))
+
+		archived, err := store.GetArchivedPropertyValues(board.ID)
+		require.NoError(t, err)
+		require.Empty(t, archived)
+	})
+
+	t.Run("removed options are remapped", func(t *testing.T) {
+		board, card := createBoardWithStatusCard(t, store)
+
+		_, err := store.PatchBoard(board.ID, &model.BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do"},
+				}},
+			},
+			RemappedOptions: map[string]map[string]string{"status": {"doing": "todo"}},
+		}, testUserID)
+		require.NoError(t, err)
+
+		props := getCardProperties(t, store, card.ID)
+		require.Equal(t, "todo", props["status"])
+		require.Equal(t, "some notes", props["notes"])
+	})
+
+	t.Run("removed values are archived", func(t *testing.T) {
+		board, card := createBoardWithStatusCard(t, store)
+
+		_, err := store.PatchBoard(board.ID, &model.BoardPatch{
+			DeletedCardProperties: []string{"notes"},
+			ArchiveRemovedValues:  true,
+		}, testUserID)
+		require.NoError(t, err)
+
+		props := getCardProperties(t, store, card.ID)
+		require.NotContains(t, props, "notes")
+		require.Equal(t, "doing", props["status"])
+
+		archived, err := store.GetArchivedPropertyValues(board.ID)
+		require.NoError(t, err)
+		require.Len(t, archived, 1)
+		require.Equal(t, card.ID, archived[0].CardID)
+		require.Equal(t, "notes", archived[0].PropertyID)
+		require.Equal(t, "some notes", archived[0].Value)
+		require.Equal(t, testUserID, archived[0].ArchivedBy)
+	})
+}
+
+func testRestoreArchivedPropertyValues(t *testing.T, store store.Store) {
+	board, card := createBoardWithStatusCard(t, store)
+
+	_, err := store.PatchBoard(board.ID, &model.BoardPatch{
+		DeletedCardProperties: []string{"notes"},
+		ArchiveRemovedValues:  true,
+	}, testUserID)
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+
+	restored, err := store.RestoreArchivedPropertyValues(board.ID, "notes", testUserID)
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	require.Equal(t, card.ID, restored[0].ID)
+	require.Equal(t, "some notes", getCardProperties(t, store, card.ID)["notes"])
+
+	archived, err := store.GetArchivedPropertyValues(board.ID)
+	require.NoError(t, err)
+	require.Empty(t, archived)
+
+	restored, err = store.RestoreArchivedPropertyValues(board.ID, "notes", testUserID)
+	require.NoError(t, err)
+	require.Empty(t, restored)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestArchivingStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("SetBlockArchiveAt", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testSetBlockArchiveAt(t, store)
+	})
+
+	t.Run("SetBoardArchiveAt", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testSetBoardArchiveAt(t, store)
+	})
+}
+
+func testSetBlockArchiveAt(t *testing.T, store store.Store) {
+	board, card := createBoardWithStatusCard(t, store)
+	other := &model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+	}
+	require.NoError(t, store.InsertBlock(other, testUserID))
+	time.Sleep(10 * time.Millisecond)
+
+	opts := model.QueryBlocksOptions{
+		BoardID:         board.ID,
+		BlockType:       model.TypeCard,
+		ExcludeArchived: true,
+	}
+
+	t.Run("archive a card", func(t *testing.T) {
+		archived, err := store.SetBlockArchiveAt(card.ID, 1000, testUserID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1000, archived.ArchiveAt)
+
+		block, err := store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1000, block.ArchiveAt)
+		require.Equal(t, "doing", block.Fields["properties"].(map[string]interface{})["status"])
+
+		blocks, err := store.GetBlocks(opts)
+		require.NoError(t, err)
+		require.Len(t, blocks, 1)
+		require.Equal(t, other.ID, blocks[0].ID)
+
+		// the archived cards are still part of the board
+		blocks, err = store.GetBlocksForBoard(board.ID)
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+
+		history, err := store.GetBlockHistory(card.ID, model.QueryBlockHistoryOptions{Limit: 1, Descending: true})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.EqualValues(t, 1000, history[0].ArchiveAt)
+	})
+
+	t.Run("the archived state survives a deletion", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, store.DeleteBlock(card.ID, testUserID))
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, store.UndeleteBlock(card.ID, testUserID))
+
+		block, err := store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1000, block.ArchiveAt)
+	})
+
+	t.Run("unarchive a card", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+		unarchived, err := store.SetBlockArchiveAt(card.ID, 0, testUserID)
+		require.NoError(t, err)
+		require.Zero(t, unarchived.ArchiveAt)
+
+		blocks, err := store.GetBlocks(opts)
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+	})
+
+	t.Run("nonexistent block", func(t *testing.T) {
+		_, err := store.SetBlockArchiveAt(utils.NewID(utils.IDTypeCard), 1000, testUserID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testSetBoardArchiveAt(t *testing.T, store store.Store) {
+	board, _ := createBoardWithStatusCard(t, store)
+
+	t.Run("archive a board", func(t *testing.T) {
+		archived, err := store.SetBoardArchiveAt(board.ID, 1000, testUserID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1000, archived.ArchiveAt)
+
+		rBoard, err := store.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1000, rBoard.ArchiveAt)
+		require.Len(t, rBoard.CardProperties, 2)
+
+		history, err := store.GetBoardHistory(board.ID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.EqualValues(t, 1000, history[0].ArchiveAt)
+	})
+
+	t.Run("unarchive a board", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+		unarchived, err := store.SetBoardArchiveAt(board.ID, 0, testUserID)
+		require.NoError(t, err)
+		require.Zero(t, unarchived.ArchiveAt)
+
+		rBoard, err := store.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Zero(t, rBoard.ArchiveAt)
+	})
+
+	t.Run("nonexistent board", func(t *testing.T) {
+		_, err := store.SetBoardArchiveAt(utils.NewID(utils.IDTypeBoard), 1000, testUserID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestAuditRecordsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetAuditRecords", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetAuditRecords(t, store)
+	})
+
+	t.Run("DeleteAuditRecords", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteAuditRecords(t, store)
+	})
+}
+
+func insertAuditRecord(t *testing.T, store store.Store, createAt int64, event, userID, boardID string) *model.AuditRecord {
+	record := &model.AuditRecord{
+		ID:       utils.NewID(utils.IDTypeNone),
+		CreateAt: createAt,
+		Level:    "mod",
+		Event:    event,
+		Status:   "success",
+		UserID:   userID,
+		BoardID:  boardID,
+		Meta:     map[string]interface{}{"boardID": boardID, "count": 2},
+	}
+	require.NoError(t, store.InsertAuditRecord(record))
+	return record
+}
+
+func auditRecordIDs(records []*model.AuditRecord) []string {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+func testGetAuditRecords(t *testing.T, store store.Store) {
+	first := insertAuditRecord(t, store, 1000, "createBoard", "user-1", "board-1")
+	second := insertAuditRecord(t, store, 2000, "deleteBoard", "user-1", "board-1")
+	third := insertAuditRecord(t, store, 2000, "deleteBoard", "user-2", "board-2")
+	fourth := insertAuditRecord(t, store, 3000, "deleteBoard", "user-1", "board-3")
+
+	// records created at the same time are sorted by id
+	sameTime := []*model.AuditRecord{second, third}
+	if second.ID < third.ID {
+		sameTime = []*model.AuditRecord{third, second}
+	}
+
+	t.Run("all the records, the most recent first", func(t *testing.T) {
+		records, hasMore, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{})
+		require.NoError(t, err)
+		require.False(t, hasMore)
+		require.Equal(t, []string{fourth.ID, sameTime[0].ID, sameTime[1].ID, first.ID}, auditRecordIDs(records))
+		require.Equal(t, map[string]interface{}{"boardID": "board-3", "count": float64(2)}, records[0].Meta)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		records, _, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: "user-1", Event: "deleteBoard"})
+		require.NoError(t, err)
+		require.Equal(t, []string{fourth.ID, second.ID}, auditRecordIDs(records))
+
+		records, _, err = store.GetAuditRecords(model.QueryAuditRecordsOptions{BoardID: "board-1"})
+		require.NoError(t, err)
+		require.Equal(t, []string{second.ID, first.ID}, auditRecordIDs(records))
+
+		records, _, err = store.GetAuditRecords(model.QueryAuditRecordsOptions{Status: "fail"})
+		require.NoError(t, err)
+		require.Empty(t, records)
+
+		records, _, err = store.GetAuditRecords(model.QueryAuditRecordsOptions{Since: 2000, Until: 3000})
+		require.NoError(t, err)
+		require.Equal(t, []string{sameTime[0].ID, sameTime[1].ID}, auditRecordIDs(records))
+	})
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		records, hasMore, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{PerPage: 2})
+		require.NoError(t, err)
+		require.True(t, hasMore)
+		require.Equal(t, []string{fourth.ID, sameTime[0].ID}, auditRecordIDs(records))
+
+		records, hasMore, err = store.GetAuditRecords(model.QueryAuditRecordsOptions{PerPage: 2, Cursor: records[1].Cursor()})
+		require.NoError(t, err)
+		require.False(t, hasMore)
+		require.Equal(t, []string{sameTime[1].ID, first.ID}, auditRecordIDs(records))
+	})
+}
+
+func testDeleteAuditRecords(t *testing.T, store store.Store) {
+	insertAuditRecord(t, store, 1000, "createBoard", "user-1", "board-1")
+	insertAuditRecord(t, store, 2000, "deleteBoard", "user-1", "board-1")
+	kept := insertAuditRecord(t, store, 3000, "deleteBoard", "user-1", "board-2")
+
+	deleted, err := store.DeleteAuditRecords(3000)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+
+	records, _, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{kept.ID}, auditRecordIDs(records))
+}
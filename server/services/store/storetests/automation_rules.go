@@ -0,0 +1,177 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestAutomationRulesStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("AutomationRules", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testAutomationRules(t, store)
+	})
+
+	t.Run("AutomationExecutions", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testAutomationExecutions(t, store)
+	})
+}
+
+func newTestAutomationRule(boardID string, triggerType model.AutomationTriggerType) *model.AutomationRule {
+	return &model.AutomationRule{
+		ID:        utils.NewID(utils.IDTypeNone),
+		BoardID:   boardID,
+		Title:     "rule",
+		Enabled:   true,
+		Trigger:   model.AutomationTrigger{Type: triggerType, PropertyID: "due"},
+		Actions:   []model.AutomationAction{{Type: model.AutomationActionPostComment, Text: "hello"}},
+		CreatedBy: testUserID,
+	}
+}
+
+func testAutomationRules(t *testing.T, store store.Store) {
+	rule := newTestAutomationRule(testBoardID, model.AutomationTriggerDateReached)
+	require.NoError(t, store.InsertAutomationRule(rule))
+	require.NotZero(t, rule.CreateAt)
+	require.Equal(t, testUserID, rule.ModifiedBy)
+
+	other := newTestAutomationRule(testBoardID, model.AutomationTriggerCardCreated)
+	require.NoError(t, store.InsertAutomationRule(other))
+	require.NoError(t, store.InsertAutomationRule(newTestAutomationRule("board-2", model.AutomationTriggerDateReached)))
+
+	t.Run("invalid rule", func(t *testing.T) {
+		require.Error(t, store.InsertAutomationRule(&model.AutomationRule{ID: "rule", BoardID: testBoardID}))
+	})
+
+	t.Run("get rules", func(t *testing.T) {
+		got, err := store.GetAutomationRule(rule.ID)
+		require.NoError(t, err)
+		require.Equal(t, rule, got)
+
+		_, err = store.GetAutomationRule("unknown")
+		require.True(t, model.IsErrNotFound(err))
+
+		rules, err := store.GetAutomationRulesForBoard(testBoardID)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+	})
+
+	t.Run("update rule", func(t *testing.T) {
+		rule.Enabled = false
+		rule.Title = "updated"
+		rule.Actions = append(rule.Actions, model.AutomationAction{Type: model.AutomationActionAddChecklist, Items: []string{"a", "b"}})
+		rule.ModifiedBy = "user-2"
+		require.NoError(t, store.UpdateAutomationRule(rule))
+
+		got, err := store.GetAutomationRule(rule.ID)
+		require.NoError(t, err)
+		require.Equal(t, rule, got)
+
+		unknown := newTestAutomationRule(testBoardID, model.AutomationTriggerCardCreated)
+		require.True(t, model.IsErrNotFound(store.UpdateAutomationRule(unknown)))
+	})
+
+	t.Run("get enabled rules with trigger", func(t *testing.T) {
+		rules, err := store.GetEnabledAutomationRulesWithTrigger(model.AutomationTriggerDateReached)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, "board-2", rules[0].BoardID)
+	})
+
+	t.Run("delete rule", func(t *testing.T) {
+		require.NoError(t, store.DeleteAutomationRule(other.ID))
+		require.True(t, model.IsErrNotFound(store.DeleteAutomationRule(other.ID)))
+
+		rules, err := store.GetAutomationRulesForBoard(testBoardID)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+	})
+}
+
+func testAutomationExecutions(t *testing.T, store store.Store) {
+	rule := newTestAutomationRule(testBoardID, model.AutomationTriggerDateReached)
+	require.NoError(t, store.InsertAutomationRule(rule))
+
+	newExecution := func(cardID, triggerKey string, createAt int64) *model.AutomationExecution {
+		return &model.AutomationExecution{
+			ID:         utils.NewID(utils.IDTypeNone),
+			RuleID:     rule.ID,
+			BoardID:    testBoardID,
+			CardID:     cardID,
+			TriggerKey: triggerKey,
+			Status:     model.AutomationExecutionRunning,
+			Results:    []model.AutomationActionResult{},
+			CreateAt:   createAt,
+		}
+	}
+
+	t.Run("executions with a trigger key are added once", func(t *testing.T) {
+		added, err := store.AddAutomationExecution(newExecution("card-1", "due:1000", 1000))
+		require.NoError(t, err)
+		require.True(t, added)
+
+		added, err = store.AddAutomationExecution(newExecution("card-1", "due:1000", 1001))
+		require.NoError(t, err)
+		require.False(t, added)
+
+		added, err = store.AddAutomationExecution(newExecution("card-1", "due:2000", 2000))
+		require.NoError(t, err)
+		require.True(t, added)
+	})
+
+	t.Run("executions without a trigger key are always added", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			added, err := store.AddAutomationExecution(newExecution("card-2", "", 3000+int64(i)))
+			require.NoError(t, err)
+			require.True(t, added)
+		}
+	})
+
+	t.Run("update and get executions", func(t *testing.T) {
+		execution := newExecution("card-3", "", 5000)
+		_, err := store.AddAutomationExecution(execution)
+		require.NoError(t, err)
+
+		execution.Status = model.AutomationExecutionError
+		execution.Results = []model.AutomationActionResult{{Type: model.AutomationActionPostComment, Detail: "post", Error: "failed"}}
+		require.NoError(t, store.UpdateAutomationExecution(execution))
+
+		executions, err := store.GetAutomationExecutions(rule.ID, 2)
+		require.NoError(t, err)
+		require.Len(t, executions, 2)
+		require.Equal(t, execution, executions[0])
+		require.Equal(t, int64(3001), executions[1].CreateAt)
+
+		executions, err = store.GetAutomationExecutions(rule.ID, 0)
+		require.NoError(t, err)
+		require.Len(t, executions, 5)
+	})
+
+	t.Run("delete old executions", func(t *testing.T) {
+		deleted, err := store.DeleteAutomationExecutionsBefore(2500)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		executions, err := store.GetAutomationExecutions(rule.ID, 0)
+		require.NoError(t, err)
+		require.Len(t, executions, 3)
+	})
+
+	t.Run("deleting a rule deletes its executions", func(t *testing.T) {
+		require.NoError(t, store.DeleteAutomationRule(rule.ID))
+
+		executions, err := store.GetAutomationExecutions(rule.ID, 0)
+		require.NoError(t, err)
+		require.Empty(t, executions)
+	})
+}
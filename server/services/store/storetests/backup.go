@@ -0,0 +1,133 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+func StoreTestBackupStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetSchemaVersion", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetSchemaVersion(t, store)
+	})
+
+	t.Run("DumpAndRestoreDatabase", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDumpAndRestoreDatabase(t, store)
+	})
+
+	t.Run("BackupAndRestoreDatabaseFile", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testBackupAndRestoreDatabaseFile(t, store)
+	})
+}
+
+// memoryDump keeps the rows of a database dump in memory.
+type memoryDump struct {
+	tables []string
+	rows   map[string][]map[string]interface{}
+}
+
+func (d *memoryDump) WriteRow(table string, row map[string]interface{}) error {
+	d.rows[table] = append(d.rows[table], row)
+	return nil
+}
+
+func (d *memoryDump) Tables() []string {
+	return d.tables
+}
+
+func (d *memoryDump) ReadRows(table string, fn func(row map[string]interface{}) error) error {
+	for _, row := range d.rows[table] {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func testGetSchemaVersion(t *testing.T, store store.Store) {
+	version, err := store.GetSchemaVersion()
+	require.NoError(t, err)
+	require.Greater(t, version, 40)
+}
+
+func testDumpAndRestoreDatabase(t *testing.T, store store.Store) {
+	board := insertTrashBoard(t, store, "backed up board")
+	card := insertUndoCard(t, store, board.ID, "backed up card")
+
+	dump := &memoryDump{rows: map[string][]map[string]interface{}{}}
+	counts, err := store.DumpDatabase(dump)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), counts["boards"])
+	require.Equal(t, int64(1), counts["blocks"])
+	require.NotContains(t, counts, "schema_migrations")
+
+	tables, err := store.GetBackupTables()
+	require.NoError(t, err)
+	require.Len(t, counts, len(tables))
+	dump.tables = tables
+
+	// changes after the dump are lost on restore
+	require.NoError(t, store.DeleteBlock(card.ID, testUserID))
+	insertTrashBoard(t, store, "board created after the backup")
+
+	restored, err := store.RestoreDatabase(dump)
+	require.NoError(t, err)
+	require.Equal(t, counts, restored)
+
+	boards, err := store.GetBoardsForUserAndTeam(testUserID, testTeamID, true)
+	require.NoError(t, err)
+	require.Len(t, boards, 1)
+	require.Equal(t, board.ID, boards[0].ID)
+
+	block, err := store.GetBlock(card.ID)
+	require.NoError(t, err)
+	require.Equal(t, "backed up card", block.Title)
+
+	t.Run("unknown tables are rejected", func(t *testing.T) {
+		_, err := store.RestoreDatabase(&memoryDump{tables: []string{"unknown_table"}})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func testBackupAndRestoreDatabaseFile(t *testing.T, store store.Store) {
+	path := filepath.Join(t.TempDir(), "backup.sqlite")
+
+	if store.DBType() != model.SqliteDBType {
+		_, err := store.BackupDatabaseFile(path)
+		require.Error(t, err)
+		return
+	}
+
+	board := insertTrashBoard(t, store, "backed up board")
+	card := insertUndoCard(t, store, board.ID, "backed up card")
+
+	counts, err := store.BackupDatabaseFile(path)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), counts["boards"])
+	require.Equal(t, int64(1), counts["blocks"])
+
+	require.NoError(t, store.DeleteBoard(board.ID, testUserID))
+
+	restored, err := store.RestoreDatabaseFile(path)
+	require.NoError(t, err)
+	require.Equal(t, counts, restored)
+
+	_, err = store.GetBoard(board.ID)
+	require.NoError(t, err)
+	block, err := store.GetBlock(card.ID)
+	require.NoError(t, err)
+	require.Equal(t, "backed up card", block.Title)
+}
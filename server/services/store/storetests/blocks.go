@@ -72,6 +72,16 @@ func StoreTestBlocksStore(t *testing.T, setup func(t *testing.T) (store.Store, f
 		defer tearDown()
 		testDuplicateBlock(t, store)
 	})
+	t.Run("MoveCardBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testMoveCardBlocks(t, store)
+	})
+	t.Run("ImportCSVBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testImportCSVBlocks(t, store)
+	})
 	t.Run("GetBlockMetadata", func(t *testing.T) {
 		store, tearDown := setup(t)
 		defer tearDown()
@@ -87,6 +97,11 @@ func StoreTestBlocksStore(t *testing.T, setup func(t *testing.T) (store.Store, f
 		defer tearDown()
 		testGetBlockHistoryNewestChildren(t, store)
 	})
+	t.Run("GetCardsReferencingCard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetCardsReferencingCard(t, store)
+	})
 }
 
 func testInsertBlock(t *testing.T, store store.Store) {
@@ -1347,3 +1362,206 @@ func testGetBlockHistoryNewestChildren(t *testing.T, store store.Store) {
 		}
 	})
 }
+
+func testGetCardsReferencingCard(t *testing.T, store store.Store) {
+	newCard := func(id string, related ...string) *model.Block {
+		return &model.Block{
+			ID:      id,
+			BoardID: testBoardID,
+			Type:    model.TypeCard,
+			Fields: map[string]interface{}{
+				"properties": map[string]interface{}{"relation-prop": related},
+			},
+		}
+	}
+
+	blocks := []*model.Block{
+		newCard("card-1"),
+		newCard("card-2", "card-1"),
+		newCard("card-3", "card-1", "card-2"),
+		newCard("card-4", "card-10"),
+		{ID: "card-5", BoardID: "other-board-id", Type: model.TypeCard, Fields: map[string]interface{}{
+			"properties": map[string]interface{}{"relation-prop": []string{"card-1"}},
+		}},
+		{ID: "text-1", BoardID: testBoardID, Type: model.TypeText, Fields: map[string]interface{}{"ref": "card-1"}},
+	}
+	err := store.InsertBlocks(blocks, testUserID)
+	require.NoError(t, err)
+
+	t.Run("returns the cards referencing the card", func(t *testing.T) {
+		cards, err := store.GetCardsReferencingCard("card-1", []string{testBoardID})
+		require.NoError(t, err)
+		ids := []string{}
+		for _, card := range cards {
+			ids = append(ids, card.ID)
+		}
+		require.ElementsMatch(t, []string{"card-2", "card-3"}, ids)
+	})
+
+	t.Run("only searches the given boards", func(t *testing.T) {
+		cards, err := store.GetCardsReferencingCard("card-1", []string{"other-board-id"})
+		require.NoError(t, err)
+		require.Len(t, cards, 1)
+		require.Equal(t, "card-5", cards[0].ID)
+
+		cards, err = store.GetCardsReferencingCard("card-1", []string{})
+		require.NoError(t, err)
+		require.Empty(t, cards)
+	})
+
+	t.Run("excludes deleted cards", func(t *testing.T) {
+		require.NoError(t, store.DeleteBlock("card-3", testUserID))
+
+		cards, err := store.GetCardsReferencingCard("card-1", []string{testBoardID})
+		require.NoError(t, err)
+		require.Len(t, cards, 1)
+		require.Equal(t, "card-2", cards[0].ID)
+	})
+
+	t.Run("returns an empty list if no card references it", func(t *testing.T) {
+		cards, err := store.GetCardsReferencingCard("card-4", []string{testBoardID})
+		require.NoError(t, err)
+		require.Empty(t, cards)
+	})
+}
+
+func testMoveCardBlocks(t *testing.T, store store.Store) {
+	userID := testUserID
+	target := insertTrashBoard(t, store, "target board")
+	now := utils.GetMillis()
+	blocks := []*model.Block{
+		{ID: "card", BoardID: testBoardID, ParentID: testBoardID, Type: model.TypeCard, CreateAt: now, UpdateAt: now},
+		{ID: "comment", BoardID: testBoardID, ParentID: "card", Type: model.TypeComment, CreateAt: now, UpdateAt: now},
+		{ID: "other-card", BoardID: testBoardID, ParentID: testBoardID, Type: model.TypeCard, Fields: map[string]interface{}{}, CreateAt: now, UpdateAt: now},
+	}
+	require.NoError(t, store.InsertBlocks(blocks, userID))
+
+	copyOf := func(id, parentID string, blockType model.BlockType) *model.Block {
+		return &model.Block{ID: id, BoardID: target.ID, ParentID: parentID, Type: blockType, CreateAt: now, UpdateAt: now}
+	}
+
+	t.Run("copy the card and its content and delete the source", func(t *testing.T) {
+		board, err := store.MoveCardBlocks(&model.CardMoveBlocks{
+			TargetBoardID: target.ID,
+			Blocks: []*model.Block{
+				copyOf("card-copy", target.ID, model.TypeCard),
+				copyOf("comment-copy", "card-copy", model.TypeComment),
+			},
+			SourceCardID: "card",
+		}, userID)
+		require.NoError(t, err)
+		require.Nil(t, board)
+
+		copied, err := store.GetBlocksForBoard(target.ID)
+		require.NoError(t, err)
+		require.Len(t, copied, 2)
+
+		_, err = store.GetBlock("card")
+		require.True(t, model.IsErrNotFound(err))
+		_, err = store.GetBlock("comment")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("patch the target board and archive the source", func(t *testing.T) {
+		board, err := store.MoveCardBlocks(&model.CardMoveBlocks{
+			TargetBoardID: target.ID,
+			TargetBoardPatch: &model.BoardPatch{
+				UpdatedCardProperties: []map[string]interface{}{{"id": "status", "name": "Status", "type": "select"}},
+			},
+			Blocks:       []*model.Block{copyOf("other-card-copy", target.ID, model.TypeCard)},
+			SourceCardID: "other-card",
+			SourcePatch: &model.BlockPatch{
+				UpdatedFields: map[string]interface{}{model.CardFieldMovedToCardID: "other-card-copy"},
+			},
+			ArchiveSource: true,
+		}, userID)
+		require.NoError(t, err)
+		require.Len(t, board.CardProperties, 1)
+
+		source, err := store.GetBlock("other-card")
+		require.NoError(t, err)
+		require.NotZero(t, source.ArchiveAt)
+		require.Equal(t, "other-card-copy", source.Fields[model.CardFieldMovedToCardID])
+	})
+
+	t.Run("nothing is written if a write fails", func(t *testing.T) {
+		if store.DBType() == model.SqliteDBType {
+			t.Skip("No transactions support int sqlite")
+		}
+
+		_, err := store.MoveCardBlocks(&model.CardMoveBlocks{
+			TargetBoardID: target.ID,
+			Blocks:        []*model.Block{copyOf("missing-copy", target.ID, model.TypeCard)},
+			SourceCardID:  "missing",
+			SourcePatch:   &model.BlockPatch{UpdatedFields: map[string]interface{}{"key": "value"}},
+		}, userID)
+		require.Error(t, err)
+
+		_, err = store.GetBlock("missing-copy")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testImportCSVBlocks(t *testing.T, store store.Store) {
+	userID := testUserID
+	board := insertTrashBoard(t, store, "import board")
+	now := utils.GetMillis()
+	card := &model.Block{ID: "card", BoardID: board.ID, ParentID: board.ID, Type: model.TypeCard, Fields: map[string]interface{}{}, CreateAt: now, UpdateAt: now}
+	require.NoError(t, store.InsertBlock(card, userID))
+
+	newCard := func(id string) *model.Block {
+		return &model.Block{ID: id, BoardID: board.ID, ParentID: board.ID, Type: model.TypeCard, CreateAt: now, UpdateAt: now}
+	}
+
+	t.Run("patch the board, insert the new cards and patch the others", func(t *testing.T) {
+		title := "updated"
+		patched, err := store.ImportCSVBlocks(&model.CSVImportBlocks{
+			BoardID: board.ID,
+			BoardPatch: &model.BoardPatch{
+				UpdatedCardProperties: []map[string]interface{}{{"id": "status", "name": "Status", "type": "select"}},
+			},
+			Blocks: []*model.Block{newCard("new-card")},
+			BlockPatches: &model.BlockPatchBatch{
+				BlockIDs:     []string{"card"},
+				BlockPatches: []model.BlockPatch{{Title: &title}},
+			},
+		}, userID)
+		require.NoError(t, err)
+		require.Len(t, patched.CardProperties, 1)
+
+		_, err = store.GetBlock("new-card")
+		require.NoError(t, err)
+		updated, err := store.GetBlock("card")
+		require.NoError(t, err)
+		require.Equal(t, "updated", updated.Title)
+	})
+
+	t.Run("the board is not patched without options", func(t *testing.T) {
+		patched, err := store.ImportCSVBlocks(&model.CSVImportBlocks{
+			BoardID: board.ID,
+			Blocks:  []*model.Block{newCard("other-new-card")},
+		}, userID)
+		require.NoError(t, err)
+		require.Nil(t, patched)
+	})
+
+	t.Run("nothing is written if a write fails", func(t *testing.T) {
+		if store.DBType() == model.SqliteDBType {
+			t.Skip("No transactions support int sqlite")
+		}
+
+		title := "missing"
+		_, err := store.ImportCSVBlocks(&model.CSVImportBlocks{
+			BoardID: board.ID,
+			Blocks:  []*model.Block{newCard("unwritten-card")},
+			BlockPatches: &model.BlockPatchBatch{
+				BlockIDs:     []string{"missing"},
+				BlockPatches: []model.BlockPatch{{Title: &title}},
+			},
+		}, userID)
+		require.Error(t, err)
+
+		_, err = store.GetBlock("unwritten-card")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
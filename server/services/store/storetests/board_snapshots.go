@@ -0,0 +1,270 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestBoardSnapshotsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetBoardVersions", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBoardVersions(t, store)
+	})
+
+	t.Run("GetBoardSnapshot", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBoardSnapshot(t, store)
+	})
+
+	t.Run("RestoreBoardSnapshot", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testRestoreBoardSnapshot(t, store)
+	})
+}
+
+// boardSnapshotFixture holds a board with two versions: at first, card A
+// with a text child; at second, card A renamed, its child deleted and a
+// new card B.
+type boardSnapshotFixture struct {
+	board  *model.Board
+	cardA  *model.Block
+	textA  *model.Block
+	cardB  *model.Block
+	first  int64
+	second int64
+}
+
+// waitMillis waits for the clock to move on, to avoid hitting the pk
+// uniqueness constraint in history and to separate the versions.
+func waitMillis() {
+	time.Sleep(10 * time.Millisecond)
+}
+
+func createBoardSnapshotFixture(t *testing.T, store store.Store) *boardSnapshotFixture {
+	f := &boardSnapshotFixture{}
+
+	board, err := store.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		Title:  "first title",
+	}, testUserID)
+	require.NoError(t, err)
+	f.board = board
+
+	f.cardA = &model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		Title:    "card A",
+		Fields:   map[string]interface{}{},
+	}
+	require.NoError(t, store.InsertBlock(f.cardA, testUserID))
+
+	f.textA = &model.Block{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  board.ID,
+		ParentID: f.cardA.ID,
+		Type:     model.TypeText,
+		Title:    "some text",
+		Fields:   map[string]interface{}{},
+	}
+	require.NoError(t, store.InsertBlock(f.textA, testUserID))
+	f.textA, err = store.GetBlock(f.textA.ID)
+	require.NoError(t, err)
+
+	waitMillis()
+	f.first = utils.GetMillis()
+	waitMillis()
+
+	newTitle := "renamed title"
+	_, err = store.PatchBoard(board.ID, &model.BoardPatch{Title: &newTitle}, testUserID)
+	require.NoError(t, err)
+
+	newCardTitle := "card A renamed"
+	require.NoError(t, store.PatchBlock(f.cardA.ID, &model.BlockPatch{Title: &newCardTitle}, testUserID))
+	require.NoError(t, store.DeleteBlock(f.textA.ID, testUserID))
+
+	f.cardB = &model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		Title:    "card B",
+		Fields:   map[string]interface{}{},
+	}
+	require.NoError(t, store.InsertBlock(f.cardB, testUserID))
+
+	waitMillis()
+	f.second = utils.GetMillis()
+	waitMillis()
+
+	return f
+}
+
+func blockTitles(blocks []*model.Block) map[string]string {
+	titles := make(map[string]string, len(blocks))
+	for _, block := range blocks {
+		titles[block.ID] = block.Title
+	}
+	return titles
+}
+
+func testGetBoardVersions(t *testing.T, store store.Store) {
+	f := createBoardSnapshotFixture(t, store)
+
+	t.Run("newest first", func(t *testing.T) {
+		versions, err := store.GetBoardVersions(f.board.ID, 0, 100)
+		require.NoError(t, err)
+		require.NotEmpty(t, versions)
+
+		for i := 1; i < len(versions); i++ {
+			require.GreaterOrEqual(t, versions[i-1].UpdateAt, versions[i].UpdateAt)
+		}
+
+		boardChanges := 0
+		for _, version := range versions {
+			require.Equal(t, testUserID, version.ModifiedBy)
+			if version.BoardChanged {
+				boardChanges++
+			}
+		}
+		require.Equal(t, 2, boardChanges)
+	})
+
+	t.Run("before and limit", func(t *testing.T) {
+		versions, err := store.GetBoardVersions(f.board.ID, f.first, 100)
+		require.NoError(t, err)
+		require.NotEmpty(t, versions)
+		for _, version := range versions {
+			require.Less(t, version.UpdateAt, f.first)
+		}
+
+		versions, err = store.GetBoardVersions(f.board.ID, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+		require.Greater(t, versions[0].UpdateAt, f.first)
+	})
+
+	t.Run("unknown board", func(t *testing.T) {
+		versions, err := store.GetBoardVersions("unknown", 0, 100)
+		require.NoError(t, err)
+		require.Empty(t, versions)
+	})
+}
+
+func testGetBoardSnapshot(t *testing.T, store store.Store) {
+	f := createBoardSnapshotFixture(t, store)
+
+	t.Run("first version", func(t *testing.T) {
+		snapshot, err := store.GetBoardSnapshot(f.board.ID, f.first)
+		require.NoError(t, err)
+		require.Equal(t, "first title", snapshot.Board.Title)
+		require.Equal(t, map[string]string{
+			f.cardA.ID: "card A",
+			f.textA.ID: "some text",
+		}, blockTitles(snapshot.Blocks))
+	})
+
+	t.Run("second version", func(t *testing.T) {
+		snapshot, err := store.GetBoardSnapshot(f.board.ID, f.second)
+		require.NoError(t, err)
+		require.Equal(t, "renamed title", snapshot.Board.Title)
+		require.Equal(t, map[string]string{
+			f.cardA.ID: "card A renamed",
+			f.cardB.ID: "card B",
+		}, blockTitles(snapshot.Blocks))
+	})
+
+	t.Run("before the board existed", func(t *testing.T) {
+		_, err := store.GetBoardSnapshot(f.board.ID, f.board.CreateAt-1)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testRestoreBoardSnapshot(t *testing.T, store store.Store) {
+	t.Run("whole board", func(t *testing.T) {
+		f := createBoardSnapshotFixture(t, store)
+
+		restore, err := store.RestoreBoardSnapshot(f.board.ID, f.first, nil, testUserID)
+		require.NoError(t, err)
+		require.NotNil(t, restore.Board)
+		require.Equal(t, "first title", restore.Board.Title)
+		require.Len(t, restore.Blocks, 2)
+		require.Equal(t, []string{f.cardB.ID}, restore.DeletedBlockIDs)
+
+		board, err := store.GetBoard(f.board.ID)
+		require.NoError(t, err)
+		require.Equal(t, "first title", board.Title)
+
+		blocks, err := store.GetBlocksForBoard(f.board.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			f.cardA.ID: "card A",
+			f.textA.ID: "some text",
+		}, blockTitles(blocks))
+
+		textA, err := store.GetBlock(f.textA.ID)
+		require.NoError(t, err)
+		require.NotZero(t, textA.CreateAt)
+		require.Equal(t, f.textA.CreateAt, textA.CreateAt)
+
+		// the restore is itself a version that can be restored
+		waitMillis()
+		restore, err = store.RestoreBoardSnapshot(f.board.ID, f.second, nil, testUserID)
+		require.NoError(t, err)
+		require.Equal(t, []string{f.textA.ID}, restore.DeletedBlockIDs)
+
+		blocks, err = store.GetBlocksForBoard(f.board.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			f.cardA.ID: "card A renamed",
+			f.cardB.ID: "card B",
+		}, blockTitles(blocks))
+	})
+
+	t.Run("selected cards", func(t *testing.T) {
+		f := createBoardSnapshotFixture(t, store)
+
+		restore, err := store.RestoreBoardSnapshot(f.board.ID, f.first, []string{f.cardA.ID}, testUserID)
+		require.NoError(t, err)
+		require.Nil(t, restore.Board)
+		require.Len(t, restore.Blocks, 2)
+		require.Empty(t, restore.DeletedBlockIDs)
+
+		board, err := store.GetBoard(f.board.ID)
+		require.NoError(t, err)
+		require.Equal(t, "renamed title", board.Title)
+
+		blocks, err := store.GetBlocksForBoard(f.board.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			f.cardA.ID: "card A",
+			f.textA.ID: "some text",
+			f.cardB.ID: "card B",
+		}, blockTitles(blocks))
+	})
+
+	t.Run("nothing to restore", func(t *testing.T) {
+		f := createBoardSnapshotFixture(t, store)
+
+		restore, err := store.RestoreBoardSnapshot(f.board.ID, f.second, nil, testUserID)
+		require.NoError(t, err)
+		require.Nil(t, restore.Board)
+		require.Empty(t, restore.Blocks)
+		require.Empty(t, restore.DeletedBlockIDs)
+	})
+}
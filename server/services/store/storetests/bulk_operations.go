@@ -0,0 +1,90 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestBulkOperationsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("BulkOperations", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testBulkOperations(t, store)
+	})
+}
+
+func testBulkOperations(t *testing.T, store store.Store) {
+	operation := &model.BulkOperation{
+		ID:      utils.NewID(utils.IDTypeNone),
+		BoardID: utils.NewID(utils.IDTypeBoard),
+		UserID:  testUserID,
+		Type:    model.BulkOperationArchive,
+		Status:  model.BulkOperationStatusRunning,
+		Total:   3,
+	}
+
+	t.Run("insert and get an operation", func(t *testing.T) {
+		require.NoError(t, store.InsertBulkOperation(operation))
+		require.NotZero(t, operation.CreateAt)
+
+		stored, err := store.GetBulkOperation(operation.ID)
+		require.NoError(t, err)
+		require.Equal(t, operation, stored)
+	})
+
+	t.Run("update an operation", func(t *testing.T) {
+		operation.Processed = 2
+		operation.Status = model.BulkOperationStatusFailed
+		operation.Error = "card not found"
+		require.NoError(t, store.UpdateBulkOperation(operation))
+
+		stored, err := store.GetBulkOperation(operation.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, stored.Processed)
+		require.Equal(t, model.BulkOperationStatusFailed, stored.Status)
+		require.Equal(t, "card not found", stored.Error)
+	})
+
+	t.Run("fail the stale running operations", func(t *testing.T) {
+		running := &model.BulkOperation{
+			ID:      utils.NewID(utils.IDTypeNone),
+			BoardID: operation.BoardID,
+			UserID:  testUserID,
+			Type:    model.BulkOperationDelete,
+			Status:  model.BulkOperationStatusRunning,
+			Total:   200,
+		}
+		require.NoError(t, store.InsertBulkOperation(running))
+
+		failed, err := store.FailStaleBulkOperations(running.UpdateAt, "interrupted")
+		require.NoError(t, err)
+		require.Zero(t, failed)
+
+		failed, err = store.FailStaleBulkOperations(running.UpdateAt+1, "interrupted")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), failed)
+
+		stored, err := store.GetBulkOperation(running.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.BulkOperationStatusFailed, stored.Status)
+		require.Equal(t, "interrupted", stored.Error)
+
+		// the operations already done are left untouched
+		stored, err = store.GetBulkOperation(operation.ID)
+		require.NoError(t, err)
+		require.Equal(t, "card not found", stored.Error)
+	})
+
+	t.Run("get a missing operation", func(t *testing.T) {
+		_, err := store.GetBulkOperation(utils.NewID(utils.IDTypeNone))
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
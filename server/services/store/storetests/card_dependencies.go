@@ -0,0 +1,111 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+func StoreTestCardDependenciesStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("AddCardDependency", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testAddCardDependency(t, store)
+	})
+
+	t.Run("DeleteCardDependency", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteCardDependency(t, store)
+	})
+
+	t.Run("GetCardDependenciesForBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetCardDependenciesForBoard(t, store)
+	})
+}
+
+func testAddCardDependency(t *testing.T, store store.Store) {
+	t.Run("add a dependency", func(t *testing.T) {
+		dependency := &model.CardDependency{BlockerID: "card-1", BlockedID: "card-2", CreatedBy: testUserID}
+		require.NoError(t, store.AddCardDependency(dependency))
+		require.NotZero(t, dependency.CreateAt)
+
+		dependencies, err := store.GetCardDependencies("card-1")
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+		require.Equal(t, dependency, dependencies[0])
+
+		dependencies, err = store.GetCardDependencies("card-2")
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+		require.Equal(t, dependency, dependencies[0])
+	})
+
+	t.Run("adding an existing dependency is not an error", func(t *testing.T) {
+		dependency := &model.CardDependency{BlockerID: "card-1", BlockedID: "card-2", CreatedBy: testUserID}
+		require.NoError(t, store.AddCardDependency(dependency))
+
+		dependencies, err := store.GetCardDependencies("card-1")
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+	})
+
+	t.Run("invalid dependency", func(t *testing.T) {
+		err := store.AddCardDependency(&model.CardDependency{BlockerID: "card-1", BlockedID: "card-1"})
+		var invalidErr model.ErrInvalidCardDependency
+		require.ErrorAs(t, err, &invalidErr)
+	})
+}
+
+func testDeleteCardDependency(t *testing.T, store store.Store) {
+	require.NoError(t, store.AddCardDependency(&model.CardDependency{BlockerID: "card-1", BlockedID: "card-2"}))
+	require.NoError(t, store.AddCardDependency(&model.CardDependency{BlockerID: "card-1", BlockedID: "card-3"}))
+
+	t.Run("delete a dependency", func(t *testing.T) {
+		require.NoError(t, store.DeleteCardDependency("card-1", "card-2"))
+
+		dependencies, err := store.GetCardDependencies("card-1")
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+		require.Equal(t, "card-3", dependencies[0].BlockedID)
+	})
+
+	t.Run("delete a non-existing dependency", func(t *testing.T) {
+		err := store.DeleteCardDependency("card-1", "card-2")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testGetCardDependenciesForBoard(t *testing.T, store store.Store) {
+	blocks := []*model.Block{
+		{ID: "card-1", BoardID: "board-1", Type: model.TypeCard},
+		{ID: "card-2", BoardID: "board-1", Type: model.TypeCard},
+		{ID: "card-3", BoardID: "board-2", Type: model.TypeCard},
+		{ID: "card-4", BoardID: "board-2", Type: model.TypeCard},
+	}
+	require.NoError(t, store.InsertBlocks(blocks, testUserID))
+
+	require.NoError(t, store.AddCardDependency(&model.CardDependency{BlockerID: "card-1", BlockedID: "card-2"}))
+	require.NoError(t, store.AddCardDependency(&model.CardDependency{BlockerID: "card-3", BlockedID: "card-1"}))
+	require.NoError(t, store.AddCardDependency(&model.CardDependency{BlockerID: "card-3", BlockedID: "card-4"}))
+
+	dependencies, err := store.GetCardDependenciesForBoard("board-1")
+	require.NoError(t, err)
+	require.Len(t, dependencies, 2)
+
+	dependencies, err = store.GetCardDependenciesForBoard("board-2")
+	require.NoError(t, err)
+	require.Len(t, dependencies, 2)
+
+	dependencies, err = store.GetCardDependenciesForBoard("board-3")
+	require.NoError(t, err)
+	require.Empty(t, dependencies)
+}
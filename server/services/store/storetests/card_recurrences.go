@@ -0,0 +1,150 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+func StoreTestCardRecurrencesStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("UpsertCardRecurrence", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpsertCardRecurrence(t, store)
+	})
+
+	t.Run("DeleteCardRecurrence", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteCardRecurrence(t, store)
+	})
+
+	t.Run("GetDueCardRecurrences", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetDueCardRecurrences(t, store)
+	})
+
+	t.Run("ClaimCardRecurrence", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testClaimCardRecurrence(t, store)
+	})
+}
+
+func createTestCardRecurrence(t *testing.T, store store.Store, cardID string, nextRunAt int64) *model.CardRecurrence {
+	recurrence := &model.CardRecurrence{
+		CardID:         cardID,
+		BoardID:        testBoardID,
+		Rule:           "FREQ=DAILY",
+		DatePropertyID: "date-prop",
+		CreatedBy:      testUserID,
+		StartAt:        1000,
+		NextRunAt:      nextRunAt,
+	}
+	require.NoError(t, store.UpsertCardRecurrence(recurrence))
+	return recurrence
+}
+
+func testUpsertCardRecurrence(t *testing.T, store store.Store) {
+	t.Run("create a recurrence", func(t *testing.T) {
+		recurrence := createTestCardRecurrence(t, store, "card-1", 2000)
+		require.NotZero(t, recurrence.CreateAt)
+		require.NotZero(t, recurrence.UpdateAt)
+
+		rRecurrence, err := store.GetCardRecurrence("card-1")
+		require.NoError(t, err)
+		require.Equal(t, recurrence, rRecurrence)
+	})
+
+	t.Run("update a recurrence", func(t *testing.T) {
+		recurrence := &model.CardRecurrence{
+			CardID:    "card-1",
+			BoardID:   testBoardID,
+			Rule:      "FREQ=WEEKLY;BYDAY=MO",
+			CreatedBy: testUserID,
+			StartAt:   3000,
+			NextRunAt: 4000,
+		}
+		require.NoError(t, store.UpsertCardRecurrence(recurrence))
+
+		rRecurrence, err := store.GetCardRecurrence("card-1")
+		require.NoError(t, err)
+		require.Equal(t, "FREQ=WEEKLY;BYDAY=MO", rRecurrence.Rule)
+		require.Empty(t, rRecurrence.DatePropertyID)
+		require.Equal(t, int64(3000), rRecurrence.StartAt)
+		require.Equal(t, int64(4000), rRecurrence.NextRunAt)
+	})
+
+	t.Run("invalid recurrence", func(t *testing.T) {
+		recurrence := &model.CardRecurrence{
+			CardID:    "card-2",
+			BoardID:   testBoardID,
+			Rule:      "FREQ=YEARLY",
+			CreatedBy: testUserID,
+		}
+		require.Error(t, store.UpsertCardRecurrence(recurrence))
+	})
+
+	t.Run("get a nonexistent recurrence", func(t *testing.T) {
+		rRecurrence, err := store.GetCardRecurrence("card-nonexistent")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, rRecurrence)
+	})
+}
+
+func testDeleteCardRecurrence(t *testing.T, store store.Store) {
+	createTestCardRecurrence(t, store, "card-1", 2000)
+
+	require.NoError(t, store.DeleteCardRecurrence("card-1"))
+
+	_, err := store.GetCardRecurrence("card-1")
+	require.True(t, model.IsErrNotFound(err))
+
+	err = store.DeleteCardRecurrence("card-1")
+	require.True(t, model.IsErrNotFound(err))
+}
+
+func testGetDueCardRecurrences(t *testing.T, store store.Store) {
+	createTestCardRecurrence(t, store, "card-1", 3000)
+	createTestCardRecurrence(t, store, "card-2", 1000)
+	createTestCardRecurrence(t, store, "card-3", 5000)
+
+	recurrences, err := store.GetDueCardRecurrences(3000)
+	require.NoError(t, err)
+	require.Len(t, recurrences, 2)
+	require.Equal(t, "card-2", recurrences[0].CardID)
+	require.Equal(t, "card-1", recurrences[1].CardID)
+
+	recurrences, err = store.GetDueCardRecurrences(500)
+	require.NoError(t, err)
+	require.Empty(t, recurrences)
+}
+
+func testClaimCardRecurrence(t *testing.T, store store.Store) {
+	createTestCardRecurrence(t, store, "card-1", 2000)
+
+	claimed, err := store.ClaimCardRecurrence("card-1", 2000, 2500, 3000)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	recurrence, err := store.GetCardRecurrence("card-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(2500), recurrence.LastRunAt)
+	require.Equal(t, int64(3000), recurrence.NextRunAt)
+
+	// the same occurrence cannot be claimed twice
+	claimed, err = store.ClaimCardRecurrence("card-1", 2000, 2500, 3000)
+	require.NoError(t, err)
+	require.False(t, claimed)
+
+	claimed, err = store.ClaimCardRecurrence("card-nonexistent", 2000, 2500, 3000)
+	require.NoError(t, err)
+	require.False(t, claimed)
+}
@@ -36,6 +36,18 @@ func StoreTestDataRetention(t *testing.T, setup func(t *testing.T) (store.Store,
 		testRunDataRetention(t, store, 2)
 		testRunDataRetention(t, store, 10)
 	})
+
+	t.Run("RetentionPolicies", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testRetentionPolicies(t, store)
+	})
+
+	t.Run("GetDataRetentionCandidates", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetDataRetentionCandidates(t, store)
+	})
 }
 
 func LoadData(t *testing.T, store store.Store) {
@@ -137,3 +149,147 @@ func testRunDataRetention(t *testing.T, store store.Store, batchSize int) {
 		require.Empty(t, category)
 	})
 }
+
+func testRetentionPolicies(t *testing.T, store store.Store) {
+	teamPolicy := &model.RetentionPolicy{TeamID: testTeamID, RetentionDays: 30, ModifiedBy: testUserID}
+	require.NoError(t, store.UpsertRetentionPolicy(teamPolicy))
+	boardPolicy := &model.RetentionPolicy{TeamID: testTeamID, BoardID: boardID, RetentionDays: 2555, ModifiedBy: testUserID}
+	require.NoError(t, store.UpsertRetentionPolicy(boardPolicy))
+	require.NoError(t, store.UpsertRetentionPolicy(&model.RetentionPolicy{TeamID: "other-team", RetentionDays: 1}))
+
+	t.Run("invalid policies are rejected", func(t *testing.T) {
+		err := store.UpsertRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, RetentionDays: -1})
+		require.Error(t, err)
+	})
+
+	t.Run("policies are listed by team", func(t *testing.T) {
+		policies, err := store.GetRetentionPolicies(testTeamID)
+		require.NoError(t, err)
+		require.Len(t, policies, 2)
+		require.Equal(t, "", policies[0].BoardID)
+		require.Equal(t, 30, policies[0].RetentionDays)
+		require.Equal(t, boardID, policies[1].BoardID)
+		require.Equal(t, 2555, policies[1].RetentionDays)
+
+		policies, err = store.GetRetentionPolicies("")
+		require.NoError(t, err)
+		require.Len(t, policies, 3)
+	})
+
+	t.Run("upsert replaces the policy", func(t *testing.T) {
+		teamPolicy.RetentionDays = 60
+		require.NoError(t, store.UpsertRetentionPolicy(teamPolicy))
+
+		policies, err := store.GetRetentionPolicies(testTeamID)
+		require.NoError(t, err)
+		require.Len(t, policies, 2)
+		require.Equal(t, 60, policies[0].RetentionDays)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, store.DeleteRetentionPolicy(testTeamID, boardID))
+		err := store.DeleteRetentionPolicy(testTeamID, boardID)
+		require.True(t, model.IsErrNotFound(err))
+
+		policies, err := store.GetRetentionPolicies(testTeamID)
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+	})
+}
+
+func testGetDataRetentionCandidates(t *testing.T, store store.Store) {
+	insertBoard := func(teamID string) *model.Board {
+		board, err := store.InsertBoard(&model.Board{
+			ID:     utils.NewID(utils.IDTypeBoard),
+			TeamID: teamID,
+			Type:   model.BoardTypeOpen,
+		}, testUserID)
+		require.NoError(t, err)
+		require.NoError(t, store.InsertBlock(&model.Block{
+			ID:      utils.NewID(utils.IDTypeBlock),
+			BoardID: board.ID,
+			Type:    model.TypeCard,
+			Fields:  map[string]interface{}{},
+		}, testUserID))
+		return board
+	}
+	scratch := insertBoard(testTeamID)
+	regulatory := insertBoard(testTeamID)
+	other := insertBoard("other-team")
+
+	// ten days after the boards were changed
+	now := utils.GetMillisForTime(time.Now().Add(10 * 24 * time.Hour))
+	global := now - int64(time.Hour/time.Millisecond)
+
+	candidateScopes := func(t *testing.T, globalRetentionDate int64) map[string]model.RetentionScope {
+		candidates, err := store.GetDataRetentionCandidates(globalRetentionDate, now)
+		require.NoError(t, err)
+		scopes := map[string]model.RetentionScope{}
+		for _, candidate := range candidates {
+			scopes[candidate.BoardID] = candidate.Scope
+		}
+		return scopes
+	}
+
+	t.Run("without policies", func(t *testing.T) {
+		require.Empty(t, candidateScopes(t, 0))
+		require.Equal(t, map[string]model.RetentionScope{
+			scratch.ID:    model.RetentionScopeGlobal,
+			regulatory.ID: model.RetentionScopeGlobal,
+			other.ID:      model.RetentionScopeGlobal,
+		}, candidateScopes(t, global))
+	})
+
+	require.NoError(t, store.UpsertRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, RetentionDays: 5}))
+	require.NoError(t, store.UpsertRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, BoardID: regulatory.ID, RetentionDays: 2555}))
+
+	t.Run("the board policy takes precedence over the team one", func(t *testing.T) {
+		require.Equal(t, map[string]model.RetentionScope{
+			scratch.ID: model.RetentionScopeTeam,
+		}, candidateScopes(t, 0))
+	})
+
+	t.Run("the team policy takes precedence over the global one", func(t *testing.T) {
+		require.NoError(t, store.UpsertRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, RetentionDays: 0}))
+		require.Equal(t, map[string]model.RetentionScope{
+			other.ID: model.RetentionScopeGlobal,
+		}, candidateScopes(t, global))
+	})
+
+	t.Run("expired boards are deleted with their policy", func(t *testing.T) {
+		require.NoError(t, store.UpsertRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, BoardID: scratch.ID, RetentionDays: 1}))
+		require.Equal(t, map[string]model.RetentionScope{
+			scratch.ID: model.RetentionScopeBoard,
+		}, candidateScopes(t, 0))
+
+		_, err := store.DeleteDataRetentionBoards([]string{scratch.ID}, 0)
+		require.NoError(t, err)
+
+		_, err = store.GetBoard(scratch.ID)
+		require.True(t, model.IsErrNotFound(err))
+		err = store.DeleteRetentionPolicy(testTeamID, scratch.ID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+	t.Run("the card data of expired boards is deleted", func(t *testing.T) {
+		board := insertTrashBoard(t, store, "expired board")
+		blocker := insertUndoCard(t, store, board.ID, "blocker")
+		blocked := insertUndoCard(t, store, board.ID, "blocked")
+		require.NoError(t, store.AddCardDependency(&model.CardDependency{BlockerID: blocker.ID, BlockedID: blocked.ID}))
+		reminder := &model.DueDateReminder{CardID: blocked.ID, PropertyID: "due", DueAt: 1, LeadTime: 1, SentAt: utils.GetMillis()}
+		added, err := store.AddDueDateReminder(reminder)
+		require.NoError(t, err)
+		require.True(t, added)
+
+		_, err = store.DeleteDataRetentionBoards([]string{board.ID}, 1)
+		require.NoError(t, err)
+
+		dependencies, err := store.GetCardDependencies(blocked.ID)
+		require.NoError(t, err)
+		require.Empty(t, dependencies)
+
+		// the reminder can be sent again once its record is gone
+		added, err = store.AddDueDateReminder(reminder)
+		require.NoError(t, err)
+		require.True(t, added)
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestDueDateRemindersStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetBoardsWithPropertyType", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBoardsWithPropertyType(t, store)
+	})
+
+	t.Run("GetCardsWithDatesBetween", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetCardsWithDatesBetween(t, store)
+	})
+
+	t.Run("AddDueDateReminder", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testAddDueDateReminder(t, store)
+	})
+
+	t.Run("DeleteDueDateRemindersBefore", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteDueDateRemindersBefore(t, store)
+	})
+}
+
+func testGetBoardsWithPropertyType(t *testing.T, store store.Store) {
+	newBoard := func(isTemplate bool, cardProperties ...map[string]interface{}) *model.Board {
+		board, err := store.InsertBoard(&model.Board{
+			ID:             utils.NewID(utils.IDTypeBoard),
+			TeamID:         testTeamID,
+			Type:           model.BoardTypeOpen,
+			IsTemplate:     isTemplate,
+			CardProperties: cardProperties,
+		}, testUserID)
+		require.NoError(t, err)
+		return board
+	}
+
+	withDate := newBoard(false,
+		map[string]interface{}{"id": "status", "name": "Status", "type": "select"},
+		map[string]interface{}{"id": "due", "name": "Due", "type": "date"},
+	)
+	newBoard(false, map[string]interface{}{"id": "status", "name": "date", "type": "select"})
+	newBoard(true, map[string]interface{}{"id": "due", "name": "Due", "type": "date"})
+	newBoard(false)
+
+	boards, err := store.GetBoardsWithPropertyType("date")
+	require.NoError(t, err)
+	require.Len(t, boards, 1)
+	require.Equal(t, withDate.ID, boards[0].ID)
+
+	boards, err = store.GetBoardsWithPropertyType("relation")
+	require.NoError(t, err)
+	require.Empty(t, boards)
+}
+
+func testGetCardsWithDatesBetween(t *testing.T, store store.Store) {
+	newCard := func(id string, properties map[string]interface{}) {
+		now := utils.GetMillis()
+		block := &model.Block{
+			ID:       id,
+			BoardID:  testBoardID,
+			ParentID: testBoardID,
+			Type:     model.TypeCard,
+			Fields:   map[string]interface{}{"properties": properties},
+			CreateAt: now,
+			UpdateAt: now,
+		}
+		require.NoError(t, store.InsertBlock(block, testUserID))
+	}
+
+	newCard("inside", map[string]interface{}{"due": `{"from":1500}`})
+	newCard("range-end-inside", map[string]interface{}{"due": `{"from":500,"to":1500}`})
+	newCard("other-property", map[string]interface{}{"start": `{"from":1500}`, "due": `{"from":3000}`})
+	newCard("outside", map[string]interface{}{"due": `{"from":3000}`})
+	newCard("invalid", map[string]interface{}{"due": "not a date"})
+	newCard("no-date", map[string]interface{}{})
+
+	cardIDs := func(propertyIDs ...string) []string {
+		cards, err := store.GetCardsWithDatesBetween(testBoardID, propertyIDs, 1000, 2000)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, card := range cards {
+			ids = append(ids, card.ID)
+		}
+		return ids
+	}
+
+	require.ElementsMatch(t, []string{"inside", "range-end-inside"}, cardIDs("due"))
+	require.ElementsMatch(t, []string{"inside", "range-end-inside", "other-property"}, cardIDs("due", "start"))
+	require.Empty(t, cardIDs())
+}
+
+func testAddDueDateReminder(t *testing.T, store store.Store) {
+	reminder := &model.DueDateReminder{CardID: "card-1", PropertyID: "due", DueAt: 1000, LeadTime: 60}
+
+	added, err := store.AddDueDateReminder(reminder)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NotZero(t, reminder.SentAt)
+
+	t.Run("the same reminder is only added once", func(t *testing.T) {
+		added, err := store.AddDueDateReminder(&model.DueDateReminder{CardID: "card-1", PropertyID: "due", DueAt: 1000, LeadTime: 60})
+		require.NoError(t, err)
+		require.False(t, added)
+	})
+
+	t.Run("reminders for other lead times or dates are added", func(t *testing.T) {
+		added, err := store.AddDueDateReminder(&model.DueDateReminder{CardID: "card-1", PropertyID: "due", DueAt: 1000, LeadTime: 0})
+		require.NoError(t, err)
+		require.True(t, added)
+
+		added, err = store.AddDueDateReminder(&model.DueDateReminder{CardID: "card-1", PropertyID: "due", DueAt: 2000, LeadTime: 60})
+		require.NoError(t, err)
+		require.True(t, added)
+	})
+
+	t.Run("invalid reminder", func(t *testing.T) {
+		_, err := store.AddDueDateReminder(&model.DueDateReminder{CardID: "card-1"})
+		require.Error(t, err)
+	})
+}
+
+func testDeleteDueDateRemindersBefore(t *testing.T, store store.Store) {
+	for i, sentAt := range []int64{1000, 2000, 3000} {
+		added, err := store.AddDueDateReminder(&model.DueDateReminder{CardID: "card-1", PropertyID: "due", DueAt: int64(i), SentAt: sentAt})
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	deleted, err := store.DeleteDueDateRemindersBefore(2500)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+
+	// deleted reminders can be added again
+	added, err := store.AddDueDateReminder(&model.DueDateReminder{CardID: "card-1", PropertyID: "due", DueAt: 0, SentAt: 4000})
+	require.NoError(t, err)
+	require.True(t, added)
+
+	added, err = store.AddDueDateReminder(&model.DueDateReminder{CardID: "card-1", PropertyID: "due", DueAt: 2, SentAt: 4000})
+	require.NoError(t, err)
+	require.False(t, added)
+}
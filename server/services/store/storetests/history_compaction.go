@@ -0,0 +1,107 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestHistoryCompactionStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CompactBlockHistory", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCompactBlockHistory(t, store)
+	})
+
+	t.Run("CompactBoardHistory", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCompactBoardHistory(t, store)
+	})
+}
+
+func patchCompactedCard(t *testing.T, store store.Store, cardID, title, userID string) {
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, store.PatchBlock(cardID, &model.BlockPatch{Title: &title}, userID))
+}
+
+func testCompactBlockHistory(t *testing.T, store store.Store) {
+	board := insertTrashBoard(t, store, "compacted board")
+	card := insertUndoCard(t, store, board.ID, "v0")
+	patchCompactedCard(t, store, card.ID, "v1", testUserID)
+	patchCompactedCard(t, store, card.ID, "v2", testUserID)
+	patchCompactedCard(t, store, card.ID, "v3", testUserID)
+	patchCompactedCard(t, store, card.ID, "v4", testUserID)
+	patchCompactedCard(t, store, card.ID, "v5", "other-user-id")
+	patchCompactedCard(t, store, card.ID, "v6", testUserID)
+
+	history, err := store.GetBlockHistory(card.ID, model.QueryBlockHistoryOptions{})
+	require.NoError(t, err)
+	require.Len(t, history, 7)
+
+	t.Run("the recent history is kept", func(t *testing.T) {
+		removed, err := store.CompactHistory(time.Hour.Milliseconds(), history[0].UpdateAt)
+		require.NoError(t, err)
+		require.Zero(t, removed["blocks_history"])
+	})
+
+	t.Run("the changes of a user within the window are merged", func(t *testing.T) {
+		removed, err := store.CompactHistory(time.Hour.Milliseconds(), utils.GetMillis()+1000)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), removed["blocks_history"])
+
+		history, err := store.GetBlockHistory(card.ID, model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		titles := []string{}
+		for _, block := range history {
+			titles = append(titles, block.Title)
+		}
+		require.Equal(t, []string{"v0", "v4", "v5", "v6"}, titles)
+
+		block, err := store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.Equal(t, "v6", block.Title)
+	})
+
+	t.Run("the changes outside of the window are kept", func(t *testing.T) {
+		other := insertUndoCard(t, store, board.ID, "v0")
+		patchCompactedCard(t, store, other.ID, "v1", testUserID)
+		patchCompactedCard(t, store, other.ID, "v2", testUserID)
+
+		removed, err := store.CompactHistory(1, utils.GetMillis()+1000)
+		require.NoError(t, err)
+		require.Zero(t, removed["blocks_history"])
+
+		history, err := store.GetBlockHistory(other.ID, model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+	})
+}
+
+func testCompactBoardHistory(t *testing.T, store store.Store) {
+	board := insertTrashBoard(t, store, "v0")
+	for _, title := range []string{"v1", "v2", "v3"} {
+		time.Sleep(2 * time.Millisecond)
+		title := title
+		_, err := store.PatchBoard(board.ID, &model.BoardPatch{Title: &title}, testUserID)
+		require.NoError(t, err)
+	}
+
+	removed, err := store.CompactHistory(time.Hour.Milliseconds(), utils.GetMillis()+1000)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), removed["boards_history"])
+
+	history, err := store.GetBoardHistory(board.ID, model.QueryBoardHistoryOptions{})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "v0", history[0].Title)
+	require.Equal(t, "v3", history[1].Title)
+}
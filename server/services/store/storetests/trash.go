@@ -0,0 +1,227 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestTrashStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetDeletedBoardsAndBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetDeletedBoardsAndBlocks(t, store)
+	})
+
+	t.Run("PurgeBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPurgeBoard(t, store)
+	})
+
+	t.Run("PurgeBlock", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPurgeBlock(t, store)
+	})
+
+	t.Run("PurgeTrash", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPurgeTrash(t, store)
+	})
+}
+
+func insertTrashBoard(t *testing.T, store store.Store, title string) *model.Board {
+	board, _, err := store.InsertBoardWithAdmin(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		Title:  title,
+	}, testUserID)
+	require.NoError(t, err)
+	return board
+}
+
+func insertTrashText(t *testing.T, store store.Store, card *model.Block) *model.Block {
+	text := &model.Block{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  card.BoardID,
+		ParentID: card.ID,
+		Type:     model.TypeText,
+		Fields:   map[string]interface{}{},
+	}
+	require.NoError(t, store.InsertBlock(text, testUserID))
+	return text
+}
+
+func testGetDeletedBoardsAndBlocks(t *testing.T, store store.Store) {
+	board := insertTrashBoard(t, store, "deleted board")
+	kept := insertTrashBoard(t, store, "kept board")
+	card := insertUndoCard(t, store, kept.ID, "deleted card")
+	insertTrashText(t, store, card)
+	insertUndoCard(t, store, kept.ID, "kept card")
+	waitMillis()
+
+	require.NoError(t, store.DeleteBoard(board.ID, "deleter"))
+	require.NoError(t, store.DeleteBlock(card.ID, "deleter"))
+	waitMillis()
+
+	t.Run("deleted boards", func(t *testing.T) {
+		boards, err := store.GetDeletedBoards(testTeamID)
+		require.NoError(t, err)
+		require.Len(t, boards, 1)
+		require.Equal(t, board.ID, boards[0].ID)
+		require.Equal(t, "deleter", boards[0].ModifiedBy)
+		require.NotZero(t, boards[0].DeleteAt)
+
+		boards, err = store.GetDeletedBoards("other-team")
+		require.NoError(t, err)
+		require.Empty(t, boards)
+	})
+
+	t.Run("deleted cards", func(t *testing.T) {
+		cards, err := store.GetDeletedBlocks([]string{kept.ID}, model.TypeCard)
+		require.NoError(t, err)
+		require.Len(t, cards, 1)
+		require.Equal(t, card.ID, cards[0].ID)
+		require.Equal(t, "deleter", cards[0].ModifiedBy)
+
+		blocks, err := store.GetDeletedBlocks([]string{kept.ID}, "")
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+	})
+
+	t.Run("restored items are not listed", func(t *testing.T) {
+		require.NoError(t, store.UndeleteBoard(board.ID, testUserID))
+		require.NoError(t, store.UndeleteBlock(card.ID, testUserID))
+		waitMillis()
+
+		boards, err := store.GetDeletedBoards(testTeamID)
+		require.NoError(t, err)
+		require.Empty(t, boards)
+
+		cards, err := store.GetDeletedBlocks([]string{kept.ID}, model.TypeCard)
+		require.NoError(t, err)
+		require.Empty(t, cards)
+	})
+
+	t.Run("a board deleted twice is listed once", func(t *testing.T) {
+		require.NoError(t, store.DeleteBoard(board.ID, testUserID))
+		waitMillis()
+		require.NoError(t, store.UndeleteBoard(board.ID, testUserID))
+		waitMillis()
+		require.NoError(t, store.DeleteBoard(board.ID, "deleter"))
+
+		boards, err := store.GetDeletedBoards(testTeamID)
+		require.NoError(t, err)
+		require.Len(t, boards, 1)
+		require.Equal(t, "deleter", boards[0].ModifiedBy)
+	})
+}
+
+func testPurgeBoard(t *testing.T, store store.Store) {
+	board := insertTrashBoard(t, store, "board")
+	card := insertUndoCard(t, store, board.ID, "card")
+	require.NoError(t, store.AddCardDependency(&model.CardDependency{
+		BlockerID: card.ID,
+		BlockedID: utils.NewID(utils.IDTypeCard),
+		CreatedBy: testUserID,
+	}))
+	waitMillis()
+
+	t.Run("a board that is not deleted can't be purged", func(t *testing.T) {
+		err := store.PurgeBoard(board.ID)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("an unknown board can't be purged", func(t *testing.T) {
+		err := store.PurgeBoard(utils.NewID(utils.IDTypeBoard))
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("purge removes the board data", func(t *testing.T) {
+		require.NoError(t, store.DeleteBoard(board.ID, testUserID))
+		require.NoError(t, store.PurgeBoard(board.ID))
+
+		boards, err := store.GetBoardHistory(board.ID, model.QueryBoardHistoryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, boards)
+
+		blocks, err := store.GetBlockHistory(card.ID, model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, blocks)
+
+		members, err := store.GetMembersForBoard(board.ID)
+		require.NoError(t, err)
+		require.Empty(t, members)
+
+		dependencies, err := store.GetCardDependencies(card.ID)
+		require.NoError(t, err)
+		require.Empty(t, dependencies)
+
+		deleted, err := store.GetDeletedBoards(testTeamID)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+	})
+}
+
+func testPurgeBlock(t *testing.T, store store.Store) {
+	board := insertTrashBoard(t, store, "board")
+	card := insertUndoCard(t, store, board.ID, "card")
+	text := insertTrashText(t, store, card)
+	waitMillis()
+
+	err := store.PurgeBlock(card.ID)
+	require.True(t, model.IsErrBadRequest(err))
+
+	require.NoError(t, store.DeleteBlock(card.ID, testUserID))
+	require.NoError(t, store.PurgeBlock(card.ID))
+
+	for _, blockID := range []string{card.ID, text.ID} {
+		blocks, err := store.GetBlockHistory(blockID, model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, blocks)
+	}
+
+	_, err = store.GetBoard(board.ID)
+	require.NoError(t, err)
+}
+
+func testPurgeTrash(t *testing.T, store store.Store) {
+	oldBoard := insertTrashBoard(t, store, "old board")
+	board := insertTrashBoard(t, store, "board")
+	oldCard := insertUndoCard(t, store, board.ID, "old card")
+	insertTrashText(t, store, oldCard)
+	waitMillis()
+
+	require.NoError(t, store.DeleteBoard(oldBoard.ID, testUserID))
+	require.NoError(t, store.DeleteBlock(oldCard.ID, testUserID))
+	waitMillis()
+	cutoff := utils.GetMillis()
+	waitMillis()
+
+	newCard := insertUndoCard(t, store, board.ID, "new card")
+	waitMillis()
+	require.NoError(t, store.DeleteBlock(newCard.ID, testUserID))
+
+	purged, err := store.PurgeTrash(cutoff)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), purged)
+
+	boards, err := store.GetDeletedBoards(testTeamID)
+	require.NoError(t, err)
+	require.Empty(t, boards)
+
+	cards, err := store.GetDeletedBlocks([]string{board.ID}, model.TypeCard)
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	require.Equal(t, newCard.ID, cards[0].ID)
+}
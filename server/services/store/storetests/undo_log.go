@@ -0,0 +1,275 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestUndoLogStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("UndoStack", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUndoStack(t, store)
+	})
+
+	t.Run("UndoAndRedoBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUndoAndRedoBlocks(t, store)
+	})
+
+	t.Run("UndoBoardPatch", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUndoBoardPatch(t, store)
+	})
+
+	t.Run("UndoConflicts", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUndoConflicts(t, store)
+	})
+}
+
+func newUndoEntry(boardID string, operation model.UndoOperation, changes ...model.BlockChange) *model.UndoEntry {
+	return &model.UndoEntry{
+		ID:           utils.NewID(utils.IDTypeNone),
+		UserID:       testUserID,
+		BoardID:      boardID,
+		Operation:    operation,
+		BlockChanges: changes,
+	}
+}
+
+func insertUndoCard(t *testing.T, store store.Store, boardID, title string) *model.Block {
+	card := &model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		BoardID:  boardID,
+		ParentID: boardID,
+		Type:     model.TypeCard,
+		Title:    title,
+		Fields:   map[string]interface{}{},
+	}
+	require.NoError(t, store.InsertBlock(card, testUserID))
+	card, err := store.GetBlock(card.ID)
+	require.NoError(t, err)
+	return card
+}
+
+func testUndoStack(t *testing.T, store store.Store) {
+	boardID := utils.NewID(utils.IDTypeBoard)
+	entries := make([]*model.UndoEntry, 3)
+	for i := range entries {
+		entries[i] = newUndoEntry(boardID, model.UndoOperationPatchBlocks)
+		require.NoError(t, store.InsertUndoEntry(entries[i], 2))
+	}
+
+	t.Run("the newest entries are kept", func(t *testing.T) {
+		state, err := store.GetUndoState(testUserID, boardID)
+		require.NoError(t, err)
+		require.Equal(t, entries[2].ID, state.Undo.ID)
+		require.Nil(t, state.Redo)
+
+		// entries without changes can be undone, but have no effect
+		result, err := store.ApplyUndoEntry(testUserID, boardID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, entries[2].ID, result.Entry.ID)
+
+		state, err = store.GetUndoState(testUserID, boardID)
+		require.NoError(t, err)
+		require.Equal(t, entries[1].ID, state.Undo.ID)
+		require.Equal(t, entries[2].ID, state.Redo.ID)
+
+		result, err = store.ApplyUndoEntry(testUserID, boardID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, entries[1].ID, result.Entry.ID)
+
+		_, err = store.ApplyUndoEntry(testUserID, boardID, false, false)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("redo follows the undo order", func(t *testing.T) {
+		result, err := store.ApplyUndoEntry(testUserID, boardID, true, false)
+		require.NoError(t, err)
+		require.Equal(t, entries[1].ID, result.Entry.ID)
+
+		state, err := store.GetUndoState(testUserID, boardID)
+		require.NoError(t, err)
+		require.Equal(t, entries[1].ID, state.Undo.ID)
+		require.Equal(t, entries[2].ID, state.Redo.ID)
+	})
+
+	t.Run("a new entry clears the redo stack", func(t *testing.T) {
+		entry := newUndoEntry(boardID, model.UndoOperationPatchBlocks)
+		require.NoError(t, store.InsertUndoEntry(entry, 2))
+
+		state, err := store.GetUndoState(testUserID, boardID)
+		require.NoError(t, err)
+		require.Equal(t, entry.ID, state.Undo.ID)
+		require.Nil(t, state.Redo)
+
+		_, err = store.ApplyUndoEntry(testUserID, boardID, true, false)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("the stack is per user", func(t *testing.T) {
+		state, err := store.GetUndoState("other-user", boardID)
+		require.NoError(t, err)
+		require.Nil(t, state.Undo)
+		require.Nil(t, state.Redo)
+	})
+}
+
+func testUndoAndRedoBlocks(t *testing.T, store store.Store) {
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	t.Run("insert", func(t *testing.T) {
+		card := insertUndoCard(t, store, boardID, "inserted")
+		require.NoError(t, store.InsertUndoEntry(newUndoEntry(boardID, model.UndoOperationInsertBlocks, model.BlockChange{After: card}), 100))
+		waitMillis()
+
+		result, err := store.ApplyUndoEntry(testUserID, boardID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{card.ID}, result.DeletedBlockIDs)
+		_, err = store.GetBlock(card.ID)
+		require.True(t, model.IsErrNotFound(err))
+		waitMillis()
+
+		result, err = store.ApplyUndoEntry(testUserID, boardID, true, false)
+		require.NoError(t, err)
+		require.Len(t, result.Blocks, 1)
+		fetched, err := store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.Equal(t, "inserted", fetched.Title)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		card := insertUndoCard(t, store, boardID, "before")
+		waitMillis()
+		title := "after"
+		require.NoError(t, store.PatchBlock(card.ID, &model.BlockPatch{Title: &title}, testUserID))
+		patched, err := store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.NoError(t, store.InsertUndoEntry(newUndoEntry(boardID, model.UndoOperationPatchBlocks, model.BlockChange{Before: card, After: patched}), 100))
+		waitMillis()
+
+		result, err := store.ApplyUndoEntry(testUserID, boardID, false, false)
+		require.NoError(t, err)
+		require.Len(t, result.Blocks, 1)
+		fetched, err := store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.Equal(t, "before", fetched.Title)
+		require.Equal(t, card.CreateAt, fetched.CreateAt)
+		waitMillis()
+
+		_, err = store.ApplyUndoEntry(testUserID, boardID, true, false)
+		require.NoError(t, err)
+		fetched, err = store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.Equal(t, "after", fetched.Title)
+	})
+
+	t.Run("delete restores the children", func(t *testing.T) {
+		card := insertUndoCard(t, store, boardID, "deleted")
+		text := &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  boardID,
+			ParentID: card.ID,
+			Type:     model.TypeText,
+			Fields:   map[string]interface{}{},
+		}
+		require.NoError(t, store.InsertBlock(text, testUserID))
+		waitMillis()
+		require.NoError(t, store.DeleteBlock(card.ID, testUserID))
+		require.NoError(t, store.InsertUndoEntry(newUndoEntry(boardID, model.UndoOperationDeleteBlock, model.BlockChange{Before: card}), 100))
+		waitMillis()
+
+		result, err := store.ApplyUndoEntry(testUserID, boardID, false, false)
+		require.NoError(t, err)
+		require.Len(t, result.Blocks, 2)
+		_, err = store.GetBlock(card.ID)
+		require.NoError(t, err)
+		_, err = store.GetBlock(text.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("the blocks of other boards are refused", func(t *testing.T) {
+		card := insertUndoCard(t, store, utils.NewID(utils.IDTypeBoard), "other board")
+		require.NoError(t, store.InsertUndoEntry(newUndoEntry(boardID, model.UndoOperationInsertBlocks, model.BlockChange{After: card}), 100))
+		waitMillis()
+
+		_, err := store.ApplyUndoEntry(testUserID, boardID, false, false)
+		require.True(t, model.IsErrBadRequest(err))
+		_, err = store.GetBlock(card.ID)
+		require.NoError(t, err)
+	})
+}
+
+func testUndoBoardPatch(t *testing.T, store store.Store) {
+	board, err := store.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		Title:  "before",
+	}, testUserID)
+	require.NoError(t, err)
+	waitMillis()
+
+	title := "after"
+	patched, err := store.PatchBoard(board.ID, &model.BoardPatch{Title: &title}, testUserID)
+	require.NoError(t, err)
+	entry := newUndoEntry(board.ID, model.UndoOperationPatchBoard)
+	entry.BoardChange = &model.BoardChange{Before: board, After: patched}
+	require.NoError(t, store.InsertUndoEntry(entry, 100))
+	waitMillis()
+
+	result, err := store.ApplyUndoEntry(testUserID, board.ID, false, false)
+	require.NoError(t, err)
+	require.NotNil(t, result.Board)
+	require.Equal(t, "before", result.Board.Title)
+
+	fetched, err := store.GetBoard(board.ID)
+	require.NoError(t, err)
+	require.Equal(t, "before", fetched.Title)
+	require.Equal(t, board.Type, fetched.Type)
+}
+
+func testUndoConflicts(t *testing.T, store store.Store) {
+	boardID := utils.NewID(utils.IDTypeBoard)
+	card := insertUndoCard(t, store, boardID, "before")
+	waitMillis()
+
+	title := "after"
+	require.NoError(t, store.PatchBlock(card.ID, &model.BlockPatch{Title: &title}, testUserID))
+	patched, err := store.GetBlock(card.ID)
+	require.NoError(t, err)
+	require.NoError(t, store.InsertUndoEntry(newUndoEntry(boardID, model.UndoOperationPatchBlocks, model.BlockChange{Before: card, After: patched}), 100))
+	waitMillis()
+
+	otherTitle := "changed by another user"
+	require.NoError(t, store.PatchBlock(card.ID, &model.BlockPatch{Title: &otherTitle}, "other-user"))
+	waitMillis()
+
+	_, err = store.ApplyUndoEntry(testUserID, boardID, false, false)
+	require.True(t, model.IsErrConflict(err))
+	require.Contains(t, err.Error(), card.ID)
+
+	fetched, err := store.GetBlock(card.ID)
+	require.NoError(t, err)
+	require.Equal(t, otherTitle, fetched.Title)
+
+	_, err = store.ApplyUndoEntry(testUserID, boardID, false, true)
+	require.NoError(t, err)
+
+	fetched, err = store.GetBlock(card.ID)
+	require.NoError(t, err)
+	require.Equal(t, "before", fetched.Title)
+}
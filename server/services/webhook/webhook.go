@@ -3,8 +3,12 @@ package webhook
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"syscall"
+	"time"
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/config"
@@ -31,6 +35,70 @@ func (wh *Client) NotifyUpdate(block *model.Block) {
 	}
 }
 
+// postTimeout is the maximum time a call to a webhook can take.
+const postTimeout = 10 * time.Second
+
+// Post sends the payload as JSON to the url. It fails if the webhook
+// doesn't answer with a 2xx status. The urls are set by users, so the
+// connections to internal addresses are refused unless the configuration
+// allows them, and redirects are not followed, as they could lead to a
+// host that is not allowed.
+func (wh *Client) Post(url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: postTimeout}
+	if !wh.config.AutomationWebhookAllowInternal {
+		dialer.Control = refuseInternalAddress
+	}
+	client := &http.Client{
+		Timeout: postTimeout,
+		// no proxy, as the addresses are checked when dialing
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewBuffer(data)) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	wh.logger.Debug("webhook.Post", mlog.String("url", url), mlog.Int("status", resp.StatusCode))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s answered with status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// refuseInternalAddress refuses the connections to loopback, private,
+// link-local and unspecified addresses. It is called once the host is
+// resolved, so host names resolving to internal addresses are refused
+// too.
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("webhook address %s is not allowed", host)
+	}
+	return nil
+}
+
+// isInternalIP returns true if an address is not reachable from the
+// internet, like the loopback, private and link-local addresses.
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast()
+}
+
 // Client is a webhook client.
 type Client struct {
 	config *config.Configuration
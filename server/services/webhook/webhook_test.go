@@ -1,6 +1,8 @@
 package webhook
 
 import (
+	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -37,3 +39,79 @@ func TestClientUpdateNotify(t *testing.T) {
 		t.Error("webhook url not be notified")
 	}
 }
+
+func TestClientPost(t *testing.T) {
+	var payload map[string]interface{}
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	logger, _ := mlog.NewLogger()
+	defer func() {
+		err := logger.Shutdown()
+		assert.NoError(t, err)
+	}()
+
+	client := NewClient(&config.Configuration{AutomationWebhookAllowInternal: true}, logger)
+
+	err := client.Post(ts.URL, map[string]string{"cardId": "card-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "card-1", payload["cardId"])
+
+	status = http.StatusInternalServerError
+	err = client.Post(ts.URL, map[string]string{"cardId": "card-2"})
+	assert.Error(t, err)
+}
+
+func TestClientPostRedirect(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer ts.Close()
+
+	logger, _ := mlog.NewLogger()
+	defer func() {
+		err := logger.Shutdown()
+		assert.NoError(t, err)
+	}()
+
+	client := NewClient(&config.Configuration{AutomationWebhookAllowInternal: true}, logger)
+
+	err := client.Post(ts.URL, map[string]string{"cardId": "card-1"})
+	assert.ErrorContains(t, err, "status 307")
+	assert.False(t, redirected)
+}
+
+func TestClientPostInternalAddress(t *testing.T) {
+	called := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer ts.Close()
+
+	logger, _ := mlog.NewLogger()
+	defer func() {
+		err := logger.Shutdown()
+		assert.NoError(t, err)
+	}()
+
+	client := NewClient(&config.Configuration{}, logger)
+
+	err := client.Post(ts.URL, map[string]string{"cardId": "card-1"})
+	assert.ErrorContains(t, err, "is not allowed")
+	assert.False(t, called)
+
+	assert.True(t, isInternalIP(net.ParseIP("169.254.169.254")))
+	assert.True(t, isInternalIP(net.ParseIP("10.1.2.3")))
+	assert.True(t, isInternalIP(net.ParseIP("::1")))
+	assert.False(t, isInternalIP(net.ParseIP("93.184.216.34")))
+}
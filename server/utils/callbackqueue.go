@@ -100,6 +100,22 @@ func (cn *CallbackQueue) Enqueue(f CallbackFunc) {
 	}
 }
 
+// TryEnqueue adds a callback to the queue if it isn't full, and returns
+// false otherwise, so that callers don't wait for the queue.
+func (cn *CallbackQueue) TryEnqueue(f CallbackFunc) bool {
+	if atomic.LoadUint32(&cn.idone) != 0 {
+		cn.logger.Debug("CallbackQueue skipping enqueue, notifier is shutdown", mlog.String("name", cn.name))
+		return false
+	}
+
+	select {
+	case cn.queue <- f:
+		return true
+	default:
+		return false
+	}
+}
+
 func (cn *CallbackQueue) loop(id int) {
 	defer func() {
 		cn.logger.Trace("CallbackQueue thread exited", mlog.String("name", cn.name), mlog.Int("id", id))
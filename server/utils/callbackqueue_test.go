@@ -61,4 +61,25 @@ func Test_newChangeNotifier(t *testing.T) {
 
 		assert.Equal(t, int32(loops), atomic.LoadInt32(&callbackCount))
 	})
+	t.Run("try enqueue on a full queue", func(t *testing.T) {
+		cn := NewCallbackQueue("test3", 1, 1, logger)
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+		assert.True(t, cn.TryEnqueue(func() error {
+			close(started)
+			<-release
+			return nil
+		}))
+		<-started
+
+		assert.True(t, cn.TryEnqueue(func() error { return nil }))
+		assert.False(t, cn.TryEnqueue(func() error { return nil }))
+		close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+		assert.True(t, cn.Shutdown(ctx))
+		assert.False(t, cn.TryEnqueue(func() error { return nil }))
+	})
 }
@@ -22,6 +22,12 @@ const (
 	websocketActionUpdateCardLimitTimestamp = "UPDATE_CARD_LIMIT_TIMESTAMP"
 	websocketActionReorderCategories        = "REORDER_CATEGORIES"
 	websocketActionReorderCategoryBoards    = "REORDER_CATEGORY_BOARDS"
+	websocketActionUpdateCardBlockedState   = "UPDATE_CARD_BLOCKED_STATE"
+	websocketActionArchiveCard              = "ARCHIVE_CARD"
+	websocketActionUnarchiveCard            = "UNARCHIVE_CARD"
+	websocketActionArchiveBoard             = "ARCHIVE_BOARD"
+	websocketActionUnarchiveBoard           = "UNARCHIVE_BOARD"
+	websocketActionUpdateBulkOperation      = "UPDATE_BULK_OPERATION"
 )
 
 type Store interface {
@@ -43,4 +49,26 @@ type Adapter interface {
 	BroadcastSubscriptionChange(teamID string, subscription *model.Subscription)
 	BroadcastCategoryReorder(teamID, userID string, categoryOrder []string)
 	BroadcastCategoryBoardsReorder(teamID, userID, categoryID string, boardsOrder []string)
+	BroadcastCardBlockedStateChange(teamID string, change *model.CardBlockedStateChange)
+	BroadcastCardArchivedStateChange(teamID string, card *model.Block)
+	BroadcastBoardArchivedStateChange(teamID string, board *model.Board)
+	BroadcastBulkOperationChange(teamID string, operation *model.BulkOperation)
+}
+
+// cardArchivedStateAction returns the websocket action for the archived
+// state of a card.
+func cardArchivedStateAction(card *model.Block) string {
+	if card.ArchiveAt != 0 {
+		return websocketActionArchiveCard
+	}
+	return websocketActionUnarchiveCard
+}
+
+// boardArchivedStateAction returns the websocket action for the archived
+// state of a board.
+func boardArchivedStateAction(board *model.Board) string {
+	if board.ArchiveAt != 0 {
+		return websocketActionArchiveBoard
+	}
+	return websocketActionUnarchiveBoard
 }
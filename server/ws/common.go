@@ -33,6 +33,21 @@ type UpdateMemberMsg struct {
 	Member *model.BoardMember `json:"member"`
 }
 
+// UpdateCardBlockedStateMsg is sent when the blocked state of a card changes.
+type UpdateCardBlockedStateMsg struct {
+	Action string                        `json:"action"`
+	TeamID string                        `json:"teamId"`
+	Change *model.CardBlockedStateChange `json:"change"`
+}
+
+// UpdateBulkOperationMsg is sent when a bulk operation on the cards of a
+// board progresses or ends.
+type UpdateBulkOperationMsg struct {
+	Action    string               `json:"action"`
+	TeamID    string               `json:"teamId"`
+	Operation *model.BulkOperation `json:"operation"`
+}
+
 // UpdateSubscription is sent on subscription updates.
 type UpdateSubscription struct {
 	Action       string              `json:"action"`
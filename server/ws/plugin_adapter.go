@@ -31,6 +31,7 @@ type PluginAdapterInterface interface {
 	BroadcastSubscriptionChange(teamID string, subscription *model.Subscription)
 	BroadcastCardLimitTimestampChange(cardLimitTimestamp int64)
 	HandleClusterEvent(ev mmModel.PluginClusterEvent)
+	SetClusterEventHandler(id string, handler func(ev mmModel.PluginClusterEvent))
 }
 
 type PluginAdapter struct {
@@ -47,6 +48,9 @@ type PluginAdapter struct {
 	subscriptionsMU  sync.RWMutex
 	listenersByTeam  map[string][]*PluginAdapterClient
 	listenersByBlock map[string][]*PluginAdapterClient
+
+	clusterHandlersMU sync.RWMutex
+	clusterHandlers   map[string]func(ev mmModel.PluginClusterEvent)
 }
 
 // servicesAPI is the interface required by the PluginAdapter to interact with
@@ -69,6 +73,7 @@ func NewPluginAdapter(api servicesAPI, auth auth.AuthInterface, store Store, log
 		listenersByBlock:  make(map[string][]*PluginAdapterClient),
 		listenersMU:       sync.RWMutex{},
 		subscriptionsMU:   sync.RWMutex{},
+		clusterHandlers:   make(map[string]func(ev mmModel.PluginClusterEvent)),
 	}
 }
 
@@ -469,6 +474,22 @@ func (pa *PluginAdapter) BroadcastBlockChange(teamID string, block *model.Block)
 	pa.sendBoardMessage(teamID, block.BoardID, utils.StructToMap(message))
 }
 
+func (pa *PluginAdapter) BroadcastCardBlockedStateChange(teamID string, change *model.CardBlockedStateChange) {
+	pa.logger.Trace("BroadcastCardBlockedStateChange",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", change.BoardID),
+		mlog.String("cardID", change.CardID),
+	)
+
+	message := UpdateCardBlockedStateMsg{
+		Action: websocketActionUpdateCardBlockedState,
+		TeamID: teamID,
+		Change: change,
+	}
+
+	pa.sendBoardMessage(teamID, change.BoardID, utils.StructToMap(message))
+}
+
 func (pa *PluginAdapter) BroadcastCategoryChange(category model.Category) {
 	pa.logger.Debug("BroadcastCategoryChange",
 		mlog.String("userID", category.UserID),
@@ -600,6 +621,53 @@ func (pa *PluginAdapter) BroadcastBoardChange(teamID string, board *model.Board)
 	pa.sendBoardMessage(teamID, board.ID, utils.StructToMap(message))
 }
 
+func (pa *PluginAdapter) BroadcastCardArchivedStateChange(teamID string, card *model.Block) {
+	pa.logger.Trace("BroadcastCardArchivedStateChange",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", card.BoardID),
+		mlog.String("cardID", card.ID),
+	)
+
+	message := UpdateBlockMsg{
+		Action: cardArchivedStateAction(card),
+		TeamID: teamID,
+		Block:  card,
+	}
+
+	pa.sendBoardMessage(teamID, card.BoardID, utils.StructToMap(message))
+}
+
+func (pa *PluginAdapter) BroadcastBoardArchivedStateChange(teamID string, board *model.Board) {
+	pa.logger.Debug("BroadcastBoardArchivedStateChange",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", board.ID),
+	)
+
+	message := UpdateBoardMsg{
+		Action: boardArchivedStateAction(board),
+		TeamID: teamID,
+		Board:  board,
+	}
+
+	pa.sendBoardMessage(teamID, board.ID, utils.StructToMap(message))
+}
+
+func (pa *PluginAdapter) BroadcastBulkOperationChange(teamID string, operation *model.BulkOperation) {
+	pa.logger.Trace("BroadcastBulkOperationChange",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", operation.BoardID),
+		mlog.String("operationID", operation.ID),
+	)
+
+	message := UpdateBulkOperationMsg{
+		Action:    websocketActionUpdateBulkOperation,
+		TeamID:    teamID,
+		Operation: operation,
+	}
+
+	pa.sendBoardMessage(teamID, operation.BoardID, utils.StructToMap(message))
+}
+
 func (pa *PluginAdapter) BroadcastBoardDelete(teamID, boardID string) {
 	now := utils.GetMillis()
 	board := &model.Board{}
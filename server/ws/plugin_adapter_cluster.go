@@ -39,9 +39,28 @@ func (pa *PluginAdapter) sendMessageToCluster(clusterMessage *ClusterMessage) {
 	}
 }
 
+// SetClusterEventHandler registers the handler of the plugin cluster
+// events with the given id, which are not websocket messages.
+func (pa *PluginAdapter) SetClusterEventHandler(id string, handler func(ev mmModel.PluginClusterEvent)) {
+	pa.clusterHandlersMU.Lock()
+	defer pa.clusterHandlersMU.Unlock()
+	pa.clusterHandlers[id] = handler
+}
+
+// HandleClusterEvent receives the plugin cluster events sent by other
+// nodes, passing them to their registered handler or, if there is none,
+// sending their websocket message to the clients of this node.
 func (pa *PluginAdapter) HandleClusterEvent(ev mmModel.PluginClusterEvent) {
 	pa.logger.Debug("received cluster event", mlog.String("id", ev.Id))
 
+	pa.clusterHandlersMU.RLock()
+	handler, ok := pa.clusterHandlers[ev.Id]
+	pa.clusterHandlersMU.RUnlock()
+	if ok {
+		handler(ev)
+		return
+	}
+
 	var clusterMessage ClusterMessage
 	if err := json.Unmarshal(ev.Data, &clusterMessage); err != nil {
 		pa.logger.Error("cannot unmarshal cluster message data",
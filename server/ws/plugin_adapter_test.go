@@ -551,3 +551,23 @@ func TestParallelSubscriptionsOnMultipleConnections(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestPluginAdapterClusterEventHandler(t *testing.T) {
+	th := SetupTestHelper(t)
+
+	received := []mmModel.PluginClusterEvent{}
+	th.pa.SetClusterEventHandler("store_event", func(ev mmModel.PluginClusterEvent) {
+		received = append(received, ev)
+	})
+
+	t.Run("Should pass the events to their handler", func(t *testing.T) {
+		ev := mmModel.PluginClusterEvent{Id: "store_event"}
+		th.pa.HandleClusterEvent(ev)
+		require.Equal(t, []mmModel.PluginClusterEvent{ev}, received)
+	})
+
+	t.Run("Should not pass the websocket messages to the handler", func(t *testing.T) {
+		th.pa.HandleClusterEvent(mmModel.PluginClusterEvent{Id: "websocket_message", Data: []byte(`{}`)})
+		require.Len(t, received, 1)
+	})
+}
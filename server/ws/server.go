@@ -710,6 +710,95 @@ func (ws *Server) BroadcastBoardChange(teamID string, board *model.Board) {
 	}
 }
 
+func (ws *Server) BroadcastCardBlockedStateChange(teamID string, change *model.CardBlockedStateChange) {
+	message := UpdateCardBlockedStateMsg{
+		Action: websocketActionUpdateCardBlockedState,
+		TeamID: teamID,
+		Change: change,
+	}
+
+	listeners := ws.getListenersForTeamAndBoard(teamID, change.BoardID)
+	listeners = append(listeners, ws.getListenersForBlock(change.CardID)...)
+	ws.logger.Trace("listener(s) for teamID and boardID",
+		mlog.Int("listener_count", len(listeners)),
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", change.BoardID),
+	)
+
+	for _, listener := range listeners {
+		ws.logger.Debug("Broadcast card blocked state change",
+			mlog.String("teamID", teamID),
+			mlog.String("cardID", change.CardID),
+			mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
+		)
+
+		err := listener.WriteJSON(message)
+		if err != nil {
+			ws.logger.Error("broadcast error", mlog.Err(err))
+			listener.conn.Close()
+		}
+	}
+}
+
+func (ws *Server) BroadcastCardArchivedStateChange(teamID string, card *model.Block) {
+	message := UpdateBlockMsg{
+		Action: cardArchivedStateAction(card),
+		TeamID: teamID,
+		Block:  card,
+	}
+
+	listeners := ws.getListenersForTeamAndBoard(teamID, card.BoardID)
+	listeners = append(listeners, ws.getListenersForBlock(card.ID)...)
+	ws.logger.Trace("listener(s) for teamID and boardID",
+		mlog.Int("listener_count", len(listeners)),
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", card.BoardID),
+	)
+
+	for _, listener := range listeners {
+		ws.logger.Debug("Broadcast card archived state change",
+			mlog.String("teamID", teamID),
+			mlog.String("cardID", card.ID),
+			mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
+		)
+
+		err := listener.WriteJSON(message)
+		if err != nil {
+			ws.logger.Error("broadcast error", mlog.Err(err))
+			listener.conn.Close()
+		}
+	}
+}
+
+func (ws *Server) BroadcastBoardArchivedStateChange(teamID string, board *model.Board) {
+	message := UpdateBoardMsg{
+		Action: boardArchivedStateAction(board),
+		TeamID: teamID,
+		Board:  board,
+	}
+
+	listeners := ws.getListenersForTeamAndBoard(teamID, board.ID)
+	ws.logger.Trace("listener(s) for teamID and boardID",
+		mlog.Int("listener_count", len(listeners)),
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", board.ID),
+	)
+
+	for _, listener := range listeners {
+		ws.logger.Debug("Broadcast board archived state change",
+			mlog.String("teamID", teamID),
+			mlog.String("boardID", board.ID),
+			mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
+		)
+
+		err := listener.WriteJSON(message)
+		if err != nil {
+			ws.logger.Error("broadcast error", mlog.Err(err))
+			listener.conn.Close()
+		}
+	}
+}
+
 func (ws *Server) BroadcastBoardDelete(teamID, boardID string) {
 	now := utils.GetMillis()
 	board := &model.Board{}
@@ -789,3 +878,32 @@ func (ws *Server) BroadcastSubscriptionChange(workspaceID string, subscription *
 func (ws *Server) BroadcastCardLimitTimestampChange(cardLimitTimestamp int64) {
 	// not implemented for standalone server.
 }
+
+func (ws *Server) BroadcastBulkOperationChange(teamID string, operation *model.BulkOperation) {
+	message := UpdateBulkOperationMsg{
+		Action:    websocketActionUpdateBulkOperation,
+		TeamID:    teamID,
+		Operation: operation,
+	}
+
+	listeners := ws.getListenersForTeamAndBoard(teamID, operation.BoardID)
+	ws.logger.Trace("listener(s) for teamID and boardID",
+		mlog.Int("listener_count", len(listeners)),
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", operation.BoardID),
+	)
+
+	for _, listener := range listeners {
+		ws.logger.Debug("Broadcast bulk operation change",
+			mlog.String("teamID", teamID),
+			mlog.String("operationID", operation.ID),
+			mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
+		)
+
+		err := listener.WriteJSON(message)
+		if err != nil {
+			ws.logger.Error("broadcast error", mlog.Err(err))
+			listener.conn.Close()
+		}
+	}
+}